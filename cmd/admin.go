@@ -37,6 +37,7 @@ var (
 			subcmdRegenerate,
 			subcmdAuth,
 			subcmdSendMail,
+			subcmdReceiveMail,
 		},
 	}
 
@@ -48,6 +49,8 @@ var (
 			microcmdUserList,
 			microcmdUserChangePassword,
 			microcmdUserDelete,
+			microcmdUserImport,
+			microcmdUserExport,
 		},
 	}
 
@@ -288,6 +291,30 @@ var (
 			Value: "",
 			Usage: "Custom icon URL for OAuth2 login source",
 		},
+		cli.StringFlag{
+			Name:  "allowed-email-domains",
+			Value: "",
+			Usage: "Comma separated email domains of the accounts allowed to register",
+		},
+		cli.StringFlag{
+			Name:  "allowed-accounts",
+			Value: "",
+			Usage: "Comma separated emails, nicknames or user IDs of the accounts allowed to register",
+		},
+		cli.StringFlag{
+			Name:  "group-claim-name",
+			Value: "",
+			Usage: "Claim listing the groups of the accounts (Default: groups)",
+		},
+		cli.StringFlag{
+			Name:  "required-group",
+			Value: "",
+			Usage: "Group the accounts must belong to in order to register",
+		},
+		cli.BoolFlag{
+			Name:  "require-registration-approval",
+			Usage: "Prohibit new accounts to sign in until an administrator approves them",
+		},
 	}
 
 	microcmdAuthUpdateOauth = cli.Command{
@@ -325,6 +352,13 @@ var (
 			},
 		},
 	}
+
+	subcmdReceiveMail = cli.Command{
+		Name:        "receivemail",
+		Usage:       "Receive an email sent to the incoming address of a repository",
+		Description: "Reads an email from the standard input, it is meant to be called by the mail server delivering the emails sent to the incoming addresses",
+		Action:      runReceiveMail,
+	}
 )
 
 func runChangePassword(c *cli.Context) error {
@@ -616,6 +650,11 @@ func parseOAuth2Config(c *cli.Context) *models.OAuth2Config {
 		OpenIDConnectAutoDiscoveryURL: c.String("auto-discover-url"),
 		CustomURLMapping:              customURLMapping,
 		IconURL:                       c.String("icon-url"),
+		AllowedEmailDomains:           models.ParseOAuth2AllowList(c.String("allowed-email-domains")),
+		AllowedAccounts:               models.ParseOAuth2AllowList(c.String("allowed-accounts")),
+		GroupClaimName:                c.String("group-claim-name"),
+		RequiredGroup:                 c.String("required-group"),
+		RequireRegistrationApproval:   c.Bool("require-registration-approval"),
 	}
 }
 
@@ -672,6 +711,26 @@ func runUpdateOauth(c *cli.Context) error {
 		oAuth2Config.IconURL = c.String("icon-url")
 	}
 
+	if c.IsSet("allowed-email-domains") {
+		oAuth2Config.AllowedEmailDomains = models.ParseOAuth2AllowList(c.String("allowed-email-domains"))
+	}
+
+	if c.IsSet("allowed-accounts") {
+		oAuth2Config.AllowedAccounts = models.ParseOAuth2AllowList(c.String("allowed-accounts"))
+	}
+
+	if c.IsSet("group-claim-name") {
+		oAuth2Config.GroupClaimName = c.String("group-claim-name")
+	}
+
+	if c.IsSet("required-group") {
+		oAuth2Config.RequiredGroup = c.String("required-group")
+	}
+
+	if c.IsSet("require-registration-approval") {
+		oAuth2Config.RequireRegistrationApproval = c.Bool("require-registration-approval")
+	}
+
 	// update custom URL mapping
 	var customURLMapping = &oauth2.CustomURLMapping{}
 
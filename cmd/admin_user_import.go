@@ -0,0 +1,431 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	pwd "code.gitea.io/gitea/modules/password"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/urfave/cli"
+)
+
+var (
+	microcmdUserImport = cli.Command{
+		Name:  "import",
+		Usage: "Create users in bulk from a CSV or JSON file",
+		Description: `The CSV file must start with a header naming its columns among username, email, full_name, source and memberships.
+The JSON file must contain an array of objects with the same fields, memberships being an array.
+Source is the name of the authentication source of the user, local if empty.
+Memberships are "org" or "org/team" names, separated by ";" in CSV files.
+Existing users are skipped.`,
+		Action: runImportUsers,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file,f",
+				Usage: `File to read the users from, "-" for the standard input`,
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of the file: csv or json, guessed from the file extension by default",
+			},
+			cli.BoolFlag{
+				Name:  "send-notify",
+				Usage: "Send an invitation email to the created users, requires Gitea to be running",
+			},
+			cli.BoolTFlag{
+				Name:  "must-change-password",
+				Usage: "Force the created local users to change their generated password after their first login (Default: true)",
+			},
+			cli.IntFlag{
+				Name:  "random-password-length",
+				Usage: "Length of the random passwords generated for local users",
+				Value: 12,
+			},
+		},
+	}
+
+	microcmdUserExport = cli.Command{
+		Name:   "export",
+		Usage:  "Export users to a CSV or JSON file which can be imported",
+		Action: runExportUsers,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file,f",
+				Value: "-",
+				Usage: `File to write the users to, "-" for the standard output`,
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of the file: csv or json, guessed from the file extension by default",
+			},
+		},
+	}
+)
+
+// userRecord represents a user of an import or export file
+type userRecord struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name,omitempty"`
+	// Source is the name of the login source of the user, empty for local users
+	Source string `json:"source,omitempty"`
+	// Memberships are the names of the organizations and teams of the user, as "org" or "org/team"
+	Memberships []string `json:"memberships,omitempty"`
+}
+
+var userRecordColumns = []string{"username", "email", "full_name", "source", "memberships"}
+
+const userRecordMembershipSeparator = ";"
+
+// userRecordsFormat returns the format of a user file, guessed from its extension if not given
+func userRecordsFormat(format, path string) (string, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return "json", nil
+		}
+		return "csv", nil
+	}
+	format = strings.ToLower(format)
+	if format != "csv" && format != "json" {
+		return "", fmt.Errorf("unknown format %q, must be csv or json", format)
+	}
+	return format, nil
+}
+
+// readUserRecords reads the users of a CSV or JSON file
+func readUserRecords(r io.Reader, format string) ([]*userRecord, error) {
+	if format == "json" {
+		var records []*userRecord
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON file: %v", err)
+		}
+		for i, record := range records {
+			if record == nil || record.Username == "" || record.Email == "" {
+				return nil, fmt.Errorf("user %d: username and email are required", i+1)
+			}
+		}
+		return records, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("the CSV file has no header")
+		}
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, column := range userRecordColumns {
+			known = known || column == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown column %q, must be one of %s", name, strings.Join(userRecordColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("the CSV file has no username column")
+	} else if _, ok := columns["email"]; !ok {
+		return nil, errors.New("the CSV file has no email column")
+	}
+
+	var records []*userRecord
+	for row := 2; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		record := &userRecord{
+			Username: field("username"),
+			Email:    field("email"),
+			FullName: field("full_name"),
+			Source:   field("source"),
+		}
+		if record.Username == "" || record.Email == "" {
+			return nil, fmt.Errorf("row %d: username and email are required", row)
+		}
+		for _, membership := range strings.Split(field("memberships"), userRecordMembershipSeparator) {
+			if membership = strings.TrimSpace(membership); membership != "" {
+				record.Memberships = append(record.Memberships, membership)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// writeUserRecords writes users to a CSV or JSON file
+func writeUserRecords(w io.Writer, format string, records []*userRecord) error {
+	if format == "json" {
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if records == nil {
+			records = []*userRecord{}
+		}
+		return enc.Encode(records)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(userRecordColumns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write([]string{
+			record.Username,
+			record.Email,
+			record.FullName,
+			record.Source,
+			strings.Join(record.Memberships, userRecordMembershipSeparator),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func runImportUsers(c *cli.Context) error {
+	if err := argsSet(c, "file"); err != nil {
+		return err
+	}
+
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	format, err := userRecordsFormat(c.String("format"), c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if c.String("file") != "-" {
+		f, err := os.Open(c.String("file"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	records, err := readUserRecords(r, format)
+	if err != nil {
+		return err
+	}
+
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	sources, err := models.LoginSources()
+	if err != nil {
+		return err
+	}
+	sourcesByName := make(map[string]*models.LoginSource, len(sources))
+	for _, source := range sources {
+		sourcesByName[source.Name] = source
+	}
+
+	var created []string
+	var skipped, failed int
+	for _, record := range records {
+		u := &models.User{
+			Name:     record.Username,
+			Email:    record.Email,
+			FullName: record.FullName,
+			IsActive: true,
+			Theme:    setting.UI.DefaultTheme,
+		}
+
+		var password string
+		if record.Source == "" {
+			if password, err = pwd.Generate(c.Int("random-password-length")); err != nil {
+				return err
+			}
+			u.Passwd = password
+			u.MustChangePassword = c.BoolT("must-change-password")
+		} else {
+			source, ok := sourcesByName[record.Source]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "User '%s' has not been created: unknown authentication source '%s'\n", record.Username, record.Source)
+				failed++
+				continue
+			} else if source.IsOAuth2() {
+				fmt.Fprintf(os.Stderr, "User '%s' has not been created: users of OAuth2 source '%s' are created on their first login\n", record.Username, record.Source)
+				failed++
+				continue
+			}
+			u.LoginType = source.Type
+			u.LoginSource = source.ID
+			u.LoginName = record.Username
+		}
+
+		if err := models.CreateUser(u); err != nil {
+			if models.IsErrUserAlreadyExist(err) {
+				fmt.Printf("User '%s' already exists, skipped\n", record.Username)
+				skipped++
+			} else {
+				fmt.Fprintf(os.Stderr, "User '%s' has not been created: %v\n", record.Username, err)
+				failed++
+			}
+			continue
+		}
+		created = append(created, u.Name)
+		if password != "" {
+			fmt.Printf("New user '%s' has been created with password '%s'\n", u.Name, password)
+		} else {
+			fmt.Printf("New user '%s' has been created\n", u.Name)
+		}
+
+		for _, membership := range record.Memberships {
+			if err := addUserMembership(u, membership); err != nil {
+				fmt.Fprintf(os.Stderr, "User '%s' has not been added to '%s': %v\n", u.Name, membership, err)
+				failed++
+			}
+		}
+	}
+
+	if c.Bool("send-notify") && len(created) > 0 {
+		status, message := private.SendRegisterNotifyEmails(ctx, created)
+		if status != http.StatusOK {
+			return fmt.Errorf("unable to send the invitation emails: %s", message)
+		}
+		fmt.Println(message)
+	}
+
+	fmt.Printf("%d user(s) created, %d skipped, %d error(s)\n", len(created), skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d error(s) occurred while importing users", failed)
+	}
+	return nil
+}
+
+// addUserMembership adds the user to an organization or one of its teams, named as "org" or "org/team"
+func addUserMembership(u *models.User, membership string) error {
+	orgName, teamName := membership, ""
+	if i := strings.IndexByte(membership, '/'); i >= 0 {
+		orgName, teamName = membership[:i], membership[i+1:]
+	}
+
+	org, err := models.GetOrgByName(orgName)
+	if err != nil {
+		return err
+	}
+	if teamName == "" {
+		return models.AddOrgUser(org.ID, u.ID)
+	}
+	team, err := org.GetTeam(teamName)
+	if err != nil {
+		return err
+	}
+	return models.AddTeamMember(team, u.ID)
+}
+
+func runExportUsers(c *cli.Context) error {
+	format, err := userRecordsFormat(c.String("format"), c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	sources, err := models.LoginSources()
+	if err != nil {
+		return err
+	}
+	sourceNames := make(map[int64]string, len(sources))
+	for _, source := range sources {
+		sourceNames[source.ID] = source.Name
+	}
+
+	users, err := models.GetAllUsers()
+	if err != nil {
+		return err
+	}
+	records := make([]*userRecord, 0, len(users))
+	for _, u := range users {
+		record := &userRecord{
+			Username: u.Name,
+			Email:    u.Email,
+			FullName: u.FullName,
+			Source:   sourceNames[u.LoginSource],
+		}
+
+		orgs, err := models.GetOrgsByUserID(u.ID, true)
+		if err != nil {
+			return err
+		}
+		orgNames := make(map[int64]string, len(orgs))
+		for _, org := range orgs {
+			orgNames[org.ID] = org.Name
+		}
+		teams, err := models.GetUserTeams(u.ID, models.ListOptions{})
+		if err != nil {
+			return err
+		}
+		inTeam := make(map[int64]bool, len(orgs))
+		for _, team := range teams {
+			if orgName, ok := orgNames[team.OrgID]; ok {
+				record.Memberships = append(record.Memberships, orgName+"/"+team.Name)
+				inTeam[team.OrgID] = true
+			}
+		}
+		for _, org := range orgs {
+			if !inTeam[org.ID] {
+				record.Memberships = append(record.Memberships, org.Name)
+			}
+		}
+		sort.Strings(record.Memberships)
+
+		records = append(records, record)
+	}
+
+	var w io.Writer = os.Stdout
+	if c.String("file") != "-" {
+		f, err := os.Create(c.String("file"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeUserRecords(w, format, records); err != nil {
+		return err
+	}
+
+	if w != os.Stdout {
+		fmt.Printf("%d user(s) exported to %s\n", len(records), c.String("file"))
+	}
+	return nil
+}
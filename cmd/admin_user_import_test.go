@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRecordsFormat(t *testing.T) {
+	format, err := userRecordsFormat("", "users.JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", format)
+
+	format, err = userRecordsFormat("", "-")
+	assert.NoError(t, err)
+	assert.Equal(t, "csv", format)
+
+	format, err = userRecordsFormat("JSON", "users.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", format)
+
+	_, err = userRecordsFormat("xml", "users.xml")
+	assert.Error(t, err)
+}
+
+func TestReadUserRecords(t *testing.T) {
+	records, err := readUserRecords(strings.NewReader(`email, username, memberships
+user1@example.com, user1, org1/team1; org2
+user2@example.com,user2,
+`), "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, []*userRecord{
+		{Username: "user1", Email: "user1@example.com", Memberships: []string{"org1/team1", "org2"}},
+		{Username: "user2", Email: "user2@example.com"},
+	}, records)
+
+	records, err = readUserRecords(strings.NewReader(`[
+		{"username": "user1", "email": "user1@example.com", "full_name": "User One", "source": "ldap", "memberships": ["org1"]}
+	]`), "json")
+	assert.NoError(t, err)
+	assert.Equal(t, []*userRecord{
+		{Username: "user1", Email: "user1@example.com", FullName: "User One", Source: "ldap", Memberships: []string{"org1"}},
+	}, records)
+
+	var cases = []struct {
+		input  string
+		format string
+		errMsg string
+	}{
+		{"", "csv", "the CSV file has no header"},
+		{"username,email,password\n", "csv", `unknown column "password", must be one of username, email, full_name, source, memberships`},
+		{"username,full_name\n", "csv", "the CSV file has no email column"},
+		{"username,email\nuser1,user1@example.com\n,user2@example.com\n", "csv", "row 3: username and email are required"},
+		{`[{"username": "user1"}]`, "json", "user 1: username and email are required"},
+	}
+	for _, c := range cases {
+		_, err := readUserRecords(strings.NewReader(c.input), c.format)
+		assert.EqualError(t, err, c.errMsg, c.input)
+	}
+}
+
+func TestWriteUserRecords(t *testing.T) {
+	records := []*userRecord{
+		{Username: "user1", Email: "user1@example.com", FullName: "User, One", Memberships: []string{"org1", "org2/team1"}},
+		{Username: "user2", Email: "user2@example.com", Source: "ldap"},
+	}
+
+	for _, format := range []string{"csv", "json"} {
+		var buf bytes.Buffer
+		assert.NoError(t, writeUserRecords(&buf, format, records))
+
+		read, err := readUserRecords(&buf, format)
+		assert.NoError(t, err)
+		assert.Equal(t, records, read, format)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeUserRecords(&buf, "csv", records))
+	assert.Equal(t, `username,email,full_name,source,memberships
+user1,user1@example.com,"User, One",,org1;org2/team1
+user2,user2@example.com,,ldap,
+`, buf.String())
+}
@@ -22,14 +22,14 @@ import (
 // CmdDumpRepository represents the available dump repository sub-command.
 var CmdDumpRepository = cli.Command{
 	Name:        "dump-repo",
-	Usage:       "Dump the repository from git/github/gitea/gitlab",
+	Usage:       "Dump the repository from git/github/gitea/gitlab/bitbucket",
 	Description: "This is a command for dumping the repository data.",
 	Action:      runDumpRepository,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "git_service",
 			Value: "",
-			Usage: "Git service, git, github, gitea, gitlab. If clone_addr could be recognized, this could be ignored.",
+			Usage: "Git service, git, github, gitea, gitlab, bitbucket. If clone_addr could be recognized, this could be ignored.",
 		},
 		cli.StringFlag{
 			Name:  "repo_dir, r",
@@ -98,6 +98,8 @@ func runDumpRepository(ctx *cli.Context) error {
 		serviceStr = "gitlab"
 	} else if strings.HasPrefix(strings.ToLower(cloneAddr), "https://gitea.com/") {
 		serviceStr = "gitea"
+	} else if strings.HasPrefix(strings.ToLower(cloneAddr), "https://bitbucket.org/") {
+		serviceStr = "bitbucket"
 	}
 	if serviceStr == "" {
 		return errors.New("git_service missed or clone_addr cannot be recognized")
@@ -222,34 +222,31 @@ Gitea or set your environment appropriately.`, "")
 		total++
 		lastline++
 
-		// If the ref is a branch or tag, check if it's protected
-		if strings.HasPrefix(refFullName, git.BranchPrefix) || strings.HasPrefix(refFullName, git.TagPrefix) {
-			oldCommitIDs[count] = oldCommitID
-			newCommitIDs[count] = newCommitID
-			refFullNames[count] = refFullName
-			count++
-			fmt.Fprintf(out, "*")
-
-			if count >= hookBatchSize {
-				fmt.Fprintf(out, " Checking %d references\n", count)
-
-				hookOptions.OldCommitIDs = oldCommitIDs
-				hookOptions.NewCommitIDs = newCommitIDs
-				hookOptions.RefFullNames = refFullNames
-				statusCode, msg := private.HookPreReceive(ctx, username, reponame, hookOptions)
-				switch statusCode {
-				case http.StatusOK:
-					// no-op
-				case http.StatusInternalServerError:
-					return fail("Internal Server Error", msg)
-				default:
-					return fail(msg, "")
-				}
-				count = 0
-				lastline = 0
+		// Check every ref, as users who can only read the code are allowed to push
+		// to refs/for/<branch> and must be stopped from updating anything else
+		oldCommitIDs[count] = oldCommitID
+		newCommitIDs[count] = newCommitID
+		refFullNames[count] = refFullName
+		count++
+		fmt.Fprintf(out, "*")
+
+		if count >= hookBatchSize {
+			fmt.Fprintf(out, " Checking %d references\n", count)
+
+			hookOptions.OldCommitIDs = oldCommitIDs
+			hookOptions.NewCommitIDs = newCommitIDs
+			hookOptions.RefFullNames = refFullNames
+			statusCode, msg := private.HookPreReceive(ctx, username, reponame, hookOptions)
+			switch statusCode {
+			case http.StatusOK:
+				// no-op
+			case http.StatusInternalServerError:
+				return fail("Internal Server Error", msg)
+			default:
+				return fail(msg, "")
 			}
-		} else {
-			fmt.Fprintf(out, ".")
+			count = 0
+			lastline = 0
 		}
 		if lastline >= hookBatchSize {
 			fmt.Fprintf(out, "\n")
@@ -329,6 +326,7 @@ Gitea or set your environment appropriately.`, "")
 	repoName := os.Getenv(models.EnvRepoName)
 	pusherID, _ := strconv.ParseInt(os.Getenv(models.EnvPusherID), 10, 64)
 	pusherName := os.Getenv(models.EnvPusherName)
+	gitOperationID, _ := strconv.ParseInt(os.Getenv(models.EnvGitOperationID), 10, 64)
 
 	hookOptions := private.HookOptions{
 		UserName:                        pusherName,
@@ -337,6 +335,7 @@ Gitea or set your environment appropriately.`, "")
 		GitObjectDirectory:              os.Getenv(private.GitObjectDirectory),
 		GitQuarantinePath:               os.Getenv(private.GitQuarantinePath),
 		GitPushOptions:                  pushOptions(),
+		GitOperationID:                  gitOperationID,
 	}
 	oldCommitIDs := make([]string, hookBatchSize)
 	newCommitIDs := make([]string, hookBatchSize)
@@ -441,6 +440,9 @@ func hookPrintResults(results []private.HookPostReceiveBranchResult) {
 		if res.Create {
 			fmt.Fprintf(os.Stderr, "Create a new pull request for '%s':\n", res.Branch)
 			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
+		} else if res.Created {
+			fmt.Fprintf(os.Stderr, "Created a new pull request for '%s':\n", res.Branch)
+			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
 		} else {
 			fmt.Fprint(os.Stderr, "Visit the existing pull request:\n")
 			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
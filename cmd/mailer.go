@@ -6,7 +6,9 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
 
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
@@ -52,3 +54,21 @@ func runSendMail(c *cli.Context) error {
 
 	return nil
 }
+
+func runReceiveMail(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setting.NewContext()
+
+	email, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	status, message := private.ReceiveEmail(ctx, email)
+	if status != http.StatusOK {
+		return fmt.Errorf("error: %s", message)
+	}
+	return nil
+}
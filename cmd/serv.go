@@ -7,6 +7,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -214,7 +215,7 @@ func runServ(c *cli.Context) error {
 		}
 	}
 
-	results, err := private.ServCommand(ctx, keyID, username, reponame, requestedMode, verb, lfsVerb)
+	results, err := private.ServCommand(ctx, keyID, username, reponame, requestedMode, sshRemoteIP(), verb, lfsVerb)
 	if err != nil {
 		if private.IsErrServCommand(err) {
 			errServCommand := err.(private.ErrServCommand)
@@ -236,6 +237,7 @@ func runServ(c *cli.Context) error {
 	os.Setenv(models.EnvIsDeployKey, fmt.Sprintf("%t", results.IsDeployKey))
 	os.Setenv(models.EnvKeyID, fmt.Sprintf("%d", results.KeyID))
 	os.Setenv(models.EnvAppURL, setting.AppURL)
+	os.Setenv(models.EnvGitOperationID, fmt.Sprintf("%d", results.GitOperationID))
 
 	//LFS token authentication
 	if verb == lfsAuthenticateVerb {
@@ -287,11 +289,25 @@ func runServ(c *cli.Context) error {
 		gitcmd = exec.CommandContext(ctx, verb, repoPath)
 	}
 
+	stdin := &countingReader{r: os.Stdin}
+	stdout := &countingWriter{w: os.Stdout}
 	gitcmd.Dir = setting.RepoRootPath
-	gitcmd.Stdout = os.Stdout
-	gitcmd.Stdin = os.Stdin
+	gitcmd.Stdout = stdout
+	gitcmd.Stdin = stdin
 	gitcmd.Stderr = os.Stderr
-	if err = gitcmd.Run(); err != nil {
+	start := time.Now()
+	err = gitcmd.Run()
+	if results.GitOperationID > 0 {
+		if err := private.FinishGitOperation(ctx, results.GitOperationID, private.GitOperationFinishOptions{
+			BytesReceived: stdin.n,
+			BytesSent:     stdout.n,
+			Duration:      time.Since(start),
+			Succeeded:     err == nil,
+		}); err != nil {
+			log.Error("Unable to finish git operation %d: %v", results.GitOperationID, err)
+		}
+	}
+	if err != nil {
 		return fail("Internal error", "Failed to execute git command: %v", err)
 	}
 
@@ -304,3 +320,36 @@ func runServ(c *cli.Context) error {
 
 	return nil
 }
+
+// sshRemoteIP returns the address of the client from the SSH_CONNECTION variable set by the SSH server
+func sshRemoteIP() string {
+	fields := strings.Fields(os.Getenv("SSH_CONNECTION"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
@@ -47,6 +48,19 @@ func runLetsEncrypt(listenAddr, domain, directory, email string, m http.Handler)
 
 	magic.Issuers = []certmagic.Issuer{myACME}
 
+	if setting.Pages.Enabled && setting.Pages.AllowCustomDomains {
+		// certificates of the verified custom domains of pages are obtained on the first request to them
+		magic.OnDemand = &certmagic.OnDemandConfig{
+			DecisionFunc: func(name string) error {
+				if name == domain {
+					return nil
+				}
+				_, err := models.GetRepoPagesByDomain(name)
+				return err
+			},
+		}
+	}
+
 	// this obtains certificates or renews them if necessary
 	err := magic.ManageSync([]string{domain})
 	if err != nil {
@@ -208,3 +208,49 @@ func TestAPIEditUser(t *testing.T) {
 	user2 = models.AssertExistsAndLoadBean(t, &models.User{LoginName: "user2"}).(*models.User)
 	assert.True(t, user2.IsRestricted)
 }
+
+func TestAPICronTasks(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/admin/cron/update_migration_poster_id?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var before api.Cron
+	DecodeJSON(t, resp, &before)
+	assert.Equal(t, "update_migration_poster_id", before.Name)
+	assert.False(t, before.Running)
+
+	// the task runs before the response is sent
+	req = NewRequest(t, "POST", "/api/v1/admin/cron/update_migration_poster_id?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/cron/update_migration_poster_id?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var after api.Cron
+	DecodeJSON(t, resp, &after)
+	assert.Equal(t, before.ExecTimes+1, after.ExecTimes)
+	assert.False(t, after.Running)
+	if assert.NotNil(t, after.LastRun) {
+		assert.False(t, after.LastRun.IsZero())
+	}
+	assert.Equal(t, "success", after.LastStatus)
+	assert.Empty(t, after.LastMessage)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/cron?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var tasks []*api.Cron
+	DecodeJSON(t, resp, &tasks)
+	assert.NotEmpty(t, tasks)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/cron/no_such_task?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "POST", "/api/v1/admin/cron/no_such_task?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// only admins can read the cron tasks
+	normalSession := loginUser(t, "user2")
+	normalToken := getTokenForLoggedInUser(t, normalSession)
+	req = NewRequest(t, "GET", "/api/v1/admin/cron/update_migration_poster_id?token="+normalToken)
+	normalSession.MakeRequest(t, req, http.StatusForbidden)
+}
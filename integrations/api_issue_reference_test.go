@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueReferenceGraph(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, &api.CreateIssueOption{
+		Title: "depends on issue1",
+		Body:  "closes #1",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var issue api.Issue
+	DecodeJSON(t, resp, &issue)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/1/references")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var graph api.IssueReferenceGraph
+	DecodeJSON(t, resp, &graph)
+	if assert.Len(t, graph.Nodes, 2) {
+		assert.Equal(t, "issue:1", graph.Nodes[0].ID)
+		assert.Equal(t, fmt.Sprintf("issue:%d", issue.ID), graph.Nodes[1].ID)
+		assert.EqualValues(t, issue.Index, graph.Nodes[1].Index)
+		assert.Equal(t, "user2/repo1", graph.Nodes[1].Repository.FullName)
+	}
+	if assert.Len(t, graph.Edges, 1) {
+		assert.Equal(t, fmt.Sprintf("issue:%d", issue.ID), graph.Edges[0].From)
+		assert.Equal(t, "issue:1", graph.Edges[0].To)
+		assert.Equal(t, "issue", graph.Edges[0].Type)
+		assert.Equal(t, "closes", graph.Edges[0].Action)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/9999/references")
+	MakeRequest(t, req, http.StatusNotFound)
+}
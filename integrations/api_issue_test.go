@@ -236,6 +236,38 @@ func TestAPISearchIssues(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusOK)
 	DecodeJSON(t, resp, &apiIssues)
 	assert.Len(t, apiIssues, 2)
+
+	query = url.Values{"repo_ids": {"1", "2"}, "state": {"all"}}
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	assert.Len(t, apiIssues, 7)
+	for _, issue := range apiIssues {
+		assert.Contains(t, []int64{1, 2}, issue.Repo.ID)
+	}
+
+	query = url.Values{"repo_ids": {"9999"}, "state": {"all"}}
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	assert.Len(t, apiIssues, 0)
+
+	// user1 is assigned to issue 1 of repo1 and issue 6 of repo3, both accessible to user2
+	query = url.Values{"assigned_by": {"user1"}, "state": {"all"}}
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 2) {
+		assert.ElementsMatch(t, []int64{1, 6}, []int64{apiIssues[0].ID, apiIssues[1].ID})
+	}
+
+	query = url.Values{"assigned_by": {"no-such-user"}}
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	session.MakeRequest(t, req, http.StatusNotFound)
 }
 
 func TestAPISearchIssuesWithLabels(t *testing.T) {
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIWorkload(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user3/repo3/workload?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var workloads []*api.AssigneeWorkload
+	DecodeJSON(t, resp, &workloads)
+	if assert.Len(t, workloads, 2) {
+		assert.Equal(t, "user1", workloads[0].Assignee.UserName)
+		assert.EqualValues(t, 1, workloads[0].OpenIssues)
+		assert.EqualValues(t, 1, workloads[0].Age.Older)
+		assert.EqualValues(t, 1, workloads[0].PendingReviews)
+		assert.Equal(t, "user2", workloads[1].Assignee.UserName)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/workload?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	workloads = nil
+	DecodeJSON(t, resp, &workloads)
+	assert.Len(t, workloads, 2)
+
+	// the repositories of the organization are private
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/workload")
+	resp = MakeRequest(t, req, http.StatusOK)
+	workloads = nil
+	DecodeJSON(t, resp, &workloads)
+	assert.Empty(t, workloads)
+
+	req = NewRequest(t, "GET", "/user3/repo3/workload")
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/org/user3/workload")
+	session.MakeRequest(t, req, http.StatusOK)
+}
@@ -114,6 +114,17 @@ func TestAPINotification(t *testing.T) {
 	thread5 = models.AssertExistsAndLoadBean(t, &models.Notification{ID: 5}).(*models.Notification)
 	assert.Equal(t, models.NotificationStatusRead, thread5.Status)
 
+	// -- PUT /notifications/threads/{id} --
+	req = NewRequest(t, "PUT", fmt.Sprintf("/api/v1/notifications/threads/%d?to-status=unread&token=%s", thread5.ID, token))
+	session.MakeRequest(t, req, http.StatusResetContent)
+	thread5 = models.AssertExistsAndLoadBean(t, &models.Notification{ID: 5}).(*models.Notification)
+	assert.Equal(t, models.NotificationStatusUnread, thread5.Status)
+
+	req = NewRequest(t, "PUT", fmt.Sprintf("/api/v1/notifications/threads/%d?token=%s", thread5.ID, token))
+	session.MakeRequest(t, req, http.StatusResetContent)
+	thread5 = models.AssertExistsAndLoadBean(t, &models.Notification{ID: 5}).(*models.Notification)
+	assert.Equal(t, models.NotificationStatusRead, thread5.Status)
+
 	// -- check notifications --
 	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/notifications/new?token=%s", token))
 	resp = session.MakeRequest(t, req, http.StatusOK)
@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgListMirrors(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	assert.NoError(t, models.InsertMirror(&models.Mirror{RepoID: 5, Interval: time.Hour, LastError: "fatal: repository not found"}))
+	defer func() {
+		assert.NoError(t, models.DeleteMirrorByRepoID(5))
+	}()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/orgs/user3/mirrors?failing=true&token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repos []*api.Repository
+	DecodeJSON(t, resp, &repos)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 5, repos[0].ID)
+		assert.Equal(t, "fatal: repository not found", repos[0].MirrorLastError)
+		assert.NotNil(t, repos[0].MirrorUpdated)
+		assert.NotNil(t, repos[0].MirrorNextUpdate)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/mirrors?failing=false&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	repos = nil
+	DecodeJSON(t, resp, &repos)
+	assert.Empty(t, repos)
+
+	// the mirror is private
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/mirrors")
+	resp = MakeRequest(t, req, http.StatusOK)
+	repos = nil
+	DecodeJSON(t, resp, &repos)
+	assert.Empty(t, repos)
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackagePyPI(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	root := fmt.Sprintf("/api/packages/%s/pypi", user.Name)
+
+	content := []byte("test wheel")
+	hash := sha256.Sum256(content)
+	hashSHA256 := hex.EncodeToString(hash[:])
+
+	newUploadRequest := func(filename string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("content", filename)
+		_, _ = part.Write(content)
+		_ = writer.WriteField(":action", "file_upload")
+		_ = writer.WriteField("name", "Test_Package")
+		_ = writer.WriteField("version", "1.0.1")
+		_ = writer.WriteField("sha256_digest", hashSHA256)
+		_ = writer.WriteField("requires_python", ">=3.6")
+		_ = writer.Close()
+
+		req := NewRequestWithBody(t, "POST", root, body)
+		req.Header.Add("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("Upload", func(t *testing.T) {
+		MakeRequest(t, newUploadRequest("test_package-1.0.1-py3-none-any.whl"), http.StatusUnauthorized)
+		MakeRequest(t, AddBasicAuthHeader(newUploadRequest("test_package-1.0.1-py3-none-any.whl"), "user4"), http.StatusForbidden)
+		MakeRequest(t, AddBasicAuthHeader(newUploadRequest("test_package-1.0.1.exe"), user.Name), http.StatusBadRequest)
+		MakeRequest(t, AddBasicAuthHeader(newUploadRequest("test_package-1.0.1-py3-none-any.whl"), user.Name), http.StatusCreated)
+		MakeRequest(t, AddBasicAuthHeader(newUploadRequest("test_package-1.0.1-py3-none-any.whl"), user.Name), http.StatusConflict)
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		req := NewRequest(t, "GET", root+"/simple")
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), `/simple/test-package">test-package</a>`)
+
+		req = NewRequest(t, "GET", root+"/simple/Test.Package/")
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), fmt.Sprintf(`/files/test-package/1.0.1/test_package-1.0.1-py3-none-any.whl#sha256=%s" data-requires-python="&gt;=3.6"`, hashSHA256))
+
+		req = NewRequest(t, "GET", root+"/simple/other")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		req := NewRequest(t, "GET", root+"/files/test-package/1.0.1/test_package-1.0.1-py3-none-any.whl")
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+
+		req = NewRequest(t, "GET", root+"/files/test-package/1.0.0/test_package-1.0.1-py3-none-any.whl")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("PrivateOwner", func(t *testing.T) {
+		req := NewRequest(t, "GET", "/api/packages/privated_org/pypi/simple")
+		MakeRequest(t, req, http.StatusUnauthorized)
+	})
+}
@@ -101,6 +101,22 @@ func TestAPIPullReview(t *testing.T) {
 	assert.EqualValues(t, "PENDING", review.State)
 	assert.EqualValues(t, 3, review.CodeCommentsCount)
 
+	// test CreatePullReview with a comment on a file which does not exist
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/reviews?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.CreatePullReviewOptions{
+		Event: "COMMENT",
+		Comments: []api.CreatePullReviewComment{{
+			Path:       "README.md",
+			Body:       "first new line",
+			NewLineNum: 1,
+		}, {
+			Path:       "not-exist.md",
+			Body:       "first new line",
+			NewLineNum: 1,
+		}},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	models.AssertCount(t, &models.Comment{IssueID: pullIssue.ID, TreePath: "not-exist.md"}, 0)
+
 	// test SubmitPullReview
 	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/reviews/%d?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, review.ID, token), &api.SubmitPullReviewOptions{
 		Event: "APPROVED",
@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoAccessRequest(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(enabled bool) {
+		setting.Repository.EnableAccessRequests = enabled
+	}(setting.Repository.EnableAccessRequests)
+
+	requesterSession := loginUser(t, "user4")
+	requesterToken := getTokenForLoggedInUser(t, requesterSession)
+	adminSession := loginUser(t, "user2")
+	adminToken := getTokenForLoggedInUser(t, adminSession)
+
+	setting.Repository.EnableAccessRequests = false
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo5/access_requests?token="+requesterToken, &api.CreateRepoAccessRequestOption{})
+	requesterSession.MakeRequest(t, req, http.StatusNotFound)
+
+	setting.Repository.EnableAccessRequests = true
+
+	// a private repository outside the organizations of the requester can't be told apart from a missing one
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo2/access_requests?token="+requesterToken, &api.CreateRepoAccessRequestOption{})
+	privateResp := requesterSession.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/unknown/access_requests?token="+requesterToken, &api.CreateRepoAccessRequestOption{})
+	missingResp := requesterSession.MakeRequest(t, req, http.StatusNotFound)
+	assert.Equal(t, missingResp.Body.String(), privateResp.Body.String())
+	req = NewRequest(t, "GET", "/user2/repo2")
+	requesterSession.MakeRequest(t, req, http.StatusNotFound)
+
+	// user4 is a member of user3 without access to repo5
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo5/access_requests?token="+requesterToken, &api.CreateRepoAccessRequestOption{
+		Message: "I would like to contribute",
+	})
+	resp := requesterSession.MakeRequest(t, req, http.StatusCreated)
+	var accessRequest api.RepoAccessRequest
+	DecodeJSON(t, resp, &accessRequest)
+	assert.Equal(t, "pending", accessRequest.State)
+	assert.Equal(t, "user3/repo5", accessRequest.RepoFullName)
+	models.AssertExistsAndLoadBean(t, &models.Notification{UserID: 2, RepoID: 5, UpdatedBy: 4})
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo5/access_requests?token="+requesterToken, &api.CreateRepoAccessRequestOption{})
+	requesterSession.MakeRequest(t, req, http.StatusConflict)
+
+	// the requester cannot review its own request
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user3/repo5/access_requests/%d/approve?token=%s", accessRequest.ID, requesterToken), &api.ApproveRepoAccessRequestOption{
+		Permission: "admin",
+	})
+	requesterSession.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user3/repo5/access_requests?state=pending&token="+adminToken)
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	var accessRequests []*api.RepoAccessRequest
+	DecodeJSON(t, resp, &accessRequests)
+	assert.Len(t, accessRequests, 1)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user3/repo5/access_requests/%d/approve?token=%s", accessRequest.ID, adminToken), &api.ApproveRepoAccessRequestOption{
+		Permission: "write",
+	})
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &accessRequest)
+	assert.Equal(t, "approved", accessRequest.State)
+	assert.Equal(t, "write", accessRequest.Permission)
+	assert.Equal(t, "user2", accessRequest.Reviewer.UserName)
+	models.AssertExistsAndLoadBean(t, &models.Collaboration{RepoID: 5, UserID: 4, Mode: models.AccessModeWrite})
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/user3/repo5/access_requests/%d/reject?token=%s", accessRequest.ID, adminToken)
+	adminSession.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequest(t, "GET", "/api/v1/user/access_requests?token="+requesterToken)
+	resp = requesterSession.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &accessRequests)
+	assert.Len(t, accessRequests, 1)
+	assert.Equal(t, "approved", accessRequests[0].State)
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoAvatar(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := fmt.Sprintf("/api/v1/repos/user2/repo1/avatar?token=%s", token)
+
+	var buff bytes.Buffer
+	assert.NoError(t, png.Encode(&buff, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	req := NewRequestWithJSON(t, "POST", link, &api.UpdateRepoAvatarOption{
+		Image: base64.StdEncoding.EncodeToString(buff.Bytes()),
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.NotEmpty(t, repo.AvatarURL)
+
+	req = NewRequestWithJSON(t, "POST", link, &api.UpdateRepoAvatarOption{Image: "not an image"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", link, &api.UpdateRepoAvatarOption{Generate: true})
+	session.MakeRequest(t, req, http.StatusOK)
+	assert.NotEmpty(t, models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository).Avatar)
+
+	req = NewRequest(t, "DELETE", link)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1, Avatar: ""})
+
+	// only admins of the repository can change its avatar
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/user2/repo1/avatar?token=%s", token))
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIRepoAccentColor(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := fmt.Sprintf("/api/v1/repos/user2/repo1?token=%s", token)
+
+	color := "#aabbcc"
+	req := NewRequestWithJSON(t, "PATCH", link, &api.EditRepoOption{AccentColor: &color})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.Equal(t, color, repo.AccentColor)
+
+	color = "red"
+	req = NewRequestWithJSON(t, "PATCH", link, &api.EditRepoOption{AccentColor: &color})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1, AccentColor: "#aabbcc"})
+}
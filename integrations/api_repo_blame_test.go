@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetBlame(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/README.md?ref=master")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var blame api.FileBlame
+	DecodeJSON(t, resp, &blame)
+	assert.Equal(t, "README.md", blame.Path)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", blame.SHA)
+	if assert.Len(t, blame.Ranges, 1) {
+		assert.Equal(t, 1, blame.Ranges[0].StartLine)
+		assert.Equal(t, 3, blame.Ranges[0].EndLine)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", blame.Ranges[0].Commit.SHA)
+		assert.Equal(t, "user1", blame.Ranges[0].Commit.Author.Name)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/blame/missing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCompare(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...branch-not-exist?token="+token, user.Name)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master?token="+token, user.Name)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...branch2?token="+token, user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var compare api.Compare
+	DecodeJSON(t, resp, &compare)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", compare.MergeBase)
+	assert.Equal(t, 2, compare.TotalCommits)
+	assert.Len(t, compare.Commits, 2)
+	assert.Equal(t, 1, compare.ChangedFiles)
+	assert.Equal(t, 4, compare.Additions)
+	assert.Equal(t, 1, compare.Deletions)
+	assert.Equal(t, []*api.ChangedFile{
+		{Filename: "README.md", Status: "modified", Additions: 4, Deletions: 1},
+	}, compare.Files)
+}
+
+func TestAPIRepoCompareRemote(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(importLocalPaths bool) {
+		setting.ImportLocalPaths = importLocalPaths
+	}(setting.ImportLocalPaths)
+	setting.ImportLocalPaths = true
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/compare_remote?token=" + token
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CompareRemoteOption{
+		RemoteAddress: repo.RepoPath(),
+		RemoteRef:     "branch-not-exist",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CompareRemoteOption{
+		Base:          "master",
+		RemoteAddress: repo.RepoPath(),
+		RemoteRef:     "branch2",
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var compare api.Compare
+	DecodeJSON(t, resp, &compare)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", compare.MergeBase)
+	assert.Equal(t, 2, compare.TotalCommits)
+	assert.Equal(t, 1, compare.ChangedFiles)
+
+	// readers who cannot write to the repository may not fetch into it
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/compare_remote?token="+token, &api.CompareRemoteOption{
+		RemoteAddress: repo.RepoPath(),
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
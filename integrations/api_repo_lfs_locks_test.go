@@ -176,3 +176,53 @@ func TestAPILFSLocksLogged(t *testing.T) {
 		assert.Len(t, lfsLocks.Locks, 0)
 	}
 }
+
+func TestAPIRepoLFSLocksAdministration(t *testing.T) {
+	defer prepareTestEnv(t)()
+	setting.LFS.StartServer = true
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	lock, err := models.CreateLFSLock(&models.LFSLock{Repo: repo1, Owner: user2, Path: "README.md"})
+	assert.NoError(t, err)
+	// a lock held by a removed user
+	user8 := models.AssertExistsAndLoadBean(t, &models.User{ID: 8}).(*models.User)
+	assert.NoError(t, repo1.AddCollaborator(user8))
+	stale, err := models.CreateLFSLock(&models.LFSLock{Repo: repo1, Owner: user8, Path: "stale.bin"})
+	assert.NoError(t, err)
+	assert.NoError(t, models.DeleteUser(user8))
+
+	session := loginUser(t, user2.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/lfs/locks?token=%s", user2.Name, repo1.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiLocks []*api.RepoLFSLock
+	DecodeJSON(t, resp, &apiLocks)
+	assert.Len(t, apiLocks, 2)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+	for _, apiLock := range apiLocks {
+		assert.Equal(t, apiLock.ID == stale.ID, apiLock.Stale)
+	}
+
+	// other users can not administrate the locks
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/lfs/locks?token=%s", user2.Name, repo1.Name, token4)
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/lfs/locks/unlock?token=%s", user2.Name, repo1.Name, token), &api.UnlockRepoLFSLocksOption{Stale: true})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiLocks)
+	if assert.Len(t, apiLocks, 1) {
+		assert.Equal(t, stale.ID, apiLocks[0].ID)
+	}
+	models.AssertNotExistsBean(t, &models.LFSLock{ID: stale.ID})
+
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/%s/lfs/locks/%d?token=%s", user2.Name, repo1.Name, lock.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.LFSLock{ID: lock.ID})
+
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/%s/lfs/locks/%d?token=%s", user2.Name, repo1.Name, lock.ID, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
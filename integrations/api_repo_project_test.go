@@ -0,0 +1,104 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoProjects(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	baseURL := fmt.Sprintf("/api/v1/repos/%s/%s/projects", owner.Name, repo.Name)
+
+	req := NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", baseURL, token))
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiProjects []*api.Project
+	DecodeJSON(t, resp, &apiProjects)
+	if assert.Len(t, apiProjects, 1) {
+		assert.EqualValues(t, 1, apiProjects[0].ID)
+		assert.Equal(t, "basic_kanban", apiProjects[0].BoardType)
+	}
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", baseURL, token), &api.CreateProjectOption{
+		Title:     "Release",
+		BoardType: "unknown",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", baseURL, token), &api.CreateProjectOption{
+		Title: "Release",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiProject api.Project
+	DecodeJSON(t, resp, &apiProject)
+	assert.Equal(t, "Release", apiProject.Title)
+	assert.Equal(t, api.StateOpen, apiProject.State)
+	projectURL := fmt.Sprintf("%s/%d", baseURL, apiProject.ID)
+
+	state := "closed"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s?token=%s", projectURL, token), &api.EditProjectOption{
+		State: &state,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiProject)
+	assert.Equal(t, api.StateClosed, apiProject.State)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/boards?token=%s", projectURL, token), &api.CreateProjectBoardOption{
+		Title:    "Done",
+		AutoMove: "close",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiBoard api.ProjectBoard
+	DecodeJSON(t, resp, &apiBoard)
+	assert.Equal(t, "Done", apiBoard.Title)
+	assert.Equal(t, "close", apiBoard.AutoMove)
+	boardURL := fmt.Sprintf("%s/boards/%d", projectURL, apiBoard.ID)
+
+	autoMove := "merge"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s?token=%s", boardURL, token), &api.EditProjectBoardOption{
+		AutoMove: &autoMove,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiBoard)
+	assert.Equal(t, "merge", apiBoard.AutoMove)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/issues?token=%s", boardURL, token), &api.MoveProjectIssueOption{
+		Issue: 1,
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: 1, ProjectID: apiProject.ID, ProjectBoardID: apiBoard.ID})
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/issues?token=%s", boardURL, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 1, apiIssues[0].Index)
+	}
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", boardURL, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.ProjectBoard{ID: apiBoard.ID})
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", projectURL, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Project{ID: apiProject.ID})
+
+	// projects of other repositories are not reachable
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/2?token=%s", baseURL, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
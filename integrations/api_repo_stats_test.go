@@ -0,0 +1,54 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// the default branch of repo1 has a single commit on 2017-03-19, a Sunday
+	const week = 1489881600
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/contributors")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var contributors []*api.ContributorStats
+	DecodeJSON(t, resp, &contributors)
+	if assert.Len(t, contributors, 1) {
+		assert.Equal(t, "user1", contributors[0].Name)
+		assert.Equal(t, "address1@example.com", contributors[0].Email)
+		assert.Nil(t, contributors[0].Author)
+		assert.EqualValues(t, 1, contributors[0].Total)
+		assert.Equal(t, []*api.ContributorWeekStats{{Week: week, Additions: 3, Commits: 1}}, contributors[0].Weeks)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/code_frequency")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var frequency [][]int64
+	DecodeJSON(t, resp, &frequency)
+	assert.Equal(t, [][]int64{{week, 3, 0}}, frequency)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/commit_activity")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var activity []*api.CommitActivity
+	DecodeJSON(t, resp, &activity)
+	assert.Len(t, activity, 52)
+
+	// private repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo15/stats/contributors")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo15/stats/contributors?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+}
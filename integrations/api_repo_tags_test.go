@@ -80,3 +80,50 @@ func createNewTagUsingAPI(t *testing.T, session *TestSession, token string, owne
 	DecodeJSON(t, resp, &respObj)
 	return &respObj
 }
+
+func TestAPIRepoTagProtection(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/repo1/tag_protections?token=%s", user.Name, token)
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateTagProtectionOption{
+		NamePattern:        "v*",
+		WhitelistUsernames: []string{user.Name},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var pt api.TagProtection
+	DecodeJSON(t, resp, &pt)
+	assert.Equal(t, "v*", pt.NamePattern)
+	assert.Equal(t, []string{user.Name}, pt.WhitelistUsernames)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateTagProtectionOption{NamePattern: "/[/"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateTagProtectionOption{NamePattern: "v*", WhitelistTeams: []string{"Owners"}})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var pts []*api.TagProtection
+	DecodeJSON(t, resp, &pts)
+	assert.Len(t, pts, 1)
+
+	pattern := "/^v[0-9]+/"
+	urlStr = fmt.Sprintf("/api/v1/repos/%s/repo1/tag_protections/%d?token=%s", user.Name, pt.ID, token)
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditTagProtectionOption{NamePattern: &pattern})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &pt)
+	assert.Equal(t, pattern, pt.NamePattern)
+	assert.Equal(t, []string{user.Name}, pt.WhitelistUsernames)
+
+	protected := models.AssertExistsAndLoadBean(t, &models.ProtectedTag{ID: pt.ID}).(*models.ProtectedTag)
+	allowed, err := models.IsUserAllowedToControlTag([]*models.ProtectedTag{protected}, "v1", 4)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	req = NewRequest(t, "DELETE", urlStr)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", urlStr)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetPullRequestTemplate(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pull_request_template")
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		opts := getCreateFileOptions()
+		opts.Content = base64.StdEncoding.EncodeToString([]byte("---\nname: Pull request\nabout: Propose a change\nlabels: [\"label1\"]\nassignees: [\"user2\"]\n---\nDescribe the change"))
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/.gitea/PULL_REQUEST_TEMPLATE.md?token="+token, &opts)
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pull_request_template")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var it api.IssueTemplate
+		DecodeJSON(t, resp, &it)
+		assert.Equal(t, "Pull request", it.Name)
+		assert.Equal(t, []string{"label1"}, it.Labels)
+		assert.Equal(t, []string{"user2"}, it.Assignees)
+		assert.Equal(t, "Describe the change", it.Content)
+		assert.Equal(t, ".gitea/PULL_REQUEST_TEMPLATE.md", it.FileName)
+	})
+}
@@ -495,6 +495,124 @@ func TestAPIRepoTransfer(t *testing.T) {
 	_ = models.DeleteRepository(user, repo.OwnerID, repo.ID)
 }
 
+func TestAPIRepoTransferConfirmation(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	ownerSession := loginUser(t, owner.Name)
+	ownerToken := getTokenForLoggedInUser(t, ownerSession)
+	recipientSession := loginUser(t, "user6")
+	recipientToken := getTokenForLoggedInUser(t, recipientSession)
+	otherSession := loginUser(t, "user4")
+	otherToken := getTokenForLoggedInUser(t, otherSession)
+
+	apiRepo := new(api.Repository)
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/user/repos?token=%s", ownerToken), &api.CreateRepoOption{
+		Name:     "transfer-me",
+		AutoInit: true,
+		Readme:   "Default",
+	})
+	resp := ownerSession.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, apiRepo)
+
+	startTransfer := func() {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/transfer-me/transfer?token=%s", owner.Name, ownerToken), &api.TransferRepoOption{
+			NewOwner: "user6",
+		})
+		resp := ownerSession.MakeRequest(t, req, http.StatusCreated)
+		DecodeJSON(t, resp, apiRepo)
+		assert.NotNil(t, apiRepo.RepoTransfer)
+		assert.EqualValues(t, "user6", apiRepo.RepoTransfer.Recipient.UserName)
+	}
+
+	// only the recipient can accept or reject the transfer
+	startTransfer()
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me/transfer/accept?token=%s", owner.Name, otherToken)
+	otherSession.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me/transfer/reject?token=%s", owner.Name, recipientToken)
+	recipientSession.MakeRequest(t, req, http.StatusNoContent)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: apiRepo.ID}).(*models.Repository)
+	assert.EqualValues(t, models.RepositoryReady, repo.Status)
+	_, err := models.GetPendingRepositoryTransfer(repo)
+	assert.True(t, models.IsErrNoPendingTransfer(err))
+
+	// the owner can cancel the transfer
+	startTransfer()
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/transfer-me/transfer?token=%s", owner.Name, ownerToken)
+	ownerSession.MakeRequest(t, req, http.StatusNoContent)
+	ownerSession.MakeRequest(t, req, http.StatusNotFound)
+
+	// the recipient accepts the transfer
+	startTransfer()
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me/transfer/accept?token=%s", owner.Name, recipientToken)
+	resp = recipientSession.MakeRequest(t, req, http.StatusAccepted)
+	DecodeJSON(t, resp, apiRepo)
+	assert.EqualValues(t, "user6", apiRepo.Owner.UserName)
+
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: apiRepo.ID}).(*models.Repository)
+	assert.EqualValues(t, 6, repo.OwnerID)
+	assert.EqualValues(t, models.RepositoryReady, repo.Status)
+}
+
+func TestAPIRepoTransferConfirmationPrivate(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	ownerSession := loginUser(t, owner.Name)
+	ownerToken := getTokenForLoggedInUser(t, ownerSession)
+	recipientSession := loginUser(t, "user6")
+	recipientToken := getTokenForLoggedInUser(t, recipientSession)
+	otherSession := loginUser(t, "user4")
+	otherToken := getTokenForLoggedInUser(t, otherSession)
+
+	apiRepo := new(api.Repository)
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/user/repos?token=%s", ownerToken), &api.CreateRepoOption{
+		Name:     "transfer-me-private",
+		AutoInit: true,
+		Readme:   "Default",
+		Private:  true,
+	})
+	resp := ownerSession.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, apiRepo)
+
+	startTransfer := func() {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/transfer-me-private/transfer?token=%s", owner.Name, ownerToken), &api.TransferRepoOption{
+			NewOwner: "user6",
+		})
+		ownerSession.MakeRequest(t, req, http.StatusCreated)
+	}
+
+	// the recipient has no access to the repository but can see and answer the transfer, other users cannot
+	startTransfer()
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/transfer-me-private?token=%s", owner.Name, recipientToken)
+	recipientSession.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me-private/transfer/accept?token=%s", owner.Name, otherToken)
+	otherSession.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me-private/transfer/reject?token=%s", owner.Name, recipientToken)
+	recipientSession.MakeRequest(t, req, http.StatusNoContent)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: apiRepo.ID}).(*models.Repository)
+	assert.EqualValues(t, models.RepositoryReady, repo.Status)
+	assert.EqualValues(t, owner.ID, repo.OwnerID)
+
+	// without a pending transfer the former recipient has no access again
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me-private/transfer/accept?token=%s", owner.Name, recipientToken)
+	recipientSession.MakeRequest(t, req, http.StatusNotFound)
+
+	startTransfer()
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/transfer-me-private/transfer/accept?token=%s", owner.Name, recipientToken)
+	resp = recipientSession.MakeRequest(t, req, http.StatusAccepted)
+	DecodeJSON(t, resp, apiRepo)
+	assert.EqualValues(t, "user6", apiRepo.Owner.UserName)
+	assert.True(t, apiRepo.Private)
+	assert.True(t, apiRepo.Permissions.Admin)
+
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: apiRepo.ID}).(*models.Repository)
+	assert.EqualValues(t, 6, repo.OwnerID)
+	assert.EqualValues(t, models.RepositoryReady, repo.Status)
+}
+
 func TestAPIGenerateRepo(t *testing.T) {
 	defer prepareTestEnv(t)()
 
@@ -162,3 +162,52 @@ func TestAPITeamSearch(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusForbidden)
 
 }
+
+func TestAPITeamRequireTwoFactor(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user2 is an owner of org3 and is not enrolled in two-factor authentication
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	requireTwoFactor := true
+	req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/orgs/%s?token=%s", org.Name, token), &api.EditOrgOption{
+		RequireTwoFactor: &requireTwoFactor,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	org.RequireTwoFactor = true
+	assert.NoError(t, models.UpdateUserCols(org, "require_two_factor"))
+
+	req = NewRequestf(t, "PUT", "/api/v1/teams/2/members/user5?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	// user24 is enrolled in two-factor authentication
+	req = NewRequestf(t, "PUT", "/api/v1/teams/2/members/user24?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestf(t, "GET", "/api/v1/teams/2/members?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var members []*api.User
+	DecodeJSON(t, resp, &members)
+	status := make(map[string]bool, len(members))
+	for _, member := range members {
+		if assert.NotNil(t, member.TwoFactorEnabled) {
+			status[member.UserName] = *member.TwoFactorEnabled
+		}
+	}
+	assert.Equal(t, map[string]bool{"user2": false, "user4": false, "user24": true}, status)
+
+	// the status is not shown to other members
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/teams/2/members?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	members = nil
+	DecodeJSON(t, resp, &members)
+	assert.Len(t, members, 3)
+	for _, member := range members {
+		assert.Nil(t, member.TwoFactorEnabled)
+	}
+}
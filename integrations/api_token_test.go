@@ -10,6 +10,8 @@ import (
 
 	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestAPICreateAndDeleteToken tests that token that was just created can be deleted
@@ -61,3 +63,47 @@ func TestAPIDeleteMissingToken(t *testing.T) {
 	req = AddBasicAuthHeader(req, user.Name)
 	MakeRequest(t, req, http.StatusNotFound)
 }
+
+// TestAPIOrgAccessToken tests that an access token of an organization is restricted to its scopes
+func TestAPIOrgAccessToken(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/tokens?token="+token, &api.CreateOrgAccessTokenOption{
+		Name:   "org-admin",
+		Scopes: []string{"admin:org"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var orgToken api.OrgAccessToken
+	DecodeJSON(t, resp, &orgToken)
+	assert.Equal(t, "user2", orgToken.Creator)
+	assert.Equal(t, []string{"admin:org"}, orgToken.Scopes)
+	models.AssertExistsAndLoadBean(t, &models.AccessToken{ID: orgToken.ID, UID: 3, CreatorID: 2})
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/teams?token="+orgToken.Token)
+	MakeRequest(t, req, http.StatusOK)
+
+	// out of the scopes of the token
+	req = NewRequest(t, "GET", "/api/v1/user?token="+orgToken.Token)
+	MakeRequest(t, req, http.StatusUnauthorized)
+	req = NewRequest(t, "GET", "/api/v1/orgs/org6/teams?token="+orgToken.Token)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// an organization token can not create other tokens
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/tokens?token="+orgToken.Token, &api.CreateOrgAccessTokenOption{
+		Name:   "org-packages",
+		Scopes: []string{"write:package"},
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/tokens?token="+token, &api.CreateOrgAccessTokenOption{
+		Name:   "org-invalid",
+		Scopes: []string{"admin:repo"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/orgs/org3/tokens/%d?token=%s", orgToken.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.AccessToken{ID: orgToken.ID})
+}
@@ -30,3 +30,24 @@ func TestUserHeatmap(t *testing.T) {
 
 	assert.Equal(t, dummyheatmap, heatmap)
 }
+
+func TestUserHeatmapInRange(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+
+	// user2 contributed at 2020-10-20 21:00 UTC
+	urlStr := "/api/v1/users/user2/heatmap?from=2020-10-01&to=2020-10-31&timezone=Asia/Tokyo"
+	resp := session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+	var heatmap []*models.UserHeatmapData
+	DecodeJSON(t, resp, &heatmap)
+	// 2020-10-21 00:00 in Tokyo
+	assert.Equal(t, []*models.UserHeatmapData{{Timestamp: 1603206000, Contributions: 1}}, heatmap)
+
+	urlStr = "/api/v1/users/user2/heatmap?from=2020-10-21T00:00:00Z&to=2020-10-31T00:00:00Z"
+	resp = session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+	DecodeJSON(t, resp, &heatmap)
+	assert.Len(t, heatmap, 0)
+
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/users/user2/heatmap?timezone=Nowhere/City"), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/users/user2/heatmap?from=2020-10-31&to=2020-10-01"), http.StatusUnprocessableEntity)
+}
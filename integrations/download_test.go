@@ -90,3 +90,30 @@ func TestDownloadRawTextFileWithMimeTypeMapping(t *testing.T) {
 	delete(setting.MimeTypeMap.Map, ".xml")
 	setting.MimeTypeMap.Enabled = false
 }
+
+func TestDownloadRawTextFileWithUnsafeMimeTypeMapping(t *testing.T) {
+	defer prepareTestEnv(t)()
+	setting.MimeTypeMap.Map[".xml"] = "text/html"
+	setting.MimeTypeMap.Enabled = true
+	defer func() {
+		delete(setting.MimeTypeMap.Map, ".xml")
+		setting.MimeTypeMap.Enabled = false
+	}()
+
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo2/raw/branch/master/test.xml")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	assert.Equal(t, "text/html; charset=utf-8", resp.HeaderMap.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="test.xml"`, resp.HeaderMap.Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", resp.HeaderMap.Get("X-Content-Type-Options"))
+
+	// the admin trusts the users
+	setting.Repository.Media.InlineTypes = append(setting.Repository.Media.InlineTypes, "text/html")
+	defer func() {
+		setting.Repository.Media.InlineTypes = setting.Repository.Media.InlineTypes[:len(setting.Repository.Media.InlineTypes)-1]
+	}()
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, `inline; filename="test.xml"`, resp.HeaderMap.Get("Content-Disposition"))
+}
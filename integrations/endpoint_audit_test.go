@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointAudit(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	createHook := func(url string) *http.Request {
+		return NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/hooks?token="+token, &api.CreateHookOption{
+			Type:   "gitea",
+			Config: api.CreateHookOptionConfig{"content_type": "json", "url": url},
+			Active: true,
+		})
+	}
+	session.MakeRequest(t, createHook("http://example.com/plaintext"), http.StatusCreated)
+
+	defer func(allow bool) {
+		setting.Webhook.AllowPlaintextHTTP = allow
+	}(setting.Webhook.AllowPlaintextHTTP)
+	setting.Webhook.AllowPlaintextHTTP = false
+	session.MakeRequest(t, createHook("http://example.com/blocked"), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, createHook("https://example.com/encrypted"), http.StatusCreated)
+
+	// the existing plaintext webhook is reported to the site administrators
+	session.MakeRequest(t, NewRequest(t, "GET", "/admin/endpoints"), http.StatusForbidden)
+	adminSession := loginUser(t, "user1")
+	resp := adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/endpoints"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	rows := htmlDoc.doc.Find(".endpoint-audit tbody tr")
+	assert.Equal(t, 1, rows.Length())
+	assert.Contains(t, rows.Text(), "http://example.com/plaintext")
+}
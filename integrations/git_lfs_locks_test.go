@@ -0,0 +1,95 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushLFSLockedFile(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		setting.LFS.StartServer = true
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		assert.NoError(t, repo.AddCollaborator(user4))
+		lock, err := models.CreateLFSLock(&models.LFSLock{
+			Repo:  repo,
+			Owner: user4,
+			Path:  "README.md",
+		})
+		assert.NoError(t, err)
+
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		doGitClone(dstPath, u)(t)
+
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "README.md"), []byte("# changed\n"), 0644))
+		_, err = git.NewCommand("commit", "-am", "Change a locked file").RunInDir(dstPath)
+		assert.NoError(t, err)
+
+		_, err = git.NewCommand("push", "origin", "master").RunInDir(dstPath)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "README.md (locked by user4")
+
+		_, err = models.DeleteLFSLockByID(lock.ID, user4, false)
+		assert.NoError(t, err)
+
+		doGitPushTestRepository(dstPath, "origin", "master")(t)
+	})
+}
+
+func TestMergeLFSLockedFile(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		setting.LFS.StartServer = true
+
+		// the head of a pull request from a fork is never pushed to the base repository
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+		resp := testPullCreate(t, session, "user1", "repo1", "master", "Change a locked file")
+		elem := strings.Split(test.RedirectURL(resp), "/")
+		index, err := strconv.ParseInt(elem[4], 10, 64)
+		assert.NoError(t, err)
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		assert.NoError(t, repo.AddCollaborator(user4))
+		lock, err := models.CreateLFSLock(&models.LFSLock{
+			Repo:  repo,
+			Owner: user4,
+			Path:  "README.md",
+		})
+		assert.NoError(t, err)
+
+		ctx := NewAPITestContext(t, "user2", "repo1")
+		ctx.ExpectedCode = http.StatusConflict
+		doAPIMergePullRequest(ctx, "user2", "repo1", index)(t)
+
+		_, err = models.DeleteLFSLockByID(lock.ID, user4, false)
+		assert.NoError(t, err)
+
+		ctx.ExpectedCode = http.StatusOK
+		doAPIMergePullRequest(ctx, "user2", "repo1", index)(t)
+	})
+}
@@ -72,6 +72,7 @@ func testGit(t *testing.T, u *url.URL) {
 
 		t.Run("BranchProtectMerge", doBranchProtectPRMerge(&httpContext, dstPath))
 		t.Run("CreatePRAndSetManuallyMerged", doCreatePRAndSetManuallyMerged(httpContext, httpContext, dstPath, "master", "test-manually-merge"))
+		t.Run("AGitPush", doAGitPush(httpContext, dstPath))
 		t.Run("MergeFork", func(t *testing.T) {
 			defer PrintCurrentTest(t)()
 			t.Run("CreatePRAndMerge", doMergeFork(httpContext, forkedUserCtx, "master", httpContext.Username+":master"))
@@ -513,6 +514,43 @@ func doCreatePRAndSetManuallyMerged(ctx, baseCtx APITestContext, dstPath, baseBr
 	}
 }
 
+func doAGitPush(ctx APITestContext, dstPath string) func(t *testing.T) {
+	return func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+		repo, err := models.GetRepositoryByOwnerAndName(ctx.Username, ctx.Reponame)
+		assert.NoError(t, err)
+
+		t.Run("CheckoutMaster", doGitCheckoutBranch(dstPath, "master"))
+		t.Run("GenerateCommit", func(t *testing.T) {
+			_, err := generateCommitWithNewData(littleSize, dstPath, "user2@example.com", "User Two", "agit-data-file-")
+			assert.NoError(t, err)
+		})
+		t.Run("PushToCreate", doGitPushTestRepository(dstPath, "origin", "HEAD:refs/for/master/test-agit", "-o", "title=AGit pull request"))
+
+		pr, err := models.GetUnmergedPullRequest(repo.ID, repo.ID, ctx.Username+"/test-agit", "master", models.PullRequestFlowAGit)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, pr.LoadIssue())
+		assert.Equal(t, "AGit pull request", pr.Issue.Title)
+		assert.False(t, git.IsReferenceExist(repo.RepoPath(), "refs/for/master/test-agit"))
+
+		t.Run("GenerateCommit", func(t *testing.T) {
+			_, err := generateCommitWithNewData(littleSize, dstPath, "user2@example.com", "User Two", "agit-data-file-")
+			assert.NoError(t, err)
+		})
+		t.Run("PushToUpdate", doGitPushTestRepository(dstPath, "origin", "HEAD:refs/for/master", "-o", "topic=test-agit"))
+
+		headCommitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+		assert.NoError(t, err)
+		refCommitID, err := git.NewCommand("rev-parse", pr.GetGitRefName()).RunInDir(repo.RepoPath())
+		assert.NoError(t, err)
+		assert.Equal(t, headCommitID, refCommitID)
+
+		t.Run("PushWithoutTopicFails", doGitPushTestRepositoryFail(dstPath, "origin", "HEAD:refs/for/master"))
+	}
+}
+
 func doEnsureCanSeePull(ctx APITestContext, pr api.PullRequest) func(t *testing.T) {
 	return func(t *testing.T) {
 		req := NewRequest(t, "GET", fmt.Sprintf("/%s/%s/pulls/%d", url.PathEscape(ctx.Username), url.PathEscape(ctx.Reponame), pr.Index))
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
 
 	jsoniter "github.com/json-iterator/go"
@@ -261,3 +262,89 @@ func TestRefreshTokenInvalidation(t *testing.T) {
 	refreshReq.Body = ioutil.NopCloser(bytes.NewReader(bs))
 	MakeRequest(t, refreshReq, 400)
 }
+
+func TestDeviceAuthorizationGrant(t *testing.T) {
+	defer prepareTestEnv(t)()
+	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
+		Name:             "device",
+		UserID:           1,
+		RedirectURIs:     []string{"a"},
+		EnableDeviceFlow: true,
+	})
+	assert.NoError(t, err)
+	req := NewRequestWithValues(t, "POST", "/login/oauth/device/code", map[string]string{
+		"client_id": app.ClientID,
+	})
+	resp := MakeRequest(t, req, 200)
+	type deviceResponse struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		Interval        int64  `json:"interval"`
+	}
+	device := new(deviceResponse)
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), device))
+	assert.Len(t, device.UserCode, 9)
+	assert.Equal(t, setting.AppURL+"login/oauth/device", device.VerificationURI)
+
+	pollValues := map[string]string{
+		"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+		"client_id":   app.ClientID,
+		"device_code": device.DeviceCode,
+	}
+	resp = MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/access_token", pollValues), 400)
+	assert.Contains(t, resp.Body.String(), "authorization_pending")
+	resp = MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/access_token", pollValues), 400)
+	assert.Contains(t, resp.Body.String(), "slow_down")
+
+	session := loginUser(t, "user2")
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/login/oauth/device?user_code="+device.UserCode), 200)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, "#authorize-device", true)
+	session.MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/device", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"user_code": device.UserCode,
+		"granted":   "true",
+	}), 302)
+
+	resp = MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/access_token", pollValues), 200)
+	type tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	token := new(tokenResponse)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), token))
+	assert.True(t, len(token.AccessToken) > 10)
+	assert.True(t, len(token.RefreshToken) > 10)
+
+	// the device code can only be exchanged once
+	resp = MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/access_token", pollValues), 400)
+	assert.Contains(t, resp.Body.String(), "invalid_grant")
+}
+
+func TestDeviceAuthorizationGrantClientAuthentication(t *testing.T) {
+	defer prepareTestEnv(t)()
+	values := map[string]string{
+		"client_id": "da7da3ba-9a13-4167-856f-3899de0b0138",
+	}
+	// the application has not opted in to the device authorization grant
+	resp := MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/device/code", values), 400)
+	assert.Contains(t, resp.Body.String(), "unauthorized_client")
+
+	_, err := models.UpdateOAuth2Application(models.UpdateOAuth2ApplicationOptions{
+		ID:                 1,
+		Name:               "Test",
+		UserID:             1,
+		RedirectURIs:       []string{"a"},
+		ConfidentialClient: true,
+		EnableDeviceFlow:   true,
+	})
+	assert.NoError(t, err)
+
+	// a confidential client has to authenticate with its secret
+	resp = MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/device/code", values), 400)
+	assert.Contains(t, resp.Body.String(), "unauthorized_client")
+	values["client_secret"] = "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA="
+	MakeRequest(t, NewRequestWithValues(t, "POST", "/login/oauth/device/code", values), 200)
+}
@@ -51,6 +51,18 @@ func testPrivateActivityHelperEnablePrivateActivity(t *testing.T) {
 	session.MakeRequest(t, req, http.StatusFound)
 }
 
+func testPrivateActivityHelperEnableSignedInOnlyActivity(t *testing.T) {
+	session := loginUser(t, privateActivityTestUser)
+	req := NewRequestWithValues(t, "POST", "/user/settings", map[string]string{
+		"_csrf":                        GetCSRF(t, session, "/user/settings"),
+		"name":                         privateActivityTestUser,
+		"email":                        privateActivityTestUser + "@example.com",
+		"language":                     "en-US",
+		"keep_activity_signed_in_only": "1",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+}
+
 func testPrivateActivityHelperHasVisibleActivitiesInHTMLDoc(htmlDoc *HTMLDoc) bool {
 	return htmlDoc.doc.Find(".feeds").Find(".news").Length() > 0
 }
@@ -409,3 +421,26 @@ func TestPrivateActivityYesHeatmapHasNoContentForAdmin(t *testing.T) {
 
 	assert.True(t, hasContent, "heatmap should show content for admin")
 }
+
+// check activity visibility if the activity is only visible to signed-in users
+
+func TestPrivateActivitySignedInOnlyInvisibleForPublic(t *testing.T) {
+	defer prepareTestEnv(t)()
+	testPrivateActivityDoSomethingForActionEntries(t)
+	testPrivateActivityHelperEnableSignedInOnlyActivity(t)
+
+	assert.False(t, testPrivateActivityHelperHasVisibleActivitiesFromPublic(t), "user should have no visible activities")
+	assert.False(t, testPrivateActivityHelperHasVisibleHeatmapFromPublic(t), "user should have no visible heatmap")
+	assert.False(t, testPrivateActivityHelperHasHeatmapContentFromPublic(t), "user should have no heatmap content")
+}
+
+func TestPrivateActivitySignedInOnlyVisibleForOtherUser(t *testing.T) {
+	defer prepareTestEnv(t)()
+	testPrivateActivityDoSomethingForActionEntries(t)
+	testPrivateActivityHelperEnableSignedInOnlyActivity(t)
+
+	session := loginUser(t, privateActivityTestOtherUser)
+	assert.True(t, testPrivateActivityHelperHasVisibleActivitiesFromSession(t, session), "user should have visible activities")
+	assert.True(t, testPrivateActivityHelperHasVisibleProfileHeatmapFromSession(t, session), "user should have visible heatmap")
+	assert.True(t, testPrivateActivityHelperHasHeatmapContentFromSession(t, session), "user should have heatmap content")
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullFilesSearch(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/pulls/3/files/search?q=gutenberg")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var result struct {
+		Files []struct {
+			Name  string
+			Hunks []struct {
+				Lines []struct {
+					Matched bool
+				}
+			}
+		}
+		HasMore bool `json:"has_more"`
+	}
+	DecodeJSON(t, resp, &result)
+	assert.False(t, result.HasMore)
+	if assert.Len(t, result.Files, 1) {
+		assert.Equal(t, "iso-8859-1.txt", result.Files[0].Name)
+		assert.NotEmpty(t, result.Files[0].Hunks)
+	}
+
+	req = NewRequest(t, "GET", "/user2/repo1/pulls/3/files/search?q=no-such-content")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &result)
+	assert.Empty(t, result.Files)
+}
@@ -148,6 +148,27 @@ func TestPullSquash(t *testing.T) {
 	})
 }
 
+func TestPullMergeAfterHeadRepoDeleted(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+
+		resp := testPullCreate(t, session, "user1", "repo1", "master", "This is a pull title")
+
+		elem := strings.Split(test.RedirectURL(resp), "/")
+		assert.EqualValues(t, "pulls", elem[3])
+
+		// the head is kept in the pull request ref of the base repository
+		testDeleteRepository(t, session, "user1", "repo1")
+		testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleMerge)
+
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerName: "user2", Name: "repo1"}).(*models.Repository)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo1.ID, HeadBranch: "master", HasMerged: true}).(*models.PullRequest)
+		assert.NotEmpty(t, pr.MergedCommitID)
+	})
+}
+
 func TestPullCleanUpAfterMerge(t *testing.T) {
 	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
 		session := loginUser(t, "user1")
@@ -65,3 +65,32 @@ func TestRepoGenerateToOrg(t *testing.T) {
 	session := loginUser(t, "user2")
 	testRepoGenerate(t, session, "user27", "template1", "user2", "generated2")
 }
+
+func TestRepoTemplateCatalog(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	req := NewRequestWithValues(t, "POST", "/admin/repos/templates", map[string]string{
+		"_csrf":       GetCSRF(t, session, "/admin/repos/templates"),
+		"repo_name":   "user27/template1",
+		"category":    "Starters",
+		"description": "A starter project",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	templateRepo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerName: "user27", Name: "template1"}).(*models.Repository)
+	models.AssertExistsAndLoadBean(t, &models.InstanceTemplate{RepoID: templateRepo.ID, Category: "Starters"})
+
+	session = loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/repo/templates")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Contains(t, htmlDoc.doc.Find(".ui.dividing.header").Text(), "Starters")
+	link, exists := htmlDoc.doc.Find(fmt.Sprintf("a.button[href^=\"/repo/create?template_id=%d\"]", templateRepo.ID)).Attr("href")
+	assert.True(t, exists)
+
+	req = NewRequest(t, "GET", link)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	_, checked := htmlDoc.doc.Find("input[name=\"units\"]").Attr("checked")
+	assert.True(t, checked)
+}
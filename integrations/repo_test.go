@@ -12,7 +12,9 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
@@ -181,3 +183,54 @@ func TestViewAsRepoAdmin(t *testing.T) {
 		assert.Equal(t, expectedNoDescription, noDescription.HasClass("no-description"))
 	}
 }
+
+func TestViewRepoHomePageSettings(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	setHomePage := func(readmePath string, showWiki bool) {
+		values := map[string]string{
+			"_csrf":            GetCSRF(t, session, "/user2/repo1/settings"),
+			"action":           "home_page",
+			"home_readme_path": readmePath,
+		}
+		if showWiki {
+			values["home_show_wiki"] = "on"
+		}
+		req := NewRequestWithValues(t, "POST", "/user2/repo1/settings", values)
+		session.MakeRequest(t, req, http.StatusFound)
+	}
+	readmeName := func() string {
+		req := NewRequest(t, "GET", "/user2/repo1")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		return strings.TrimSpace(htmlDoc.doc.Find(".file-header strong").Text())
+	}
+
+	assert.Equal(t, "README.md", readmeName())
+
+	// a missing path falls back to the detected README
+	setHomePage("../missing.md", false)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	unit, err := repo.GetUnit(models.UnitTypeCode)
+	assert.NoError(t, err)
+	assert.Equal(t, "missing.md", unit.CodeConfig().ReadmePath)
+	assert.Equal(t, "README.md", readmeName())
+
+	setHomePage("", true)
+	assert.Equal(t, "Home.md", readmeName())
+
+	token := getTokenForLoggedInUser(t, session)
+	readmePath := "README.md"
+	showWikiHome := false
+	req := NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1?token="+token, &api.EditRepoOption{
+		ReadmePath:   &readmePath,
+		ShowWikiHome: &showWikiHome,
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiRepo api.Repository
+	DecodeJSON(t, resp, &apiRepo)
+	assert.Equal(t, "README.md", apiRepo.ReadmePath)
+	assert.False(t, apiRepo.ShowWikiHome)
+	assert.Equal(t, "README.md", readmeName())
+}
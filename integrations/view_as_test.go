@@ -0,0 +1,96 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminViewAs(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/admin/users/2")
+	req := NewRequestWithValues(t, "POST", "/admin/users/2/view_as", map[string]string{
+		"_csrf": csrf,
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	// the admin sees the site with the permissions of user2
+	req = NewRequest(t, "GET", "/admin/users")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "GET", "/user/settings")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "user2", htmlDoc.GetInputValueByName("name"))
+	assert.EqualValues(t, 1, htmlDoc.Find(".view-as-banner").Length())
+
+	// but can not change anything
+	csrf = htmlDoc.GetCSRF()
+	req = NewRequestWithValues(t, "POST", "/user/settings", map[string]string{
+		"_csrf":    csrf,
+		"name":     "user2-renamed",
+		"email":    "user2@example.com",
+		"language": "en-US",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.User{ID: 2, Name: "user2"})
+
+	// nor authorize an application, which creates an authorization code even for a GET request
+	codes := models.GetCount(t, &models.OAuth2AuthorizationCode{})
+	req = NewRequest(t, "GET", defaultAuthorize)
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertCount(t, &models.OAuth2AuthorizationCode{}, codes)
+
+	req = NewRequestWithValues(t, "POST", "/user/view_as/stop", map[string]string{
+		"_csrf": csrf,
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.EqualValues(t, "/admin/users/2", resp.Header().Get("Location"))
+
+	req = NewRequest(t, "GET", "/admin/users")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// only admins can view as other users
+	session = loginUser(t, "user2")
+	csrf = GetCSRF(t, session, "/user/settings")
+	req = NewRequestWithValues(t, "POST", "/admin/users/4/view_as", map[string]string{
+		"_csrf": csrf,
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIAdminViewAs(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/user?token=%s&view_as=user2", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var user struct {
+		UserName string `json:"login"`
+	}
+	DecodeJSON(t, resp, &user)
+	assert.Equal(t, "user2", user.UserName)
+	models.AssertExistsAndLoadBean(t, &models.Notice{
+		Type:        models.NoticeViewAs,
+		Description: "Admin user1 requested GET /api/v1/user through the API as user2",
+	})
+
+	req = NewRequestf(t, "POST", "/api/v1/user/repos?token=%s&view_as=user2", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/user?token=%s&view_as=user1", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -297,11 +297,13 @@ type GetFeedsOptions struct {
 	OnlyPerformedBy bool   // only actions performed by requested user
 	IncludeDeleted  bool   // include deleted actions
 	Date            string // the day we want activity for: YYYY-MM-DD
+
+	IncludeFollowedOrgs bool // include public activity of organizations followed by requested user
 }
 
 // GetFeeds returns actions according to the provided options
 func GetFeeds(opts GetFeedsOptions) ([]*Action, error) {
-	if !activityReadable(opts.RequestedUser, opts.Actor) {
+	if !opts.RequestedUser.IsActivityVisibleToUser(opts.Actor) {
 		return make([]*Action, 0), nil
 	}
 
@@ -323,19 +325,6 @@ func GetFeeds(opts GetFeedsOptions) ([]*Action, error) {
 	return actions, nil
 }
 
-func activityReadable(user, doer *User) bool {
-	var doerID int64
-	if doer != nil {
-		doerID = doer.ID
-	}
-	if doer == nil || !doer.IsAdmin {
-		if user.KeepActivityPrivate && doerID != user.ID {
-			return false
-		}
-	}
-	return true
-}
-
 func activityQueryCondition(opts GetFeedsOptions) (builder.Cond, error) {
 	cond := builder.NewCond()
 
@@ -370,7 +359,22 @@ func activityQueryCondition(opts GetFeedsOptions) (builder.Cond, error) {
 		cond = cond.And(builder.In("repo_id", teamRepoIDs))
 	}
 
-	cond = cond.And(builder.Eq{"user_id": opts.RequestedUser.ID})
+	var userCond builder.Cond = builder.Eq{"user_id": opts.RequestedUser.ID}
+	if opts.IncludeFollowedOrgs && !opts.RequestedUser.IsOrganization() {
+		orgIDs, err := GetFollowedOrgIDs(opts.RequestedUser.ID, opts.Actor)
+		if err != nil {
+			return nil, fmt.Errorf("GetFollowedOrgIDs: %v", err)
+		}
+		if len(orgIDs) > 0 {
+			// Skip what already reaches the feed through watching or acting oneself
+			userCond = userCond.Or(builder.In("user_id", orgIDs).
+				And(builder.Eq{"is_private": false}).
+				And(builder.Neq{"act_user_id": opts.RequestedUser.ID}).
+				And(builder.NotIn("repo_id", builder.Select("repo_id").From("watch").
+					Where(builder.Eq{"user_id": opts.RequestedUser.ID}.And(builder.In("mode", RepoWatchModeNormal, RepoWatchModeAuto))))))
+		}
+	}
+	cond = cond.And(userCond)
 
 	if opts.OnlyPerformedBy {
 		cond = cond.And(builder.Eq{"act_user_id": opts.RequestedUser.ID})
@@ -89,3 +89,33 @@ func TestGetFeeds2(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, actions, 0)
 }
+
+func TestGetFeedsFollowedOrgs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	act := &Action{UserID: 3, ActUserID: 2, OpType: ActionCreateRepo, RepoID: 32, IsPrivate: false}
+	_, err := x.Insert(act)
+	assert.NoError(t, err)
+
+	opts := GetFeedsOptions{
+		RequestedUser:       user,
+		Actor:               user,
+		IncludePrivate:      true,
+		IncludeFollowedOrgs: true,
+	}
+	actions, err := GetFeeds(opts)
+	assert.NoError(t, err)
+	assert.Len(t, actions, 0)
+
+	assert.NoError(t, FollowUser(user.ID, 3))
+	actions, err = GetFeeds(opts)
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 1) {
+		assert.EqualValues(t, act.ID, actions[0].ID)
+	}
+
+	opts.IncludeFollowedOrgs = false
+	actions, err = GetFeeds(opts)
+	assert.NoError(t, err)
+	assert.Len(t, actions, 0)
+}
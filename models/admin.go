@@ -22,6 +22,10 @@ const (
 	NoticeRepository NoticeType = iota + 1
 	// NoticeTask type
 	NoticeTask
+	// NoticeViewAs type, an admin started or stopped viewing the site as another user
+	NoticeViewAs
+	// NoticeFlaggedContent type, the content classifier flagged published content for moderation
+	NoticeFlaggedContent
 )
 
 // Notice represents a system notice for admin.
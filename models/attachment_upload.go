@@ -0,0 +1,264 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	gouuid "github.com/google/uuid"
+)
+
+func init() {
+	tables = append(tables,
+		new(AttachmentUpload),
+	)
+}
+
+// AttachmentUpload represents an issue attachment uploaded in several chunks,
+// so that an interrupted upload can be resumed instead of being restarted
+type AttachmentUpload struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UUID        string             `xorm:"uuid UNIQUE"`
+	IssueID     int64              `xorm:"INDEX"`
+	UploaderID  int64              `xorm:"INDEX"`
+	Name        string             `xorm:"NOT NULL"`
+	Size        int64              `xorm:"NOT NULL"`
+	Received    int64              `xorm:"NOT NULL DEFAULT 0"`
+	Chunks      int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// ErrAttachmentUploadNotExist represents a "AttachmentUploadNotExist" kind of error.
+type ErrAttachmentUploadNotExist struct {
+	UUID string
+}
+
+// IsErrAttachmentUploadNotExist checks if an error is a ErrAttachmentUploadNotExist.
+func IsErrAttachmentUploadNotExist(err error) bool {
+	_, ok := err.(ErrAttachmentUploadNotExist)
+	return ok
+}
+
+func (err ErrAttachmentUploadNotExist) Error() string {
+	return fmt.Sprintf("attachment upload does not exist [uuid: %s]", err.UUID)
+}
+
+// ErrAttachmentUploadOffsetMismatch represents a "AttachmentUploadOffsetMismatch" kind of error,
+// a chunk must start right after the bytes already received.
+type ErrAttachmentUploadOffsetMismatch struct {
+	UUID     string
+	Offset   int64
+	Received int64
+}
+
+// IsErrAttachmentUploadOffsetMismatch checks if an error is a ErrAttachmentUploadOffsetMismatch.
+func IsErrAttachmentUploadOffsetMismatch(err error) bool {
+	_, ok := err.(ErrAttachmentUploadOffsetMismatch)
+	return ok
+}
+
+func (err ErrAttachmentUploadOffsetMismatch) Error() string {
+	return fmt.Sprintf("attachment upload chunk does not start at the received offset [uuid: %s, offset: %d, received: %d]", err.UUID, err.Offset, err.Received)
+}
+
+// ErrAttachmentUploadChunkInvalid represents a "AttachmentUploadChunkInvalid" kind of error,
+// the chunk did not have the announced length or checksum.
+type ErrAttachmentUploadChunkInvalid struct {
+	UUID   string
+	Reason string
+}
+
+// IsErrAttachmentUploadChunkInvalid checks if an error is a ErrAttachmentUploadChunkInvalid.
+func IsErrAttachmentUploadChunkInvalid(err error) bool {
+	_, ok := err.(ErrAttachmentUploadChunkInvalid)
+	return ok
+}
+
+func (err ErrAttachmentUploadChunkInvalid) Error() string {
+	return fmt.Sprintf("attachment upload chunk is invalid [uuid: %s]: %s", err.UUID, err.Reason)
+}
+
+func (u *AttachmentUpload) chunkPath(index int) string {
+	return path.Join("uploads", u.UUID, strconv.Itoa(index))
+}
+
+// IsComplete returns true if all the bytes of the upload have been received
+func (u *AttachmentUpload) IsComplete() bool {
+	return u.Received >= u.Size
+}
+
+// NewAttachmentUpload starts a new chunked upload
+func NewAttachmentUpload(u *AttachmentUpload) error {
+	u.UUID = gouuid.New().String()
+	u.Received = 0
+	u.Chunks = 0
+	_, err := x.Insert(u)
+	return err
+}
+
+// GetAttachmentUploadByUUID returns the chunked upload with the given UUID
+func GetAttachmentUploadByUUID(uuid string) (*AttachmentUpload, error) {
+	u := new(AttachmentUpload)
+	has, err := x.Where("uuid = ?", uuid).Get(u)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAttachmentUploadNotExist{UUID: uuid}
+	}
+	return u, nil
+}
+
+// attachmentUploadPool serializes the chunks appended to the same upload,
+// a chunk racing with another one for the same offset would overwrite or delete its stored content
+var attachmentUploadPool = sync.NewExclusivePool()
+
+// AppendChunk stores the chunk of the given length starting at offset, which must be the number of bytes already received.
+// The chunk is discarded if it is shorter than announced or if its SHA-256 checksum does not match the given one, if any.
+func (u *AttachmentUpload) AppendChunk(r io.Reader, offset, length int64, checksum []byte) error {
+	attachmentUploadPool.CheckIn(u.UUID)
+	defer attachmentUploadPool.CheckOut(u.UUID)
+
+	// another chunk may have been appended while waiting
+	current, err := GetAttachmentUploadByUUID(u.UUID)
+	if err != nil {
+		return err
+	}
+	u.Received, u.Chunks = current.Received, current.Chunks
+
+	if offset != u.Received {
+		return ErrAttachmentUploadOffsetMismatch{UUID: u.UUID, Offset: offset, Received: u.Received}
+	}
+	if length <= 0 || offset+length > u.Size {
+		return ErrAttachmentUploadChunkInvalid{UUID: u.UUID, Reason: "chunk exceeds the size of the upload"}
+	}
+
+	chunkPath := u.chunkPath(u.Chunks)
+	hash := sha256.New()
+	written, err := storage.Attachments.Save(chunkPath, io.TeeReader(io.LimitReader(r, length), hash), length)
+	if err == nil && written != length {
+		err = ErrAttachmentUploadChunkInvalid{UUID: u.UUID, Reason: fmt.Sprintf("received %d bytes instead of %d", written, length)}
+	}
+	if err == nil && len(checksum) > 0 && !bytes.Equal(hash.Sum(nil), checksum) {
+		err = ErrAttachmentUploadChunkInvalid{UUID: u.UUID, Reason: "checksum mismatch"}
+	}
+	if err != nil {
+		if err := storage.Attachments.Delete(chunkPath); err != nil {
+			log.Warn("Unable to delete the chunk %s: %v", chunkPath, err)
+		}
+		return err
+	}
+
+	// the condition on the received bytes guards against concurrent uploads of the same chunk
+	updated, err := x.ID(u.ID).Where("received = ?", u.Received).
+		Cols("received", "chunks").
+		Update(&AttachmentUpload{Received: u.Received + length, Chunks: u.Chunks + 1})
+	if err != nil {
+		return err
+	} else if updated == 0 {
+		current, err := GetAttachmentUploadByUUID(u.UUID)
+		if err != nil {
+			return err
+		}
+		return ErrAttachmentUploadOffsetMismatch{UUID: u.UUID, Offset: offset, Received: current.Received}
+	}
+	u.Received += length
+	u.Chunks++
+	return nil
+}
+
+// Complete assembles the received chunks into a new attachment of the issue and removes the upload
+func (u *AttachmentUpload) Complete() (*Attachment, error) {
+	if !u.IsComplete() {
+		return nil, ErrAttachmentUploadChunkInvalid{UUID: u.UUID, Reason: "upload is not complete"}
+	}
+
+	attach := &Attachment{
+		UUID:       gouuid.New().String(),
+		IssueID:    u.IssueID,
+		UploaderID: u.UploaderID,
+		Name:       u.Name,
+	}
+
+	chunkPaths := make([]string, 0, u.Chunks)
+	for i := 0; i < u.Chunks; i++ {
+		chunkPaths = append(chunkPaths, u.chunkPath(i))
+	}
+	size, err := storage.Concatenate(storage.Attachments, attach.RelativePath(), chunkPaths, u.Size)
+	if err != nil {
+		return nil, fmt.Errorf("Concatenate: %v", err)
+	}
+	attach.Size = size
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+	if _, err := sess.Insert(attach); err != nil {
+		return nil, err
+	}
+	if _, err := sess.ID(u.ID).Delete(new(AttachmentUpload)); err != nil {
+		return nil, err
+	}
+	if err := sess.Commit(); err != nil {
+		return nil, err
+	}
+
+	u.removeChunks()
+	return attach, nil
+}
+
+func (u *AttachmentUpload) removeChunks() {
+	for i := 0; i < u.Chunks; i++ {
+		if err := storage.Attachments.Delete(u.chunkPath(i)); err != nil {
+			log.Warn("Unable to delete the chunk %s: %v", u.chunkPath(i), err)
+		}
+	}
+}
+
+// DeleteAttachmentUpload aborts the chunked upload and removes the received chunks
+func DeleteAttachmentUpload(u *AttachmentUpload) error {
+	if _, err := x.ID(u.ID).Delete(new(AttachmentUpload)); err != nil {
+		return err
+	}
+	u.removeChunks()
+	return nil
+}
+
+// DeleteStaleAttachmentUploads deletes the chunked uploads which have not received any chunk for the given duration
+func DeleteStaleAttachmentUploads(ctx context.Context, olderThan time.Duration) error {
+	log.Trace("Doing: DeleteStaleAttachmentUploads")
+
+	uploads := make([]*AttachmentUpload, 0, 10)
+	if err := x.Where("updated_unix < ?", time.Now().Add(-olderThan).Unix()).Find(&uploads); err != nil {
+		return err
+	}
+	for _, u := range uploads {
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before deleting stale attachment upload %s", u.UUID)
+		default:
+		}
+		if err := DeleteAttachmentUpload(u); err != nil {
+			return err
+		}
+	}
+
+	log.Trace("Finished: DeleteStaleAttachmentUploads: %d uploads deleted", len(uploads))
+	return nil
+}
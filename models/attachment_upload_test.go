@@ -0,0 +1,108 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentUpload(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	u := &AttachmentUpload{
+		IssueID:    1,
+		UploaderID: 2,
+		Name:       "notes.txt",
+		Size:       11,
+	}
+	assert.NoError(t, NewAttachmentUpload(u))
+	assert.NotEmpty(t, u.UUID)
+
+	// chunks must be sent in order
+	err := u.AppendChunk(strings.NewReader("world"), 6, 5, nil)
+	assert.True(t, IsErrAttachmentUploadOffsetMismatch(err))
+
+	// a chunk with a wrong checksum is discarded
+	wrong := sha256.Sum256([]byte("other"))
+	err = u.AppendChunk(strings.NewReader("hello "), 0, 6, wrong[:])
+	assert.True(t, IsErrAttachmentUploadChunkInvalid(err))
+	assert.EqualValues(t, 0, u.Received)
+
+	// a truncated chunk is discarded
+	err = u.AppendChunk(strings.NewReader("hel"), 0, 6, nil)
+	assert.True(t, IsErrAttachmentUploadChunkInvalid(err))
+	assert.EqualValues(t, 0, u.Received)
+
+	checksum := sha256.Sum256([]byte("hello "))
+	assert.NoError(t, u.AppendChunk(strings.NewReader("hello "), 0, 6, checksum[:]))
+	assert.False(t, u.IsComplete())
+
+	// the upload is resumed from the stored state
+	u, err = GetAttachmentUploadByUUID(u.UUID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 6, u.Received)
+	assert.EqualValues(t, 1, u.Chunks)
+
+	// a chunk sent again for an offset already received doesn't replace the stored one
+	stale := *u
+	stale.Received, stale.Chunks = 0, 0
+	err = stale.AppendChunk(strings.NewReader("HELLO "), 0, 6, nil)
+	assert.True(t, IsErrAttachmentUploadOffsetMismatch(err))
+
+	assert.NoError(t, u.AppendChunk(strings.NewReader("world"), 6, 5, nil))
+	assert.True(t, u.IsComplete())
+
+	attach, err := u.Complete()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, attach.Size)
+	assert.EqualValues(t, 1, attach.IssueID)
+	assert.EqualValues(t, "notes.txt", attach.Name)
+
+	obj, err := storage.Attachments.Open(attach.RelativePath())
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(obj)
+	obj.Close()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "hello world", string(content))
+
+	_, err = GetAttachmentUploadByUUID(u.UUID)
+	assert.True(t, IsErrAttachmentUploadNotExist(err))
+	_, err = storage.Attachments.Stat(u.chunkPath(0))
+	assert.Error(t, err)
+}
+
+func TestDeleteStaleAttachmentUploads(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	u := &AttachmentUpload{
+		IssueID:    1,
+		UploaderID: 2,
+		Name:       "notes.txt",
+		Size:       11,
+	}
+	assert.NoError(t, NewAttachmentUpload(u))
+	assert.NoError(t, u.AppendChunk(strings.NewReader("hello "), 0, 6, nil))
+
+	assert.NoError(t, DeleteStaleAttachmentUploads(context.Background(), time.Hour))
+	_, err := GetAttachmentUploadByUUID(u.UUID)
+	assert.NoError(t, err)
+
+	_, err = x.Exec("UPDATE attachment_upload SET updated_unix = ? WHERE id = ?", time.Now().Add(-2*time.Hour).Unix(), u.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, DeleteStaleAttachmentUploads(context.Background(), time.Hour))
+	_, err = GetAttachmentUploadByUUID(u.UUID)
+	assert.True(t, IsErrAttachmentUploadNotExist(err))
+	_, err = storage.Attachments.Stat(u.chunkPath(0))
+	assert.Error(t, err)
+}
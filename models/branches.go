@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -40,6 +41,7 @@ type ProtectedBranch struct {
 	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
 	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
 	ProtectedFilePatterns         string   `xorm:"TEXT"`
@@ -571,3 +573,64 @@ func RemoveOldDeletedBranches(ctx context.Context, olderThan time.Duration) {
 		log.Error("DeletedBranchesCleanup: %v", err)
 	}
 }
+
+// RenameBranch updates the references to a branch of the repository after it is renamed in one transaction:
+// the default branch, the protection of the branch, which replaces the one of the new name if any,
+// the base and head branches of the pull requests which have not been merged and the branches of the issues.
+// The git repository is updated by gitAction, passed whether the branch is the default one,
+// before the transaction is committed.
+func RenameBranch(repo *Repository, from, to string, gitAction func(isDefault bool) error) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	isDefault := repo.DefaultBranch == from
+	if isDefault {
+		repo.DefaultBranch = to
+		defer func() {
+			if err != nil {
+				repo.DefaultBranch = from
+			}
+		}()
+		if _, err := sess.ID(repo.ID).Cols("default_branch").Update(repo); err != nil {
+			return err
+		}
+	}
+
+	if has, err := sess.Exist(&ProtectedBranch{RepoID: repo.ID, BranchName: from}); err != nil {
+		return err
+	} else if has {
+		if _, err := sess.Delete(&ProtectedBranch{RepoID: repo.ID, BranchName: to}); err != nil {
+			return err
+		}
+		if _, err := sess.Where("repo_id = ? AND branch_name = ?", repo.ID, from).
+			Cols("branch_name").Update(&ProtectedBranch{BranchName: to}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sess.Where("base_repo_id = ? AND base_branch = ? AND has_merged = ?", repo.ID, from, false).
+		Cols("base_branch").Update(&PullRequest{BaseBranch: to}); err != nil {
+		return err
+	}
+	if _, err := sess.Where("head_repo_id = ? AND head_branch = ? AND has_merged = ? AND flow = ?", repo.ID, from, false, PullRequestFlowGithub).
+		Cols("head_branch").Update(&PullRequest{HeadBranch: to}); err != nil {
+		return err
+	}
+
+	if _, err := sess.Where("repo_id = ? AND ref = ?", repo.ID, from).
+		Cols("ref").Update(&Issue{Ref: to}); err != nil {
+		return err
+	}
+	if _, err := sess.Where("repo_id = ? AND ref = ?", repo.ID, git.BranchPrefix+from).
+		Cols("ref").Update(&Issue{Ref: git.BranchPrefix + to}); err != nil {
+		return err
+	}
+
+	if err := gitAction(isDefault); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -78,3 +79,36 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestRenameBranch(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, UpdateProtectBranch(repo, &ProtectedBranch{RepoID: repo.ID, BranchName: "master"}, WhitelistOptions{}))
+	_, err := x.ID(1).Cols("default_branch").Update(&Repository{DefaultBranch: "master"})
+	assert.NoError(t, err)
+	_, err = x.ID(1).Cols("ref").Update(&Issue{Ref: "refs/heads/master"})
+	assert.NoError(t, err)
+
+	errGit := errors.New("git failed")
+	assert.Equal(t, errGit, RenameBranch(repo, "master", "main", func(isDefault bool) error {
+		return errGit
+	}))
+	assert.Equal(t, "master", repo.DefaultBranch)
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, DefaultBranch: "master"})
+
+	gitCalled := false
+	assert.NoError(t, RenameBranch(repo, "master", "main", func(isDefault bool) error {
+		gitCalled = true
+		assert.True(t, isDefault)
+		return nil
+	}))
+	assert.True(t, gitCalled)
+	assert.Equal(t, "main", repo.DefaultBranch)
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, DefaultBranch: "main"})
+	AssertExistsAndLoadBean(t, &ProtectedBranch{RepoID: repo.ID, BranchName: "main"})
+	AssertNotExistsBean(t, &ProtectedBranch{RepoID: repo.ID, BranchName: "master"})
+	AssertExistsAndLoadBean(t, &PullRequest{ID: 2, BaseBranch: "main"})
+	// Merged pull requests keep the branch they were merged into
+	AssertExistsAndLoadBean(t, &PullRequest{ID: 1, BaseBranch: "master"})
+	AssertExistsAndLoadBean(t, &Issue{ID: 1, Ref: "refs/heads/main"})
+}
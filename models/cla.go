@@ -0,0 +1,200 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables,
+		new(CLA),
+		new(CLASignature),
+	)
+}
+
+// CLA represents a version of the contributor license agreement of an organization
+type CLA struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Version     int                `xorm:"UNIQUE(s) NOT NULL"`
+	Content     string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// CLASignature represents the signature of a version of the contributor license agreement of an organization by a user
+type CLASignature struct {
+	ID         int64              `xorm:"pk autoincr"`
+	OrgID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Version    int                `xorm:"UNIQUE(s) NOT NULL"`
+	UserID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	User       *User              `xorm:"-"`
+	FullName   string             `xorm:"NOT NULL"`
+	Email      string             `xorm:"NOT NULL"`
+	SignedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ErrCLANotExist represents a "CLANotExist" kind of error.
+type ErrCLANotExist struct {
+	OrgID int64
+}
+
+// IsErrCLANotExist checks if an error is a ErrCLANotExist.
+func IsErrCLANotExist(err error) bool {
+	_, ok := err.(ErrCLANotExist)
+	return ok
+}
+
+func (err ErrCLANotExist) Error() string {
+	return fmt.Sprintf("contributor license agreement does not exist [org_id: %d]", err.OrgID)
+}
+
+// GetCurrentCLA returns the latest version of the contributor license agreement of the organization
+func GetCurrentCLA(orgID int64) (*CLA, error) {
+	cla := new(CLA)
+	has, err := x.Where("org_id = ?", orgID).Desc("version").Get(cla)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCLANotExist{OrgID: orgID}
+	}
+	return cla, nil
+}
+
+// GetCLAVersions returns the versions of the contributor license agreement of the organization, latest first
+func GetCLAVersions(orgID int64) ([]*CLA, error) {
+	versions := make([]*CLA, 0, 5)
+	return versions, x.Where("org_id = ?", orgID).Desc("version").Find(&versions)
+}
+
+// PublishCLA publishes a new version of the contributor license agreement of the organization,
+// which all contributors have to sign again
+func PublishCLA(orgID int64, content string) (*CLA, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	current := new(CLA)
+	if _, err := sess.Where("org_id = ?", orgID).Desc("version").Get(current); err != nil {
+		return nil, err
+	}
+	cla := &CLA{
+		OrgID:   orgID,
+		Version: current.Version + 1,
+		Content: content,
+	}
+	if _, err := sess.Insert(cla); err != nil {
+		return nil, err
+	}
+	return cla, sess.Commit()
+}
+
+// GetCLASignature returns the signature of the version of the contributor license agreement
+// of the organization by the user, nil if the user has not signed it
+func GetCLASignature(orgID int64, version int, userID int64) (*CLASignature, error) {
+	sig := new(CLASignature)
+	has, err := x.Where("org_id = ? AND version = ? AND user_id = ?", orgID, version, userID).Get(sig)
+	if err != nil || !has {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// GetLatestCLASignature returns the signature of the latest version of the contributor license agreement
+// of the organization the user has signed, nil if the user has never signed it
+func GetLatestCLASignature(orgID, userID int64) (*CLASignature, error) {
+	sig := new(CLASignature)
+	has, err := x.Where("org_id = ? AND user_id = ?", orgID, userID).Desc("version").Get(sig)
+	if err != nil || !has {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// GetCLASignerIDs returns the IDs of the users who signed the version of the contributor license agreement
+// of the organization among the passed users
+func GetCLASignerIDs(orgID int64, version int, userIDs []int64) (map[int64]bool, error) {
+	signers := make(map[int64]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return signers, nil
+	}
+
+	sigs := make([]*CLASignature, 0, len(userIDs))
+	if err := x.Where("org_id = ? AND version = ?", orgID, version).
+		In("user_id", userIDs).
+		Find(&sigs); err != nil {
+		return nil, err
+	}
+	for _, sig := range sigs {
+		signers[sig.UserID] = true
+	}
+	return signers, nil
+}
+
+// GetCLASignatures returns the signatures of the version of the contributor license agreement of the organization
+func GetCLASignatures(orgID int64, version int, listOptions ListOptions) ([]*CLASignature, int64, error) {
+	sess := x.Where("org_id = ? AND version = ?", orgID, version).Desc("signed_unix")
+	if listOptions.Page > 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+
+	sigs := make([]*CLASignature, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&sigs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	userIDs := make([]int64, 0, len(sigs))
+	for _, sig := range sigs {
+		userIDs = append(userIDs, sig.UserID)
+	}
+	users, err := GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	userMap := make(map[int64]*User, len(users))
+	for _, u := range users {
+		userMap[u.ID] = u
+	}
+	for _, sig := range sigs {
+		sig.User = userMap[sig.UserID]
+	}
+	return sigs, count, nil
+}
+
+// SignCLA records the signature of the version of the contributor license agreement of the organization by the user.
+// Signing a version twice keeps the first signature.
+func SignCLA(cla *CLA, user *User, fullName string) (*CLASignature, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	sig := new(CLASignature)
+	has, err := sess.Where("org_id = ? AND version = ? AND user_id = ?", cla.OrgID, cla.Version, user.ID).Get(sig)
+	if err != nil {
+		return nil, err
+	} else if has {
+		return sig, nil
+	}
+
+	sig = &CLASignature{
+		OrgID:    cla.OrgID,
+		Version:  cla.Version,
+		UserID:   user.ID,
+		User:     user,
+		FullName: fullName,
+		Email:    user.Email,
+	}
+	if _, err := sess.Insert(sig); err != nil {
+		return nil, err
+	}
+	return sig, sess.Commit()
+}
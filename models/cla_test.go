@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLA(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := GetCurrentCLA(3)
+	assert.True(t, IsErrCLANotExist(err))
+
+	first, err := PublishCLA(3, "First version")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.Version)
+	second, err := PublishCLA(3, "Second version")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.Version)
+
+	current, err := GetCurrentCLA(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "Second version", current.Content)
+	versions, err := GetCLAVersions(3)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	sig, err := SignCLA(first, user2, "User Two")
+	assert.NoError(t, err)
+	assert.Equal(t, "user2@example.com", sig.Email)
+
+	signers, err := GetCLASignerIDs(3, second.Version, []int64{2, 4})
+	assert.NoError(t, err)
+	assert.Empty(t, signers)
+
+	again, err := SignCLA(second, user2, "User Two")
+	assert.NoError(t, err)
+	_, err = SignCLA(second, user2, "Someone Else")
+	assert.NoError(t, err)
+	AssertCount(t, &CLASignature{OrgID: 3, Version: second.Version}, 1)
+
+	signers, err = GetCLASignerIDs(3, second.Version, []int64{2, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{2: true}, signers)
+
+	latest, err := GetLatestCLASignature(3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, again.ID, latest.ID)
+	none, err := GetCLASignature(3, second.Version, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+
+	sigs, count, err := GetCLASignatures(3, second.Version, ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, sigs, 1) {
+		assert.Equal(t, "User Two", sigs[0].FullName)
+		assert.Equal(t, "user2", sigs[0].User.Name)
+	}
+}
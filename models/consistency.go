@@ -123,6 +123,9 @@ func (issue *Issue) checkForConsistency(t *testing.T) {
 	actual := getCount(t, x.Where("type=?", CommentTypeComment), &Comment{IssueID: issue.ID})
 	assert.EqualValues(t, issue.NumComments, actual,
 		"Unexpected number of comments for issue %+v", issue)
+	actual = getCount(t, x.Where("`type`=? AND comment_id=0", VoteReaction), &Reaction{IssueID: issue.ID})
+	assert.EqualValues(t, issue.NumVotes, actual,
+		"Unexpected number of votes for issue %+v", issue)
 	if issue.IsPull {
 		pr := AssertExistsAndLoadBean(t, &PullRequest{IssueID: issue.ID}).(*PullRequest)
 		assert.EqualValues(t, pr.Index, issue.Index)
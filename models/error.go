@@ -325,6 +325,23 @@ func (err ErrReachLimitOfRepo) Error() string {
 	return fmt.Sprintf("user has reached maximum limit of repositories [limit: %d]", err.Limit)
 }
 
+// ErrSizeQuotaExceeded represents a "SizeQuotaExceeded" kind of error.
+type ErrSizeQuotaExceeded struct {
+	Quota      int64
+	Used       int64
+	Additional int64
+}
+
+// IsErrSizeQuotaExceeded checks if an error is a ErrSizeQuotaExceeded.
+func IsErrSizeQuotaExceeded(err error) bool {
+	_, ok := err.(ErrSizeQuotaExceeded)
+	return ok
+}
+
+func (err ErrSizeQuotaExceeded) Error() string {
+	return fmt.Sprintf("size quota exceeded [quota: %d, used: %d, additional: %d]", err.Quota, err.Used, err.Additional)
+}
+
 //  __      __.__ __   .__
 // /  \    /  \__|  | _|__|
 // \   \/\/   /  |  |/ /  |
@@ -884,6 +901,22 @@ func (err ErrForkAlreadyExist) Error() string {
 	return fmt.Sprintf("repository is already forked by user [uname: %s, repo path: %s, fork path: %s]", err.Uname, err.RepoName, err.ForkName)
 }
 
+// ErrForkHasOpenPullRequests represents a "ForkHasOpenPullRequests" kind of error.
+type ErrForkHasOpenPullRequests struct {
+	RepoID int64
+	Count  int64
+}
+
+// IsErrForkHasOpenPullRequests checks if an error is an ErrForkHasOpenPullRequests.
+func IsErrForkHasOpenPullRequests(err error) bool {
+	_, ok := err.(ErrForkHasOpenPullRequests)
+	return ok
+}
+
+func (err ErrForkHasOpenPullRequests) Error() string {
+	return fmt.Sprintf("fork has open pull requests with other repositories [repo_id: %d, count: %d]", err.RepoID, err.Count)
+}
+
 // ErrRepoRedirectNotExist represents a "RepoRedirectNotExist" kind of error.
 type ErrRepoRedirectNotExist struct {
 	OwnerID  int64
@@ -1063,6 +1096,22 @@ func (err ErrFilenameInvalid) Error() string {
 	return fmt.Sprintf("path contains a malformed path component [path: %s]", err.Path)
 }
 
+// ErrUploadCommitTooLarge represents a "UploadCommitTooLarge" kind of error.
+type ErrUploadCommitTooLarge struct {
+	Size    int64
+	MaxSize int64
+}
+
+// IsErrUploadCommitTooLarge checks if an error is an ErrUploadCommitTooLarge.
+func IsErrUploadCommitTooLarge(err error) bool {
+	_, ok := err.(ErrUploadCommitTooLarge)
+	return ok
+}
+
+func (err ErrUploadCommitTooLarge) Error() string {
+	return fmt.Sprintf("uploaded files are too large for one commit [size: %d, max size: %d]", err.Size, err.MaxSize)
+}
+
 // ErrUserCannotCommit represents "UserCannotCommit" kind of error.
 type ErrUserCannotCommit struct {
 	UserName string
@@ -1309,6 +1358,21 @@ func (err ErrSHAOrCommitIDNotProvided) Error() string {
 	return "a SHA or commit ID must be proved when updating a file"
 }
 
+// ErrPatchNotApplicable represents a "PatchNotApplicable" kind of error.
+type ErrPatchNotApplicable struct {
+	Message string
+}
+
+// IsErrPatchNotApplicable checks if an error is a ErrPatchNotApplicable.
+func IsErrPatchNotApplicable(err error) bool {
+	_, ok := err.(ErrPatchNotApplicable)
+	return ok
+}
+
+func (err ErrPatchNotApplicable) Error() string {
+	return fmt.Sprintf("patch does not apply [message: %s]", err.Message)
+}
+
 //  __      __      ___.   .__                   __
 // /  \    /  \ ____\_ |__ |  |__   ____   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \ /  _ \ /  _ \|  |/ /
@@ -1331,6 +1395,117 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrHookTaskNotExist represents a "HookTaskNotExist" kind of error.
+type ErrHookTaskNotExist struct {
+	HookID int64
+	ID     int64
+}
+
+// IsErrHookTaskNotExist checks if an error is a ErrHookTaskNotExist.
+func IsErrHookTaskNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskNotExist)
+	return ok
+}
+
+func (err ErrHookTaskNotExist) Error() string {
+	return fmt.Sprintf("hook task does not exist [hook_id: %d, id: %d]", err.HookID, err.ID)
+}
+
+// ErrRepoEventNotExist represents a "RepoEventNotExist" kind of error.
+type ErrRepoEventNotExist struct {
+	RepoID int64
+	ID     int64
+}
+
+// IsErrRepoEventNotExist checks if an error is a ErrRepoEventNotExist.
+func IsErrRepoEventNotExist(err error) bool {
+	_, ok := err.(ErrRepoEventNotExist)
+	return ok
+}
+
+func (err ErrRepoEventNotExist) Error() string {
+	return fmt.Sprintf("repository event does not exist [repo_id: %d, id: %d]", err.RepoID, err.ID)
+}
+
+// ErrGitOperationNotExist represents a "GitOperationNotExist" kind of error.
+type ErrGitOperationNotExist struct {
+	ID int64
+}
+
+// IsErrGitOperationNotExist checks if an error is a ErrGitOperationNotExist.
+func IsErrGitOperationNotExist(err error) bool {
+	_, ok := err.(ErrGitOperationNotExist)
+	return ok
+}
+
+func (err ErrGitOperationNotExist) Error() string {
+	return fmt.Sprintf("git operation does not exist [id: %d]", err.ID)
+}
+
+// ErrRepoAccessRequestNotExist represents a "RepoAccessRequestNotExist" kind of error.
+type ErrRepoAccessRequestNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrRepoAccessRequestNotExist checks if an error is a ErrRepoAccessRequestNotExist.
+func IsErrRepoAccessRequestNotExist(err error) bool {
+	_, ok := err.(ErrRepoAccessRequestNotExist)
+	return ok
+}
+
+func (err ErrRepoAccessRequestNotExist) Error() string {
+	return fmt.Sprintf("repository access request does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrRepoAccessRequestAlreadyExist represents a "RepoAccessRequestAlreadyExist" kind of error,
+// a user can only have one pending access request per repository.
+type ErrRepoAccessRequestAlreadyExist struct {
+	RepoID int64
+	UserID int64
+}
+
+// IsErrRepoAccessRequestAlreadyExist checks if an error is a ErrRepoAccessRequestAlreadyExist.
+func IsErrRepoAccessRequestAlreadyExist(err error) bool {
+	_, ok := err.(ErrRepoAccessRequestAlreadyExist)
+	return ok
+}
+
+func (err ErrRepoAccessRequestAlreadyExist) Error() string {
+	return fmt.Sprintf("repository access request already exists [repo_id: %d, user_id: %d]", err.RepoID, err.UserID)
+}
+
+// ErrRepoAccessRequestNotPending represents a "RepoAccessRequestNotPending" kind of error,
+// an access request can only be reviewed once.
+type ErrRepoAccessRequestNotPending struct {
+	ID int64
+}
+
+// IsErrRepoAccessRequestNotPending checks if an error is a ErrRepoAccessRequestNotPending.
+func IsErrRepoAccessRequestNotPending(err error) bool {
+	_, ok := err.(ErrRepoAccessRequestNotPending)
+	return ok
+}
+
+func (err ErrRepoAccessRequestNotPending) Error() string {
+	return fmt.Sprintf("repository access request is not pending [id: %d]", err.ID)
+}
+
+// ErrPendingRegistrationNotExist represents a "PendingRegistrationNotExist" kind of error.
+type ErrPendingRegistrationNotExist struct {
+	ID int64
+}
+
+// IsErrPendingRegistrationNotExist checks if an error is a ErrPendingRegistrationNotExist.
+func IsErrPendingRegistrationNotExist(err error) bool {
+	_, ok := err.(ErrPendingRegistrationNotExist)
+	return ok
+}
+
+func (err ErrPendingRegistrationNotExist) Error() string {
+	return fmt.Sprintf("pending registration does not exist [id: %d]", err.ID)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -1947,6 +2122,22 @@ func (err ErrTwoFactorNotEnrolled) Error() string {
 	return fmt.Sprintf("user not enrolled in 2FA [uid: %d]", err.UID)
 }
 
+// ErrTwoFactorRequired indicates that an organization requires its members to be enrolled in two-factor authentication.
+type ErrTwoFactorRequired struct {
+	OrgID int64
+	UID   int64
+}
+
+// IsErrTwoFactorRequired checks if an error is a ErrTwoFactorRequired.
+func IsErrTwoFactorRequired(err error) bool {
+	_, ok := err.(ErrTwoFactorRequired)
+	return ok
+}
+
+func (err ErrTwoFactorRequired) Error() string {
+	return fmt.Sprintf("organization requires 2FA of its members [org_id: %d, uid: %d]", err.OrgID, err.UID)
+}
+
 //  ____ ___        .__                    .___
 // |    |   \______ |  |   _________     __| _/
 // |    |   /\____ \|  |  /  _ \__  \   / __ |
@@ -2198,3 +2389,17 @@ func IsErrOAuthApplicationNotFound(err error) bool {
 func (err ErrOAuthApplicationNotFound) Error() string {
 	return fmt.Sprintf("OAuth application not found [ID: %d]", err.ID)
 }
+
+// ErrOAuth2DeviceCodeNotExist will be thrown if a device or user code cannot be found
+type ErrOAuth2DeviceCodeNotExist struct{}
+
+// IsErrOAuth2DeviceCodeNotExist checks if an error is a ErrOAuth2DeviceCodeNotExist.
+func IsErrOAuth2DeviceCodeNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2DeviceCodeNotExist)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuth2DeviceCodeNotExist) Error() string {
+	return "OAuth2 device code does not exist"
+}
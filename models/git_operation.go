@@ -0,0 +1,218 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables, new(GitOperation))
+}
+
+// GitOperationType is the type of a git operation
+type GitOperationType string
+
+// Types of git operations
+const (
+	GitOperationFetch   GitOperationType = "fetch"
+	GitOperationPush    GitOperationType = "push"
+	GitOperationArchive GitOperationType = "archive"
+)
+
+// GitOperationTypeFromService returns the type of the operation of a git service like upload-pack
+func GitOperationTypeFromService(service string) GitOperationType {
+	switch strings.TrimPrefix(service, "git-") {
+	case "upload-pack":
+		return GitOperationFetch
+	case "receive-pack":
+		return GitOperationPush
+	case "upload-archive":
+		return GitOperationArchive
+	}
+	return ""
+}
+
+// Protocols of git operations
+const (
+	GitOperationProtocolSSH  = "ssh"
+	GitOperationProtocolHTTP = "http"
+)
+
+// GitOperation represents a fetch or a push of a repository, kept for auditing.
+// The operations are kept when their repository is deleted.
+type GitOperation struct {
+	ID       int64 `xorm:"pk autoincr"`
+	RepoID   int64 `xorm:"INDEX"`
+	IsWiki   bool
+	UserID   int64 `xorm:"INDEX"`
+	KeyID    int64
+	TokenID  int64
+	Type     GitOperationType `xorm:"VARCHAR(16)"`
+	Protocol string           `xorm:"VARCHAR(8)"`
+	RemoteIP string           `xorm:"VARCHAR(64) INDEX"`
+	// Refs are the references updated by a push, one "<old commit> <new commit> <ref>" per line
+	Refs          string `xorm:"TEXT"`
+	BytesReceived int64
+	BytesSent     int64
+	// Duration is in milliseconds
+	Duration    int64
+	IsFinished  bool
+	IsSucceeded bool
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+
+	Repo *Repository `xorm:"-"`
+	User *User       `xorm:"-"`
+}
+
+// RefLines returns the references updated by the operation
+func (op *GitOperation) RefLines() []string {
+	if op.Refs == "" {
+		return nil
+	}
+	return strings.Split(op.Refs, "\n")
+}
+
+// CreateGitOperation records the start of a git operation
+func CreateGitOperation(op *GitOperation) error {
+	_, err := x.Insert(op)
+	return err
+}
+
+// AddGitOperationRefs adds references updated by a push to its operation
+func AddGitOperationRefs(id int64, oldCommitIDs, newCommitIDs, refFullNames []string) error {
+	if len(refFullNames) == 0 {
+		return nil
+	}
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	op := new(GitOperation)
+	if has, err := sess.ID(id).Get(op); err != nil {
+		return err
+	} else if !has {
+		return ErrGitOperationNotExist{ID: id}
+	}
+
+	lines := op.RefLines()
+	for i := range refFullNames {
+		lines = append(lines, oldCommitIDs[i]+" "+newCommitIDs[i]+" "+refFullNames[i])
+	}
+	op.Refs = strings.Join(lines, "\n")
+	if _, err := sess.ID(id).Cols("refs").Update(op); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// FinishGitOperation records the end of a git operation
+func FinishGitOperation(id, bytesReceived, bytesSent int64, duration time.Duration, succeeded bool) error {
+	_, err := x.ID(id).Cols("bytes_received", "bytes_sent", "duration", "is_finished", "is_succeeded").Update(&GitOperation{
+		BytesReceived: bytesReceived,
+		BytesSent:     bytesSent,
+		Duration:      duration.Milliseconds(),
+		IsFinished:    true,
+		IsSucceeded:   succeeded,
+	})
+	return err
+}
+
+// FindGitOperationsOptions represents the options to search git operations
+type FindGitOperationsOptions struct {
+	ListOptions
+	RepoID   int64
+	UserID   int64
+	RemoteIP string
+	Type     GitOperationType
+}
+
+func (opts *FindGitOperationsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"user_id": opts.UserID})
+	}
+	if opts.RemoteIP != "" {
+		cond = cond.And(builder.Eq{"remote_ip": opts.RemoteIP})
+	}
+	if opts.Type != "" {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	return cond
+}
+
+// FindGitOperations returns the git operations matching the options, most recent first, and their total count
+func FindGitOperations(opts *FindGitOperationsOptions) (GitOperationList, int64, error) {
+	sess := x.Where(opts.toConds()).Desc("id")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	ops := make(GitOperationList, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&ops)
+	return ops, count, err
+}
+
+// GitOperationList is a list of git operations
+type GitOperationList []*GitOperation
+
+// LoadAttributes loads the repositories and the users of the operations
+func (ops GitOperationList) LoadAttributes() error {
+	repoIDs := make([]int64, 0, len(ops))
+	userIDs := make([]int64, 0, len(ops))
+	for _, op := range ops {
+		repoIDs = append(repoIDs, op.RepoID)
+		if op.UserID > 0 {
+			userIDs = append(userIDs, op.UserID)
+		}
+	}
+
+	repos := make(map[int64]*Repository, len(repoIDs))
+	if err := x.In("id", repoIDs).Find(&repos); err != nil {
+		return err
+	}
+	if err := RepositoryList(valuesRepository(repos)).loadAttributes(x); err != nil {
+		return err
+	}
+	users := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		op.Repo = repos[op.RepoID]
+		op.User = users[op.UserID]
+	}
+	return nil
+}
+
+// DeleteOldGitOperations deletes the git operations recorded more than olderThan ago
+func DeleteOldGitOperations(ctx context.Context, olderThan time.Duration) error {
+	log.Trace("Doing: DeleteOldGitOperations")
+
+	select {
+	case <-ctx.Done():
+		return ErrCancelledf("before deleting old git operations")
+	default:
+	}
+	deleted, err := x.Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(new(GitOperation))
+	if err != nil {
+		return err
+	}
+
+	log.Trace("Finished: DeleteOldGitOperations: %d operations deleted", deleted)
+	return nil
+}
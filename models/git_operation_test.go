@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitOperation(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.Equal(t, GitOperationFetch, GitOperationTypeFromService("git-upload-pack"))
+	assert.Equal(t, GitOperationPush, GitOperationTypeFromService("receive-pack"))
+	assert.Equal(t, GitOperationType(""), GitOperationTypeFromService("git-lfs-authenticate"))
+
+	push := &GitOperation{RepoID: 1, UserID: 2, KeyID: 1, Type: GitOperationPush, Protocol: GitOperationProtocolSSH, RemoteIP: "192.0.2.1"}
+	assert.NoError(t, CreateGitOperation(push))
+	fetch := &GitOperation{RepoID: 1, Type: GitOperationFetch, Protocol: GitOperationProtocolHTTP, RemoteIP: "192.0.2.2"}
+	assert.NoError(t, CreateGitOperation(fetch))
+
+	assert.NoError(t, AddGitOperationRefs(push.ID, []string{"a"}, []string{"b"}, []string{"refs/heads/master"}))
+	assert.NoError(t, AddGitOperationRefs(push.ID, []string{"c"}, []string{"d"}, []string{"refs/tags/v1"}))
+	assert.True(t, IsErrGitOperationNotExist(AddGitOperationRefs(NonexistentID, []string{"a"}, []string{"b"}, []string{"refs/heads/master"})))
+	assert.NoError(t, FinishGitOperation(push.ID, 100, 20, 1500*time.Millisecond, true))
+
+	ops, count, err := FindGitOperations(&FindGitOperationsOptions{ListOptions: ListOptions{Page: 1, PageSize: 10}, RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.NoError(t, ops.LoadAttributes())
+	assert.Equal(t, fetch.ID, ops[0].ID)
+	assert.Nil(t, ops[0].User)
+	assert.Equal(t, "user2/repo1", ops[1].Repo.FullName())
+	assert.Equal(t, "user2", ops[1].User.Name)
+	assert.Equal(t, []string{"a b refs/heads/master", "c d refs/tags/v1"}, ops[1].RefLines())
+	assert.EqualValues(t, 100, ops[1].BytesReceived)
+	assert.EqualValues(t, 20, ops[1].BytesSent)
+	assert.EqualValues(t, 1500, ops[1].Duration)
+	assert.True(t, ops[1].IsFinished)
+	assert.True(t, ops[1].IsSucceeded)
+
+	ops, count, err = FindGitOperations(&FindGitOperationsOptions{RemoteIP: "192.0.2.1", Type: GitOperationPush})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Equal(t, push.ID, ops[0].ID)
+
+	assert.NoError(t, DeleteOldGitOperations(context.Background(), time.Hour))
+	AssertCount(t, new(GitOperation), 2)
+	assert.NoError(t, DeleteOldGitOperations(context.Background(), -time.Minute))
+	AssertCount(t, new(GitOperation), 0)
+}
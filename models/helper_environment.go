@@ -26,6 +26,8 @@ const (
 	EnvPRID         = "GITEA_PR_ID"
 	EnvIsInternal   = "GITEA_INTERNAL_PUSH"
 	EnvAppURL       = "GITEA_ROOT_URL"
+	// EnvGitOperationID is the id of the GitOperation recording the push
+	EnvGitOperationID = "GITEA_GIT_OPERATION_ID"
 )
 
 // InternalPushingEnvironment returns an os environment to switch off hooks on push
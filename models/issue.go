@@ -50,6 +50,7 @@ type Issue struct {
 	IsPull           bool         `xorm:"INDEX"` // Indicates whether is a pull request or not.
 	PullRequest      *PullRequest `xorm:"-"`
 	NumComments      int
+	NumVotes         int `xorm:"NOT NULL DEFAULT 0"`
 	Ref              string
 
 	DeadlineUnix timeutil.TimeStamp `xorm:"INDEX"`
@@ -657,6 +658,12 @@ func (issue *Issue) doChangeStatus(e *xorm.Session, doer *User, isMergePull bool
 		return nil, err
 	}
 
+	if issue.IsClosed {
+		if err := issue.autoMoveProjectBoard(e, isMergePull); err != nil {
+			return nil, err
+		}
+	}
+
 	// New action comment
 	cmtType := CommentTypeClose
 	if !issue.IsClosed {
@@ -1129,6 +1136,10 @@ func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID int64
 		sess.Desc("issue.num_comments")
 	case "leastcomment":
 		sess.Asc("issue.num_comments")
+	case "mostvotes":
+		sess.Desc("issue.num_votes").Desc("issue.created_unix")
+	case "leastvotes":
+		sess.Asc("issue.num_votes").Desc("issue.created_unix")
 	case "priority":
 		sess.Desc("issue.priority")
 	case "nearduedate":
@@ -102,6 +102,10 @@ const (
 	CommentTypeProjectBoard
 	// Dismiss Review
 	CommentTypeDismissReview
+	// 33 pull request scheduled to be merged when checks succeed
+	CommentTypePRScheduledToAutoMerge
+	// 34 scheduled merge of a pull request canceled
+	CommentTypePRUnScheduledToAutoMerge
 )
 
 // CommentTag defines comment tag type
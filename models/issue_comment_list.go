@@ -4,6 +4,8 @@
 
 package models
 
+import "xorm.io/builder"
+
 // CommentList defines a list of comments
 type CommentList []*Comment
 
@@ -541,3 +543,12 @@ func (comments CommentList) LoadPosters() error {
 func (comments CommentList) LoadIssues() error {
 	return comments.loadIssues(x)
 }
+
+// GetReactionCounts returns the number of reactions of each allowed type to the comments by comment ID
+func (comments CommentList) GetReactionCounts() (map[int64]map[string]int64, error) {
+	return comments.getReactionCounts(x)
+}
+
+func (comments CommentList) getReactionCounts(e Engine) (map[int64]map[string]int64, error) {
+	return countReactionsBy(e, "comment_id", comments.getCommentIDs(), builder.NewCond())
+}
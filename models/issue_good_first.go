@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"xorm.io/builder"
+)
+
+// FindGoodFirstIssuesOptions represents the options to find the beginner-friendly issues
+type FindGoodFirstIssuesOptions struct {
+	ListOptions
+	LabelNames []string
+}
+
+func (opts *FindGoodFirstIssuesOptions) toCond() builder.Cond {
+	labelNames := make([]string, 0, len(opts.LabelNames))
+	for _, name := range opts.LabelNames {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			labelNames = append(labelNames, name)
+		}
+	}
+
+	return builder.And(
+		builder.Eq{"issue.is_closed": false, "issue.is_pull": false},
+		builder.In("issue.id", builder.Select("issue_label.issue_id").
+			From("issue_label").
+			Join("INNER", "label", "label.id = issue_label.label_id").
+			Where(builder.In("LOWER(label.name)", labelNames))),
+		// Only list the issues of the public repositories with enabled issues
+		builder.In("issue.repo_id", builder.Select("id").
+			From("repository").
+			Where(builder.And(
+				accessibleRepositoryCondition(nil),
+				builder.Eq{"`repository`.is_archived": false},
+				builder.In("`repository`.id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": UnitTypeIssues})),
+			))),
+	)
+}
+
+// FindGoodFirstIssues returns the open issues of public repositories labelled with one of the given label names
+func FindGoodFirstIssues(opts FindGoodFirstIssuesOptions) (IssueList, int64, error) {
+	if len(opts.LabelNames) == 0 {
+		return IssueList{}, 0, nil
+	}
+
+	cond := opts.toCond()
+	count, err := x.Where(cond).Count(new(Issue))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(cond).Desc("issue.created_unix")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	issues := make(IssueList, 0, opts.PageSize)
+	if err := sess.Find(&issues); err != nil {
+		return nil, 0, err
+	}
+	if err := issues.LoadAttributes(); err != nil {
+		return nil, 0, err
+	}
+	return issues, count, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindGoodFirstIssues(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issues, count, err := FindGoodFirstIssues(FindGoodFirstIssuesOptions{LabelNames: []string{"LABEL1 "}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+		assert.NotNil(t, issues[0].Repo)
+	}
+
+	// Closed issues and pull requests are not listed
+	_, count, err = FindGoodFirstIssues(FindGoodFirstIssuesOptions{LabelNames: []string{"label2", "orglabel4"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	issues, count, err = FindGoodFirstIssues(FindGoodFirstIssuesOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	assert.Len(t, issues, 0)
+}
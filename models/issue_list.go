@@ -555,3 +555,13 @@ func (issues IssueList) getApprovalCounts(e Engine) (map[int64][]*ReviewCount, e
 
 	return approvalCountMap, nil
 }
+
+// GetReactionCounts returns the number of reactions of each allowed type to the issues by issue ID,
+// the reactions to their comments are not counted
+func (issues IssueList) GetReactionCounts() (map[int64]map[string]int64, error) {
+	return issues.getReactionCounts(x)
+}
+
+func (issues IssueList) getReactionCounts(e Engine) (map[int64]map[string]int64, error) {
+	return countReactionsBy(e, "issue_id", issues.getIssueIDs(), builder.Eq{"reaction.comment_id": 0})
+}
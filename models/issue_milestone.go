@@ -465,6 +465,63 @@ func GetMilestonesByRepoIDs(repoIDs []int64, page int, isClosed bool, sortType s
 	)
 }
 
+// GetMilestoneIDsByRepoIDs returns the ids of the milestones of the given repositories ordered by due date.
+func GetMilestoneIDsByRepoIDs(repoIDs []int64, isClosed bool) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	if len(repoIDs) == 0 {
+		return ids, nil
+	}
+	return ids, x.Table("milestone").
+		In("repo_id", repoIDs).
+		And("is_closed = ?", isClosed).
+		Asc("deadline_unix").
+		Asc("id").
+		Cols("id").
+		Find(&ids)
+}
+
+// GetMilestonesByIDs returns the milestones with the given ids in the same order, ignoring the missing ones.
+func GetMilestonesByIDs(ids []int64) (MilestoneList, error) {
+	milestonesMap := make(map[int64]*Milestone, len(ids))
+	if len(ids) > 0 {
+		if err := x.In("id", ids).Find(&milestonesMap); err != nil {
+			return nil, err
+		}
+	}
+
+	milestones := make(MilestoneList, 0, len(milestonesMap))
+	for _, id := range ids {
+		if milestone, ok := milestonesMap[id]; ok {
+			milestones = append(milestones, milestone)
+		}
+	}
+	return milestones, nil
+}
+
+// LoadRepos loads the repositories of the milestones
+func (milestones MilestoneList) LoadRepos() error {
+	repoIDs := make([]int64, 0, len(milestones))
+	for _, milestone := range milestones {
+		if milestone.Repo == nil {
+			repoIDs = append(repoIDs, milestone.RepoID)
+		}
+	}
+	if len(repoIDs) == 0 {
+		return nil
+	}
+
+	repos, err := GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		if milestone.Repo == nil {
+			milestone.Repo = repos[milestone.RepoID]
+		}
+	}
+	return nil
+}
+
 //  ____  _        _
 // / ___|| |_ __ _| |_ ___
 // \___ \| __/ _` | __/ __|
@@ -375,3 +375,34 @@ func TestGetMilestonesStats(t *testing.T) {
 	assert.EqualValues(t, repo1.NumOpenMilestones+repo2.NumOpenMilestones, milestoneStats.OpenCount)
 	assert.EqualValues(t, repo1.NumClosedMilestones+repo2.NumClosedMilestones, milestoneStats.ClosedCount)
 }
+
+func TestGetMilestoneIDsByRepoIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	ids, err := GetMilestoneIDsByRepoIDs([]int64{1, 42}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 4}, ids)
+
+	ids, err = GetMilestoneIDsByRepoIDs([]int64{1, 42}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, ids)
+
+	ids, err = GetMilestoneIDsByRepoIDs(nil, false)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 0)
+}
+
+func TestGetMilestonesByIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	milestones, err := GetMilestonesByIDs([]int64{4, 1, NonexistentID})
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 2) {
+		assert.EqualValues(t, 4, milestones[0].ID)
+		assert.EqualValues(t, 1, milestones[1].ID)
+	}
+
+	assert.NoError(t, milestones.LoadRepos())
+	assert.EqualValues(t, 42, milestones[0].Repo.ID)
+	assert.EqualValues(t, 1, milestones[1].Repo.ID)
+}
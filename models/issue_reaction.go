@@ -97,6 +97,63 @@ func findReactions(e Engine, opts FindReactionsOptions) ([]*Reaction, error) {
 	return reactions, e.Find(&reactions)
 }
 
+// CountReactions returns the number of reactions of each allowed type matching the options
+func CountReactions(opts FindReactionsOptions) (map[string]int64, error) {
+	return countReactions(x, opts)
+}
+
+func countReactions(e Engine, opts FindReactionsOptions) (map[string]int64, error) {
+	counts := make([]*struct {
+		Type  string
+		Count int64
+	}, 0, len(setting.UI.Reactions))
+	if err := e.Table("reaction").
+		Select("reaction.`type` AS type, COUNT(*) AS count").
+		Where(opts.toConds()).
+		In("reaction.`type`", setting.UI.Reactions).
+		GroupBy("reaction.`type`").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+
+	countsMap := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		countsMap[c.Type] = c.Count
+	}
+	return countsMap, nil
+}
+
+// countReactionsBy returns the number of reactions of each allowed type matching cond
+// for each of the ids of the issues or comments in column, with a single grouped query
+func countReactionsBy(e Engine, column string, ids []int64, cond builder.Cond) (map[int64]map[string]int64, error) {
+	countsMap := make(map[int64]map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return countsMap, nil
+	}
+	counts := make([]*struct {
+		ID    int64
+		Type  string
+		Count int64
+	}, 0, len(ids))
+	if err := e.Table("reaction").
+		Select("reaction."+column+" AS id, reaction.`type` AS type, COUNT(*) AS count").
+		Where(cond).
+		In("reaction."+column, ids).
+		In("reaction.`type`", setting.UI.Reactions).
+		GroupBy("reaction." + column + ", reaction.`type`").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+
+	for _, c := range counts {
+		if countsMap[c.ID] == nil {
+			countsMap[c.ID] = make(map[string]int64)
+		}
+		countsMap[c.ID][c.Type] = c.Count
+	}
+	return countsMap, nil
+}
+
 func createReaction(e *xorm.Session, opts *ReactionOptions) (*Reaction, error) {
 	reaction := &Reaction{
 		Type:    opts.Type,
@@ -126,6 +183,12 @@ func createReaction(e *xorm.Session, opts *ReactionOptions) (*Reaction, error) {
 		return nil, err
 	}
 
+	if reaction.IsVote() {
+		if err := updateIssueNumVotes(e, reaction.IssueID); err != nil {
+			return nil, err
+		}
+	}
+
 	return reaction, nil
 }
 
@@ -192,8 +255,14 @@ func deleteReaction(e Engine, opts *ReactionOptions) error {
 	if opts.Comment != nil {
 		reaction.CommentID = opts.Comment.ID
 	}
-	_, err := e.Where("original_author_id = 0").Delete(reaction)
-	return err
+	if _, err := e.Where("original_author_id = 0").Delete(reaction); err != nil {
+		return err
+	}
+
+	if reaction.IsVote() {
+		return updateIssueNumVotes(e, reaction.IssueID)
+	}
+	return nil
 }
 
 // DeleteReaction deletes reaction for issue or comment.
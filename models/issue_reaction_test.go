@@ -164,3 +164,43 @@ func TestIssueCommentReactionCount(t *testing.T) {
 
 	AssertNotExistsBean(t, &Reaction{Type: "heart", UserID: user1.ID, IssueID: issue1.ID, CommentID: comment1.ID})
 }
+
+func TestCountReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	counts, err := CountReactions(FindReactionsOptions{IssueID: 1, CommentID: -1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"eyes": 1}, counts)
+
+	counts, err = CountReactions(FindReactionsOptions{IssueID: 1, CommentID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"laugh": 2}, counts)
+
+	counts, err = CountReactions(FindReactionsOptions{IssueID: 2, CommentID: -1})
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestGetReactionCounts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issues := IssueList{
+		AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue),
+		AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue),
+	}
+	issueCounts, err := issues.GetReactionCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]map[string]int64{1: {"eyes": 1}}, issueCounts)
+
+	comments := CommentList{
+		AssertExistsAndLoadBean(t, &Comment{ID: 1}).(*Comment),
+		AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment),
+	}
+	commentCounts, err := comments.GetReactionCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]map[string]int64{2: {"laugh": 2}}, commentCounts)
+
+	commentCounts, err = CommentList{}.GetReactionCounts()
+	assert.NoError(t, err)
+	assert.Empty(t, commentCounts)
+}
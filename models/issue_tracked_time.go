@@ -214,6 +214,34 @@ func TotalTimes(options FindTrackedTimesOptions) (map[*User]string, error) {
 	return totalTimes, nil
 }
 
+// CountTrackedTimes returns count of tracked times that fit to the given options.
+func CountTrackedTimes(opts FindTrackedTimesOptions) (int64, error) {
+	opts.Page = 0
+	return opts.ToSession(x).Count(&TrackedTime{})
+}
+
+// GetTrackedSecondsPerRepo returns the sum of tracked seconds for each repository
+func GetTrackedSecondsPerRepo(opts FindTrackedTimesOptions) (map[int64]int64, error) {
+	rows := make([]*struct {
+		RepoID int64
+		Time   int64
+	}, 0, 10)
+	if err := x.Table("tracked_time").
+		Join("INNER", "issue", "issue.id = tracked_time.issue_id").
+		Where(opts.ToCond()).
+		Select("issue.repo_id AS repo_id, SUM(tracked_time.time) AS time").
+		GroupBy("issue.repo_id").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		totals[row.RepoID] = row.Time
+	}
+	return totals, nil
+}
+
 // DeleteIssueUserTimes deletes times for issue
 func DeleteIssueUserTimes(issue *Issue, user *User) error {
 	sess := x.NewSession()
@@ -111,3 +111,19 @@ func TestTotalTimes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, total, 2)
 }
+
+func TestGetTrackedSecondsPerRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	totals, err := GetTrackedSecondsPerRepo(FindTrackedTimesOptions{UserID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 420, 2: 71}, totals)
+
+	totals, err = GetTrackedSecondsPerRepo(FindTrackedTimesOptions{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 3663, 2: 3}, totals)
+
+	count, err := CountTrackedTimes(FindTrackedTimesOptions{UserID: 2, ListOptions: ListOptions{Page: 1, PageSize: 1}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables,
+		new(IssueTriageRule),
+		new(IssueTriageRun),
+	)
+}
+
+// IssueTriageCondition is the condition an issue has to fulfill for the actions of a triage rule to be applied
+type IssueTriageCondition int
+
+const (
+	// IssueTriageTitleMatches matches the issues whose title matches a regular expression
+	IssueTriageTitleMatches IssueTriageCondition = iota + 1
+	// IssueTriageMissingSections matches the issues whose description lacks sections of the issue template
+	IssueTriageMissingSections
+	// IssueTriageAuthorIsMember matches the issues opened by a member of the organization owning the repository
+	IssueTriageAuthorIsMember
+)
+
+var issueTriageConditionNames = map[IssueTriageCondition]string{
+	IssueTriageTitleMatches:    "title_matches",
+	IssueTriageMissingSections: "missing_sections",
+	IssueTriageAuthorIsMember:  "author_is_member",
+}
+
+// Name returns the name of the condition used in forms and exports
+func (c IssueTriageCondition) Name() string {
+	return issueTriageConditionNames[c]
+}
+
+// IssueTriageConditionFromName returns the condition of the passed name, 0 if there is none
+func IssueTriageConditionFromName(name string) IssueTriageCondition {
+	for condition, conditionName := range issueTriageConditionNames {
+		if conditionName == name {
+			return condition
+		}
+	}
+	return 0
+}
+
+// IssueTriageRule represents a rule labelling, commenting on or assigning the issues of a repository
+// matching its condition when they are opened or edited
+type IssueTriageRule struct {
+	ID        int64                `xorm:"pk autoincr"`
+	RepoID    int64                `xorm:"INDEX NOT NULL"`
+	Condition IssueTriageCondition `xorm:"NOT NULL DEFAULT 0"`
+	// Pattern is the regular expression matching the title, or the required sections, one per line
+	Pattern string `xorm:"TEXT"`
+	LabelID int64  `xorm:"NOT NULL DEFAULT 0"`
+	Label   *Label `xorm:"-"`
+	TeamID  int64  `xorm:"NOT NULL DEFAULT 0"`
+	Team    *Team  `xorm:"-"`
+	Comment string `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IssueTriageRun records that the actions of a triage rule have been applied to an issue,
+// so that they are not applied again when the issue is edited
+type IssueTriageRun struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RuleID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// Sections returns the sections required by a missing sections rule
+func (rule *IssueTriageRule) Sections() []string {
+	sections := make([]string, 0, 5)
+	for _, line := range strings.Split(rule.Pattern, "\n") {
+		if section := strings.TrimSpace(line); section != "" {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// LoadAttributes loads the label and the team of the rule.
+// They are left nil if they have been deleted since the rule was saved.
+func (rule *IssueTriageRule) LoadAttributes() error {
+	if rule.Label == nil && rule.LabelID > 0 {
+		label, err := GetLabelByID(rule.LabelID)
+		if err != nil && !IsErrLabelNotExist(err) {
+			return err
+		}
+		rule.Label = label
+	}
+	if rule.Team == nil && rule.TeamID > 0 {
+		team, err := GetTeamByID(rule.TeamID)
+		if err != nil && !IsErrTeamNotExist(err) {
+			return err
+		}
+		rule.Team = team
+	}
+	return nil
+}
+
+// GetIssueTriageRules returns the triage rules of the repository in the order they are applied
+func GetIssueTriageRules(repoID int64) ([]*IssueTriageRule, error) {
+	rules := make([]*IssueTriageRule, 0, 5)
+	if err := x.Where("repo_id = ?", repoID).Asc("id").Find(&rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if err := rule.LoadAttributes(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// GetIssueTriageRuleByID returns the triage rule of the repository by ID, nil if it does not exist
+func GetIssueTriageRuleByID(repoID, id int64) (*IssueTriageRule, error) {
+	rule := new(IssueTriageRule)
+	has, err := x.Where("id = ? AND repo_id = ?", id, repoID).Get(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	return rule, rule.LoadAttributes()
+}
+
+// InsertIssueTriageRule inserts a triage rule to database
+func InsertIssueTriageRule(rule *IssueTriageRule) error {
+	_, err := x.Insert(rule)
+	return err
+}
+
+// UpdateIssueTriageRule updates the triage rule
+func UpdateIssueTriageRule(rule *IssueTriageRule) error {
+	_, err := x.ID(rule.ID).AllCols().Update(rule)
+	return err
+}
+
+// DeleteIssueTriageRule deletes a triage rule and the record of the issues it has been applied to
+func DeleteIssueTriageRule(rule *IssueTriageRule) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&IssueTriageRun{RuleID: rule.ID}); err != nil {
+		return err
+	}
+	if _, err := sess.ID(rule.ID).Delete(new(IssueTriageRule)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// MarkIssueTriageRuleApplied records that the triage rule is applied to the issue.
+// It returns false if the rule has already been applied to it.
+func MarkIssueTriageRuleApplied(ruleID, issueID int64) (bool, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return false, err
+	}
+
+	if has, err := sess.Exist(&IssueTriageRun{RuleID: ruleID, IssueID: issueID}); err != nil || has {
+		return false, err
+	}
+	if _, err := sess.Insert(&IssueTriageRun{RuleID: ruleID, IssueID: issueID}); err != nil {
+		return false, err
+	}
+	return true, sess.Commit()
+}
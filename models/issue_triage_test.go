@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueTriageRules(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	rule := &IssueTriageRule{
+		RepoID:    3,
+		Condition: IssueTriageMissingSections,
+		Pattern:   "Description\n\n  Logs  \n",
+		LabelID:   1,
+		TeamID:    2,
+	}
+	assert.NoError(t, InsertIssueTriageRule(rule))
+	assert.Equal(t, []string{"Description", "Logs"}, rule.Sections())
+
+	rules, err := GetIssueTriageRules(3)
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 1) {
+		assert.Equal(t, "label1", rules[0].Label.Name)
+		assert.Equal(t, "team1", rules[0].Team.Name)
+	}
+
+	got, err := GetIssueTriageRuleByID(1, rule.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	rule.Condition = IssueTriageTitleMatches
+	rule.Pattern = "crash"
+	rule.LabelID = 0
+	assert.NoError(t, UpdateIssueTriageRule(rule))
+	got, err = GetIssueTriageRuleByID(3, rule.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "title_matches", got.Condition.Name())
+	assert.Nil(t, got.Label)
+
+	applied, err := MarkIssueTriageRuleApplied(rule.ID, 6)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	applied, err = MarkIssueTriageRuleApplied(rule.ID, 6)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+
+	assert.NoError(t, DeleteIssueTriageRule(rule))
+	AssertNotExistsBean(t, &IssueTriageRule{ID: rule.ID})
+	AssertNotExistsBean(t, &IssueTriageRun{RuleID: rule.ID})
+}
+
+func TestIssueTriageConditionFromName(t *testing.T) {
+	assert.Equal(t, IssueTriageAuthorIsMember, IssueTriageConditionFromName("author_is_member"))
+	assert.EqualValues(t, 0, IssueTriageConditionFromName("unknown"))
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/log"
+)
+
+// VoteReaction is the type of the reactions to an issue counted as votes for it
+const VoteReaction = "+1"
+
+// IsVote returns whether the reaction is a vote for its issue
+func (r *Reaction) IsVote() bool {
+	return r.Type == VoteReaction && r.CommentID == 0
+}
+
+func updateIssueNumVotes(e Engine, issueID int64) error {
+	_, err := e.Exec("UPDATE `issue` SET num_votes = (SELECT COUNT(*) FROM `reaction` WHERE issue_id = ? AND comment_id = 0 AND `type` = ?) WHERE id = ?",
+		issueID, VoteReaction, issueID)
+	return err
+}
+
+// IsVotingEnabled returns if voting is enabled for the issues of the repository
+func (repo *Repository) IsVotingEnabled() bool {
+	u, err := repo.GetUnit(UnitTypeIssues)
+	if err != nil {
+		log.Trace("%s", err)
+		return false
+	}
+	return u.IssuesConfig().EnableVoting
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueVotes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+
+	addReaction(t, user1, issue2, nil, VoteReaction)
+	addReaction(t, user2, issue2, nil, VoteReaction)
+	addReaction(t, user1, issue1, nil, VoteReaction)
+	addReaction(t, user1, issue1, nil, "heart")
+	addReaction(t, user2, issue1, comment, VoteReaction)
+
+	AssertExistsAndLoadBean(t, &Issue{ID: 1, NumVotes: 1})
+	AssertExistsAndLoadBean(t, &Issue{ID: 2, NumVotes: 2})
+	CheckConsistencyFor(t, &Issue{})
+
+	issues, err := Issues(&IssuesOptions{
+		RepoIDs:  []int64{1},
+		IsClosed: util.OptionalBoolNone,
+		SortType: "mostvotes",
+	})
+	assert.NoError(t, err)
+	if assert.True(t, len(issues) > 2) {
+		assert.EqualValues(t, 2, issues[0].ID)
+		assert.EqualValues(t, 1, issues[1].ID)
+	}
+
+	assert.NoError(t, DeleteIssueReaction(user2, issue2, VoteReaction))
+	AssertExistsAndLoadBean(t, &Issue{ID: 2, NumVotes: 1})
+	CheckConsistencyFor(t, &Issue{})
+}
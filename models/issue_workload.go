@@ -0,0 +1,174 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// WorkloadAge counts open issues and pull requests by the time elapsed since they were created
+type WorkloadAge struct {
+	UnderWeek    int64
+	UnderMonth   int64
+	UnderQuarter int64
+	Older        int64
+}
+
+func (age *WorkloadAge) add(createdUnix, now timeutil.TimeStamp) {
+	const day = 24 * 60 * 60
+	switch elapsed := now - createdUnix; {
+	case elapsed < 7*day:
+		age.UnderWeek++
+	case elapsed < 30*day:
+		age.UnderMonth++
+	case elapsed < 90*day:
+		age.UnderQuarter++
+	default:
+		age.Older++
+	}
+}
+
+// AssigneeWorkload summarizes the open work of a user
+type AssigneeWorkload struct {
+	Assignee   *User
+	OpenIssues int64
+	OpenPulls  int64
+	Age        WorkloadAge
+	// PendingReviews is the number of open pull requests waiting for a review of the user,
+	// review requests made to a team are not included
+	PendingReviews int64
+}
+
+// NumOpen returns the number of open issues and pull requests assigned to the user
+func (w *AssigneeWorkload) NumOpen() int64 {
+	return w.OpenIssues + w.OpenPulls
+}
+
+// GetAssigneeWorkloads returns the workload of every user who is assigned to an open issue of issueRepoIDs,
+// or who is assigned to or requested to review an open pull request of pullRepoIDs.
+// The busiest users are returned first.
+func GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs []int64) ([]*AssigneeWorkload, error) {
+	workloads := make(map[int64]*AssigneeWorkload)
+	getWorkload := func(userID int64) *AssigneeWorkload {
+		w, ok := workloads[userID]
+		if !ok {
+			w = &AssigneeWorkload{}
+			workloads[userID] = w
+		}
+		return w
+	}
+
+	issueCond := builder.Or(
+		builder.In("issue.repo_id", issueRepoIDs).And(builder.Eq{"issue.is_pull": false}),
+		builder.In("issue.repo_id", pullRepoIDs).And(builder.Eq{"issue.is_pull": true}),
+	).And(builder.Eq{"issue.is_closed": false})
+
+	type assignedIssue struct {
+		AssigneeID  int64
+		IsPull      bool
+		CreatedUnix timeutil.TimeStamp
+	}
+	assigned := make([]*assignedIssue, 0, 50)
+	if err := x.Table("issue_assignees").
+		Join("INNER", "issue", "issue.id = issue_assignees.issue_id").
+		Where(issueCond).
+		Select("issue_assignees.assignee_id, issue.is_pull, issue.created_unix").
+		Find(&assigned); err != nil {
+		return nil, err
+	}
+
+	now := timeutil.TimeStampNow()
+	for _, issue := range assigned {
+		w := getWorkload(issue.AssigneeID)
+		if issue.IsPull {
+			w.OpenPulls++
+		} else {
+			w.OpenIssues++
+		}
+		w.Age.add(issue.CreatedUnix, now)
+	}
+
+	// Only the latest review of a reviewer tells whether a review request is still pending
+	type reviewerCount struct {
+		ReviewerID int64
+		Count      int64
+	}
+	pending := make([]*reviewerCount, 0, 10)
+	if len(pullRepoIDs) > 0 {
+		openPulls := builder.Select("id").From("issue").
+			Where(builder.In("repo_id", pullRepoIDs).And(builder.Eq{"is_pull": true, "is_closed": false}))
+		latestReviews := builder.Select("max(id)").From("review").
+			Where(builder.In("issue_id", openPulls).
+				And(builder.In("type", ReviewTypeApprove, ReviewTypeReject, ReviewTypeRequest)).
+				And(builder.Eq{"reviewer_team_id": 0})).
+			GroupBy("issue_id, reviewer_id")
+		if err := x.Table("review").
+			Where(builder.In("id", latestReviews).
+				And(builder.Eq{"type": ReviewTypeRequest, "dismissed": false}).
+				And(builder.Gt{"reviewer_id": 0})).
+			Select("reviewer_id, COUNT(*) AS count").
+			GroupBy("reviewer_id").
+			Find(&pending); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range pending {
+		getWorkload(p.ReviewerID).PendingReviews = p.Count
+	}
+
+	userIDs := make([]int64, 0, len(workloads))
+	for userID := range workloads {
+		userIDs = append(userIDs, userID)
+	}
+	users, err := GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users are sorted by name, keep that order for users with the same workload
+	result := make([]*AssigneeWorkload, 0, len(users))
+	for _, user := range users {
+		w := workloads[user.ID]
+		w.Assignee = user
+		result = append(result, w)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NumOpen()+result[i].PendingReviews > result[j].NumOpen()+result[j].PendingReviews
+	})
+	return result, nil
+}
+
+// GetOrgAssigneeWorkloads returns the workloads of the repositories owned by org,
+// only counting the issues and pull requests doer is allowed to read
+func GetOrgAssigneeWorkloads(org, doer *User) ([]*AssigneeWorkload, error) {
+	repos, _, err := SearchRepository(&SearchRepoOptions{
+		Actor:   doer,
+		OwnerID: org.ID,
+		Private: doer != nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issueRepoIDs := make([]int64, 0, len(repos))
+	pullRepoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		perm, err := GetUserRepoPermission(repo, doer)
+		if err != nil {
+			return nil, err
+		}
+		if perm.CanRead(UnitTypeIssues) {
+			issueRepoIDs = append(issueRepoIDs, repo.ID)
+		}
+		if perm.CanRead(UnitTypePullRequests) {
+			pullRepoIDs = append(pullRepoIDs, repo.ID)
+		}
+	}
+	return GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs)
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAssigneeWorkloads(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	workloads, err := GetAssigneeWorkloads([]int64{1, 3}, []int64{1, 3})
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 2) {
+		// user1 is assigned to issues 1 and 6 and is requested to review pull 12
+		assert.EqualValues(t, 1, workloads[0].Assignee.ID)
+		assert.EqualValues(t, 2, workloads[0].OpenIssues)
+		assert.EqualValues(t, 0, workloads[0].OpenPulls)
+		assert.EqualValues(t, 1, workloads[0].PendingReviews)
+		assert.Equal(t, WorkloadAge{Older: 2}, workloads[0].Age)
+
+		assert.EqualValues(t, 2, workloads[1].Assignee.ID)
+		assert.EqualValues(t, 1, workloads[1].NumOpen())
+		assert.EqualValues(t, 0, workloads[1].PendingReviews)
+	}
+
+	// the review requests are only counted for the repositories whose pull requests are included
+	workloads, err = GetAssigneeWorkloads([]int64{3}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 2) {
+		assert.EqualValues(t, 1, workloads[0].NumOpen())
+		assert.EqualValues(t, 0, workloads[0].PendingReviews)
+	}
+
+	workloads, err = GetAssigneeWorkloads(nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, workloads)
+}
+
+func TestGetOrgAssigneeWorkloads(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	member := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	workloads, err := GetOrgAssigneeWorkloads(org, member)
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 2) {
+		assert.EqualValues(t, 1, workloads[0].Assignee.ID)
+		assert.EqualValues(t, 1, workloads[0].OpenIssues)
+		assert.EqualValues(t, 1, workloads[0].PendingReviews)
+	}
+
+	// the repositories of the organization are private
+	workloads, err = GetOrgAssigneeWorkloads(org, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, workloads)
+}
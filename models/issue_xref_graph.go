@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/references"
+
+	"xorm.io/builder"
+)
+
+// MaxIssueReferenceGraphDepth is the maximum number of references followed from the issue of a graph
+const MaxIssueReferenceGraphDepth = 5
+
+// IssueReferenceGraph represents the cross-references between issues, pull requests and commits
+// reachable from an issue
+type IssueReferenceGraph struct {
+	// Issues are the issues and pull requests of the graph, with their repository loaded
+	Issues map[int64]*Issue
+	// References are the reference comments linking the nodes of the graph, ordered by creation
+	References []*Comment
+}
+
+// GetIssueReferenceGraph follows the cross-references of the issue in both directions up to the given depth.
+// Neutered references, issues the doer is not allowed to read and the references from or to them are left out.
+func GetIssueReferenceGraph(issue *Issue, doer *User, depth int) (*IssueReferenceGraph, error) {
+	if err := issue.loadRepo(x); err != nil {
+		return nil, err
+	}
+
+	graph := &IssueReferenceGraph{
+		Issues:     map[int64]*Issue{issue.ID: issue},
+		References: make([]*Comment, 0, 10),
+	}
+	hidden := make(map[int64]bool)
+	perms := make(map[int64]Permission)
+	seen := make(map[int64]bool)
+
+	frontier := []int64{issue.ID}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		refs := make([]*Comment, 0, 10)
+		if err := x.
+			Where(builder.In("type", CommentTypeIssueRef, CommentTypeCommitRef, CommentTypeCommentRef, CommentTypePullRef)).
+			And(builder.Neq{"ref_action": references.XRefActionNeutered}).
+			And(builder.In("issue_id", frontier).Or(builder.In("ref_issue_id", frontier))).
+			Asc("id").
+			Find(&refs); err != nil {
+			return nil, err
+		}
+
+		unknown := make([]int64, 0, len(refs))
+		for _, ref := range refs {
+			for _, id := range []int64{ref.IssueID, ref.RefIssueID} {
+				if _, ok := graph.Issues[id]; !ok && id > 0 && !hidden[id] {
+					unknown = append(unknown, id)
+				}
+			}
+		}
+
+		frontier = frontier[:0]
+		if len(unknown) > 0 {
+			issues, err := getIssuesByIDs(x, unknown)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = IssueList(issues).loadRepositories(x); err != nil {
+				return nil, err
+			}
+			for _, is := range issues {
+				if _, ok := graph.Issues[is.ID]; ok || hidden[is.ID] {
+					continue
+				}
+				perm, ok := perms[is.RepoID]
+				if !ok {
+					if perm, err = getUserRepoPermission(x, is.Repo, doer); err != nil {
+						return nil, err
+					}
+					perms[is.RepoID] = perm
+				}
+				if !perm.CanReadIssuesOrPulls(is.IsPull) {
+					hidden[is.ID] = true
+					continue
+				}
+				graph.Issues[is.ID] = is
+				frontier = append(frontier, is.ID)
+			}
+		}
+
+		for _, ref := range refs {
+			if seen[ref.ID] {
+				continue
+			}
+			seen[ref.ID] = true
+			if _, ok := graph.Issues[ref.IssueID]; !ok {
+				continue
+			}
+			if _, ok := graph.Issues[ref.RefIssueID]; !ok && ref.Type != CommentTypeCommitRef {
+				continue
+			}
+			graph.References = append(graph.References, ref)
+		}
+	}
+
+	sort.Slice(graph.References, func(i, j int) bool {
+		return graph.References[i].ID < graph.References[j].ID
+	})
+	return graph, nil
+}
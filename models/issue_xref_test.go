@@ -178,3 +178,45 @@ func testCreateComment(t *testing.T, repo, doer, issue int64, content string) *C
 	assert.NoError(t, sess.Commit())
 	return c
 }
+
+func TestXRef_GetIssueReferenceGraph(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	itarget := testCreateIssue(t, 1, 2, "title1", "content1", false)
+	i1 := testCreateIssue(t, 1, 2, "title2", fmt.Sprintf("content2, mentions #%d", itarget.Index), false)
+	i2 := testCreateIssue(t, 1, 2, "title3", fmt.Sprintf("content3, mentions #%d", i1.Index), false)
+	// Cross-reference from the private repo2
+	iprivate := testCreateIssue(t, 2, 2, "title4", fmt.Sprintf("content4, closes user2/repo1#%d", itarget.Index), false)
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	sha := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	assert.NoError(t, CreateRefComment(user2, repo1, itarget, "commit message", sha))
+
+	edges := func(graph *IssueReferenceGraph) map[string]bool {
+		result := make(map[string]bool)
+		for _, ref := range graph.References {
+			result[fmt.Sprintf("%d-%s->%d", ref.RefIssueID, ref.CommitSHA, ref.IssueID)] = true
+		}
+		return result
+	}
+
+	graph, err := GetIssueReferenceGraph(itarget, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, graph.Issues, 2)
+	assert.Contains(t, graph.Issues, i1.ID)
+	assert.Equal(t, map[string]bool{
+		fmt.Sprintf("%d-->%d", i1.ID, itarget.ID): true,
+		fmt.Sprintf("0-%s->%d", sha, itarget.ID):  true,
+	}, edges(graph))
+
+	graph, err = GetIssueReferenceGraph(itarget, nil, 2)
+	assert.NoError(t, err)
+	assert.Len(t, graph.Issues, 3)
+	assert.Contains(t, graph.Issues, i2.ID)
+	assert.Contains(t, edges(graph), fmt.Sprintf("%d-->%d", i2.ID, i1.ID))
+
+	graph, err = GetIssueReferenceGraph(itarget, user2, 1)
+	assert.NoError(t, err)
+	assert.Contains(t, graph.Issues, iprivate.ID)
+	assert.Contains(t, edges(graph), fmt.Sprintf("%d-->%d", iprivate.ID, itarget.ID))
+}
@@ -12,6 +12,7 @@ import (
 
 	"code.gitea.io/gitea/modules/log"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -38,7 +39,11 @@ func (l *LFSLock) AfterLoad(session *xorm.Session) {
 	var err error
 	l.Owner, err = getUserByID(session, l.OwnerID)
 	if err != nil {
-		log.Error("LFS lock AfterLoad failed OwnerId[%d] not found: %v", l.OwnerID, err)
+		if IsErrUserNotExist(err) {
+			l.Owner = NewGhostUser()
+		} else {
+			log.Error("LFS lock AfterLoad failed OwnerId[%d] not found: %v", l.OwnerID, err)
+		}
 	}
 	l.Repo, err = getRepositoryByID(session, l.RepoID)
 	if err != nil {
@@ -46,6 +51,11 @@ func (l *LFSLock) AfterLoad(session *xorm.Session) {
 	}
 }
 
+// IsStale returns true if the owner of the lock has been removed
+func (l *LFSLock) IsStale() bool {
+	return l.Owner == nil || l.Owner.IsGhost()
+}
+
 func cleanPath(p string) string {
 	return path.Clean("/" + p)[1:]
 }
@@ -118,6 +128,66 @@ func CountLFSLockByRepoID(repoID int64) (int64, error) {
 	return x.Count(&LFSLock{RepoID: repoID})
 }
 
+func staleLFSLockCond(repoID int64) builder.Cond {
+	return builder.Eq{"repo_id": repoID}.And(builder.NotIn("owner_id", builder.Select("id").From("`user`")))
+}
+
+// GetStaleLFSLocksByRepoID returns the locks of a repository whose owners have been removed.
+func GetStaleLFSLocksByRepoID(repoID int64) ([]*LFSLock, error) {
+	lfsLocks := make([]*LFSLock, 0, 10)
+	return lfsLocks, x.Where(staleLFSLockCond(repoID)).Find(&lfsLocks)
+}
+
+// CountStaleLFSLockByRepoID returns the number of locks of a repository whose owners have been removed.
+func CountStaleLFSLockByRepoID(repoID int64) (int64, error) {
+	return x.Where(staleLFSLockCond(repoID)).Count(new(LFSLock))
+}
+
+// DeleteStaleLFSLocks deletes the locks of a repository whose owners have been removed.
+// The deleted locks are returned.
+func DeleteStaleLFSLocks(repoID int64) ([]*LFSLock, error) {
+	lfsLocks, err := GetStaleLFSLocksByRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(lfsLocks))
+	for _, lock := range lfsLocks {
+		ids = append(ids, lock.ID)
+	}
+	return DeleteLFSLocksByIDs(repoID, ids)
+}
+
+// DeleteLFSLocksByIDs deletes the locks of a repository by given IDs, IDs of other repositories are ignored.
+// The deleted locks are returned.
+func DeleteLFSLocksByIDs(repoID int64, ids []int64) ([]*LFSLock, error) {
+	if len(ids) == 0 {
+		return []*LFSLock{}, nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	lfsLocks := make([]*LFSLock, 0, len(ids))
+	if err := sess.Where("repo_id = ?", repoID).In("id", ids).Find(&lfsLocks); err != nil {
+		return nil, err
+	}
+	if len(lfsLocks) == 0 {
+		return lfsLocks, nil
+	}
+
+	deleted := make([]int64, 0, len(lfsLocks))
+	for _, lock := range lfsLocks {
+		deleted = append(deleted, lock.ID)
+	}
+	if _, err := sess.In("id", deleted).Delete(new(LFSLock)); err != nil {
+		return nil, err
+	}
+	return lfsLocks, sess.Commit()
+}
+
 // DeleteLFSLockByID deletes a lock by given ID.
 func DeleteLFSLockByID(id int64, u *User, force bool) (*LFSLock, error) {
 	lock, err := GetLFSLockByID(id)
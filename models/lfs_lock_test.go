@@ -0,0 +1,54 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleLFSLocks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	lock, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "README.md"})
+	assert.NoError(t, err)
+	assert.False(t, lock.IsStale())
+
+	// the owner of the lock does not exist anymore
+	_, err = x.Insert(&LFSLock{Repo: repo, Owner: &User{ID: NonexistentID}, Path: "stale.bin"})
+	assert.NoError(t, err)
+
+	count, err := CountStaleLFSLockByRepoID(repo.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	locks, err := GetLFSLockByRepoID(repo.ID, 0, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, locks, 2) {
+		assert.False(t, locks[0].IsStale())
+		assert.True(t, locks[1].IsStale())
+		assert.True(t, locks[1].Owner.IsGhost())
+	}
+
+	deleted, err := DeleteStaleLFSLocks(repo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, deleted, 1) {
+		assert.Equal(t, "stale.bin", deleted[0].Path)
+	}
+
+	// locks of other repositories are not deleted
+	deleted, err = DeleteLFSLocksByIDs(repo.ID+1, []int64{lock.ID})
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 0)
+	AssertExistsAndLoadBean(t, &LFSLock{ID: lock.ID})
+
+	deleted, err = DeleteLFSLocksByIDs(repo.ID, []int64{lock.ID})
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 1)
+	AssertNotExistsBean(t, &LFSLock{ID: lock.ID})
+}
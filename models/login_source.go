@@ -13,6 +13,7 @@ import (
 	"net/textproto"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"code.gitea.io/gitea/modules/auth/ldap"
 	"code.gitea.io/gitea/modules/auth/oauth2"
@@ -24,6 +25,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	gouuid "github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/markbates/goth"
 
 	"xorm.io/xorm"
 	"xorm.io/xorm/convert"
@@ -163,6 +165,87 @@ type OAuth2Config struct {
 	OpenIDConnectAutoDiscoveryURL string
 	CustomURLMapping              *oauth2.CustomURLMapping
 	IconURL                       string
+	// AllowedEmailDomains and AllowedAccounts restrict the external accounts allowed to register,
+	// an account is allowed if the domain of its email or its email, nickname or user ID is listed.
+	// Emails are only considered if the provider asserts they are verified. Any account is allowed if both are empty.
+	AllowedEmailDomains []string
+	AllowedAccounts     []string
+	// RequiredGroup is a group the external accounts must belong to in order to register,
+	// as listed by their GroupClaimName claim ("groups" if empty).
+	GroupClaimName string
+	RequiredGroup  string
+	// RequireRegistrationApproval prohibits the new accounts to sign in until an administrator approves them
+	RequireRegistrationApproval bool
+}
+
+// hasVerifiedEmail returns true if the provider asserts that the email of the external account is verified
+// with the email_verified claim of OpenID Connect, the verification is unknown for the other providers
+func hasVerifiedEmail(gothUser goth.User) bool {
+	switch verified := gothUser.RawData["email_verified"].(type) {
+	case bool:
+		return verified
+	case string:
+		return strings.EqualFold(verified, "true")
+	}
+	return false
+}
+
+// IsRegistrationAllowed returns true if the external account is allowed to register
+func (cfg *OAuth2Config) IsRegistrationAllowed(gothUser goth.User) bool {
+	if len(cfg.AllowedEmailDomains) > 0 || len(cfg.AllowedAccounts) > 0 {
+		allowed := false
+		// anyone can claim an unverified email, so it can't grant access
+		email := ""
+		if hasVerifiedEmail(gothUser) {
+			email = gothUser.Email
+		}
+		if i := strings.LastIndexByte(email, '@'); i >= 0 {
+			domain := email[i+1:]
+			for _, allowedDomain := range cfg.AllowedEmailDomains {
+				allowed = allowed || strings.EqualFold(allowedDomain, domain)
+			}
+		}
+		for _, account := range cfg.AllowedAccounts {
+			allowed = allowed || account == gothUser.UserID ||
+				(email != "" && strings.EqualFold(account, email)) ||
+				(gothUser.NickName != "" && strings.EqualFold(account, gothUser.NickName))
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	if cfg.RequiredGroup == "" {
+		return true
+	}
+	claimName := cfg.GroupClaimName
+	if claimName == "" {
+		claimName = "groups"
+	}
+	switch groups := gothUser.RawData[claimName].(type) {
+	case string:
+		return groups == cfg.RequiredGroup
+	case []string:
+		for _, group := range groups {
+			if group == cfg.RequiredGroup {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, group := range groups {
+			if group == cfg.RequiredGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseOAuth2AllowList splits a list of email domains or accounts separated by commas or white spaces
+func ParseOAuth2AllowList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
 }
 
 // FromDB fills up an OAuth2Config from serialized format.
@@ -79,6 +79,9 @@ func insertIssue(sess *xorm.Session, issue *Issue) error {
 		if _, err := sess.Insert(issue.Reactions); err != nil {
 			return err
 		}
+		if err := updateIssueNumVotes(sess, issue.ID); err != nil {
+			return err
+		}
 	}
 
 	cols := make([]string, 0)
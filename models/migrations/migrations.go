@@ -327,6 +327,82 @@ var migrations = []Migration{
 	NewMigration("Drop unneeded webhook related columns", dropWebhookColumns),
 	// v188 -> v189
 	NewMigration("Add key is verified to gpg key", addKeyIsVerified),
+	// v189 -> v190
+	NewMigration("Create star list tables", createStarListTables),
+	// v190 -> v191
+	NewMigration("Create snippet tables", createSnippetTables),
+	// v191 -> v192
+	NewMigration("Create repo pages table", createRepoPagesTable),
+	// v192 -> v193
+	NewMigration("Add checklist to review", addChecklistToReview),
+	// v193 -> v194
+	NewMigration("Add payload version to webhook", addPayloadVersionToWebhook),
+	// v194 -> v195
+	NewMigration("Add num votes to issue", addNumVotesToIssue),
+	// v195 -> v196
+	NewMigration("Create repo snapshot table", createRepoSnapshotTable),
+	// v196 -> v197
+	NewMigration("Create instance template table", createInstanceTemplateTable),
+	// v197 -> v198
+	NewMigration("Add KeepActivitySignedInOnly to User table", addKeepActivitySignedInOnlyUserColumn),
+	// v198 -> v199
+	NewMigration("Add RequireTwoFactor to User table", addRequireTwoFactorUserColumn),
+	// v199 -> v200
+	NewMigration("Add LastError to Mirror table", addLastErrorToMirror),
+	// v200 -> v201
+	NewMigration("Create package tables", createPackageTables),
+	// v201 -> v202
+	NewMigration("Add MaxBandwidth and SyncWindows to Mirror table", addBandwidthAndSyncWindowsToMirror),
+	// v202 -> v203
+	NewMigration("Add AccentColor to Repository table", addAccentColorToRepository),
+	// v203 -> v204
+	NewMigration("Create reviewer statistics tables", createReviewerStatsTables),
+	// v204 -> v205
+	NewMigration("Add AutoMove to ProjectBoard table", addAutoMoveToProjectBoard),
+	// v205 -> v206
+	NewMigration("Add Flow to PullRequest table", addFlowToPullRequest),
+	// v206 -> v207
+	NewMigration("Create RepoEvent table", createRepoEventTable),
+	// v207 -> v208
+	NewMigration("Create GitOperation table", createGitOperationTable),
+	// v208 -> v209
+	NewMigration("Create OAuth2DeviceCode table", createOAuth2DeviceCodeTable),
+	// v209 -> v210
+	NewMigration("Add CreatorID, Scope and ExpiresUnix to AccessToken table", addOrgTokenColumnsToAccessToken),
+	// v210 -> v211
+	NewMigration("Add SizeQuota to User table", addSizeQuotaToUser),
+	// v211 -> v212
+	NewMigration("Add CreatedUnix to UserRedirect table", addCreatedUnixToUserRedirect),
+	// v212 -> v213
+	NewMigration("Add RepoAccessRequest table", addRepoAccessRequestTable),
+	// v213 -> v214
+	NewMigration("Add PendingRegistration table", addPendingRegistrationTable),
+	// v214 -> v215
+	NewMigration("Add SignatureHeader to Webhook table", addSignatureHeaderToWebhook),
+	// v215 -> v216
+	NewMigration("Add failure counts to Webhook table", addFailureCountsToWebhook),
+	// v216 -> v217
+	NewMigration("Add expiry and source to PublicKey table", addExpiryAndSourceToPublicKey),
+	// v217 -> v218
+	NewMigration("Add RequireCodeOwnerApproval to ProtectedBranch table", addRequireCodeOwnerApprovalToProtectedBranch),
+	// v218 -> v219
+	NewMigration("Add PullAutoMerge table", addPullAutoMergeTable),
+	// v219 -> v220
+	NewMigration("Add Deployment table", addDeploymentTable),
+	// v220 -> v221
+	NewMigration("Add failure status to Mirror table", addFailureStatusToMirror),
+	// v221 -> v222
+	NewMigration("Add RepoSizeSample table", addRepoSizeSampleTable),
+	// v222 -> v223
+	NewMigration("Add IssueTriageRule and IssueTriageRun tables", addIssueTriageTables),
+	// v223 -> v224
+	NewMigration("Add CLA and CLASignature tables and enforce_cla to user", addCLATables),
+	// v224 -> v225
+	NewMigration("Add attachment_upload table for resumable uploads", addAttachmentUploadTable),
+	// v225 -> v226
+	NewMigration("Add domain_verified to repo_pages", addDomainVerifiedToRepoPages),
+	// v226 -> v227
+	NewMigration("Add confidential_client and enable_device_flow to oauth2_application", addDeviceFlowToOAuth2Application),
 }
 
 // GetCurrentDBVersion returns the current db version
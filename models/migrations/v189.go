@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createStarListTables(x *xorm.Engine) error {
+	type StarList struct {
+		ID          int64  `xorm:"pk autoincr"`
+		UserID      int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name        string `xorm:"NOT NULL"`
+		LowerName   string `xorm:"UNIQUE(s) NOT NULL"`
+		Description string `xorm:"TEXT"`
+		IsPrivate   bool   `xorm:"NOT NULL DEFAULT false"`
+		NumRepos    int    `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type StarListRepo struct {
+		ID          int64              `xorm:"pk autoincr"`
+		StarListID  int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(StarList), new(StarListRepo))
+}
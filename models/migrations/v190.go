@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createSnippetTables(x *xorm.Engine) error {
+	type Snippet struct {
+		ID          int64  `xorm:"pk autoincr"`
+		UUID        string `xorm:"uuid UNIQUE"`
+		OwnerID     int64  `xorm:"INDEX NOT NULL"`
+		Description string `xorm:"TEXT"`
+		Visibility  int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		NumComments int    `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type SnippetComment struct {
+		ID        int64  `xorm:"pk autoincr"`
+		SnippetID int64  `xorm:"INDEX NOT NULL"`
+		PosterID  int64  `xorm:"INDEX NOT NULL"`
+		Content   string `xorm:"TEXT NOT NULL"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(Snippet), new(SnippetComment))
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createRepoPagesTable(x *xorm.Engine) error {
+	type RepoPages struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RepoID       int64  `xorm:"UNIQUE NOT NULL"`
+		Branch       string `xorm:"NOT NULL"`
+		Folder       string
+		Domain       string `xorm:"INDEX"`
+		Status       int    `xorm:"NOT NULL DEFAULT 0"`
+		CommitID     string `xorm:"VARCHAR(40)"`
+		Size         int64  `xorm:"NOT NULL DEFAULT 0"`
+		Message      string `xorm:"TEXT"`
+		DeployedUnix timeutil.TimeStamp
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoPages))
+}
@@ -0,0 +1,27 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addChecklistToReview(x *xorm.Engine) error {
+	type ReviewChecklistItem struct {
+		Text    string `json:"text"`
+		Checked bool   `json:"checked"`
+	}
+
+	type Review struct {
+		Checklist []*ReviewChecklistItem `xorm:"JSON TEXT"`
+	}
+
+	if err := x.Sync2(new(Review)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
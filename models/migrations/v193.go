@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addPayloadVersionToWebhook(x *xorm.Engine) error {
+	// the existing webhooks keep the legacy payloads their receivers expect
+	type Webhook struct {
+		PayloadVersion int `xorm:"NOT NULL DEFAULT 1"`
+	}
+
+	if err := x.Sync2(new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
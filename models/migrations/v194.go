@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addNumVotesToIssue(x *xorm.Engine) error {
+	type Issue struct {
+		NumVotes int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(Issue)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	_, err := x.Exec("UPDATE `issue` SET num_votes = (SELECT COUNT(*) FROM `reaction` WHERE `reaction`.issue_id = `issue`.id AND `reaction`.comment_id = 0 AND `reaction`.`type` = ?)", "+1")
+	return err
+}
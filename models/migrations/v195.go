@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createRepoSnapshotTable(x *xorm.Engine) error {
+	type RepoSnapshot struct {
+		ID               int64  `xorm:"pk autoincr"`
+		RepoID           int64  `xorm:"UNIQUE NOT NULL"`
+		Branch           string `xorm:"NOT NULL"`
+		TagPrefix        string `xorm:"NOT NULL"`
+		Retention        int    `xorm:"NOT NULL DEFAULT 0"`
+		GenerateArchives bool   `xorm:"NOT NULL DEFAULT false"`
+		LastTagName      string
+		LastSnapshotUnix timeutil.TimeStamp
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoSnapshot))
+}
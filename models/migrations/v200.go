@@ -0,0 +1,45 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPackageTables(x *xorm.Engine) error {
+	type Package struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Type        string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		LowerName   string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type PackageVersion struct {
+		ID           int64              `xorm:"pk autoincr"`
+		PackageID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatorID    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Version      string             `xorm:"NOT NULL"`
+		LowerVersion string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		MetadataJSON string             `xorm:"metadata_json TEXT"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type PackageFile struct {
+		ID          int64              `xorm:"pk autoincr"`
+		PackageID   int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		VersionID   int64              `xorm:"INDEX NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		LowerName   string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+		HashSHA256  string             `xorm:"hash_sha256 NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(Package), new(PackageVersion), new(PackageFile))
+}
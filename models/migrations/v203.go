@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createReviewerStatsTables(x *xorm.Engine) error {
+	type ReviewerStats struct {
+		ID               int64 `xorm:"pk autoincr"`
+		RepoID           int64 `xorm:"UNIQUE(s) NOT NULL"`
+		ReviewerID       int64 `xorm:"UNIQUE(s) NOT NULL"`
+		Reviews          int64 `xorm:"NOT NULL DEFAULT 0"`
+		Approvals        int64 `xorm:"NOT NULL DEFAULT 0"`
+		ChangesRequested int64 `xorm:"NOT NULL DEFAULT 0"`
+		Comments         int64 `xorm:"NOT NULL DEFAULT 0"`
+		TurnaroundSum    int64 `xorm:"NOT NULL DEFAULT 0"`
+		TurnaroundCount  int64 `xorm:"NOT NULL DEFAULT 0"`
+
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ReviewStatsCursor struct {
+		ID            int64 `xorm:"pk"`
+		LastCommentID int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(ReviewerStats), new(ReviewStatsCursor)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
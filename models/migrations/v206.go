@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createRepoEventTable(x *xorm.Engine) error {
+	type RepoEvent struct {
+		ID             int64 `xorm:"pk autoincr"`
+		RepoID         int64 `xorm:"INDEX"`
+		EventType      string
+		PayloadType    string
+		PayloadContent string             `xorm:"LONGTEXT"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(RepoEvent)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createGitOperationTable(x *xorm.Engine) error {
+	type GitOperation struct {
+		ID            int64 `xorm:"pk autoincr"`
+		RepoID        int64 `xorm:"INDEX"`
+		IsWiki        bool
+		UserID        int64 `xorm:"INDEX"`
+		KeyID         int64
+		TokenID       int64
+		Type          string `xorm:"VARCHAR(16)"`
+		Protocol      string `xorm:"VARCHAR(8)"`
+		RemoteIP      string `xorm:"VARCHAR(64) INDEX"`
+		Refs          string `xorm:"TEXT"`
+		BytesReceived int64
+		BytesSent     int64
+		Duration      int64
+		IsFinished    bool
+		IsSucceeded   bool
+		CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(GitOperation)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
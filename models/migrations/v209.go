@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addOrgTokenColumnsToAccessToken(x *xorm.Engine) error {
+	type AccessToken struct {
+		CreatorID   int64
+		Scope       string
+		ExpiresUnix timeutil.TimeStamp
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCreatedUnixToUserRedirect(x *xorm.Engine) error {
+	type UserRedirect struct {
+		RedirectUserID int64              `xorm:"INDEX"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(UserRedirect)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoAccessRequestTable(x *xorm.Engine) error {
+	type RepoAccessRequest struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RepoID       int64  `xorm:"INDEX"`
+		UserID       int64  `xorm:"INDEX"`
+		Message      string `xorm:"TEXT"`
+		Status       int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Mode         int
+		ReviewerID   int64
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+		ReviewedUnix timeutil.TimeStamp
+	}
+
+	if err := x.Sync2(new(RepoAccessRequest)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPendingRegistrationTable(x *xorm.Engine) error {
+	type PendingRegistration struct {
+		ID            int64              `xorm:"pk autoincr"`
+		UserID        int64              `xorm:"UNIQUE NOT NULL"`
+		LoginSourceID int64              `xorm:"INDEX"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(PendingRegistration)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
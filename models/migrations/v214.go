@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addSignatureHeaderToWebhook(x *xorm.Engine) error {
+	// the existing webhooks keep signing their payloads in the X-Gitea-Signature header
+	type Webhook struct {
+		SignatureHeader string `xorm:"VARCHAR(255)"`
+	}
+
+	if err := x.Sync2(new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addExpiryAndSourceToPublicKey(x *xorm.Engine) error {
+	type PublicKey struct {
+		CreatedFrom int                `xorm:"NOT NULL DEFAULT 0"`
+		ExpiredUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(PublicKey)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
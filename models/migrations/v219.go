@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addDeploymentTable(x *xorm.Engine) error {
+	type Deployment struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX NOT NULL"`
+		PullID      int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Environment string `xorm:"UNIQUE(s) NOT NULL"`
+		URL         string `xorm:"TEXT NOT NULL"`
+		SHA         string `xorm:"VARCHAR(40)"`
+		CreatorID   int64
+		IsActive    bool               `xorm:"INDEX NOT NULL DEFAULT true"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(Deployment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
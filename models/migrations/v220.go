@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addFailureStatusToMirror(x *xorm.Engine) error {
+	type Mirror struct {
+		LastErrorUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		FailureCount  int                `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(Mirror)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
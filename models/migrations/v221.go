@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoSizeSampleTable(x *xorm.Engine) error {
+	type RepoSizeSample struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+		SampledUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	if err := x.Sync2(new(RepoSizeSample)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueTriageTables(x *xorm.Engine) error {
+	type IssueTriageRule struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		Condition   int                `xorm:"NOT NULL DEFAULT 0"`
+		Pattern     string             `xorm:"TEXT"`
+		LabelID     int64              `xorm:"NOT NULL DEFAULT 0"`
+		TeamID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		Comment     string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueTriageRun struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RuleID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(IssueTriageRule), new(IssueTriageRun)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCLATables(x *xorm.Engine) error {
+	type User struct {
+		EnforceCLA bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type CLA struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Version     int                `xorm:"UNIQUE(s) NOT NULL"`
+		Content     string             `xorm:"LONGTEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type CLASignature struct {
+		ID         int64              `xorm:"pk autoincr"`
+		OrgID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Version    int                `xorm:"UNIQUE(s) NOT NULL"`
+		UserID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		FullName   string             `xorm:"NOT NULL"`
+		Email      string             `xorm:"NOT NULL"`
+		SignedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(User), new(CLA), new(CLASignature)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addAttachmentUploadTable(x *xorm.Engine) error {
+	type AttachmentUpload struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UUID        string             `xorm:"uuid UNIQUE"`
+		IssueID     int64              `xorm:"INDEX"`
+		UploaderID  int64              `xorm:"INDEX"`
+		Name        string             `xorm:"NOT NULL"`
+		Size        int64              `xorm:"NOT NULL"`
+		Received    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Chunks      int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	if err := x.Sync2(new(AttachmentUpload)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
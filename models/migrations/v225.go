@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addDomainVerifiedToRepoPages(x *xorm.Engine) error {
+	// the custom domains claimed so far have to be verified before they are served again
+	type RepoPages struct {
+		DomainVerified bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(RepoPages)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+// oauth2ApplicationV226 is the part of models.OAuth2Application changed by this version
+type oauth2ApplicationV226 struct {
+	// the existing applications stay confidential clients and have to opt in to the device authorization grant
+	ConfidentialClient bool `xorm:"NOT NULL DEFAULT TRUE"`
+	EnableDeviceFlow   bool `xorm:"NOT NULL DEFAULT FALSE"`
+}
+
+// TableName sets the database table name to be the correct one
+func (app *oauth2ApplicationV226) TableName() string {
+	return "oauth2_application"
+}
+
+func addDeviceFlowToOAuth2Application(x *xorm.Engine) error {
+	if err := x.Sync2(new(oauth2ApplicationV226)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
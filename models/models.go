@@ -125,6 +125,7 @@ func init() {
 		new(OAuth2Application),
 		new(OAuth2AuthorizationCode),
 		new(OAuth2Grant),
+		new(OAuth2DeviceCode),
 		new(Task),
 		new(LanguageStat),
 		new(EmailHash),
@@ -165,6 +165,39 @@ func CreateRepoTransferNotification(doer, newOwner *User, repo *Repository) erro
 	return sess.Commit()
 }
 
+// CreateRepoAccessRequestNotification creates a notification for each administrator of the repository the doer requested access to
+func CreateRepoAccessRequestNotification(doer *User, repo *Repository) error {
+	admins, err := repo.GetAdmins()
+	if err != nil || len(admins) == 0 {
+		return err
+	}
+
+	notify := make([]*Notification, 0, len(admins))
+	for _, admin := range admins {
+		notify = append(notify, &Notification{
+			UserID:    admin.ID,
+			RepoID:    repo.ID,
+			Status:    NotificationStatusUnread,
+			UpdatedBy: doer.ID,
+			Source:    NotificationSourceRepository,
+		})
+	}
+	_, err = x.Insert(notify)
+	return err
+}
+
+// CreateRepoAccessGrantedNotification creates a notification for the user the doer granted access to the repository
+func CreateRepoAccessGrantedNotification(doer, user *User, repo *Repository) error {
+	_, err := x.Insert(&Notification{
+		UserID:    user.ID,
+		RepoID:    repo.ID,
+		Status:    NotificationStatusUnread,
+		UpdatedBy: doer.ID,
+		Source:    NotificationSourceRepository,
+	})
+	return err
+}
+
 // CreateOrUpdateIssueNotifications creates an issue notification
 // for each watcher, or updates it if already exists
 // receiverID > 0 just send to reciver, else send to all watcher
@@ -36,6 +36,11 @@ type OAuth2Application struct {
 
 	RedirectURIs []string `xorm:"redirect_uris JSON TEXT"`
 
+	// ConfidentialClient is false for public clients which cannot keep their secret, like native applications
+	ConfidentialClient bool `xorm:"NOT NULL DEFAULT TRUE"`
+	// EnableDeviceFlow allows the application to use the device authorization grant (RFC 8628)
+	EnableDeviceFlow bool `xorm:"NOT NULL DEFAULT FALSE"`
+
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -165,9 +170,11 @@ func getOAuth2ApplicationsByUserID(e Engine, userID int64) (apps []*OAuth2Applic
 
 // CreateOAuth2ApplicationOptions holds options to create an oauth2 application
 type CreateOAuth2ApplicationOptions struct {
-	Name         string
-	UserID       int64
-	RedirectURIs []string
+	Name               string
+	UserID             int64
+	RedirectURIs       []string
+	ConfidentialClient bool
+	EnableDeviceFlow   bool
 }
 
 // CreateOAuth2Application inserts a new oauth2 application
@@ -178,10 +185,12 @@ func CreateOAuth2Application(opts CreateOAuth2ApplicationOptions) (*OAuth2Applic
 func createOAuth2Application(e Engine, opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error) {
 	clientID := uuid.New().String()
 	app := &OAuth2Application{
-		UID:          opts.UserID,
-		Name:         opts.Name,
-		ClientID:     clientID,
-		RedirectURIs: opts.RedirectURIs,
+		UID:                opts.UserID,
+		Name:               opts.Name,
+		ClientID:           clientID,
+		RedirectURIs:       opts.RedirectURIs,
+		ConfidentialClient: opts.ConfidentialClient,
+		EnableDeviceFlow:   opts.EnableDeviceFlow,
 	}
 	if _, err := e.Insert(app); err != nil {
 		return nil, err
@@ -191,10 +200,12 @@ func createOAuth2Application(e Engine, opts CreateOAuth2ApplicationOptions) (*OA
 
 // UpdateOAuth2ApplicationOptions holds options to update an oauth2 application
 type UpdateOAuth2ApplicationOptions struct {
-	ID           int64
-	Name         string
-	UserID       int64
-	RedirectURIs []string
+	ID                 int64
+	Name               string
+	UserID             int64
+	RedirectURIs       []string
+	ConfidentialClient bool
+	EnableDeviceFlow   bool
 }
 
 // UpdateOAuth2Application updates an oauth2 application
@@ -215,6 +226,8 @@ func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) (*OAuth2Applic
 
 	app.Name = opts.Name
 	app.RedirectURIs = opts.RedirectURIs
+	app.ConfidentialClient = opts.ConfidentialClient
+	app.EnableDeviceFlow = opts.EnableDeviceFlow
 
 	if err = updateOAuth2Application(sess, app); err != nil {
 		return nil, err
@@ -225,7 +238,7 @@ func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) (*OAuth2Applic
 }
 
 func updateOAuth2Application(e Engine, app *OAuth2Application) error {
-	if _, err := e.ID(app.ID).Update(app); err != nil {
+	if _, err := e.ID(app.ID).Cols("name", "redirect_uris", "confidential_client", "enable_device_flow").Update(app); err != nil {
 		return err
 	}
 	return nil
@@ -255,10 +268,14 @@ func deleteOAuth2Application(sess *xorm.Session, id, userid int64) error {
 	if _, err := sess.Where("application_id = ?", id).Delete(new(OAuth2Grant)); err != nil {
 		return err
 	}
+
+	if _, err := sess.Where("application_id = ?", id).Delete(new(OAuth2DeviceCode)); err != nil {
+		return err
+	}
 	return nil
 }
 
-// DeleteOAuth2Application deletes the application with the given id and the grants and auth codes related to it. It checks if the userid was the creator of the app.
+// DeleteOAuth2Application deletes the application with the given id and the grants, auth codes and device codes related to it. It checks if the userid was the creator of the app.
 func DeleteOAuth2Application(id, userid int64) error {
 	sess := x.NewSession()
 	defer sess.Close()
@@ -68,6 +68,23 @@ func TestCreateOAuth2Application(t *testing.T) {
 	AssertExistsAndLoadBean(t, &OAuth2Application{Name: "newapp"})
 }
 
+func TestUpdateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	assert.True(t, app.ConfidentialClient)
+	assert.False(t, app.EnableDeviceFlow)
+
+	_, err := UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{ID: 1, Name: "Test", UserID: 1, RedirectURIs: []string{"a"}, EnableDeviceFlow: true})
+	assert.NoError(t, err)
+	app = AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	assert.False(t, app.ConfidentialClient)
+	assert.True(t, app.EnableDeviceFlow)
+	assert.NotEmpty(t, app.ClientSecret)
+
+	_, err = UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{ID: 1, Name: "Test", UserID: 2, RedirectURIs: []string{"a"}})
+	assert.Error(t, err)
+}
+
 func TestOAuth2Application_LoadUser(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
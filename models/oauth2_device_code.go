@@ -0,0 +1,164 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// OAuth2DeviceCodeInterval is the minimum number of seconds between two polls of a device for an access token
+const OAuth2DeviceCodeInterval = 5
+
+// userCodeCharset are the characters of user codes, consonants only to avoid forming words and ambiguous characters
+const userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLength is the number of characters of a user code
+const userCodeLength = 8
+
+// OAuth2DeviceCode is a device authorization request as specified in RFC 8628. The device polls for an access token
+// with the device code until the user enters the user code on the verification page and grants access.
+type OAuth2DeviceCode struct {
+	ID            int64  `xorm:"pk autoincr"`
+	ApplicationID int64  `xorm:"INDEX"`
+	DeviceCode    string `xorm:"INDEX UNIQUE"`
+	UserCode      string `xorm:"INDEX UNIQUE"`
+	Scope         string `xorm:"TEXT"`
+	// GrantID is set when the user grants access to the device
+	GrantID      int64
+	IsDenied     bool
+	LastPollUnix timeutil.TimeStamp
+	ValidUntil   timeutil.TimeStamp `xorm:"INDEX"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+// TableName sets the table name to `oauth2_device_code`
+func (code *OAuth2DeviceCode) TableName() string {
+	return "oauth2_device_code"
+}
+
+// IsExpired returns true if the code can no longer be used
+func (code *OAuth2DeviceCode) IsExpired() bool {
+	return code.ValidUntil <= timeutil.TimeStampNow()
+}
+
+// IsPending returns true if the user has neither granted nor denied access yet
+func (code *OAuth2DeviceCode) IsPending() bool {
+	return code.GrantID == 0 && !code.IsDenied
+}
+
+// FormattedUserCode returns the user code as it is shown to the user, e.g. "BCDF-GHJK"
+func (code *OAuth2DeviceCode) FormattedUserCode() string {
+	return code.UserCode[:userCodeLength/2] + "-" + code.UserCode[userCodeLength/2:]
+}
+
+func generateUserCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(userCodeCharset)))
+	for i := 0; i < userCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeCharset[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// normalizeUserCode returns the user code entered by a user without separators
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(userCode)))
+}
+
+// CreateDeviceCode creates a device authorization request for the application, the expired requests are deleted
+func (app *OAuth2Application) CreateDeviceCode(scope string) (*OAuth2DeviceCode, error) {
+	if _, err := x.Where("valid_until <= ?", timeutil.TimeStampNow()).Delete(new(OAuth2DeviceCode)); err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := secret.New()
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		return nil, err
+	}
+	code := &OAuth2DeviceCode{
+		ApplicationID: app.ID,
+		DeviceCode:    deviceCode,
+		UserCode:      userCode,
+		Scope:         scope,
+		ValidUntil:    timeutil.TimeStampNow().Add(setting.OAuth2.DeviceCodeExpirationTime),
+	}
+	if _, err := x.Insert(code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// GetOAuth2DeviceCodeByUserCode returns the device authorization request of a user code entered by a user
+func GetOAuth2DeviceCodeByUserCode(userCode string) (*OAuth2DeviceCode, error) {
+	code := new(OAuth2DeviceCode)
+	if has, err := x.Where("user_code = ?", normalizeUserCode(userCode)).Get(code); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuth2DeviceCodeNotExist{}
+	}
+	return code, nil
+}
+
+// GetOAuth2DeviceCodeByDeviceCode returns the device authorization request of a device code
+func GetOAuth2DeviceCodeByDeviceCode(deviceCode string) (*OAuth2DeviceCode, error) {
+	code := new(OAuth2DeviceCode)
+	if has, err := x.Where("device_code = ?", deviceCode).Get(code); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuth2DeviceCodeNotExist{}
+	}
+	return code, nil
+}
+
+// Authorize records that the user granted access to the device with the grant,
+// it returns false if the request was already granted or denied
+func (code *OAuth2DeviceCode) Authorize(grant *OAuth2Grant) (bool, error) {
+	code.GrantID = grant.ID
+	affected, err := x.ID(code.ID).Where("grant_id = 0 AND is_denied = ?", false).Cols("grant_id").Update(code)
+	return affected > 0, err
+}
+
+// Deny records that the user denied access to the device,
+// it returns false if the request was already granted or denied
+func (code *OAuth2DeviceCode) Deny() (bool, error) {
+	code.IsDenied = true
+	affected, err := x.ID(code.ID).Where("grant_id = 0 AND is_denied = ?", false).Cols("is_denied").Update(code)
+	return affected > 0, err
+}
+
+// Poll records a poll of the device for an access token,
+// it returns false if the device polled again before OAuth2DeviceCodeInterval seconds
+func (code *OAuth2DeviceCode) Poll() (bool, error) {
+	now := timeutil.TimeStampNow()
+	code.LastPollUnix = now
+	affected, err := x.ID(code.ID).Where("last_poll_unix <= ?", now.Add(-OAuth2DeviceCodeInterval)).Cols("last_poll_unix").Update(code)
+	return affected > 0, err
+}
+
+// Invalidate deletes the device code so that it can be exchanged only once for an access token,
+// it returns false if it was already deleted
+func (code *OAuth2DeviceCode) Invalidate() (bool, error) {
+	deleted, err := x.ID(code.ID).Delete(new(OAuth2DeviceCode))
+	return deleted > 0, err
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2DeviceCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+
+	code, err := app.CreateDeviceCode("openid")
+	assert.NoError(t, err)
+	assert.True(t, code.IsPending())
+	assert.False(t, code.IsExpired())
+	assert.Len(t, code.UserCode, userCodeLength)
+	assert.Equal(t, code.UserCode[:4]+"-"+code.UserCode[4:], code.FormattedUserCode())
+
+	byUserCode, err := GetOAuth2DeviceCodeByUserCode(" " + strings.ToLower(code.FormattedUserCode()))
+	assert.NoError(t, err)
+	assert.Equal(t, code.ID, byUserCode.ID)
+	_, err = GetOAuth2DeviceCodeByUserCode("AAAA-AAAA")
+	assert.True(t, IsErrOAuth2DeviceCodeNotExist(err))
+
+	ok, err := code.Poll()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = code.Poll()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
+	ok, err = byUserCode.Authorize(grant)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = byUserCode.Deny()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	byDeviceCode, err := GetOAuth2DeviceCodeByDeviceCode(code.DeviceCode)
+	assert.NoError(t, err)
+	assert.EqualValues(t, grant.ID, byDeviceCode.GrantID)
+	assert.False(t, byDeviceCode.IsDenied)
+
+	ok, err = byDeviceCode.Invalidate()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = byDeviceCode.Invalidate()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = GetOAuth2DeviceCodeByDeviceCode(code.DeviceCode)
+	assert.True(t, IsErrOAuth2DeviceCodeNotExist(err))
+}
@@ -79,6 +79,16 @@ func (org *User) GetMembers() (err error) {
 	return
 }
 
+// GetMembersWithoutTwoFactor returns the members of the organization who are not enrolled in two-factor authentication.
+func (org *User) GetMembersWithoutTwoFactor() (UserList, error) {
+	users := make(UserList, 0, 10)
+	return users, x.Join("INNER", "org_user", "org_user.uid = `user`.id").
+		Where("org_user.org_id = ?", org.ID).
+		And(builder.NotIn("`user`.id", builder.Select("uid").From("two_factor"))).
+		OrderBy("`user`.name").
+		Find(&users)
+}
+
 // FindOrgMembersOpts represensts find org members conditions
 type FindOrgMembersOpts struct {
 	ListOptions
@@ -151,6 +161,9 @@ func CreateOrganization(org, owner *User) (err error) {
 	} else if isExist {
 		return ErrUserAlreadyExist{org.Name}
 	}
+	if err = checkUserNameReservation(x, 0, org.Name); err != nil {
+		return err
+	}
 
 	org.LowerName = strings.ToLower(org.Name)
 	if org.Rands, err = GetUserSalt(); err != nil {
@@ -161,6 +174,7 @@ func CreateOrganization(org, owner *User) (err error) {
 	}
 	org.UseCustomAvatar = true
 	org.MaxRepoCreation = -1
+	org.SizeQuota = -1
 	org.NumTeams = 1
 	org.NumMembers = 1
 	org.Type = UserTypeOrganization
@@ -296,10 +310,18 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&Webhook{OrgID: u.ID},
+		&AccessToken{UID: u.ID},
+		&CLA{OrgID: u.ID},
+		&CLASignature{OrgID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deletePackagesByOwnerID(e, u.ID); err != nil {
+		return fmt.Errorf("deletePackagesByOwnerID: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
@@ -577,6 +599,25 @@ func ChangeOrgUserStatus(orgID, uid int64, public bool) error {
 	return err
 }
 
+func (org *User) checkTwoFactorRequirement(e Engine, uid int64) error {
+	if !org.RequireTwoFactor {
+		return nil
+	}
+	has, err := hasTwoFactorByUID(e, uid)
+	if err != nil {
+		return err
+	} else if !has {
+		return ErrTwoFactorRequired{OrgID: org.ID, UID: uid}
+	}
+	return nil
+}
+
+// CheckTwoFactorRequirement returns ErrTwoFactorRequired if the organization requires two-factor authentication
+// and the user is not enrolled in it.
+func (org *User) CheckTwoFactorRequirement(uid int64) error {
+	return org.checkTwoFactorRequirement(x, uid)
+}
+
 // AddOrgUser adds new user to given organization.
 func AddOrgUser(orgID, uid int64) error {
 	isAlreadyMember, err := IsOrganizationMember(orgID, uid)
@@ -584,6 +625,14 @@ func AddOrgUser(orgID, uid int64) error {
 		return err
 	}
 
+	org, err := GetUserByID(orgID)
+	if err != nil {
+		return err
+	}
+	if err := org.CheckTwoFactorRequirement(uid); err != nil {
+		return err
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -469,6 +469,35 @@ func TestAddOrgUser(t *testing.T) {
 	CheckConsistencyFor(t, &User{}, &Team{})
 }
 
+func TestAddOrgUser_RequireTwoFactor(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	org.RequireTwoFactor = true
+	assert.NoError(t, UpdateUserCols(org, "require_two_factor"))
+
+	err := AddOrgUser(3, 5)
+	assert.True(t, IsErrTwoFactorRequired(err))
+	AssertNotExistsBean(t, &OrgUser{OrgID: 3, UID: 5})
+
+	// user 24 is enrolled in two-factor authentication
+	assert.NoError(t, AddOrgUser(3, 24))
+	AssertExistsAndLoadBean(t, &OrgUser{OrgID: 3, UID: 24})
+
+	members, err := org.GetMembersWithoutTwoFactor()
+	assert.NoError(t, err)
+	memberIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.ID)
+	}
+	assert.EqualValues(t, []int64{2, 28, 4}, memberIDs)
+
+	status, err := GetTwoFactorStatusByUIDs([]int64{2, 24})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{2: false, 24: true}, status)
+
+	CheckConsistencyFor(t, &User{}, &Team{})
+}
+
 func TestRemoveOrgUser(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	testSuccess := func(orgID, userID int64) {
@@ -0,0 +1,289 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables,
+		new(Package),
+		new(PackageVersion),
+		new(PackageFile),
+	)
+}
+
+// PackageType is the type of registry a package belongs to
+type PackageType string
+
+// Types of package registries
+const (
+	PackageTypePyPI PackageType = "pypi"
+)
+
+// Package represents a package of a user or an organization, its name is unique per owner and type
+type Package struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Type        PackageType        `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string             `xorm:"NOT NULL"`
+	LowerName   string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// PackageVersion represents a version of a package
+type PackageVersion struct {
+	ID           int64  `xorm:"pk autoincr"`
+	PackageID    int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatorID    int64  `xorm:"NOT NULL DEFAULT 0"`
+	Version      string `xorm:"NOT NULL"`
+	LowerVersion string `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// MetadataJSON holds the metadata of the version, its format depends on the type of the package
+	MetadataJSON string             `xorm:"metadata_json TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+// PackageFile represents a file of a package version, its name is unique per package
+type PackageFile struct {
+	ID         int64           `xorm:"pk autoincr"`
+	PackageID  int64           `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	VersionID  int64           `xorm:"INDEX NOT NULL"`
+	Version    *PackageVersion `xorm:"-"`
+	Name       string          `xorm:"NOT NULL"`
+	LowerName  string          `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Size       int64           `xorm:"NOT NULL DEFAULT 0"`
+	HashSHA256 string          `xorm:"hash_sha256 NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// RelativePath returns the path of the file content in the package storage,
+// files with the same content share the same path
+func (f *PackageFile) RelativePath() string {
+	return path.Join(f.HashSHA256[0:2], f.HashSHA256[2:4], f.HashSHA256)
+}
+
+// ErrPackageNotExist represents a "PackageNotExist" kind of error.
+type ErrPackageNotExist struct {
+	OwnerID int64
+	Type    PackageType
+	Name    string
+}
+
+// IsErrPackageNotExist checks if an error is a ErrPackageNotExist.
+func IsErrPackageNotExist(err error) bool {
+	_, ok := err.(ErrPackageNotExist)
+	return ok
+}
+
+func (err ErrPackageNotExist) Error() string {
+	return fmt.Sprintf("package does not exist [owner_id: %d, type: %s, name: %s]", err.OwnerID, err.Type, err.Name)
+}
+
+// ErrPackageFileNotExist represents a "PackageFileNotExist" kind of error.
+type ErrPackageFileNotExist struct {
+	PackageID int64
+	Version   string
+	Name      string
+}
+
+// IsErrPackageFileNotExist checks if an error is a ErrPackageFileNotExist.
+func IsErrPackageFileNotExist(err error) bool {
+	_, ok := err.(ErrPackageFileNotExist)
+	return ok
+}
+
+func (err ErrPackageFileNotExist) Error() string {
+	return fmt.Sprintf("package file does not exist [package_id: %d, version: %s, name: %s]", err.PackageID, err.Version, err.Name)
+}
+
+// ErrPackageFileAlreadyExist represents a "PackageFileAlreadyExist" kind of error.
+type ErrPackageFileAlreadyExist struct {
+	PackageID int64
+	Name      string
+}
+
+// IsErrPackageFileAlreadyExist checks if an error is a ErrPackageFileAlreadyExist.
+func IsErrPackageFileAlreadyExist(err error) bool {
+	_, ok := err.(ErrPackageFileAlreadyExist)
+	return ok
+}
+
+func (err ErrPackageFileAlreadyExist) Error() string {
+	return fmt.Sprintf("package file already exists [package_id: %d, name: %s]", err.PackageID, err.Name)
+}
+
+// GetPackageAccessMode returns the access mode of doer to the packages of owner.
+// Users can publish their own packages and those of the organizations they are allowed
+// to create repositories in, everybody who can see the owner can read its packages.
+func GetPackageAccessMode(owner, doer *User) (AccessMode, error) {
+	if doer != nil && (doer.IsAdmin || doer.ID == owner.ID) {
+		return AccessModeWrite, nil
+	}
+
+	if doer != nil && owner.IsOrganization() {
+		canCreate, err := owner.CanCreateOrgRepo(doer.ID)
+		if err != nil {
+			return AccessModeNone, err
+		}
+		if canCreate {
+			return AccessModeWrite, nil
+		}
+	}
+
+	if HasOrgOrUserVisible(owner, doer) {
+		return AccessModeRead, nil
+	}
+	return AccessModeNone, nil
+}
+
+// GetPackageByName returns the package of the owner with the given type and name
+func GetPackageByName(ownerID int64, typ PackageType, name string) (*Package, error) {
+	p := &Package{}
+	has, err := x.Where("owner_id = ? AND type = ? AND lower_name = ?", ownerID, typ, strings.ToLower(name)).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageNotExist{OwnerID: ownerID, Type: typ, Name: name}
+	}
+	return p, nil
+}
+
+// GetPackagesByOwner returns the packages of the owner with the given type, sorted by name
+func GetPackagesByOwner(ownerID int64, typ PackageType) ([]*Package, error) {
+	packages := make([]*Package, 0, 10)
+	return packages, x.
+		Where("owner_id = ? AND type = ?", ownerID, typ).
+		Asc("lower_name").
+		Find(&packages)
+}
+
+// GetPackageFiles returns the files of all versions of the package with their version loaded
+func GetPackageFiles(packageID int64) ([]*PackageFile, error) {
+	versions := make(map[int64]*PackageVersion)
+	if err := x.Where("package_id = ?", packageID).Find(&versions); err != nil {
+		return nil, err
+	}
+
+	files := make([]*PackageFile, 0, len(versions))
+	if err := x.Where("package_id = ?", packageID).Asc("id").Find(&files); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		f.Version = versions[f.VersionID]
+	}
+	return files, nil
+}
+
+// GetPackageFile returns the file of the package version with the given name
+func GetPackageFile(packageID int64, version, name string) (*PackageFile, error) {
+	f := &PackageFile{}
+	has, err := x.Table("package_file").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Where(builder.Eq{
+			"package_file.package_id":       packageID,
+			"package_file.lower_name":       strings.ToLower(name),
+			"package_version.lower_version": strings.ToLower(version),
+		}).
+		Cols("package_file.*").
+		Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageFileNotExist{PackageID: packageID, Version: version, Name: name}
+	}
+	return f, nil
+}
+
+// AddPackageFileOptions represents the options of a new package file
+type AddPackageFileOptions struct {
+	Owner   *User
+	Creator *User
+	Type    PackageType
+	Name    string
+	Version string
+	// MetadataJSON is only stored when the version is created
+	MetadataJSON string
+	File         *PackageFile
+}
+
+// AddPackageFile adds a file to a package version, the package and the version are created if needed.
+// The content of the file has to be stored before.
+func AddPackageFile(opts *AddPackageFileOptions) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	p := &Package{}
+	has, err := sess.Where("owner_id = ? AND type = ? AND lower_name = ?", opts.Owner.ID, opts.Type, strings.ToLower(opts.Name)).Get(p)
+	if err != nil {
+		return err
+	} else if !has {
+		p = &Package{
+			OwnerID:   opts.Owner.ID,
+			Type:      opts.Type,
+			Name:      opts.Name,
+			LowerName: strings.ToLower(opts.Name),
+		}
+		if _, err = sess.Insert(p); err != nil {
+			return err
+		}
+	}
+
+	if has, err = sess.Exist(&PackageFile{PackageID: p.ID, LowerName: strings.ToLower(opts.File.Name)}); err != nil {
+		return err
+	} else if has {
+		return ErrPackageFileAlreadyExist{PackageID: p.ID, Name: opts.File.Name}
+	}
+
+	v := &PackageVersion{}
+	has, err = sess.Where("package_id = ? AND lower_version = ?", p.ID, strings.ToLower(opts.Version)).Get(v)
+	if err != nil {
+		return err
+	} else if !has {
+		v = &PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    opts.Creator.ID,
+			Version:      opts.Version,
+			LowerVersion: strings.ToLower(opts.Version),
+			MetadataJSON: opts.MetadataJSON,
+		}
+		if _, err = sess.Insert(v); err != nil {
+			return err
+		}
+	}
+
+	opts.File.PackageID = p.ID
+	opts.File.VersionID = v.ID
+	opts.File.Version = v
+	opts.File.LowerName = strings.ToLower(opts.File.Name)
+	if _, err = sess.Insert(opts.File); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// deletePackagesByOwnerID deletes the packages of the owner, the file contents are kept
+// in the storage as they may be shared with the files of other packages
+func deletePackagesByOwnerID(e Engine, ownerID int64) error {
+	packageIDs := builder.Select("id").From("package").Where(builder.Eq{"owner_id": ownerID})
+	if _, err := e.Where(builder.In("package_id", packageIDs)).Delete(new(PackageFile)); err != nil {
+		return err
+	}
+	if _, err := e.Where(builder.In("package_id", packageIDs)).Delete(new(PackageVersion)); err != nil {
+		return err
+	}
+	_, err := e.Where("owner_id = ?", ownerID).Delete(new(Package))
+	return err
+}
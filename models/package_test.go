@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPackageFile(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	owner := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	add := func(version, name string) error {
+		return AddPackageFile(&AddPackageFileOptions{
+			Owner:        owner,
+			Creator:      owner,
+			Type:         PackageTypePyPI,
+			Name:         "test-package",
+			Version:      version,
+			MetadataJSON: `{"summary":"` + version + `"}`,
+			File:         &PackageFile{Name: name, Size: 3, HashSHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		})
+	}
+
+	assert.NoError(t, add("1.0", "test_package-1.0.tar.gz"))
+	assert.NoError(t, add("1.0", "test_package-1.0-py3-none-any.whl"))
+	assert.NoError(t, add("1.1", "test_package-1.1.tar.gz"))
+	err := add("1.1", "Test_Package-1.1.tar.gz")
+	assert.True(t, IsErrPackageFileAlreadyExist(err))
+
+	p, err := GetPackageByName(owner.ID, PackageTypePyPI, "Test-Package")
+	assert.NoError(t, err)
+	_, err = GetPackageByName(owner.ID, "other", "test-package")
+	assert.True(t, IsErrPackageNotExist(err))
+
+	packages, err := GetPackagesByOwner(owner.ID, PackageTypePyPI)
+	assert.NoError(t, err)
+	assert.Len(t, packages, 1)
+
+	files, err := GetPackageFiles(p.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 3) {
+		assert.Equal(t, "1.0", files[1].Version.Version)
+		// the metadata of the first file of a version is kept
+		assert.Equal(t, `{"summary":"1.0"}`, files[1].Version.MetadataJSON)
+		assert.Equal(t, "1.1", files[2].Version.Version)
+	}
+
+	f, err := GetPackageFile(p.ID, "1.0", "test_package-1.0-py3-none-any.whl")
+	assert.NoError(t, err)
+	assert.Equal(t, files[1].ID, f.ID)
+	_, err = GetPackageFile(p.ID, "1.1", "test_package-1.0-py3-none-any.whl")
+	assert.True(t, IsErrPackageFileNotExist(err))
+
+	assert.NoError(t, deletePackagesByOwnerID(x, owner.ID))
+	AssertNotExistsBean(t, &Package{ID: p.ID})
+	AssertNotExistsBean(t, &PackageFile{PackageID: p.ID})
+	AssertNotExistsBean(t, &PackageVersion{PackageID: p.ID})
+}
+
+func TestGetPackageAccessMode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	privateOrg := AssertExistsAndLoadBean(t, &User{ID: 23}).(*User)
+
+	test := func(owner, doer *User, expected AccessMode) {
+		mode, err := GetPackageAccessMode(owner, doer)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	test(user2, user2, AccessModeWrite)
+	test(user2, user4, AccessModeRead)
+	test(user2, nil, AccessModeRead)
+	// user2 owns org3, user4 is a member of a team which can't create repositories
+	test(org3, user2, AccessModeWrite)
+	test(org3, user4, AccessModeRead)
+	test(privateOrg, user4, AccessModeNone)
+	test(privateOrg, nil, AccessModeNone)
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(PendingRegistration))
+}
+
+// PendingRegistration represents an account registered through an OAuth2 login source requiring
+// the approval of an administrator. Its user is prohibited to sign in until the registration is approved.
+type PendingRegistration struct {
+	ID            int64              `xorm:"pk autoincr"`
+	UserID        int64              `xorm:"UNIQUE NOT NULL"`
+	LoginSourceID int64              `xorm:"INDEX"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+
+	User        *User        `xorm:"-"`
+	LoginSource *LoginSource `xorm:"-"`
+}
+
+// LoadAttributes loads the user and the login source of the registration
+func (r *PendingRegistration) LoadAttributes() (err error) {
+	if r.User == nil {
+		if r.User, err = getUserByID(x, r.UserID); err != nil {
+			return err
+		}
+	}
+	if r.LoginSource == nil {
+		if r.LoginSource, err = GetLoginSourceByID(r.LoginSourceID); err != nil {
+			if !IsErrLoginSourceNotExist(err) {
+				return err
+			}
+			r.LoginSource = nil
+		}
+	}
+	return nil
+}
+
+// CreatePendingRegistration prohibits the user to sign in until an administrator approves its registration
+func CreatePendingRegistration(u *User, loginSourceID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	u.ProhibitLogin = true
+	if _, err := sess.ID(u.ID).Cols("prohibit_login").Update(u); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(&PendingRegistration{UserID: u.ID, LoginSourceID: loginSourceID}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetPendingRegistrationByID returns the pending registration with the given ID
+func GetPendingRegistrationByID(id int64) (*PendingRegistration, error) {
+	r := new(PendingRegistration)
+	has, err := x.ID(id).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPendingRegistrationNotExist{ID: id}
+	}
+	return r, nil
+}
+
+// IsRegistrationPending returns true if the registration of the user waits for the approval of an administrator
+func IsRegistrationPending(userID int64) (bool, error) {
+	return x.Where("user_id = ?", userID).Exist(new(PendingRegistration))
+}
+
+// CountPendingRegistrations returns the number of registrations waiting for an approval
+func CountPendingRegistrations() (int64, error) {
+	return x.Count(new(PendingRegistration))
+}
+
+// PendingRegistrationList is a list of pending registrations
+type PendingRegistrationList []*PendingRegistration
+
+// FindPendingRegistrations returns the pending registrations, oldest first, and their total count
+func FindPendingRegistrations(opts ListOptions) (PendingRegistrationList, int64, error) {
+	sess := x.Asc("id")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	registrations := make(PendingRegistrationList, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&registrations)
+	return registrations, count, err
+}
+
+// LoadAttributes loads the users and the login sources of the registrations
+func (registrations PendingRegistrationList) LoadAttributes() error {
+	for _, r := range registrations {
+		if err := r.LoadAttributes(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApprovePendingRegistration allows the user of the registration to sign in, its account is activated as well.
+// Pending registrations are rejected by deleting their user.
+func ApprovePendingRegistration(r *PendingRegistration) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	affected, err := sess.Delete(&PendingRegistration{ID: r.ID})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrPendingRegistrationNotExist{ID: r.ID}
+	}
+	if _, err := sess.ID(r.UserID).Cols("prohibit_login", "is_active").
+		Update(&User{ProhibitLogin: false, IsActive: true}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/markbates/goth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingRegistration(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	source := &LoginSource{
+		Type:      LoginOAuth2,
+		Name:      "pending-registration",
+		IsActived: true,
+		Cfg:       &OAuth2Config{Provider: "gitea", RequireRegistrationApproval: true},
+	}
+	assert.NoError(t, CreateLoginSource(source))
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.NoError(t, CreatePendingRegistration(user, source.ID))
+	AssertExistsAndLoadBean(t, &User{ID: user.ID, ProhibitLogin: true})
+
+	pending, err := IsRegistrationPending(user.ID)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+	count, err := CountPendingRegistrations()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	registrations, total, err := FindPendingRegistrations(ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	if assert.Len(t, registrations, 1) {
+		assert.NoError(t, registrations.LoadAttributes())
+		assert.Equal(t, user.ID, registrations[0].User.ID)
+		assert.Equal(t, source.ID, registrations[0].LoginSource.ID)
+	}
+
+	r, err := GetPendingRegistrationByID(registrations[0].ID)
+	assert.NoError(t, err)
+	assert.NoError(t, ApprovePendingRegistration(r))
+	assert.True(t, IsErrPendingRegistrationNotExist(ApprovePendingRegistration(r)))
+	AssertExistsAndLoadBean(t, &User{ID: user.ID, ProhibitLogin: false, IsActive: true})
+
+	pending, err = IsRegistrationPending(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, pending)
+	_, err = GetPendingRegistrationByID(r.ID)
+	assert.True(t, IsErrPendingRegistrationNotExist(err))
+}
+
+func TestOAuth2Config_IsRegistrationAllowed(t *testing.T) {
+	gothUser := goth.User{
+		UserID:   "1234",
+		Email:    "User@Example.com",
+		NickName: "user",
+		RawData: map[string]interface{}{
+			"groups":         []interface{}{"developers", "gitea-users"},
+			"role":           "admin",
+			"email_verified": true,
+		},
+	}
+
+	var cases = []struct {
+		cfg     OAuth2Config
+		allowed bool
+	}{
+		{OAuth2Config{}, true},
+		{OAuth2Config{AllowedEmailDomains: []string{"example.com"}}, true},
+		{OAuth2Config{AllowedEmailDomains: []string{"example.org"}}, false},
+		{OAuth2Config{AllowedEmailDomains: []string{"example.org"}, AllowedAccounts: []string{"user@example.com"}}, true},
+		{OAuth2Config{AllowedAccounts: []string{"USER"}}, true},
+		{OAuth2Config{AllowedAccounts: []string{"1234"}}, true},
+		{OAuth2Config{AllowedAccounts: []string{"other"}}, false},
+		{OAuth2Config{RequiredGroup: "gitea-users"}, true},
+		{OAuth2Config{RequiredGroup: "admins"}, false},
+		{OAuth2Config{GroupClaimName: "role", RequiredGroup: "admin"}, true},
+		{OAuth2Config{GroupClaimName: "missing", RequiredGroup: "admin"}, false},
+		{OAuth2Config{AllowedEmailDomains: []string{"example.org"}, RequiredGroup: "gitea-users"}, false},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.allowed, c.cfg.IsRegistrationAllowed(gothUser), "case %d", i)
+	}
+
+	// unverified emails don't grant access
+	for _, verified := range []interface{}{false, "false", nil} {
+		gothUser.RawData["email_verified"] = verified
+		assert.False(t, (&OAuth2Config{AllowedEmailDomains: []string{"example.com"}}).IsRegistrationAllowed(gothUser))
+		assert.False(t, (&OAuth2Config{AllowedAccounts: []string{"user@example.com"}}).IsRegistrationAllowed(gothUser))
+		assert.True(t, (&OAuth2Config{AllowedAccounts: []string{"user"}}).IsRegistrationAllowed(gothUser))
+	}
+	gothUser.RawData["email_verified"] = "true"
+	assert.True(t, (&OAuth2Config{AllowedEmailDomains: []string{"example.com"}}).IsRegistrationAllowed(gothUser))
+
+	assert.Equal(t, []string{"example.com", "example.org", "user"}, ParseOAuth2AllowList(" example.com,example.org\nuser, "))
+}
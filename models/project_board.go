@@ -18,6 +18,9 @@ type (
 
 	// ProjectBoardList is a list of all project boards in a repository
 	ProjectBoardList []*ProjectBoard
+
+	// ProjectBoardAutoMove is used to represent the event moving an issue to a project board automatically
+	ProjectBoardAutoMove uint8
 )
 
 const (
@@ -31,12 +34,46 @@ const (
 	ProjectBoardTypeBugTriage
 )
 
+const (
+	// ProjectBoardAutoMoveNone is a project board issues are only moved to by hand
+	ProjectBoardAutoMoveNone ProjectBoardAutoMove = iota
+
+	// ProjectBoardAutoMoveOnClose is a project board issues and pull requests are moved to when they are closed
+	ProjectBoardAutoMoveOnClose
+
+	// ProjectBoardAutoMoveOnMerge is a project board pull requests are moved to when they are merged
+	ProjectBoardAutoMoveOnMerge
+)
+
+var projectBoardAutoMoveNames = map[ProjectBoardAutoMove]string{
+	ProjectBoardAutoMoveNone:    "none",
+	ProjectBoardAutoMoveOnClose: "close",
+	ProjectBoardAutoMoveOnMerge: "merge",
+}
+
+// Name returns the name of the event
+func (m ProjectBoardAutoMove) Name() string {
+	return projectBoardAutoMoveNames[m]
+}
+
+// ProjectBoardAutoMoveFromName returns the event with the given name
+func ProjectBoardAutoMoveFromName(name string) (ProjectBoardAutoMove, bool) {
+	for m, n := range projectBoardAutoMoveNames {
+		if n == name {
+			return m, true
+		}
+	}
+	return ProjectBoardAutoMoveNone, false
+}
+
 // ProjectBoard is used to represent boards on a project
 type ProjectBoard struct {
 	ID      int64 `xorm:"pk autoincr"`
 	Title   string
 	Default bool `xorm:"NOT NULL DEFAULT false"` // issues not assigned to a specific board will be assigned to this board
 	Sorting int8 `xorm:"NOT NULL DEFAULT 0"`
+	// AutoMove is the event moving issues of the project to this board, at most one board of a project has each
+	AutoMove ProjectBoardAutoMove `xorm:"NOT NULL DEFAULT 0"`
 
 	ProjectID int64 `xorm:"INDEX NOT NULL"`
 	CreatorID int64 `xorm:"NOT NULL"`
@@ -47,6 +84,27 @@ type ProjectBoard struct {
 	Issues []*Issue `xorm:"-"`
 }
 
+var projectBoardTypeNames = map[ProjectBoardType]string{
+	ProjectBoardTypeNone:        "none",
+	ProjectBoardTypeBasicKanban: "basic_kanban",
+	ProjectBoardTypeBugTriage:   "bug_triage",
+}
+
+// Name returns the name of the project board type
+func (p ProjectBoardType) Name() string {
+	return projectBoardTypeNames[p]
+}
+
+// ProjectBoardTypeFromName returns the project board type with the given name
+func ProjectBoardTypeFromName(name string) (ProjectBoardType, bool) {
+	for p, n := range projectBoardTypeNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return ProjectBoardTypeNone, false
+}
+
 // IsProjectBoardTypeValid checks if the project board type is valid
 func IsProjectBoardTypeValid(p ProjectBoardType) bool {
 	switch p {
@@ -178,6 +236,28 @@ func updateProjectBoard(e Engine, board *ProjectBoard) error {
 	return err
 }
 
+// SetProjectBoardAutoMove sets the event moving issues to the board, the event is removed from any other board of the project
+func SetProjectBoardAutoMove(board *ProjectBoard, autoMove ProjectBoardAutoMove) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if autoMove != ProjectBoardAutoMoveNone {
+		if _, err := sess.Where("project_id = ? AND auto_move = ?", board.ProjectID, autoMove).
+			Cols("auto_move").Update(&ProjectBoard{AutoMove: ProjectBoardAutoMoveNone}); err != nil {
+			return err
+		}
+	}
+
+	board.AutoMove = autoMove
+	if _, err := sess.ID(board.ID).Cols("auto_move").Update(board); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
 // GetProjectBoards fetches all boards related to a project
 // if no default board set, first board is a temporary "Uncategorized" board
 func GetProjectBoards(projectID int64) (ProjectBoardList, error) {
@@ -201,6 +201,34 @@ func MoveIssueAcrossProjectBoards(issue *Issue, board *ProjectBoard) error {
 	return sess.Commit()
 }
 
+// autoMoveProjectBoard moves the closed issue to the board of its project expecting closed issues,
+// or merged pull requests, if there is one
+func (i *Issue) autoMoveProjectBoard(e Engine, isMergePull bool) error {
+	var pis ProjectIssue
+	has, err := e.Where("issue_id=?", i.ID).Get(&pis)
+	if err != nil || !has || pis.ProjectID == 0 {
+		return err
+	}
+
+	events := []ProjectBoardAutoMove{ProjectBoardAutoMoveOnClose}
+	if isMergePull {
+		events = []ProjectBoardAutoMove{ProjectBoardAutoMoveOnMerge, ProjectBoardAutoMoveOnClose}
+	}
+	for _, event := range events {
+		var board ProjectBoard
+		has, err := e.Where("project_id=? AND auto_move=?", pis.ProjectID, event).Get(&board)
+		if err != nil {
+			return err
+		}
+		if has {
+			pis.ProjectBoardID = board.ID
+			_, err = e.ID(pis.ID).Cols("project_board_id").Update(&pis)
+			return err
+		}
+	}
+	return nil
+}
+
 func (pb *ProjectBoard) removeIssues(e Engine) error {
 	_, err := e.Exec("UPDATE `project_issue` SET project_board_id = 0 WHERE project_board_id = ? ", pb.ID)
 	return err
@@ -80,3 +80,40 @@ func TestProject(t *testing.T) {
 
 	assert.True(t, projectFromDB.IsClosed)
 }
+
+func TestProjectBoardAutoMove(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	done := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 3}).(*ProjectBoard)
+	inProgress := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 2}).(*ProjectBoard)
+	assert.NoError(t, SetProjectBoardAutoMove(inProgress, ProjectBoardAutoMoveOnClose))
+	assert.NoError(t, SetProjectBoardAutoMove(done, ProjectBoardAutoMoveOnClose))
+
+	// only one board of a project moves issues on each event
+	inProgress = AssertExistsAndLoadBean(t, &ProjectBoard{ID: 2}).(*ProjectBoard)
+	assert.Equal(t, ProjectBoardAutoMoveNone, inProgress.AutoMove)
+	done = AssertExistsAndLoadBean(t, &ProjectBoard{ID: 3}).(*ProjectBoard)
+	assert.Equal(t, ProjectBoardAutoMoveOnClose, done.AutoMove)
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, issue.LoadRepo())
+	_, err := issue.ChangeStatus(doer, true)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, issue.ProjectBoardID())
+
+	// reopening the issue leaves it on its board
+	_, err = issue.ChangeStatus(doer, false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, issue.ProjectBoardID())
+}
+
+func TestProjectBoardAutoMoveFromName(t *testing.T) {
+	for _, m := range []ProjectBoardAutoMove{ProjectBoardAutoMoveNone, ProjectBoardAutoMoveOnClose, ProjectBoardAutoMoveOnMerge} {
+		parsed, ok := ProjectBoardAutoMoveFromName(m.Name())
+		assert.True(t, ok)
+		assert.Equal(t, m, parsed)
+	}
+	_, ok := ProjectBoardAutoMoveFromName("unknown")
+	assert.False(t, ok)
+}
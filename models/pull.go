@@ -10,6 +10,7 @@ import (
 	"io"
 	"strings"
 
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -25,6 +26,16 @@ const (
 	PullRequestGit
 )
 
+// PullRequestFlow the flow of pull request
+type PullRequestFlow int
+
+const (
+	// PullRequestFlowGithub github flow from head branch to base branch
+	PullRequestFlowGithub PullRequestFlow = iota
+	// PullRequestFlowAGit agit flow from a push to refs/for/<base branch>, the head commit is only kept in the pull request ref
+	PullRequestFlowAGit
+)
+
 // PullRequestStatus defines pull request status
 type PullRequestStatus int
 
@@ -61,6 +72,9 @@ type PullRequest struct {
 	BaseBranch      string
 	ProtectedBranch *ProtectedBranch `xorm:"-"`
 	MergeBase       string           `xorm:"VARCHAR(40)"`
+	Flow            PullRequestFlow  `xorm:"NOT NULL DEFAULT 0"`
+	// HeadCommitID is the head commit of an AGit pull request being created, before its ref exists
+	HeadCommitID string `xorm:"-"`
 
 	HasMerged      bool               `xorm:"INDEX"`
 	MergedCommitID string             `xorm:"VARCHAR(40)"`
@@ -203,7 +217,7 @@ func (pr *PullRequest) GetDefaultMergeMessage() string {
 	if pr.HeadRepo == nil {
 		var err error
 		pr.HeadRepo, err = GetRepositoryByID(pr.HeadRepoID)
-		if err != nil {
+		if err != nil && !IsErrRepoNotExist(err) { // Head repo may have been deleted
 			log.Error("GetRepositoryById[%d]: %v", pr.HeadRepoID, err)
 			return ""
 		}
@@ -226,7 +240,7 @@ func (pr *PullRequest) GetDefaultMergeMessage() string {
 		return fmt.Sprintf("Merge pull request '%s' (%s%d) from %s into %s", pr.Issue.Title, issueReference, pr.Issue.Index, pr.HeadBranch, pr.BaseBranch)
 	}
 
-	return fmt.Sprintf("Merge pull request '%s' (%s%d) from %s:%s into %s", pr.Issue.Title, issueReference, pr.Issue.Index, pr.HeadRepo.FullName(), pr.HeadBranch, pr.BaseBranch)
+	return fmt.Sprintf("Merge pull request '%s' (%s%d) from %s:%s into %s", pr.Issue.Title, issueReference, pr.Issue.Index, pr.HeadRepoFullName(), pr.HeadBranch, pr.BaseBranch)
 }
 
 // ReviewCount represents a count of Reviews
@@ -331,6 +345,24 @@ func (pr *PullRequest) GetGitRefName() string {
 	return fmt.Sprintf("refs/pull/%d/head", pr.Index)
 }
 
+// GetHeadRefName returns the full name of the ref of the head commit in the head repository,
+// AGit pull requests have no head branch and only keep it in their pull request ref
+func (pr *PullRequest) GetHeadRefName() string {
+	if pr.Flow == PullRequestFlowAGit {
+		return pr.GetGitRefName()
+	}
+	return git.BranchPrefix + pr.HeadBranch
+}
+
+// HeadRepoFullName returns the full name of the head repository,
+// or a placeholder if it has been deleted and the head is only kept in the pull request ref
+func (pr *PullRequest) HeadRepoFullName() string {
+	if pr.HeadRepo == nil {
+		return "<deleted>"
+	}
+	return pr.HeadRepo.FullName()
+}
+
 // IsChecking returns true if this pull request is still checking conflict.
 func (pr *PullRequest) IsChecking() bool {
 	return pr.Status == PullRequestStatusChecking
@@ -470,11 +502,11 @@ func NewPullRequest(repo *Repository, issue *Issue, labelIDs []int64, uuids []st
 
 // GetUnmergedPullRequest returns a pull request that is open and has not been merged
 // by given head/base and repo/branch.
-func GetUnmergedPullRequest(headRepoID, baseRepoID int64, headBranch, baseBranch string) (*PullRequest, error) {
+func GetUnmergedPullRequest(headRepoID, baseRepoID int64, headBranch, baseBranch string, flow PullRequestFlow) (*PullRequest, error) {
 	pr := new(PullRequest)
 	has, err := x.
-		Where("head_repo_id=? AND head_branch=? AND base_repo_id=? AND base_branch=? AND has_merged=? AND issue.is_closed=?",
-			headRepoID, headBranch, baseRepoID, baseBranch, false, false).
+		Where("head_repo_id=? AND head_branch=? AND base_repo_id=? AND base_branch=? AND has_merged=? AND flow = ? AND issue.is_closed=?",
+			headRepoID, headBranch, baseRepoID, baseBranch, false, flow, false).
 		Join("INNER", "issue", "issue.id=pull_request.issue_id").
 		Get(pr)
 	if err != nil {
@@ -667,7 +699,7 @@ func (pr *PullRequest) GetHeadBranchHTMLURL() string {
 		log.Error("LoadHeadRepo: %v", err)
 		return ""
 	}
-	if pr.HeadRepo == nil {
+	if pr.HeadRepo == nil || pr.Flow == PullRequestFlowAGit {
 		return ""
 	}
 	return pr.HeadRepo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(pr.HeadBranch)
@@ -0,0 +1,145 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(PullAutoMerge))
+}
+
+// PullAutoMerge represents a pull request scheduled to be merged once its checks succeed
+type PullAutoMerge struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PullID      int64              `xorm:"UNIQUE"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	Doer        *User              `xorm:"-"`
+	MergeStyle  MergeStyle         `xorm:"varchar(30)"`
+	Message     string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ErrPullAlreadyScheduledToAutoMerge represents a "PullAlreadyScheduledToAutoMerge" kind of error.
+type ErrPullAlreadyScheduledToAutoMerge struct {
+	PullID int64
+}
+
+// IsErrPullAlreadyScheduledToAutoMerge checks if an error is a ErrPullAlreadyScheduledToAutoMerge.
+func IsErrPullAlreadyScheduledToAutoMerge(err error) bool {
+	_, ok := err.(ErrPullAlreadyScheduledToAutoMerge)
+	return ok
+}
+
+func (err ErrPullAlreadyScheduledToAutoMerge) Error() string {
+	return fmt.Sprintf("pull request is already scheduled to be merged when checks succeed [pull_id: %d]", err.PullID)
+}
+
+// LoadDoer loads the user who scheduled the merge
+func (pam *PullAutoMerge) LoadDoer() (err error) {
+	if pam.Doer == nil {
+		pam.Doer, err = GetUserByID(pam.DoerID)
+	}
+	return err
+}
+
+// ScheduleAutoMerge schedules the pull request to be merged by the doer with the passed style and message
+// once its checks succeed, and adds a comment about it to the pull request
+func ScheduleAutoMerge(doer *User, pr *PullRequest, style MergeStyle, message string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := sess.Exist(&PullAutoMerge{PullID: pr.ID}); err != nil {
+		return err
+	} else if exist {
+		return ErrPullAlreadyScheduledToAutoMerge{PullID: pr.ID}
+	}
+
+	if _, err := sess.Insert(&PullAutoMerge{
+		PullID:     pr.ID,
+		DoerID:     doer.ID,
+		MergeStyle: style,
+		Message:    message,
+	}); err != nil {
+		return err
+	}
+
+	if err := pr.loadIssue(sess); err != nil {
+		return err
+	}
+	if err := pr.Issue.loadRepo(sess); err != nil {
+		return err
+	}
+	if _, err := createComment(sess, &CreateCommentOptions{
+		Type:  CommentTypePRScheduledToAutoMerge,
+		Doer:  doer,
+		Repo:  pr.Issue.Repo,
+		Issue: pr.Issue,
+	}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetScheduledMergeByPullID returns the scheduled merge of the pull request, or nil if it is not scheduled to be merged
+func GetScheduledMergeByPullID(pullID int64) (*PullAutoMerge, error) {
+	scheduled := new(PullAutoMerge)
+	has, err := x.Where("pull_id = ?", pullID).Get(scheduled)
+	if err != nil || !has {
+		return nil, err
+	}
+	return scheduled, nil
+}
+
+// GetScheduledMergesByRepoID returns the scheduled merges of the open pull requests into or from the repository
+func GetScheduledMergesByRepoID(repoID int64) ([]*PullAutoMerge, error) {
+	scheduled := make([]*PullAutoMerge, 0, 5)
+	return scheduled, x.Table("pull_auto_merge").
+		Join("INNER", "pull_request", "pull_request.id = pull_auto_merge.pull_id").
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Where("pull_request.base_repo_id = ? OR pull_request.head_repo_id = ?", repoID, repoID).
+		And("issue.is_closed = ?", false).
+		Select("pull_auto_merge.*").
+		Find(&scheduled)
+}
+
+// RemoveScheduledAutoMerge cancels the scheduled merge of the pull request.
+// A comment is added to the pull request when a doer is passed.
+func RemoveScheduledAutoMerge(doer *User, pr *PullRequest) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	deleted, err := sess.Delete(&PullAutoMerge{PullID: pr.ID})
+	if err != nil {
+		return err
+	}
+
+	if doer != nil && deleted > 0 {
+		if err := pr.loadIssue(sess); err != nil {
+			return err
+		}
+		if err := pr.Issue.loadRepo(sess); err != nil {
+			return err
+		}
+		if _, err := createComment(sess, &CreateCommentOptions{
+			Type:  CommentTypePRUnScheduledToAutoMerge,
+			Doer:  doer,
+			Repo:  pr.Issue.Repo,
+			Issue: pr.Issue,
+		}); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
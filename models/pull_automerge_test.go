@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleAutoMerge(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+
+	scheduled, err := GetScheduledMergeByPullID(pr.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, scheduled)
+
+	assert.NoError(t, ScheduleAutoMerge(doer, pr, MergeStyleSquash, "Squash message"))
+	AssertExistsAndLoadBean(t, &Comment{IssueID: pr.IssueID, Type: CommentTypePRScheduledToAutoMerge, PosterID: doer.ID})
+
+	err = ScheduleAutoMerge(doer, pr, MergeStyleMerge, "")
+	assert.True(t, IsErrPullAlreadyScheduledToAutoMerge(err))
+
+	scheduled, err = GetScheduledMergeByPullID(pr.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, scheduled) {
+		assert.EqualValues(t, doer.ID, scheduled.DoerID)
+		assert.Equal(t, MergeStyleSquash, scheduled.MergeStyle)
+		assert.Equal(t, "Squash message", scheduled.Message)
+	}
+
+	scheduledMerges, err := GetScheduledMergesByRepoID(pr.BaseRepoID)
+	assert.NoError(t, err)
+	assert.Len(t, scheduledMerges, 1)
+
+	assert.NoError(t, RemoveScheduledAutoMerge(doer, pr))
+	AssertNotExistsBean(t, &PullAutoMerge{PullID: pr.ID})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: pr.IssueID, Type: CommentTypePRUnScheduledToAutoMerge, PosterID: doer.ID})
+
+	// removing a merge which is not scheduled does not add a comment
+	assert.NoError(t, RemoveScheduledAutoMerge(doer, pr))
+	AssertCount(t, &Comment{IssueID: pr.IssueID, Type: CommentTypePRUnScheduledToAutoMerge}, 1)
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// CountMergedPullRequestsByPosters returns the number of merged pull requests of each of the given posters in the base repository
+func CountMergedPullRequestsByPosters(repoID int64, posterIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(posterIDs))
+	if len(posterIDs) == 0 {
+		return counts, nil
+	}
+
+	rows := make([]*struct {
+		PosterID int64
+		Count    int64
+	}, 0, len(posterIDs))
+	if err := x.Table("pull_request").
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Where("pull_request.base_repo_id = ? AND pull_request.has_merged = ?", repoID, true).
+		In("issue.poster_id", posterIDs).
+		Select("issue.poster_id AS poster_id, COUNT(*) AS count").
+		GroupBy("issue.poster_id").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.PosterID] = row.Count
+	}
+	return counts, nil
+}
+
+// IsFirstTimeContributor returns true if the poster of the pull request has no other merged pull request in the base repository
+func (pr *PullRequest) IsFirstTimeContributor() (bool, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return false, err
+	}
+	count, err := x.
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Where("pull_request.base_repo_id = ? AND pull_request.has_merged = ? AND pull_request.id <> ? AND issue.poster_id = ?",
+			pr.BaseRepoID, true, pr.ID, pr.Issue.PosterID).
+		Count(new(PullRequest))
+	return count == 0, err
+}
+
+// IsExternalContribution returns true if the poster of the pull request is neither the owner,
+// a collaborator nor a member of the organization owning the base repository
+func (pr *PullRequest) IsExternalContribution() (bool, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return false, err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, err
+	}
+	isMember, err := pr.BaseRepo.IsContributorMember(pr.Issue.PosterID)
+	return !isMember, err
+}
+
+// IsContributorMember returns true if the user is the owner, a collaborator or a member of the organization owning the repository
+func (repo *Repository) IsContributorMember(userID int64) (bool, error) {
+	if repo.OwnerID == userID {
+		return true, nil
+	}
+	isCollaborator, err := repo.IsCollaborator(userID)
+	if err != nil || isCollaborator {
+		return isCollaborator, err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return false, err
+	}
+	if repo.Owner.IsOrganization() {
+		return IsOrganizationMember(repo.OwnerID, userID)
+	}
+	return false, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMergedPullRequestsByPosters(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	counts, err := CountMergedPullRequestsByPosters(1, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 1}, counts)
+}
+
+func TestPullRequest_IsFirstTimeContributor(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// the merged pull request 1 is the only one of its poster
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	isFirstTime, err := pr.IsFirstTimeContributor()
+	assert.NoError(t, err)
+	assert.True(t, isFirstTime)
+
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	isFirstTime, err = pr.IsFirstTimeContributor()
+	assert.NoError(t, err)
+	assert.False(t, isFirstTime)
+}
+
+func TestPullRequest_IsExternalContribution(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user 1 is not a collaborator of repo 1
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	isExternal, err := pr.IsExternalContribution()
+	assert.NoError(t, err)
+	assert.True(t, isExternal)
+
+	// user 2 is a member of the organization owning repo 3
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 6}).(*PullRequest)
+	isExternal, err = pr.IsExternalContribution()
+	assert.NoError(t, err)
+	assert.False(t, isExternal)
+}
@@ -47,12 +47,12 @@ func listPullRequestStatement(baseRepoID int64, opts *PullRequestsOptions) (*xor
 }
 
 // GetUnmergedPullRequestsByHeadInfo returns all pull requests that are open and has not been merged
-// by given head information (repo and branch).
+// by given head information (repo and branch), AGit pull requests have no head branch and are left out.
 func GetUnmergedPullRequestsByHeadInfo(repoID int64, branch string) ([]*PullRequest, error) {
 	prs := make([]*PullRequest, 0, 2)
 	return prs, x.
-		Where("head_repo_id = ? AND head_branch = ? AND has_merged = ? AND issue.is_closed = ?",
-			repoID, branch, false, false).
+		Where("head_repo_id = ? AND head_branch = ? AND has_merged = ? AND issue.is_closed = ? AND flow = ?",
+			repoID, branch, false, false, PullRequestFlowGithub).
 		Join("INNER", "issue", "issue.id = pull_request.issue_id").
 		Find(&prs)
 }
@@ -68,6 +68,26 @@ func GetUnmergedPullRequestsByBaseInfo(repoID int64, branch string) ([]*PullRequ
 		Find(&prs)
 }
 
+// GetUnmergedPullRequestIDsByBaseOwner returns the IDs of all pull requests that are open and have not been merged
+// into the repositories of the given owner.
+func GetUnmergedPullRequestIDsByBaseOwner(ownerID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, x.Table("pull_request").
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Join("INNER", "repository", "repository.id=pull_request.base_repo_id").
+		Where("repository.owner_id=? AND pull_request.has_merged=? AND issue.is_closed=?", ownerID, false, false).
+		Cols("pull_request.id").
+		Find(&ids)
+}
+
+// CountPullRequestsByPoster returns the number of pull requests opened by the given user in the given base repository.
+func CountPullRequestsByPoster(repoID, posterID int64) (int64, error) {
+	return x.
+		Where("pull_request.base_repo_id=? AND issue.poster_id=?", repoID, posterID).
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Count(new(PullRequest))
+}
+
 // GetPullRequestIDsByCheckStatus returns all pull requests according the special checking status.
 func GetPullRequestIDsByCheckStatus(status PullRequestStatus) ([]int64, error) {
 	prs := make([]int64, 0, 10)
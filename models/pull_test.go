@@ -92,15 +92,35 @@ func TestPullRequestsOldest(t *testing.T) {
 
 func TestGetUnmergedPullRequest(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
-	pr, err := GetUnmergedPullRequest(1, 1, "branch2", "master")
+	pr, err := GetUnmergedPullRequest(1, 1, "branch2", "master", PullRequestFlowGithub)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), pr.ID)
 
-	_, err = GetUnmergedPullRequest(1, 9223372036854775807, "branch1", "master")
+	_, err = GetUnmergedPullRequest(1, 9223372036854775807, "branch1", "master", PullRequestFlowGithub)
 	assert.Error(t, err)
 	assert.True(t, IsErrPullRequestNotExist(err))
 }
 
+func TestGetUnmergedPullRequest_AGit(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	_, err := GetUnmergedPullRequest(1, 1, "branch2", "master", PullRequestFlowAGit)
+	assert.True(t, IsErrPullRequestNotExist(err))
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.Flow = PullRequestFlowAGit
+	_, err = x.ID(pr.ID).Cols("flow").Update(pr)
+	assert.NoError(t, err)
+
+	pr, err = GetUnmergedPullRequest(1, 1, "branch2", "master", PullRequestFlowAGit)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pr.ID)
+	assert.Equal(t, pr.GetGitRefName(), pr.GetHeadRefName())
+
+	prs, err := GetUnmergedPullRequestsByHeadInfo(1, "branch2")
+	assert.NoError(t, err)
+	assert.Len(t, prs, 0)
+}
+
 func TestGetUnmergedPullRequestsByHeadInfo(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	prs, err := GetUnmergedPullRequestsByHeadInfo(1, "branch2")
@@ -112,6 +132,17 @@ func TestGetUnmergedPullRequestsByHeadInfo(t *testing.T) {
 	}
 }
 
+func TestCountPullRequestsByPoster(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	count, err := CountPullRequestsByPoster(1, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+
+	count, err = CountPullRequestsByPoster(1, 2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
 func TestGetUnmergedPullRequestsByBaseInfo(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	prs, err := GetUnmergedPullRequestsByBaseInfo(1, "master")
@@ -246,6 +277,15 @@ func TestPullRequest_GetDefaultMergeMessage_InternalTracker(t *testing.T) {
 	assert.Equal(t, "Merge pull request 'issue3' (#3) from user2/repo1:branch2 into master", pr.GetDefaultMergeMessage())
 }
 
+func TestPullRequest_GetDefaultMergeMessage_DeletedHeadRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.HeadRepoID = NonexistentID
+
+	assert.Equal(t, "<deleted>", pr.HeadRepoFullName())
+	assert.Equal(t, "Merge pull request 'issue3' (#3) from <deleted>:branch2 into master", pr.GetDefaultMergeMessage())
+}
+
 func TestPullRequest_GetDefaultMergeMessage_ExternalTracker(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -246,6 +246,8 @@ type Repository struct {
 
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
 	Avatar string `xorm:"VARCHAR(64)"`
+	// AccentColor is the #rrggbb color of the repository, empty if it has none
+	AccentColor string `xorm:"VARCHAR(7)"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -308,6 +310,16 @@ func (repo *Repository) FullName() string {
 	return repo.OwnerName + "/" + repo.Name
 }
 
+// IncomingEmailAddress returns the address patches and replies can be sent to for the repository,
+// it is empty if incoming email is disabled
+func (repo *Repository) IncomingEmailAddress() string {
+	if !setting.IncomingEmail.Enabled {
+		return ""
+	}
+	at := strings.LastIndex(setting.IncomingEmail.Address, "@")
+	return setting.IncomingEmail.Address[:at] + "+" + repo.FullName() + setting.IncomingEmail.Address[at:]
+}
+
 // HTMLURL returns the repository HTML URL
 func (repo *Repository) HTMLURL() string {
 	return setting.AppURL + repo.FullName()
@@ -423,6 +435,11 @@ func (repo *Repository) MustGetUnit(tp UnitType) *RepoUnit {
 			Type:   tp,
 			Config: new(IssuesConfig),
 		}
+	} else if tp == UnitTypeCode {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(CodeConfig),
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -488,6 +505,9 @@ func (repo *Repository) ComposeMetas() map[string]string {
 			switch unit.ExternalTrackerConfig().ExternalTrackerStyle {
 			case markup.IssueNameStyleAlphanumeric:
 				metas["style"] = markup.IssueNameStyleAlphanumeric
+			case markup.IssueNameStyleRegexp:
+				metas["style"] = markup.IssueNameStyleRegexp
+				metas["regexp"] = unit.ExternalTrackerConfig().ExternalTrackerRegexpPattern
 			default:
 				metas["style"] = markup.IssueNameStyleNumeric
 			}
@@ -833,6 +853,11 @@ func (repo *Repository) GetWriters() (_ []*User, err error) {
 	return repo.getUsersWithAccessMode(x, AccessModeWrite)
 }
 
+// GetAdmins returns all users that have admin access to the repository.
+func (repo *Repository) GetAdmins() (_ []*User, err error) {
+	return repo.getUsersWithAccessMode(x, AccessModeAdmin)
+}
+
 // IsReader returns true if user has explicit read access or higher to the repository.
 func (repo *Repository) IsReader(userID int64) (bool, error) {
 	if repo.OwnerID == userID {
@@ -1100,6 +1125,12 @@ func CreateRepository(ctx DBContext, doer, u *User, repo *Repository, overwriteO
 				Type:   tp,
 				Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true, DefaultMergeStyle: MergeStyleMerge},
 			})
+		} else if tp == UnitTypeCode {
+			units = append(units, RepoUnit{
+				RepoID: repo.ID,
+				Type:   tp,
+				Config: new(CodeConfig),
+			})
 		} else {
 			units = append(units, RepoUnit{
 				RepoID: repo.ID,
@@ -1483,6 +1514,14 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
+	if _, err := sess.Exec("DELETE FROM `pull_auto_merge` WHERE pull_id IN (SELECT id FROM `pull_request` WHERE base_repo_id = ?)", repoID); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM `issue_triage_run` WHERE rule_id IN (SELECT id FROM `issue_triage_rule` WHERE repo_id = ?)", repoID); err != nil {
+		return err
+	}
+
 	if err := deleteBeans(sess,
 		&Access{RepoID: repo.ID},
 		&Action{RepoID: repo.ID},
@@ -1490,7 +1529,10 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&Comment{RefRepoID: repoID},
 		&CommitStatus{RepoID: repoID},
 		&DeletedBranch{RepoID: repoID},
+		&Deployment{RepoID: repoID},
 		&HookTask{RepoID: repoID},
+		&InstanceTemplate{RepoID: repoID},
+		&IssueTriageRule{RepoID: repoID},
 		&LFSLock{RepoID: repoID},
 		&LanguageStat{RepoID: repoID},
 		&Milestone{RepoID: repoID},
@@ -1501,8 +1543,13 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&PullRequest{BaseRepoID: repoID},
 		&PushMirror{RepoID: repoID},
 		&Release{RepoID: repoID},
+		&RepoAccessRequest{RepoID: repoID},
+		&RepoEvent{RepoID: repoID},
 		&RepoIndexerStatus{RepoID: repoID},
+		&RepoPages{RepoID: repoID},
+		&RepoSnapshot{RepoID: repoID},
 		&RepoRedirect{RedirectRepoID: repoID},
+		&ReviewerStats{RepoID: repoID},
 		&RepoUnit{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Task{RepoID: repoID},
@@ -1512,6 +1559,10 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err := deleteStarListsByRepoID(sess, repoID); err != nil {
+		return fmt.Errorf("deleteStarListsByRepoID: %v", err)
+	}
+
 	// Delete Labels and related objects
 	if err := deleteLabelsByRepoID(sess, repoID); err != nil {
 		return err
@@ -1559,6 +1610,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 	// FIXME: Remove repository files should be executed after transaction succeed.
 	repoPath := repo.RepoPath()
 	removeAllWithNotice(sess, "Delete repository files", repoPath)
+	removeAllWithNotice(sess, "Delete repository pages", RepoPagesPath(repoID))
 
 	err = repo.deleteWiki(sess)
 	if err != nil {
@@ -2021,6 +2073,62 @@ func (repo *Repository) GetForks(listOptions ListOptions) ([]*Repository, error)
 	return forks, sess.Find(&forks, &Repository{ForkID: repo.ID})
 }
 
+// ConvertForkToNormalRepository detaches the fork from its parent repository and decreases the fork count of the parent.
+// The forks of the repository stay its forks and its closed pull requests keep referencing it,
+// but a fork can't be detached while it has open pull requests with other repositories.
+func ConvertForkToNormalRepository(repo *Repository) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	fork := new(Repository)
+	has, err := sess.ID(repo.ID).Get(fork)
+	if err != nil {
+		return err
+	} else if !has {
+		return ErrRepoNotExist{ID: repo.ID}
+	}
+	if !fork.IsFork {
+		repo.IsFork = false
+		repo.ForkID = 0
+		return nil
+	}
+
+	count, err := sess.Table("pull_request").
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Where("issue.is_closed = ?", false).
+		And(builder.Or(
+			builder.Eq{"pull_request.head_repo_id": repo.ID}.And(builder.Neq{"pull_request.base_repo_id": repo.ID}),
+			builder.Eq{"pull_request.base_repo_id": repo.ID}.And(builder.Neq{"pull_request.head_repo_id": repo.ID}),
+		)).
+		Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrForkHasOpenPullRequests{RepoID: repo.ID, Count: count}
+	}
+
+	// Only the conversion which actually detached the fork decreases the fork count of the parent
+	affected, err := sess.ID(repo.ID).Where("is_fork = ?", true).Cols("is_fork", "fork_id").Update(&Repository{})
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		if _, err := sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", fork.ForkID); err != nil {
+			return fmt.Errorf("decrease fork count: %v", err)
+		}
+	}
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+	repo.IsFork = false
+	repo.ForkID = 0
+	return nil
+}
+
 // GetUserFork return user forked repository from this repository, if not forked return nil
 func (repo *Repository) GetUserFork(userID int64) (*Repository, error) {
 	var forkedRepo Repository
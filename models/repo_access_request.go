@@ -0,0 +1,257 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables, new(RepoAccessRequest))
+}
+
+// RepoAccessRequestStatus is the status of a repository access request
+type RepoAccessRequestStatus int
+
+// Statuses of repository access requests
+const (
+	RepoAccessRequestPending RepoAccessRequestStatus = iota
+	RepoAccessRequestApproved
+	RepoAccessRequestRejected
+)
+
+var repoAccessRequestStatusNames = map[RepoAccessRequestStatus]string{
+	RepoAccessRequestPending:  "pending",
+	RepoAccessRequestApproved: "approved",
+	RepoAccessRequestRejected: "rejected",
+}
+
+func (s RepoAccessRequestStatus) String() string {
+	return repoAccessRequestStatusNames[s]
+}
+
+// ParseRepoAccessRequestStatus returns the status of the given name, ok is false if it is unknown
+func ParseRepoAccessRequestStatus(name string) (status RepoAccessRequestStatus, ok bool) {
+	for s, n := range repoAccessRequestStatusNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return RepoAccessRequestPending, false
+}
+
+// RepoAccessRequest represents the request of a user to access a private repository.
+// Reviewed requests are kept as a record of who granted which access.
+type RepoAccessRequest struct {
+	ID      int64                   `xorm:"pk autoincr"`
+	RepoID  int64                   `xorm:"INDEX"`
+	UserID  int64                   `xorm:"INDEX"`
+	Message string                  `xorm:"TEXT"`
+	Status  RepoAccessRequestStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// Mode is the access granted when the request was approved
+	Mode         AccessMode
+	ReviewerID   int64
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	ReviewedUnix timeutil.TimeStamp
+
+	Repo     *Repository `xorm:"-"`
+	User     *User       `xorm:"-"`
+	Reviewer *User       `xorm:"-"`
+}
+
+// IsPending returns true if the request has not been reviewed yet
+func (r *RepoAccessRequest) IsPending() bool {
+	return r.Status == RepoAccessRequestPending
+}
+
+// LoadAttributes loads the repository, the requester and the reviewer of the request
+func (r *RepoAccessRequest) LoadAttributes() (err error) {
+	if r.Repo == nil {
+		if r.Repo, err = GetRepositoryByID(r.RepoID); err != nil {
+			return err
+		}
+	}
+	if r.User == nil {
+		if r.User, err = getUserByID(x, r.UserID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			r.User = NewGhostUser()
+		}
+	}
+	if r.Reviewer == nil && r.ReviewerID > 0 {
+		if r.Reviewer, err = getUserByID(x, r.ReviewerID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			r.Reviewer = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// CreateRepoAccessRequest creates a pending access request, a user can only have one per repository
+func CreateRepoAccessRequest(r *RepoAccessRequest) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Where("repo_id = ? AND user_id = ? AND status = ?", r.RepoID, r.UserID, RepoAccessRequestPending).
+		Exist(new(RepoAccessRequest))
+	if err != nil {
+		return err
+	} else if has {
+		return ErrRepoAccessRequestAlreadyExist{RepoID: r.RepoID, UserID: r.UserID}
+	}
+
+	r.Status = RepoAccessRequestPending
+	if _, err := sess.Insert(r); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// IsRepoAccessRequestable returns true if the user knows the repository exists even without access to it,
+// that is if the user is a member of the organization owning it. For anybody else a private repository
+// must not be told apart from a repository which doesn't exist.
+func IsRepoAccessRequestable(user *User, repo *Repository) (bool, error) {
+	if user == nil || user.IsOrganization() {
+		return false, nil
+	}
+	if err := repo.GetOwner(); err != nil {
+		return false, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return false, nil
+	}
+	return repo.Owner.IsOrgMember(user.ID)
+}
+
+// GetRepoAccessRequestByID returns the access request of a repository by its ID
+func GetRepoAccessRequestByID(repoID, id int64) (*RepoAccessRequest, error) {
+	r := &RepoAccessRequest{ID: id, RepoID: repoID}
+	has, err := x.Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoAccessRequestNotExist{ID: id, RepoID: repoID}
+	}
+	return r, nil
+}
+
+// GetPendingRepoAccessRequest returns the pending access request of the user to the repository
+func GetPendingRepoAccessRequest(repoID, userID int64) (*RepoAccessRequest, error) {
+	r := &RepoAccessRequest{RepoID: repoID, UserID: userID}
+	has, err := x.Where("status = ?", RepoAccessRequestPending).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoAccessRequestNotExist{RepoID: repoID}
+	}
+	return r, nil
+}
+
+// CountPendingRepoAccessRequests returns the number of pending access requests to the repository
+func CountPendingRepoAccessRequests(repoID int64) (int64, error) {
+	return x.Where("repo_id = ? AND status = ?", repoID, RepoAccessRequestPending).Count(new(RepoAccessRequest))
+}
+
+// FindRepoAccessRequestsOptions represents the options to search repository access requests
+type FindRepoAccessRequestsOptions struct {
+	ListOptions
+	RepoID int64
+	UserID int64
+	// Statuses are the statuses of the requests to return, all of them if empty
+	Statuses []RepoAccessRequestStatus
+}
+
+func (opts *FindRepoAccessRequestsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"user_id": opts.UserID})
+	}
+	if len(opts.Statuses) > 0 {
+		cond = cond.And(builder.In("status", opts.Statuses))
+	}
+	return cond
+}
+
+// FindRepoAccessRequests returns the access requests matching the options, most recent first, and their total count
+func FindRepoAccessRequests(opts *FindRepoAccessRequestsOptions) (RepoAccessRequestList, int64, error) {
+	sess := x.Where(opts.toConds()).Desc("id")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	requests := make(RepoAccessRequestList, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&requests)
+	return requests, count, err
+}
+
+// RepoAccessRequestList is a list of repository access requests
+type RepoAccessRequestList []*RepoAccessRequest
+
+// LoadAttributes loads the repositories, the requesters and the reviewers of the requests
+func (requests RepoAccessRequestList) LoadAttributes() error {
+	for _, r := range requests {
+		if err := r.LoadAttributes(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewRepoAccessRequest marks a pending request as reviewed by reviewer
+func reviewRepoAccessRequest(e Engine, r *RepoAccessRequest, reviewer *User, status RepoAccessRequestStatus, mode AccessMode) error {
+	r.Status = status
+	r.Mode = mode
+	r.ReviewerID = reviewer.ID
+	r.Reviewer = reviewer
+	r.ReviewedUnix = timeutil.TimeStampNow()
+	affected, err := e.Where("id = ? AND status = ?", r.ID, RepoAccessRequestPending).
+		Cols("status", "mode", "reviewer_id", "reviewed_unix").
+		Update(r)
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrRepoAccessRequestNotPending{ID: r.ID}
+	}
+	return nil
+}
+
+// ApproveRepoAccessRequest approves a pending request, its user becomes a collaborator of the repository with the given access mode
+func ApproveRepoAccessRequest(r *RepoAccessRequest, reviewer *User, mode AccessMode) error {
+	if err := r.LoadAttributes(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := reviewRepoAccessRequest(sess, r, reviewer, RepoAccessRequestApproved, mode); err != nil {
+		return err
+	}
+	if err := r.Repo.addCollaborator(sess, r.User); err != nil {
+		return err
+	}
+	if err := r.Repo.changeCollaborationAccessMode(sess, r.UserID, mode); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// RejectRepoAccessRequest rejects a pending request
+func RejectRepoAccessRequest(r *RepoAccessRequest, reviewer *User) error {
+	return reviewRepoAccessRequest(x, r, reviewer, RepoAccessRequestRejected, AccessModeNone)
+}
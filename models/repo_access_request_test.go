@@ -0,0 +1,83 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoAccessRequest(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	owner := AssertExistsAndLoadBean(t, &User{ID: repo.OwnerID}).(*User)
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	perm, err := GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.Equal(t, AccessModeNone, perm.AccessMode)
+
+	req := &RepoAccessRequest{RepoID: repo.ID, UserID: user.ID, Message: "please"}
+	assert.NoError(t, CreateRepoAccessRequest(req))
+	assert.True(t, IsErrRepoAccessRequestAlreadyExist(CreateRepoAccessRequest(&RepoAccessRequest{RepoID: repo.ID, UserID: user.ID})))
+
+	pending, err := GetPendingRepoAccessRequest(repo.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, req.ID, pending.ID)
+	count, err := CountPendingRepoAccessRequests(repo.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	admins, err := repo.GetAdmins()
+	assert.NoError(t, err)
+	assert.Contains(t, admins, owner)
+
+	assert.NoError(t, ApproveRepoAccessRequest(req, owner, AccessModeRead))
+	assert.True(t, IsErrRepoAccessRequestNotPending(RejectRepoAccessRequest(req, owner)))
+
+	perm, err = GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.Equal(t, AccessModeRead, perm.AccessMode)
+
+	req, err = GetRepoAccessRequestByID(repo.ID, req.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, RepoAccessRequestApproved, req.Status)
+	assert.Equal(t, AccessModeRead, req.Mode)
+	assert.Equal(t, owner.ID, req.ReviewerID)
+
+	requests, total, err := FindRepoAccessRequests(&FindRepoAccessRequestsOptions{
+		UserID:   user.ID,
+		Statuses: []RepoAccessRequestStatus{RepoAccessRequestPending},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, total)
+	assert.Empty(t, requests)
+
+	_, err = GetRepoAccessRequestByID(1, req.ID)
+	assert.True(t, IsErrRepoAccessRequestNotExist(err))
+}
+
+func TestIsRepoAccessRequestable(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	for repoID, expected := range map[int64]bool{
+		2:  false, // private repository of a user
+		5:  true,  // private repository of an organization user4 is a member of
+		24: false, // private repository of another organization
+	} {
+		repo := AssertExistsAndLoadBean(t, &Repository{ID: repoID}).(*Repository)
+		requestable, err := IsRepoAccessRequestable(user4, repo)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, requestable, "repo %d", repoID)
+	}
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 5}).(*Repository)
+	requestable, err := IsRepoAccessRequestable(nil, repo)
+	assert.NoError(t, err)
+	assert.False(t, requestable)
+}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"fmt"
@@ -161,6 +162,19 @@ func (repo *Repository) UploadAvatar(data []byte) error {
 	return sess.Commit()
 }
 
+// GenerateAvatar replaces the avatar of the repository by one generated from its name.
+func (repo *Repository) GenerateAvatar() error {
+	img, err := avatar.RandomImage([]byte(repo.FullName()))
+	if err != nil {
+		return fmt.Errorf("RandomImage: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("Encode: %v", err)
+	}
+	return repo.UploadAvatar(buf.Bytes())
+}
+
 // DeleteAvatar deletes the repos's custom avatar.
 func (repo *Repository) DeleteAvatar() error {
 	// Avatar not exists
@@ -114,6 +114,11 @@ func (repo *Repository) getCollaboration(e Engine, uid int64) (*Collaboration, e
 	return collaboration, err
 }
 
+// GetCollaboration returns the collaboration of the user with the given ID, or nil if the user is no collaborator
+func (repo *Repository) GetCollaboration(uid int64) (*Collaboration, error) {
+	return repo.getCollaboration(x, uid)
+}
+
 func (repo *Repository) isCollaborator(e Engine, userID int64) (bool, error) {
 	return e.Get(&Collaboration{RepoID: repo.ID, UserID: userID})
 }
@@ -0,0 +1,174 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables, new(Deployment))
+}
+
+// Deployment represents an environment deployed by an external service, e.g. the review app of a pull request
+type Deployment struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX NOT NULL"`
+	PullID      int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Environment string `xorm:"UNIQUE(s) NOT NULL"`
+	URL         string `xorm:"TEXT NOT NULL"`
+	SHA         string `xorm:"VARCHAR(40)"`
+	CreatorID   int64
+	Creator     *User `xorm:"-"`
+	// IsActive is false once the environment has been torn down
+	IsActive bool `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrDeploymentNotExist represents a "DeploymentNotExist" kind of error.
+type ErrDeploymentNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrDeploymentNotExist checks if an error is a ErrDeploymentNotExist.
+func IsErrDeploymentNotExist(err error) bool {
+	_, ok := err.(ErrDeploymentNotExist)
+	return ok
+}
+
+func (err ErrDeploymentNotExist) Error() string {
+	return fmt.Sprintf("deployment does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// LoadCreator loads the user who registered the deployment
+func (d *Deployment) LoadCreator() (err error) {
+	if d.Creator == nil {
+		d.Creator, err = GetUserByID(d.CreatorID)
+		if IsErrUserNotExist(err) {
+			d.Creator = NewGhostUser()
+			err = nil
+		}
+	}
+	return err
+}
+
+// CreateOrUpdateDeployment registers the deployment of an environment for a pull request,
+// replacing the previous deployment of the same environment. It returns true if the deployment is new.
+func CreateOrUpdateDeployment(d *Deployment) (isNew bool, err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return false, err
+	}
+
+	existing := new(Deployment)
+	has, err := sess.Where("pull_id = ? AND environment = ?", d.PullID, d.Environment).Get(existing)
+	if err != nil {
+		return false, err
+	}
+
+	d.IsActive = true
+	if has {
+		d.ID = existing.ID
+		d.CreatedUnix = existing.CreatedUnix
+		if _, err := sess.ID(d.ID).Cols("url", "sha", "creator_id", "is_active").Update(d); err != nil {
+			return false, err
+		}
+	} else if _, err := sess.Insert(d); err != nil {
+		return false, err
+	}
+	return !has, sess.Commit()
+}
+
+// GetDeploymentByID returns the deployment of the repository with the given ID
+func GetDeploymentByID(repoID, id int64) (*Deployment, error) {
+	d := new(Deployment)
+	has, err := x.ID(id).Where("repo_id = ?", repoID).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrDeploymentNotExist{ID: id, RepoID: repoID}
+	}
+	return d, nil
+}
+
+// FindDeploymentsOptions describes the conditions to find the deployments of a repository
+type FindDeploymentsOptions struct {
+	ListOptions
+	RepoID     int64
+	PullID     int64
+	OnlyActive bool
+}
+
+func (opts *FindDeploymentsOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
+	if opts.PullID > 0 {
+		cond = cond.And(builder.Eq{"pull_id": opts.PullID})
+	}
+	if opts.OnlyActive {
+		cond = cond.And(builder.Eq{"is_active": true})
+	}
+	return cond
+}
+
+// FindDeployments returns the deployments matching the options, the most recent first
+func FindDeployments(opts FindDeploymentsOptions) ([]*Deployment, error) {
+	sess := x.Where(opts.toConds()).Desc("updated_unix", "id")
+	if opts.PageSize != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	deployments := make([]*Deployment, 0, opts.PageSize)
+	return deployments, sess.Find(&deployments)
+}
+
+// CountDeployments returns the number of deployments matching the options
+func CountDeployments(opts FindDeploymentsOptions) (int64, error) {
+	return x.Where(opts.toConds()).Count(new(Deployment))
+}
+
+// TearDownDeployments marks the active deployments of a pull request as torn down and returns them
+func TearDownDeployments(pr *PullRequest) ([]*Deployment, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	deployments := make([]*Deployment, 0, 2)
+	if err := sess.Where("pull_id = ? AND is_active = ?", pr.ID, true).Find(&deployments); err != nil {
+		return nil, err
+	}
+	if len(deployments) == 0 {
+		return deployments, nil
+	}
+
+	if _, err := sess.Where("pull_id = ? AND is_active = ?", pr.ID, true).
+		Cols("is_active").
+		Update(&Deployment{IsActive: false}); err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		d.IsActive = false
+	}
+	return deployments, sess.Commit()
+}
+
+// DeleteDeployment deletes a deployment of a repository
+func DeleteDeployment(repoID, id int64) error {
+	deleted, err := x.ID(id).Where("repo_id = ?", repoID).Delete(new(Deployment))
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrDeploymentNotExist{ID: id, RepoID: repoID}
+	}
+	return nil
+}
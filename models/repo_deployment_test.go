@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrUpdateDeployment(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+
+	d := &Deployment{RepoID: pr.BaseRepoID, PullID: pr.ID, Environment: "review", URL: "https://pr-2.example.com", CreatorID: 2}
+	isNew, err := CreateOrUpdateDeployment(d)
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+	assert.True(t, d.IsActive)
+
+	// deploying the environment again replaces its URL
+	redeployed := &Deployment{RepoID: pr.BaseRepoID, PullID: pr.ID, Environment: "review", URL: "https://pr-2-new.example.com", CreatorID: 2}
+	isNew, err = CreateOrUpdateDeployment(redeployed)
+	assert.NoError(t, err)
+	assert.False(t, isNew)
+	assert.EqualValues(t, d.ID, redeployed.ID)
+
+	loaded, err := GetDeploymentByID(pr.BaseRepoID, d.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://pr-2-new.example.com", loaded.URL)
+
+	_, err = GetDeploymentByID(pr.BaseRepoID+1, d.ID)
+	assert.True(t, IsErrDeploymentNotExist(err))
+
+	_, err = CreateOrUpdateDeployment(&Deployment{RepoID: pr.BaseRepoID, PullID: pr.ID, Environment: "docs", URL: "https://docs-2.example.com", CreatorID: 2})
+	assert.NoError(t, err)
+
+	opts := FindDeploymentsOptions{RepoID: pr.BaseRepoID, PullID: pr.ID, OnlyActive: true}
+	deployments, err := FindDeployments(opts)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 2)
+	count, err := CountDeployments(opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+}
+
+func TestTearDownDeployments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	d := &Deployment{RepoID: pr.BaseRepoID, PullID: pr.ID, Environment: "review", URL: "https://pr-2.example.com", CreatorID: 2}
+	_, err := CreateOrUpdateDeployment(d)
+	assert.NoError(t, err)
+
+	tornDown, err := TearDownDeployments(pr)
+	assert.NoError(t, err)
+	if assert.Len(t, tornDown, 1) {
+		assert.EqualValues(t, d.ID, tornDown[0].ID)
+		assert.False(t, tornDown[0].IsActive)
+	}
+	loaded, err := GetDeploymentByID(pr.BaseRepoID, d.ID)
+	assert.NoError(t, err)
+	assert.False(t, loaded.IsActive)
+
+	// environments which are already torn down are not returned again
+	tornDown, err = TearDownDeployments(pr)
+	assert.NoError(t, err)
+	assert.Empty(t, tornDown)
+
+	assert.NoError(t, DeleteDeployment(pr.BaseRepoID, d.ID))
+	assert.True(t, IsErrDeploymentNotExist(DeleteDeployment(pr.BaseRepoID, d.ID)))
+}
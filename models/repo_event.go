@@ -0,0 +1,79 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(RepoEvent))
+}
+
+// RepoEvent represents a recent event of a repository with its webhook payload,
+// kept to be replayed into webhooks when debugging integrations
+type RepoEvent struct {
+	ID             int64 `xorm:"pk autoincr"`
+	RepoID         int64 `xorm:"INDEX"`
+	EventType      HookEventType
+	PayloadType    string
+	PayloadContent string             `xorm:"LONGTEXT"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// CreateRepoEvent records an event of a repository
+func CreateRepoEvent(e *RepoEvent) error {
+	_, err := x.Insert(e)
+	return err
+}
+
+// GetRepoEventByID returns the event of a repository by its ID
+func GetRepoEventByID(repoID, id int64) (*RepoEvent, error) {
+	e := &RepoEvent{ID: id, RepoID: repoID}
+	has, err := x.Get(e)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoEventNotExist{RepoID: repoID, ID: id}
+	}
+	return e, nil
+}
+
+// FindRepoEvents returns the events of a repository, most recent first
+func FindRepoEvents(repoID int64, listOptions ListOptions) ([]*RepoEvent, error) {
+	sess := x.Where("repo_id=?", repoID).Desc("id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	events := make([]*RepoEvent, 0, listOptions.PageSize)
+	return events, sess.Find(&events)
+}
+
+// CountRepoEvents returns the number of events of a repository
+func CountRepoEvents(repoID int64) (int64, error) {
+	return x.Where("repo_id=?", repoID).Count(new(RepoEvent))
+}
+
+// DeleteOldRepoEvents deletes the events of all repositories recorded more than olderThan ago
+func DeleteOldRepoEvents(ctx context.Context, olderThan time.Duration) error {
+	log.Trace("Doing: DeleteOldRepoEvents")
+
+	select {
+	case <-ctx.Done():
+		return ErrCancelledf("before deleting old repository events")
+	default:
+	}
+	deleted, err := x.Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(new(RepoEvent))
+	if err != nil {
+		return err
+	}
+
+	log.Trace("Finished: DeleteOldRepoEvents: %d events deleted", deleted)
+	return nil
+}
@@ -28,11 +28,12 @@ type GenerateRepoOptions struct {
 	Webhooks    bool
 	Avatar      bool
 	IssueLabels bool
+	Units       bool
 }
 
 // IsValid checks whether at least one option is chosen for generation
 func (gro GenerateRepoOptions) IsValid() bool {
-	return gro.GitContent || gro.Topics || gro.GitHooks || gro.Webhooks || gro.Avatar || gro.IssueLabels // or other items as they are added
+	return gro.GitContent || gro.Topics || gro.GitHooks || gro.Webhooks || gro.Avatar || gro.IssueLabels || gro.Units // or other items as they are added
 }
 
 // GiteaTemplate holds information about a .gitea/template file
@@ -167,3 +168,29 @@ func GenerateIssueLabels(ctx DBContext, templateRepo, generateRepo *Repository)
 	}
 	return nil
 }
+
+// GenerateUnits replaces the units of the generated repository with the units and their settings of a template repository
+func GenerateUnits(ctx DBContext, templateRepo, generateRepo *Repository) error {
+	templateUnits, err := getUnitsByRepoID(ctx.e, templateRepo.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ctx.e.Delete(&RepoUnit{RepoID: generateRepo.ID}); err != nil {
+		return err
+	}
+	units := make([]RepoUnit, 0, len(templateUnits))
+	for _, templateUnit := range templateUnits {
+		units = append(units, RepoUnit{
+			RepoID: generateRepo.ID,
+			Type:   templateUnit.Type,
+			Config: templateUnit.Config,
+		})
+	}
+	generateRepo.Units = nil
+	if len(units) == 0 {
+		return nil
+	}
+	_, err = ctx.e.Insert(&units)
+	return err
+}
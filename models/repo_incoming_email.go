@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// replyTokenSignatureLength is the number of hex characters of the signature of a reply token,
+// kept short as the token has to fit in the local part of an email address
+const replyTokenSignatureLength = 20
+
+func replyTokenSignature(repoID, userID, issueIndex int64) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "incoming-reply:%d:%d:%d", repoID, userID, issueIndex)
+	return hex.EncodeToString(mac.Sum(nil))[:replyTokenSignatureLength]
+}
+
+// IncomingReplyAddress returns the address user can reply to to comment on the issue of the repository,
+// its reply token identifies the user whatever address the reply is sent from.
+// It is empty if incoming email is disabled.
+func (repo *Repository) IncomingReplyAddress(user *User, issueIndex int64) string {
+	address := repo.IncomingEmailAddress()
+	if address == "" {
+		return ""
+	}
+	at := strings.LastIndex(address, "@")
+	return fmt.Sprintf("%s+%d.%d.%s%s", address[:at], user.ID, issueIndex, replyTokenSignature(repo.ID, user.ID, issueIndex), address[at:])
+}
+
+// ParseIncomingReplyToken returns the user and the issue index of a reply token of the repository,
+// ok is false if the token was not generated by IncomingReplyAddress
+func (repo *Repository) ParseIncomingReplyToken(token string) (userID, issueIndex int64, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	userID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	issueIndex, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !hmac.Equal([]byte(strings.ToLower(parts[2])), []byte(replyTokenSignature(repo.ID, userID, issueIndex))) {
+		return 0, 0, false
+	}
+	return userID, issueIndex, true
+}
+
+// IncomingSubmissionAddress returns the personal address user can send new issues and patches to for the repository,
+// the reply address of no issue. It is empty if incoming email is disabled.
+func (repo *Repository) IncomingSubmissionAddress(user *User) string {
+	return repo.IncomingReplyAddress(user, 0)
+}
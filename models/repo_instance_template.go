@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(InstanceTemplate))
+}
+
+// InstanceTemplate represents a template repository listed in the template catalog of the instance
+type InstanceTemplate struct {
+	ID          int64       `xorm:"pk autoincr"`
+	RepoID      int64       `xorm:"UNIQUE NOT NULL"`
+	Repo        *Repository `xorm:"-"`
+	Category    string      `xorm:"INDEX NOT NULL"`
+	Description string      `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrInstanceTemplateNotExist represents a "InstanceTemplateNotExist" kind of error.
+type ErrInstanceTemplateNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrInstanceTemplateNotExist checks if an error is a ErrInstanceTemplateNotExist.
+func IsErrInstanceTemplateNotExist(err error) bool {
+	_, ok := err.(ErrInstanceTemplateNotExist)
+	return ok
+}
+
+func (err ErrInstanceTemplateNotExist) Error() string {
+	return fmt.Sprintf("instance template does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// LoadRepo loads the template repository
+func (t *InstanceTemplate) LoadRepo() (err error) {
+	if t.Repo != nil {
+		return nil
+	}
+	t.Repo, err = GetRepositoryByID(t.RepoID)
+	return err
+}
+
+// GetInstanceTemplateByRepoID returns the catalog entry of the template repository
+func GetInstanceTemplateByRepoID(repoID int64) (*InstanceTemplate, error) {
+	t := &InstanceTemplate{RepoID: repoID}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrInstanceTemplateNotExist{RepoID: repoID}
+	}
+	return t, nil
+}
+
+// GetInstanceTemplates returns the template catalog sorted by category, with the repositories loaded
+func GetInstanceTemplates() ([]*InstanceTemplate, error) {
+	templates := make([]*InstanceTemplate, 0, 10)
+	if err := x.Asc("category", "id").Find(&templates); err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if err := t.LoadRepo(); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// CountInstanceTemplates returns the number of templates in the catalog
+func CountInstanceTemplates() (int64, error) {
+	return x.Count(new(InstanceTemplate))
+}
+
+// SaveInstanceTemplate adds the repository to the template catalog or updates its entry,
+// the repository is marked as template
+func SaveInstanceTemplate(t *InstanceTemplate) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if t.ID == 0 {
+		if _, err := sess.Insert(t); err != nil {
+			return err
+		}
+	} else if _, err := sess.ID(t.ID).Cols("category", "description").Update(t); err != nil {
+		return err
+	}
+	if _, err := sess.ID(t.RepoID).Cols("is_template").Update(&Repository{IsTemplate: true}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteInstanceTemplate removes the entry from the template catalog, the repository stays a template
+func DeleteInstanceTemplate(id int64) error {
+	_, err := x.ID(id).Delete(new(InstanceTemplate))
+	return err
+}
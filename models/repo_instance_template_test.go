@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceTemplates(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, SaveInstanceTemplate(&InstanceTemplate{RepoID: 1, Category: "Go", Description: "A Go project"}))
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.True(t, repo.IsTemplate)
+
+	tmpl, err := GetInstanceTemplateByRepoID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Go", tmpl.Category)
+
+	tmpl.Description = "A Go module"
+	assert.NoError(t, SaveInstanceTemplate(tmpl))
+	assert.NoError(t, SaveInstanceTemplate(&InstanceTemplate{RepoID: 2, Category: "Docs"}))
+
+	templates, err := GetInstanceTemplates()
+	assert.NoError(t, err)
+	if assert.Len(t, templates, 2) {
+		assert.Equal(t, "Docs", templates[0].Category)
+		assert.Equal(t, "A Go module", templates[1].Description)
+		assert.Equal(t, repo.Name, templates[1].Repo.Name)
+	}
+
+	assert.NoError(t, DeleteInstanceTemplate(tmpl.ID))
+	_, err = GetInstanceTemplateByRepoID(1)
+	assert.True(t, IsErrInstanceTemplateNotExist(err))
+	count, err := CountInstanceTemplates()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestGenerateUnits(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	templateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	generateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	templateUnits, err := getUnitsByRepoID(x, templateRepo.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, WithTx(func(ctx DBContext) error {
+		return GenerateUnits(ctx, templateRepo, generateRepo)
+	}))
+
+	generateUnits, err := getUnitsByRepoID(x, generateRepo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, generateUnits, len(templateUnits)) {
+		for i, unit := range generateUnits {
+			assert.Equal(t, templateUnits[i].Type, unit.Type)
+		}
+	}
+}
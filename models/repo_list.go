@@ -143,6 +143,7 @@ type SearchRepoOptions struct {
 	OrderBy         SearchOrderBy
 	Private         bool // Include private repositories in results
 	StarredByID     int64
+	StarListID      int64
 	WatchedByID     int64
 	AllPublic       bool // Include also all public repositories of users and public organisations
 	AllLimited      bool // Include also all public repositories of limited organisations
@@ -166,6 +167,10 @@ type SearchRepoOptions struct {
 	// True -> include just mirrors
 	// False -> include just non-mirrors
 	Mirror util.OptionalBool
+	// None -> include mirrors regardless of their last synchronization
+	// True -> include just mirrors whose last synchronization failed
+	// False -> include just mirrors whose last synchronization succeeded
+	MirrorFailing util.OptionalBool
 	// None -> include archived AND non-archived
 	// True -> include just archived
 	// False -> include just non-archived
@@ -207,6 +212,27 @@ const (
 	SearchOrderByForksReverse          SearchOrderBy = "num_forks DESC"
 )
 
+// splitTopicQualifiers separates the topic:<name> qualifiers of a search keyword from the rest of the keyword
+func splitTopicQualifiers(keyword string) (string, []string) {
+	if !strings.Contains(keyword, "topic:") {
+		return keyword, nil
+	}
+
+	var topics []string
+	fields := strings.Fields(keyword)
+	rest := fields[:0]
+	for _, field := range fields {
+		if strings.HasPrefix(field, "topic:") {
+			if topic := strings.ToLower(strings.TrimPrefix(field, "topic:")); topic != "" {
+				topics = append(topics, topic)
+			}
+			continue
+		}
+		rest = append(rest, field)
+	}
+	return strings.Join(rest, " "), topics
+}
+
 // SearchRepositoryCondition creates a query condition according search repository options
 func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 	cond := builder.NewCond()
@@ -242,6 +268,11 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.In("id", builder.Select("repo_id").From("star").Where(builder.Eq{"uid": opts.StarredByID})))
 	}
 
+	// Restrict to repositories of a star list
+	if opts.StarListID > 0 {
+		cond = cond.And(builder.In("id", builder.Select("repo_id").From("star_list_repo").Where(builder.Eq{"star_list_id": opts.StarListID})))
+	}
+
 	// Restrict to watched repositories
 	if opts.WatchedByID > 0 {
 		cond = cond.And(builder.In("id", builder.Select("repo_id").From("watch").Where(builder.Eq{"user_id": opts.WatchedByID})))
@@ -306,10 +337,17 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.In("`repository`.id", builder.Select("`team_repo`.repo_id").From("team_repo").Where(builder.Eq{"`team_repo`.team_id": opts.TeamID})))
 	}
 
-	if opts.Keyword != "" {
+	keyword, topics := splitTopicQualifiers(opts.Keyword)
+	for _, topic := range topics {
+		cond = cond.And(builder.In("id", builder.Select("repo_topic.repo_id").From("repo_topic").
+			Join("INNER", "topic", "topic.id = repo_topic.topic_id").
+			Where(builder.Eq{"topic.name": topic})))
+	}
+
+	if keyword != "" {
 		// separate keyword
 		subQueryCond := builder.NewCond()
-		for _, v := range strings.Split(opts.Keyword, ",") {
+		for _, v := range strings.Split(keyword, ",") {
 			if opts.TopicOnly {
 				subQueryCond = subQueryCond.Or(builder.Eq{"topic.name": strings.ToLower(v)})
 			} else {
@@ -324,7 +362,7 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		keywordCond := builder.In("id", subQuery)
 		if !opts.TopicOnly {
 			likes := builder.NewCond()
-			for _, v := range strings.Split(opts.Keyword, ",") {
+			for _, v := range strings.Split(keyword, ",") {
 				likes = likes.Or(builder.Like{"lower_name", strings.ToLower(v)})
 				if opts.IncludeDescription {
 					likes = likes.Or(builder.Like{"LOWER(description)", strings.ToLower(v)})
@@ -343,6 +381,15 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.Eq{"is_mirror": opts.Mirror == util.OptionalBoolTrue})
 	}
 
+	if opts.MirrorFailing != util.OptionalBoolNone {
+		failingMirrors := builder.Select("repo_id").From("mirror").Where(builder.Neq{"last_error": ""})
+		if opts.MirrorFailing == util.OptionalBoolTrue {
+			cond = cond.And(builder.In("id", failingMirrors))
+		} else {
+			cond = cond.And(builder.Eq{"is_mirror": true}, builder.NotIn("id", failingMirrors))
+		}
+	}
+
 	if opts.Actor != nil && opts.Actor.IsRestricted {
 		cond = cond.And(accessibleRepositoryCondition(opts.Actor))
 	}
@@ -322,6 +322,16 @@ func TestSearchRepository(t *testing.T) {
 	}
 }
 
+func TestSplitTopicQualifiers(t *testing.T) {
+	keyword, topics := splitTopicQualifiers("gitea")
+	assert.Equal(t, "gitea", keyword)
+	assert.Empty(t, topics)
+
+	keyword, topics = splitTopicQualifiers("topic:Go gitea topic: topic:web")
+	assert.Equal(t, "gitea", keyword)
+	assert.Equal(t, []string{"go", "web"}, topics)
+}
+
 func TestSearchRepositoryByTopicName(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -345,6 +355,21 @@ func TestSearchRepositoryByTopicName(t *testing.T) {
 			opts:  &SearchRepoOptions{OwnerID: 21, AllPublic: true, Keyword: "graphql,golang", TopicOnly: true},
 			count: 2,
 		},
+		{
+			name:  "AllPublic/SearchPublicRepositoriesWithTopicQualifier",
+			opts:  &SearchRepoOptions{OwnerID: 21, AllPublic: true, Keyword: "topic:golang"},
+			count: 2,
+		},
+		{
+			name:  "AllPublic/SearchPublicRepositoriesWithTopicQualifiers",
+			opts:  &SearchRepoOptions{OwnerID: 21, AllPublic: true, Keyword: "topic:golang topic:GraphQL"},
+			count: 1,
+		},
+		{
+			name:  "AllPublic/SearchPublicRepositoriesWithTopicQualifierAndName",
+			opts:  &SearchRepoOptions{OwnerID: 21, AllPublic: true, Keyword: "repo1 topic:golang"},
+			count: 1,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -355,3 +380,28 @@ func TestSearchRepositoryByTopicName(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchRepositoryByMirrorFailing(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := x.Insert(&Mirror{RepoID: 25, LastError: "fatal: repository not found"}, &Mirror{RepoID: 26})
+	assert.NoError(t, err)
+	defer func() {
+		_, err := x.In("repo_id", 25, 26).Delete(new(Mirror))
+		assert.NoError(t, err)
+	}()
+
+	repos, count, err := SearchRepository(&SearchRepoOptions{OwnerID: 20, Private: true, Collaborate: util.OptionalBoolFalse, MirrorFailing: util.OptionalBoolTrue})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 25, repos[0].ID)
+	}
+
+	repos, count, err = SearchRepository(&SearchRepoOptions{OwnerID: 20, Private: true, Collaborate: util.OptionalBoolFalse, MirrorFailing: util.OptionalBoolFalse})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 26, repos[0].ID)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/xorm"
 )
@@ -34,6 +36,19 @@ type Mirror struct {
 	LFS         bool   `xorm:"lfs_enabled NOT NULL DEFAULT false"`
 	LFSEndpoint string `xorm:"lfs_endpoint TEXT"`
 
+	// LastError is the error of the last synchronization, it is empty if the synchronization succeeded
+	LastError string `xorm:"TEXT"`
+	// LastErrorUnix is the time of the last failed synchronization
+	LastErrorUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// FailureCount is the number of synchronizations which failed in a row
+	FailureCount int `xorm:"NOT NULL DEFAULT 0"`
+
+	// MaxBandwidth limits the bandwidth of the synchronizations in bytes per second, 0 if unlimited
+	MaxBandwidth int64 `xorm:"NOT NULL DEFAULT 0"`
+	// SyncWindows restricts the scheduled synchronizations to these times of day,
+	// the global windows apply if it is empty
+	SyncWindows string `xorm:"TEXT"`
+
 	Address string `xorm:"-"`
 }
 
@@ -68,6 +83,24 @@ func (m *Mirror) GetRemoteName() string {
 	return "origin"
 }
 
+// GetSyncWindows returns the times of day the mirror may be synchronized at by the scheduler
+func (m *Mirror) GetSyncWindows() []util.TimeWindow {
+	if m.SyncWindows == "" {
+		return setting.Mirror.SyncWindows
+	}
+	windows, err := util.ParseTimeWindows(m.SyncWindows)
+	if err != nil {
+		log.Error("Invalid sync windows of mirror %d: %v", m.ID, err)
+		return setting.Mirror.SyncWindows
+	}
+	return windows
+}
+
+// InSyncWindow returns if the mirror may be synchronized by the scheduler at the given time
+func (m *Mirror) InSyncWindow(t time.Time) bool {
+	return util.InTimeWindows(m.GetSyncWindows(), t)
+}
+
 // ScheduleNextUpdate calculates and sets next update time.
 func (m *Mirror) ScheduleNextUpdate() {
 	if m.Interval != 0 {
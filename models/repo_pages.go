@@ -0,0 +1,240 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(RepoPages))
+}
+
+// PagesStatus represents the state of the last deployment of a pages site
+type PagesStatus int
+
+const (
+	// PagesStatusNone the site has never been deployed
+	PagesStatusNone PagesStatus = iota
+	// PagesStatusQueued a deployment is waiting in the queue
+	PagesStatusQueued
+	// PagesStatusDeployed the last deployment succeeded
+	PagesStatusDeployed
+	// PagesStatusFailed the last deployment failed, see the message
+	PagesStatusFailed
+)
+
+var pagesStatusNames = map[PagesStatus]string{
+	PagesStatusNone:     "none",
+	PagesStatusQueued:   "queued",
+	PagesStatusDeployed: "deployed",
+	PagesStatusFailed:   "failed",
+}
+
+// String returns the name of the status
+func (s PagesStatus) String() string {
+	return pagesStatusNames[s]
+}
+
+// RepoPages represents the static site of a repository, deployed from a branch.
+// It is only served on its custom domain once a DNS record proved the domain belongs to the repository admins.
+type RepoPages struct {
+	ID     int64       `xorm:"pk autoincr"`
+	RepoID int64       `xorm:"UNIQUE NOT NULL"`
+	Repo   *Repository `xorm:"-"`
+	Branch string      `xorm:"NOT NULL"`
+	// Folder is the folder of the branch holding the site, empty for its root
+	Folder         string
+	Domain         string      `xorm:"INDEX"`
+	DomainVerified bool        `xorm:"NOT NULL DEFAULT false"`
+	Status         PagesStatus `xorm:"NOT NULL DEFAULT 0"`
+	CommitID       string      `xorm:"VARCHAR(40)"`
+	Size           int64       `xorm:"NOT NULL DEFAULT 0"`
+	Message        string      `xorm:"TEXT"`
+	DeployedUnix   timeutil.TimeStamp
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrRepoPagesNotExist represents a "RepoPagesNotExist" kind of error.
+type ErrRepoPagesNotExist struct {
+	RepoID int64
+	Domain string
+}
+
+// IsErrRepoPagesNotExist checks if an error is a ErrRepoPagesNotExist.
+func IsErrRepoPagesNotExist(err error) bool {
+	_, ok := err.(ErrRepoPagesNotExist)
+	return ok
+}
+
+func (err ErrRepoPagesNotExist) Error() string {
+	return fmt.Sprintf("pages do not exist [repo_id: %d, domain: %s]", err.RepoID, err.Domain)
+}
+
+// ErrPagesDomainInUse represents a "PagesDomainInUse" kind of error.
+type ErrPagesDomainInUse struct {
+	Domain string
+}
+
+// IsErrPagesDomainInUse checks if an error is a ErrPagesDomainInUse.
+func IsErrPagesDomainInUse(err error) bool {
+	_, ok := err.(ErrPagesDomainInUse)
+	return ok
+}
+
+func (err ErrPagesDomainInUse) Error() string {
+	return fmt.Sprintf("pages domain is already in use [domain: %s]", err.Domain)
+}
+
+// RepoPagesPath returns the directory the site of the repository is deployed to
+func RepoPagesPath(repoID int64) string {
+	return filepath.Join(setting.Pages.Path, strconv.FormatInt(repoID, 10))
+}
+
+// SitePath returns the directory the site is deployed to
+func (p *RepoPages) SitePath() string {
+	return RepoPagesPath(p.RepoID)
+}
+
+// LoadRepo loads the repository of the site
+func (p *RepoPages) LoadRepo() (err error) {
+	if p.Repo != nil {
+		return nil
+	}
+	p.Repo, err = GetRepositoryByID(p.RepoID)
+	return err
+}
+
+// DomainVerificationRecord returns the name of the DNS TXT record proving the custom domain belongs to the repository admins
+func (p *RepoPages) DomainVerificationRecord() string {
+	return "_gitea-pages." + p.Domain
+}
+
+// DomainVerificationValue returns the value of the DNS TXT record proving the custom domain belongs to the repository admins
+func (p *RepoPages) DomainVerificationValue() string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "pages-domain:%d:%s", p.RepoID, p.Domain)
+	return "gitea-pages-verification=" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// IsDeployed returns true if a deployment of the site succeeded
+func (p *RepoPages) IsDeployed() bool {
+	return p.CommitID != ""
+}
+
+func getRepoPages(e Engine, p *RepoPages) (*RepoPages, error) {
+	has, err := e.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPagesNotExist{RepoID: p.RepoID, Domain: p.Domain}
+	}
+	return p, nil
+}
+
+// GetRepoPages returns the site of the repository
+func GetRepoPages(repoID int64) (*RepoPages, error) {
+	return getRepoPages(x, &RepoPages{RepoID: repoID})
+}
+
+// GetRepoPagesByDomain returns the site served on the given custom domain, which has to be verified
+func GetRepoPagesByDomain(domain string) (*RepoPages, error) {
+	domain = strings.ToLower(domain)
+	if domain == "" {
+		return nil, ErrRepoPagesNotExist{Domain: domain}
+	}
+	p := new(RepoPages)
+	has, err := x.Where("domain = ? AND domain_verified = ?", domain, true).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPagesNotExist{Domain: domain}
+	}
+	return p, nil
+}
+
+// SaveRepoPages enables the site of a repository or updates its settings.
+// A new custom domain has to be verified before the site is served on it.
+func SaveRepoPages(p *RepoPages) error {
+	p.Domain = strings.ToLower(p.Domain)
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if p.Domain != "" {
+		// unverified claims of the domain by other sites do not prevent claiming it
+		has, err := sess.Where("domain = ? AND domain_verified = ? AND repo_id <> ?", p.Domain, true, p.RepoID).Exist(new(RepoPages))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrPagesDomainInUse{p.Domain}
+		}
+	}
+
+	if p.ID == 0 {
+		p.DomainVerified = false
+		if _, err := sess.Insert(p); err != nil {
+			return err
+		}
+	} else {
+		old := new(RepoPages)
+		if _, err := sess.ID(p.ID).Cols("domain").Get(old); err != nil {
+			return err
+		}
+		if old.Domain != p.Domain {
+			p.DomainVerified = false
+		}
+		if _, err := sess.ID(p.ID).Cols("branch", "folder", "domain", "domain_verified").Update(p); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// VerifyRepoPagesDomain marks the custom domain of the site as verified,
+// the claims of the domain by other sites are not verified anymore
+func VerifyRepoPagesDomain(p *RepoPages) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Where("domain = ? AND repo_id <> ?", p.Domain, p.RepoID).
+		Cols("domain_verified").Update(&RepoPages{DomainVerified: false}); err != nil {
+		return err
+	}
+	p.DomainVerified = true
+	if _, err := sess.ID(p.ID).Cols("domain_verified").Update(p); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// UpdateRepoPagesStatus updates the status of the last deployment of the site
+func UpdateRepoPagesStatus(p *RepoPages) error {
+	_, err := x.ID(p.ID).Cols("status", "commit_id", "size", "message", "deployed_unix").Update(p)
+	return err
+}
+
+// DeleteRepoPages deletes the record of the site of a repository, the deployed files have to be removed by the caller
+func DeleteRepoPages(repoID int64) error {
+	_, err := x.Delete(&RepoPages{RepoID: repoID})
+	return err
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveRepoPages(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	p := &RepoPages{RepoID: 1, Branch: "gh-pages", Domain: "Docs.Example.com"}
+	assert.NoError(t, SaveRepoPages(p))
+	AssertExistsAndLoadBean(t, &RepoPages{RepoID: 1, Domain: "docs.example.com"})
+
+	// the domain is only served once it is verified
+	_, err := GetRepoPagesByDomain("docs.example.com")
+	assert.True(t, IsErrRepoPagesNotExist(err))
+	assert.NoError(t, VerifyRepoPagesDomain(p))
+	found, err := GetRepoPagesByDomain("DOCS.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, p.ID, found.ID)
+	_, err = GetRepoPagesByDomain("")
+	assert.True(t, IsErrRepoPagesNotExist(err))
+
+	err = SaveRepoPages(&RepoPages{RepoID: 2, Branch: "gh-pages", Domain: "docs.example.com"})
+	assert.True(t, IsErrPagesDomainInUse(err))
+
+	p.Branch = "master"
+	assert.NoError(t, SaveRepoPages(p))
+	AssertExistsAndLoadBean(t, &RepoPages{RepoID: 1, Branch: "master", DomainVerified: true})
+
+	// changing the domain requires a new verification
+	p.Domain = "www.example.com"
+	assert.NoError(t, SaveRepoPages(p))
+	assert.False(t, p.DomainVerified)
+	_, err = GetRepoPagesByDomain("www.example.com")
+	assert.True(t, IsErrRepoPagesNotExist(err))
+
+	// an unverified claim does not prevent another site from claiming and verifying the domain
+	other := &RepoPages{RepoID: 2, Branch: "gh-pages", Domain: "www.example.com"}
+	assert.NoError(t, SaveRepoPages(other))
+	assert.NoError(t, VerifyRepoPagesDomain(other))
+	found, err = GetRepoPagesByDomain("www.example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, found.RepoID)
+	assert.NotEqual(t, p.DomainVerificationValue(), other.DomainVerificationValue())
+
+	assert.NoError(t, DeleteRepoPages(1))
+	_, err = GetRepoPages(1)
+	assert.True(t, IsErrRepoPagesNotExist(err))
+}
@@ -96,6 +96,12 @@ func (p *Permission) CanWriteIssuesOrPulls(isPull bool) bool {
 	return p.CanWrite(UnitTypeIssues)
 }
 
+// CanPushForPullRequest returns true if user could push to refs/for/<branch> to create AGit pull requests,
+// which only needs read access to the code and the pull requests
+func (p *Permission) CanPushForPullRequest() bool {
+	return p.CanRead(UnitTypeCode) && p.CanRead(UnitTypePullRequests)
+}
+
 // ColorFormat writes a colored string for these Permissions
 func (p *Permission) ColorFormat(s fmt.State) {
 	noColor := log.ColorBytes(log.Reset)
@@ -201,6 +207,24 @@ func getUserRepoPermission(e Engine, repo *Repository, user *User) (perm Permiss
 		return
 	}
 
+	// Members of an organization requiring two-factor authentication who are not enrolled in it
+	// only have the access of other users
+	if repo.Owner.IsOrganization() && repo.Owner.RequireTwoFactor {
+		var hasTwoFactor bool
+		if hasTwoFactor, err = hasTwoFactorByUID(e, user.ID); err != nil {
+			return
+		}
+		if !hasTwoFactor {
+			if repo.IsPrivate || user.IsRestricted || repo.Owner.Visibility.IsPrivate() {
+				perm.AccessMode = AccessModeNone
+				perm.Units = nil
+			} else {
+				perm.AccessMode = AccessModeRead
+			}
+			return
+		}
+	}
+
 	// plain user
 	perm.AccessMode, err = accessLevel(e, user, repo)
 	if err != nil {
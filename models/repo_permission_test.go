@@ -255,3 +255,37 @@ func TestRepoPermissionPrivateOrgRepo(t *testing.T) {
 		assert.True(t, perm.CanWrite(unit.Type))
 	}
 }
+
+func TestRepoPermissionOrgRequiringTwoFactor(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	org.RequireTwoFactor = true
+	assert.NoError(t, UpdateUserCols(org, "require_two_factor"))
+
+	// owner of the organization without two-factor authentication
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	privateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	perm, err := GetUserRepoPermission(privateRepo, user)
+	assert.NoError(t, err)
+	assert.False(t, perm.HasAccess())
+
+	publicRepo := AssertExistsAndLoadBean(t, &Repository{ID: 32}).(*Repository)
+	assert.NoError(t, publicRepo.getUnits(x))
+	perm, err = GetUserRepoPermission(publicRepo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanRead(UnitTypeCode))
+	assert.False(t, perm.CanWrite(UnitTypeCode))
+
+	// site admin
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	perm, err = GetUserRepoPermission(privateRepo, admin)
+	assert.NoError(t, err)
+	assert.True(t, perm.IsOwner())
+
+	// access is restored once enrolled
+	assert.NoError(t, NewTwoFactor(&TwoFactor{UID: user.ID}))
+	perm, err = GetUserRepoPermission(privateRepo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.IsOwner())
+}
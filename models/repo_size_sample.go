@@ -0,0 +1,219 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables, new(RepoSizeSample))
+}
+
+// repoSizeSampleBatchSize is the number of repositories sampled at once
+const repoSizeSampleBatchSize = 100
+
+// RepoSizeSample represents the size of a repository, including its LFS objects, when the disk usage was sampled
+type RepoSizeSample struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL"`
+	Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+	SampledUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+// DiskUsagePoint represents the total size of repositories when the disk usage was sampled
+type DiskUsagePoint struct {
+	SampledUnix timeutil.TimeStamp
+	Size        int64
+}
+
+// OwnerDiskUsage represents the total size of the repositories of an owner
+type OwnerDiskUsage struct {
+	OwnerID  int64
+	Owner    *User `xorm:"-"`
+	Size     int64
+	NumRepos int64
+}
+
+// SampleRepoSizes records the current size of every repository as sampled at the passed time
+func SampleRepoSizes(ctx context.Context, sampledUnix timeutil.TimeStamp) error {
+	var lastID int64
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		repos := make([]*Repository, 0, repoSizeSampleBatchSize)
+		if err := x.Cols("id", "owner_id", "size").
+			Where("id > ?", lastID).
+			Asc("id").
+			Limit(repoSizeSampleBatchSize).
+			Find(&repos); err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			return nil
+		}
+
+		samples := make([]*RepoSizeSample, 0, len(repos))
+		for _, repo := range repos {
+			samples = append(samples, &RepoSizeSample{
+				RepoID:      repo.ID,
+				OwnerID:     repo.OwnerID,
+				Size:        repo.Size,
+				SampledUnix: sampledUnix,
+			})
+		}
+		if _, err := x.Insert(&samples); err != nil {
+			return err
+		}
+		lastID = repos[len(repos)-1].ID
+	}
+}
+
+// GetLastRepoSizeSampleTime returns the time the disk usage was last sampled, 0 if it has never been sampled
+func GetLastRepoSizeSampleTime() (timeutil.TimeStamp, error) {
+	sample := new(RepoSizeSample)
+	has, err := x.Desc("sampled_unix").Get(sample)
+	if err != nil || !has {
+		return 0, err
+	}
+	return sample.SampledUnix, nil
+}
+
+// GetFirstRepoSizeSampleTimeSince returns the time the disk usage was first sampled at or after since,
+// 0 if it has not been sampled since then
+func GetFirstRepoSizeSampleTimeSince(since timeutil.TimeStamp) (timeutil.TimeStamp, error) {
+	sample := new(RepoSizeSample)
+	has, err := x.Where("sampled_unix >= ?", since).Asc("sampled_unix").Get(sample)
+	if err != nil || !has {
+		return 0, err
+	}
+	return sample.SampledUnix, nil
+}
+
+// GetSampledOwnerSizes returns the total size of the repositories of each owner sampled at the passed time by owner ID
+func GetSampledOwnerSizes(sampledUnix timeutil.TimeStamp) (map[int64]int64, error) {
+	return getSampledSizes("owner_id", builder.Eq{"sampled_unix": sampledUnix})
+}
+
+// GetSampledRepoSizes returns the size of the repositories sampled at the passed time by repository ID
+func GetSampledRepoSizes(sampledUnix timeutil.TimeStamp, repoIDs []int64) (map[int64]int64, error) {
+	return getSampledSizes("repo_id", builder.Eq{"sampled_unix": sampledUnix}.And(builder.In("repo_id", repoIDs)))
+}
+
+func getSampledSizes(groupBy string, cond builder.Cond) (map[int64]int64, error) {
+	results, err := x.Table("repo_size_sample").
+		Select(groupBy + " AS id, SUM(size) AS size").
+		Where(cond).
+		GroupBy(groupBy).
+		QueryString()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[int64]int64, len(results))
+	for _, result := range results {
+		var id, size int64
+		if _, err := fmt.Sscan(result["id"], &id); err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Sscan(result["size"], &size); err != nil {
+			return nil, err
+		}
+		sizes[id] = size
+	}
+	return sizes, nil
+}
+
+// GetDiskUsageTrend returns the total size of the repositories of the owner, of all the repositories if 0,
+// each time the disk usage was sampled since the passed time
+func GetDiskUsageTrend(ownerID int64, since timeutil.TimeStamp) ([]*DiskUsagePoint, error) {
+	cond := builder.NewCond().And(builder.Gte{"sampled_unix": since})
+	if ownerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": ownerID})
+	}
+
+	points := make([]*DiskUsagePoint, 0, 10)
+	return points, x.Table("repo_size_sample").
+		Select("sampled_unix, SUM(size) AS size").
+		Where(cond).
+		GroupBy("sampled_unix").
+		Asc("sampled_unix").
+		Find(&points)
+}
+
+// GetTopOwnersByDiskUsage returns the owners whose repositories use the most disk space
+func GetTopOwnersByDiskUsage(limit int) ([]*OwnerDiskUsage, error) {
+	usages := make([]*OwnerDiskUsage, 0, limit)
+	if err := x.Table("repository").
+		Select("owner_id, SUM(size) AS size, COUNT(*) AS num_repos").
+		GroupBy("owner_id").
+		OrderBy("SUM(size) DESC").
+		Limit(limit).
+		Find(&usages); err != nil {
+		return nil, err
+	}
+
+	ownerIDs := make([]int64, 0, len(usages))
+	for _, usage := range usages {
+		ownerIDs = append(ownerIDs, usage.OwnerID)
+	}
+	owners, err := GetUsersByIDs(ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+	ownerMap := make(map[int64]*User, len(owners))
+	for _, owner := range owners {
+		ownerMap[owner.ID] = owner
+	}
+
+	// Skip the repositories left by deleted owners
+	filtered := usages[:0]
+	for _, usage := range usages {
+		if usage.Owner = ownerMap[usage.OwnerID]; usage.Owner != nil {
+			filtered = append(filtered, usage)
+		}
+	}
+	return filtered, nil
+}
+
+// GetTopReposBySize returns the repositories of the owner, of all owners if 0, using the most disk space
+func GetTopReposBySize(ownerID int64, limit int) (RepositoryList, error) {
+	sess := x.Desc("size").Limit(limit)
+	if ownerID > 0 {
+		sess.Where("owner_id = ?", ownerID)
+	}
+	repos := make(RepositoryList, 0, limit)
+	if err := sess.Find(&repos); err != nil {
+		return nil, err
+	}
+	return repos, repos.LoadAttributes()
+}
+
+// DeleteOldRepoSizeSamples deletes the samples of the disk usage older than the passed duration
+func DeleteOldRepoSizeSamples(ctx context.Context, olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("Aborted due to shutdown")
+	default:
+	}
+
+	_, err := x.Where("sampled_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(new(RepoSizeSample))
+	return err
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoSizeSamples(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	setSize := func(repoID, size int64) {
+		_, err := x.ID(repoID).Cols("size").Update(&Repository{Size: size})
+		assert.NoError(t, err)
+	}
+	setSize(1, 1000)
+	setSize(2, 500)
+	setSize(3, 3000)
+
+	last, err := GetLastRepoSizeSampleTime()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, last)
+
+	assert.NoError(t, SampleRepoSizes(context.Background(), 100))
+	setSize(1, 1500)
+	assert.NoError(t, SampleRepoSizes(context.Background(), 200))
+
+	last, err = GetLastRepoSizeSampleTime()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, last)
+	first, err := GetFirstRepoSizeSampleTimeSince(150)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, first)
+
+	ownerSizes, err := GetSampledOwnerSizes(100)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1500, ownerSizes[2])
+	assert.EqualValues(t, 3000, ownerSizes[3])
+
+	repoSizes, err := GetSampledRepoSizes(200, []int64{1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 1500, 3: 3000}, repoSizes)
+
+	trend, err := GetDiskUsageTrend(2, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, trend, 2) {
+		assert.EqualValues(t, 100, trend[0].SampledUnix)
+		assert.EqualValues(t, 1500, trend[0].Size)
+		assert.EqualValues(t, 2000, trend[1].Size)
+	}
+	trend, err = GetDiskUsageTrend(0, 150)
+	assert.NoError(t, err)
+	if assert.Len(t, trend, 1) {
+		assert.EqualValues(t, 5000, trend[0].Size)
+	}
+
+	owners, err := GetTopOwnersByDiskUsage(2)
+	assert.NoError(t, err)
+	if assert.Len(t, owners, 2) {
+		assert.EqualValues(t, 3, owners[0].Owner.ID)
+		assert.EqualValues(t, 3000, owners[0].Size)
+		assert.EqualValues(t, 2, owners[1].Owner.ID)
+		assert.EqualValues(t, 2000, owners[1].Size)
+	}
+
+	repos, err := GetTopReposBySize(2, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	assert.NoError(t, DeleteOldRepoSizeSamples(context.Background(), time.Hour))
+	AssertCount(t, &RepoSizeSample{}, 0)
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables, new(RepoSnapshot))
+}
+
+// RepoSnapshot represents the scheduled snapshots of a branch of a repository, tagged as <TagPrefix><YYYYMMDD>
+type RepoSnapshot struct {
+	ID        int64       `xorm:"pk autoincr"`
+	RepoID    int64       `xorm:"UNIQUE NOT NULL"`
+	Repo      *Repository `xorm:"-"`
+	Branch    string      `xorm:"NOT NULL"`
+	TagPrefix string      `xorm:"NOT NULL"`
+	// Retention is the number of snapshots kept, 0 keeps all of them
+	Retention        int  `xorm:"NOT NULL DEFAULT 0"`
+	GenerateArchives bool `xorm:"NOT NULL DEFAULT false"`
+	LastTagName      string
+	LastSnapshotUnix timeutil.TimeStamp
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrRepoSnapshotNotExist represents a "RepoSnapshotNotExist" kind of error.
+type ErrRepoSnapshotNotExist struct {
+	RepoID int64
+}
+
+// IsErrRepoSnapshotNotExist checks if an error is a ErrRepoSnapshotNotExist.
+func IsErrRepoSnapshotNotExist(err error) bool {
+	_, ok := err.(ErrRepoSnapshotNotExist)
+	return ok
+}
+
+func (err ErrRepoSnapshotNotExist) Error() string {
+	return fmt.Sprintf("repository snapshots do not exist [repo_id: %d]", err.RepoID)
+}
+
+// LoadRepo loads the repository of the snapshots
+func (s *RepoSnapshot) LoadRepo() (err error) {
+	if s.Repo != nil {
+		return nil
+	}
+	s.Repo, err = GetRepositoryByID(s.RepoID)
+	return err
+}
+
+// ProtectedTagPattern returns the pattern of the protected tag rule keeping the snapshot tags immutable
+func (s *RepoSnapshot) ProtectedTagPattern() string {
+	return s.TagPrefix + "*"
+}
+
+// GetReleases returns the tags of the repository starting with the tag prefix, the newest first
+func (s *RepoSnapshot) GetReleases() ([]*Release, error) {
+	rels := make([]*Release, 0, 10)
+	return rels, x.
+		Where("repo_id = ? AND is_tag = ?", s.RepoID, true).
+		And("lower_tag_name LIKE ?", strings.ToLower(s.TagPrefix)+"%").
+		Desc("lower_tag_name").
+		Find(&rels)
+}
+
+// GetRepoSnapshot returns the snapshot settings of the repository
+func GetRepoSnapshot(repoID int64) (*RepoSnapshot, error) {
+	s := &RepoSnapshot{RepoID: repoID}
+	has, err := x.Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoSnapshotNotExist{RepoID: repoID}
+	}
+	return s, nil
+}
+
+// GetRepoSnapshots returns the snapshot settings of all repositories
+func GetRepoSnapshots() ([]*RepoSnapshot, error) {
+	snapshots := make([]*RepoSnapshot, 0, 10)
+	return snapshots, x.Asc("id").Find(&snapshots)
+}
+
+// SaveRepoSnapshot enables the snapshots of a repository or updates their settings
+func SaveRepoSnapshot(s *RepoSnapshot) error {
+	if s.ID == 0 {
+		_, err := x.Insert(s)
+		return err
+	}
+	_, err := x.ID(s.ID).Cols("branch", "tag_prefix", "retention", "generate_archives").Update(s)
+	return err
+}
+
+// UpdateRepoSnapshotLast updates the last snapshot taken
+func UpdateRepoSnapshotLast(s *RepoSnapshot) error {
+	_, err := x.ID(s.ID).Cols("last_tag_name", "last_snapshot_unix").Update(s)
+	return err
+}
+
+// DeleteRepoSnapshot deletes the snapshot settings of a repository, the snapshot tags are kept
+func DeleteRepoSnapshot(repoID int64) error {
+	_, err := x.Delete(&RepoSnapshot{RepoID: repoID})
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/modules/markup"
@@ -55,6 +56,11 @@ func TestMetas(t *testing.T) {
 	externalTracker.ExternalTrackerConfig().ExternalTrackerStyle = markup.IssueNameStyleNumeric
 	testSuccess(markup.IssueNameStyleNumeric)
 
+	externalTracker.ExternalTrackerConfig().ExternalTrackerStyle = markup.IssueNameStyleRegexp
+	externalTracker.ExternalTrackerConfig().ExternalTrackerRegexpPattern = `JIRA-(\d+)`
+	testSuccess(markup.IssueNameStyleRegexp)
+	assert.Equal(t, `JIRA-(\d+)`, repo.ComposeMetas()["regexp"])
+
 	repo, err := GetRepositoryByID(3)
 	assert.NoError(t, err)
 
@@ -133,6 +139,30 @@ func TestGetUserFork(t *testing.T) {
 	assert.Nil(t, repo)
 }
 
+func TestConvertForkToNormalRepository(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// Repo 11 has an open pull request into repo 10
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 11}).(*Repository)
+	repo.IsFork = true
+	assert.NoError(t, UpdateRepositoryCols(repo, "is_fork"))
+	err := ConvertForkToNormalRepository(repo)
+	assert.True(t, IsErrForkHasOpenPullRequests(err))
+	AssertExistsAndLoadBean(t, &Repository{ID: 11, IsFork: true, ForkID: 10})
+
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 29}).(*Repository)
+	assert.NoError(t, ConvertForkToNormalRepository(repo))
+	assert.False(t, repo.IsFork)
+	assert.EqualValues(t, 0, repo.ForkID)
+	AssertExistsAndLoadBean(t, &Repository{ID: 29}, Cond("is_fork = ? AND fork_id = ?", false, 0))
+	parent := AssertExistsAndLoadBean(t, &Repository{ID: 27}).(*Repository)
+	assert.EqualValues(t, 0, parent.NumForks)
+
+	// Converting again does not change the fork count of the former parent
+	assert.NoError(t, ConvertForkToNormalRepository(repo))
+	CheckConsistencyFor(t, &Repository{ID: 27})
+}
+
 func TestRepoAPIURL(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	repo := AssertExistsAndLoadBean(t, &Repository{ID: 10}).(*Repository)
@@ -185,6 +215,15 @@ func TestDeleteAvatar(t *testing.T) {
 	assert.Equal(t, "", repo.Avatar)
 }
 
+func TestGenerateAvatar(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 10}).(*Repository)
+
+	assert.NoError(t, repo.GenerateAvatar())
+	assert.True(t, strings.HasPrefix(repo.Avatar, "10-"))
+	AssertExistsAndLoadBean(t, &Repository{ID: 10, Avatar: repo.Avatar})
+}
+
 func TestDoctorUserStarNum(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -6,6 +6,8 @@ package models
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"code.gitea.io/gitea/modules/timeutil"
 
@@ -37,6 +39,31 @@ func (cfg *UnitConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// CodeConfig describes code unit config
+type CodeConfig struct {
+	// ReadmePath is the file or directory rendered on the repository home page instead of the detected README
+	ReadmePath string
+	// ShowWikiHome renders the wiki home page on the repository home page
+	ShowWikiHome bool
+}
+
+// CleanHomeReadmePath normalizes a path to be rendered on the repository home page,
+// an empty result means the README is detected automatically
+func CleanHomeReadmePath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.TrimSpace(p)), "/")
+}
+
+// FromDB fills up a CodeConfig from serialized format.
+func (cfg *CodeConfig) FromDB(bs []byte) error {
+	return jsonUnmarshalIgnoreErroneousBOM(bs, &cfg)
+}
+
+// ToDB exports a CodeConfig to a serialized format.
+func (cfg *CodeConfig) ToDB() ([]byte, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.Marshal(cfg)
+}
+
 // ExternalWikiConfig describes external wiki config
 type ExternalWikiConfig struct {
 	ExternalWikiURL string
@@ -58,6 +85,9 @@ type ExternalTrackerConfig struct {
 	ExternalTrackerURL    string
 	ExternalTrackerFormat string
 	ExternalTrackerStyle  string
+	// ExternalTrackerRegexpPattern matches the references to the external tracker of the regexp style,
+	// its first group is the issue index
+	ExternalTrackerRegexpPattern string
 }
 
 // FromDB fills up a ExternalTrackerConfig from serialized format.
@@ -76,6 +106,7 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	EnableVoting                     bool
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -100,6 +131,7 @@ type PullRequestsConfig struct {
 	AutodetectManualMerge         bool
 	DefaultDeleteBranchAfterMerge bool
 	DefaultMergeStyle             MergeStyle
+	WelcomeFirstTimeContributors  bool
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -154,7 +186,9 @@ func (r *RepoUnit) BeforeSet(colName string, val xorm.Cell) {
 	switch colName {
 	case "type":
 		switch UnitType(Cell2Int64(val)) {
-		case UnitTypeCode, UnitTypeReleases, UnitTypeWiki, UnitTypeProjects:
+		case UnitTypeCode:
+			r.Config = new(CodeConfig)
+		case UnitTypeReleases, UnitTypeWiki, UnitTypeProjects:
 			r.Config = new(UnitConfig)
 		case UnitTypeExternalWiki:
 			r.Config = new(ExternalWikiConfig)
@@ -176,8 +210,8 @@ func (r *RepoUnit) Unit() Unit {
 }
 
 // CodeConfig returns config for UnitTypeCode
-func (r *RepoUnit) CodeConfig() *UnitConfig {
-	return r.Config.(*UnitConfig)
+func (r *RepoUnit) CodeConfig() *CodeConfig {
+	return r.Config.(*CodeConfig)
 }
 
 // PullRequestsConfig returns config for UnitTypePullRequests
@@ -205,6 +239,12 @@ func (r *RepoUnit) ExternalTrackerConfig() *ExternalTrackerConfig {
 	return r.Config.(*ExternalTrackerConfig)
 }
 
+// UpdateRepoUnit updates the config of a repository unit
+func UpdateRepoUnit(unit *RepoUnit) error {
+	_, err := x.ID(unit.ID).Cols("config").Update(unit)
+	return err
+}
+
 func getUnitsByRepoID(e Engine, repoID int64) (units []*RepoUnit, err error) {
 	var tmpUnits []*RepoUnit
 	if err := e.Where("repo_id = ?", repoID).Find(&tmpUnits); err != nil {
@@ -0,0 +1,40 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanHomeReadmePath(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":                "",
+		"/":               "",
+		" docs/ ":         "docs",
+		"docs/index.md":   "docs/index.md",
+		"../../README.md": "README.md",
+		"./a/../b.md":     "b.md",
+	} {
+		assert.Equal(t, expected, CleanHomeReadmePath(input), "input: %q", input)
+	}
+}
+
+func TestUpdateRepoUnit(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	unit, err := repo.GetUnit(UnitTypeCode)
+	assert.NoError(t, err)
+	unit.CodeConfig().ReadmePath = "docs"
+	unit.CodeConfig().ShowWikiHome = true
+	assert.NoError(t, UpdateRepoUnit(unit))
+
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	unit, err = repo.GetUnit(UnitTypeCode)
+	assert.NoError(t, err)
+	assert.Equal(t, &CodeConfig{ReadmePath: "docs", ShowWikiHome: true}, unit.CodeConfig())
+}
@@ -49,6 +49,12 @@ func (rt ReviewType) Icon() string {
 	}
 }
 
+// ReviewChecklistItem represents an item of the review checklist of a repository and whether the reviewer checked it
+type ReviewChecklistItem struct {
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
 // Review represents collection of code comments giving feedback for a PR
 type Review struct {
 	ID               int64 `xorm:"pk autoincr"`
@@ -67,6 +73,8 @@ type Review struct {
 	CommitID  string `xorm:"VARCHAR(40)"`
 	Stale     bool   `xorm:"NOT NULL DEFAULT false"`
 	Dismissed bool   `xorm:"NOT NULL DEFAULT false"`
+	// Checklist is the review checklist of the repository as filled in by the reviewer
+	Checklist []*ReviewChecklistItem `xorm:"JSON TEXT"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -218,6 +226,7 @@ type CreateReviewOptions struct {
 	Official     bool
 	CommitID     string
 	Stale        bool
+	Checklist    []*ReviewChecklistItem
 }
 
 // IsOfficialReviewer check if at least one of the provided reviewers can make official reviews in issue (counts towards required approvals)
@@ -282,6 +291,7 @@ func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
 		Official:     opts.Official,
 		CommitID:     opts.CommitID,
 		Stale:        opts.Stale,
+		Checklist:    opts.Checklist,
 	}
 	if opts.Reviewer != nil {
 		review.ReviewerID = opts.Reviewer.ID
@@ -347,7 +357,7 @@ func IsContentEmptyErr(err error) bool {
 }
 
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
-func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool, attachmentUUIDs []string) (*Review, *Comment, error) {
+func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool, checklist []*ReviewChecklistItem, attachmentUUIDs []string) (*Review, *Comment, error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -378,13 +388,14 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 
 		// No current review. Create a new one!
 		if review, err = createReview(sess, CreateReviewOptions{
-			Type:     reviewType,
-			Issue:    issue,
-			Reviewer: doer,
-			Content:  content,
-			Official: official,
-			CommitID: commitID,
-			Stale:    stale,
+			Type:      reviewType,
+			Issue:     issue,
+			Reviewer:  doer,
+			Content:   content,
+			Official:  official,
+			CommitID:  commitID,
+			Stale:     stale,
+			Checklist: checklist,
 		}); err != nil {
 			return nil, nil, err
 		}
@@ -412,8 +423,9 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 		review.Type = reviewType
 		review.CommitID = commitID
 		review.Stale = stale
+		review.Checklist = checklist
 
-		if _, err := sess.ID(review.ID).Cols("content, type, official, commit_id, stale").Update(review); err != nil {
+		if _, err := sess.ID(review.ID).Cols("content, type, official, commit_id, stale, checklist").Update(review); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -0,0 +1,301 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"sort"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables,
+		new(ReviewerStats),
+		new(ReviewStatsCursor),
+	)
+}
+
+// ReviewerStats are the review statistics of a reviewer in a repository,
+// they are updated incrementally by UpdateReviewerStats
+type ReviewerStats struct {
+	ID               int64 `xorm:"pk autoincr"`
+	RepoID           int64 `xorm:"UNIQUE(s) NOT NULL"`
+	ReviewerID       int64 `xorm:"UNIQUE(s) NOT NULL"`
+	Reviews          int64 `xorm:"NOT NULL DEFAULT 0"`
+	Approvals        int64 `xorm:"NOT NULL DEFAULT 0"`
+	ChangesRequested int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Comments is the number of code comments of the reviews
+	Comments int64 `xorm:"NOT NULL DEFAULT 0"`
+	// TurnaroundSum is the total time in seconds between the review requests, or the creation
+	// of the pull requests if the reviewer was not requested, and the first review following them
+	TurnaroundSum   int64 `xorm:"NOT NULL DEFAULT 0"`
+	TurnaroundCount int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ReviewStatsCursor remembers the last review comment counted in the reviewer statistics
+type ReviewStatsCursor struct {
+	ID            int64 `xorm:"pk"`
+	LastCommentID int64 `xorm:"NOT NULL DEFAULT 0"`
+}
+
+const reviewStatsCursorID = 1
+
+// ReviewerSummary sums up the review statistics of a reviewer over one or more repositories
+type ReviewerSummary struct {
+	Reviewer         *User
+	Reviews          int64
+	Approvals        int64
+	ChangesRequested int64
+	Comments         int64
+	TurnaroundSum    int64
+	TurnaroundCount  int64
+}
+
+// AverageTurnaround returns the average time in seconds the reviewer took to review a pull request, 0 if unknown
+func (s *ReviewerSummary) AverageTurnaround() int64 {
+	if s.TurnaroundCount == 0 {
+		return 0
+	}
+	return s.TurnaroundSum / s.TurnaroundCount
+}
+
+// GetReviewerStats returns the review statistics of every reviewer of the repositories, the most active first
+func GetReviewerStats(repoIDs []int64) ([]*ReviewerSummary, error) {
+	if len(repoIDs) == 0 {
+		return []*ReviewerSummary{}, nil
+	}
+
+	type reviewerSums struct {
+		ReviewerID       int64
+		Reviews          int64
+		Approvals        int64
+		ChangesRequested int64
+		Comments         int64
+		TurnaroundSum    int64
+		TurnaroundCount  int64
+	}
+	sums := make([]*reviewerSums, 0, 10)
+	if err := x.Table("reviewer_stats").
+		Where(builder.In("repo_id", repoIDs)).
+		Select("reviewer_id, SUM(reviews) AS reviews, SUM(approvals) AS approvals, SUM(changes_requested) AS changes_requested, " +
+			"SUM(comments) AS comments, SUM(turnaround_sum) AS turnaround_sum, SUM(turnaround_count) AS turnaround_count").
+		GroupBy("reviewer_id").
+		Find(&sums); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(sums))
+	for _, s := range sums {
+		userIDs = append(userIDs, s.ReviewerID)
+	}
+	users, err := GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[int64]*User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	result := make([]*ReviewerSummary, 0, len(sums))
+	for _, s := range sums {
+		u, ok := usersByID[s.ReviewerID]
+		if !ok {
+			continue
+		}
+		result = append(result, &ReviewerSummary{
+			Reviewer:         u,
+			Reviews:          s.Reviews,
+			Approvals:        s.Approvals,
+			ChangesRequested: s.ChangesRequested,
+			Comments:         s.Comments,
+			TurnaroundSum:    s.TurnaroundSum,
+			TurnaroundCount:  s.TurnaroundCount,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Reviews != result[j].Reviews {
+			return result[i].Reviews > result[j].Reviews
+		}
+		return result[i].Reviewer.LowerName < result[j].Reviewer.LowerName
+	})
+	return result, nil
+}
+
+// GetOrgReviewerStats returns the review statistics of the repositories owned by org
+// whose pull requests doer is allowed to read
+func GetOrgReviewerStats(org, doer *User) ([]*ReviewerSummary, error) {
+	repos, _, err := SearchRepository(&SearchRepoOptions{
+		Actor:   doer,
+		OwnerID: org.ID,
+		Private: doer != nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		perm, err := GetUserRepoPermission(repo, doer)
+		if err != nil {
+			return nil, err
+		}
+		if perm.CanRead(UnitTypePullRequests) {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	return GetReviewerStats(repoIDs)
+}
+
+// UpdateReviewerStats counts the reviews submitted since its last run in the reviewer statistics
+func UpdateReviewerStats(ctx context.Context) error {
+	const batchSize = 100
+	for {
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before updating the reviewer statistics")
+		default:
+		}
+
+		count, err := updateReviewerStatsBatch(batchSize)
+		if err != nil {
+			return err
+		}
+		if count < batchSize {
+			return nil
+		}
+	}
+}
+
+// updateReviewerStatsBatch counts the next review comments in the reviewer statistics,
+// it returns the number of comments it went through
+func updateReviewerStatsBatch(limit int) (int, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return 0, err
+	}
+
+	cursor := &ReviewStatsCursor{ID: reviewStatsCursorID}
+	has, err := sess.Get(cursor)
+	if err != nil {
+		return 0, err
+	}
+	if !has {
+		if _, err := sess.Insert(cursor); err != nil {
+			return 0, err
+		}
+	}
+
+	comments := make([]*Comment, 0, limit)
+	if err := sess.Where("id > ? AND type = ?", cursor.LastCommentID, CommentTypeReview).
+		OrderBy("id").Limit(limit).Find(&comments); err != nil {
+		return 0, err
+	}
+	if len(comments) == 0 {
+		return 0, nil
+	}
+
+	for _, c := range comments {
+		if err := countReviewComment(sess, c); err != nil {
+			return 0, err
+		}
+	}
+
+	cursor.LastCommentID = comments[len(comments)-1].ID
+	if _, err := sess.ID(cursor.ID).Cols("last_comment_id").Update(cursor); err != nil {
+		return 0, err
+	}
+	return len(comments), sess.Commit()
+}
+
+// countReviewComment adds the review the comment was made for to the statistics of its reviewer
+func countReviewComment(e Engine, c *Comment) error {
+	review, err := getReviewByID(e, c.ReviewID)
+	if err != nil {
+		if IsErrReviewNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if review.ReviewerID <= 0 {
+		// reviews of migrated pull requests are not tied to a user
+		return nil
+	}
+
+	stats := &ReviewerStats{ReviewerID: review.ReviewerID}
+	switch review.Type {
+	case ReviewTypeApprove:
+		stats.Approvals = 1
+	case ReviewTypeReject:
+		stats.ChangesRequested = 1
+	case ReviewTypeComment:
+	default:
+		return nil
+	}
+
+	issue, err := getIssueByID(e, c.IssueID)
+	if err != nil {
+		if IsErrIssueNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	stats.RepoID = issue.RepoID
+
+	if stats.Comments, err = e.Where("review_id = ? AND type = ?", review.ID, CommentTypeCode).Count(new(Comment)); err != nil {
+		return err
+	}
+
+	// The turnaround is measured from the latest review request of the reviewer, or the creation of the
+	// pull request, and only for the first review following it
+	start := issue.CreatedUnix
+	request := new(Comment)
+	has, err := e.Where("issue_id = ? AND type = ? AND assignee_id = ? AND removed_assignee = ? AND id < ?",
+		issue.ID, CommentTypeReviewRequest, review.ReviewerID, false, c.ID).
+		Desc("id").Get(request)
+	if err != nil {
+		return err
+	}
+	if has {
+		start = request.CreatedUnix
+	}
+	reviewed, err := e.Where("issue_id = ? AND type = ? AND poster_id = ? AND id < ? AND created_unix >= ?",
+		issue.ID, CommentTypeReview, c.PosterID, c.ID, start).Exist(new(Comment))
+	if err != nil {
+		return err
+	}
+	if !reviewed && c.CreatedUnix >= start {
+		stats.TurnaroundSum = int64(c.CreatedUnix - start)
+		stats.TurnaroundCount = 1
+	}
+
+	has, err = e.Where("repo_id = ? AND reviewer_id = ?", stats.RepoID, stats.ReviewerID).Exist(new(ReviewerStats))
+	if err != nil {
+		return err
+	} else if !has {
+		stats.Reviews = 1
+		_, err = e.Insert(stats)
+		return err
+	}
+	_, err = e.Where("repo_id = ? AND reviewer_id = ?", stats.RepoID, stats.ReviewerID).
+		Incr("reviews").
+		Incr("approvals", stats.Approvals).
+		Incr("changes_requested", stats.ChangesRequested).
+		Incr("comments", stats.Comments).
+		Incr("turnaround_sum", stats.TurnaroundSum).
+		Incr("turnaround_count", stats.TurnaroundCount).
+		Update(new(ReviewerStats))
+	if err != nil {
+		log.Error("Unable to update the review statistics of %d in %d: %v", stats.ReviewerID, stats.RepoID, err)
+	}
+	return err
+}
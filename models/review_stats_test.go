@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateReviewerStats(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, issue.LoadRepo())
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+
+	_, _, err := SubmitReview(user4, issue, ReviewTypeApprove, "lgtm", "", false, nil, nil)
+	assert.NoError(t, err)
+	_, _, err = SubmitReview(user5, issue, ReviewTypeReject, "needs work", "", false, nil, nil)
+	assert.NoError(t, err)
+	_, _, err = SubmitReview(user5, issue, ReviewTypeApprove, "better now", "", false, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, UpdateReviewerStats(context.Background()))
+
+	stats, err := GetReviewerStats([]int64{issue.RepoID})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.EqualValues(t, 5, stats[0].Reviewer.ID)
+		assert.EqualValues(t, 2, stats[0].Reviews)
+		assert.EqualValues(t, 1, stats[0].Approvals)
+		assert.EqualValues(t, 1, stats[0].ChangesRequested)
+		// only the first review after the creation of the pull request has a turnaround
+		assert.EqualValues(t, 1, stats[0].TurnaroundCount)
+
+		assert.EqualValues(t, 4, stats[1].Reviewer.ID)
+		assert.EqualValues(t, 1, stats[1].Reviews)
+		assert.EqualValues(t, 1, stats[1].Approvals)
+		assert.EqualValues(t, 0, stats[1].ChangesRequested)
+	}
+
+	// reviews already counted are not counted again
+	assert.NoError(t, UpdateReviewerStats(context.Background()))
+	again, err := GetReviewerStats([]int64{issue.RepoID})
+	assert.NoError(t, err)
+	assert.Equal(t, stats, again)
+
+	stats, err = GetReviewerStats([]int64{2})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 0)
+}
@@ -0,0 +1,361 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	tables = append(tables,
+		new(Snippet),
+		new(SnippetComment),
+	)
+}
+
+// SnippetVisibility defines who can see a snippet
+type SnippetVisibility int
+
+const (
+	// SnippetVisibilityPublic snippets are listed and visible to everyone
+	SnippetVisibilityPublic SnippetVisibility = iota
+	// SnippetVisibilityUnlisted snippets are visible to everyone knowing their link but not listed
+	SnippetVisibilityUnlisted
+	// SnippetVisibilityPrivate snippets are only visible to their owner
+	SnippetVisibilityPrivate
+)
+
+var snippetVisibilityNames = map[SnippetVisibility]string{
+	SnippetVisibilityPublic:   "public",
+	SnippetVisibilityUnlisted: "unlisted",
+	SnippetVisibilityPrivate:  "private",
+}
+
+// String returns the name of the visibility
+func (v SnippetVisibility) String() string {
+	return snippetVisibilityNames[v]
+}
+
+// SnippetVisibilityFromString returns the visibility of the given name
+func SnippetVisibilityFromString(name string) (SnippetVisibility, bool) {
+	for v, n := range snippetVisibilityNames {
+		if n == name {
+			return v, true
+		}
+	}
+	return SnippetVisibilityPublic, false
+}
+
+// Snippet represents a set of files shared by a user, stored in its own git repository
+type Snippet struct {
+	ID          int64             `xorm:"pk autoincr"`
+	UUID        string            `xorm:"uuid UNIQUE"`
+	OwnerID     int64             `xorm:"INDEX NOT NULL"`
+	Owner       *User             `xorm:"-"`
+	Description string            `xorm:"TEXT"`
+	Visibility  SnippetVisibility `xorm:"INDEX NOT NULL DEFAULT 0"`
+	NumComments int               `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// SnippetComment represents a comment on a snippet
+type SnippetComment struct {
+	ID        int64  `xorm:"pk autoincr"`
+	SnippetID int64  `xorm:"INDEX NOT NULL"`
+	PosterID  int64  `xorm:"INDEX NOT NULL"`
+	Poster    *User  `xorm:"-"`
+	Content   string `xorm:"TEXT NOT NULL"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// ErrSnippetNotExist represents a "SnippetNotExist" kind of error.
+type ErrSnippetNotExist struct {
+	ID   int64
+	UUID string
+}
+
+// IsErrSnippetNotExist checks if an error is a ErrSnippetNotExist.
+func IsErrSnippetNotExist(err error) bool {
+	_, ok := err.(ErrSnippetNotExist)
+	return ok
+}
+
+func (err ErrSnippetNotExist) Error() string {
+	return fmt.Sprintf("snippet does not exist [id: %d, uuid: %s]", err.ID, err.UUID)
+}
+
+// ErrSnippetCommentNotExist represents a "SnippetCommentNotExist" kind of error.
+type ErrSnippetCommentNotExist struct {
+	ID int64
+}
+
+// IsErrSnippetCommentNotExist checks if an error is a ErrSnippetCommentNotExist.
+func IsErrSnippetCommentNotExist(err error) bool {
+	_, ok := err.(ErrSnippetCommentNotExist)
+	return ok
+}
+
+func (err ErrSnippetCommentNotExist) Error() string {
+	return fmt.Sprintf("snippet comment does not exist [id: %d]", err.ID)
+}
+
+// SnippetPath returns the path of the git repository of the snippet with given UUID
+func SnippetPath(uuid string) string {
+	return filepath.Join(setting.RepoRootPath, "snippets", uuid[0:1], uuid[1:2], uuid+".git")
+}
+
+// RepoPath returns the path of the git repository of the snippet
+func (s *Snippet) RepoPath() string {
+	return SnippetPath(s.UUID)
+}
+
+// HTMLURL returns the absolute URL of the snippet
+func (s *Snippet) HTMLURL() string {
+	return setting.AppURL + "snippets/" + s.UUID
+}
+
+// Link returns the relative URL of the snippet
+func (s *Snippet) Link() string {
+	return setting.AppSubURL + "/snippets/" + s.UUID
+}
+
+// RawLink returns the relative URL of the raw content of a file of the snippet
+func (s *Snippet) RawLink(filename string) string {
+	return s.Link() + "/raw/" + filename
+}
+
+// LoadOwner loads the owner of the snippet
+func (s *Snippet) LoadOwner() (err error) {
+	if s.Owner != nil {
+		return nil
+	}
+	s.Owner, err = GetUserByID(s.OwnerID)
+	return err
+}
+
+// IsOwnedBy returns true if the user owns the snippet
+func (s *Snippet) IsOwnedBy(user *User) bool {
+	return user != nil && user.ID == s.OwnerID
+}
+
+// CanRead returns true if the user (which may be nil) can see the snippet
+func (s *Snippet) CanRead(user *User) bool {
+	if s.Visibility != SnippetVisibilityPrivate {
+		return true
+	}
+	return user != nil && (user.ID == s.OwnerID || user.IsAdmin)
+}
+
+// CanWrite returns true if the user (which may be nil) can change the snippet
+func (s *Snippet) CanWrite(user *User) bool {
+	return user != nil && (user.ID == s.OwnerID || user.IsAdmin)
+}
+
+// InsertSnippet inserts the record of a snippet whose repository has been initialized
+func InsertSnippet(s *Snippet) error {
+	_, err := x.Insert(s)
+	return err
+}
+
+func getSnippet(e Engine, s *Snippet) (*Snippet, error) {
+	has, err := e.Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSnippetNotExist{ID: s.ID, UUID: s.UUID}
+	}
+	return s, nil
+}
+
+// GetSnippetByID returns the snippet with given ID
+func GetSnippetByID(id int64) (*Snippet, error) {
+	return getSnippet(x, &Snippet{ID: id})
+}
+
+// GetSnippetByUUID returns the snippet with given UUID
+func GetSnippetByUUID(uuid string) (*Snippet, error) {
+	if len(uuid) < 2 {
+		return nil, ErrSnippetNotExist{UUID: uuid}
+	}
+	return getSnippet(x, &Snippet{UUID: uuid})
+}
+
+// SearchSnippetOptions contains the options to search snippets
+type SearchSnippetOptions struct {
+	ListOptions
+	OwnerID int64
+	Actor   *User
+}
+
+func (opts *SearchSnippetOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+
+	// unlisted snippets are only listed to their owners, as are private ones unless to site admins
+	visible := builder.Eq{"visibility": SnippetVisibilityPublic}
+	if opts.Actor != nil {
+		if opts.Actor.IsAdmin {
+			cond = cond.And(builder.Or(visible, builder.Eq{"visibility": SnippetVisibilityPrivate}, builder.Eq{"owner_id": opts.Actor.ID}))
+		} else {
+			cond = cond.And(builder.Or(visible, builder.Eq{"owner_id": opts.Actor.ID}))
+		}
+	} else {
+		cond = cond.And(visible)
+	}
+	return cond
+}
+
+// SearchSnippets returns the snippets matching the options, most recently updated first
+func SearchSnippets(opts *SearchSnippetOptions) ([]*Snippet, int64, error) {
+	cond := opts.toConds()
+	count, err := x.Where(cond).Count(new(Snippet))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(cond).OrderBy("updated_unix DESC")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	snippets := make([]*Snippet, 0, opts.PageSize)
+	if err = sess.Find(&snippets); err != nil {
+		return nil, 0, err
+	}
+	return snippets, count, nil
+}
+
+// UpdateSnippet updates the description and visibility of a snippet
+func UpdateSnippet(s *Snippet) error {
+	_, err := x.ID(s.ID).Cols("description", "visibility").Update(s)
+	return err
+}
+
+// TouchSnippet bumps the updated time of a snippet after its files changed
+func TouchSnippet(s *Snippet) error {
+	_, err := x.ID(s.ID).NoAutoTime().Cols("updated_unix").Update(&Snippet{UpdatedUnix: timeutil.TimeStampNow()})
+	return err
+}
+
+// DeleteSnippet deletes the record of a snippet and its comments, the repository has to be removed by the caller
+func DeleteSnippet(s *Snippet) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := deleteBeans(sess,
+		&SnippetComment{SnippetID: s.ID},
+		&Snippet{ID: s.ID},
+	); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// LoadPoster loads the poster of the comment
+func (c *SnippetComment) LoadPoster() (err error) {
+	if c.Poster != nil {
+		return nil
+	}
+	c.Poster, err = GetUserByID(c.PosterID)
+	if IsErrUserNotExist(err) {
+		c.PosterID = -1
+		c.Poster = NewGhostUser()
+		return nil
+	}
+	return err
+}
+
+// CreateSnippetComment adds a comment to a snippet
+func CreateSnippetComment(s *Snippet, poster *User, content string) (*SnippetComment, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	c := &SnippetComment{
+		SnippetID: s.ID,
+		PosterID:  poster.ID,
+		Poster:    poster,
+		Content:   content,
+	}
+	if _, err := sess.Insert(c); err != nil {
+		return nil, err
+	}
+	if _, err := sess.Exec("UPDATE `snippet` SET num_comments = num_comments + 1 WHERE id = ?", s.ID); err != nil {
+		return nil, err
+	}
+	return c, sess.Commit()
+}
+
+// GetSnippetCommentByID returns the comment of the snippet with given ID
+func GetSnippetCommentByID(snippetID, id int64) (*SnippetComment, error) {
+	c := &SnippetComment{ID: id, SnippetID: snippetID}
+	has, err := x.Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSnippetCommentNotExist{id}
+	}
+	return c, nil
+}
+
+// GetSnippetComments returns the comments of a snippet, oldest first
+func GetSnippetComments(snippetID int64, opts ListOptions) ([]*SnippetComment, error) {
+	sess := x.Where("snippet_id = ?", snippetID).OrderBy("created_unix ASC, id ASC")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	comments := make([]*SnippetComment, 0, 10)
+	return comments, sess.Find(&comments)
+}
+
+// DeleteSnippetComment deletes a comment of a snippet
+func DeleteSnippetComment(c *SnippetComment) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	affected, err := sess.ID(c.ID).Delete(new(SnippetComment))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return nil
+	}
+	if _, err := sess.Exec("UPDATE `snippet` SET num_comments = num_comments - 1 WHERE id = ?", c.SnippetID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// deleteSnippetsByOwnerID deletes the records of all snippets of a deleted user
+// and returns their UUIDs so their repositories can be removed afterwards
+func deleteSnippetsByOwnerID(e Engine, ownerID int64) ([]string, error) {
+	uuids := make([]string, 0, 5)
+	if err := e.Table("snippet").Where("owner_id = ?", ownerID).Cols("uuid").Find(&uuids); err != nil {
+		return nil, err
+	}
+	if _, err := e.Exec("DELETE FROM `snippet_comment` WHERE snippet_id IN (SELECT id FROM `snippet` WHERE owner_id = ?)", ownerID); err != nil {
+		return nil, err
+	}
+	_, err := e.Delete(&Snippet{OwnerID: ownerID})
+	return uuids, err
+}
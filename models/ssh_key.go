@@ -57,6 +57,33 @@ const (
 	KeyTypePrincipal
 )
 
+// KeySource specifies how a public key was added
+type KeySource int
+
+const (
+	// KeySourceUnknown is used for keys added before the source was recorded
+	KeySourceUnknown KeySource = iota
+	// KeySourceWeb specifies a key added through the web interface
+	KeySourceWeb
+	// KeySourceAPI specifies a key added through the API
+	KeySourceAPI
+	// KeySourceImport specifies a key imported from a login source
+	KeySourceImport
+)
+
+// String returns the name of the key source
+func (s KeySource) String() string {
+	switch s {
+	case KeySourceWeb:
+		return "web"
+	case KeySourceAPI:
+		return "api"
+	case KeySourceImport:
+		return "import"
+	}
+	return "unknown"
+}
+
 // PublicKey represents a user or deploy SSH public key.
 type PublicKey struct {
 	ID            int64      `xorm:"pk autoincr"`
@@ -67,9 +94,11 @@ type PublicKey struct {
 	Mode          AccessMode `xorm:"NOT NULL DEFAULT 2"`
 	Type          KeyType    `xorm:"NOT NULL DEFAULT 1"`
 	LoginSourceID int64      `xorm:"NOT NULL DEFAULT 0"`
+	CreatedFrom   KeySource  `xorm:"NOT NULL DEFAULT 0"`
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	ExpiredUnix       timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 }
@@ -80,6 +109,11 @@ func (key *PublicKey) AfterLoad() {
 	key.HasRecentActivity = key.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
 }
 
+// IsExpired returns true if the key has an expiry date which has passed.
+func (key *PublicKey) IsExpired() bool {
+	return key.ExpiredUnix > 0 && key.ExpiredUnix <= timeutil.TimeStampNow()
+}
+
 // OmitEmail returns content of public key without email address.
 func (key *PublicKey) OmitEmail() string {
 	return strings.Join(strings.Split(key.Content, " ")[:2], " ")
@@ -512,7 +546,8 @@ func addKey(e Engine, key *PublicKey) (err error) {
 }
 
 // AddPublicKey adds new public key to database and authorized_keys file.
-func AddPublicKey(ownerID int64, name, content string, loginSourceID int64) (*PublicKey, error) {
+// A zero expiredUnix means the key never expires.
+func AddPublicKey(ownerID int64, name, content string, loginSourceID int64, from KeySource, expiredUnix timeutil.TimeStamp) (*PublicKey, error) {
 	log.Trace(content)
 
 	fingerprint, err := calcFingerprint(content)
@@ -548,6 +583,8 @@ func AddPublicKey(ownerID int64, name, content string, loginSourceID int64) (*Pu
 		Mode:          AccessModeWrite,
 		Type:          KeyTypeUser,
 		LoginSourceID: loginSourceID,
+		CreatedFrom:   from,
+		ExpiredUnix:   expiredUnix,
 	}
 	if err = addKey(sess, key); err != nil {
 		return nil, fmt.Errorf("addKey: %v", err)
@@ -643,6 +680,23 @@ func ListPublicLdapSSHKeys(uid, loginSourceID int64) ([]*PublicKey, error) {
 		Find(&keys)
 }
 
+// ListExpiringPublicKeys returns user public keys whose expiry date lies in the
+// range (after, before], ordered by expiry date. An after of zero includes keys
+// which have already expired.
+func ListExpiringPublicKeys(after, before timeutil.TimeStamp, listOptions ListOptions) ([]*PublicKey, error) {
+	sess := x.Where("type = ? AND expired_unix > ? AND expired_unix <= ?", KeyTypeUser, after, before).
+		Asc("expired_unix", "id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+
+		keys := make([]*PublicKey, 0, listOptions.PageSize)
+		return keys, sess.Find(&keys)
+	}
+
+	keys := make([]*PublicKey, 0, 10)
+	return keys, sess.Find(&keys)
+}
+
 // UpdatePublicKeyUpdated updates public key use time.
 func UpdatePublicKeyUpdated(id int64) error {
 	// Check if key exists before update as affected rows count is unreliable
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -190,3 +191,33 @@ func Test_calcFingerprint(t *testing.T) {
 		})
 	}
 }
+
+func TestPublicKey_IsExpired(t *testing.T) {
+	now := timeutil.TimeStampNow()
+	assert.False(t, (&PublicKey{}).IsExpired())
+	assert.False(t, (&PublicKey{ExpiredUnix: now + 3600}).IsExpired())
+	assert.True(t, (&PublicKey{ExpiredUnix: now - 3600}).IsExpired())
+}
+
+func TestListExpiringPublicKeys(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	now := timeutil.TimeStampNow()
+	keys, err := ListExpiringPublicKeys(0, now+30*24*3600, ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = x.ID(1).Cols("expired_unix").Update(&PublicKey{ExpiredUnix: now + 7*24*3600})
+	assert.NoError(t, err)
+
+	keys, err = ListExpiringPublicKeys(now, now+30*24*3600, ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.EqualValues(t, 1, keys[0].ID)
+		assert.False(t, keys[0].IsExpired())
+	}
+
+	keys, err = ListExpiringPublicKeys(now, now+24*3600, ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
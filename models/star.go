@@ -47,6 +47,9 @@ func StarRepo(userID, repoID int64, star bool) error {
 		if _, err := sess.Delete(&Star{UID: userID, RepoID: repoID}); err != nil {
 			return err
 		}
+		if err := removeRepoFromStarLists(sess, userID, repoID); err != nil {
+			return err
+		}
 		if _, err := sess.Exec("UPDATE `repository` SET num_stars = num_stars - 1 WHERE id = ?", repoID); err != nil {
 			return err
 		}
@@ -0,0 +1,243 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	tables = append(tables,
+		new(StarList),
+		new(StarListRepo),
+	)
+}
+
+// StarList represents a named collection of repositories starred by a user
+type StarList struct {
+	ID          int64  `xorm:"pk autoincr"`
+	UserID      int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string `xorm:"NOT NULL"`
+	LowerName   string `xorm:"UNIQUE(s) NOT NULL"`
+	Description string `xorm:"TEXT"`
+	IsPrivate   bool   `xorm:"NOT NULL DEFAULT false"`
+	NumRepos    int    `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// StarListRepo represents a starred repository put into a star list
+type StarListRepo struct {
+	ID          int64              `xorm:"pk autoincr"`
+	StarListID  int64              `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// ErrStarListNotExist represents a "StarListNotExist" kind of error.
+type ErrStarListNotExist struct {
+	ID int64
+}
+
+// IsErrStarListNotExist checks if an error is a ErrStarListNotExist.
+func IsErrStarListNotExist(err error) bool {
+	_, ok := err.(ErrStarListNotExist)
+	return ok
+}
+
+func (err ErrStarListNotExist) Error() string {
+	return fmt.Sprintf("star list does not exist [id: %d]", err.ID)
+}
+
+// ErrStarListAlreadyExist represents a "StarListAlreadyExist" kind of error.
+type ErrStarListAlreadyExist struct {
+	Name string
+}
+
+// IsErrStarListAlreadyExist checks if an error is a ErrStarListAlreadyExist.
+func IsErrStarListAlreadyExist(err error) bool {
+	_, ok := err.(ErrStarListAlreadyExist)
+	return ok
+}
+
+func (err ErrStarListAlreadyExist) Error() string {
+	return fmt.Sprintf("star list already exists [name: %s]", err.Name)
+}
+
+// CreateStarList creates a new star list for its owner
+func CreateStarList(list *StarList) error {
+	list.LowerName = strings.ToLower(list.Name)
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Exist(&StarList{UserID: list.UserID, LowerName: list.LowerName})
+	if err != nil {
+		return err
+	} else if has {
+		return ErrStarListAlreadyExist{list.Name}
+	}
+
+	list.NumRepos = 0
+	if _, err = sess.Insert(list); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetStarListByID returns the star list with given ID
+func GetStarListByID(id int64) (*StarList, error) {
+	list := new(StarList)
+	has, err := x.ID(id).Get(list)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrStarListNotExist{id}
+	}
+	return list, nil
+}
+
+// GetStarListsByUserID returns the star lists of a user, private ones only if asked for
+func GetStarListsByUserID(userID int64, includePrivate bool) ([]*StarList, error) {
+	sess := x.Where("user_id = ?", userID)
+	if !includePrivate {
+		sess = sess.And("is_private = ?", false)
+	}
+	lists := make([]*StarList, 0, 5)
+	return lists, sess.OrderBy("lower_name ASC").Find(&lists)
+}
+
+// UpdateStarList updates the name, description and visibility of a star list
+func UpdateStarList(list *StarList) error {
+	list.LowerName = strings.ToLower(list.Name)
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Where("user_id = ? AND lower_name = ? AND id <> ?", list.UserID, list.LowerName, list.ID).Exist(new(StarList))
+	if err != nil {
+		return err
+	} else if has {
+		return ErrStarListAlreadyExist{list.Name}
+	}
+
+	if _, err = sess.ID(list.ID).Cols("name", "lower_name", "description", "is_private").Update(list); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteStarList deletes a star list, the repositories in it stay starred
+func DeleteStarList(list *StarList) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := deleteBeans(sess,
+		&StarListRepo{StarListID: list.ID},
+		&StarList{ID: list.ID},
+	); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// AddRepoToStarList puts a repository into a star list, starring it for the list owner first if needed
+func AddRepoToStarList(list *StarList, repoID int64) error {
+	if err := StarRepo(list.UserID, repoID, true); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Exist(&StarListRepo{StarListID: list.ID, RepoID: repoID})
+	if err != nil {
+		return err
+	} else if has {
+		return nil
+	}
+
+	if _, err = sess.Insert(&StarListRepo{StarListID: list.ID, RepoID: repoID}); err != nil {
+		return err
+	}
+	if _, err = sess.Exec("UPDATE `star_list` SET num_repos = num_repos + 1 WHERE id = ?", list.ID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// RemoveRepoFromStarList takes a repository out of a star list, it stays starred
+func RemoveRepoFromStarList(list *StarList, repoID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	affected, err := sess.Delete(&StarListRepo{StarListID: list.ID, RepoID: repoID})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return nil
+	}
+
+	if _, err = sess.Exec("UPDATE `star_list` SET num_repos = num_repos - 1 WHERE id = ?", list.ID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// removeRepoFromStarLists takes a repository out of all star lists of a user
+func removeRepoFromStarLists(e Engine, userID, repoID int64) error {
+	if _, err := e.Exec("UPDATE `star_list` SET num_repos = num_repos - 1 WHERE user_id = ? AND id IN (SELECT star_list_id FROM `star_list_repo` WHERE repo_id = ?)", userID, repoID); err != nil {
+		return err
+	}
+	_, err := e.Exec("DELETE FROM `star_list_repo` WHERE repo_id = ? AND star_list_id IN (SELECT id FROM `star_list` WHERE user_id = ?)", repoID, userID)
+	return err
+}
+
+// deleteStarListsByRepoID takes a deleted repository out of every star list
+func deleteStarListsByRepoID(e Engine, repoID int64) error {
+	if _, err := e.Exec("UPDATE `star_list` SET num_repos = num_repos - 1 WHERE id IN (SELECT star_list_id FROM `star_list_repo` WHERE repo_id = ?)", repoID); err != nil {
+		return err
+	}
+	_, err := e.Delete(&StarListRepo{RepoID: repoID})
+	return err
+}
+
+// deleteStarListsByUserID deletes all star lists of a deleted user
+func deleteStarListsByUserID(e Engine, userID int64) error {
+	if _, err := e.Exec("DELETE FROM `star_list_repo` WHERE star_list_id IN (SELECT id FROM `star_list` WHERE user_id = ?)", userID); err != nil {
+		return err
+	}
+	_, err := e.Delete(&StarList{UserID: userID})
+	return err
+}
+
+// GetStarListIDsByRepo returns the IDs of the lists of a user containing the repository
+func GetStarListIDsByRepo(userID, repoID int64) ([]int64, error) {
+	ids := make([]int64, 0, 2)
+	return ids, x.Table("star_list_repo").
+		Join("INNER", "star_list", "star_list.id = star_list_repo.star_list_id").
+		Where("star_list.user_id = ? AND star_list_repo.repo_id = ?", userID, repoID).
+		Cols("star_list_repo.star_list_id").
+		Find(&ids)
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarList(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	const userID = 2
+	const repoID = 1
+
+	list := &StarList{UserID: userID, Name: "Tools"}
+	assert.NoError(t, CreateStarList(list))
+	assert.True(t, IsErrStarListAlreadyExist(CreateStarList(&StarList{UserID: userID, Name: "tools"})))
+
+	assert.NoError(t, AddRepoToStarList(list, repoID))
+	assert.NoError(t, AddRepoToStarList(list, repoID))
+	assert.True(t, IsStaring(userID, repoID))
+	list = AssertExistsAndLoadBean(t, &StarList{ID: list.ID}).(*StarList)
+	assert.EqualValues(t, 1, list.NumRepos)
+
+	ids, err := GetStarListIDsByRepo(userID, repoID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{list.ID}, ids)
+
+	repos, count, err := SearchRepository(&SearchRepoOptions{StarListID: list.ID, Private: true, ListOptions: ListOptions{Page: 1}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, repoID, repos[0].ID)
+	}
+
+	// unstarring takes the repository out of the list
+	assert.NoError(t, StarRepo(userID, repoID, false))
+	AssertNotExistsBean(t, &StarListRepo{StarListID: list.ID, RepoID: repoID})
+	list = AssertExistsAndLoadBean(t, &StarList{ID: list.ID}).(*StarList)
+	assert.EqualValues(t, 0, list.NumRepos)
+
+	assert.NoError(t, DeleteStarList(list))
+	AssertNotExistsBean(t, &StarList{ID: list.ID})
+}
@@ -7,6 +7,7 @@ package models
 
 import (
 	"crypto/subtle"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
@@ -16,7 +17,49 @@ import (
 	gouuid "github.com/google/uuid"
 )
 
-// AccessToken represents a personal access token.
+// AccessTokenScope is a permission of an organization access token, or of a restricted personal access token
+type AccessTokenScope string
+
+// Scopes of access tokens
+const (
+	// AccessTokenScopeAdminOrg allows to use the administration API of the organization
+	AccessTokenScopeAdminOrg AccessTokenScope = "admin:org"
+	// AccessTokenScopeWritePackage allows to publish and read the packages of the organization
+	AccessTokenScopeWritePackage AccessTokenScope = "write:package"
+	// AccessTokenScopeWriteStatus allows to create and read commit statuses, but not to read the code
+	AccessTokenScopeWriteStatus AccessTokenScope = "write:status"
+)
+
+// AccessTokenScopes are the valid scopes of organization access tokens
+var AccessTokenScopes = []AccessTokenScope{AccessTokenScopeAdminOrg, AccessTokenScopeWritePackage, AccessTokenScopeWriteStatus}
+
+// PersonalAccessTokenScopes are the valid scopes of personal access tokens,
+// personal access tokens without scopes are not restricted
+var PersonalAccessTokenScopes = []AccessTokenScope{AccessTokenScopeWriteStatus}
+
+// IsValidPersonalAccessTokenScope returns true if scope is a scope of personal access tokens
+func IsValidPersonalAccessTokenScope(scope string) bool {
+	for _, s := range PersonalAccessTokenScopes {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAccessTokenScope returns true if scope is a scope of organization access tokens
+func IsValidAccessTokenScope(scope string) bool {
+	for _, s := range AccessTokenScopes {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessToken represents a personal access token, or an access token of an organization.
+// The requests authenticated with an organization token are done by the organization itself,
+// and only within the scopes of the token.
 type AccessToken struct {
 	ID             int64 `xorm:"pk autoincr"`
 	UID            int64 `xorm:"INDEX"`
@@ -25,7 +68,14 @@ type AccessToken struct {
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
 	TokenLastEight string `xorm:"token_last_eight"`
-
+	// CreatorID is the owner who created an organization token, it is 0 for personal tokens
+	CreatorID int64
+	// Scope is the comma separated scopes of the token, it is empty for unrestricted personal tokens
+	Scope string
+	// ExpiresUnix is 0 if the token never expires
+	ExpiresUnix timeutil.TimeStamp
+
+	Creator           *User              `xorm:"-"`
 	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"INDEX updated"`
 	HasRecentActivity bool               `xorm:"-"`
@@ -38,6 +88,57 @@ func (t *AccessToken) AfterLoad() {
 	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
 }
 
+// IsOrgToken returns true if the token is an access token of an organization
+func (t *AccessToken) IsOrgToken() bool {
+	return t.CreatorID > 0
+}
+
+// IsRestricted returns true if the token may only be used within its scopes
+func (t *AccessToken) IsRestricted() bool {
+	return t.IsOrgToken() || t.Scope != ""
+}
+
+// Scopes returns the scopes of the token
+func (t *AccessToken) Scopes() []AccessTokenScope {
+	if t.Scope == "" {
+		return nil
+	}
+	parts := strings.Split(t.Scope, ",")
+	scopes := make([]AccessTokenScope, len(parts))
+	for i := range parts {
+		scopes[i] = AccessTokenScope(parts[i])
+	}
+	return scopes
+}
+
+// HasScope returns true if the token has the scope
+func (t *AccessToken) HasScope(scope AccessTokenScope) bool {
+	for _, s := range t.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the token has expired
+func (t *AccessToken) IsExpired() bool {
+	return t.ExpiresUnix > 0 && t.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// LoadCreator loads the creator of an organization token
+func (t *AccessToken) LoadCreator() (err error) {
+	if t.Creator != nil || t.CreatorID == 0 {
+		return nil
+	}
+	t.Creator, err = GetUserByID(t.CreatorID)
+	if IsErrUserNotExist(err) {
+		t.Creator = NewGhostUser()
+		return nil
+	}
+	return err
+}
+
 // NewAccessToken creates new access token.
 func NewAccessToken(t *AccessToken) error {
 	salt, err := util.RandomString(10)
@@ -52,7 +153,7 @@ func NewAccessToken(t *AccessToken) error {
 	return err
 }
 
-// GetAccessTokenBySHA returns access token by given token value
+// GetAccessTokenBySHA returns access token by given token value, expired tokens do not exist
 func GetAccessTokenBySHA(token string) (*AccessToken, error) {
 	if token == "" {
 		return nil, ErrAccessTokenEmpty{}
@@ -77,6 +178,9 @@ func GetAccessTokenBySHA(token string) (*AccessToken, error) {
 	for _, t := range tokens {
 		tempHash := hashToken(token, t.TokenSalt)
 		if subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(tempHash)) == 1 {
+			if t.IsExpired() {
+				return nil, ErrAccessTokenNotExist{token}
+			}
 			return &t, nil
 		}
 	}
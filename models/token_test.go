@@ -6,6 +6,9 @@ package models
 
 import (
 	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -127,3 +130,39 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, IsErrAccessTokenNotExist(err))
 }
+
+func TestOrgAccessToken(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	token := &AccessToken{
+		UID:       3,
+		CreatorID: 2,
+		Name:      "Org Token",
+		Scope:     "admin:org,write:package",
+	}
+	assert.NoError(t, NewAccessToken(token))
+	assert.True(t, token.IsOrgToken())
+	assert.True(t, token.HasScope(AccessTokenScopeAdminOrg))
+	assert.True(t, token.HasScope(AccessTokenScopeWritePackage))
+	assert.NoError(t, token.LoadCreator())
+	assert.EqualValues(t, 2, token.Creator.ID)
+
+	got, err := GetAccessTokenBySHA(token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, got.ID)
+
+	expired := &AccessToken{
+		UID:         3,
+		CreatorID:   2,
+		Name:        "Expired Org Token",
+		Scope:       "write:package",
+		ExpiresUnix: timeutil.TimeStamp(time.Now().Add(-time.Hour).Unix()),
+	}
+	assert.NoError(t, NewAccessToken(expired))
+	assert.True(t, expired.IsExpired())
+	assert.False(t, expired.HasScope(AccessTokenScopeAdminOrg))
+	_, err = GetAccessTokenBySHA(expired.Token)
+	assert.True(t, IsErrAccessTokenNotExist(err))
+
+	assert.True(t, IsValidAccessTokenScope("admin:org"))
+	assert.False(t, IsValidAccessTokenScope("admin:repo"))
+}
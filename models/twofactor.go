@@ -111,6 +111,35 @@ func GetTwoFactorByUID(uid int64) (*TwoFactor, error) {
 	return twofa, nil
 }
 
+func hasTwoFactorByUID(e Engine, uid int64) (bool, error) {
+	return e.Where("uid=?", uid).Exist(&TwoFactor{})
+}
+
+// HasTwoFactorByUID returns true if the user is enrolled in two-factor authentication.
+func HasTwoFactorByUID(uid int64) (bool, error) {
+	return hasTwoFactorByUID(x, uid)
+}
+
+// GetTwoFactorStatusByUIDs returns whether each of the users is enrolled in two-factor authentication.
+func GetTwoFactorStatusByUIDs(uids []int64) (map[int64]bool, error) {
+	status := make(map[int64]bool, len(uids))
+	if len(uids) == 0 {
+		return status, nil
+	}
+
+	enrolled := make([]int64, 0, len(uids))
+	if err := x.Table("two_factor").In("uid", uids).Cols("uid").Find(&enrolled); err != nil {
+		return nil, err
+	}
+	for _, uid := range uids {
+		status[uid] = false
+	}
+	for _, uid := range enrolled {
+		status[uid] = true
+	}
+	return status, nil
+}
+
 // DeleteTwoFactorByID deletes two-factor authentication token by given ID.
 func DeleteTwoFactorByID(id, userID int64) error {
 	cnt, err := x.ID(id).Delete(&TwoFactor{
@@ -128,6 +128,8 @@ type User struct {
 	LastRepoVisibility bool
 	// Maximum repository creation limit, -1 means use global default
 	MaxRepoCreation int `xorm:"NOT NULL DEFAULT -1"`
+	// Maximum total size of the repositories in MiB, -1 means use global default
+	SizeQuota int64 `xorm:"NOT NULL DEFAULT -1"`
 
 	// Permissions
 	IsActive                bool `xorm:"INDEX"` // Activate primary email
@@ -157,11 +159,17 @@ type User struct {
 	MembersIsPublic           map[int64]bool      `xorm:"-"`
 	Visibility                structs.VisibleType `xorm:"NOT NULL DEFAULT 0"`
 	RepoAdminChangeTeamAccess bool                `xorm:"NOT NULL DEFAULT false"`
+	// RequireTwoFactor restricts the access of the organization members who are not enrolled in two-factor authentication
+	RequireTwoFactor bool `xorm:"NOT NULL DEFAULT false"`
+	// EnforceCLA blocks the pull requests into the repositories of the organization
+	// until all their commit authors have signed the current version of its contributor license agreement
+	EnforceCLA bool `xorm:"NOT NULL DEFAULT false"`
 
 	// Preferences
-	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
-	Theme               string `xorm:"NOT NULL DEFAULT ''"`
-	KeepActivityPrivate bool   `xorm:"NOT NULL DEFAULT false"`
+	DiffViewStyle            string `xorm:"NOT NULL DEFAULT ''"`
+	Theme                    string `xorm:"NOT NULL DEFAULT ''"`
+	KeepActivityPrivate      bool   `xorm:"NOT NULL DEFAULT false"`
+	KeepActivitySignedInOnly bool   `xorm:"NOT NULL DEFAULT false"`
 }
 
 // SearchOrganizationsOptions options to filter organizations
@@ -182,6 +190,9 @@ func (u *User) BeforeUpdate() {
 	if u.MaxRepoCreation < -1 {
 		u.MaxRepoCreation = -1
 	}
+	if u.SizeQuota < -1 {
+		u.SizeQuota = -1
+	}
 
 	// Organization does not need email
 	u.Email = strings.ToLower(u.Email)
@@ -432,6 +443,17 @@ func (u *User) IsPasswordSet() bool {
 	return len(u.Passwd) != 0
 }
 
+// IsActivityVisibleToUser checks if viewer is able to see the activity and heatmap of the user
+func (u *User) IsActivityVisibleToUser(viewer *User) bool {
+	if viewer != nil && (viewer.IsAdmin || viewer.ID == u.ID) {
+		return true
+	}
+	if u.KeepActivityPrivate {
+		return false
+	}
+	return viewer != nil || !u.KeepActivitySignedInOnly
+}
+
 // IsVisibleToUser check if viewer is able to see user profile
 func (u *User) IsVisibleToUser(viewer *User) bool {
 	return u.isVisibleToUser(x, viewer)
@@ -809,6 +831,7 @@ var (
 		"robots.txt",
 		"search",
 		"serviceworker.js",
+		"snippets",
 		"stars",
 		"template",
 		"user",
@@ -894,6 +917,9 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 	} else if isExist {
 		return ErrUserAlreadyExist{u.Name}
 	}
+	if err = checkUserNameReservation(sess, 0, u.Name); err != nil {
+		return err
+	}
 
 	isExist, err = isEmailUsed(sess, u.Email)
 	if err != nil {
@@ -1021,6 +1047,9 @@ func ChangeUserName(u *User, newUserName string) (err error) {
 	} else if isExist {
 		return ErrUserAlreadyExist{newUserName}
 	}
+	if err = checkUserNameReservation(sess, u.ID, newUserName); err != nil {
+		return err
+	}
 
 	if _, err = sess.Exec("UPDATE `repository` SET owner_name=? WHERE owner_name=?", newUserName, oldUserName); err != nil {
 		return fmt.Errorf("Change repo owner name: %v", err)
@@ -1169,6 +1198,17 @@ func deleteUser(e Engine, u *User) error {
 	}
 	// ***** END: Star *****
 
+	// ***** START: Vote *****
+	votedIssueIDs := make([]int64, 0, 10)
+	if err = e.Table("reaction").Cols("reaction.issue_id").
+		Where("reaction.user_id = ? AND reaction.comment_id = 0 AND reaction.original_author_id = 0", u.ID).
+		And("reaction.`type` = ?", VoteReaction).Find(&votedIssueIDs); err != nil {
+		return fmt.Errorf("get all votes: %v", err)
+	} else if _, err = e.Decr("num_votes").In("id", votedIssueIDs).NoAutoTime().Update(new(Issue)); err != nil {
+		return fmt.Errorf("decrease issue num_votes: %v", err)
+	}
+	// ***** END: Vote *****
+
 	// ***** START: Follow *****
 	followeeIDs := make([]int64, 0, 10)
 	if err = e.Table("follow").Cols("follow.follow_id").
@@ -1187,6 +1227,19 @@ func deleteUser(e Engine, u *User) error {
 	}
 	// ***** END: Follow *****
 
+	if err = deleteStarListsByUserID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteStarListsByUserID: %v", err)
+	}
+
+	snippetUUIDs, err := deleteSnippetsByOwnerID(e, u.ID)
+	if err != nil {
+		return fmt.Errorf("deleteSnippetsByOwnerID: %v", err)
+	}
+
+	if err = deletePackagesByOwnerID(e, u.ID); err != nil {
+		return fmt.Errorf("deletePackagesByOwnerID: %v", err)
+	}
+
 	if err = deleteBeans(e,
 		&AccessToken{UID: u.ID},
 		&Collaboration{UserID: u.ID},
@@ -1199,10 +1252,13 @@ func deleteUser(e Engine, u *User) error {
 		&IssueUser{UID: u.ID},
 		&EmailAddress{UID: u.ID},
 		&UserOpenID{UID: u.ID},
+		&PendingRegistration{UserID: u.ID},
 		&Reaction{UserID: u.ID},
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&Webhook{OrgID: u.ID},
+		&RepoAccessRequest{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1288,6 +1344,15 @@ func deleteUser(e Engine, u *User) error {
 		return err
 	}
 
+	for _, uuid := range snippetUUIDs {
+		snippetPath := SnippetPath(uuid)
+		if err = util.RemoveAll(snippetPath); err != nil {
+			err = fmt.Errorf("Failed to RemoveAll %s: %v", snippetPath, err)
+			_ = createNotice(e, NoticeTask, fmt.Sprintf("delete user '%s': %v", u.Name, err))
+			return err
+		}
+	}
+
 	if len(u.Avatar) > 0 {
 		avatarPath := u.CustomAvatarRelativePath()
 		if err = storage.Avatars.Delete(avatarPath); err != nil {
@@ -1597,21 +1662,48 @@ type SearchUserOptions struct {
 	Actor         *User // The user doing the search
 	IsActive      util.OptionalBool
 	SearchByEmail bool // Search by email as well as username/full name
+	IsFuzzy       bool // Match keyword characters in order, allowing gaps between them
+
+	OrgID              int64 // Only return members of this organization
+	OrgPublicOnly      bool  // Only consider public memberships of OrgID
+	CollaboratorRepoID int64 // Only return collaborators of this repository
+}
+
+// fuzzyPattern turns a keyword into a LIKE pattern that matches its
+// characters in order with anything in between, e.g. "usr" -> "u%s%r".
+func fuzzyPattern(keyword string) string {
+	var sb strings.Builder
+	for i, r := range keyword {
+		if r == '%' || r == '_' {
+			continue
+		}
+		if i > 0 {
+			sb.WriteByte('%')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
 }
 
 func (opts *SearchUserOptions) toConds() builder.Cond {
 	var cond builder.Cond = builder.Eq{"type": opts.Type}
 	if len(opts.Keyword) > 0 {
-		lowerKeyword := strings.ToLower(opts.Keyword)
-		keywordCond := builder.Or(
-			builder.Like{"lower_name", lowerKeyword},
-			builder.Like{"LOWER(full_name)", lowerKeyword},
-		)
-		if opts.SearchByEmail {
-			keywordCond = keywordCond.Or(builder.Like{"LOWER(email)", lowerKeyword})
-		}
+		// Every word of the keyword has to match, but each may do so in
+		// a different column, so "john doe" finds "jdoe" named "John Doe".
+		for _, word := range strings.Fields(strings.ToLower(opts.Keyword)) {
+			if opts.IsFuzzy {
+				word = fuzzyPattern(word)
+			}
+			keywordCond := builder.Or(
+				builder.Like{"lower_name", word},
+				builder.Like{"LOWER(full_name)", word},
+			)
+			if opts.SearchByEmail {
+				keywordCond = keywordCond.Or(builder.Like{"LOWER(email)", word})
+			}
 
-		cond = cond.And(keywordCond)
+			cond = cond.And(keywordCond)
+		}
 	}
 
 	// If visibility filtered
@@ -1641,6 +1733,11 @@ func (opts *SearchUserOptions) toConds() builder.Cond {
 				// restricted users only see orgs they are a member of
 				accessCond = builder.In("id", builder.Select("org_id").From("org_user").LeftJoin("`user`", exprCond).Where(builder.And(builder.Eq{"uid": opts.Actor.ID})))
 			}
+			// Members of a shared organization can see each other if the membership is public,
+			// a hidden membership must not be revealed by the search
+			accessCond = accessCond.Or(builder.In("id", builder.Select("uid").From("org_user").Where(builder.And(
+				builder.Eq{"is_public": true},
+				builder.In("org_id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": opts.Actor.ID}))))))
 			// Don't forget about self
 			accessCond = accessCond.Or(builder.Eq{"id": opts.Actor.ID})
 			cond = cond.And(accessCond)
@@ -1649,13 +1746,25 @@ func (opts *SearchUserOptions) toConds() builder.Cond {
 	} else {
 		// Force visibility for privacy
 		// Not logged in - only public users
-		cond = cond.And(builder.In("visibility", structs.VisibleTypePublic))
+		cond = cond.And(builder.Eq{"visibility": structs.VisibleTypePublic})
 	}
 
 	if opts.UID > 0 {
 		cond = cond.And(builder.Eq{"id": opts.UID})
 	}
 
+	if opts.OrgID != 0 {
+		memberCond := builder.Eq{"org_id": opts.OrgID}
+		if opts.OrgPublicOnly {
+			memberCond["is_public"] = true
+		}
+		cond = cond.And(builder.In("id", builder.Select("uid").From("org_user").Where(memberCond)))
+	}
+
+	if opts.CollaboratorRepoID != 0 {
+		cond = cond.And(builder.In("id", builder.Select("user_id").From("collaboration").Where(builder.Eq{"repo_id": opts.CollaboratorRepoID})))
+	}
+
 	if !opts.IsActive.IsNone() {
 		cond = cond.And(builder.Eq{"is_active": opts.IsActive.IsTrue()})
 	}
@@ -1775,7 +1884,7 @@ func addLdapSSHPublicKeys(usr *User, s *LoginSource, sshPublicKeys []string) boo
 			marshalled = marshalled[:len(marshalled)-1]
 			sshKeyName := fmt.Sprintf("%s-%s", s.Name, ssh.FingerprintSHA256(out))
 
-			if _, err := AddPublicKey(usr.ID, sshKeyName, marshalled, s.ID); err != nil {
+			if _, err := AddPublicKey(usr.ID, sshKeyName, marshalled, s.ID, KeySourceImport, 0); err != nil {
 				if IsErrKeyAlreadyExist(err) {
 					log.Trace("addLdapSSHPublicKeys[%s]: LDAP Public SSH Key %s already exists for user", sshKeyName, usr.Name)
 				} else {
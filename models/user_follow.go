@@ -5,7 +5,10 @@
 package models
 
 import (
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // Follow represents relations of user and his/her followers.
@@ -22,6 +25,35 @@ func IsFollowing(userID, followID int64) bool {
 	return has
 }
 
+// GetFollowedOrgIDs returns the IDs of the organizations followed by userID which are visible to doer.
+func GetFollowedOrgIDs(userID int64, doer *User) ([]int64, error) {
+	cond := builder.NewCond().And(builder.Eq{
+		"follow.user_id": userID,
+		"`user`.type":    UserTypeOrganization,
+	})
+	// the same as hasOrgOrUserVisible
+	if doer == nil {
+		cond = cond.And(builder.Eq{"`user`.visibility": structs.VisibleTypePublic})
+	} else if !doer.IsAdmin {
+		memberCond := builder.In("`user`.id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": doer.ID}))
+		if doer.IsRestricted {
+			cond = cond.And(memberCond)
+		} else {
+			cond = cond.And(builder.Or(
+				builder.In("`user`.visibility", structs.VisibleTypePublic, structs.VisibleTypeLimited),
+				memberCond,
+			))
+		}
+	}
+
+	orgIDs := make([]int64, 0, 5)
+	return orgIDs, x.Table("follow").
+		Join("INNER", "`user`", "`user`.id = follow.follow_id").
+		Where(cond).
+		Cols("follow.follow_id").
+		Find(&orgIDs)
+}
+
 // FollowUser marks someone be another's follower.
 func FollowUser(userID, followID int64) (err error) {
 	if userID == followID || IsFollowing(userID, followID) {
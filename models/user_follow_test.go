@@ -47,3 +47,23 @@ func TestUnfollowUser(t *testing.T) {
 
 	CheckConsistencyFor(t, &User{})
 }
+
+func TestGetFollowedOrgIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	// organizations 3, 22 and 23 are public, limited and private
+	for _, orgID := range []int64{3, 22, 23} {
+		assert.NoError(t, FollowUser(5, orgID))
+	}
+
+	testSuccess := func(doer *User, expected []int64) {
+		orgIDs, err := GetFollowedOrgIDs(5, doer)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, expected, orgIDs)
+	}
+	testSuccess(nil, []int64{3})
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	testSuccess(user5, []int64{3, 22})
+	testSuccess(AssertExistsAndLoadBean(t, &User{ID: 1}).(*User), []int64{3, 22, 23})
+	user5.IsRestricted = true
+	testSuccess(user5, []int64{})
+}
@@ -5,6 +5,8 @@
 package models
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 )
@@ -15,20 +17,33 @@ type UserHeatmapData struct {
 	Contributions int64              `json:"contributions"`
 }
 
+// UserHeatmapOptions limits the time range of a heatmap
+type UserHeatmapOptions struct {
+	// From is the start of the range, the last year is used if it is not set
+	From timeutil.TimeStamp
+	// To is the end of the range (exclusive), the range is open if it is not set
+	To timeutil.TimeStamp
+}
+
 // GetUserHeatmapDataByUser returns an array of UserHeatmapData
 func GetUserHeatmapDataByUser(user, doer *User) ([]*UserHeatmapData, error) {
-	return getUserHeatmapData(user, nil, doer)
+	return getUserHeatmapData(user, nil, doer, UserHeatmapOptions{})
+}
+
+// GetUserHeatmapDataByUserInRange returns an array of UserHeatmapData of the given time range
+func GetUserHeatmapDataByUserInRange(user, doer *User, opts UserHeatmapOptions) ([]*UserHeatmapData, error) {
+	return getUserHeatmapData(user, nil, doer, opts)
 }
 
 // GetUserHeatmapDataByUserTeam returns an array of UserHeatmapData
 func GetUserHeatmapDataByUserTeam(user *User, team *Team, doer *User) ([]*UserHeatmapData, error) {
-	return getUserHeatmapData(user, team, doer)
+	return getUserHeatmapData(user, team, doer, UserHeatmapOptions{})
 }
 
-func getUserHeatmapData(user *User, team *Team, doer *User) ([]*UserHeatmapData, error) {
+func getUserHeatmapData(user *User, team *Team, doer *User, opts UserHeatmapOptions) ([]*UserHeatmapData, error) {
 	hdata := make([]*UserHeatmapData, 0)
 
-	if !activityReadable(user, doer) {
+	if !user.IsActivityVisibleToUser(doer) {
 		return hdata, nil
 	}
 
@@ -58,12 +73,36 @@ func getUserHeatmapData(user *User, team *Team, doer *User) ([]*UserHeatmapData,
 		return nil, err
 	}
 
-	return hdata, x.
-		Select(groupBy+" AS timestamp, count(user_id) as contributions").
+	sess := x.
+		Select(groupBy + " AS timestamp, count(user_id) as contributions").
 		Table("action").
-		Where(cond).
-		And("created_unix > ?", timeutil.TimeStampNow()-31536000).
+		Where(cond)
+	if opts.From > 0 {
+		sess.And("created_unix >= ?", opts.From)
+	} else {
+		sess.And("created_unix > ?", timeutil.TimeStampNow()-31536000)
+	}
+	if opts.To > 0 {
+		sess.And("created_unix < ?", opts.To)
+	}
+	return hdata, sess.
 		GroupBy(groupByName).
 		OrderBy("timestamp").
 		Find(&hdata)
 }
+
+// GroupUserHeatmapDataByDay sums up the contributions of each day in the given location,
+// the timestamps of the result are the starts of the days. The data must be sorted by timestamp.
+func GroupUserHeatmapDataByDay(data []*UserHeatmapData, loc *time.Location) []*UserHeatmapData {
+	days := make([]*UserHeatmapData, 0, len(data))
+	for _, d := range data {
+		t := d.Timestamp.AsTimeInLocation(loc)
+		day := timeutil.TimeStamp(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Unix())
+		if len(days) > 0 && days[len(days)-1].Timestamp == day {
+			days[len(days)-1].Contributions += d.Contributions
+			continue
+		}
+		days = append(days, &UserHeatmapData{Timestamp: day, Contributions: d.Contributions})
+	}
+	return days
+}
@@ -7,6 +7,7 @@ package models
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -74,3 +75,37 @@ func TestGetUserHeatmapDataByUser(t *testing.T) {
 		assert.Equal(t, tc.JSONResult, string(jsonData))
 	}
 }
+
+func TestGetUserHeatmapDataByUserInRange(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 10}).(*User)
+
+	heatmap, err := GetUserHeatmapDataByUserInRange(user, user, UserHeatmapOptions{From: 1603000000, To: 1603100000})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*UserHeatmapData{
+		{Timestamp: 1603009800, Contributions: 1},
+		{Timestamp: 1603010700, Contributions: 2},
+	}, heatmap)
+
+	heatmap, err = GetUserHeatmapDataByUserInRange(user, user, UserHeatmapOptions{From: 1603000000, To: 1603011300})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*UserHeatmapData{{Timestamp: 1603009800, Contributions: 1}}, heatmap)
+}
+
+func TestGroupUserHeatmapDataByDay(t *testing.T) {
+	data := []*UserHeatmapData{
+		{Timestamp: 1603009800, Contributions: 1}, // 2020-10-18 08:30 UTC
+		{Timestamp: 1603010700, Contributions: 2}, // 2020-10-18 08:45 UTC
+		{Timestamp: 1603067400, Contributions: 4}, // 2020-10-19 00:30 UTC
+	}
+
+	assert.EqualValues(t, []*UserHeatmapData{
+		{Timestamp: 1602979200, Contributions: 3},
+		{Timestamp: 1603065600, Contributions: 4},
+	}, GroupUserHeatmapDataByDay(data, time.UTC))
+
+	// all the contributions are made on 2020-10-18 in UTC-7
+	assert.EqualValues(t, []*UserHeatmapData{
+		{Timestamp: 1603004400, Contributions: 7},
+	}, GroupUserHeatmapDataByDay(data, time.FixedZone("UTC-7", -7*60*60)))
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// SizeQuotaLimit returns the maximum total size in bytes of the repositories of the user or the organization,
+// -1 means no limit. Administrators are not limited.
+func (u *User) SizeQuotaLimit() int64 {
+	if u.IsAdmin {
+		return -1
+	}
+	quota := u.SizeQuota
+	if quota <= -1 {
+		quota = setting.Repository.DefaultSizeQuota
+	}
+	if quota <= -1 {
+		return -1
+	}
+	return quota * 1024 * 1024
+}
+
+// UsedSize returns the total size in bytes of the repositories of the user, including their LFS objects
+func (u *User) UsedSize() (int64, error) {
+	return x.Where("owner_id = ?", u.ID).SumInt(new(Repository), "size")
+}
+
+// RemainingSize returns the size in bytes the repositories of the user can still grow, -1 means no limit
+func (u *User) RemainingSize() (int64, error) {
+	limit := u.SizeQuotaLimit()
+	if limit <= -1 {
+		return -1, nil
+	}
+	used, err := u.UsedSize()
+	if err != nil {
+		return 0, err
+	}
+	if used >= limit {
+		return 0, nil
+	}
+	return limit - used, nil
+}
+
+// CheckSizeQuota returns ErrSizeQuotaExceeded if the repositories of the user can not grow by additional bytes
+func (u *User) CheckSizeQuota(additional int64) error {
+	limit := u.SizeQuotaLimit()
+	if limit <= -1 {
+		return nil
+	}
+	used, err := u.UsedSize()
+	if err != nil {
+		return err
+	}
+	if used+additional > limit {
+		return ErrSizeQuotaExceeded{Quota: limit, Used: used, Additional: additional}
+	}
+	return nil
+}
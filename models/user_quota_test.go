@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSizeQuota(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(quota int64) {
+		setting.Repository.DefaultSizeQuota = quota
+	}(setting.Repository.DefaultSizeQuota)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.EqualValues(t, -1, user.SizeQuotaLimit())
+	assert.NoError(t, user.CheckSizeQuota(1<<40))
+	remaining, err := user.RemainingSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, -1, remaining)
+
+	setting.Repository.DefaultSizeQuota = 1
+	_, err = x.ID(1).Cols("size").Update(&Repository{Size: 1 << 19})
+	assert.NoError(t, err)
+
+	used, err := user.UsedSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1<<19, used)
+	remaining, err = user.RemainingSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1<<19, remaining)
+	assert.NoError(t, user.CheckSizeQuota(1<<19))
+	err = user.CheckSizeQuota(1<<19 + 1)
+	assert.True(t, IsErrSizeQuotaExceeded(err))
+
+	// the quota of the user overrides the global default
+	user.SizeQuota = 2
+	assert.EqualValues(t, 2<<20, user.SizeQuotaLimit())
+	assert.NoError(t, user.CheckSizeQuota(1<<20))
+
+	// administrators are not limited
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	assert.EqualValues(t, -1, admin.SizeQuotaLimit())
+}
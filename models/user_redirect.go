@@ -4,13 +4,20 @@
 
 package models
 
-import "strings"
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
 
 // UserRedirect represents that a user name should be redirected to another
 type UserRedirect struct {
-	ID             int64  `xorm:"pk autoincr"`
-	LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	RedirectUserID int64  // userID to redirect to
+	ID             int64              `xorm:"pk autoincr"`
+	LowerName      string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RedirectUserID int64              `xorm:"INDEX"` // userID to redirect to
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
 }
 
 // LookupUserRedirect look up userID if a user has a redirect name
@@ -50,3 +57,33 @@ func deleteUserRedirect(e Engine, userName string) error {
 	_, err := e.Delete(&UserRedirect{LowerName: userName})
 	return err
 }
+
+// checkUserNameReservation returns ErrNameReserved if userName is the old name of another user
+// renamed less than setting.Service.UsernameReservationPeriod ago
+func checkUserNameReservation(e Engine, userID int64, userName string) error {
+	if setting.Service.UsernameReservationPeriod <= 0 {
+		return nil
+	}
+	redirect := &UserRedirect{LowerName: strings.ToLower(userName)}
+	if has, err := e.Get(redirect); err != nil {
+		return err
+	} else if !has || redirect.RedirectUserID == userID {
+		return nil
+	}
+	if redirect.CreatedUnix.AddDuration(setting.Service.UsernameReservationPeriod) > timeutil.TimeStampNow() {
+		return ErrNameReserved{Name: userName}
+	}
+	return nil
+}
+
+// GetLastUserRenameTime returns the time the user was renamed for the last time,
+// it is zero if the user has never been renamed
+func GetLastUserRenameTime(userID int64) (time.Time, error) {
+	redirect := new(UserRedirect)
+	if has, err := x.Where("redirect_user_id = ?", userID).Desc("created_unix").Get(redirect); err != nil {
+		return time.Time{}, err
+	} else if !has || redirect.CreatedUnix == 0 {
+		return time.Time{}, nil
+	}
+	return redirect.CreatedUnix.AsTime(), nil
+}
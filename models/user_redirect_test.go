@@ -6,6 +6,9 @@ package models
 
 import (
 	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -67,3 +70,37 @@ func TestNewUserRedirect3(t *testing.T) {
 		RedirectUserID: user.ID,
 	})
 }
+
+func TestUserNameReservation(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(period time.Duration) {
+		setting.Service.UsernameReservationPeriod = period
+	}(setting.Service.UsernameReservationPeriod)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	lastRename, err := GetLastUserRenameTime(user.ID)
+	assert.NoError(t, err)
+	assert.True(t, lastRename.IsZero())
+
+	oldName := user.Name
+	assert.NoError(t, ChangeUserName(user, "user2-renamed"))
+	user.Name, user.LowerName = "user2-renamed", "user2-renamed"
+	assert.NoError(t, UpdateUserCols(user, "name", "lower_name"))
+	lastRename, err = GetLastUserRenameTime(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, lastRename.IsZero())
+
+	// the old name can be claimed as soon as it is released without reservation period
+	assert.NoError(t, checkUserNameReservation(x, 4, oldName))
+
+	setting.Service.UsernameReservationPeriod = time.Hour
+	assert.True(t, IsErrNameReserved(checkUserNameReservation(x, 4, oldName)))
+	assert.True(t, IsErrNameReserved(checkUserNameReservation(x, 0, oldName)))
+	// the user can take its old name back
+	assert.NoError(t, checkUserNameReservation(x, user.ID, oldName))
+	// the fixture redirect has no creation time so it is not reserved anymore
+	assert.NoError(t, checkUserNameReservation(x, 4, "olduser1"))
+
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.True(t, IsErrNameReserved(ChangeUserName(user4, oldName)))
+}
@@ -151,6 +151,43 @@ func TestSearchUsers(t *testing.T) {
 	// order by name asc default
 	testUserSuccess(&SearchUserOptions{Keyword: "user1", ListOptions: ListOptions{Page: 1}, IsActive: util.OptionalBoolTrue},
 		[]int64{1, 10, 11, 12, 13, 14, 15, 16, 18})
+
+	testUserSuccess(&SearchUserOptions{Keyword: "usr1", IsFuzzy: true, OrderBy: "id ASC", ListOptions: ListOptions{Page: 1}, IsActive: util.OptionalBoolTrue},
+		[]int64{1, 10, 11, 12, 13, 14, 15, 16, 18, 21})
+
+	testUserSuccess(&SearchUserOptions{Keyword: "user one", OrderBy: "id ASC", ListOptions: ListOptions{Page: 1}},
+		[]int64{1})
+
+	testUserSuccess(&SearchUserOptions{OrgID: 3, OrderBy: "id ASC", ListOptions: ListOptions{Page: 1}},
+		[]int64{2, 4, 28})
+
+	testUserSuccess(&SearchUserOptions{OrgID: 3, OrgPublicOnly: true, OrderBy: "id ASC", ListOptions: ListOptions{Page: 1}},
+		[]int64{2, 28})
+
+	testUserSuccess(&SearchUserOptions{CollaboratorRepoID: 4, OrderBy: "id ASC", ListOptions: ListOptions{Page: 1}},
+		[]int64{4, 29})
+
+	// anonymous searches only find public users and organizations
+	testUserSuccess(&SearchUserOptions{Keyword: "user31", ListOptions: ListOptions{Page: 1}},
+		[]int64{})
+	testOrgSuccess(&SearchUserOptions{Keyword: "_org", ListOptions: ListOptions{Page: 1}},
+		[]int64{})
+
+	// a private user is only found by the members of an organization in which the membership is public
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user31 := AssertExistsAndLoadBean(t, &User{ID: 31}).(*User)
+	testUserSuccess(&SearchUserOptions{Keyword: "user31", Actor: user2, ListOptions: ListOptions{Page: 1}},
+		[]int64{})
+	defer func(visible bool) {
+		setting.Service.DefaultOrgMemberVisible = visible
+	}(setting.Service.DefaultOrgMemberVisible)
+	setting.Service.DefaultOrgMemberVisible = false
+	assert.NoError(t, AddOrgUser(3, user31.ID))
+	testUserSuccess(&SearchUserOptions{Keyword: "user31", Actor: user2, ListOptions: ListOptions{Page: 1}},
+		[]int64{})
+	assert.NoError(t, ChangeOrgUserStatus(3, user31.ID, true))
+	testUserSuccess(&SearchUserOptions{Keyword: "user31", Actor: user2, ListOptions: ListOptions{Page: 1}},
+		[]int64{31})
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -470,6 +507,27 @@ ssh-dss AAAAB3NzaC1kc3MAAACBAOChCC7lf6Uo9n7BmZ6M8St19PZf4Tn59NriyboW2x/DZuYAz3ib
 	}
 }
 
+func TestIsActivityVisibleToUser(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	other := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	assert.True(t, user.IsActivityVisibleToUser(nil))
+	assert.True(t, user.IsActivityVisibleToUser(other))
+
+	user.KeepActivitySignedInOnly = true
+	assert.False(t, user.IsActivityVisibleToUser(nil))
+	assert.True(t, user.IsActivityVisibleToUser(other))
+	assert.True(t, user.IsActivityVisibleToUser(user))
+
+	user.KeepActivityPrivate = true
+	assert.False(t, user.IsActivityVisibleToUser(nil))
+	assert.False(t, user.IsActivityVisibleToUser(other))
+	assert.True(t, user.IsActivityVisibleToUser(user))
+	assert.True(t, user.IsActivityVisibleToUser(admin))
+}
+
 func TestUpdateUser(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
@@ -8,6 +8,9 @@ package models
 import (
 	"context"
 	"fmt"
+	"net/textproto"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -77,6 +80,47 @@ func IsValidHookContentType(name string) bool {
 	return ok
 }
 
+// HookPayloadVersion is the version of the schema of the payloads of gitea and gogs web hooks
+type HookPayloadVersion int
+
+const (
+	// PayloadVersionLegacy is the schema of the payloads before they were versioned
+	PayloadVersionLegacy HookPayloadVersion = iota + 1
+	// PayloadVersion2 adds the reaction counts of issues and comments and the pending transfer of repositories
+	PayloadVersion2
+
+	// PayloadVersionLatest is the latest version of the schema of the payloads
+	PayloadVersionLatest = PayloadVersion2
+)
+
+// ToHookPayloadVersion returns the HookPayloadVersion of the given name, it is the latest one for an empty name
+func ToHookPayloadVersion(name string) (HookPayloadVersion, error) {
+	if name == "" {
+		return PayloadVersionLatest, nil
+	}
+	version, err := strconv.Atoi(name)
+	if err != nil || version < int(PayloadVersionLegacy) || version > int(PayloadVersionLatest) {
+		return 0, fmt.Errorf("invalid payload version: %s", name)
+	}
+	return HookPayloadVersion(version), nil
+}
+
+// Name returns the name of a given web hook's payload version
+func (v HookPayloadVersion) Name() string {
+	return strconv.Itoa(int(v))
+}
+
+// DefaultSignatureHeader is the header carrying the HMAC-SHA256 signature of the payloads of the web hooks by default
+const DefaultSignatureHeader = "X-Gitea-Signature"
+
+var signatureHeaderPattern = regexp.MustCompile(`^[\w-]{1,255}$`)
+
+// IsValidSignatureHeader returns true if the name can be used as the signature header of a web hook,
+// an empty name selects the default header.
+func IsValidSignatureHeader(name string) bool {
+	return name == "" || signatureHeaderPattern.MatchString(name)
+}
+
 // HookEvents is a set of web hook events
 type HookEvents struct {
 	Create               bool `json:"create"`
@@ -95,7 +139,9 @@ type HookEvents struct {
 	PullRequestComment   bool `json:"pull_request_comment"`
 	PullRequestReview    bool `json:"pull_request_review"`
 	PullRequestSync      bool `json:"pull_request_sync"`
+	Deployment           bool `json:"deployment"`
 	Repository           bool `json:"repository"`
+	RepositorySettings   bool `json:"repository_settings"`
 	Release              bool `json:"release"`
 }
 
@@ -135,6 +181,17 @@ const (
 	HookStatusFail
 )
 
+// HookHealth is the health of a web hook according to its last deliveries
+type HookHealth string
+
+// Possible healths of a web hook
+const (
+	HookHealthUnknown  HookHealth = "unknown"
+	HookHealthHealthy  HookHealth = "healthy"
+	HookHealthFailing  HookHealth = "failing"
+	HookHealthDisabled HookHealth = "disabled"
+)
+
 // Webhook represents a web hook object.
 type Webhook struct {
 	ID              int64 `xorm:"pk autoincr"`
@@ -144,13 +201,20 @@ type Webhook struct {
 	URL             string `xorm:"url TEXT"`
 	HTTPMethod      string `xorm:"http_method"`
 	ContentType     HookContentType
-	Secret          string `xorm:"TEXT"`
-	Events          string `xorm:"TEXT"`
+	PayloadVersion  HookPayloadVersion `xorm:"NOT NULL DEFAULT 1"`
+	Secret          string             `xorm:"TEXT"`
+	SignatureHeader string             `xorm:"VARCHAR(255)"` // DefaultSignatureHeader if empty
+	Events          string             `xorm:"TEXT"`
 	*HookEvent      `xorm:"-"`
 	IsActive        bool       `xorm:"INDEX"`
 	Type            HookType   `xorm:"VARCHAR(16) 'type'"`
 	Meta            string     `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus      HookStatus // Last delivery status
+	// FailureCount is the number of consecutive failed deliveries since FirstFailureUnix
+	FailureCount     int                `xorm:"NOT NULL DEFAULT 0"`
+	FirstFailureUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// IsAutoDisabled is true when the webhook has been disabled because its deliveries kept failing
+	IsAutoDisabled bool `xorm:"NOT NULL DEFAULT false"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -166,6 +230,36 @@ func (w *Webhook) AfterLoad() {
 	}
 }
 
+// GetSignatureHeader returns the header carrying the HMAC-SHA256 signature of the payloads of the webhook.
+func (w *Webhook) GetSignatureHeader() string {
+	if w.SignatureHeader == "" {
+		return DefaultSignatureHeader
+	}
+	return textproto.CanonicalMIMEHeaderKey(w.SignatureHeader)
+}
+
+// Health returns the health of the webhook, it is disabled if its deliveries kept failing until it was disabled.
+func (w *Webhook) Health() HookHealth {
+	switch {
+	case w.IsAutoDisabled:
+		return HookHealthDisabled
+	case w.FailureCount > 0:
+		return HookHealthFailing
+	case w.LastStatus == HookStatusSucceed:
+		return HookHealthHealthy
+	}
+	return HookHealthUnknown
+}
+
+// shouldAutoDisable returns true if the consecutive failures of the webhook reached the limits of the settings
+func (w *Webhook) shouldAutoDisable(now timeutil.TimeStamp) bool {
+	if setting.Webhook.DisableAfterFailures > 0 && w.FailureCount >= setting.Webhook.DisableAfterFailures {
+		return true
+	}
+	return setting.Webhook.DisableAfterDays > 0 &&
+		now-w.FirstFailureUnix >= timeutil.TimeStamp(setting.Webhook.DisableAfterDays*24*60*60)
+}
+
 // History returns history of webhook by given conditions.
 func (w *Webhook) History(page int) ([]*HookTask, error) {
 	return HookTasks(w.ID, page)
@@ -287,6 +381,12 @@ func (w *Webhook) HasPullRequestSyncEvent() bool {
 		(w.ChooseEvents && w.HookEvents.PullRequestSync)
 }
 
+// HasDeploymentEvent returns true if hook enabled deployment event.
+func (w *Webhook) HasDeploymentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Deployment)
+}
+
 // HasReleaseEvent returns if hook enabled release event.
 func (w *Webhook) HasReleaseEvent() bool {
 	return w.SendEverything ||
@@ -299,6 +399,12 @@ func (w *Webhook) HasRepositoryEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasRepositorySettingsEvent returns if hook enabled repository settings event.
+func (w *Webhook) HasRepositorySettingsEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.RepositorySettings)
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -326,7 +432,9 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasPullRequestRejectedEvent, HookEventPullRequestReviewRejected},
 		{w.HasPullRequestCommentEvent, HookEventPullRequestReviewComment},
 		{w.HasPullRequestSyncEvent, HookEventPullRequestSync},
+		{w.HasDeploymentEvent, HookEventDeployment},
 		{w.HasRepositoryEvent, HookEventRepository},
+		{w.HasRepositorySettingsEvent, HookEventRepositorySettings},
 		{w.HasReleaseEvent, HookEventRelease},
 	}
 }
@@ -350,6 +458,9 @@ func CreateWebhook(w *Webhook) error {
 
 func createWebhook(e Engine, w *Webhook) error {
 	w.Type = strings.TrimSpace(w.Type)
+	if w.PayloadVersion == 0 {
+		w.PayloadVersion = PayloadVersionLatest
+	}
 	_, err := e.Insert(w)
 	return err
 }
@@ -477,7 +588,13 @@ func getSystemWebhooks(e Engine) ([]*Webhook, error) {
 }
 
 // UpdateWebhook updates information of webhook.
+// Its failures are forgotten when a webhook disabled because of them is enabled again.
 func UpdateWebhook(w *Webhook) error {
+	if w.IsActive && w.IsAutoDisabled {
+		w.IsAutoDisabled = false
+		w.FailureCount = 0
+		w.FirstFailureUnix = 0
+	}
 	_, err := x.ID(w.ID).AllCols().Update(w)
 	return err
 }
@@ -488,6 +605,31 @@ func UpdateWebhookLastStatus(w *Webhook) error {
 	return err
 }
 
+// UpdateWebhookDeliveryStatus updates the last status of the webhook and counts its consecutive failures,
+// it disables the webhook once they reach the limits of the settings and returns true if it did.
+func UpdateWebhookDeliveryStatus(w *Webhook, succeeded bool) (disabled bool, err error) {
+	if succeeded {
+		w.LastStatus = HookStatusSucceed
+		w.FailureCount = 0
+		w.FirstFailureUnix = 0
+	} else {
+		now := timeutil.TimeStampNow()
+		w.LastStatus = HookStatusFail
+		if w.FailureCount == 0 {
+			w.FirstFailureUnix = now
+		}
+		w.FailureCount++
+		if w.IsActive && w.shouldAutoDisable(now) {
+			w.IsActive = false
+			w.IsAutoDisabled = true
+			disabled = true
+		}
+	}
+
+	_, err = x.ID(w.ID).Cols("last_status", "failure_count", "first_failure_unix", "is_active", "is_auto_disabled").Update(w)
+	return disabled, err
+}
+
 // deleteWebhook uses argument bean as query condition,
 // ID must be specified and do not assign unnecessary fields.
 func deleteWebhook(bean *Webhook) (err error) {
@@ -595,7 +737,9 @@ const (
 	HookEventPullRequestReviewRejected HookEventType = "pull_request_review_rejected"
 	HookEventPullRequestReviewComment  HookEventType = "pull_request_review_comment"
 	HookEventPullRequestSync           HookEventType = "pull_request_sync"
+	HookEventDeployment                HookEventType = "deployment"
 	HookEventRepository                HookEventType = "repository"
+	HookEventRepositorySettings        HookEventType = "repository_settings"
 	HookEventRelease                   HookEventType = "release"
 )
 
@@ -623,8 +767,12 @@ func (h HookEventType) Event() string {
 		return "pull_request_rejected"
 	case HookEventPullRequestReviewComment:
 		return "pull_request_comment"
+	case HookEventDeployment:
+		return "deployment"
 	case HookEventRepository:
 		return "repository"
+	case HookEventRepositorySettings:
+		return "repository_settings"
 	case HookEventRelease:
 		return "release"
 	}
@@ -718,6 +866,53 @@ func HookTasks(hookID int64, page int) ([]*HookTask, error) {
 		Find(&tasks)
 }
 
+// FindHookTasks returns the hook tasks of a webhook, most recent first
+func FindHookTasks(hookID int64, listOptions ListOptions) ([]*HookTask, error) {
+	sess := x.Where("hook_id=?", hookID).Desc("id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	tasks := make([]*HookTask, 0, listOptions.PageSize)
+	return tasks, sess.Find(&tasks)
+}
+
+// CountHookTasks returns the number of hook tasks of a webhook
+func CountHookTasks(hookID int64) (int64, error) {
+	return x.Where("hook_id=?", hookID).Count(new(HookTask))
+}
+
+// GetHookTaskByID returns the hook task of a webhook by its ID
+func GetHookTaskByID(hookID, id int64) (*HookTask, error) {
+	t := &HookTask{ID: id, HookID: hookID}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskNotExist{HookID: hookID, ID: id}
+	}
+	return t, nil
+}
+
+// ReplayHookTask creates a new undelivered hook task with the payload of the given one
+func ReplayHookTask(hookID, id int64) (*HookTask, error) {
+	t, err := GetHookTaskByID(hookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	newTask := &HookTask{
+		RepoID:         t.RepoID,
+		HookID:         t.HookID,
+		UUID:           gouuid.New().String(),
+		PayloadContent: t.PayloadContent,
+		EventType:      t.EventType,
+	}
+	if _, err = x.Insert(newTask); err != nil {
+		return nil, err
+	}
+	return newTask, nil
+}
+
 // CreateHookTask creates a new hook task,
 // it handles conversion from Payload to PayloadContent.
 func CreateHookTask(t *HookTask) error {
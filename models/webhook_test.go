@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 
 	jsoniter "github.com/json-iterator/go"
@@ -69,7 +70,7 @@ func TestWebhook_EventsArray(t *testing.T) {
 		"issues", "issue_assign", "issue_label", "issue_milestone", "issue_comment",
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
 		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
-		"pull_request_review_comment", "pull_request_sync", "repository", "release",
+		"pull_request_review_comment", "pull_request_sync", "deployment", "repository", "repository_settings", "release",
 	},
 		(&Webhook{
 			HookEvent: &HookEvent{SendEverything: true},
@@ -226,6 +227,21 @@ func TestUpdateHookTask(t *testing.T) {
 	AssertExistsAndLoadBean(t, hook)
 }
 
+func TestReplayHookTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	hookTask := AssertExistsAndLoadBean(t, &HookTask{ID: 1}).(*HookTask)
+	newTask, err := ReplayHookTask(hookTask.HookID, hookTask.ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hookTask.ID, newTask.ID)
+	assert.NotEqual(t, hookTask.UUID, newTask.UUID)
+	assert.False(t, newTask.IsDelivered)
+	AssertExistsAndLoadBean(t, &HookTask{ID: newTask.ID, HookID: hookTask.HookID, PayloadContent: hookTask.PayloadContent})
+
+	_, err = ReplayHookTask(NonexistentID, hookTask.ID)
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
+
 func TestCleanupHookTaskTable_PerWebhook_DeletesDelivered(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	hookTask := &HookTask{
@@ -325,3 +341,63 @@ func TestCleanupHookTaskTable_OlderThan_LeavesTaskEarlierThanAgeToDelete(t *test
 	assert.NoError(t, CleanupHookTaskTable(context.Background(), OlderThan, 168*time.Hour, 0))
 	AssertExistsAndLoadBean(t, hookTask)
 }
+
+func TestToHookPayloadVersion(t *testing.T) {
+	version, err := ToHookPayloadVersion("")
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadVersionLatest, version)
+
+	version, err = ToHookPayloadVersion("1")
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadVersionLegacy, version)
+
+	_, err = ToHookPayloadVersion("0")
+	assert.Error(t, err)
+	_, err = ToHookPayloadVersion("legacy")
+	assert.Error(t, err)
+}
+
+func TestWebhook_GetSignatureHeader(t *testing.T) {
+	assert.Equal(t, DefaultSignatureHeader, (&Webhook{}).GetSignatureHeader())
+	assert.Equal(t, "X-Payload-Signature", (&Webhook{SignatureHeader: "x-payload-signature"}).GetSignatureHeader())
+
+	assert.True(t, IsValidSignatureHeader(""))
+	assert.True(t, IsValidSignatureHeader("X-Payload-Signature"))
+	assert.False(t, IsValidSignatureHeader("X-Payload Signature"))
+	assert.False(t, IsValidSignatureHeader("X-Signature:"))
+}
+
+func TestUpdateWebhookDeliveryStatus(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(failures int) {
+		setting.Webhook.DisableAfterFailures = failures
+	}(setting.Webhook.DisableAfterFailures)
+	setting.Webhook.DisableAfterFailures = 2
+
+	hook := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.True(t, hook.IsActive)
+
+	disabled, err := UpdateWebhookDeliveryStatus(hook, false)
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+	assert.Equal(t, HookHealthFailing, hook.Health())
+
+	disabled, err = UpdateWebhookDeliveryStatus(hook, true)
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+	assert.Equal(t, HookHealthHealthy, hook.Health())
+
+	for i := 1; i <= 2; i++ {
+		disabled, err = UpdateWebhookDeliveryStatus(hook, false)
+		assert.NoError(t, err)
+		assert.Equal(t, i == 2, disabled)
+	}
+	hook = AssertExistsAndLoadBean(t, &Webhook{ID: 1, IsActive: false, IsAutoDisabled: true}).(*Webhook)
+	assert.Equal(t, 2, hook.FailureCount)
+	assert.Equal(t, HookHealthDisabled, hook.Health())
+
+	hook.IsActive = true
+	assert.NoError(t, UpdateWebhook(hook))
+	hook = AssertExistsAndLoadBean(t, &Webhook{ID: 1, IsActive: true, IsAutoDisabled: false}).(*Webhook)
+	assert.Zero(t, hook.FailureCount)
+}
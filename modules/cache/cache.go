@@ -37,6 +37,13 @@ func NewContext() error {
 		}
 	}
 
+	lastCommit := setting.CacheService.LastCommit
+	if lastCommitConn == nil && lastCommit.Enabled && lastCommit.Adapter != "" {
+		if lastCommitConn, err = newLastCommitCache(lastCommit.Adapter, lastCommit.Conn, lastCommit.MaxEntries); err != nil {
+			return err
+		}
+	}
+
 	return err
 }
 
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var lastCommitConn git.Cache
+
+func newLastCommitCache(adapter, conn string, maxEntries int64) (git.Cache, error) {
+	switch adapter {
+	case "redis":
+		return newRedisLastCommitCache(conn, maxEntries)
+	case "leveldb":
+		return newLevelDBLastCommitCache(conn, maxEntries)
+	default:
+		return nil, fmt.Errorf("unknown last commit cache adapter: %s", adapter)
+	}
+}
+
+// GetLastCommitCache returns the cache storing the last commits of the entries of repositories,
+// it is the cache service unless a dedicated adapter is configured
+func GetLastCommitCache() git.Cache {
+	if setting.CacheService.LastCommit.Adapter != "" {
+		return lastCommitConn
+	}
+	if conn == nil {
+		return nil
+	}
+	return conn
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/nosql"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/unknwon/com"
+)
+
+// levelDBLastCommitCache stores the last commits in a leveldb as "<expiry>:<value>"
+type levelDBLastCommitCache struct {
+	lock       sync.Mutex
+	db         *leveldb.DB
+	count      int64
+	maxEntries int64
+}
+
+func newLevelDBLastCommitCache(conn string, maxEntries int64) (*levelDBLastCommitCache, error) {
+	db, err := nosql.GetManager().GetLevelDB(conn)
+	if err != nil {
+		return nil, err
+	}
+	c := &levelDBLastCommitCache{
+		db:         db,
+		maxEntries: maxEntries,
+	}
+
+	iter := db.NewIterator(nil, nil)
+	for iter.Next() {
+		c.count++
+	}
+	iter.Release()
+	return c, iter.Error()
+}
+
+// Put puts value into cache with key and expire time.
+// If expired is 0, it lives forever.
+func (c *levelDBLastCommitCache) Put(key string, val interface{}, expire int64) error {
+	var expiry int64
+	if expire > 0 {
+		expiry = time.Now().Unix() + expire
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	has, err := c.db.Has([]byte(key), nil)
+	if err != nil {
+		return err
+	}
+	if err := c.db.Put([]byte(key), []byte(strconv.FormatInt(expiry, 10)+":"+com.ToStr(val)), nil); err != nil {
+		return err
+	}
+	if !has {
+		c.count++
+	}
+	if c.maxEntries > 0 && c.count > c.maxEntries {
+		return c.evict()
+	}
+	return nil
+}
+
+// evict removes the expired entries, and then other entries until there is room for a tenth of the max entries.
+// As the keys are hashes, the evicted entries are random.
+func (c *levelDBLastCommitCache) evict() error {
+	now := time.Now().Unix()
+	evicted, err := c.deleteEntries(func(value []byte) bool {
+		expiry, _, ok := parseLevelDBLastCommit(value)
+		return !ok || (expiry > 0 && expiry <= now)
+	})
+	if err != nil {
+		return err
+	}
+
+	if target := c.maxEntries - c.maxEntries/10; c.count > target {
+		toEvict := c.count - target
+		n, err := c.deleteEntries(func([]byte) bool {
+			toEvict--
+			return toEvict >= 0
+		})
+		if err != nil {
+			return err
+		}
+		evicted += n
+	}
+
+	log.Debug("LastCommitCache evicted %d entries", evicted)
+	return nil
+}
+
+// deleteEntries deletes the entries whose value matches the condition
+func (c *levelDBLastCommitCache) deleteEntries(cond func(value []byte) bool) (int64, error) {
+	batch := new(leveldb.Batch)
+	iter := c.db.NewIterator(nil, nil)
+	for iter.Next() {
+		if cond(iter.Value()) {
+			batch.Delete(append([]byte{}, iter.Key()...))
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := c.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	c.count -= int64(batch.Len())
+	return int64(batch.Len()), nil
+}
+
+// Get gets cached value by given key.
+func (c *levelDBLastCommitCache) Get(key string) interface{} {
+	data, err := c.db.Get([]byte(key), nil)
+	if err != nil {
+		return nil
+	}
+	expiry, val, ok := parseLevelDBLastCommit(data)
+	if !ok {
+		return nil
+	}
+	if expiry > 0 && expiry <= time.Now().Unix() {
+		return nil
+	}
+	return val
+}
+
+func parseLevelDBLastCommit(data []byte) (expiry int64, val string, ok bool) {
+	fields := strings.SplitN(string(data), ":", 2)
+	if len(fields) != 2 {
+		return 0, "", false
+	}
+	expiry, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return expiry, fields[1], true
+}
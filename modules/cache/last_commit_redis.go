@@ -0,0 +1,91 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/nosql"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/unknwon/com"
+)
+
+// redisLastCommitCache stores the last commits in redis, the keys are indexed by insertion time
+// in a sorted set to evict the oldest ones when there are more than maxEntries
+type redisLastCommitCache struct {
+	client     redis.UniversalClient
+	prefix     string
+	indexName  string
+	maxEntries int64
+}
+
+func newRedisLastCommitCache(conn string, maxEntries int64) (*redisLastCommitCache, error) {
+	uri := nosql.ToRedisURI(conn)
+	c := &redisLastCommitCache{
+		client:     nosql.GetManager().GetRedisClient(uri.String()),
+		indexName:  "last_commit_index",
+		maxEntries: maxEntries,
+	}
+	for k, v := range uri.Query() {
+		switch k {
+		case "prefix":
+			c.prefix = v[0]
+		case "index_name":
+			c.indexName = v[0]
+		}
+	}
+	return c, c.client.Ping(graceful.GetManager().HammerContext()).Err()
+}
+
+// Put puts value into cache with key and expire time.
+// If expired is 0, it lives forever.
+func (c *redisLastCommitCache) Put(key string, val interface{}, expire int64) error {
+	ctx := graceful.GetManager().HammerContext()
+	key = c.prefix + key
+	if err := c.client.Set(ctx, key, com.ToStr(val), time.Duration(expire)*time.Second).Err(); err != nil {
+		return err
+	}
+	if c.maxEntries <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if err := c.client.ZAdd(ctx, c.indexName, &redis.Z{Score: float64(now.Unix()), Member: key}).Err(); err != nil {
+		return err
+	}
+	if expire > 0 {
+		// the keys expired by redis don't need to be evicted anymore
+		expired := strconv.FormatInt(now.Unix()-expire, 10)
+		if err := c.client.ZRemRangeByScore(ctx, c.indexName, "-inf", "("+expired).Err(); err != nil {
+			return err
+		}
+	}
+
+	count, err := c.client.ZCard(ctx, c.indexName).Result()
+	if err != nil || count <= c.maxEntries {
+		return err
+	}
+	evicted, err := c.client.ZPopMin(ctx, c.indexName, count-c.maxEntries).Result()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(evicted))
+	for _, z := range evicted {
+		keys = append(keys, z.Member.(string))
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Get gets cached value by given key.
+func (c *redisLastCommitCache) Get(key string) interface{} {
+	val, err := c.client.Get(graceful.GetManager().HammerContext(), c.prefix+key).Result()
+	if err != nil {
+		return nil
+	}
+	return val
+}
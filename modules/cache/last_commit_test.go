@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"code.gitea.io/gitea/modules/nosql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelDBLastCommitCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "last_commit_cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer nosql.GetManager().CloseLevelDB(dir)
+
+	c, err := newLevelDBLastCommitCache(dir, 10)
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Put("key", "65f1bf27bc3bf70f64657658635e66094edbcb4d", 60))
+	assert.EqualValues(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", c.Get("key"))
+	assert.Nil(t, c.Get("missing"))
+
+	assert.NoError(t, c.Put("expired", "65f1bf27bc3bf70f64657658635e66094edbcb4d", 60))
+	assert.NoError(t, c.db.Put([]byte("expired"), []byte("1:65f1bf27bc3bf70f64657658635e66094edbcb4d"), nil))
+	assert.Nil(t, c.Get("expired"))
+	assert.EqualValues(t, 2, c.count)
+
+	// the 11th entry evicts the expired entry and then another one to leave room for a tenth of the entries
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, c.Put(fmt.Sprintf("key%d", i), "65f1bf27bc3bf70f64657658635e66094edbcb4d", 0))
+	}
+	assert.EqualValues(t, 9, c.count)
+
+	c2, err := newLevelDBLastCommitCache(dir, 10)
+	assert.NoError(t, err)
+	defer nosql.GetManager().CloseLevelDB(dir)
+	assert.EqualValues(t, 9, c2.count)
+}
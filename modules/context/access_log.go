@@ -45,6 +45,7 @@ func AccessLogger() func(http.Handler) http.Handler {
 				Ctx: map[string]interface{}{
 					"RemoteAddr": req.RemoteAddr,
 					"Req":        req,
+					"RequestID":  GetRequestID(req),
 				},
 			})
 			if err != nil {
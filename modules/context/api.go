@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/auth"
 
@@ -26,7 +27,8 @@ import (
 // APIContext is a specific context for API service
 type APIContext struct {
 	*Context
-	Org *APIOrganization
+	Org     *APIOrganization
+	Package *Package
 }
 
 // APIError is error format response
@@ -230,6 +232,12 @@ func APIAuth(authMethod auth.Auth) func(*APIContext) {
 			ctx.Data["SignedUserID"] = ctx.User.ID
 			ctx.Data["SignedUserName"] = ctx.User.Name
 			ctx.Data["IsAdmin"] = ctx.User.IsAdmin
+
+			if u := ctx.viewAsUser(); u != nil && !ctx.ViewAs(u) {
+				ctx.JSON(http.StatusForbidden, map[string]string{
+					"message": "Only read-only requests are allowed while viewing as another user.",
+				})
+			}
 		} else {
 			ctx.Data["SignedUserID"] = int64(0)
 			ctx.Data["SignedUserName"] = ""
@@ -239,6 +247,7 @@ func APIAuth(authMethod auth.Auth) func(*APIContext) {
 
 // APIContexter returns apicontext as middleware
 func APIContexter() func(http.Handler) http.Handler {
+	var rnd = templates.HTMLRenderer()
 	var csrfOpts = getCsrfOpts()
 
 	return func(next http.Handler) http.Handler {
@@ -250,6 +259,7 @@ func APIContexter() func(http.Handler) http.Handler {
 					Resp:    NewResponse(w),
 					Data:    map[string]interface{}{},
 					Locale:  locale,
+					Render:  rnd,
 					Session: session.GetSession(req),
 					Repo: &Repository{
 						PullRequest: &PullRequest{},
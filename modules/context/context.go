@@ -64,6 +64,8 @@ type Context struct {
 	User        *models.User
 	IsSigned    bool
 	IsBasicAuth bool
+	// ViewAsAdmin is the signed in admin viewing the site as User, it is nil otherwise
+	ViewAsAdmin *models.User
 
 	Repo *Repository
 	Org  *Organization
@@ -638,6 +640,11 @@ func Auth(authMethod auth.Auth) func(*Context) {
 			ctx.Data["SignedUserID"] = ctx.User.ID
 			ctx.Data["SignedUserName"] = ctx.User.Name
 			ctx.Data["IsAdmin"] = ctx.User.IsAdmin
+
+			if u := ctx.viewAsUser(); u != nil && !ctx.ViewAs(u) {
+				ctx.Flash.Error(ctx.Tr("admin.view_as.read_only"))
+				ctx.RedirectToFirst(ctx.Req.Referer())
+			}
 		} else {
 			ctx.Data["SignedUserID"] = int64(0)
 			ctx.Data["SignedUserName"] = ""
@@ -673,6 +680,7 @@ func Contexter() func(next http.Handler) http.Handler {
 					"CurrentURL":    setting.AppSubURL + req.URL.RequestURI(),
 					"PageStartTime": startTime,
 					"Link":          link,
+					"RequestID":     GetRequestID(req),
 				},
 			}
 
@@ -0,0 +1,49 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Package contains the owner of the requested packages and the access mode of the doer
+type Package struct {
+	Owner      *models.User
+	AccessMode models.AccessMode
+}
+
+// PackageAssignmentAPI returns a middleware to handle Context.Package assignment for the package registry routes
+func PackageAssignmentAPI() func(ctx *APIContext) {
+	return func(ctx *APIContext) {
+		if !setting.Packages.Enabled {
+			ctx.NotFound()
+			return
+		}
+
+		owner, err := models.GetUserByName(ctx.Params("username"))
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+
+		mode, err := models.GetPackageAccessMode(owner, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetPackageAccessMode", err)
+			return
+		}
+
+		ctx.Package = &Package{
+			Owner:      owner,
+			AccessMode: mode,
+		}
+	}
+}
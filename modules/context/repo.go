@@ -9,11 +9,13 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
@@ -26,6 +28,8 @@ import (
 	"github.com/unknwon/com"
 )
 
+const tplRepoAccessRequest base.TplName = "repo/access_request"
+
 // IssueTemplateDirCandidates issue templates directory
 var IssueTemplateDirCandidates = []string{
 	"ISSUE_TEMPLATE",
@@ -38,6 +42,16 @@ var IssueTemplateDirCandidates = []string{
 	".gitlab/issue_template",
 }
 
+// PullRequestTemplateCandidates pull request templates
+var PullRequestTemplateCandidates = []string{
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	".gitea/PULL_REQUEST_TEMPLATE.md",
+	".gitea/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".github/pull_request_template.md",
+}
+
 // PullRequest contains information to make a pull request
 type PullRequest struct {
 	BaseRepo *models.Repository
@@ -331,6 +345,19 @@ func RedirectToRepo(ctx *Context, redirectRepoID int64) {
 	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath))
 }
 
+// renderRepoAccessRequest renders the page to request access to a repository the signed in user has no access to
+func renderRepoAccessRequest(ctx *Context, repo *models.Repository) {
+	req, err := models.GetPendingRepoAccessRequest(repo.ID, ctx.User.ID)
+	if err != nil && !models.IsErrRepoAccessRequestNotExist(err) {
+		ctx.ServerError("GetPendingRepoAccessRequest", err)
+		return
+	}
+	ctx.Data["Title"] = repo.Owner.Name + "/" + repo.Name
+	ctx.Data["AccessRequestRepo"] = repo
+	ctx.Data["PendingAccessRequest"] = req
+	ctx.HTML(http.StatusForbidden, tplRepoAccessRequest)
+}
+
 func repoAssignment(ctx *Context, repo *models.Repository) {
 	var err error
 	if err = repo.GetOwner(); err != nil {
@@ -350,6 +377,17 @@ func repoAssignment(ctx *Context, repo *models.Repository) {
 			EarlyResponseForGoGetMeta(ctx)
 			return
 		}
+		if ctx.IsSigned && setting.Repository.EnableAccessRequests && ctx.Req.Method == http.MethodGet {
+			requestable, err := models.IsRepoAccessRequestable(ctx.User, repo)
+			if err != nil {
+				ctx.ServerError("IsRepoAccessRequestable", err)
+				return
+			}
+			if requestable {
+				renderRepoAccessRequest(ctx, repo)
+				return
+			}
+		}
 		ctx.NotFound("no access right", nil)
 		return
 	}
@@ -484,6 +522,12 @@ func RepoAssignment(ctx *Context) (cancel context.CancelFunc) {
 	ctx.Data["Owner"] = ctx.Repo.Repository.Owner
 	ctx.Data["IsRepositoryOwner"] = ctx.Repo.IsOwner()
 	ctx.Data["IsRepositoryAdmin"] = ctx.Repo.IsAdmin()
+	if setting.Repository.EnableAccessRequests && ctx.Repo.IsAdmin() {
+		if ctx.Data["NumPendingAccessRequests"], err = models.CountPendingRepoAccessRequests(repo.ID); err != nil {
+			ctx.ServerError("CountPendingRepoAccessRequests", err)
+			return
+		}
+	}
 	ctx.Data["RepoOwnerIsOrganization"] = repo.Owner.IsOrganization()
 	ctx.Data["CanWriteCode"] = ctx.Repo.CanWrite(models.UnitTypeCode)
 	ctx.Data["CanWriteIssues"] = ctx.Repo.CanWrite(models.UnitTypeIssues)
@@ -878,6 +922,48 @@ func UnitTypes() func(ctx *Context) {
 	}
 }
 
+// PullRequestTemplateFromDefaultBranch returns the pull request template in the repo's default branch, or nil if there is none
+func (ctx *Context) PullRequestTemplateFromDefaultBranch() *api.IssueTemplate {
+	if ctx.Repo.Commit == nil {
+		var err error
+		ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
+		if err != nil {
+			return nil
+		}
+	}
+
+	for _, filename := range PullRequestTemplateCandidates {
+		entry, err := ctx.Repo.Commit.GetTreeEntryByPath(filename)
+		if err != nil {
+			continue
+		}
+		if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+			log.Debug("Pull request template is too large: %s", filename)
+			return nil
+		}
+		r, err := entry.Blob().DataAsync()
+		if err != nil {
+			log.Debug("DataAsync: %v", err)
+			return nil
+		}
+		data, err := ioutil.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			log.Debug("ReadAll: %v", err)
+			return nil
+		}
+		it := &api.IssueTemplate{FileName: filename}
+		content, err := markdown.ExtractMetadata(string(data), it)
+		if err != nil {
+			log.Debug("ExtractMetadata: %v", err)
+			content = string(data)
+		}
+		it.Content = content
+		return it
+	}
+	return nil
+}
+
 // IssueTemplatesFromDefaultBranch checks for issue templates in the repo's default branch
 func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 	var issueTemplates []api.IssueTemplate
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the response header holding the ID of the request
+const RequestIDHeader = "X-Gitea-Request-Id"
+
+type requestIDKeyType struct{}
+
+var requestIDKey = requestIDKeyType{}
+
+// RequestIDer generates an ID for every request, returns it in the RequestIDHeader
+// and attaches it to the log events written while the request is handled
+func RequestIDer() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			requestID := strings.ReplaceAll(uuid.New().String(), "-", "")
+			resp.Header().Set(RequestIDHeader, requestID)
+
+			defer log.BindTrace(requestID)()
+			next.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), requestIDKey, requestID)))
+		})
+	}
+}
+
+// GetRequestID returns the ID generated for the request by RequestIDer
+func GetRequestID(req *http.Request) string {
+	requestID, _ := req.Context().Value(requestIDKey).(string)
+	return requestID
+}
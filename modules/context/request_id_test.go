@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDer(t *testing.T) {
+	var requestIDs []string
+	handler := RequestIDer()(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requestIDs = append(requestIDs, GetRequestID(req))
+	}))
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Len(t, requestIDs[i], 32)
+		assert.Equal(t, requestIDs[i], recorder.Header().Get(RequestIDHeader))
+	}
+	assert.NotEqual(t, requestIDs[0], requestIDs[1])
+
+	assert.Empty(t, GetRequestID(httptest.NewRequest("GET", "/", nil)))
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/auth"
+)
+
+// viewAsSessionKey is the session key of the user an admin views the site as
+const viewAsSessionKey = "view_as_uid"
+
+// ViewAsStopLink is the link to stop viewing the site as another user
+const ViewAsStopLink = "/user/view_as/stop"
+
+// viewAsRefusedPaths are the prefixes of the paths of the requests which change something
+// even with a read-only method, e.g. authorizing an OAuth2 application creates an authorization code
+var viewAsRefusedPaths = []string{
+	"/login/oauth/",
+	"/user/activate",
+}
+
+// IsReadOnlyMethod returns if requests with the method can not change anything
+func IsReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isAllowedWhileViewingAs returns if req can be made while viewing the site as another user,
+// only read-only requests which have no side effect are allowed
+func isAllowedWhileViewingAs(req *http.Request) bool {
+	if req.URL.Path == ViewAsStopLink {
+		return true
+	}
+	if !IsReadOnlyMethod(req.Method) {
+		return false
+	}
+	for _, prefix := range viewAsRefusedPaths {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// StartViewAs lets the signed in admin view the site as u
+func (ctx *Context) StartViewAs(u *models.User) error {
+	if err := ctx.Session.Set(viewAsSessionKey, u.ID); err != nil {
+		return err
+	}
+	log.Info("View as: admin %s started viewing the site as %s", ctx.User.Name, u.Name)
+	return models.CreateNotice(models.NoticeViewAs, "Admin %s started viewing the site as %s", ctx.User.Name, u.Name)
+}
+
+// StopViewAs ends the view as mode of the admin, it returns the user viewed as
+func (ctx *Context) StopViewAs() (*models.User, error) {
+	admin, u := ctx.ViewAsAdmin, ctx.User
+	if admin == nil {
+		return nil, nil
+	}
+	if err := ctx.Session.Delete(viewAsSessionKey); err != nil {
+		return nil, err
+	}
+	ctx.User, ctx.ViewAsAdmin = admin, nil
+	log.Info("View as: admin %s stopped viewing the site as %s", admin.Name, u.Name)
+	return u, models.CreateNotice(models.NoticeViewAs, "Admin %s stopped viewing the site as %s", admin.Name, u.Name)
+}
+
+// viewAsUser returns the user the admin signed in by session views the site as, nil if they do not
+func (ctx *Context) viewAsUser() *models.User {
+	uid, ok := ctx.Session.Get(viewAsSessionKey).(int64)
+	if !ok || !ctx.User.IsAdmin || ctx.Data["AuthedMethod"] != new(auth.Session).Name() {
+		return nil
+	}
+	u, err := models.GetUserByID(uid)
+	if err != nil {
+		log.Error("View as: GetUserByID(%d): %v", uid, err)
+		_ = ctx.Session.Delete(viewAsSessionKey)
+		return nil
+	}
+	return u
+}
+
+// ViewAs replaces the signed in admin by u for the request.
+// It returns false if the request is not read-only or has side effects, it has to be refused then.
+func (ctx *Context) ViewAs(u *models.User) bool {
+	admin := ctx.User
+	log.Info("View as: admin %s requested %s %s as %s", admin.Name, ctx.Req.Method, ctx.Req.URL.RequestURI(), u.Name)
+	ctx.ViewAsAdmin = admin
+	ctx.User = u
+	ctx.Data["SignedUser"] = u
+	ctx.Data["SignedUserID"] = u.ID
+	ctx.Data["SignedUserName"] = u.Name
+	ctx.Data["IsAdmin"] = u.IsAdmin
+	ctx.Data["ViewAsAdmin"] = admin
+
+	if !isAllowedWhileViewingAs(ctx.Req) {
+		log.Warn("View as: refused %s %s of admin %s as %s", ctx.Req.Method, ctx.Req.URL.RequestURI(), admin.Name, u.Name)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToCLA converts a version of the contributor license agreement of an organization to API format
+func ToCLA(cla *models.CLA, org *models.User) *api.CLA {
+	return &api.CLA{
+		Version:  cla.Version,
+		Content:  cla.Content,
+		Enforced: org.EnforceCLA,
+		Created:  cla.CreatedUnix.AsTime(),
+	}
+}
@@ -124,6 +124,7 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
 		BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      bp.RequireCodeOwnerApproval,
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
 		RequireSignedCommits:          bp.RequireSignedCommits,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
@@ -132,6 +133,27 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 	}
 }
 
+// ToTagProtection convert a ProtectedTag to api.TagProtection
+func ToTagProtection(pt *models.ProtectedTag) *api.TagProtection {
+	whitelistUsernames, err := models.GetUserNamesByIDs(pt.AllowlistUserIDs)
+	if err != nil {
+		log.Error("GetUserNamesByIDs (AllowlistUserIDs): %v", err)
+	}
+	whitelistTeams, err := models.GetTeamNamesByID(pt.AllowlistTeamIDs)
+	if err != nil {
+		log.Error("GetTeamNamesByID (AllowlistTeamIDs): %v", err)
+	}
+
+	return &api.TagProtection{
+		ID:                 pt.ID,
+		NamePattern:        pt.NamePattern,
+		WhitelistUsernames: whitelistUsernames,
+		WhitelistTeams:     whitelistTeams,
+		Created:            pt.CreatedUnix.AsTime(),
+		Updated:            pt.UpdatedUnix.AsTime(),
+	}
+}
+
 // ToTag convert a git.Tag to an api.Tag
 func ToTag(repo *models.Repository, t *git.Tag) *api.Tag {
 	return &api.Tag{
@@ -166,7 +188,7 @@ func ToVerification(c *git.Commit) *api.PayloadCommitVerification {
 
 // ToPublicKey convert models.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *models.PublicKey) *api.PublicKey {
-	return &api.PublicKey{
+	apiKey := &api.PublicKey{
 		ID:          key.ID,
 		Key:         key.Content,
 		URL:         fmt.Sprintf("%s%d", apiLink, key.ID),
@@ -174,6 +196,11 @@ func ToPublicKey(apiLink string, key *models.PublicKey) *api.PublicKey {
 		Fingerprint: key.Fingerprint,
 		Created:     key.CreatedUnix.AsTime(),
 	}
+	if key.ExpiredUnix > 0 {
+		expires := key.ExpiredUnix.AsTime()
+		apiKey.Expires = &expires
+	}
+	return apiKey
 }
 
 // ToGPGKey converts models.GPGKey to api.GPGKey
@@ -229,6 +256,12 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 		"url":          w.URL,
 		"content_type": w.ContentType.Name(),
 	}
+	if w.Type == models.GITEA || w.Type == models.GOGS {
+		config["payload_version"] = w.PayloadVersion.Name()
+	}
+	if w.SignatureHeader != "" {
+		config["signature_header"] = w.SignatureHeader
+	}
 	if w.Type == models.SLACK {
 		s := webhook.GetSlackHook(w)
 		config["channel"] = s.Channel
@@ -238,15 +271,130 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	}
 
 	return &api.Hook{
-		ID:      w.ID,
-		Type:    string(w.Type),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
-		Active:  w.IsActive,
-		Config:  config,
-		Events:  w.EventsArray(),
-		Updated: w.UpdatedUnix.AsTime(),
-		Created: w.CreatedUnix.AsTime(),
+		ID:                  w.ID,
+		Type:                string(w.Type),
+		URL:                 fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		Active:              w.IsActive,
+		Health:              string(w.Health()),
+		ConsecutiveFailures: w.FailureCount,
+		Config:              config,
+		Events:              w.EventsArray(),
+		Updated:             w.UpdatedUnix.AsTime(),
+		Created:             w.CreatedUnix.AsTime(),
+	}
+}
+
+// ToRepoEvent converts models.RepoEvent to api.RepoEvent
+func ToRepoEvent(e *models.RepoEvent) *api.RepoEvent {
+	return &api.RepoEvent{
+		ID:      e.ID,
+		Event:   string(e.EventType),
+		Payload: e.PayloadContent,
+		Created: e.CreatedUnix.AsTime(),
+	}
+}
+
+// ToOrgAccessToken convert models.AccessToken of an organization to api.OrgAccessToken
+func ToOrgAccessToken(t *models.AccessToken) *api.OrgAccessToken {
+	apiToken := &api.OrgAccessToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		Token:          t.Token,
+		TokenLastEight: t.TokenLastEight,
+		Scopes:         make([]string, 0, len(t.Scopes())),
+		Created:        t.CreatedUnix.AsTime(),
+	}
+	for _, scope := range t.Scopes() {
+		apiToken.Scopes = append(apiToken.Scopes, string(scope))
+	}
+	if t.Creator != nil {
+		apiToken.Creator = t.Creator.Name
+	}
+	if t.ExpiresUnix > 0 {
+		expires := t.ExpiresUnix.AsTime()
+		apiToken.Expires = &expires
+	}
+	return apiToken
+}
+
+// ToGitOperation convert models.GitOperation to api.GitOperation
+func ToGitOperation(op *models.GitOperation) *api.GitOperation {
+	apiOp := &api.GitOperation{
+		ID:            op.ID,
+		RepoID:        op.RepoID,
+		IsWiki:        op.IsWiki,
+		KeyID:         op.KeyID,
+		TokenID:       op.TokenID,
+		Type:          string(op.Type),
+		Protocol:      op.Protocol,
+		RemoteIP:      op.RemoteIP,
+		Refs:          op.RefLines(),
+		BytesReceived: op.BytesReceived,
+		BytesSent:     op.BytesSent,
+		DurationMs:    op.Duration,
+		Finished:      op.IsFinished,
+		Succeeded:     op.IsSucceeded,
+		Created:       op.CreatedUnix.AsTime(),
+	}
+	if op.Repo != nil {
+		apiOp.RepoFullName = op.Repo.FullName()
+	}
+	if op.User != nil {
+		apiOp.UserName = op.User.Name
+	}
+	return apiOp
+}
+
+// ToRepoAccessRequest convert models.RepoAccessRequest to api.RepoAccessRequest, its attributes have to be loaded
+func ToRepoAccessRequest(r *models.RepoAccessRequest, doer *models.User) *api.RepoAccessRequest {
+	apiReq := &api.RepoAccessRequest{
+		ID:           r.ID,
+		RepoFullName: r.Repo.FullName(),
+		User:         ToUser(r.User, doer),
+		Message:      r.Message,
+		State:        r.Status.String(),
+		Created:      r.CreatedUnix.AsTime(),
+	}
+	if r.Status == models.RepoAccessRequestApproved {
+		apiReq.Permission = r.Mode.String()
+	}
+	if r.Reviewer != nil {
+		apiReq.Reviewer = ToUser(r.Reviewer, doer)
+		reviewed := r.ReviewedUnix.AsTime()
+		apiReq.Reviewed = &reviewed
+	}
+	return apiReq
+}
+
+// ToHookDelivery convert models.HookTask to api.HookDelivery
+func ToHookDelivery(t *models.HookTask) *api.HookDelivery {
+	d := &api.HookDelivery{
+		ID:        t.ID,
+		UUID:      t.UUID,
+		Event:     string(t.EventType),
+		Delivered: t.IsDelivered,
+		Succeeded: t.IsSucceed,
+	}
+	if t.IsDelivered {
+		deliveredAt := time.Unix(0, t.Delivered)
+		d.DeliveredAt = &deliveredAt
+	}
+	if t.RequestInfo != nil {
+		d.Request = &api.HookDeliveryRequest{
+			URL:        t.RequestInfo.URL,
+			HTTPMethod: t.RequestInfo.HTTPMethod,
+			Headers:    t.RequestInfo.Headers,
+			Payload:    t.PayloadContent,
+		}
 	}
+	if t.ResponseInfo != nil {
+		d.Response = &api.HookDeliveryResponse{
+			StatusCode: t.ResponseInfo.Status,
+			Headers:    t.ResponseInfo.Headers,
+			Body:       t.ResponseInfo.Body,
+		}
+	}
+	return d
 }
 
 // ToGitHook convert git.Hook to api.GitHook
@@ -284,6 +432,7 @@ func ToOrganization(org *models.User) *api.Organization {
 		Location:                  org.Location,
 		Visibility:                org.Visibility.String(),
 		RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		RequireTwoFactor:          org.RequireTwoFactor,
 	}
 }
 
@@ -337,15 +486,58 @@ func ToTopicResponse(topic *models.Topic) *api.TopicResponse {
 	}
 }
 
+// ToStarList convert from models.StarList to api.StarList
+func ToStarList(list *models.StarList) *api.StarList {
+	return &api.StarList{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		Private:     list.IsPrivate,
+		NumRepos:    list.NumRepos,
+		Created:     list.CreatedUnix.AsTime(),
+		Updated:     list.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToSnippet convert from models.Snippet to api.Snippet, the files are read from
+// the snippet repository by the caller
+func ToSnippet(s *models.Snippet, files []*api.SnippetFile, doer *models.User) *api.Snippet {
+	return &api.Snippet{
+		ID:          s.ID,
+		UUID:        s.UUID,
+		Owner:       ToUser(s.Owner, doer),
+		Description: s.Description,
+		Visibility:  s.Visibility.String(),
+		HTMLURL:     s.HTMLURL(),
+		Files:       files,
+		NumComments: s.NumComments,
+		Created:     s.CreatedUnix.AsTime(),
+		Updated:     s.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToSnippetComment convert from models.SnippetComment to api.SnippetComment
+func ToSnippetComment(c *models.SnippetComment, doer *models.User) *api.SnippetComment {
+	return &api.SnippetComment{
+		ID:      c.ID,
+		Poster:  ToUser(c.Poster, doer),
+		Body:    c.Content,
+		Created: c.CreatedUnix.AsTime(),
+		Updated: c.UpdatedUnix.AsTime(),
+	}
+}
+
 // ToOAuth2Application convert from models.OAuth2Application to api.OAuth2Application
 func ToOAuth2Application(app *models.OAuth2Application) *api.OAuth2Application {
 	return &api.OAuth2Application{
-		ID:           app.ID,
-		Name:         app.Name,
-		ClientID:     app.ClientID,
-		ClientSecret: app.ClientSecret,
-		RedirectURIs: app.RedirectURIs,
-		Created:      app.CreatedUnix.AsTime(),
+		ID:                 app.ID,
+		Name:               app.Name,
+		ClientID:           app.ClientID,
+		ClientSecret:       app.ClientSecret,
+		RedirectURIs:       app.RedirectURIs,
+		ConfidentialClient: app.ConfidentialClient,
+		EnableDeviceFlow:   app.EnableDeviceFlow,
+		Created:            app.CreatedUnix.AsTime(),
 	}
 }
 
@@ -360,3 +552,14 @@ func ToLFSLock(l *models.LFSLock) *api.LFSLock {
 		},
 	}
 }
+
+// ToRepoLFSLock convert a LFSLock to api.RepoLFSLock
+func ToRepoLFSLock(l *models.LFSLock, doer *models.User) *api.RepoLFSLock {
+	return &api.RepoLFSLock{
+		ID:      l.ID,
+		Path:    l.Path,
+		Owner:   ToUser(l.Owner, doer),
+		Stale:   l.IsStale(),
+		Created: l.Created,
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToDeployment converts models.Deployment of the pull request to api.Deployment
+func ToDeployment(d *models.Deployment, pr *models.PullRequest) *api.Deployment {
+	apiDeployment := &api.Deployment{
+		ID:             d.ID,
+		PullRequest:    pr.Index,
+		Environment:    d.Environment,
+		EnvironmentURL: d.URL,
+		SHA:            d.SHA,
+		Active:         d.IsActive,
+		Created:        d.CreatedUnix.AsTime(),
+		Updated:        d.UpdatedUnix.AsTime(),
+	}
+	if err := d.LoadCreator(); err == nil {
+		apiDeployment.Creator = ToUser(d.Creator, nil)
+	}
+	return apiDeployment
+}
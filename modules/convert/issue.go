@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -16,6 +17,18 @@ import (
 // Required - Poster, Labels,
 // Optional - Milestone, Assignee, PullRequest
 func ToAPIIssue(issue *models.Issue) *api.Issue {
+	counts, err := models.CountReactions(models.FindReactionsOptions{
+		IssueID:   issue.ID,
+		CommentID: -1,
+	})
+	if err != nil {
+		log.Error("CountReactions: %v", err)
+		return toAPIIssue(issue, nil)
+	}
+	return toAPIIssue(issue, ToReactionSummary(counts))
+}
+
+func toAPIIssue(issue *models.Issue, reactions *api.ReactionSummary) *api.Issue {
 	if err := issue.LoadLabels(); err != nil {
 		return &api.Issue{}
 	}
@@ -39,8 +52,11 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		State:    issue.State(),
 		IsLocked: issue.IsLocked,
 		Comments: issue.NumComments,
+		Votes:    issue.NumVotes,
 		Created:  issue.CreatedUnix.AsTime(),
 		Updated:  issue.UpdatedUnix.AsTime(),
+
+		Reactions: reactions,
 	}
 
 	apiIssue.Repo = &api.RepositoryMeta{
@@ -90,9 +106,17 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 
 // ToAPIIssueList converts an IssueList to API format
 func ToAPIIssueList(il models.IssueList) []*api.Issue {
+	counts, err := il.GetReactionCounts()
+	if err != nil {
+		log.Error("GetReactionCounts: %v", err)
+	}
 	result := make([]*api.Issue, len(il))
 	for i := range il {
-		result[i] = ToAPIIssue(il[i])
+		var reactions *api.ReactionSummary
+		if err == nil {
+			reactions = ToReactionSummary(counts[il[i].ID])
+		}
+		result[i] = toAPIIssue(il[i], reactions)
 	}
 	return result
 }
@@ -6,11 +6,43 @@ package convert
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
 // ToComment converts a models.Comment to the api.Comment format
 func ToComment(c *models.Comment) *api.Comment {
+	apiComment := toComment(c)
+	counts, err := models.CountReactions(models.FindReactionsOptions{
+		IssueID:   c.IssueID,
+		CommentID: c.ID,
+	})
+	if err != nil {
+		log.Error("CountReactions[%d]: %v", c.ID, err)
+	} else {
+		apiComment.Reactions = ToReactionSummary(counts)
+	}
+	return apiComment
+}
+
+// ToCommentList converts a models.CommentList to the api.Comment format,
+// the reactions of all the comments are counted at once
+func ToCommentList(comments models.CommentList) []*api.Comment {
+	counts, err := comments.GetReactionCounts()
+	if err != nil {
+		log.Error("GetReactionCounts: %v", err)
+	}
+	apiComments := make([]*api.Comment, len(comments))
+	for i, c := range comments {
+		apiComments[i] = toComment(c)
+		if err == nil {
+			apiComments[i].Reactions = ToReactionSummary(counts[c.ID])
+		}
+	}
+	return apiComments
+}
+
+func toComment(c *models.Comment) *api.Comment {
 	return &api.Comment{
 		ID:       c.ID,
 		Poster:   ToUser(c.Poster, nil),
@@ -22,3 +54,17 @@ func ToComment(c *models.Comment) *api.Comment {
 		Updated:  c.UpdatedUnix.AsTime(),
 	}
 }
+
+// ToReactionSummary converts the number of reactions by type to the api.ReactionSummary format
+func ToReactionSummary(counts map[string]int64) *api.ReactionSummary {
+	if counts == nil {
+		counts = map[string]int64{}
+	}
+	summary := &api.ReactionSummary{
+		Counts: counts,
+	}
+	for _, count := range counts {
+		summary.TotalCount += count
+	}
+	return summary
+}
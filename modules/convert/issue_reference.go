@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"fmt"
+	"sort"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/references"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func issueNodeID(id int64) string {
+	return fmt.Sprintf("issue:%d", id)
+}
+
+// ToIssueReferenceGraph converts an IssueReferenceGraph to API format,
+// issues come first ordered by ID and commits follow in order of appearance
+func ToIssueReferenceGraph(graph *models.IssueReferenceGraph) *api.IssueReferenceGraph {
+	ids := make([]int64, 0, len(graph.Issues))
+	for id := range graph.Issues {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := &api.IssueReferenceGraph{
+		Nodes: make([]*api.IssueReferenceNode, 0, len(graph.Issues)),
+		Edges: make([]*api.IssueReferenceEdge, 0, len(graph.References)),
+	}
+	for _, id := range ids {
+		issue := graph.Issues[id]
+		node := &api.IssueReferenceNode{
+			ID:   issueNodeID(issue.ID),
+			Type: "issue",
+			Repository: &api.RepositoryMeta{
+				ID:       issue.Repo.ID,
+				Name:     issue.Repo.Name,
+				Owner:    issue.Repo.OwnerName,
+				FullName: issue.Repo.FullName(),
+			},
+			Index:   issue.Index,
+			Title:   issue.Title,
+			State:   issue.State(),
+			HTMLURL: issue.HTMLURL(),
+		}
+		if issue.IsPull {
+			node.Type = "pull"
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+
+	commits := make(map[string]bool)
+	for _, ref := range graph.References {
+		edge := &api.IssueReferenceEdge{
+			From:    issueNodeID(ref.RefIssueID),
+			To:      issueNodeID(ref.IssueID),
+			Action:  "none",
+			Created: ref.CreatedUnix.AsTime(),
+		}
+		switch ref.Type {
+		case models.CommentTypeIssueRef:
+			edge.Type = "issue"
+		case models.CommentTypePullRef:
+			edge.Type = "pull"
+		case models.CommentTypeCommentRef:
+			edge.Type = "comment"
+			edge.CommentID = ref.RefCommentID
+		case models.CommentTypeCommitRef:
+			edge.Type = "commit"
+			edge.From = "commit:" + ref.CommitSHA
+			if !commits[ref.CommitSHA] {
+				commits[ref.CommitSHA] = true
+				result.Nodes = append(result.Nodes, &api.IssueReferenceNode{
+					ID:   edge.From,
+					Type: "commit",
+					SHA:  ref.CommitSHA,
+				})
+			}
+		}
+		switch ref.RefAction {
+		case references.XRefActionCloses:
+			edge.Action = "closes"
+		case references.XRefActionReopens:
+			edge.Action = "reopens"
+		}
+		result.Edges = append(result.Edges, edge)
+	}
+	return result
+}
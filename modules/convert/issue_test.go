@@ -50,3 +50,30 @@ func TestMilestone_APIFormat(t *testing.T) {
 		Deadline:     milestone.DeadlineUnix.AsTimePtr(),
 	}, *ToAPIMilestone(milestone))
 }
+
+func TestToAPIIssueList_Reactions(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	issues := models.IssueList{
+		models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue),
+		models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue),
+	}
+	apiIssues := ToAPIIssueList(issues)
+	assert.Len(t, apiIssues, 2)
+	assert.Equal(t, ToAPIIssue(issues[0]).Reactions, apiIssues[0].Reactions)
+	assert.Equal(t, &api.ReactionSummary{TotalCount: 1, Counts: map[string]int64{"eyes": 1}}, apiIssues[0].Reactions)
+	assert.Equal(t, &api.ReactionSummary{TotalCount: 0, Counts: map[string]int64{}}, apiIssues[1].Reactions)
+}
+
+func TestToCommentList_Reactions(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	comments := models.CommentList{
+		models.AssertExistsAndLoadBean(t, &models.Comment{ID: 1}).(*models.Comment),
+		models.AssertExistsAndLoadBean(t, &models.Comment{ID: 2}).(*models.Comment),
+	}
+	assert.NoError(t, comments.LoadPosters())
+	apiComments := ToCommentList(comments)
+	assert.Len(t, apiComments, 2)
+	assert.Equal(t, ToComment(comments[0]).Reactions, apiComments[0].Reactions)
+	assert.Equal(t, ToComment(comments[1]).Reactions, apiComments[1].Reactions)
+	assert.EqualValues(t, 2, apiComments[1].Reactions.TotalCount)
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAssigneeWorkload converts an AssigneeWorkload to API format
+func ToAssigneeWorkload(w *models.AssigneeWorkload, doer *models.User) *api.AssigneeWorkload {
+	return &api.AssigneeWorkload{
+		Assignee:   ToUser(w.Assignee, doer),
+		OpenIssues: w.OpenIssues,
+		OpenPulls:  w.OpenPulls,
+		Age: api.WorkloadAge{
+			UnderWeek:    w.Age.UnderWeek,
+			UnderMonth:   w.Age.UnderMonth,
+			UnderQuarter: w.Age.UnderQuarter,
+			Older:        w.Age.Older,
+		},
+		PendingReviews: w.PendingReviews,
+	}
+}
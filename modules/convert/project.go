@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAPIProject converts a Project to API format
+func ToAPIProject(p *models.Project) *api.Project {
+	apiProject := &api.Project{
+		ID:           p.ID,
+		Title:        p.Title,
+		Description:  p.Description,
+		State:        api.StateOpen,
+		BoardType:    p.BoardType.Name(),
+		OpenIssues:   p.NumOpenIssues(),
+		ClosedIssues: p.NumClosedIssues(),
+		Created:      p.CreatedUnix.AsTime(),
+		Updated:      p.UpdatedUnix.AsTime(),
+	}
+	if p.IsClosed {
+		apiProject.State = api.StateClosed
+		apiProject.Closed = p.ClosedDateUnix.AsTimePtr()
+	}
+	return apiProject
+}
+
+// ToAPIProjectBoard converts a ProjectBoard to API format
+func ToAPIProjectBoard(b *models.ProjectBoard) *api.ProjectBoard {
+	return &api.ProjectBoard{
+		ID:       b.ID,
+		Title:    b.Title,
+		Default:  b.Default,
+		Sorting:  int(b.Sorting),
+		AutoMove: b.AutoMove.Name(),
+		Created:  b.CreatedUnix.AsTime(),
+		Updated:  b.UpdatedUnix.AsTime(),
+	}
+}
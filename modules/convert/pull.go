@@ -77,6 +77,13 @@ func ToAPIPullRequest(pr *models.PullRequest) *api.PullRequest {
 		},
 	}
 
+	if apiPullRequest.IsFirstTimeContributor, err = pr.IsFirstTimeContributor(); err != nil {
+		log.Error("IsFirstTimeContributor[%d]: %v", pr.ID, err)
+	}
+	if apiPullRequest.IsExternalContribution, err = pr.IsExternalContribution(); err != nil {
+		log.Error("IsExternalContribution[%d]: %v", pr.ID, err)
+	}
+
 	baseBranch, err = repo_module.GetBranch(pr.BaseRepo, pr.BaseBranch)
 	if err != nil && !git.IsErrBranchNotExist(err) {
 		log.Error("GetBranch[%s]: %v", pr.BaseBranch, err)
@@ -106,10 +113,15 @@ func ToAPIPullRequest(pr *models.PullRequest) *api.PullRequest {
 		}
 		defer headGitRepo.Close()
 
-		headBranch, err = headGitRepo.GetBranch(pr.HeadBranch)
-		if err != nil && !git.IsErrBranchNotExist(err) {
-			log.Error("GetBranch[%s]: %v", pr.HeadBranch, err)
-			return nil
+		if pr.Flow == models.PullRequestFlowAGit {
+			// AGit pull requests have no head branch, only the pull ref
+			err = git.ErrBranchNotExist{Name: pr.HeadBranch}
+		} else {
+			headBranch, err = headGitRepo.GetBranch(pr.HeadBranch)
+			if err != nil && !git.IsErrBranchNotExist(err) {
+				log.Error("GetBranch[%s]: %v", pr.HeadBranch, err)
+				return nil
+			}
 		}
 
 		if git.IsErrBranchNotExist(err) {
@@ -36,6 +36,16 @@ func ToPullReview(r *models.Review, doer *models.User) (*api.PullReview, error)
 		HTMLPullURL:       r.Issue.HTMLURL(),
 	}
 
+	if len(r.Checklist) > 0 {
+		result.Checklist = make([]*api.PullReviewChecklistItem, len(r.Checklist))
+		for i, item := range r.Checklist {
+			result.Checklist[i] = &api.PullReviewChecklistItem{
+				Text:    item.Text,
+				Checked: item.Checked,
+			}
+		}
+	}
+
 	switch r.Type {
 	case models.ReviewTypeApprove:
 		result.State = api.ReviewStateApproved
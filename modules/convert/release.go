@@ -46,3 +46,15 @@ func ToReleaseAttachment(a *models.Attachment) *api.Attachment {
 		DownloadURL:   a.DownloadURL(),
 	}
 }
+
+// ToAttachmentUpload convert a models.AttachmentUpload to api.AttachmentUpload
+func ToAttachmentUpload(u *models.AttachmentUpload) *api.AttachmentUpload {
+	return &api.AttachmentUpload{
+		UUID:     u.UUID,
+		Name:     u.Name,
+		Size:     u.Size,
+		Received: u.Received,
+		Created:  u.CreatedUnix.AsTime(),
+		Updated:  u.UpdatedUnix.AsTime(),
+	}
+}
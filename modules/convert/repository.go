@@ -5,7 +5,10 @@
 package convert
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -44,14 +47,16 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			EnableIssueVoting:                config.EnableVoting,
 		}
 	} else if unit, err := repo.GetUnit(models.UnitTypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
 		hasIssues = true
 		externalTracker = &api.ExternalTracker{
-			ExternalTrackerURL:    config.ExternalTrackerURL,
-			ExternalTrackerFormat: config.ExternalTrackerFormat,
-			ExternalTrackerStyle:  config.ExternalTrackerStyle,
+			ExternalTrackerURL:           config.ExternalTrackerURL,
+			ExternalTrackerFormat:        config.ExternalTrackerFormat,
+			ExternalTrackerStyle:         config.ExternalTrackerStyle,
+			ExternalTrackerRegexpPattern: config.ExternalTrackerRegexpPattern,
 		}
 	}
 	hasWiki := false
@@ -86,6 +91,13 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	if _, err := repo.GetUnit(models.UnitTypeProjects); err == nil {
 		hasProjects = true
 	}
+	readmePath := ""
+	showWikiHome := false
+	if unit, err := repo.GetUnit(models.UnitTypeCode); err == nil {
+		config := unit.CodeConfig()
+		readmePath = config.ReadmePath
+		showWikiHome = config.ShowWikiHome
+	}
 
 	if err := repo.GetOwner(); err != nil {
 		return nil
@@ -94,9 +106,40 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	numReleases, _ := models.GetReleaseCountByRepoID(repo.ID, models.FindReleasesOptions{IncludeDrafts: false, IncludeTags: false})
 
 	mirrorInterval := ""
+	var mirrorUpdated, mirrorNextUpdate, mirrorLastErrorAt *time.Time
+	var mirrorLastError string
+	var mirrorFailureCount int
 	if repo.IsMirror {
 		if err := repo.GetMirror(); err == nil {
 			mirrorInterval = repo.Mirror.Interval.String()
+			updated := repo.Mirror.UpdatedUnix.AsTime()
+			mirrorUpdated = &updated
+			if repo.Mirror.NextUpdateUnix > 0 {
+				nextUpdate := repo.Mirror.NextUpdateUnix.AsTime()
+				mirrorNextUpdate = &nextUpdate
+			}
+			if mode >= models.AccessModeWrite {
+				mirrorLastError = repo.Mirror.LastError
+				mirrorFailureCount = repo.Mirror.FailureCount
+				if repo.Mirror.LastErrorUnix > 0 {
+					lastErrorAt := repo.Mirror.LastErrorUnix.AsTime()
+					mirrorLastErrorAt = &lastErrorAt
+				}
+			}
+		}
+	}
+
+	var transfer *api.RepoTransfer
+	if repo.Status == models.RepositoryPendingTransfer {
+		t, err := models.GetPendingRepositoryTransfer(repo)
+		if err != nil && !models.IsErrNoPendingTransfer(err) {
+			log.Warn("GetPendingRepositoryTransfer: %v", err)
+		} else if t != nil {
+			if err := t.LoadAttributes(); err != nil {
+				log.Warn("LoadAttributes of RepoTransfer: %v", err)
+			} else {
+				transfer = ToRepoTransfer(t)
+			}
 		}
 	}
 
@@ -143,7 +186,30 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		AllowSquash:               allowSquash,
 		DefaultMergeStyle:         string(defaultMergeStyle),
 		AvatarURL:                 repo.AvatarLink(),
+		AccentColor:               repo.AccentColor,
 		Internal:                  !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
 		MirrorInterval:            mirrorInterval,
+		MirrorUpdated:             mirrorUpdated,
+		MirrorNextUpdate:          mirrorNextUpdate,
+		MirrorLastError:           mirrorLastError,
+		MirrorLastErrorAt:         mirrorLastErrorAt,
+		MirrorFailureCount:        mirrorFailureCount,
+		ReadmePath:                readmePath,
+		ShowWikiHome:              showWikiHome,
+		RepoTransfer:              transfer,
+	}
+}
+
+// ToRepoTransfer convert a models.RepoTransfer to a structs.RepoTransfer
+func ToRepoTransfer(t *models.RepoTransfer) *api.RepoTransfer {
+	teams := make([]*api.Team, len(t.Teams))
+	for i := range t.Teams {
+		teams[i] = ToTeam(t.Teams[i])
+	}
+
+	return &api.RepoTransfer{
+		Doer:      ToUser(t.Doer, nil),
+		Recipient: ToUser(t.Recipient, nil),
+		Teams:     teams,
 	}
 }
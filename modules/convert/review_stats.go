@@ -0,0 +1,22 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToReviewerStats converts a ReviewerSummary to API format
+func ToReviewerStats(s *models.ReviewerSummary, doer *models.User) *api.ReviewerStats {
+	return &api.ReviewerStats{
+		Reviewer:          ToUser(s.Reviewer, doer),
+		Reviews:           s.Reviews,
+		Approvals:         s.Approvals,
+		ChangesRequested:  s.ChangesRequested,
+		Comments:          s.Comments,
+		AverageTurnaround: s.AverageTurnaround(),
+	}
+}
@@ -93,5 +93,7 @@ func User2UserSettings(user *models.User) api.UserSettings {
 		HideEmail:     user.KeepEmailPrivate,
 		HideActivity:  user.KeepActivityPrivate,
 		DiffViewStyle: user.DiffViewStyle,
+
+		HideActivityFromAnonymous: user.KeepActivitySignedInOnly,
 	}
 }
@@ -33,6 +33,8 @@ func ToGitServiceType(value string) structs.GitServiceType {
 		return structs.GitlabService
 	case "gogs":
 		return structs.GogsService
+	case "bitbucket":
+		return structs.BitbucketService
 	default:
 		return structs.PlainGitService
 	}
@@ -53,6 +53,14 @@ type TaskTableRow struct {
 	Next      time.Time
 	Prev      time.Time
 	ExecTimes int64
+	Enabled   bool
+	Running   bool
+	// LastRun is the start of the last run, whether it was scheduled or started by hand
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastStatus   TaskStatus
+	// LastMessage is the error of the last run if it failed or was aborted
+	LastMessage string
 }
 
 // TaskTable represents a table of tasks
@@ -86,6 +94,13 @@ func ListTasks() TaskTable {
 			Next:      next,
 			Prev:      prev,
 			ExecTimes: task.ExecTimes,
+			Enabled:   task.IsEnabled(),
+			Running:   task.IsRunning(),
+
+			LastRun:      task.lastRun,
+			LastDuration: task.lastDuration,
+			LastStatus:   task.lastStatus,
+			LastMessage:  task.lastMessage,
 		})
 		task.lock.Unlock()
 	}
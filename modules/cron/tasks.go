@@ -9,12 +9,14 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tasklog"
 )
 
 var lock = sync.Mutex{}
@@ -22,6 +24,16 @@ var started = false
 var tasks = []*Task{}
 var tasksMap = map[string]*Task{}
 
+// TaskStatus represents the outcome of a run of a task
+type TaskStatus string
+
+// Outcomes of a run of a task
+const (
+	TaskStatusSuccess TaskStatus = "success"
+	TaskStatusError   TaskStatus = "error"
+	TaskStatusAborted TaskStatus = "aborted"
+)
+
 // Task represents a Cron task
 type Task struct {
 	lock      sync.Mutex
@@ -29,6 +41,11 @@ type Task struct {
 	config    Config
 	fun       func(context.Context, *models.User, Config) error
 	ExecTimes int64
+
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastStatus   TaskStatus
+	lastMessage  string
 }
 
 // DoRunAtStart returns if this task should run at the start
@@ -41,6 +58,11 @@ func (t *Task) IsEnabled() bool {
 	return t.config.IsEnabled()
 }
 
+// IsRunning returns if this task is currently running
+func (t *Task) IsRunning() bool {
+	return taskStatusTable.IsRunning(t.Name)
+}
+
 // GetConfig will return a copy of the task's config
 func (t *Task) GetConfig() Config {
 	if reflect.TypeOf(t.config).Kind() == reflect.Ptr {
@@ -71,13 +93,22 @@ func (t *Task) RunWithUser(doer *models.User, config Config) {
 	}
 	t.ExecTimes++
 	t.lock.Unlock()
+	start := time.Now()
+	status, message := TaskStatusSuccess, ""
 	defer func() {
-		taskStatusTable.Stop(t.Name)
 		if err := recover(); err != nil {
 			// Recover a panic within the
 			combinedErr := fmt.Errorf("%s\n%s", err, log.Stack(2))
 			log.Error("PANIC whilst running task: %s Value: %v", t.Name, combinedErr)
+			status, message = TaskStatusError, fmt.Sprint(err)
 		}
+		t.lock.Lock()
+		t.lastRun = start
+		t.lastDuration = time.Since(start)
+		t.lastStatus = status
+		t.lastMessage = message
+		t.lock.Unlock()
+		taskStatusTable.Stop(t.Name)
 	}()
 	graceful.GetManager().RunWithShutdownContext(func(baseCtx context.Context) {
 		ctx, cancel := context.WithCancel(baseCtx)
@@ -85,19 +116,27 @@ func (t *Task) RunWithUser(doer *models.User, config Config) {
 		pm := process.GetManager()
 		pid := pm.Add(config.FormatMessage(t.Name, "process", doer), cancel)
 		defer pm.Remove(pid)
-		if err := t.fun(ctx, doer, config); err != nil {
+		tl := tasklog.GetManager().Start(t.Name)
+		defer tl.Close()
+		tl.Info("Started by %s", doer.Name)
+		if err := t.fun(tasklog.WithLog(ctx, tl), doer, config); err != nil {
 			if models.IsErrCancelled(err) {
-				message := err.(models.ErrCancelled).Message
+				message = err.(models.ErrCancelled).Message
+				status = TaskStatusAborted
+				tl.Warn("Aborted: %s", message)
 				if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "aborted", doer, message)); err != nil {
 					log.Error("CreateNotice: %v", err)
 				}
 				return
 			}
+			status, message = TaskStatusError, err.Error()
+			tl.Error("Failed: %v", err)
 			if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "error", doer, err)); err != nil {
 				log.Error("CreateNotice: %v", err)
 			}
 			return
 		}
+		tl.Info("Finished")
 		if config.DoNoticeOnSuccess() {
 			if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "finished", doer)); err != nil {
 				log.Error("CreateNotice: %v", err)
@@ -12,7 +12,9 @@ import (
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/diskusage"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	snapshot_service "code.gitea.io/gitea/services/snapshot"
 )
 
 func registerUpdateMirrorTask() {
@@ -125,6 +127,78 @@ func registerCleanupHookTaskTable() {
 	})
 }
 
+func registerRepoSnapshots() {
+	RegisterTaskFatal("repo_snapshots", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return snapshot_service.CreateSnapshots(ctx)
+	})
+}
+
+func registerUpdateReviewerStats() {
+	RegisterTaskFatal("update_reviewer_stats", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.UpdateReviewerStats(ctx)
+	})
+}
+
+func registerRepoEventsCleanup() {
+	RegisterTaskFatal("repo_events_cleanup", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.DeleteOldRepoEvents(ctx, setting.Webhook.EventRetention)
+	})
+}
+
+func registerGitOperationsCleanup() {
+	RegisterTaskFatal("git_operations_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 90 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return models.DeleteOldGitOperations(ctx, realConfig.OlderThan)
+	})
+}
+
+func registerRepoSizeSamples() {
+	RegisterTaskFatal("repo_size_samples", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@weekly",
+		},
+		OlderThan: 365 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return diskusage.SampleDiskUsage(ctx, realConfig.OlderThan)
+	})
+}
+
+func registerAttachmentUploadsCleanup() {
+	RegisterTaskFatal("attachment_uploads_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 6h",
+		},
+		OlderThan: 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return models.DeleteStaleAttachmentUploads(ctx, realConfig.OlderThan)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
@@ -136,4 +210,10 @@ func initBasicTasks() {
 		registerUpdateMigrationPosterID()
 	}
 	registerCleanupHookTaskTable()
+	registerRepoSnapshots()
+	registerUpdateReviewerStats()
+	registerRepoEventsCleanup()
+	registerGitOperationsCleanup()
+	registerRepoSizeSamples()
+	registerAttachmentUploadsCleanup()
 }
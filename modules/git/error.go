@@ -176,3 +176,25 @@ func IsErrMoreThanOne(err error) bool {
 func (err *ErrMoreThanOne) Error() string {
 	return fmt.Sprintf("ErrMoreThanOne Error: %v: %s\n%s", err.Err, err.StdErr, err.StdOut)
 }
+
+// ErrFetchRemote represents an error if a ref of a remote repository could not be fetched
+type ErrFetchRemote struct {
+	Ref    string
+	StdErr string
+	Err    error
+}
+
+// IsErrFetchRemote checks if an error is a ErrFetchRemote
+func IsErrFetchRemote(err error) bool {
+	_, ok := err.(*ErrFetchRemote)
+	return ok
+}
+
+func (err *ErrFetchRemote) Error() string {
+	return fmt.Sprintf("FetchRemote Error: unable to fetch %s: %v: %s", err.Ref, err.Err, err.StdErr)
+}
+
+// Unwrap unwraps the underlying error
+func (err *ErrFetchRemote) Unwrap() error {
+	return err.Err
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -97,6 +98,7 @@ type CloneRepoOptions struct {
 	Shared     bool
 	NoCheckout bool
 	Depth      int
+	Env        []string
 }
 
 // Clone clones original repository to target path.
@@ -147,7 +149,7 @@ func CloneWithArgs(ctx context.Context, from, to string, args []string, opts Clo
 		opts.Timeout = -1
 	}
 
-	_, err = cmd.RunTimeout(opts.Timeout)
+	_, err = cmd.RunInDirTimeoutEnv(opts.Env, opts.Timeout, "")
 	return err
 }
 
@@ -396,3 +398,26 @@ func GetDivergingCommits(repoPath string, baseBranch string, targetBranch string
 
 	return DivergeObject{ahead, behind}, nil
 }
+
+// GetDivergingCommitsBetweenRepos returns the number of commits the head commit of another repository, e.g. a fork,
+// is ahead or behind the base commit of the repository. The objects of the head repository are only read.
+func GetDivergingCommitsBetweenRepos(baseRepoPath, baseCommitID, headRepoPath, headCommitID string) (DivergeObject, error) {
+	env := append(os.Environ(), "GIT_ALTERNATE_OBJECT_DIRECTORIES="+filepath.Join(headRepoPath, "objects"))
+	stdout, err := NewCommand("rev-list", "--left-right", "--count", baseCommitID+"..."+headCommitID).RunInDirWithEnv(baseRepoPath, env)
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) != 2 {
+		return DivergeObject{}, fmt.Errorf("unexpected rev-list output: %q", stdout)
+	}
+	behind, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	ahead, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	return DivergeObject{ahead, behind}, nil
+}
@@ -13,6 +13,9 @@ import (
 // BranchPrefix base dir of the branch information file store on git
 const BranchPrefix = "refs/heads/"
 
+// ForPrefix base dir of the refs pushed to create or update AGit pull requests
+const ForPrefix = "refs/for/"
+
 // IsReferenceExist returns true if given reference exists in the repository.
 func IsReferenceExist(repoPath, name string) bool {
 	_, err := NewCommand("show-ref", "--verify", "--", name).RunInDir(repoPath)
@@ -134,6 +137,12 @@ func (repo *Repository) CreateBranch(branch, oldbranchOrCommit string) error {
 	return err
 }
 
+// RenameBranch renames a branch, keeping its reflog
+func (repo *Repository) RenameBranch(from, to string) error {
+	_, err := NewCommand("branch", "-m", "--", from, to).RunInDir(repo.Path)
+	return err
+}
+
 // AddRemote adds a new remote to repository.
 func (repo *Repository) AddRemote(name, url string, fetch bool) error {
 	cmd := NewCommand("remote", "add")
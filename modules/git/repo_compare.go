@@ -10,12 +10,17 @@ import (
 	"container/list"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	logger "code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // CompareInfo represents needed information for comparing references.
@@ -108,6 +113,95 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 	return compareInfo, nil
 }
 
+// remoteCredentialHelper answers the credential requests of git with the credentials given in the environment,
+// so that they are neither stored in the configuration of a repository nor visible in the arguments of a process.
+const remoteCredentialHelper = `!f() { test "$1" = get && echo "username=${GITEA_REMOTE_USERNAME}" && echo "password=${GITEA_REMOTE_PASSWORD}"; }; f`
+
+// GetRemoteCompareInfo generates and returns compare information between a base branch of the repository
+// and a ref of the repository at remoteURL. The ref is fetched into a temporary repository which borrows the
+// objects of the repository through its alternates, so the repository itself is never modified.
+// The temporary repository is returned to read the compared commits and has to be released with the returned cleanup function.
+func (repo *Repository) GetRemoteCompareInfo(remoteURL, baseBranch, remoteRef string, timeout time.Duration) (*Repository, *CompareInfo, func(), error) {
+	if remoteRef == "" || strings.HasPrefix(remoteRef, "-") || strings.ContainsAny(remoteRef, ": ") {
+		return nil, nil, nil, ErrNotExist{ID: remoteRef}
+	}
+
+	baseCommitID, err := GetFullCommitID(repo.Path, baseBranch)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	env := os.Environ()
+	if u, err := url.Parse(remoteURL); err == nil && u.User != nil {
+		password, _ := u.User.Password()
+		env = append(env,
+			"GITEA_REMOTE_USERNAME="+u.User.Username(),
+			"GITEA_REMOTE_PASSWORD="+password,
+		)
+		u.User = nil
+		remoteURL = u.String()
+	}
+
+	tmpPath, err := ioutil.TempDir(os.TempDir(), "gitea-compare-")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("TempDir: %v", err)
+	}
+	cleanup := func() {
+		if err := util.RemoveAll(tmpPath); err != nil {
+			logger.Error("GetRemoteCompareInfo: RemoveAll: %v", err)
+		}
+	}
+
+	if err := InitRepository(tmpPath, true); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("InitRepository: %v", err)
+	}
+	objectsPath, err := filepath.Abs(filepath.Join(repo.Path, "objects"))
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpPath, "objects", "info", "alternates"), []byte(objectsPath+"\n"), 0600); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("write alternates: %v", err)
+	}
+
+	tmpRef := "refs/compare/head"
+	stderr := new(bytes.Buffer)
+	if err := NewCommand("-c", "credential.helper=", "-c", "credential.helper="+remoteCredentialHelper,
+		"fetch", "--no-tags", "--", remoteURL, remoteRef+":"+tmpRef).
+		RunInDirTimeoutEnvPipeline(env, timeout, tmpPath, nil, stderr); err != nil {
+		cleanup()
+		return nil, nil, nil, &ErrFetchRemote{
+			Ref:    remoteRef,
+			StdErr: stderr.String(),
+			Err:    err,
+		}
+	}
+
+	tmpRepo, err := OpenRepository(tmpPath)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	release := func() {
+		tmpRepo.Close()
+		cleanup()
+	}
+
+	headCommitID, err := GetFullCommitID(tmpPath, tmpRef)
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	compareInfo, err := tmpRepo.GetCompareInfo(tmpPath, baseCommitID, headCommitID)
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return tmpRepo, compareInfo, release, nil
+}
+
 type lineCountWriter struct {
 	numLines int
 }
@@ -203,6 +297,62 @@ func parseDiffStat(stdout string) (numFiles, totalAdditions, totalDeletions int,
 	return
 }
 
+// DiffFileStat represents the changes of a file between two revisions
+type DiffFileStat struct {
+	Name      string
+	Status    string
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// GetDiffFileStats returns the changes of each file between the given revisions
+func (repo *Repository) GetDiffFileStats(base, head string) ([]*DiffFileStat, error) {
+	stdout, err := NewCommand("diff", "--name-status", "--no-renames", "-z", base, head).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(strings.TrimSuffix(stdout, "\x00"), "\x00")
+	stats := make([]*DiffFileStat, 0, len(fields)/2)
+	statsMap := make(map[string]*DiffFileStat, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		stat := &DiffFileStat{Name: fields[i+1]}
+		switch fields[i] {
+		case "A":
+			stat.Status = "added"
+		case "D":
+			stat.Status = "deleted"
+		default:
+			stat.Status = "modified"
+		}
+		stats = append(stats, stat)
+		statsMap[stat.Name] = stat
+	}
+
+	// Now add the numbers of changed lines: "<additions>\t<deletions>\t<name>"
+	stdout, err = NewCommand("diff", "--numstat", "--no-renames", "-z", base, head).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(stdout, "\x00") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		stat, ok := statsMap[parts[2]]
+		if !ok {
+			continue
+		}
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.IsBinary = true
+			continue
+		}
+		stat.Additions, _ = strconv.Atoi(parts[0])
+		stat.Deletions, _ = strconv.Atoi(parts[1])
+	}
+	return stats, nil
+}
+
 // GetDiffOrPatch generates either diff or formatted patch data between given revisions
 func (repo *Repository) GetDiffOrPatch(base, head string, w io.Writer, formatted bool) error {
 	if formatted {
@@ -7,7 +7,9 @@ package git
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/modules/util"
@@ -31,3 +33,52 @@ func TestGetFormatPatch(t *testing.T) {
 	assert.Regexp(t, "^From 8d92fc95", patch)
 	assert.Contains(t, patch, "Subject: [PATCH] Add file2.txt")
 }
+
+func TestGetDiffFileStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	stats, err := repo.GetDiffFileStats("8d92fc95^", "8d92fc95")
+	assert.NoError(t, err)
+	assert.Equal(t, []*DiffFileStat{
+		{Name: "file2.txt", Status: "added", Additions: 1},
+	}, stats)
+}
+
+func TestGetRemoteCompareInfo(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestGetRemoteCompareInfo")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+	repo, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer repo.Close()
+	bareRepo1Path, err = filepath.Abs(bareRepo1Path)
+	assert.NoError(t, err)
+
+	compareRepo, compareInfo, cleanup, err := repo.GetRemoteCompareInfo(bareRepo1Path, "8d92fc95", "master", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "feaf4ba6bc635fec442f46ddd4512416ec43c2c2", compareInfo.HeadCommitID)
+	assert.Equal(t, 4, compareInfo.Commits.Len())
+	stats, err := compareRepo.GetDiffFileStats(compareInfo.MergeBase, compareInfo.HeadCommitID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, stats)
+	tmpPath := compareRepo.Path
+	cleanup()
+	_, err = os.Stat(tmpPath)
+	assert.True(t, os.IsNotExist(err))
+
+	_, _, _, err = repo.GetRemoteCompareInfo(bareRepo1Path, "8d92fc95", "unknown", -1)
+	assert.True(t, IsErrFetchRemote(err))
+	_, _, _, err = repo.GetRemoteCompareInfo(bareRepo1Path, "8d92fc95", "--upload-pack=touch", -1)
+	assert.True(t, IsErrNotExist(err))
+
+	remotes, err := NewCommand("remote").RunInDir(clonedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "origin", strings.TrimSpace(remotes))
+	refs, err := NewCommand("for-each-ref", "refs/remotes/").RunInDir(clonedPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, refs, "remote_compare_")
+}
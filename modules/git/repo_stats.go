@@ -151,3 +151,212 @@ func (repo *Repository) GetCodeActivityStats(fromTime time.Time, branch string)
 
 	return stats, nil
 }
+
+// CommitStat represents the changes of a commit for the repository statistics
+type CommitStat struct {
+	AuthorName  string
+	AuthorEmail string
+	AuthorTime  time.Time
+	Additions   int64
+	Deletions   int64
+}
+
+// GetCommitStats returns the changes of the non-merge commits reachable from revision, newest first
+func (repo *Repository) GetCommitStats(revision string) ([]*CommitStat, error) {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+
+	commits := make([]*CommitStat, 0, 50)
+	stderr := new(strings.Builder)
+	err = NewCommand("log", "--numstat", "--no-merges", "--pretty=format:---%n%aN%n%aE%n%at", revision, "--").RunInDirTimeoutEnvFullPipelineFunc(
+		nil, -1, repo.Path,
+		stdoutWriter, stderr, nil,
+		func(ctx context.Context, cancel context.CancelFunc) error {
+			_ = stdoutWriter.Close()
+
+			scanner := bufio.NewScanner(stdoutReader)
+			scanner.Split(bufio.ScanLines)
+			var commit *CommitStat
+			p := 0
+			for scanner.Scan() {
+				l := strings.TrimSpace(scanner.Text())
+				if l == "---" {
+					commit = &CommitStat{}
+					commits = append(commits, commit)
+					p = 1
+					continue
+				} else if p == 0 {
+					continue
+				}
+				p++
+				switch p {
+				case 2: // Author
+					commit.AuthorName = l
+				case 3: // E-mail
+					commit.AuthorEmail = strings.ToLower(l)
+				case 4: // Author date
+					if unix, err := strconv.ParseInt(l, 10, 64); err == nil {
+						commit.AuthorTime = time.Unix(unix, 0).UTC()
+					}
+				default: // Changed file
+					if parts := strings.Fields(l); len(parts) >= 3 {
+						if c, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
+							commit.Additions += c
+						}
+						if c, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+							commit.Deletions += c
+						}
+					}
+				}
+			}
+
+			_ = stdoutReader.Close()
+			return scanner.Err()
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get GetCommitStats for repository.\nError: %w\nStderr: %s", err, stderr)
+	}
+	return commits, nil
+}
+
+// DirectoryStat represents the size and the history intensity of a directory
+type DirectoryStat struct {
+	Path string
+	// Size is the total size of the blobs below the directory
+	Size      int64
+	FileCount int64
+	// CommitCount is the number of commits which changed files below the directory
+	CommitCount int64
+}
+
+// GetDirectoryStats returns the stats of the directories below treePath at the given commit
+// which are at most depth levels deep, ordered by path. Only commits since the given time
+// are counted, all of them if it is zero.
+func (repo *Repository) GetDirectoryStats(commitID, treePath string, depth int, since time.Time) ([]*DirectoryStat, error) {
+	treePath = strings.Trim(treePath, "/")
+	prefix := ""
+	if treePath != "" {
+		prefix = treePath + "/"
+	}
+
+	// directoryKeys returns the directories of the file which should be reported
+	directoryKeys := func(file string) []string {
+		if !strings.HasPrefix(file, prefix) {
+			return nil
+		}
+		parts := strings.Split(strings.TrimPrefix(file, prefix), "/")
+		parts = parts[:len(parts)-1]
+		if len(parts) > depth {
+			parts = parts[:depth]
+		}
+		keys := make([]string, len(parts))
+		for i := range parts {
+			keys[i] = prefix + strings.Join(parts[:i+1], "/")
+		}
+		return keys
+	}
+
+	args := []string{"ls-tree", "-r", "-l", "-z", commitID}
+	if treePath != "" {
+		args = append(args, "--", treePath)
+	}
+	stdout, err := NewCommand(args...).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*DirectoryStat)
+	for _, entry := range strings.Split(string(stdout), "\x00") {
+		tab := strings.IndexByte(entry, '\t')
+		if tab < 0 {
+			continue
+		}
+		// <mode> SP <type> SP <object> SP+ <size> TAB <file>
+		fields := strings.Fields(entry[:tab])
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		for _, key := range directoryKeys(entry[tab+1:]) {
+			stat, ok := byPath[key]
+			if !ok {
+				stat = &DirectoryStat{Path: key}
+				byPath[key] = stat
+			}
+			stat.Size += size
+			stat.FileCount++
+		}
+	}
+
+	if len(byPath) > 0 {
+		stdoutReader, stdoutWriter, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = stdoutReader.Close()
+			_ = stdoutWriter.Close()
+		}()
+
+		args = []string{"log", "--name-only", "--no-renames", "--pretty=format:%x00"}
+		if !since.IsZero() {
+			args = append(args, fmt.Sprintf("--since=%s", since.Format(time.RFC3339)))
+		}
+		args = append(args, commitID, "--")
+		if treePath != "" {
+			args = append(args, treePath)
+		}
+
+		stderr := new(strings.Builder)
+		err = NewCommand(args...).RunInDirTimeoutEnvFullPipelineFunc(
+			nil, -1, repo.Path,
+			stdoutWriter, stderr, nil,
+			func(ctx context.Context, cancel context.CancelFunc) error {
+				_ = stdoutWriter.Close()
+
+				scanner := bufio.NewScanner(stdoutReader)
+				scanner.Split(bufio.ScanLines)
+				touched := make(map[string]bool)
+				count := func() {
+					for key := range touched {
+						if stat, ok := byPath[key]; ok {
+							stat.CommitCount++
+						}
+					}
+					touched = make(map[string]bool)
+				}
+				for scanner.Scan() {
+					l := scanner.Text()
+					if l == "\x00" {
+						count()
+						continue
+					}
+					for _, key := range directoryKeys(l) {
+						touched[key] = true
+					}
+				}
+				count()
+
+				_ = stdoutReader.Close()
+				return scanner.Err()
+			})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get GetDirectoryStats for repository.\nError: %w\nStderr: %s", err, stderr)
+		}
+	}
+
+	stats := make([]*DirectoryStat, 0, len(byPath))
+	for _, stat := range byPath {
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Path < stats[j].Path
+	})
+	return stats, nil
+}
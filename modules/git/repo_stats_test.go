@@ -35,3 +35,56 @@ func TestRepository_GetCodeActivityStats(t *testing.T) {
 	assert.EqualValues(t, 3, code.Authors[1].Commits)
 	assert.EqualValues(t, 5, code.Authors[0].Commits)
 }
+
+func TestRepository_GetCommitStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	commits, err := bareRepo1.GetCommitStats("master")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 6)
+
+	assert.Equal(t, "silverwind", commits[0].AuthorName)
+	assert.Equal(t, "me@silverwind.io", commits[0].AuthorEmail)
+	assert.EqualValues(t, 1563741793, commits[0].AuthorTime.Unix())
+	assert.EqualValues(t, 0, commits[0].Additions)
+
+	assert.Equal(t, "tris.git@shoddynet.org", commits[2].AuthorEmail)
+	assert.EqualValues(t, 2, commits[2].Additions)
+	assert.EqualValues(t, 0, commits[2].Deletions)
+
+	assert.Equal(t, "Example User", commits[5].AuthorName)
+	assert.Empty(t, commits[5].AuthorEmail)
+}
+
+func TestRepository_GetDirectoryStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	stats, err := bareRepo1.GetDirectoryStats("master", "", 2, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 3) {
+		assert.Equal(t, "foo", stats[0].Path)
+		assert.EqualValues(t, 49, stats[0].Size)
+		assert.EqualValues(t, 5, stats[0].FileCount)
+		assert.EqualValues(t, 3, stats[0].CommitCount)
+
+		assert.Equal(t, "foo/bar", stats[1].Path)
+		assert.EqualValues(t, 12, stats[1].Size)
+		assert.EqualValues(t, 1, stats[1].FileCount)
+		assert.EqualValues(t, 1, stats[1].CommitCount)
+
+		assert.Equal(t, "foo/nar", stats[2].Path)
+	}
+
+	stats, err = bareRepo1.GetDirectoryStats("master", "foo", 1, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, "foo/bar", stats[0].Path)
+		assert.Equal(t, "foo/nar", stats[1].Path)
+	}
+}
@@ -30,3 +30,10 @@ func TestRepoIsEmpty(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, isEmpty)
 }
+
+func TestGetDivergingCommitsBetweenRepos(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	diverge, err := GetDivergingCommitsBetweenRepos(bareRepo1Path, "master", bareRepo1Path, "branch1")
+	assert.NoError(t, err)
+	assert.Equal(t, DivergeObject{Ahead: 2, Behind: 5}, diverge)
+}
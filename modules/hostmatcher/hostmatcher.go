@@ -0,0 +1,155 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hostmatcher matches host names and IP addresses against the host lists of the settings.
+package hostmatcher
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// HostMatchList is used to check if a host or IP is in a list
+type HostMatchList struct {
+	// SettingKeyHint is the name of the setting the list comes from, it is used in error messages
+	SettingKeyHint string
+
+	// builtins networks
+	builtins []string
+	// patterns for host names (with wildcard support)
+	patterns []string
+	// ipNets is the CIDR network list
+	ipNets []*net.IPNet
+}
+
+// Built-in network names
+const (
+	// MatchBuiltinExternal matches the IPs which are not private or loopback
+	MatchBuiltinExternal = "external"
+	// MatchBuiltinPrivate matches the private IPs, see RFC 1918 and RFC 4193
+	MatchBuiltinPrivate = "private"
+	// MatchBuiltinLoopback matches the loopback IPs
+	MatchBuiltinLoopback = "loopback"
+	// MatchBuiltinAll matches all hosts and IPs
+	MatchBuiltinAll = "*"
+)
+
+// ParseHostMatchList parses the host list, the items are separated by commas
+func ParseHostMatchList(settingKeyHint, hostList string) *HostMatchList {
+	hl := &HostMatchList{SettingKeyHint: settingKeyHint}
+	for _, s := range strings.Split(hostList, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err == nil {
+			hl.ipNets = append(hl.ipNets, ipNet)
+		} else if util.IsStringInSlice(s, []string{MatchBuiltinExternal, MatchBuiltinPrivate, MatchBuiltinLoopback, MatchBuiltinAll}) {
+			hl.builtins = append(hl.builtins, s)
+		} else {
+			if _, err := filepath.Match(s, ""); err != nil {
+				log.Error("Invalid host pattern %q in %s: %v", s, settingKeyHint, err)
+				continue
+			}
+			hl.patterns = append(hl.patterns, s)
+		}
+	}
+	return hl
+}
+
+// IsEmpty returns true if the list has no entry
+func (hl *HostMatchList) IsEmpty() bool {
+	return hl == nil || (len(hl.builtins) == 0 && len(hl.patterns) == 0 && len(hl.ipNets) == 0)
+}
+
+func isPrivate(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4[0] == 10 ||
+			(ip4[0] == 172 && ip4[1]&0xf0 == 16) ||
+			(ip4[0] == 192 && ip4[1] == 168) ||
+			(ip4[0] == 169 && ip4[1] == 254) ||
+			// shared address space for carrier-grade NAT, RFC 6598
+			(ip4[0] == 100 && ip4[1]&0xc0 == 64)
+	}
+	return len(ip) == net.IPv6len && (ip[0]&0xfe == 0xfc || ip.IsLinkLocalUnicast())
+}
+
+func (hl *HostMatchList) checkPattern(host string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	for _, pattern := range hl.patterns {
+		if matched, _ := filepath.Match(pattern, host); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (hl *HostMatchList) checkIP(ip net.IP) bool {
+	for _, pattern := range hl.patterns {
+		if pattern == ip.String() {
+			return true
+		}
+	}
+	for _, builtin := range hl.builtins {
+		switch builtin {
+		case MatchBuiltinAll:
+			return true
+		case MatchBuiltinExternal:
+			if ip.IsGlobalUnicast() && !isPrivate(ip) {
+				return true
+			}
+		case MatchBuiltinPrivate:
+			if isPrivate(ip) {
+				return true
+			}
+		case MatchBuiltinLoopback:
+			if ip.IsLoopback() {
+				return true
+			}
+		}
+	}
+	for _, ipNet := range hl.ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchHostName checks if the host name matches the list, IP addresses given as host names are checked as IPs
+func (hl *HostMatchList) MatchHostName(host string) bool {
+	if hl == nil {
+		return false
+	}
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	hostname = strings.Trim(hostname, "[]")
+	if util.IsStringInSlice(MatchBuiltinAll, hl.builtins) || hl.checkPattern(hostname) {
+		return true
+	}
+	if ip := net.ParseIP(hostname); ip != nil {
+		return hl.checkIP(ip)
+	}
+	return false
+}
+
+// MatchIPAddr checks if the IP matches the list
+func (hl *HostMatchList) MatchIPAddr(ip net.IP) bool {
+	if hl == nil {
+		return false
+	}
+	return hl.checkIP(ip)
+}
+
+// MatchHostOrIP checks if either the host name or the IP matches the list
+func (hl *HostMatchList) MatchHostOrIP(host string, ip net.IP) bool {
+	return hl.MatchHostName(host) || hl.MatchIPAddr(ip)
+}
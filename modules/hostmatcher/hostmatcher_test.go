@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hostmatcher
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostOrIPMatchesList(t *testing.T) {
+	type tc struct {
+		host     string
+		ip       net.IP
+		expected bool
+	}
+
+	// for IPv6: "::1" is loopback, "fd00::/8" is private
+
+	hl := ParseHostMatchList("", "private, External, *.myDomain.com, 169.254.1.0/24")
+	cases := []tc{
+		{"", net.IPv4zero, false},
+		{"", net.IPv6zero, false},
+
+		{"", net.ParseIP("127.0.0.1"), false},
+		{"", net.ParseIP("::1"), false},
+
+		{"", net.ParseIP("10.0.1.1"), true},
+		{"", net.ParseIP("192.168.1.1"), true},
+		{"", net.ParseIP("fd00::1"), true},
+
+		{"", net.ParseIP("8.8.8.8"), true},
+		{"", net.ParseIP("1001::1"), true},
+
+		{"mydomain.com", net.IPv4zero, false},
+		{"sub.mydomain.com", net.IPv4zero, true},
+
+		{"", net.ParseIP("169.254.1.1"), true},
+		{"", net.ParseIP("169.254.2.2"), true},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, hl.MatchHostOrIP(c.host, c.ip), "case %s(%v)", c.host, c.ip)
+	}
+
+	hl = ParseHostMatchList("", "loopback")
+	cases = []tc{
+		{"", net.IPv4zero, false},
+		{"", net.ParseIP("127.0.0.1"), true},
+		{"", net.ParseIP("10.0.1.1"), false},
+		{"", net.ParseIP("192.168.1.1"), false},
+		{"", net.ParseIP("8.8.8.8"), false},
+
+		{"", net.ParseIP("::1"), true},
+		{"", net.ParseIP("fd00::1"), false},
+		{"", net.ParseIP("1000::1"), false},
+
+		{"mydomain.com", net.IPv4zero, false},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, hl.MatchHostOrIP(c.host, c.ip), "case %s(%v)", c.host, c.ip)
+	}
+
+	hl = ParseHostMatchList("", "*")
+	cases = []tc{
+		{"", net.ParseIP("127.0.0.1"), true},
+		{"", net.ParseIP("10.0.1.1"), true},
+		{"", net.ParseIP("8.8.8.8"), true},
+		{"mydomain.com", net.IPv4zero, true},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, hl.MatchHostOrIP(c.host, c.ip), "case %s(%v)", c.host, c.ip)
+	}
+}
+
+func TestMatchHostName(t *testing.T) {
+	hl := ParseHostMatchList("", "example.com, *.example.org, 10.0.0.0/8")
+	assert.True(t, hl.MatchHostName("example.com"))
+	assert.True(t, hl.MatchHostName("EXAMPLE.com:3000"))
+	assert.False(t, hl.MatchHostName("sub.example.com"))
+	assert.True(t, hl.MatchHostName("sub.example.org"))
+	assert.True(t, hl.MatchHostName("10.1.2.3"))
+	assert.True(t, hl.MatchHostName("10.1.2.3:80"))
+	assert.False(t, hl.MatchHostName("11.1.2.3"))
+
+	var empty *HostMatchList
+	assert.True(t, empty.IsEmpty())
+	assert.False(t, empty.MatchHostName("example.com"))
+	assert.True(t, ParseHostMatchList("", " , ").IsEmpty())
+}
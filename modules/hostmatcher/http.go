@@ -0,0 +1,114 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hostmatcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// HTTPChecker denies the outgoing HTTP connections to the hosts which are not allowed by the host lists
+type HTTPChecker struct {
+	usage     string
+	allowList *HostMatchList
+	blockList *HostMatchList
+
+	// proxies contains the addresses of the proxies used by the requests, the connections to them are trusted
+	proxies sync.Map
+}
+
+// NewHTTPChecker creates a checker, usage describes the caller in error messages.
+// If allowList is not empty only the matching hosts are allowed, the hosts matching blockList are always denied.
+func NewHTTPChecker(usage string, allowList, blockList *HostMatchList) *HTTPChecker {
+	return &HTTPChecker{
+		usage:     usage,
+		allowList: allowList,
+		blockList: blockList,
+	}
+}
+
+// Check returns an error if the host resolved to ip is not allowed
+func (c *HTTPChecker) Check(host string, ip net.IP) error {
+	if c.blockList.MatchHostOrIP(host, ip) {
+		return fmt.Errorf("%s can not call blocked HTTP servers (check your %s setting), deny '%s(%s)'", c.usage, c.blockList.SettingKeyHint, host, ip)
+	}
+	if !c.allowList.IsEmpty() && !c.allowList.MatchHostOrIP(host, ip) {
+		return fmt.Errorf("%s can only call allowed HTTP servers (check your %s setting), deny '%s(%s)'", c.usage, c.allowList.SettingKeyHint, host, ip)
+	}
+	return nil
+}
+
+// Proxy wraps the proxy function of a transport. When a request is sent through a proxy
+// the proxy can not be checked when dialing, so the target host is resolved and checked here.
+func (c *HTTPChecker) Proxy(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if proxy == nil {
+			return nil, nil
+		}
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+
+		host := req.URL.Hostname()
+		ips, err := net.DefaultResolver.LookupIPAddr(req.Context(), host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			if err := c.Check(host, ip.IP); err != nil {
+				return nil, err
+			}
+		}
+		c.proxies.Store(canonicalAddr(proxyURL), true)
+		return proxyURL, nil
+	}
+}
+
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// DialContext dials the address, the connection is refused if the resolved IP is not allowed
+func (c *HTTPChecker) DialContext(timeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialer := net.Dialer{
+			Timeout:   timeout,
+			KeepAlive: 30 * time.Second,
+		}
+		if _, ok := c.proxies.Load(addr); !ok {
+			dialer.Control = func(network, ipAddr string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(addr)
+				if err != nil {
+					return err
+				}
+				// the address is already resolved to IP:PORT here
+				tcpAddr, err := net.ResolveTCPAddr(network, ipAddr)
+				if err != nil {
+					return fmt.Errorf("%s can only call HTTP servers via TCP, deny '%s(%s:%s)': %v", c.usage, host, network, ipAddr, err)
+				}
+				return c.Check(host, tcpAddr.IP)
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
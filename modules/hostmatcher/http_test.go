@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hostmatcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	newClient := func(allowList, blockList string) *http.Client {
+		checker := NewHTTPChecker("test", ParseHostMatchList("test.ALLOWED", allowList), ParseHostMatchList("test.BLOCKED", blockList))
+		return &http.Client{
+			Transport: &http.Transport{
+				DialContext: checker.DialContext(5 * time.Second),
+			},
+		}
+	}
+
+	// the test server listens on the loopback interface
+	_, err := newClient("external", "").Get(server.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "test.ALLOWED")
+	}
+
+	resp, err := newClient("loopback", "").Get(server.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	resp, err = newClient("", "").Get(server.URL)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+
+	_, err = newClient("*", "127.0.0.0/8").Get(server.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "test.BLOCKED")
+	}
+}
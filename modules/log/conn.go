@@ -9,16 +9,28 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 )
 
+const (
+	connDialTimeout      = 5 * time.Second
+	connMinReconnectWait = time.Second
+	connMaxReconnectWait = time.Minute
+)
+
 type connWriter struct {
 	innerWriter    io.WriteCloser
 	ReconnectOnMsg bool   `json:"reconnectOnMsg"`
 	Reconnect      bool   `json:"reconnect"`
 	Net            string `json:"net"`
 	Addr           string `json:"addr"`
+
+	// after a failed connection attempt, wait before dialing again
+	// so that logging does not stall on every message while the server is down
+	reconnectWait time.Duration
+	nextDial      time.Time
 }
 
 // Close the inner writer
@@ -41,15 +53,18 @@ func (i *connWriter) Write(p []byte) (int, error) {
 		defer i.innerWriter.Close()
 	}
 
-	return i.innerWriter.Write(p)
+	n, err := i.innerWriter.Write(p)
+	if err != nil && i.Reconnect && !i.ReconnectOnMsg {
+		// the connection is lost, e.g. the server has been restarted: reconnect and try once more
+		if err := i.connect(); err != nil {
+			return n, err
+		}
+		return i.innerWriter.Write(p)
+	}
+	return n, err
 }
 
 func (i *connWriter) neededConnectOnMsg() bool {
-	if i.Reconnect {
-		i.Reconnect = false
-		return true
-	}
-
 	if i.innerWriter == nil {
 		return true
 	}
@@ -63,10 +78,23 @@ func (i *connWriter) connect() error {
 		i.innerWriter = nil
 	}
 
-	conn, err := net.Dial(i.Net, i.Addr)
+	if now := time.Now(); now.Before(i.nextDial) {
+		return fmt.Errorf("unable to connect to %s %s, retrying in %v", i.Net, i.Addr, i.nextDial.Sub(now).Round(time.Millisecond))
+	}
+
+	conn, err := net.DialTimeout(i.Net, i.Addr, connDialTimeout)
 	if err != nil {
+		i.reconnectWait *= 2
+		if i.reconnectWait < connMinReconnectWait {
+			i.reconnectWait = connMinReconnectWait
+		} else if i.reconnectWait > connMaxReconnectWait {
+			i.reconnectWait = connMaxReconnectWait
+		}
+		i.nextDial = time.Now().Add(i.reconnectWait)
 		return err
 	}
+	i.reconnectWait = 0
+	i.nextDial = time.Time{}
 
 	if tcpConn, ok := conn.(*net.TCPConn); ok {
 		err = tcpConn.SetKeepAlive(true)
@@ -87,7 +115,7 @@ func (i *connWriter) releaseReopen() error {
 }
 
 // ConnLogger implements LoggerProvider.
-// it writes messages in keep-live tcp connection.
+// it writes messages in keep-live tcp connection, which is reestablished when lost if Reconnect is set.
 type ConnLogger struct {
 	WriterLogger
 	ReconnectOnMsg bool   `json:"reconnectOnMsg"`
@@ -5,6 +5,7 @@
 package log
 
 import (
+	"bufio"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -230,3 +231,80 @@ func TestConnLoggerClose(t *testing.T) {
 	logger.Flush()
 	logger.Close()
 }
+
+func TestConnLoggerReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	logger := NewConn()
+	err = logger.Init(fmt.Sprintf("{\"level\":\"INFO\",\"flags\":-1,\"reconnect\":true,\"net\":\"tcp\",\"addr\":\"%s\"}", l.Addr().String()))
+	assert.NoError(t, err)
+	defer logger.Close()
+
+	event := Event{
+		level: INFO,
+		msg:   "TEST MSG",
+		time:  time.Now(),
+	}
+	assert.NoError(t, logger.LogEvent(&event))
+
+	// the server drops the connection after the first message
+	conn, err := l.Accept()
+	assert.NoError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST MSG\n", line)
+	conn.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	// writing to the dropped connection fails sooner or later and the logger reconnects
+	for i := 0; i < 50; i++ {
+		select {
+		case conn := <-accepted:
+			defer conn.Close()
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, "TEST MSG\n", line)
+			return
+		default:
+		}
+		_ = logger.LogEvent(&event)
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("the logger did not reconnect")
+}
+
+func TestConnLoggerReconnectWait(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := l.Addr().String()
+	l.Close()
+
+	writer := &connWriter{
+		Reconnect: true,
+		Net:       "tcp",
+		Addr:      address,
+	}
+	_, err = writer.Write([]byte("TEST MSG\n"))
+	assert.Error(t, err)
+	assert.Equal(t, connMinReconnectWait, writer.reconnectWait)
+
+	// no connection is attempted before the wait is over
+	_, err = writer.Write([]byte("TEST MSG\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "retrying in")
+	assert.Equal(t, connMinReconnectWait, writer.reconnectWait)
+
+	writer.nextDial = time.Now()
+	_, err = writer.Write([]byte("TEST MSG\n"))
+	assert.Error(t, err)
+	assert.Equal(t, 2*connMinReconnectWait, writer.reconnectWait)
+}
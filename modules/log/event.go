@@ -19,6 +19,8 @@ type Event struct {
 	line       int
 	time       time.Time
 	stacktrace string
+	// trace identifies the request or task during which the event was logged
+	trace string
 }
 
 // EventLogger represents the behaviours of a logger
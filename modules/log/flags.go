@@ -26,12 +26,13 @@ const (
 	LUTC                       // if Ldate or Ltime is set, use UTC rather than the local time zone
 	Llevelinitial              // Initial character of the provided level in brackets eg. [I] for info
 	Llevel                     // Provided level in brackets [INFO]
+	Ltrace                     // Trace of the request the event was logged for in brackets eg. [e0b0...]
 
 	// Last 20 characters of the filename
 	Lmedfile = Lshortfile | Llongfile
 
 	// LstdFlags is the initial value for the standard logger
-	LstdFlags = Ldate | Ltime | Lmedfile | Lshortfuncname | Llevelinitial
+	LstdFlags = Ldate | Ltime | Lmedfile | Lshortfuncname | Llevelinitial | Ltrace
 )
 
 var flagFromString = map[string]int{
@@ -46,6 +47,7 @@ var flagFromString = map[string]int{
 	"utc":           LUTC,
 	"levelinitial":  Llevelinitial,
 	"level":         Llevel,
+	"trace":         Ltrace,
 	"medfile":       Lmedfile,
 	"stdflags":      LstdFlags,
 }
@@ -92,6 +92,7 @@ func (l *MultiChannelledLogger) SendLog(level Level, caller, filename string, li
 		msg:        msg,
 		time:       time.Now(),
 		stacktrace: stack,
+		trace:      currentTrace(),
 	}
 	l.LogEvent(event)
 	return nil
@@ -0,0 +1,209 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogSeverities maps the levels to the severities of RFC5424
+var syslogSeverities = map[Level]int{
+	TRACE:    7, // debug
+	DEBUG:    7, // debug
+	INFO:     6, // informational
+	WARN:     4, // warning
+	ERROR:    3, // error
+	CRITICAL: 2, // critical
+	FATAL:    1, // alert
+}
+
+// the sockets of the local syslog daemon
+var syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogLogger implements LoggerProvider.
+// It sends RFC5424 messages to a syslog server over udp, tcp or a unix socket, reconnecting when the connection is lost.
+type SyslogLogger struct {
+	WriterLogger
+	// Net is one of "udp", "tcp", "unix" or "unixgram", the local syslog daemon is used if it is empty
+	Net      string `json:"net"`
+	Addr     string `json:"addr"`
+	Facility string `json:"facility"`
+	AppName  string `json:"appName"`
+	Hostname string `json:"hostname"`
+
+	priority int
+	procID   string
+	framed   bool
+}
+
+// NewSyslog creates new SyslogLogger returning as LoggerProvider.
+func NewSyslog() LoggerProvider {
+	syslog := new(SyslogLogger)
+	syslog.Level = TRACE
+	return syslog
+}
+
+// Init inits syslog writer with json config.
+func (log *SyslogLogger) Init(jsonconfig string) error {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	err := json.Unmarshal([]byte(jsonconfig), log)
+	if err != nil {
+		return fmt.Errorf("Unable to parse JSON: %v", err)
+	}
+
+	if log.Facility == "" {
+		log.Facility = "daemon"
+	}
+	facility, ok := syslogFacilities[strings.ToLower(log.Facility)]
+	if !ok {
+		return fmt.Errorf("Unknown syslog facility: %s", log.Facility)
+	}
+	log.priority = facility * 8
+
+	if log.Hostname == "" {
+		log.Hostname, _ = os.Hostname()
+	}
+	log.Hostname = syslogHeaderField(log.Hostname, 255)
+	if log.AppName == "" {
+		log.AppName = "gitea"
+	}
+	log.AppName = syslogHeaderField(log.AppName, 48)
+	log.procID = strconv.Itoa(os.Getpid())
+
+	switch log.Net {
+	case "":
+		log.Net = "unixgram"
+		for _, addr := range syslogLocalAddrs {
+			if _, err := os.Stat(addr); err == nil {
+				log.Addr = addr
+				break
+			}
+		}
+		if log.Addr == "" {
+			return fmt.Errorf("Unable to find the socket of the local syslog daemon")
+		}
+	case "udp", "unixgram":
+	case "tcp", "unix":
+		// stream transports need the octet counting framing of RFC6587
+		log.framed = true
+	default:
+		return fmt.Errorf("Unsupported syslog protocol: %s", log.Net)
+	}
+
+	log.NewWriterLogger(&connWriter{
+		Reconnect: true,
+		Net:       log.Net,
+		Addr:      log.Addr,
+	}, log.Level)
+
+	// the header of the messages already contains the time and the severity
+	log.Flags &^= Ldate | Ltime | Lmicroseconds | LUTC | Llevel | Llevelinitial
+	log.Colorize = false
+	return nil
+}
+
+// syslogHeaderField restricts a field of the header to printable ascii characters without spaces
+func syslogHeaderField(value string, maxLen int) string {
+	field := []byte(value)
+	for i, c := range field {
+		if c < 33 || c > 126 {
+			field[i] = '_'
+		}
+	}
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	if len(field) == 0 {
+		return "-"
+	}
+	return string(field)
+}
+
+// LogEvent sends the event as a syslog message
+func (log *SyslogLogger) LogEvent(event *Event) error {
+	if log.Level > event.level {
+		return nil
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if !log.Match(event) {
+		return nil
+	}
+
+	var msg []byte
+	log.createMsg(&msg, event)
+	msg = bytes.TrimRight(msg, "\n")
+
+	severity, ok := syslogSeverities[event.level]
+	if !ok {
+		severity = 6
+	}
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	header := fmt.Sprintf("<%d>1 %s %s %s %s - - ",
+		log.priority+severity,
+		event.time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		log.Hostname,
+		log.AppName,
+		log.procID)
+
+	var buf []byte
+	if log.framed {
+		buf = append(buf, strconv.Itoa(len(header)+len(msg))...)
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, header...)
+	buf = append(buf, msg...)
+	_, err := log.out.Write(buf)
+	return err
+}
+
+// Flush does nothing for this implementation
+func (log *SyslogLogger) Flush() {
+}
+
+// GetName returns the default name for this implementation
+func (log *SyslogLogger) GetName() string {
+	return "syslog"
+}
+
+// ReleaseReopen causes the SyslogLogger to reconnect to the server
+func (log *SyslogLogger) ReleaseReopen() error {
+	return log.out.(*connWriter).releaseReopen()
+}
+
+func init() {
+	Register("syslog", NewSyslog)
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogLoggerUDP(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer server.Close()
+
+	logger := NewSyslog()
+	err = logger.Init(fmt.Sprintf(`{"level":"INFO","flags":%d,"net":"udp","addr":"%s","facility":"local0","appName":"gitea test","hostname":"host"}`, LstdFlags|LUTC|Lshortfile, server.LocalAddr().String()))
+	assert.NoError(t, err)
+	defer logger.Close()
+
+	date := time.Date(2019, time.January, 13, 22, 3, 30, 15000, time.UTC)
+	event := Event{
+		level:    WARN,
+		msg:      "TEST MSG\n",
+		caller:   "CALLER",
+		filename: "FULL/FILENAME",
+		line:     1,
+		time:     date,
+	}
+	assert.NoError(t, logger.LogEvent(&event))
+
+	// events below the level are not sent
+	event.level = DEBUG
+	assert.NoError(t, logger.LogEvent(&event))
+
+	buf := make([]byte, 1024)
+	assert.NoError(t, server.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := server.ReadFrom(buf)
+	assert.NoError(t, err)
+	// local0 * 8 + warning, without the date, time and level flags
+	expected := fmt.Sprintf("<132>1 2019-01-13T22:03:30.000015Z host gitea_test %d - - FULL/FILENAME:1:CALLER TEST MSG", os.Getpid())
+	assert.Equal(t, expected, string(buf[:n]))
+}
+
+func TestSyslogLoggerTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	logger := NewSyslog()
+	err = logger.Init(fmt.Sprintf(`{"level":"INFO","flags":%d,"net":"tcp","addr":"%s","hostname":"host"}`, Lshortfile, l.Addr().String()))
+	assert.NoError(t, err)
+	defer logger.Close()
+
+	event := Event{
+		level:    ERROR,
+		msg:      "TEST MSG",
+		filename: "FULL/FILENAME",
+		line:     1,
+		time:     time.Date(2019, time.January, 13, 22, 3, 30, 0, time.UTC),
+	}
+	assert.NoError(t, logger.LogEvent(&event))
+
+	conn, err := l.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// messages are prefixed by their length over stream sockets
+	reader := bufio.NewReader(conn)
+	length, err := reader.ReadString(' ')
+	assert.NoError(t, err)
+	size, err := strconv.Atoi(length[:len(length)-1])
+	assert.NoError(t, err)
+	msg := make([]byte, size)
+	_, err = io.ReadFull(reader, msg)
+	assert.NoError(t, err)
+	// daemon * 8 + error
+	assert.Equal(t, fmt.Sprintf("<27>1 2019-01-13T22:03:30.000000Z host gitea %d - - FILENAME:1 TEST MSG", os.Getpid()), string(msg))
+}
+
+func TestSyslogLoggerBadConfig(t *testing.T) {
+	logger := NewSyslog()
+	err := logger.Init("{")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to parse JSON")
+
+	logger = NewSyslog()
+	err = logger.Init(`{"net":"udp","addr":"127.0.0.1:514","facility":"nope"}`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown syslog facility")
+
+	logger = NewSyslog()
+	err = logger.Init(`{"net":"http","addr":"127.0.0.1:514"}`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported syslog protocol")
+}
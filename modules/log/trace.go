@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	traces     sync.Map // goroutine id -> trace
+	traceCount int64
+)
+
+// BindTrace attaches trace to every event logged by the calling goroutine
+// until the returned function is called
+func BindTrace(trace string) (release func()) {
+	id := goroutineID()
+	traces.Store(id, trace)
+	atomic.AddInt64(&traceCount, 1)
+	return func() {
+		traces.Delete(id)
+		atomic.AddInt64(&traceCount, -1)
+	}
+}
+
+// currentTrace returns the trace bound to the calling goroutine
+func currentTrace() string {
+	if atomic.LoadInt64(&traceCount) == 0 {
+		return ""
+	}
+	if trace, ok := traces.Load(goroutineID()); ok {
+		return trace.(string)
+	}
+	return ""
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the id of the calling goroutine, read from the header of its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, goroutinePrefix)
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindTrace(t *testing.T) {
+	assert.Empty(t, currentTrace())
+
+	release := BindTrace("trace1")
+	assert.Equal(t, "trace1", currentTrace())
+
+	// other goroutines are not affected
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Empty(t, currentTrace())
+		defer BindTrace("trace2")()
+		assert.Equal(t, "trace2", currentTrace())
+	}()
+	wg.Wait()
+	assert.Equal(t, "trace1", currentTrace())
+
+	release()
+	assert.Empty(t, currentTrace())
+}
+
+func TestWriterLoggerTrace(t *testing.T) {
+	var written []byte
+	b := WriterLogger{
+		out: CallbackWriteCloser{
+			callback: func(p []byte, close bool) {
+				written = p
+			},
+		},
+		Level: INFO,
+		Flags: Llevelinitial | Ltrace,
+	}
+
+	event := Event{
+		level: INFO,
+		msg:   "TEST MSG",
+		time:  time.Now(),
+		trace: "abc123",
+	}
+	assert.NoError(t, b.LogEvent(&event))
+	assert.Equal(t, "[I] [abc123] TEST MSG\n", string(written))
+
+	event.trace = ""
+	assert.NoError(t, b.LogEvent(&event))
+	assert.Equal(t, "[I] TEST MSG\n", string(written))
+}
@@ -189,6 +189,12 @@ func (logger *WriterLogger) createMsg(buf *[]byte, event *Event) {
 		*buf = append(*buf, ' ')
 	}
 
+	if logger.Flags&Ltrace != 0 && event.trace != "" {
+		*buf = append(*buf, '[')
+		*buf = append(*buf, event.trace...)
+		*buf = append(*buf, "] "...)
+	}
+
 	var msg = []byte(event.msg)
 	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
 		msg = msg[:len(msg)-1]
@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// FilePreviewOptions describes the lines of a file of a repository at a commit
+type FilePreviewOptions struct {
+	OwnerName string
+	RepoName  string
+	CommitID  string
+	FilePath  string
+	LineStart int
+	LineEnd   int
+}
+
+// RenderFilePreview renders the lines of a file described by the options as HTML, it returns an empty string
+// when they can't be shown in the rendering context. It is set by the services able to read repositories.
+var RenderFilePreview func(ctx *RenderContext, opts FilePreviewOptions) (string, error)
+
+var (
+	// filePreviewPattern matches permalinks to lines of a file at a commit of a repository of this instance
+	filePreviewPattern     *regexp.Regexp
+	filePreviewPatternOnce sync.Once
+)
+
+func getFilePreviewPattern() *regexp.Regexp {
+	filePreviewPatternOnce.Do(func() {
+		filePreviewPattern = regexp.MustCompile(regexp.QuoteMeta(setting.AppURL) +
+			`([^\s/]+)/([^\s/]+)/src/commit/([0-9a-f]{40})/(\S+)#L([1-9][0-9]*)(?:-L([1-9][0-9]*))?\b`)
+	})
+	return filePreviewPattern
+}
+
+// filePreviewPatternProcessor replaces permalinks to lines of a file by a preview of these lines
+func filePreviewPatternProcessor(ctx *RenderContext, node *html.Node) {
+	if ctx.Metas == nil || RenderFilePreview == nil {
+		return
+	}
+
+	start := 0
+	next := node.NextSibling
+	for node != nil && node != next && start < len(node.Data) {
+		m := getFilePreviewPattern().FindStringSubmatchIndex(node.Data[start:])
+		if m == nil {
+			return
+		}
+		for i := range m {
+			if m[i] >= 0 {
+				m[i] += start
+			}
+		}
+
+		filePath, err := url.PathUnescape(node.Data[m[8]:m[9]])
+		if err != nil {
+			start = m[1]
+			continue
+		}
+		opts := FilePreviewOptions{
+			OwnerName: node.Data[m[2]:m[3]],
+			RepoName:  node.Data[m[4]:m[5]],
+			CommitID:  node.Data[m[6]:m[7]],
+			FilePath:  filePath,
+		}
+		opts.LineStart, _ = strconv.Atoi(node.Data[m[10]:m[11]])
+		opts.LineEnd = opts.LineStart
+		if m[12] >= 0 {
+			opts.LineEnd, _ = strconv.Atoi(node.Data[m[12]:m[13]])
+		}
+		if opts.LineEnd < opts.LineStart {
+			opts.LineStart, opts.LineEnd = opts.LineEnd, opts.LineStart
+		}
+
+		preview, err := RenderFilePreview(ctx, opts)
+		if err != nil {
+			log.Error("RenderFilePreview: %v", err)
+		}
+		if err != nil || preview == "" {
+			start = m[1]
+			continue
+		}
+		nodes, err := html.ParseFragment(strings.NewReader(preview), &html.Node{
+			Type:     html.ElementNode,
+			Data:     atom.Div.String(),
+			DataAtom: atom.Div,
+		})
+		if err != nil {
+			log.Error("Unable to parse file preview: %v", err)
+			start = m[1]
+			continue
+		}
+
+		replaceContentList(node, m[0], m[1], nodes)
+		node = nodes[len(nodes)-1].NextSibling
+		start = 0
+	}
+}
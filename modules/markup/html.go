@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup/common"
 	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/regexplru"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 
@@ -34,6 +35,7 @@ import (
 const (
 	IssueNameStyleNumeric      = "numeric"
 	IssueNameStyleAlphanumeric = "alphanumeric"
+	IssueNameStyleRegexp       = "regexp"
 )
 
 var (
@@ -153,6 +155,7 @@ type processor func(ctx *RenderContext, node *html.Node)
 
 var defaultProcessors = []processor{
 	fullIssuePatternProcessor,
+	filePreviewPatternProcessor,
 	fullSha1PatternProcessor,
 	shortLinkProcessor,
 	linkProcessor,
@@ -810,17 +813,28 @@ func issueIndexPatternProcessor(ctx *RenderContext, node *html.Node) {
 	for node != nil && node != next {
 		_, exttrack := ctx.Metas["format"]
 		alphanum := ctx.Metas["style"] == IssueNameStyleAlphanumeric
+		var pattern *regexp.Regexp
+		if exttrack && ctx.Metas["style"] == IssueNameStyleRegexp {
+			var err error
+			if pattern, err = regexplru.GetCompiled(ctx.Metas["regexp"]); err != nil {
+				log.Debug("Invalid external tracker pattern %q: %v", ctx.Metas["regexp"], err)
+				return
+			}
+		}
 
 		// Repos with external issue trackers might still need to reference local PRs
 		// We need to concern with the first one that shows up in the text, whichever it is
-		found, ref = references.FindRenderizableReferenceNumeric(node.Data, exttrack && alphanum)
+		found, ref = references.FindRenderizableReferenceNumeric(node.Data, exttrack && (alphanum || pattern != nil))
+		var found2 bool
+		var ref2 *references.RenderizableReference
 		if exttrack && alphanum {
-			if found2, ref2 := references.FindRenderizableReferenceAlphanumeric(node.Data); found2 {
-				if !found || ref2.RefLocation.Start < ref.RefLocation.Start {
-					found = true
-					ref = ref2
-				}
-			}
+			found2, ref2 = references.FindRenderizableReferenceAlphanumeric(node.Data)
+		} else if pattern != nil {
+			found2, ref2 = references.FindRenderizableReferenceRegexp(node.Data, pattern)
+		}
+		if found2 && (!found || ref2.RefLocation.Start < ref.RefLocation.Start) {
+			found = true
+			ref = ref2
 		}
 		if !found {
 			return
@@ -52,6 +52,14 @@ var alphanumericMetas = map[string]string{
 	"style":  IssueNameStyleAlphanumeric,
 }
 
+var regexpMetas = map[string]string{
+	"format": "https://someurl.com/{user}/{repo}/{index}",
+	"user":   "someUser",
+	"repo":   "someRepo",
+	"style":  IssueNameStyleRegexp,
+	"regexp": `JIRA-(\d+)`,
+}
+
 // these values should match the Repo const above
 var localMetas = map[string]string{
 	"user": "gogits",
@@ -188,6 +196,34 @@ func TestRender_IssueIndexPattern4(t *testing.T) {
 	test("test issue ABCDEFGHIJ-1234567890", "test issue %s", "ABCDEFGHIJ-1234567890")
 }
 
+func TestRender_IssueIndexPattern_Regexp(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	test := func(s, expected string) {
+		testRenderIssueIndexPattern(t, s, expected, &RenderContext{Metas: regexpMetas})
+	}
+	// regexp: render inputs without valid mentions
+	test("this is a test", "this is a test")
+	test("test JIRA- 123", "test JIRA- 123")
+	test("test ABC-123", "test ABC-123")
+
+	// regexp: render inputs with valid mentions, linked to the first group of the pattern
+	test("JIRA-123 test", `<a href="https://someurl.com/someUser/someRepo/123" class="ref-issue">JIRA-123</a> test`)
+	test("fixes JIRA-1 and JIRA-22", `fixes <a href="https://someurl.com/someUser/someRepo/1" class="ref-issue">JIRA-1</a> and <a href="https://someurl.com/someUser/someRepo/22" class="ref-issue">JIRA-22</a>`)
+
+	// regexp: local pull requests are still rendered, issues are not
+	test("test #123", "test #123")
+	test("test !123 JIRA-1", `test <a href="http://localhost:3000/someUser/someRepo/pulls/123" class="ref-issue">!123</a> <a href="https://someurl.com/someUser/someRepo/1" class="ref-issue">JIRA-1</a>`)
+
+	// regexp: invalid patterns render nothing
+	testRenderIssueIndexPattern(t, "JIRA-123 test", "JIRA-123 test", &RenderContext{Metas: map[string]string{
+		"format": "https://someurl.com/{user}/{repo}/{index}",
+		"style":  IssueNameStyleRegexp,
+		"regexp": "(",
+	}})
+}
+
 func testRenderIssueIndexPattern(t *testing.T, input, expected string, ctx *RenderContext) {
 	if ctx.URLPrefix == "" {
 		ctx.URLPrefix = AppSubURL
@@ -84,6 +84,55 @@ func TestRender_CrossReferences(t *testing.T) {
 		`<p>/home/gitea/go-gitea/gitea#12345</p>`)
 }
 
+func TestRender_FilePreview(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	defer func(fn func(*RenderContext, FilePreviewOptions) (string, error)) { RenderFilePreview = fn }(RenderFilePreview)
+	var rendered []FilePreviewOptions
+	RenderFilePreview = func(ctx *RenderContext, opts FilePreviewOptions) (string, error) {
+		rendered = append(rendered, opts)
+		if opts.RepoName == "private" {
+			return "", nil
+		}
+		return `<div class="file-preview-box"><table class="file-preview"><tbody><tr><td class="lines-num">1</td><td class="lines-code"><code>x</code></td></tr></tbody></table></div>`, nil
+	}
+
+	test := func(input, expected string) {
+		buffer, err := RenderString(&RenderContext{
+			Filename:  "a.md",
+			URLPrefix: setting.AppSubURL,
+			Metas:     localMetas,
+		}, input)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(buffer))
+	}
+
+	var sha = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	test(
+		AppURL+"gogits/gogs/src/commit/"+sha+"/path/to/file%20name.go#L3-L1",
+		`<p><div class="file-preview-box"><table class="file-preview"><tbody><tr><td class="lines-num">1</td><td class="lines-code"><code>x</code></td></tr></tbody></table></div></p>`)
+	assert.Equal(t, []FilePreviewOptions{{
+		OwnerName: "gogits",
+		RepoName:  "gogs",
+		CommitID:  sha,
+		FilePath:  "path/to/file name.go",
+		LineStart: 1,
+		LineEnd:   3,
+	}}, rendered)
+
+	// links which can't be previewed are rendered as usual
+	var link = util.URLJoin(AppURL, "gogits", "private", "src", "commit", sha, "main.go") + "#L2"
+	test(link, `<p><a href="`+link+`" rel="nofollow"><code>65f1bf27bc/main.go (L2)</code></a></p>`)
+	assert.Len(t, rendered, 2)
+	assert.Equal(t, 2, rendered[1].LineEnd)
+
+	// links without lines are not previewed
+	link = util.URLJoin(AppURL, "gogits", "gogs", "src", "commit", sha, "main.go")
+	test(link, `<p><a href="`+link+`" rel="nofollow"><code>65f1bf27bc/main.go</code></a></p>`)
+	assert.Len(t, rendered, 2)
+}
+
 func TestMisc_IsSameDomain(t *testing.T) {
 	setting.AppURL = AppURL
 	setting.AppSubURL = AppSubURL
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"io"
 	"regexp"
+	"strings"
 	"sync"
 
 	"code.gitea.io/gitea/modules/setting"
@@ -68,6 +69,9 @@ func createDefaultPolicy() *bluemonday.Policy {
 	// Allow classes for anchors
 	policy.AllowAttrs("class").Matching(regexp.MustCompile(`ref-issue`)).OnElements("a")
 
+	// Allow classes for file previews
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^file-preview(-box|-header| chroma)?$|^lines-(num|code)$`)).OnElements("div", "table", "td")
+
 	// Allow classes for task lists
 	policy.AllowAttrs("class").Matching(regexp.MustCompile(`task-list-item`)).OnElements("li")
 
@@ -116,6 +120,17 @@ func createDefaultPolicy() *bluemonday.Policy {
 
 	policy.AllowAttrs("itemscope", "itemtype").OnElements("div")
 
+	// Extra elements allowed by the administrator
+	if len(setting.MarkupSanitizerPolicy.AllowElements) > 0 {
+		policy.AllowAttrs(generalSafeAttrs...).OnElements(setting.MarkupSanitizerPolicy.AllowElements...)
+	}
+
+	// Embeds from trusted domains
+	if len(setting.MarkupSanitizerPolicy.IFrameDomains) > 0 {
+		policy.AllowAttrs("src").Matching(iframeSourcePattern(setting.MarkupSanitizerPolicy.IFrameDomains)).OnElements("iframe")
+		policy.AllowAttrs("width", "height", "title", "allow", "allowfullscreen", "frameborder", "loading").OnElements("iframe")
+	}
+
 	// FIXME: Need to handle longdesc in img but there is no easy way to do it
 
 	// Custom keyword markup
@@ -124,6 +139,19 @@ func createDefaultPolicy() *bluemonday.Policy {
 	return policy
 }
 
+// iframeSourcePattern matches http(s) URLs on the given domains, "*.example.com" matching any subdomain of example.com
+func iframeSourcePattern(domains []string) *regexp.Regexp {
+	hosts := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if strings.HasPrefix(domain, "*.") {
+			hosts = append(hosts, `([a-z0-9-]+\.)+`+regexp.QuoteMeta(domain[2:]))
+		} else {
+			hosts = append(hosts, regexp.QuoteMeta(domain))
+		}
+	}
+	return regexp.MustCompile(`(?i)^https?://(` + strings.Join(hosts, "|") + `)(:\d+)?([/?#]|$)`)
+}
+
 func addSanitizerRules(policy *bluemonday.Policy, rules []setting.MarkupSanitizerRule) {
 	for _, rule := range rules {
 		if rule.AllowDataURIImages {
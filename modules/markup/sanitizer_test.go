@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/modules/setting"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +63,32 @@ func TestSanitizeNonEscape(t *testing.T) {
 	}
 
 }
+
+func TestSanitizerPolicyExtensions(t *testing.T) {
+	NewSanitizer()
+	oldPolicy := setting.MarkupSanitizerPolicy
+	defer func() {
+		setting.MarkupSanitizerPolicy = oldPolicy
+		InitializeSanitizer()
+	}()
+
+	embed := `<iframe src="https://asciinema.org/a/1/iframe" width="400" onload="alert(1)"></iframe>`
+	assert.Equal(t, "", Sanitize(embed))
+	assert.Equal(t, "note", Sanitize(`<center>note</center>`))
+
+	setting.MarkupSanitizerPolicy.AllowElements = []string{"center"}
+	setting.MarkupSanitizerPolicy.IFrameDomains = []string{"asciinema.org", "*.example.com"}
+	InitializeSanitizer()
+
+	testCases := []string{
+		`<center title="x" onclick="alert(1)">note</center>`, `<center title="x">note</center>`,
+		embed, `<iframe src="https://asciinema.org/a/1/iframe" width="400"></iframe>`,
+		`<iframe src="https://dash.example.com:8443"></iframe>`, `<iframe src="https://dash.example.com:8443"></iframe>`,
+		`<iframe src="https://example.com/"></iframe>`, ``,
+		`<iframe src="https://asciinema.org.evil.com/"></iframe>`, ``,
+		`<iframe src="javascript:alert(1)//asciinema.org"></iframe>`, ``,
+	}
+	for i := 0; i < len(testCases); i += 2 {
+		assert.Equal(t, testCases[i+1], Sanitize(testCases[i]))
+	}
+}
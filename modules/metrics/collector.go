@@ -6,6 +6,7 @@ package metrics
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/queue"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -29,6 +30,8 @@ type Collector struct {
 	Oauths        *prometheus.Desc
 	Organizations *prometheus.Desc
 	PublicKeys    *prometheus.Desc
+	QueueLength   *prometheus.Desc
+	QueueWorkers  *prometheus.Desc
 	Releases      *prometheus.Desc
 	Repositories  *prometheus.Desc
 	Stars         *prometheus.Desc
@@ -112,6 +115,16 @@ func NewCollector() Collector {
 			"Number of PublicKeys",
 			nil, nil,
 		),
+		QueueLength: prometheus.NewDesc(
+			namespace+"queue_length",
+			"Number of items waiting in the queue",
+			[]string{"queue", "type"}, nil,
+		),
+		QueueWorkers: prometheus.NewDesc(
+			namespace+"queue_workers",
+			"Number of workers of the queue",
+			[]string{"queue", "type"}, nil,
+		),
 		Releases: prometheus.NewDesc(
 			namespace+"releases",
 			"Number of Releases",
@@ -172,6 +185,8 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Oauths
 	ch <- c.Organizations
 	ch <- c.PublicKeys
+	ch <- c.QueueLength
+	ch <- c.QueueWorkers
 	ch <- c.Releases
 	ch <- c.Repositories
 	ch <- c.Stars
@@ -256,6 +271,24 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.PublicKey),
 	)
+	for _, mq := range queue.GetManager().ManagedQueues() {
+		if length := mq.NumberInQueue(); length >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueLength,
+				prometheus.GaugeValue,
+				float64(length),
+				mq.Name, string(mq.Type),
+			)
+		}
+		if workers := mq.NumberOfWorkers(); workers >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueWorkers,
+				prometheus.GaugeValue,
+				float64(workers),
+				mq.Name, string(mq.Type),
+			)
+		}
+	}
 	ch <- prometheus.MustNewConstMetric(
 		c.Releases,
 		prometheus.GaugeValue,
@@ -0,0 +1,115 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	mirrorSyncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    namespace + "mirror_sync_duration_seconds",
+			Help:    "Duration of the syncs of pull mirrors",
+			Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+		},
+		[]string{"result"},
+	)
+	webhookDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "webhook_deliveries_total",
+			Help: "Number of webhook deliveries",
+		},
+		[]string{"type", "result"},
+	)
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    namespace + "http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+	sshConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: namespace + "ssh_connections",
+			Help: "Number of open connections to the builtin SSH server",
+		},
+	)
+	sshSessions = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: namespace + "ssh_sessions",
+			Help: "Number of running sessions of the builtin SSH server",
+		},
+	)
+	sshRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "ssh_rejections_total",
+			Help: "Number of connections and sessions refused by the builtin SSH server",
+		},
+		[]string{"reason"},
+	)
+)
+
+// Register registers the collector and the instrumentation metrics with prometheus
+func Register() {
+	prometheus.MustRegister(
+		NewCollector(),
+		mirrorSyncDuration,
+		webhookDeliveries,
+		httpRequestDuration,
+		sshConnections,
+		sshSessions,
+		sshRejections,
+	)
+}
+
+func resultLabel(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
+
+// ObserveMirrorSync records the duration of a pull mirror sync started at start
+func ObserveMirrorSync(start time.Time, success bool) {
+	mirrorSyncDuration.WithLabelValues(resultLabel(success)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveWebhookDelivery records the result of a webhook delivery
+func ObserveWebhookDelivery(hookType string, success bool) {
+	webhookDeliveries.WithLabelValues(hookType, resultLabel(success)).Inc()
+}
+
+// ObserveHTTPRequest records the duration of an HTTP request started at start
+func ObserveHTTPRequest(method string, status int, start time.Time) {
+	httpRequestDuration.WithLabelValues(method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveSSHConnection records the opening or the closing of a connection to the builtin SSH server
+func ObserveSSHConnection(opened bool) {
+	if opened {
+		sshConnections.Inc()
+	} else {
+		sshConnections.Dec()
+	}
+}
+
+// ObserveSSHSession records the start or the end of a session of the builtin SSH server
+func ObserveSSHSession(started bool) {
+	if started {
+		sshSessions.Inc()
+	} else {
+		sshSessions.Dec()
+	}
+}
+
+// ObserveSSHRejection records a connection or a session refused by the builtin SSH server
+func ObserveSSHRejection(reason string) {
+	sshRejections.WithLabelValues(reason).Inc()
+}
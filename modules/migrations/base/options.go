@@ -30,6 +30,7 @@ type MigrateOptions struct {
 	OriginalURL     string
 	GitServiceType  structs.GitServiceType
 	Wiki            bool
+	WikiCloneAddr   string
 	Issues          bool
 	Milestones      bool
 	Labels          bool
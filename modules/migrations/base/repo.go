@@ -14,5 +14,6 @@ type Repository struct {
 	Description   string
 	CloneURL      string `yaml:"clone_url"`
 	OriginalURL   string `yaml:"original_url"`
+	WikiURL       string `yaml:"wiki_url"`
 	DefaultBranch string
 }
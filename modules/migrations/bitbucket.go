@@ -0,0 +1,608 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+var (
+	_ base.Downloader        = &BitbucketDownloader{}
+	_ base.DownloaderFactory = &BitbucketDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&BitbucketDownloaderFactory{})
+}
+
+// BitbucketDownloaderFactory defines a bitbucket downloader factory
+type BitbucketDownloaderFactory struct {
+}
+
+// New returns a Downloader related to this factory according MigrateOptions
+func (f *BitbucketDownloaderFactory) New(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(u.Hostname(), "bitbucket.org") {
+		return nil, fmt.Errorf("only repositories hosted on bitbucket.org are supported, got %s", u.Hostname())
+	}
+
+	fields := strings.Split(strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"), "/")
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid path: %s", u.Path)
+	}
+
+	log.Trace("Create bitbucket downloader. Workspace: %s RepoSlug: %s", fields[0], fields[1])
+	return NewBitbucketDownloader(ctx, "https://api.bitbucket.org/2.0", opts.AuthUsername, opts.AuthPassword, fields[0], fields[1]), nil
+}
+
+// GitServiceType returns the type of git service
+func (f *BitbucketDownloaderFactory) GitServiceType() structs.GitServiceType {
+	return structs.BitbucketService
+}
+
+// BitbucketDownloader implements a Downloader interface to get repository information
+// from Bitbucket Cloud via its 2.0 API, authenticating with an app password.
+// Bitbucket numbers issues and pull requests separately, pull requests are
+// numbered after the last issue and pullRequests maps them back to their
+// Bitbucket ID to fetch their comments.
+type BitbucketDownloader struct {
+	base.NullDownloader
+	ctx             context.Context
+	client          *http.Client
+	apiURL          string
+	userName        string
+	password        string
+	workspace       string
+	repoSlug        string
+	maxPerPage      int
+	lastIssueNumber int64
+	pullRequests    map[int64]int64
+}
+
+// NewBitbucketDownloader creates a Bitbucket downloader for the repository workspace/repoSlug
+func NewBitbucketDownloader(ctx context.Context, apiURL, userName, password, workspace, repoSlug string) *BitbucketDownloader {
+	return &BitbucketDownloader{
+		ctx:          ctx,
+		client:       NewMigrationHTTPClient(),
+		apiURL:       strings.TrimSuffix(apiURL, "/"),
+		userName:     userName,
+		password:     password,
+		workspace:    workspace,
+		repoSlug:     repoSlug,
+		maxPerPage:   50,
+		pullRequests: make(map[int64]int64),
+	}
+}
+
+// SetContext set context
+func (d *BitbucketDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+type bitbucketUser struct {
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+}
+
+func (u *bitbucketUser) name() string {
+	if u == nil {
+		return ""
+	}
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+	return u.DisplayName
+}
+
+type bitbucketContent struct {
+	Raw string `json:"raw"`
+}
+
+type bitbucketLink struct {
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+type bitbucketRepository struct {
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	IsPrivate   bool   `json:"is_private"`
+	HasWiki     bool   `json:"has_wiki"`
+	MainBranch  *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Links struct {
+		HTML  bitbucketLink   `json:"html"`
+		Clone []bitbucketLink `json:"clone"`
+	} `json:"links"`
+}
+
+type bitbucketIssue struct {
+	ID        int64            `json:"id"`
+	Title     string           `json:"title"`
+	Content   bitbucketContent `json:"content"`
+	Reporter  *bitbucketUser   `json:"reporter"`
+	Assignee  *bitbucketUser   `json:"assignee"`
+	State     string           `json:"state"`
+	Kind      string           `json:"kind"`
+	Priority  string           `json:"priority"`
+	Milestone *struct {
+		Name string `json:"name"`
+	} `json:"milestone"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+type bitbucketComment struct {
+	Content   bitbucketContent `json:"content"`
+	User      *bitbucketUser   `json:"user"`
+	Deleted   bool             `json:"deleted"`
+	CreatedOn time.Time        `json:"created_on"`
+	UpdatedOn time.Time        `json:"updated_on"`
+}
+
+type bitbucketPullRequestEnd struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit *struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository *bitbucketRepository `json:"repository"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int64                   `json:"id"`
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	State       string                  `json:"state"`
+	Author      *bitbucketUser          `json:"author"`
+	Source      bitbucketPullRequestEnd `json:"source"`
+	Destination bitbucketPullRequestEnd `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	Participants []struct {
+		User           *bitbucketUser `json:"user"`
+		State          string         `json:"state"`
+		Approved       bool           `json:"approved"`
+		ParticipatedOn *time.Time     `json:"participated_on"`
+	} `json:"participants"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+type errBitbucketNotFound struct {
+	path string
+}
+
+func (e errBitbucketNotFound) Error() string {
+	return fmt.Sprintf("bitbucket: %s not found", e.path)
+}
+
+func isErrBitbucketNotFound(err error) bool {
+	_, ok := err.(errBitbucketNotFound)
+	return ok
+}
+
+// callAPI decodes the response of a GET request on the API path into result
+func (d *BitbucketDownloader) callAPI(path string, query url.Values, result interface{}) error {
+	u := d.apiURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if d.userName != "" || d.password != "" {
+		req.SetBasicAuth(d.userName, d.password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errBitbucketNotFound{path}
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("bitbucket: GET %s returned %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (d *BitbucketDownloader) repoPath() string {
+	return "/repositories/" + url.PathEscape(d.workspace) + "/" + url.PathEscape(d.repoSlug)
+}
+
+func pageQuery(page, perPage int) url.Values {
+	return url.Values{
+		"page":    []string{strconv.Itoa(page)},
+		"pagelen": []string{strconv.Itoa(perPage)},
+	}
+}
+
+// GetRepoInfo returns a repository information
+func (d *BitbucketDownloader) GetRepoInfo() (*base.Repository, error) {
+	var repo bitbucketRepository
+	if err := d.callAPI(d.repoPath(), nil, &repo); err != nil {
+		return nil, err
+	}
+
+	var cloneURL string
+	for _, link := range repo.Links.Clone {
+		if link.Name == "https" {
+			cloneURL = link.Href
+		}
+	}
+	if cloneURL == "" {
+		cloneURL = repo.Links.HTML.Href + ".git"
+	}
+	// Clone URLs contain the name of the authenticated user
+	if u, err := url.Parse(cloneURL); err == nil {
+		u.User = nil
+		cloneURL = u.String()
+	}
+
+	var wikiURL string
+	if repo.HasWiki {
+		wikiURL = strings.TrimSuffix(cloneURL, ".git") + ".git/wiki"
+	}
+
+	var defaultBranch string
+	if repo.MainBranch != nil {
+		defaultBranch = repo.MainBranch.Name
+	}
+
+	return &base.Repository{
+		Owner:         d.workspace,
+		Name:          repo.Name,
+		IsPrivate:     repo.IsPrivate,
+		Description:   repo.Description,
+		OriginalURL:   repo.Links.HTML.Href,
+		CloneURL:      cloneURL,
+		WikiURL:       wikiURL,
+		DefaultBranch: defaultBranch,
+	}, nil
+}
+
+// GetTopics returns an empty list as Bitbucket repositories have no topics
+func (d *BitbucketDownloader) GetTopics() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetMilestones returns milestones
+func (d *BitbucketDownloader) GetMilestones() ([]*base.Milestone, error) {
+	var milestones = make([]*base.Milestone, 0, d.maxPerPage)
+	for page := 1; ; page++ {
+		var result struct {
+			Values []struct {
+				Name string `json:"name"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if err := d.callAPI(d.repoPath()+"/milestones", pageQuery(page, d.maxPerPage), &result); err != nil {
+			// the issue tracker is disabled
+			if isErrBitbucketNotFound(err) {
+				return milestones, nil
+			}
+			return nil, err
+		}
+		for _, milestone := range result.Values {
+			milestones = append(milestones, &base.Milestone{
+				Title: milestone.Name,
+				State: "open",
+			})
+		}
+		if result.Next == "" {
+			return milestones, nil
+		}
+	}
+}
+
+var (
+	bitbucketIssueKinds      = []string{"bug", "enhancement", "proposal", "task"}
+	bitbucketIssuePriorities = []string{"trivial", "minor", "major", "critical", "blocker"}
+	bitbucketPriorityColors  = []string{"c2e0c6", "fef2c0", "fbca04", "eb6420", "b60205"}
+)
+
+// GetLabels returns the labels used for the kinds and priorities of Bitbucket issues
+func (d *BitbucketDownloader) GetLabels() ([]*base.Label, error) {
+	labels := make([]*base.Label, 0, len(bitbucketIssueKinds)+len(bitbucketIssuePriorities))
+	for _, kind := range bitbucketIssueKinds {
+		labels = append(labels, &base.Label{
+			Name:  "kind/" + kind,
+			Color: "1d76db",
+		})
+	}
+	for i, priority := range bitbucketIssuePriorities {
+		labels = append(labels, &base.Label{
+			Name:  "priority/" + priority,
+			Color: bitbucketPriorityColors[i],
+		})
+	}
+	return labels, nil
+}
+
+// GetIssues returns issues according page and perPage
+func (d *BitbucketDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	if perPage > d.maxPerPage {
+		perPage = d.maxPerPage
+	}
+
+	var result struct {
+		Values []*bitbucketIssue `json:"values"`
+		Next   string            `json:"next"`
+	}
+	query := pageQuery(page, perPage)
+	query.Set("sort", "id")
+	if err := d.callAPI(d.repoPath()+"/issues", query, &result); err != nil {
+		// the issue tracker is disabled
+		if isErrBitbucketNotFound(err) {
+			return []*base.Issue{}, true, nil
+		}
+		return nil, false, fmt.Errorf("error while listing issues: %v", err)
+	}
+
+	var allIssues = make([]*base.Issue, 0, len(result.Values))
+	for _, issue := range result.Values {
+		var state string
+		var closed *time.Time
+		switch issue.State {
+		case "new", "open", "on hold":
+			state = "open"
+		default:
+			state = "closed"
+			closed = &issue.UpdatedOn
+		}
+
+		var labels []*base.Label
+		if issue.Kind != "" {
+			labels = append(labels, &base.Label{Name: "kind/" + issue.Kind})
+		}
+		if issue.Priority != "" {
+			labels = append(labels, &base.Label{Name: "priority/" + issue.Priority})
+		}
+
+		var milestone string
+		if issue.Milestone != nil {
+			milestone = issue.Milestone.Name
+		}
+
+		var assignees []string
+		if issue.Assignee != nil {
+			assignees = []string{issue.Assignee.name()}
+		}
+
+		allIssues = append(allIssues, &base.Issue{
+			Number:     issue.ID,
+			PosterName: issue.Reporter.name(),
+			Title:      issue.Title,
+			Content:    issue.Content.Raw,
+			Milestone:  milestone,
+			State:      state,
+			Created:    issue.CreatedOn,
+			Updated:    issue.UpdatedOn,
+			Closed:     closed,
+			Labels:     labels,
+			Assignees:  assignees,
+		})
+
+		if issue.ID > d.lastIssueNumber {
+			d.lastIssueNumber = issue.ID
+		}
+	}
+
+	return allIssues, result.Next == "", nil
+}
+
+// GetComments returns comments according issueNumber
+func (d *BitbucketDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comment, bool, error) {
+	path := d.repoPath() + "/issues/" + strconv.FormatInt(opts.IssueNumber, 10) + "/comments"
+	if id, ok := d.pullRequests[opts.IssueNumber]; ok {
+		path = d.repoPath() + "/pullrequests/" + strconv.FormatInt(id, 10) + "/comments"
+	}
+
+	var allComments = make([]*base.Comment, 0, d.maxPerPage)
+	for page := 1; ; page++ {
+		var result struct {
+			Values []*bitbucketComment `json:"values"`
+			Next   string              `json:"next"`
+		}
+		if err := d.callAPI(path, pageQuery(page, d.maxPerPage), &result); err != nil {
+			return nil, false, fmt.Errorf("error while listing comments: %v", err)
+		}
+		for _, comment := range result.Values {
+			// state changes of issues are comments without content
+			if comment.Deleted || strings.TrimSpace(comment.Content.Raw) == "" {
+				continue
+			}
+			allComments = append(allComments, &base.Comment{
+				IssueIndex: opts.IssueNumber,
+				PosterName: comment.User.name(),
+				Content:    comment.Content.Raw,
+				Created:    comment.CreatedOn,
+				Updated:    comment.UpdatedOn,
+			})
+		}
+		if result.Next == "" {
+			return allComments, true, nil
+		}
+	}
+}
+
+// resolveCommit returns the full hash of the abbreviated commit hash returned for pull requests
+func (d *BitbucketDownloader) resolveCommit(repoFullName, hash string) (string, error) {
+	var commit struct {
+		Hash string `json:"hash"`
+	}
+	if err := d.callAPI("/repositories/"+repoFullName+"/commit/"+url.PathEscape(hash), nil, &commit); err != nil {
+		return "", err
+	}
+	return commit.Hash, nil
+}
+
+func (d *BitbucketDownloader) convertPullRequestEnd(end *bitbucketPullRequestEnd, number int64) (base.PullRequestBranch, error) {
+	branch := base.PullRequestBranch{
+		Ref:       end.Branch.Name,
+		OwnerName: d.workspace,
+		RepoName:  d.repoSlug,
+	}
+	repoFullName := d.workspace + "/" + d.repoSlug
+	if end.Repository != nil && end.Repository.FullName != "" {
+		repoFullName = end.Repository.FullName
+		fields := strings.SplitN(repoFullName, "/", 2)
+		if len(fields) == 2 && !strings.EqualFold(repoFullName, d.workspace+"/"+d.repoSlug) {
+			branch.OwnerName, branch.RepoName = fields[0], fields[1]
+			branch.CloneURL = end.Repository.Links.HTML.Href + ".git"
+		}
+	}
+	if end.Commit == nil || end.Commit.Hash == "" {
+		return branch, nil
+	}
+
+	sha, err := d.resolveCommit(repoFullName, end.Commit.Hash)
+	if err != nil {
+		if !isErrBitbucketNotFound(err) {
+			return branch, err
+		}
+		log.Warn("BitbucketDownloader: commit %s of pull request %d not found in %s", end.Commit.Hash, number, repoFullName)
+	}
+	branch.SHA = sha
+	return branch, nil
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (d *BitbucketDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	if perPage > d.maxPerPage {
+		perPage = d.maxPerPage
+	}
+
+	var result struct {
+		Values []*bitbucketPullRequest `json:"values"`
+		Next   string                  `json:"next"`
+	}
+	query := pageQuery(page, perPage)
+	query.Set("sort", "id")
+	for _, state := range []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"} {
+		query.Add("state", state)
+	}
+	if err := d.callAPI(d.repoPath()+"/pullrequests", query, &result); err != nil {
+		return nil, false, fmt.Errorf("error while listing pull requests: %v", err)
+	}
+
+	var allPRs = make([]*base.PullRequest, 0, len(result.Values))
+	for _, pr := range result.Values {
+		// Pull requests are numbered after the issues as they share the same numbers in Gitea
+		number := d.lastIssueNumber + pr.ID
+		d.pullRequests[number] = pr.ID
+
+		head, err := d.convertPullRequestEnd(&pr.Source, pr.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		baseBranch, err := d.convertPullRequestEnd(&pr.Destination, pr.ID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		state := "open"
+		var closed, mergedTime *time.Time
+		var mergeCommitSHA string
+		merged := pr.State == "MERGED"
+		if pr.State != "OPEN" {
+			state = "closed"
+			closed = &pr.UpdatedOn
+		}
+		if merged {
+			mergedTime = &pr.UpdatedOn
+			if pr.MergeCommit != nil && pr.MergeCommit.Hash != "" {
+				mergeCommitSHA, err = d.resolveCommit(d.workspace+"/"+d.repoSlug, pr.MergeCommit.Hash)
+				if err != nil && !isErrBitbucketNotFound(err) {
+					return nil, false, err
+				}
+			}
+		}
+
+		// the head commit of a pull request from a deleted fork can't be resolved
+		if head.SHA == "" {
+			head.SHA = mergeCommitSHA
+		}
+		if head.SHA == "" {
+			head.SHA = baseBranch.SHA
+		}
+
+		allPRs = append(allPRs, &base.PullRequest{
+			Number:         number,
+			OriginalNumber: pr.ID,
+			Title:          pr.Title,
+			PosterName:     pr.Author.name(),
+			Content:        pr.Description,
+			State:          state,
+			Created:        pr.CreatedOn,
+			Updated:        pr.UpdatedOn,
+			Closed:         closed,
+			Merged:         merged,
+			MergedTime:     mergedTime,
+			MergeCommitSHA: mergeCommitSHA,
+			Head:           head,
+			Base:           baseBranch,
+		})
+	}
+
+	return allPRs, result.Next == "", nil
+}
+
+// GetReviews returns the approvals and change requests of a pull request according to its Bitbucket ID
+func (d *BitbucketDownloader) GetReviews(pullRequestNumber int64) ([]*base.Review, error) {
+	var pr bitbucketPullRequest
+	if err := d.callAPI(d.repoPath()+"/pullrequests/"+strconv.FormatInt(pullRequestNumber, 10), nil, &pr); err != nil {
+		return nil, err
+	}
+
+	var reviews = make([]*base.Review, 0, len(pr.Participants))
+	for _, participant := range pr.Participants {
+		var state string
+		switch {
+		case participant.Approved || participant.State == "approved":
+			state = base.ReviewStateApproved
+		case participant.State == "changes_requested":
+			state = base.ReviewStateChangesRequested
+		default:
+			continue
+		}
+
+		createdAt := pr.UpdatedOn
+		if participant.ParticipatedOn != nil {
+			createdAt = *participant.ParticipatedOn
+		}
+		reviews = append(reviews, &base.Review{
+			IssueIndex:   pullRequestNumber,
+			ReviewerName: participant.User.name(),
+			CreatedAt:    createdAt,
+			State:        state,
+		})
+	}
+	return reviews, nil
+}
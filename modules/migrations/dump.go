@@ -160,7 +160,14 @@ func (g *RepositoryDumper) CreateRepo(repo *base.Repository, opts base.MigrateOp
 
 	if opts.Wiki {
 		wikiPath := g.wikiPath()
-		wikiRemotePath := repository.WikiRemoteURL(remoteAddr)
+		var wikiRemotePath string
+		if len(repo.WikiURL) > 0 {
+			if wikiRemotePath, err = g.setURLToken(repo.WikiURL); err != nil {
+				return err
+			}
+		} else {
+			wikiRemotePath = repository.WikiRemoteURL(remoteAddr)
+		}
 		if len(wikiRemotePath) > 0 {
 			if err := os.MkdirAll(wikiPath, os.ModePerm); err != nil {
 				return fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
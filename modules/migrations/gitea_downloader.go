@@ -87,6 +87,7 @@ func NewGiteaDownloader(ctx context.Context, baseURL, repoPath, username, passwo
 		gitea_sdk.SetToken(token),
 		gitea_sdk.SetBasicAuth(username, password),
 		gitea_sdk.SetContext(ctx),
+		gitea_sdk.SetHTTPClient(NewMigrationHTTPClient()),
 	)
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed to create NewGiteaDownloader for: %s. Error: %v", baseURL, err))
@@ -139,6 +140,11 @@ func (g *GiteaDownloader) GetRepoInfo() (*base.Repository, error) {
 		return nil, err
 	}
 
+	wikiURL := ""
+	if repo.HasWiki && repo.ExternalWiki == nil {
+		wikiURL = wikiCloneURL(repo.CloneURL)
+	}
+
 	return &base.Repository{
 		Name:          repo.Name,
 		Owner:         repo.Owner.UserName,
@@ -146,6 +152,7 @@ func (g *GiteaDownloader) GetRepoInfo() (*base.Repository, error) {
 		Description:   repo.Description,
 		CloneURL:      repo.CloneURL,
 		OriginalURL:   repo.HTMLURL,
+		WikiURL:       wikiURL,
 		DefaultBranch: repo.DefaultBranch,
 	}, nil
 }
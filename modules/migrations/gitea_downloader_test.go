@@ -70,6 +70,7 @@ func TestGiteaDownloadRepo(t *testing.T) {
 		Description:   "Test repository for testing migration from gitea to gitea",
 		CloneURL:      "https://gitea.com/gitea/test_repo.git",
 		OriginalURL:   "https://gitea.com/gitea/test_repo",
+		WikiURL:       "https://gitea.com/gitea/test_repo.wiki.git",
 		DefaultBranch: "master",
 	}, repo)
 
@@ -121,6 +121,7 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 		CloneAddr:      repo.CloneURL,
 		Private:        repo.IsPrivate,
 		Wiki:           opts.Wiki,
+		WikiCloneAddr:  repo.WikiURL,
 		Releases:       opts.Releases, // if didn't get releases, then sync them from tags
 		MirrorInterval: opts.MirrorInterval,
 	})
@@ -553,16 +554,33 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 		}
 	}
 
-	// download patch file
-	err := func() error {
-		// pr.PatchURL maybe a local file
-		ret, err := uri.Open(pr.PatchURL)
+	// some services (e.g. gogs) don't expose the commits of a pull request,
+	// take them from the pull request ref mirrored with the repository
+	if pr.Head.SHA == "" {
+		headSHA, err := g.gitRepo.GetRefCommitID(fmt.Sprintf("refs/pull/%d/head", pr.Number))
+		if git.IsErrNotExist(err) {
+			// the source didn't keep the commits of the pull request, keep its discussion as a closed pull request
+			log.Warn("Pull request %d has no head ref, it is migrated closed", pr.Number)
+			pr.State = "closed"
+		} else if err != nil {
+			return nil, fmt.Errorf("GetRefCommitID: %v", err)
+		} else {
+			pr.Head.SHA = headSHA
+		}
+	}
+	if pr.Base.SHA == "" && pr.Base.Ref != "" && pr.Head.SHA != "" {
+		mergeBase, _, err := g.gitRepo.GetMergeBase("", git.BranchPrefix+pr.Base.Ref, pr.Head.SHA)
 		if err != nil {
-			return err
+			log.Warn("GetMergeBase of pull request %d failed: %v", pr.Number, err)
+		} else {
+			pr.Base.SHA = mergeBase
 		}
-		defer ret.Close()
+	}
+
+	// download patch file
+	err := func() error {
 		pullDir := filepath.Join(g.repo.RepoPath(), "pulls")
-		if err = os.MkdirAll(pullDir, os.ModePerm); err != nil {
+		if err := os.MkdirAll(pullDir, os.ModePerm); err != nil {
 			return err
 		}
 		f, err := os.Create(filepath.Join(pullDir, fmt.Sprintf("%d.patch", pr.Number)))
@@ -570,6 +588,20 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 			return err
 		}
 		defer f.Close()
+
+		if pr.PatchURL == "" {
+			if pr.Base.SHA == "" {
+				return nil
+			}
+			return g.gitRepo.GetPatch(pr.Base.SHA, pr.Head.SHA, f)
+		}
+
+		// pr.PatchURL maybe a local file
+		ret, err := uri.Open(pr.PatchURL)
+		if err != nil {
+			return err
+		}
+		defer ret.Close()
 		_, err = io.Copy(f, ret)
 		return err
 	}()
@@ -578,18 +610,20 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 	}
 
 	// set head information
-	pullHead := filepath.Join(g.repo.RepoPath(), "refs", "pull", fmt.Sprintf("%d", pr.Number))
-	if err := os.MkdirAll(pullHead, os.ModePerm); err != nil {
-		return nil, err
-	}
-	p, err := os.Create(filepath.Join(pullHead, "head"))
-	if err != nil {
-		return nil, err
-	}
-	_, err = p.WriteString(pr.Head.SHA)
-	p.Close()
-	if err != nil {
-		return nil, err
+	if pr.Head.SHA != "" {
+		pullHead := filepath.Join(g.repo.RepoPath(), "refs", "pull", fmt.Sprintf("%d", pr.Number))
+		if err := os.MkdirAll(pullHead, os.ModePerm); err != nil {
+			return nil, err
+		}
+		p, err := os.Create(filepath.Join(pullHead, "head"))
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.WriteString(pr.Head.SHA)
+		p.Close()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var head = "unknown repository"
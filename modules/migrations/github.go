@@ -17,6 +17,7 @@ import (
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 
@@ -90,7 +91,7 @@ func NewGithubDownloaderV3(ctx context.Context, baseURL, userName, password, tok
 		Transport: &http.Transport{
 			Proxy: func(req *http.Request) (*url.URL, error) {
 				req.SetBasicAuth(userName, password)
-				return nil, nil
+				return proxy.Proxy()(req)
 			},
 		},
 	}
@@ -98,7 +99,12 @@ func NewGithubDownloaderV3(ctx context.Context, baseURL, userName, password, tok
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		client = oauth2.NewClient(downloader.ctx, ts)
+		client = &http.Client{
+			Transport: &oauth2.Transport{
+				Base:   &http.Transport{Proxy: proxy.Proxy()},
+				Source: oauth2.ReuseTokenSource(nil, ts),
+			},
+		}
 	}
 	downloader.client = github.NewClient(client)
 	if baseURL != "https://github.com" {
@@ -159,6 +165,11 @@ func (g *GithubDownloaderV3) GetRepoInfo() (*base.Repository, error) {
 		defaultBranch = *gr.DefaultBranch
 	}
 
+	wikiURL := ""
+	if gr.GetHasWiki() {
+		wikiURL = wikiCloneURL(gr.GetCloneURL())
+	}
+
 	// convert github repo to stand Repo
 	return &base.Repository{
 		Owner:         g.repoOwner,
@@ -167,6 +178,7 @@ func (g *GithubDownloaderV3) GetRepoInfo() (*base.Repository, error) {
 		Description:   gr.GetDescription(),
 		OriginalURL:   gr.GetHTMLURL(),
 		CloneURL:      gr.GetCloneURL(),
+		WikiURL:       wikiURL,
 		DefaultBranch: defaultBranch,
 	}, nil
 }
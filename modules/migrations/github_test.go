@@ -77,6 +77,7 @@ func TestGitHubDownloadRepo(t *testing.T) {
 		Description:   "Test repository for testing migration from github to gitea",
 		CloneURL:      "https://github.com/go-gitea/test_repo.git",
 		OriginalURL:   "https://github.com/go-gitea/test_repo",
+		WikiURL:       "https://github.com/go-gitea/test_repo.wiki.git",
 		DefaultBranch: "master",
 	}, repo)
 
@@ -77,11 +77,11 @@ type GitlabDownloader struct {
 //   Use either a username/password, personal token entered into the username field, or anonymous/public access
 //   Note: Public access only allows very basic access
 func NewGitlabDownloader(ctx context.Context, baseURL, repoPath, username, password, token string) (*GitlabDownloader, error) {
-	gitlabClient, err := gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
+	gitlabClient, err := gitlab.NewClient(token, gitlab.WithBaseURL(baseURL), gitlab.WithHTTPClient(NewMigrationHTTPClient()))
 	// Only use basic auth if token is blank and password is NOT
 	// Basic auth will fail with empty strings, but empty token will allow anonymous public API usage
 	if token == "" && password != "" {
-		gitlabClient, err = gitlab.NewBasicAuthClient(username, password, gitlab.WithBaseURL(baseURL))
+		gitlabClient, err = gitlab.NewBasicAuthClient(username, password, gitlab.WithBaseURL(baseURL), gitlab.WithHTTPClient(NewMigrationHTTPClient()))
 	}
 
 	if err != nil {
@@ -164,6 +164,11 @@ func (g *GitlabDownloader) GetRepoInfo() (*base.Repository, error) {
 		owner = gr.Owner.Username
 	}
 
+	wikiURL := ""
+	if gr.WikiEnabled {
+		wikiURL = wikiCloneURL(gr.HTTPURLToRepo)
+	}
+
 	// convert gitlab repo to stand Repo
 	return &base.Repository{
 		Owner:         owner,
@@ -172,6 +177,7 @@ func (g *GitlabDownloader) GetRepoInfo() (*base.Repository, error) {
 		Description:   gr.Description,
 		OriginalURL:   gr.WebURL,
 		CloneURL:      gr.HTTPURLToRepo,
+		WikiURL:       wikiURL,
 		DefaultBranch: gr.DefaultBranch,
 	}, nil
 }
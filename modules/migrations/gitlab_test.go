@@ -42,6 +42,7 @@ func TestGitlabDownloadRepo(t *testing.T) {
 		Description:   "Test repository for testing migration from gitlab to gitea",
 		CloneURL:      "https://gitlab.com/gitea/test_repo.git",
 		OriginalURL:   "https://gitlab.com/gitea/test_repo",
+		WikiURL:       "https://gitlab.com/gitea/test_repo.wiki.git",
 		DefaultBranch: "master",
 	}, repo)
 
@@ -6,7 +6,9 @@ package migrations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,6 +16,7 @@ import (
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/structs"
 
 	"github.com/gogs/go-gogs-client"
@@ -68,6 +71,8 @@ type GogsDownloader struct {
 	repoName           string
 	userName           string
 	password           string
+	token              string
+	defaultBranch      string
 	openIssuesFinished bool
 	openIssuesPages    int
 	transport          http.RoundTripper
@@ -85,6 +90,7 @@ func NewGogsDownloader(ctx context.Context, baseURL, userName, password, token,
 		baseURL:   baseURL,
 		userName:  userName,
 		password:  password,
+		token:     token,
 		repoOwner: repoOwner,
 		repoName:  repoName,
 	}
@@ -92,12 +98,17 @@ func NewGogsDownloader(ctx context.Context, baseURL, userName, password, token,
 	var client *gogs.Client
 	if len(token) != 0 {
 		client = gogs.NewClient(baseURL, token)
+		httpClient := NewMigrationHTTPClient()
+		client.SetHTTPClient(httpClient)
+		downloader.transport = httpClient.Transport
 		downloader.userName = token
 	} else {
 		downloader.transport = &http.Transport{
 			Proxy: func(req *http.Request) (*url.URL, error) {
-				req.SetBasicAuth(userName, password)
-				return nil, nil
+				if downloader.isGogsURL(req.URL) {
+					req.SetBasicAuth(userName, password)
+				}
+				return proxy.Proxy()(req)
 			},
 		}
 
@@ -124,7 +135,9 @@ func (g *GogsDownloader) GetRepoInfo() (*base.Repository, error) {
 		return nil, err
 	}
 
-	// convert gogs repo to stand Repo
+	g.defaultBranch = gr.DefaultBranch
+
+	// convert gogs repo to stand Repo, the API of gogs does not tell whether the wiki is enabled
 	return &base.Repository{
 		Owner:         g.repoOwner,
 		Name:          g.repoName,
@@ -132,6 +145,7 @@ func (g *GogsDownloader) GetRepoInfo() (*base.Repository, error) {
 		Description:   gr.Description,
 		CloneURL:      gr.CloneURL,
 		OriginalURL:   gr.HTMLURL,
+		WikiURL:       wikiCloneURL(gr.CloneURL),
 		DefaultBranch: gr.DefaultBranch,
 	}, nil
 }
@@ -253,6 +267,172 @@ func (g *GogsDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comme
 	return allComments, true, nil
 }
 
+// GetPullRequests returns pull requests according page and perPage.
+// Gogs API can't list pull requests, but they share their indexes with the issues,
+// so every index of the page is fetched and the pull requests are picked out.
+func (g *GogsDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	if len(g.defaultBranch) == 0 {
+		gr, err := g.client.GetRepo(g.repoOwner, g.repoName)
+		if err != nil {
+			return nil, false, err
+		}
+		g.defaultBranch = gr.DefaultBranch
+	}
+
+	var allPRs = make([]*base.PullRequest, 0, perPage)
+	var found bool
+	for index := int64((page-1)*perPage + 1); index <= int64(page*perPage); index++ {
+		issue, err := g.getIssue(index)
+		if err != nil {
+			if statusErr, ok := err.(*errUnexpectedStatus); ok && statusErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, false, fmt.Errorf("error while getting issue %d: %v", index, err)
+		}
+		found = true
+
+		if issue.PullRequest == nil {
+			continue
+		}
+		allPRs = append(allPRs, convertGogsPullRequest(issue, g.defaultBranch))
+	}
+
+	return allPRs, !found, nil
+}
+
+// getIssue returns the issue or the pull request of the index, the requests are sent without the gogs client
+// to tell the missing indexes from the other errors by the status of the response
+func (g *GogsDownloader) getIssue(index int64) (*gogs.Issue, error) {
+	resp, err := g.doRequest(g.baseURL + fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", g.repoOwner, g.repoName, index))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	issue := new(gogs.Issue)
+	return issue, json.NewDecoder(resp.Body).Decode(issue)
+}
+
+// gogsRelease is the release API object of gogs including the attachments
+// which the gogs client doesn't decode
+type gogsRelease struct {
+	gogs.Release
+	Assets []*gogsReleaseAsset `json:"assets"`
+}
+
+type gogsReleaseAsset struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Size          int       `json:"size"`
+	DownloadCount int       `json:"download_count"`
+	Created       time.Time `json:"created_at"`
+	DownloadURL   string    `json:"browser_download_url"`
+}
+
+// GetReleases returns releases
+func (g *GogsDownloader) GetReleases() ([]*base.Release, error) {
+	resp, err := g.doRequest(g.baseURL + fmt.Sprintf("/api/v1/repos/%s/%s/releases", g.repoOwner, g.repoName))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing releases: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var rels []*gogsRelease
+	if err = json.NewDecoder(resp.Body).Decode(&rels); err != nil {
+		return nil, err
+	}
+
+	var releases = make([]*base.Release, 0, len(rels))
+	for _, rel := range rels {
+		releases = append(releases, g.convertGogsRelease(rel))
+	}
+	return releases, nil
+}
+
+// errUnexpectedStatus is returned by doRequest if gogs doesn't respond with a success status
+type errUnexpectedStatus struct {
+	StatusCode int
+	Status     string
+}
+
+func (err *errUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected status %s", err.Status)
+}
+
+// isGogsURL returns true if the URL points to the gogs instance, the credentials must not be sent to other hosts
+func (g *GogsDownloader) isGogsURL(u *url.URL) bool {
+	base, err := url.Parse(g.baseURL)
+	return err == nil && strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+// doRequest sends a GET request, authenticated if it is sent to the gogs instance.
+// The gogs client doesn't cover every endpoint and can't download attachments
+func (g *GogsDownloader) doRequest(link string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(g.ctx, "GET", link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.isGogsURL(req.URL) {
+		if len(g.token) != 0 {
+			req.Header.Set("Authorization", "token "+g.token)
+		} else if len(g.userName) != 0 {
+			req.SetBasicAuth(g.userName, g.password)
+		}
+	}
+
+	httpClient := &http.Client{Transport: g.transport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, &errUnexpectedStatus{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return resp, nil
+}
+
+func (g *GogsDownloader) convertGogsRelease(rel *gogsRelease) *base.Release {
+	r := &base.Release{
+		TagName:         rel.TagName,
+		TargetCommitish: rel.TargetCommitish,
+		Name:            rel.Name,
+		Body:            rel.Body,
+		Draft:           rel.Draft,
+		Prerelease:      rel.Prerelease,
+		Created:         rel.Created,
+		Published:       rel.Created,
+		Assets:          make([]*base.ReleaseAsset, 0, len(rel.Assets)),
+	}
+	if rel.Author != nil {
+		r.PublisherID = rel.Author.ID
+		r.PublisherName = rel.Author.Login
+		r.PublisherEmail = rel.Author.Email
+	}
+
+	for _, asset := range rel.Assets {
+		var downloadURL = asset.DownloadURL
+		var size = asset.Size
+		var downloadCount = asset.DownloadCount
+		r.Assets = append(r.Assets, &base.ReleaseAsset{
+			ID:            asset.ID,
+			Name:          asset.Name,
+			Size:          &size,
+			DownloadCount: &downloadCount,
+			Created:       asset.Created,
+			Updated:       asset.Created,
+			DownloadFunc: func() (io.ReadCloser, error) {
+				resp, err := g.doRequest(downloadURL)
+				if err != nil {
+					return nil, err
+				}
+				return resp.Body, nil
+			},
+		})
+	}
+	return r
+}
+
 // GetTopics return repository topics
 func (g *GogsDownloader) GetTopics() ([]string, error) {
 	return []string{}, nil
@@ -305,6 +485,55 @@ func convertGogsIssue(issue *gogs.Issue) *base.Issue {
 	}
 }
 
+// convertGogsPullRequest converts a gogs issue of a pull request. Gogs doesn't expose
+// the branches and commits of a pull request, so the head commit is taken from the
+// pull request ref mirrored with the repository and the pull request is migrated
+// closed (or merged) since it can't be kept in sync with its head branch.
+func convertGogsPullRequest(issue *gogs.Issue, baseBranch string) *base.PullRequest {
+	var milestone string
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+	var labels = make([]*base.Label, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		labels = append(labels, convertGogsLabel(l))
+	}
+
+	var closed = issue.Updated
+	if issue.PullRequest.HasMerged && issue.PullRequest.Merged != nil {
+		closed = *issue.PullRequest.Merged
+	}
+
+	var assignees []string
+	if issue.Assignee != nil {
+		assignees = append(assignees, issue.Assignee.Login)
+	}
+
+	return &base.PullRequest{
+		Number:      issue.Index,
+		Title:       issue.Title,
+		PosterID:    issue.Poster.ID,
+		PosterName:  issue.Poster.Login,
+		PosterEmail: issue.Poster.Email,
+		Content:     issue.Body,
+		Milestone:   milestone,
+		State:       string(gogs.STATE_CLOSED),
+		Created:     issue.Created,
+		Updated:     issue.Updated,
+		Closed:      &closed,
+		Labels:      labels,
+		Merged:      issue.PullRequest.HasMerged,
+		MergedTime:  issue.PullRequest.Merged,
+		Head: base.PullRequestBranch{
+			Ref: fmt.Sprintf("pull/%d/head", issue.Index),
+		},
+		Base: base.PullRequestBranch{
+			Ref: baseBranch,
+		},
+		Assignees: assignees,
+	}
+}
+
 func convertGogsLabel(label *gogs.Label) *base.Label {
 	return &base.Label{
 		Name:  label.Name,
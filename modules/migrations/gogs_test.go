@@ -6,8 +6,10 @@ package migrations
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,6 +41,7 @@ func TestGogsDownloadRepo(t *testing.T) {
 		Owner:       "lunnytest",
 		Description: "",
 		CloneURL:    "https://try.gogs.io/lunnytest/TESTREPO.git",
+		WikiURL:     "https://try.gogs.io/lunnytest/TESTREPO.wiki.git",
 	}, repo)
 
 	milestones, err := downloader.GetMilestones()
@@ -75,7 +78,7 @@ func TestGogsDownloadRepo(t *testing.T) {
 	}
 
 	_, err = downloader.GetReleases()
-	assert.Error(t, err)
+	assert.NoError(t, err)
 
 	// downloader.GetIssues()
 	issues, isEnd, err := downloader.GetIssues(1, 8)
@@ -119,6 +122,58 @@ func TestGogsDownloadRepo(t *testing.T) {
 	}, comments)
 
 	// downloader.GetPullRequests()
-	_, _, err = downloader.GetPullRequests(1, 3)
-	assert.Error(t, err)
+	prs, isEnd, err := downloader.GetPullRequests(1, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, prs)
+	assert.False(t, isEnd)
+}
+
+type gogsTestRoundTripper func(req *http.Request) (*http.Response, error)
+
+func (f gogsTestRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGogsDownloaderDoRequest(t *testing.T) {
+	var requested, authorization string
+	downloader := &GogsDownloader{
+		ctx:     context.Background(),
+		baseURL: "https://gogs.example.com",
+		token:   "token",
+		// the transport of the downloader applies the proxy settings
+		transport: gogsTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			authorization = req.Header.Get("Authorization")
+			status := http.StatusOK
+			if strings.HasSuffix(req.URL.Path, "/missing") {
+				status = http.StatusNotFound
+			}
+			return &http.Response{
+				StatusCode: status,
+				Status:     http.StatusText(status),
+				Body:       ioutil.NopCloser(strings.NewReader("asset")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	resp, err := downloader.doRequest("https://gogs.example.com/attachments/asset.zip")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "https://gogs.example.com/attachments/asset.zip", requested)
+	assert.Equal(t, "token token", authorization)
+
+	// the credentials are not sent to other hosts
+	resp, err = downloader.doRequest("https://cdn.example.com/asset.zip")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, authorization)
+
+	_, err = downloader.doRequest("https://gogs.example.com/missing")
+	if assert.IsType(t, &errUnexpectedStatus{}, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errUnexpectedStatus).StatusCode)
+	}
+
+	downloader = NewGogsDownloader(context.Background(), "https://gogs.example.com", "", "", "token", "owner", "repo")
+	assert.NotNil(t, downloader.transport)
 }
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/matchlist"
 	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
@@ -36,6 +38,13 @@ func RegisterDownloaderFactory(factory base.DownloaderFactory) {
 	factories = append(factories, factory)
 }
 
+// NewMigrationHTTPClient returns a HTTP client for the downloaders which uses the configured proxy
+func NewMigrationHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+}
+
 // IsMigrateURLAllowed checks if an URL is allowed to be migrated from
 func IsMigrateURLAllowed(remoteURL string, doer *models.User) error {
 	// Remote address can be HTTP/HTTPS/Git URL or local path.
@@ -187,6 +196,11 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 	if repo.CloneURL, err = downloader.FormatCloneURL(opts, repo.CloneURL); err != nil {
 		return err
 	}
+	if len(repo.WikiURL) > 0 {
+		if repo.WikiURL, err = downloader.FormatCloneURL(opts, repo.WikiURL); err != nil {
+			return err
+		}
+	}
 
 	log.Trace("migrating git data from %s", repo.CloneURL)
 	messenger("repo.migrate.migrating_git")
@@ -471,6 +485,12 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 	return uploader.Finish()
 }
 
+// wikiCloneURL returns the clone URL of the wiki of the repository of the passed clone URL,
+// GitHub, GitLab, Gogs and Gitea serve it next to the repository as *.wiki.git
+func wikiCloneURL(cloneURL string) string {
+	return strings.TrimSuffix(cloneURL, ".git") + ".wiki.git"
+}
+
 // Init migrations service
 func Init() error {
 	var err error
@@ -71,3 +71,8 @@ func TestMigrateWhiteBlocklist(t *testing.T) {
 
 	setting.ImportLocalPaths = old
 }
+
+func TestWikiCloneURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/go-gitea/gitea.wiki.git", wikiCloneURL("https://github.com/go-gitea/gitea.git"))
+	assert.Equal(t, "https://try.gogs.io/lunnytest/TESTREPO.wiki.git", wikiCloneURL("https://try.gogs.io/lunnytest/TESTREPO"))
+}
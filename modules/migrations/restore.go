@@ -13,6 +13,7 @@ import (
 	"strconv"
 
 	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/util"
 
 	"gopkg.in/yaml.v2"
 )
@@ -77,6 +78,11 @@ func (r *RepositoryRestorer) GetRepoInfo() (*base.Repository, error) {
 
 	isPrivate, _ := strconv.ParseBool(opts["is_private"])
 
+	wikiURL := ""
+	if isDir, _ := util.IsDir(filepath.Join(r.baseDir, "wiki")); isDir {
+		wikiURL = filepath.Join(r.baseDir, "wiki")
+	}
+
 	return &base.Repository{
 		Owner:         r.repoOwner,
 		Name:          r.repoName,
@@ -84,6 +90,7 @@ func (r *RepositoryRestorer) GetRepoInfo() (*base.Repository, error) {
 		Description:   opts["description"],
 		OriginalURL:   opts["original_url"],
 		CloneURL:      filepath.Join(r.baseDir, "git"),
+		WikiURL:       wikiURL,
 		DefaultBranch: opts["default_branch"],
 	}, nil
 }
@@ -59,4 +59,16 @@ type Notifier interface {
 	NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string)
 
 	NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository)
+	NotifyRepoAccessRequest(doer *models.User, req *models.RepoAccessRequest)
+	NotifyRepoAccessRequestReviewed(doer *models.User, req *models.RepoAccessRequest)
+
+	NotifyUpdateBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch, isNew bool)
+	NotifyDeleteBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch)
+	NotifyAddCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User, mode models.AccessMode)
+	NotifyChangeCollaboratorAccessMode(doer *models.User, repo *models.Repository, collaborator *models.User, oldMode, mode models.AccessMode)
+	NotifyRemoveCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User)
+	NotifyChangeRepositoryVisibility(doer *models.User, repo *models.Repository)
+
+	NotifyCreateDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment)
+	NotifyTearDownDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment)
 }
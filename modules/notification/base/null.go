@@ -170,3 +170,43 @@ func (*NullNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Reposit
 // NotifyRepoPendingTransfer places a place holder function
 func (*NullNotifier) NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository) {
 }
+
+// NotifyRepoAccessRequest places a place holder function
+func (*NullNotifier) NotifyRepoAccessRequest(doer *models.User, req *models.RepoAccessRequest) {
+}
+
+// NotifyRepoAccessRequestReviewed places a place holder function
+func (*NullNotifier) NotifyRepoAccessRequestReviewed(doer *models.User, req *models.RepoAccessRequest) {
+}
+
+// NotifyUpdateBranchProtection places a place holder function
+func (*NullNotifier) NotifyUpdateBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch, isNew bool) {
+}
+
+// NotifyDeleteBranchProtection places a place holder function
+func (*NullNotifier) NotifyDeleteBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch) {
+}
+
+// NotifyAddCollaborator places a place holder function
+func (*NullNotifier) NotifyAddCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User, mode models.AccessMode) {
+}
+
+// NotifyChangeCollaboratorAccessMode places a place holder function
+func (*NullNotifier) NotifyChangeCollaboratorAccessMode(doer *models.User, repo *models.Repository, collaborator *models.User, oldMode, mode models.AccessMode) {
+}
+
+// NotifyRemoveCollaborator places a place holder function
+func (*NullNotifier) NotifyRemoveCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User) {
+}
+
+// NotifyChangeRepositoryVisibility places a place holder function
+func (*NullNotifier) NotifyChangeRepositoryVisibility(doer *models.User, repo *models.Repository) {
+}
+
+// NotifyCreateDeployment places a place holder function
+func (*NullNotifier) NotifyCreateDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+}
+
+// NotifyTearDownDeployment places a place holder function
+func (*NullNotifier) NotifyTearDownDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+}
@@ -297,3 +297,73 @@ func NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Reposit
 		notifier.NotifyRepoPendingTransfer(doer, newOwner, repo)
 	}
 }
+
+// NotifyRepoAccessRequest notifies a request to access a repository to notifiers
+func NotifyRepoAccessRequest(doer *models.User, req *models.RepoAccessRequest) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoAccessRequest(doer, req)
+	}
+}
+
+// NotifyRepoAccessRequestReviewed notifies the approval or the rejection of a request to access a repository to notifiers
+func NotifyRepoAccessRequestReviewed(doer *models.User, req *models.RepoAccessRequest) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoAccessRequestReviewed(doer, req)
+	}
+}
+
+// NotifyUpdateBranchProtection notifies the creation or update of a branch protection to notifiers
+func NotifyUpdateBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch, isNew bool) {
+	for _, notifier := range notifiers {
+		notifier.NotifyUpdateBranchProtection(doer, repo, protectBranch, isNew)
+	}
+}
+
+// NotifyDeleteBranchProtection notifies the deletion of a branch protection to notifiers
+func NotifyDeleteBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteBranchProtection(doer, repo, protectBranch)
+	}
+}
+
+// NotifyAddCollaborator notifies a new collaborator of a repository to notifiers
+func NotifyAddCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User, mode models.AccessMode) {
+	for _, notifier := range notifiers {
+		notifier.NotifyAddCollaborator(doer, repo, collaborator, mode)
+	}
+}
+
+// NotifyChangeCollaboratorAccessMode notifies the access mode change of a collaborator to notifiers
+func NotifyChangeCollaboratorAccessMode(doer *models.User, repo *models.Repository, collaborator *models.User, oldMode, mode models.AccessMode) {
+	for _, notifier := range notifiers {
+		notifier.NotifyChangeCollaboratorAccessMode(doer, repo, collaborator, oldMode, mode)
+	}
+}
+
+// NotifyRemoveCollaborator notifies the removal of a collaborator to notifiers
+func NotifyRemoveCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRemoveCollaborator(doer, repo, collaborator)
+	}
+}
+
+// NotifyChangeRepositoryVisibility notifies a repository being made private or public to notifiers
+func NotifyChangeRepositoryVisibility(doer *models.User, repo *models.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyChangeRepositoryVisibility(doer, repo)
+	}
+}
+
+// NotifyCreateDeployment notifies the deployment of an environment for a pull request to notifiers
+func NotifyCreateDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateDeployment(doer, pr, deployment)
+	}
+}
+
+// NotifyTearDownDeployment notifies an environment of a pull request to tear down to notifiers
+func NotifyTearDownDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyTearDownDeployment(doer, pr, deployment)
+	}
+}
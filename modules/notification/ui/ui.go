@@ -237,3 +237,18 @@ func (ns *notificationService) NotifyRepoPendingTransfer(doer, newOwner *models.
 		log.Error("NotifyRepoPendingTransfer: %v", err)
 	}
 }
+
+func (ns *notificationService) NotifyRepoAccessRequest(doer *models.User, req *models.RepoAccessRequest) {
+	if err := models.CreateRepoAccessRequestNotification(doer, req.Repo); err != nil {
+		log.Error("NotifyRepoAccessRequest: %v", err)
+	}
+}
+
+func (ns *notificationService) NotifyRepoAccessRequestReviewed(doer *models.User, req *models.RepoAccessRequest) {
+	if req.Status != models.RepoAccessRequestApproved {
+		return
+	}
+	if err := models.CreateRepoAccessGrantedNotification(doer, req.User, req.Repo); err != nil {
+		log.Error("NotifyRepoAccessRequestReviewed: %v", err)
+	}
+}
@@ -5,6 +5,8 @@
 package webhook
 
 import (
+	"strconv"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
@@ -819,3 +821,103 @@ func (m *webhookNotifier) NotifySyncCreateRef(pusher *models.User, repo *models.
 func (m *webhookNotifier) NotifySyncDeleteRef(pusher *models.User, repo *models.Repository, refType, refFullName string) {
 	m.NotifyDeleteRef(pusher, repo, refType, refFullName)
 }
+
+func sendRepositorySettingsHook(doer *models.User, repo *models.Repository, payload *api.RepositorySettingsPayload) {
+	payload.Repository = convert.ToRepo(repo, models.AccessModeOwner)
+	payload.Sender = convert.ToUser(doer, nil)
+	if err := webhook_services.PrepareWebhooks(repo, models.HookEventRepositorySettings, payload); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyUpdateBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch, isNew bool) {
+	action := api.HookRepoSettingsBranchProtectionUpdated
+	if isNew {
+		action = api.HookRepoSettingsBranchProtectionCreated
+	}
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action:           action,
+		BranchProtection: convert.ToBranchProtection(protectBranch),
+	})
+}
+
+func (m *webhookNotifier) NotifyDeleteBranchProtection(doer *models.User, repo *models.Repository, protectBranch *models.ProtectedBranch) {
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action:           api.HookRepoSettingsBranchProtectionDeleted,
+		BranchProtection: convert.ToBranchProtection(protectBranch),
+	})
+}
+
+func (m *webhookNotifier) NotifyAddCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User, mode models.AccessMode) {
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action:       api.HookRepoSettingsCollaboratorAdded,
+		Collaborator: convert.ToUser(collaborator, nil),
+		Permission:   mode.String(),
+	})
+}
+
+func (m *webhookNotifier) NotifyChangeCollaboratorAccessMode(doer *models.User, repo *models.Repository, collaborator *models.User, oldMode, mode models.AccessMode) {
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action:       api.HookRepoSettingsCollaboratorUpdated,
+		Collaborator: convert.ToUser(collaborator, nil),
+		Permission:   mode.String(),
+		Changes: &api.ChangesPayload{
+			Permission: &api.ChangesFromPayload{
+				From: oldMode.String(),
+			},
+		},
+	})
+}
+
+func (m *webhookNotifier) NotifyRemoveCollaborator(doer *models.User, repo *models.Repository, collaborator *models.User) {
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action:       api.HookRepoSettingsCollaboratorRemoved,
+		Collaborator: convert.ToUser(collaborator, nil),
+	})
+}
+
+func (m *webhookNotifier) NotifyChangeRepositoryVisibility(doer *models.User, repo *models.Repository) {
+	sendRepositorySettingsHook(doer, repo, &api.RepositorySettingsPayload{
+		Action: api.HookRepoSettingsVisibilityChanged,
+		Changes: &api.ChangesPayload{
+			Private: &api.ChangesFromPayload{
+				From: strconv.FormatBool(!repo.IsPrivate),
+			},
+		},
+	})
+}
+
+func sendDeploymentHook(doer *models.User, pr *models.PullRequest, deployment *models.Deployment, action api.HookDeploymentAction) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		log.Error("pr.Issue.LoadRepo: %v", err)
+		return
+	}
+
+	mode, err := models.AccessLevel(doer, pr.Issue.Repo)
+	if err != nil {
+		log.Error("models.AccessLevel: %v", err)
+		return
+	}
+
+	if err := webhook_services.PrepareWebhooks(pr.Issue.Repo, models.HookEventDeployment, &api.DeploymentPayload{
+		Action:      action,
+		Deployment:  convert.ToDeployment(deployment, pr),
+		PullRequest: convert.ToAPIPullRequest(pr),
+		Repository:  convert.ToRepo(pr.Issue.Repo, mode),
+		Sender:      convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", pr.Issue.RepoID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyCreateDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+	sendDeploymentHook(doer, pr, deployment, api.HookDeploymentCreated)
+}
+
+func (m *webhookNotifier) NotifyTearDownDeployment(doer *models.User, pr *models.PullRequest, deployment *models.Deployment) {
+	sendDeploymentHook(doer, pr, deployment, api.HookDeploymentTornDown)
+}
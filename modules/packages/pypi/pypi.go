@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package pypi implements the naming rules and metadata of Python packages
+// hosted in the PyPI compatible package registry.
+package pypi
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// https://www.python.org/dev/peps/pep-0508/#names
+	nameMatcher = regexp.MustCompile(`\A(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9._-]*[a-zA-Z0-9])\z`)
+	// https://www.python.org/dev/peps/pep-0503/#normalized-names
+	nameNormalizer = regexp.MustCompile(`[-_.]+`)
+	// https://www.python.org/dev/peps/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+	versionMatcher = regexp.MustCompile(`(?i)\A(?:[1-9]\d*!)?(?:0|[1-9]\d*)(?:\.(?:0|[1-9]\d*))*(?:(?:a|b|rc)(?:0|[1-9]\d*))?(?:\.post(?:0|[1-9]\d*))?(?:\.dev(?:0|[1-9]\d*))?(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?\z`)
+)
+
+// FileExtensions are the extensions of the distributions which can be uploaded
+var FileExtensions = []string{".whl", ".tar.gz", ".zip", ".egg"}
+
+// Metadata is the metadata of a package version, it is provided by the first uploaded file of the version
+type Metadata struct {
+	Summary        string `json:"summary,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Author         string `json:"author,omitempty"`
+	AuthorEmail    string `json:"author_email,omitempty"`
+	License        string `json:"license,omitempty"`
+	HomePage       string `json:"home_page,omitempty"`
+	Keywords       string `json:"keywords,omitempty"`
+	RequiresPython string `json:"requires_python,omitempty"`
+}
+
+// IsValidName returns true if name is a valid distribution name
+func IsValidName(name string) bool {
+	return nameMatcher.MatchString(name)
+}
+
+// NormalizeName returns the normalized form of a distribution name used to look up packages,
+// e.g. "Foo.Bar_baz" becomes "foo-bar-baz"
+func NormalizeName(name string) string {
+	return strings.ToLower(nameNormalizer.ReplaceAllString(name, "-"))
+}
+
+// IsValidVersion returns true if version is a valid public version identifier
+func IsValidVersion(version string) bool {
+	return versionMatcher.MatchString(version)
+}
+
+// IsValidFilename returns true if filename is the name of a supported distribution
+func IsValidFilename(filename string) bool {
+	if filename == "" || strings.ContainsAny(filename, `/\`) || strings.HasPrefix(filename, ".") {
+		return false
+	}
+	lower := strings.ToLower(filename)
+	for _, ext := range FileExtensions {
+		if strings.HasSuffix(lower, ext) && len(lower) > len(ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "foo-bar-baz", NormalizeName("Foo.Bar_baz"))
+	assert.Equal(t, "foo-bar", NormalizeName("foo__-.bar"))
+	assert.Equal(t, "foo", NormalizeName("FOO"))
+}
+
+func TestIsValidName(t *testing.T) {
+	for _, name := range []string{"a", "foo", "Foo.Bar_baz-1", "1foo"} {
+		assert.True(t, IsValidName(name), name)
+	}
+	for _, name := range []string{"", "-foo", "foo.", "foo bar", "foo/bar"} {
+		assert.False(t, IsValidName(name), name)
+	}
+}
+
+func TestIsValidVersion(t *testing.T) {
+	for _, version := range []string{"1", "1.0", "1.0.0", "1!2.0", "1.0a1", "1.0rc2", "1.0.post1", "1.0.dev3", "1.0+local.1", "1.0RC1"} {
+		assert.True(t, IsValidVersion(version), version)
+	}
+	for _, version := range []string{"", "v1.0", "1.0-beta", "01.0", "1..0", "1.0+"} {
+		assert.False(t, IsValidVersion(version), version)
+	}
+}
+
+func TestIsValidFilename(t *testing.T) {
+	for _, filename := range []string{"foo-1.0.tar.gz", "foo-1.0-py3-none-any.whl", "foo-1.0.zip", "foo-1.0-py2.7.egg", "FOO-1.0.TAR.GZ"} {
+		assert.True(t, IsValidFilename(filename), filename)
+	}
+	for _, filename := range []string{"", ".whl", "foo-1.0.exe", "../foo-1.0.whl", `foo\1.0.whl`, ".hidden.whl"} {
+		assert.False(t, IsValidFilename(filename), filename)
+	}
+}
@@ -32,6 +32,10 @@ type GitPushOptions map[string]string
 const (
 	GitPushOptionRepoPrivate  = "repo.private"
 	GitPushOptionRepoTemplate = "repo.template"
+
+	GitPushOptionTopic       = "topic"
+	GitPushOptionTitle       = "title"
+	GitPushOptionDescription = "description"
 )
 
 // Bool checks for a key in the map and parses as a boolean
@@ -57,6 +61,7 @@ type HookOptions struct {
 	GitPushOptions                  GitPushOptions
 	PullRequestID                   int64
 	IsDeployKey                     bool
+	GitOperationID                  int64
 }
 
 // SSHLogOption ssh log options
@@ -76,6 +81,7 @@ type HookPostReceiveResult struct {
 type HookPostReceiveBranchResult struct {
 	Message bool
 	Create  bool
+	Created bool
 	Branch  string
 	URL     string
 }
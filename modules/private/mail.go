@@ -58,3 +58,58 @@ func SendEmail(ctx context.Context, subject, message string, to []string) (int,
 
 	return http.StatusOK, fmt.Sprintf("Sent %s email(s) to %s users", body, users)
 }
+
+// ReceiveEmail calls the internal ReceiveEmail function with a raw email
+func ReceiveEmail(ctx context.Context, email []byte) (int, string) {
+	reqURL := setting.LocalURL + "api/internal/mail/receive"
+
+	req := newInternalRequest(ctx, reqURL, "POST")
+	req = req.Header("Content-Type", "message/rfc822")
+	req.Body(email)
+	resp, err := req.Response()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, decodeJSONError(resp).Err
+	}
+
+	return http.StatusOK, "Email received"
+}
+
+// RegisterNotifyEmails structure holds the users to notify of the creation of their accounts
+type RegisterNotifyEmails struct {
+	Usernames []string
+}
+
+// SendRegisterNotifyEmails calls the internal SendRegisterNotifyEmails function
+//
+// It sends the email notifying of the creation of their accounts by an admin to the given users.
+func SendRegisterNotifyEmails(ctx context.Context, usernames []string) (int, string) {
+	reqURL := setting.LocalURL + "api/internal/mail/register-notify"
+
+	req := newInternalRequest(ctx, reqURL, "POST")
+	req = req.Header("Content-Type", "application/json")
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonBytes, _ := json.Marshal(RegisterNotifyEmails{
+		Usernames: usernames,
+	})
+	req.Body(jsonBytes)
+	resp, err := req.Response()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, decodeJSONError(resp).Err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Sprintf("Response body error: %v", err.Error())
+	}
+	return http.StatusOK, fmt.Sprintf("Sent %s invitation email(s)", body)
+}
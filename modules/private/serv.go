@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
@@ -54,6 +55,8 @@ type ServCommandResults struct {
 	OwnerName   string
 	RepoName    string
 	RepoID      int64
+	// GitOperationID is the id of the GitOperation recording a git command
+	GitOperationID int64
 }
 
 // ErrServCommand is an error returned from ServCommmand.
@@ -73,13 +76,14 @@ func IsErrServCommand(err error) bool {
 	return ok
 }
 
-// ServCommand preps for a serv call
-func ServCommand(ctx context.Context, keyID int64, ownerName, repoName string, mode models.AccessMode, verbs ...string) (*ServCommandResults, error) {
-	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/serv/command/%d/%s/%s?mode=%d",
+// ServCommand preps for a serv call, the git commands are recorded as GitOperations from remoteIP
+func ServCommand(ctx context.Context, keyID int64, ownerName, repoName string, mode models.AccessMode, remoteIP string, verbs ...string) (*ServCommandResults, error) {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/serv/command/%d/%s/%s?mode=%d&remote_ip=%s",
 		keyID,
 		url.PathEscape(ownerName),
 		url.PathEscape(repoName),
-		mode)
+		mode,
+		url.QueryEscape(remoteIP))
 	for _, verb := range verbs {
 		if verb != "" {
 			reqURL += fmt.Sprintf("&verb=%s", url.QueryEscape(verb))
@@ -107,3 +111,31 @@ func ServCommand(ctx context.Context, keyID int64, ownerName, repoName string, m
 	return &results, nil
 
 }
+
+// GitOperationFinishOptions represents the end of a git operation
+type GitOperationFinishOptions struct {
+	BytesReceived int64
+	BytesSent     int64
+	Duration      time.Duration
+	Succeeded     bool
+}
+
+// FinishGitOperation records the end of a git operation started by ServCommand
+func FinishGitOperation(ctx context.Context, id int64, opts GitOperationFinishOptions) error {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/serv/operation/%d/finish", id)
+	req := newInternalRequest(ctx, reqURL, "POST")
+	req = req.Header("Content-Type", "application/json")
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonBytes, _ := json.Marshal(opts)
+	req.Body(jsonBytes)
+
+	resp, err := req.Response()
+	if err != nil {
+		return fmt.Errorf("unable to contact gitea: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error returned from gitea: %v", decodeJSONError(resp).Err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/gobwas/glob"
+)
+
+var (
+	once         sync.Once
+	hostMatchers []glob.Glob
+)
+
+func compileHostMatchers() {
+	once.Do(func() {
+		for _, h := range setting.Proxy.ProxyHosts {
+			if g, err := glob.Compile(h); err == nil {
+				hostMatchers = append(hostMatchers, g)
+			} else {
+				log.Error("glob.Compile %s failed: %v", h, err)
+			}
+		}
+	})
+}
+
+// GetProxyURL returns the configured proxy URL, it is empty if the proxy is disabled
+func GetProxyURL() string {
+	if !setting.Proxy.Enabled {
+		return ""
+	}
+	return setting.Proxy.ProxyURL
+}
+
+// Match returns true if the connections to host should use the configured proxy
+func Match(host string) bool {
+	if GetProxyURL() == "" {
+		return false
+	}
+	// an empty host list sends all hosts through the proxy
+	if len(setting.Proxy.ProxyHosts) == 0 {
+		return true
+	}
+	compileHostMatchers()
+	for _, v := range hostMatchers {
+		if v.Match(host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Proxy returns the proxy function for http.Transport.
+// Without a configured proxy the proxy environment variables are followed.
+func Proxy() func(req *http.Request) (*url.URL, error) {
+	if GetProxyURL() == "" {
+		return http.ProxyFromEnvironment
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if Match(req.URL.Host) {
+			return http.ProxyURL(setting.Proxy.ProxyURLFixed)(req)
+		}
+		return http.ProxyFromEnvironment(req)
+	}
+}
+
+// EnvWithProxy returns the environment for git commands connecting to u, with the proxy variables set if needed
+func EnvWithProxy(u *url.URL) []string {
+	envs := os.Environ()
+	if u == nil || !(strings.EqualFold(u.Scheme, "http") || strings.EqualFold(u.Scheme, "https")) || !Match(u.Host) {
+		return envs
+	}
+	proxyURL := GetProxyURL()
+	return append(envs, "http_proxy="+proxyURL, "https_proxy="+proxyURL)
+}
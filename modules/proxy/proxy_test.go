@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) {
+	oldProxy := setting.Proxy
+	defer func() {
+		setting.Proxy = oldProxy
+	}()
+
+	setting.Proxy.Enabled = true
+	setting.Proxy.ProxyURL = "http://localhost:8080"
+	setting.Proxy.ProxyURLFixed, _ = url.Parse(setting.Proxy.ProxyURL)
+	setting.Proxy.ProxyHosts = []string{"*.github.com", "github.com"}
+
+	var kases = map[string]string{
+		"https://github.com/a/b":         "http://localhost:8080",
+		"https://api.github.com/repos/a": "http://localhost:8080",
+		"http://gitlab.com/a/b":          "",
+	}
+	for reqURL, proxyURL := range kases {
+		req, err := http.NewRequest("GET", reqURL, nil)
+		assert.NoError(t, err)
+
+		u, err := Proxy()(req)
+		assert.NoError(t, err)
+		if proxyURL == "" {
+			assert.Nil(t, u)
+		} else {
+			assert.EqualValues(t, proxyURL, u.String())
+		}
+	}
+
+	u, _ := url.Parse("https://github.com/a/b.git")
+	assert.Contains(t, EnvWithProxy(u), "https_proxy=http://localhost:8080")
+	u, _ = url.Parse("git://github.com/a/b.git")
+	assert.NotContains(t, EnvWithProxy(u), "https_proxy=http://localhost:8080")
+
+	setting.Proxy.Enabled = false
+	assert.False(t, Match("github.com"))
+}
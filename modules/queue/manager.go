@@ -53,6 +53,12 @@ type Flushable interface {
 	IsEmpty() bool
 }
 
+// Countable represents a pool or queue that can report the number of items waiting in it
+type Countable interface {
+	// NumberInQueue returns the number of items waiting in the queue
+	NumberInQueue() int64
+}
+
 // ManagedPool is a simple interface to get certain details from a worker pool
 type ManagedPool interface {
 	// AddWorkers adds a number of worker as group to the pool with the provided timeout. A CancelFunc is provided to cancel the group
@@ -307,6 +313,14 @@ func (q *ManagedQueue) IsEmpty() bool {
 	return true
 }
 
+// NumberInQueue returns the number of items waiting in the queue or -1 if the queue cannot count them
+func (q *ManagedQueue) NumberInQueue() int64 {
+	if countable, ok := q.Managed.(Countable); ok {
+		return countable.NumberInQueue()
+	}
+	return -1
+}
+
 // NumberOfWorkers returns the number of workers in the queue
 func (q *ManagedQueue) NumberOfWorkers() int {
 	if pool, ok := q.Managed.(ManagedPool); ok {
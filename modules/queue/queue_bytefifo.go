@@ -109,6 +109,11 @@ func (q *ByteFIFOQueue) IsEmpty() bool {
 	return q.byteFIFO.Len(q.terminateCtx) == 0
 }
 
+// NumberInQueue returns the number of items waiting in the queue and its fifo
+func (q *ByteFIFOQueue) NumberInQueue() int64 {
+	return q.WorkerPool.NumberInQueue() + q.byteFIFO.Len(q.terminateCtx)
+}
+
 // Run runs the bytefifo queue
 func (q *ByteFIFOQueue) Run(atShutdown, atTerminate func(func())) {
 	atShutdown(q.Shutdown)
@@ -95,3 +95,26 @@ func TestChannelQueue_Batch(t *testing.T) {
 	err = queue.Push(test1)
 	assert.Error(t, err)
 }
+
+func TestChannelQueue_NumberInQueue(t *testing.T) {
+	handle := func(data ...Data) {}
+
+	queue, err := NewChannelQueue(handle,
+		ChannelQueueConfiguration{
+			WorkerPoolConfiguration: WorkerPoolConfiguration{
+				QueueLength: 10,
+				MaxWorkers:  10,
+			},
+			Workers: 0,
+			Name:    "TestChannelQueue_NumberInQueue",
+		}, &testData{})
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 0, queue.(*ChannelQueue).NumberInQueue())
+	assert.NoError(t, queue.Push(&testData{"A", 1}))
+	assert.NoError(t, queue.Push(&testData{"B", 2}))
+	assert.EqualValues(t, 2, queue.(*ChannelQueue).NumberInQueue())
+
+	mq := GetManager().GetManagedQueue(queue.(*ChannelQueue).qid)
+	assert.EqualValues(t, 2, mq.NumberInQueue())
+}
@@ -227,6 +227,11 @@ func (q *PersistableChannelQueue) IsEmpty() bool {
 	return q.internal.IsEmpty()
 }
 
+// NumberInQueue returns the number of items waiting in the channel queue
+func (q *PersistableChannelQueue) NumberInQueue() int64 {
+	return q.channelQueue.NumberInQueue()
+}
+
 // Shutdown processing this queue
 func (q *PersistableChannelQueue) Shutdown() {
 	log.Trace("PersistableChannelQueue: %s Shutting down", q.delayedStarter.name)
@@ -218,6 +218,17 @@ func (q *WrappedQueue) IsEmpty() bool {
 	return q.internal.IsEmpty()
 }
 
+// NumberInQueue returns the number of items waiting to be passed to the internal queue and waiting in it
+func (q *WrappedQueue) NumberInQueue() int64 {
+	number := atomic.LoadInt64(&q.numInQueue)
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if countable, ok := q.internal.(Countable); ok {
+		number += countable.NumberInQueue()
+	}
+	return number
+}
+
 // Run starts to run the queue and attempts to create the internal queue
 func (q *WrappedQueue) Run(atShutdown, atTerminate func(func())) {
 	log.Debug("WrappedQueue: %s Starting", q.name)
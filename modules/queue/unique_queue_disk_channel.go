@@ -215,6 +215,11 @@ func (q *PersistableChannelUniqueQueue) IsEmpty() bool {
 	return q.channelQueue.IsEmpty()
 }
 
+// NumberInQueue returns the number of items waiting in the channel queue
+func (q *PersistableChannelUniqueQueue) NumberInQueue() int64 {
+	return q.channelQueue.NumberInQueue()
+}
+
 // Shutdown processing this queue
 func (q *PersistableChannelUniqueQueue) Shutdown() {
 	log.Trace("PersistableChannelUniqueQueue: %s Shutting down", q.delayedStarter.name)
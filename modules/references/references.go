@@ -358,6 +358,29 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 	}
 }
 
+// FindRenderizableReferenceRegexp returns the first unvalidated reference matching the pattern of an external tracker found in a string.
+// The first group of the pattern is the issue index, the whole match if it has none.
+func FindRenderizableReferenceRegexp(content string, pattern *regexp.Regexp) (bool, *RenderizableReference) {
+	match := pattern.FindStringSubmatchIndex(content)
+	if match == nil || match[0] == match[1] {
+		return false, nil
+	}
+
+	issue := content[match[0]:match[1]]
+	if len(match) >= 4 && match[2] >= 0 {
+		issue = content[match[2]:match[3]]
+	}
+	action, location := findActionKeywords([]byte(content), match[0])
+
+	return true, &RenderizableReference{
+		Issue:          issue,
+		RefLocation:    &RefSpan{Start: match[0], End: match[1]},
+		Action:         action,
+		ActionLocation: location,
+		IsPull:         false,
+	}
+}
+
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
 func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
 
@@ -264,6 +264,57 @@ func TestFindAllIssueReferences(t *testing.T) {
 	}
 }
 
+func TestFindRenderizableReferenceRegexp(t *testing.T) {
+	type regexpFixture struct {
+		input          string
+		pattern        string
+		issue          string
+		refLocation    *RefSpan
+		action         XRefAction
+		actionLocation *RefSpan
+	}
+
+	fixtures := []regexpFixture{
+		{
+			"This ref JIRA-123 matches",
+			`JIRA-\d+`,
+			"JIRA-123", &RefSpan{Start: 9, End: 17},
+			XRefActionNone, nil,
+		},
+		{
+			"This ref JIRA-123 has a group",
+			`JIRA-(\d+)`,
+			"123", &RefSpan{Start: 9, End: 17},
+			XRefActionNone, nil,
+		},
+		{
+			"This closes JIRA-123",
+			`JIRA-\d+`,
+			"JIRA-123", &RefSpan{Start: 12, End: 20},
+			XRefActionCloses, &RefSpan{Start: 5, End: 11},
+		},
+		{
+			"This ref ABC-123 does not match",
+			`JIRA-\d+`,
+			"", nil,
+			XRefActionNone, nil,
+		},
+	}
+
+	for _, fixture := range fixtures {
+		found, ref := FindRenderizableReferenceRegexp(fixture.input, regexp.MustCompile(fixture.pattern))
+		if fixture.issue == "" {
+			assert.False(t, found, "Failed to parse: {%s}", fixture.input)
+		} else {
+			assert.True(t, found, "Failed to parse: {%s}", fixture.input)
+			assert.Equal(t, fixture.issue, ref.Issue, "Failed to parse: {%s}", fixture.input)
+			assert.Equal(t, fixture.refLocation, ref.RefLocation, "Failed to parse: {%s}", fixture.input)
+			assert.Equal(t, fixture.action, ref.Action, "Failed to parse: {%s}", fixture.input)
+			assert.Equal(t, fixture.actionLocation, ref.ActionLocation, "Failed to parse: {%s}", fixture.input)
+		}
+	}
+}
+
 func testFixtures(t *testing.T, fixtures []testFixture, context string) {
 	// Save original value for other tests that may rely on it
 	prevURL := setting.AppURL
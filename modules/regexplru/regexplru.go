@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package regexplru
+
+import (
+	"regexp"
+
+	"code.gitea.io/gitea/modules/log"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var lruCache *lru.Cache
+
+func init() {
+	var err error
+	lruCache, err = lru.New(1000)
+	if err != nil {
+		log.Fatal("failed to new LRU cache, err: %v", err)
+	}
+}
+
+// GetCompiled works like regexp.Compile, the compiled expression or the compilation error is kept in a LRU cache
+func GetCompiled(expr string) (*regexp.Regexp, error) {
+	if v, ok := lruCache.Get(expr); ok {
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		return v.(*regexp.Regexp), nil
+	}
+
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		lruCache.Add(expr, err)
+		return nil, err
+	}
+	lruCache.Add(expr, r)
+	return r, nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package regexplru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompiled(t *testing.T) {
+	assert.Equal(t, 0, lruCache.Len())
+
+	r, err := GetCompiled("a")
+	assert.NoError(t, err)
+	assert.True(t, r.MatchString("a"))
+	assert.Equal(t, 1, lruCache.Len())
+
+	r2, err := GetCompiled("a")
+	assert.NoError(t, err)
+	assert.Same(t, r, r2)
+	assert.Equal(t, 1, lruCache.Len())
+
+	_, err = GetCompiled("(")
+	assert.Error(t, err)
+	_, err = GetCompiled("(")
+	assert.Error(t, err)
+	assert.Equal(t, 2, lruCache.Len())
+}
@@ -5,6 +5,7 @@
 package repofiles
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path"
@@ -29,6 +30,8 @@ type UploadRepoFileOptions struct {
 
 type uploadInfo struct {
 	upload        *models.Upload
+	treePath      string
+	size          int64
 	lfsMetaObject *models.LFSMetaObject
 }
 
@@ -59,6 +62,7 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 
 	names := make([]string, len(uploads))
 	infos := make([]uploadInfo, len(uploads))
+	var totalSize int64
 	for i, upload := range uploads {
 		// Check file is not lfs locked, will return nil if lock setting not enabled
 		filepath := path.Join(opts.TreePath, upload.Name)
@@ -70,8 +74,17 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 			return models.ErrLFSFileLocked{RepoID: repo.ID, Path: filepath, UserName: lfsLock.Owner.Name}
 		}
 
-		names[i] = upload.Name
-		infos[i] = uploadInfo{upload: upload}
+		fi, err := os.Stat(upload.LocalPath())
+		if err != nil {
+			return err
+		}
+		totalSize += fi.Size()
+
+		names[i] = filepath
+		infos[i] = uploadInfo{upload: upload, treePath: filepath, size: fi.Size()}
+	}
+	if maxSize := setting.Repository.Upload.CommitMaxSize * 1024 * 1024; maxSize > 0 && totalSize > maxSize {
+		return models.ErrUploadCommitTooLarge{Size: totalSize, MaxSize: maxSize}
 	}
 
 	t, err := NewTemporaryUploadRepository(repo)
@@ -98,8 +111,18 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 	}
 
 	// Copy uploaded files into repository.
+	var lfsRoutedPaths []string
 	for i := range infos {
-		if err := copyUploadedLFSFileIntoRepository(&infos[i], filename2attribute2info, t, opts.TreePath); err != nil {
+		routed, err := copyUploadedLFSFileIntoRepository(&infos[i], filename2attribute2info, t)
+		if err != nil {
+			return err
+		}
+		if routed {
+			lfsRoutedPaths = append(lfsRoutedPaths, infos[i].treePath)
+		}
+	}
+	if len(lfsRoutedPaths) > 0 {
+		if err := addLFSAttributes(t, lfsRoutedPaths); err != nil {
 			return err
 		}
 	}
@@ -151,33 +174,89 @@ func UploadRepoFiles(repo *models.Repository, doer *models.User, opts *UploadRep
 	return models.DeleteUploads(uploads...)
 }
 
-func copyUploadedLFSFileIntoRepository(info *uploadInfo, filename2attribute2info map[string]map[string]string, t *TemporaryUploadRepository, treePath string) error {
+// copyUploadedLFSFileIntoRepository adds the uploaded file to the index, storing it in LFS when it is tracked by LFS
+// or large enough to be routed to LFS, in which case it returns true so that it can be tracked
+func copyUploadedLFSFileIntoRepository(info *uploadInfo, filename2attribute2info map[string]map[string]string, t *TemporaryUploadRepository) (bool, error) {
 	file, err := os.Open(info.upload.LocalPath())
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer file.Close()
 
+	tracked := filename2attribute2info[info.treePath] != nil && filename2attribute2info[info.treePath]["filter"] == "lfs"
+	minSize := setting.Repository.Upload.LFSMinSize * 1024 * 1024
+	routed := !tracked && minSize > 0 && info.size >= minSize
+
 	var objectHash string
-	if setting.LFS.StartServer && filename2attribute2info[info.upload.Name] != nil && filename2attribute2info[info.upload.Name]["filter"] == "lfs" {
+	if setting.LFS.StartServer && (tracked || routed) {
 		// Handle LFS
 		// FIXME: Inefficient! this should probably happen in models.Upload
 		pointer, err := lfs.GeneratePointer(file)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		info.lfsMetaObject = &models.LFSMetaObject{Pointer: pointer, RepositoryID: t.repo.ID}
 
 		if objectHash, err = t.HashObject(strings.NewReader(pointer.StringContent())); err != nil {
-			return err
+			return false, err
 		}
 	} else if objectHash, err = t.HashObject(file); err != nil {
-		return err
+		return false, err
 	}
 
 	// Add the object to the index
-	return t.AddObjectToIndex("100644", objectHash, path.Join(treePath, info.upload.Name))
+	return info.lfsMetaObject != nil && routed, t.AddObjectToIndex("100644", objectHash, info.treePath)
+}
+
+// addLFSAttributes tracks the given paths by LFS in the .gitattributes file at the root of the index
+func addLFSAttributes(t *TemporaryUploadRepository, treePaths []string) error {
+	content := new(bytes.Buffer)
+	files, err := t.LsFiles(".gitattributes")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file != ".gitattributes" {
+			continue
+		}
+		stdout, err := git.NewCommand("cat-file", "blob", ":.gitattributes").RunInDirBytes(t.basePath)
+		if err != nil {
+			return fmt.Errorf("unable to read .gitattributes of the index: %v", err)
+		}
+		content.Write(stdout)
+		if len(stdout) > 0 && stdout[len(stdout)-1] != '\n' {
+			content.WriteByte('\n')
+		}
+		break
+	}
+
+	for _, treePath := range treePaths {
+		content.WriteString("/" + escapeAttributesPattern(treePath) + " filter=lfs diff=lfs merge=lfs -text\n")
+	}
+
+	objectHash, err := t.HashObject(content)
+	if err != nil {
+		return err
+	}
+	return t.AddObjectToIndex("100644", objectHash, ".gitattributes")
+}
+
+// escapeAttributesPattern escapes a path so that it only matches itself as a .gitattributes pattern
+func escapeAttributesPattern(treePath string) string {
+	var sb strings.Builder
+	for _, r := range treePath {
+		switch r {
+		case '*', '?', '[', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case ' ':
+			sb.WriteString("[[:space:]]")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
 }
 
 func uploadToLFSContentStore(info uploadInfo, contentStore *lfs.ContentStore) error {
@@ -0,0 +1,17 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeAttributesPattern(t *testing.T) {
+	assert.EqualValues(t, "assets/video.mp4", escapeAttributesPattern("assets/video.mp4"))
+	assert.EqualValues(t, "my[[:space:]]file.bin", escapeAttributesPattern("my file.bin"))
+	assert.EqualValues(t, `a\*b\?c\[d\\e`, escapeAttributesPattern(`a*b?c[d\e`))
+}
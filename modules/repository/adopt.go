@@ -119,6 +119,73 @@ func DeleteUnadoptedRepository(doer, u *models.User, repoName string) error {
 	return util.RemoveAll(repoPath)
 }
 
+// getUnadoptedRepository returns the owner and the name of the unadopted repository dir, given as owner/name
+func getUnadoptedRepository(dir string) (*models.User, string, error) {
+	dirSplit := strings.SplitN(dir, "/", 2)
+	if len(dirSplit) != 2 {
+		return nil, "", models.ErrRepoNotExist{Name: dir}
+	}
+	u, err := models.GetUserByName(dirSplit[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	repoName := dirSplit[1]
+	if err := models.IsUsableRepoName(repoName); err != nil {
+		return nil, "", err
+	}
+	has, err := models.IsRepositoryExist(u, repoName)
+	if err != nil {
+		return nil, "", err
+	} else if has {
+		return nil, "", models.ErrRepoAlreadyExist{Uname: u.Name, Name: repoName}
+	}
+	isDir, err := util.IsDir(models.RepoPath(u.Name, repoName))
+	if err != nil {
+		return nil, "", err
+	} else if !isDir {
+		return nil, "", models.ErrRepoNotExist{OwnerName: u.Name, Name: repoName}
+	}
+	return u, repoName, nil
+}
+
+// AdoptUnadoptedRepositories adopts the unadopted repositories, given as owner/name, as private repositories
+// of their owners. It goes on when one of them fails and returns the errors by repository.
+func AdoptUnadoptedRepositories(doer *models.User, dirs []string) map[string]error {
+	errs := make(map[string]error)
+	for _, dir := range dirs {
+		u, repoName, err := getUnadoptedRepository(dir)
+		if err == nil {
+			_, err = AdoptRepository(doer, u, models.CreateRepoOptions{
+				Name:      repoName,
+				IsPrivate: true,
+			})
+		}
+		if err != nil {
+			log.Warn("Unable to adopt %s: %v", dir, err)
+			errs[dir] = err
+		}
+	}
+	return errs
+}
+
+// DeleteUnadoptedRepositories deletes the files of the unadopted repositories, given as owner/name.
+// It goes on when one of them fails and returns the errors by repository.
+func DeleteUnadoptedRepositories(doer *models.User, dirs []string) map[string]error {
+	errs := make(map[string]error)
+	for _, dir := range dirs {
+		u, repoName, err := getUnadoptedRepository(dir)
+		if err == nil {
+			err = DeleteUnadoptedRepository(doer, u, repoName)
+		}
+		if err != nil {
+			log.Warn("Unable to delete the unadopted repository %s: %v", dir, err)
+			errs[dir] = err
+		}
+	}
+	return errs
+}
+
 // ListUnadoptedRepositories lists all the unadopted repositories that match the provided query
 func ListUnadoptedRepositories(query string, opts *models.ListOptions) ([]string, int, error) {
 	globUser, _ := glob.Compile("*")
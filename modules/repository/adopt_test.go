@@ -0,0 +1,40 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdoptOrDeleteUnadoptedRepositories(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	for _, name := range []string{"unadopted1", "unadopted2"} {
+		assert.NoError(t, git.InitRepository(models.RepoPath("user2", name), true))
+	}
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+
+	errs := AdoptUnadoptedRepositories(doer, []string{"user2/unadopted1", "user2/repo1", "user2/missing", "nouser/repo", "invalid"})
+	assert.Len(t, errs, 4)
+	assert.True(t, models.IsErrRepoAlreadyExist(errs["user2/repo1"]))
+	assert.True(t, models.IsErrRepoNotExist(errs["user2/missing"]))
+	assert.True(t, models.IsErrUserNotExist(errs["nouser/repo"]))
+	assert.True(t, models.IsErrRepoNotExist(errs["invalid"]))
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 2, LowerName: "unadopted1"}).(*models.Repository)
+	assert.True(t, repo.IsPrivate)
+
+	errs = DeleteUnadoptedRepositories(doer, []string{"user2/unadopted1", "user2/unadopted2"})
+	assert.Len(t, errs, 1)
+	assert.True(t, models.IsErrRepoAlreadyExist(errs["user2/unadopted1"]))
+	isExist, err := util.IsExist(models.RepoPath("user2", "unadopted2"))
+	assert.NoError(t, err)
+	assert.False(t, isExist)
+}
@@ -42,7 +42,7 @@ func CacheRef(ctx context.Context, repo *models.Repository, gitRepo *git.Reposit
 		return nil
 	}
 
-	commitCache := git.NewLastCommitCache(repo.FullName(), gitRepo, setting.LastCommitCacheTTLSeconds, cache.GetCache())
+	commitCache := git.NewLastCommitCache(repo.FullName(), gitRepo, setting.LastCommitCacheTTLSeconds, cache.GetLastCommitCache())
 
 	return commitCache.CacheCommit(ctx, commit)
 }
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/tasklog"
 	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/builder"
@@ -21,6 +22,7 @@ import (
 // GitFsck calls 'git fsck' to check repository health.
 func GitFsck(ctx context.Context, timeout time.Duration, args []string) error {
 	log.Trace("Doing: GitFsck")
+	tl := tasklog.FromContext(ctx)
 
 	if err := models.Iterate(
 		models.DefaultDBContext(),
@@ -34,9 +36,11 @@ func GitFsck(ctx context.Context, timeout time.Duration, args []string) error {
 			default:
 			}
 			log.Trace("Running health check on repository %v", repo)
+			tl.Info("Checking %s", repo.FullName())
 			repoPath := repo.RepoPath()
 			if err := git.Fsck(ctx, repoPath, timeout, args...); err != nil {
 				log.Warn("Failed to health check repository (%v): %v", repo, err)
+				tl.Warn("Failed to health check %s: %v", repo.FullName(), err)
 				if err = models.CreateRepositoryNotice("Failed to health check repository (%s): %v", repo.FullName(), err); err != nil {
 					log.Error("CreateRepositoryNotice: %v", err)
 				}
@@ -56,6 +60,8 @@ func GitFsck(ctx context.Context, timeout time.Duration, args []string) error {
 func GitGcRepos(ctx context.Context, timeout time.Duration, args ...string) error {
 	log.Trace("Doing: GitGcRepos")
 	args = append([]string{"gc"}, args...)
+	tl := tasklog.FromContext(ctx)
+	total := int(models.CountRepositories(true))
 
 	if err := models.Iterate(
 		models.DefaultDBContext(),
@@ -69,6 +75,7 @@ func GitGcRepos(ctx context.Context, timeout time.Duration, args ...string) erro
 			default:
 			}
 			log.Trace("Running git gc on %v", repo)
+			tl.Progress(idx, total, "Running git gc on %s", repo.FullName())
 			command := git.NewCommandContext(ctx, args...).
 				SetDescription(fmt.Sprintf("Repository Garbage Collection: %s", repo.FullName()))
 			var stdout string
@@ -85,6 +92,7 @@ func GitGcRepos(ctx context.Context, timeout time.Duration, args ...string) erro
 
 			if err != nil {
 				log.Error("Repository garbage collection failed for %v. Stdout: %s\nError: %v", repo, stdout, err)
+				tl.Error("Repository garbage collection failed for %s: %v", repo.FullName(), err)
 				desc := fmt.Sprintf("Repository garbage collection failed for %s. Stdout: %s\nError: %v", repo.RepoPath(), stdout, err)
 				if err = models.CreateRepositoryNotice(desc); err != nil {
 					log.Error("CreateRepositoryNotice: %v", err)
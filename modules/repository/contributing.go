@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ContributingGuideCandidates are the paths where the contributing guide of a repository is looked for
+var ContributingGuideCandidates = []string{
+	"CONTRIBUTING.md",
+	"CONTRIBUTING",
+	".gitea/CONTRIBUTING.md",
+	".github/CONTRIBUTING.md",
+	"docs/CONTRIBUTING.md",
+}
+
+// FindContributingGuide returns the path of the contributing guide in the given commit, or an empty string if there is none
+func FindContributingGuide(commit *git.Commit) string {
+	for _, candidate := range ContributingGuideCandidates {
+		if entry, err := commit.GetTreeEntryByPath(candidate); err == nil && !entry.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// GetContributingGuideLink returns the link to the contributing guide of the default branch, or an empty string if there is none
+func GetContributingGuideLink(repo *models.Repository, gitRepo *git.Repository) (string, error) {
+	if repo.IsEmpty || !gitRepo.IsBranchExist(repo.DefaultBranch) {
+		return "", nil
+	}
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return "", err
+	}
+	guide := FindContributingGuide(commit)
+	if guide == "" {
+		return "", nil
+	}
+	return repo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(repo.DefaultBranch) + "/" + util.PathEscapeSegments(guide), nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetContributingGuideLink(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	assert.NoError(t, err)
+	assert.Empty(t, FindContributingGuide(commit))
+
+	link, err := GetContributingGuideLink(repo, gitRepo)
+	assert.NoError(t, err)
+	assert.Empty(t, link)
+}
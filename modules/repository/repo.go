@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	migration "code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -58,6 +59,11 @@ func MigrateRepositoryGitData(ctx context.Context, u *models.User, repo *models.
 		repo.NumWatches = 1
 	}
 
+	// there is no need to clone anything if the size quota of the owner has been reached already
+	if err := u.CheckSizeQuota(1); err != nil {
+		return repo, err
+	}
+
 	migrateTimeout := time.Duration(setting.Git.Timeout.Migrate) * time.Second
 
 	var err error
@@ -65,27 +71,34 @@ func MigrateRepositoryGitData(ctx context.Context, u *models.User, repo *models.
 		return repo, fmt.Errorf("Failed to remove %s: %v", repoPath, err)
 	}
 
+	cloneURL, _ := url.Parse(opts.CloneAddr)
 	if err = git.CloneWithContext(ctx, opts.CloneAddr, repoPath, git.CloneRepoOptions{
 		Mirror:  true,
 		Quiet:   true,
 		Timeout: migrateTimeout,
+		Env:     proxy.EnvWithProxy(cloneURL),
 	}); err != nil {
 		return repo, fmt.Errorf("Clone: %v", err)
 	}
 
 	if opts.Wiki {
 		wikiPath := models.WikiPath(u.Name, opts.RepoName)
-		wikiRemotePath := WikiRemoteURL(opts.CloneAddr)
+		wikiRemotePath := opts.WikiCloneAddr
+		if len(wikiRemotePath) == 0 {
+			wikiRemotePath = WikiRemoteURL(opts.CloneAddr)
+		}
 		if len(wikiRemotePath) > 0 {
 			if err := util.RemoveAll(wikiPath); err != nil {
 				return repo, fmt.Errorf("Failed to remove %s: %v", wikiPath, err)
 			}
 
+			wikiURL, _ := url.Parse(wikiRemotePath)
 			if err = git.CloneWithContext(ctx, wikiRemotePath, wikiPath, git.CloneRepoOptions{
 				Mirror:  true,
 				Quiet:   true,
 				Timeout: migrateTimeout,
 				Branch:  "master",
+				Env:     proxy.EnvWithProxy(wikiURL),
 			}); err != nil {
 				log.Warn("Clone wiki: %v", err)
 				if err := util.RemoveAll(wikiPath); err != nil {
@@ -134,6 +147,8 @@ func MigrateRepositoryGitData(ctx context.Context, u *models.User, repo *models.
 
 	if err = repo.UpdateSize(models.DefaultDBContext()); err != nil {
 		log.Error("Failed to update size for repository: %v", err)
+	} else if err = u.CheckSizeQuota(0); err != nil {
+		return repo, err
 	}
 
 	if opts.Mirror {
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// AntiSpam settings
+var AntiSpam = struct {
+	// ClassifierURL is the URL of the external service classifying the issues, pull requests and comments, disabled if empty
+	ClassifierURL string
+	// ClassifierToken is sent to the classifier as Authorization: Bearer header
+	ClassifierToken string
+	// Timeout of the requests to the classifier
+	Timeout time.Duration
+	// FailOpen publishes the content when the classifier can't be reached or fails, it is rejected otherwise
+	FailOpen bool
+}{
+	Timeout:  5 * time.Second,
+	FailOpen: true,
+}
+
+func newAntiSpamService() {
+	sec := Cfg.Section("antispam")
+	AntiSpam.ClassifierURL = sec.Key("CLASSIFIER_URL").MustString("")
+	AntiSpam.ClassifierToken = sec.Key("CLASSIFIER_TOKEN").MustString("")
+	AntiSpam.Timeout = sec.Key("TIMEOUT").MustDuration(AntiSpam.Timeout)
+	AntiSpam.FailOpen = sec.Key("FAIL_OPEN").MustBool(true)
+	if AntiSpam.ClassifierURL != "" {
+		log.Info("Content Classifier Enabled")
+	}
+}
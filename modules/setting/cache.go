@@ -5,6 +5,7 @@
 package setting
 
 import (
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -27,8 +28,11 @@ var (
 
 		LastCommit struct {
 			Enabled      bool
+			Adapter      string
+			Conn         string        `ini:"HOST"`
 			TTL          time.Duration `ini:"ITEM_TTL"`
 			CommitsCount int64
+			MaxEntries   int64
 		} `ini:"cache.last_commit"`
 	}{
 		Cache: Cache{
@@ -39,8 +43,11 @@ var (
 		},
 		LastCommit: struct {
 			Enabled      bool
+			Adapter      string
+			Conn         string        `ini:"HOST"`
 			TTL          time.Duration `ini:"ITEM_TTL"`
 			CommitsCount int64
+			MaxEntries   int64
 		}{
 			Enabled:      true,
 			TTL:          8760 * time.Hour,
@@ -83,8 +90,17 @@ func newCacheService() {
 	}
 
 	sec = Cfg.Section("cache.last_commit")
-	if !CacheService.Enabled {
-		CacheService.LastCommit.Enabled = false
+	// an empty adapter stores the last commits in the cache service
+	CacheService.LastCommit.Adapter = sec.Key("ADAPTER").In("", []string{"", "redis", "leveldb"})
+	switch CacheService.LastCommit.Adapter {
+	case "":
+		if !CacheService.Enabled {
+			CacheService.LastCommit.Enabled = false
+		}
+	case "redis":
+		CacheService.LastCommit.Conn = strings.Trim(sec.Key("HOST").String(), "\" ")
+	case "leveldb":
+		CacheService.LastCommit.Conn = strings.TrimSpace(sec.Key("HOST").MustString(filepath.Join(AppDataPath, "last_commit_cache")))
 	}
 
 	CacheService.LastCommit.CommitsCount = sec.Key("COMMITS_COUNT").MustInt64(1000)
@@ -104,7 +120,7 @@ func (c Cache) TTLSeconds() int64 {
 
 // LastCommitCacheTTLSeconds returns the TTLSeconds or unix timestamp for memcache
 func LastCommitCacheTTLSeconds() int64 {
-	if CacheService.Adapter == "memcache" && CacheService.LastCommit.TTL > MemcacheMaxTTL {
+	if CacheService.LastCommit.Adapter == "" && CacheService.Adapter == "memcache" && CacheService.LastCommit.TTL > MemcacheMaxTTL {
 		return time.Now().Add(CacheService.LastCommit.TTL).Unix()
 	}
 	return int64(CacheService.LastCommit.TTL.Seconds())
@@ -24,6 +24,7 @@ var (
 		VerbosePushDelay          time.Duration
 		GCArgs                    []string `ini:"GC_ARGS" delim:" "`
 		EnableAutoGitWireProtocol bool
+		DisablePartialClone       bool
 		PullRequestPushMessage    bool
 		LargeObjectThreshold      int64
 		Timeout                   struct {
@@ -45,6 +46,7 @@ var (
 		VerbosePushDelay:          5 * time.Second,
 		GCArgs:                    []string{},
 		EnableAutoGitWireProtocol: true,
+		DisablePartialClone:       false,
 		PullRequestPushMessage:    true,
 		LargeObjectThreshold:      1024 * 1024,
 		Timeout: struct {
@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/mail"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// IncomingEmail settings
+var (
+	IncomingEmail = struct {
+		Enabled            bool
+		Address            string
+		MaxEmailSize       int64
+		LMTPListenAddr     string   `ini:"LMTP_LISTEN_ADDR"`
+		TrustedAuthservIDs []string `ini:"-"`
+	}{
+		Enabled:      false,
+		MaxEmailSize: 10,
+	}
+)
+
+func newIncomingEmailService() {
+	sec := Cfg.Section("email.incoming")
+	if err := sec.MapTo(&IncomingEmail); err != nil {
+		log.Fatal("Failed to map IncomingEmail settings: %v", err)
+	}
+	IncomingEmail.TrustedAuthservIDs = sec.Key("TRUSTED_AUTHSERV_IDS").Strings(",")
+	if !IncomingEmail.Enabled {
+		return
+	}
+
+	address, err := mail.ParseAddress(IncomingEmail.Address)
+	if err != nil || strings.Contains(address.Address, "+") {
+		log.Error("Invalid email.incoming ADDRESS %q, it must be an email address without a sub-address. Incoming email is disabled.", IncomingEmail.Address)
+		IncomingEmail.Enabled = false
+		return
+	}
+	IncomingEmail.Address = address.Address
+	log.Info("Incoming Email Service Enabled")
+}
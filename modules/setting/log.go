@@ -190,6 +190,12 @@ func generateLogConfig(sec *ini.Section, name string, defaults defaultLogOptions
 		logConfig["reconnect"] = sec.Key("RECONNECT").MustBool()
 		logConfig["net"] = sec.Key("PROTOCOL").In("tcp", []string{"tcp", "unix", "udp"})
 		logConfig["addr"] = sec.Key("ADDR").MustString(":7020")
+	case "syslog":
+		logConfig["net"] = sec.Key("PROTOCOL").In("", []string{"udp", "tcp", "unix", "unixgram"})
+		logConfig["addr"] = sec.Key("ADDR").MustString("")
+		logConfig["facility"] = sec.Key("FACILITY").MustString("daemon")
+		logConfig["appName"] = sec.Key("APP_NAME").MustString("gitea")
+		logConfig["hostname"] = sec.Key("HOSTNAME").MustString("")
 	case "smtp":
 		logConfig["username"] = sec.Key("USER").MustString("example@example.com")
 		logConfig["password"] = sec.Key("PASSWD").MustString("******")
@@ -276,7 +282,7 @@ func newRouterLogService() {
 	if !DisableRouterLog {
 		options := newDefaultLogOptions()
 		options.filename = filepath.Join(LogRootPath, "router.log")
-		options.flags = "date,time" // For the router we don't want any prefixed flags
+		options.flags = "date,time,trace" // For the router we don't want any prefixed flags
 		options.bufferLength = Cfg.Section("log").Key("BUFFER_LEN").MustInt64(10000)
 		generateNamedLogger("router", options)
 	}
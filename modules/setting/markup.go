@@ -5,6 +5,7 @@
 package setting
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -19,6 +20,25 @@ var (
 	ExternalSanitizerRules  []MarkupSanitizerRule
 )
 
+// MarkupSanitizerPolicy holds the instance wide extensions of the sanitizer allow-list
+var MarkupSanitizerPolicy = struct {
+	AllowElements []string
+	IFrameDomains []string
+}{}
+
+var (
+	sanitizerElementPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	sanitizerDomainPattern  = regexp.MustCompile(`^(\*\.)?[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+	// sanitizerForbiddenElements can never be allowed as they are able to run scripts,
+	// change the behaviour of the page or embed arbitrary content
+	sanitizerForbiddenElements = map[string]bool{
+		"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+		"object": true, "embed": true, "applet": true, "base": true, "link": true,
+		"meta": true, "form": true, "template": true, "noscript": true, "svg": true, "math": true,
+	}
+)
+
 // MarkupRenderer defines the external parser configured in ini
 type MarkupRenderer struct {
 	Enabled              bool
@@ -43,6 +63,10 @@ func newMarkup() {
 	ExternalMarkupRenderers = make([]*MarkupRenderer, 0, 10)
 	ExternalSanitizerRules = make([]MarkupSanitizerRule, 0, 10)
 
+	if err := newMarkupSanitizerPolicy(Cfg.Section("markup")); err != nil {
+		log.Fatal("Invalid [markup] sanitizer settings: %v", err)
+	}
+
 	for _, sec := range Cfg.Section("markup").ChildSections() {
 		name := strings.TrimPrefix(sec.Name(), "markup.")
 		if name == "" {
@@ -58,6 +82,33 @@ func newMarkup() {
 	}
 }
 
+func newMarkupSanitizerPolicy(sec *ini.Section) error {
+	elements := sec.Key("SANITIZER_ALLOW_ELEMENTS").Strings(",")
+	for i, element := range elements {
+		element = strings.ToLower(element)
+		if !sanitizerElementPattern.MatchString(element) {
+			return fmt.Errorf("SANITIZER_ALLOW_ELEMENTS: %q is not a valid element name", element)
+		}
+		if sanitizerForbiddenElements[element] {
+			return fmt.Errorf("SANITIZER_ALLOW_ELEMENTS: %q cannot be allowed", element)
+		}
+		elements[i] = element
+	}
+
+	domains := sec.Key("SANITIZER_IFRAME_DOMAINS").Strings(",")
+	for i, domain := range domains {
+		domain = strings.ToLower(domain)
+		if !sanitizerDomainPattern.MatchString(domain) {
+			return fmt.Errorf("SANITIZER_IFRAME_DOMAINS: %q is not a valid domain", domain)
+		}
+		domains[i] = domain
+	}
+
+	MarkupSanitizerPolicy.AllowElements = elements
+	MarkupSanitizerPolicy.IFrameDomains = domains
+	return nil
+}
+
 func newMarkupSanitizer(name string, sec *ini.Section) {
 	rule, ok := createMarkupSanitizerRule(name, sec)
 	if ok {
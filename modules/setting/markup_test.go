@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ini "gopkg.in/ini.v1"
+)
+
+func TestNewMarkupSanitizerPolicy(t *testing.T) {
+	oldPolicy := MarkupSanitizerPolicy
+	defer func() {
+		MarkupSanitizerPolicy = oldPolicy
+	}()
+
+	parse := func(elements, domains string) error {
+		cfg := ini.Empty()
+		sec := cfg.Section("markup")
+		sec.Key("SANITIZER_ALLOW_ELEMENTS").SetValue(elements)
+		sec.Key("SANITIZER_IFRAME_DOMAINS").SetValue(domains)
+		return newMarkupSanitizerPolicy(sec)
+	}
+
+	assert.NoError(t, parse("Aside, x-note", "asciinema.org, *.Example.com"))
+	assert.Equal(t, []string{"aside", "x-note"}, MarkupSanitizerPolicy.AllowElements)
+	assert.Equal(t, []string{"asciinema.org", "*.example.com"}, MarkupSanitizerPolicy.IFrameDomains)
+
+	assert.Error(t, parse("script", ""))
+	assert.Error(t, parse("iframe", ""))
+	assert.Error(t, parse("a b", ""))
+	assert.Error(t, parse("", "https://asciinema.org"))
+	assert.Error(t, parse("", "example.com/path"))
+	assert.Error(t, parse("", "*example.com"))
+}
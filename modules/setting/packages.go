@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+// Packages settings
+var (
+	Packages = struct {
+		Storage
+		Enabled bool
+		MaxSize int64
+	}{
+		Enabled: false,
+		MaxSize: 100,
+	}
+)
+
+func newPackagesService() {
+	sec := Cfg.Section("packages")
+	Packages.Enabled = sec.Key("ENABLED").MustBool(false)
+	Packages.MaxSize = sec.Key("MAX_SIZE").MustInt64(100)
+
+	storageType := sec.Key("STORAGE_TYPE").MustString("")
+	Packages.Storage = getStorage("packages", storageType, sec)
+}
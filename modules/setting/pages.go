@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Pages settings
+var (
+	Pages = struct {
+		Enabled            bool
+		Path               string
+		DefaultBranch      string
+		MaxSize            int64
+		AllowCustomDomains bool
+	}{
+		Enabled:            false,
+		DefaultBranch:      "gh-pages",
+		MaxSize:            100,
+		AllowCustomDomains: false,
+	}
+)
+
+func newPagesService() {
+	sec := Cfg.Section("pages")
+	if err := sec.MapTo(&Pages); err != nil {
+		log.Fatal("Failed to map Pages settings: %v", err)
+	}
+
+	Pages.Path = sec.Key("PATH").MustString(filepath.Join(AppDataPath, "pages"))
+	if !filepath.IsAbs(Pages.Path) {
+		Pages.Path = filepath.Join(AppWorkPath, Pages.Path)
+	}
+}
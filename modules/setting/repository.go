@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -29,6 +30,9 @@ var (
 		DefaultPrivate                          string
 		DefaultPushCreatePrivate                bool
 		MaxCreationLimit                        int
+		DefaultSizeQuota                        int64
+		DiskUsageAlertThreshold                 int64
+		EnableAccessRequests                    bool
 		MirrorQueueLength                       int
 		PullRequestQueueLength                  int
 		PreferredLicenses                       []string
@@ -56,11 +60,13 @@ var (
 
 		// Repository upload settings
 		Upload struct {
-			Enabled      bool
-			TempPath     string
-			AllowedTypes string
-			FileMaxSize  int64
-			MaxFiles     int
+			Enabled       bool
+			TempPath      string
+			AllowedTypes  string
+			FileMaxSize   int64
+			MaxFiles      int
+			CommitMaxSize int64
+			LFSMinSize    int64 `ini:"LFS_MIN_SIZE"`
 		} `ini:"-"`
 
 		// Repository local settings
@@ -68,6 +74,12 @@ var (
 			LocalCopyPath string
 		} `ini:"-"`
 
+		// Repository media settings
+		Media struct {
+			InlineTypes          []string
+			AttachmentExtensions []string
+		} `ini:"-"`
+
 		// Pull request settings
 		PullRequest struct {
 			WorkInProgressPrefixes                   []string
@@ -79,11 +91,15 @@ var (
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
 			PopulateSquashCommentWithCommitMessages  bool
+			ConflictResolverURL                      string        `ini:"CONFLICT_RESOLVER_URL"`
+			ConflictResolverToken                    string        `ini:"CONFLICT_RESOLVER_TOKEN"`
+			ConflictResolverTimeout                  time.Duration `ini:"CONFLICT_RESOLVER_TIMEOUT"`
 		} `ini:"repository.pull-request"`
 
 		// Issue Setting
 		Issue struct {
-			LockReasons []string
+			LockReasons          []string
+			GoodFirstIssueLabels []string
 		} `ini:"repository.issue"`
 
 		Release struct {
@@ -142,6 +158,7 @@ var (
 		DefaultPrivate:                          RepoCreatingLastUserVisibility,
 		DefaultPushCreatePrivate:                true,
 		MaxCreationLimit:                        -1,
+		DefaultSizeQuota:                        -1,
 		MirrorQueueLength:                       1000,
 		PullRequestQueueLength:                  1000,
 		PreferredLicenses:                       []string{"Apache License 2.0", "MIT License"},
@@ -170,17 +187,21 @@ var (
 
 		// Repository upload settings
 		Upload: struct {
-			Enabled      bool
-			TempPath     string
-			AllowedTypes string
-			FileMaxSize  int64
-			MaxFiles     int
+			Enabled       bool
+			TempPath      string
+			AllowedTypes  string
+			FileMaxSize   int64
+			MaxFiles      int
+			CommitMaxSize int64
+			LFSMinSize    int64 `ini:"LFS_MIN_SIZE"`
 		}{
-			Enabled:      true,
-			TempPath:     "data/tmp/uploads",
-			AllowedTypes: "",
-			FileMaxSize:  3,
-			MaxFiles:     5,
+			Enabled:       true,
+			TempPath:      "data/tmp/uploads",
+			AllowedTypes:  "",
+			FileMaxSize:   3,
+			MaxFiles:      5,
+			CommitMaxSize: 0,
+			LFSMinSize:    0,
 		},
 
 		// Repository local settings
@@ -190,6 +211,15 @@ var (
 			LocalCopyPath: "tmp/local-repo",
 		},
 
+		// Repository media settings
+		Media: struct {
+			InlineTypes          []string
+			AttachmentExtensions []string
+		}{
+			InlineTypes:          []string{"image/*", "application/pdf"},
+			AttachmentExtensions: []string{".htm", ".html", ".xhtml", ".xht", ".mht", ".mhtml"},
+		},
+
 		// Pull request settings
 		PullRequest: struct {
 			WorkInProgressPrefixes                   []string
@@ -201,6 +231,9 @@ var (
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
 			PopulateSquashCommentWithCommitMessages  bool
+			ConflictResolverURL                      string        `ini:"CONFLICT_RESOLVER_URL"`
+			ConflictResolverToken                    string        `ini:"CONFLICT_RESOLVER_TOKEN"`
+			ConflictResolverTimeout                  time.Duration `ini:"CONFLICT_RESOLVER_TIMEOUT"`
 		}{
 			WorkInProgressPrefixes: []string{"WIP:", "[WIP]"},
 			// Same as GitHub. See
@@ -213,13 +246,16 @@ var (
 			DefaultMergeMessageMaxApprovers:          10,
 			DefaultMergeMessageOfficialApproversOnly: true,
 			PopulateSquashCommentWithCommitMessages:  false,
+			ConflictResolverTimeout:                  time.Minute,
 		},
 
 		// Issue settings
 		Issue: struct {
-			LockReasons []string
+			LockReasons          []string
+			GoodFirstIssueLabels []string
 		}{
-			LockReasons: strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			LockReasons:          strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			GoodFirstIssueLabels: []string{"good first issue"},
 		},
 
 		Release: struct {
@@ -264,6 +300,9 @@ func newRepository() {
 	Repository.DisableHTTPGit = sec.Key("DISABLE_HTTP_GIT").MustBool()
 	Repository.UseCompatSSHURI = sec.Key("USE_COMPAT_SSH_URI").MustBool()
 	Repository.MaxCreationLimit = sec.Key("MAX_CREATION_LIMIT").MustInt(-1)
+	Repository.DefaultSizeQuota = sec.Key("DEFAULT_SIZE_QUOTA").MustInt64(-1)
+	Repository.DiskUsageAlertThreshold = sec.Key("DISK_USAGE_ALERT_THRESHOLD").MustInt64(0)
+	Repository.EnableAccessRequests = sec.Key("ENABLE_ACCESS_REQUESTS").MustBool()
 	Repository.DefaultBranch = sec.Key("DEFAULT_BRANCH").MustString(Repository.DefaultBranch)
 	RepoRootPath = sec.Key("ROOT").MustString(path.Join(AppDataPath, "gitea-repositories"))
 	forcePathSeparator(RepoRootPath)
@@ -286,6 +325,8 @@ func newRepository() {
 		log.Fatal("Failed to map Repository.Upload settings: %v", err)
 	} else if err = Cfg.Section("repository.local").MapTo(&Repository.Local); err != nil {
 		log.Fatal("Failed to map Repository.Local settings: %v", err)
+	} else if err = Cfg.Section("repository.media").MapTo(&Repository.Media); err != nil {
+		log.Fatal("Failed to map Repository.Media settings: %v", err)
 	} else if err = Cfg.Section("repository.pull-request").MapTo(&Repository.PullRequest); err != nil {
 		log.Fatal("Failed to map Repository.PullRequest settings: %v", err)
 	}
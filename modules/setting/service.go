@@ -61,6 +61,10 @@ var Service = struct {
 	AutoWatchOnChanges                      bool
 	DefaultOrgMemberVisible                 bool
 	UserDeleteWithCommentsMaxTime           time.Duration
+	UsernameChangeCooldown                  time.Duration
+	UsernameReservationPeriod               time.Duration
+	EmailChangeRequireVerification          bool
+	EmailChangeNotifyOldAddress             bool
 	ValidSiteURLSchemes                     []string
 
 	// OpenID settings
@@ -160,6 +164,10 @@ func newService() {
 	Service.DefaultOrgVisibilityMode = structs.VisibilityModes[Service.DefaultOrgVisibility]
 	Service.DefaultOrgMemberVisible = sec.Key("DEFAULT_ORG_MEMBER_VISIBLE").MustBool()
 	Service.UserDeleteWithCommentsMaxTime = sec.Key("USER_DELETE_WITH_COMMENTS_MAX_TIME").MustDuration(0)
+	Service.UsernameChangeCooldown = sec.Key("USERNAME_CHANGE_COOLDOWN").MustDuration(0)
+	Service.UsernameReservationPeriod = sec.Key("USERNAME_RESERVATION_PERIOD").MustDuration(0)
+	Service.EmailChangeRequireVerification = sec.Key("EMAIL_CHANGE_REQUIRE_VERIFICATION").MustBool(false)
+	Service.EmailChangeNotifyOldAddress = sec.Key("EMAIL_CHANGE_NOTIFY_OLD_ADDRESS").MustBool(true)
 	sec.Key("VALID_SITE_URL_SCHEMES").MustString("http,https")
 	Service.ValidSiteURLSchemes = sec.Key("VALID_SITE_URL_SCHEMES").Strings(",")
 	schemes := make([]string, len(Service.ValidSiteURLSchemes))
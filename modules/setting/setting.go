@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/user"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/dustin/go-humanize"
 	jsoniter "github.com/json-iterator/go"
 	shellquote "github.com/kballard/go-shellquote"
 	"github.com/unknwon/com"
@@ -154,6 +155,10 @@ var (
 		TrustedUserCAKeysParsed               []gossh.PublicKey  `ini:"-"`
 		PerWriteTimeout                       time.Duration      `ini:"SSH_PER_WRITE_TIMEOUT"`
 		PerWritePerKbTimeout                  time.Duration      `ini:"SSH_PER_WRITE_PER_KB_TIMEOUT"`
+		MaxConnectionsPerIP                   int                `ini:"SSH_MAX_CONNECTIONS_PER_IP"`
+		MaxSessionsPerUser                    int                `ini:"SSH_MAX_SESSIONS_PER_USER"`
+		IdleTimeout                           time.Duration      `ini:"SSH_IDLE_TIMEOUT"`
+		MaxSessionDuration                    time.Duration      `ini:"SSH_MAX_SESSION_DURATION"`
 	}{
 		Disabled:                      false,
 		StartBuiltinServer:            false,
@@ -351,6 +356,12 @@ var (
 	Mirror struct {
 		DefaultInterval time.Duration
 		MinInterval     time.Duration
+		// MaxBandwidth is the total bandwidth of the pull mirror syncs in bytes per second, 0 if unlimited
+		MaxBandwidth int64
+		// SyncWindows restricts the scheduled pull mirror syncs to these times of day
+		SyncWindows []util.TimeWindow
+		// NotifyAfterFailures notifies the administrators of the pull mirrors failing this many syncs in a row, never if 0
+		NotifyAfterFailures int
 	}
 
 	// API settings
@@ -374,6 +385,7 @@ var (
 		Enable                     bool
 		AccessTokenExpirationTime  int64
 		RefreshTokenExpirationTime int64
+		DeviceCodeExpirationTime   int64
 		InvalidateRefreshTokens    bool
 		JWTSigningAlgorithm        string `ini:"JWT_SIGNING_ALGORITHM"`
 		JWTSecretBase64            string `ini:"JWT_SECRET"`
@@ -383,6 +395,7 @@ var (
 		Enable:                     true,
 		AccessTokenExpirationTime:  3600,
 		RefreshTokenExpirationTime: 730,
+		DeviceCodeExpirationTime:   600,
 		InvalidateRefreshTokens:    false,
 		JWTSigningAlgorithm:        "RS256",
 		JWTSigningPrivateKeyFile:   "jwt/private.pem",
@@ -803,6 +816,10 @@ func NewContext() {
 
 	SSH.PerWriteTimeout = sec.Key("SSH_PER_WRITE_TIMEOUT").MustDuration(PerWriteTimeout)
 	SSH.PerWritePerKbTimeout = sec.Key("SSH_PER_WRITE_PER_KB_TIMEOUT").MustDuration(PerWritePerKbTimeout)
+	SSH.MaxConnectionsPerIP = sec.Key("SSH_MAX_CONNECTIONS_PER_IP").MustInt(0)
+	SSH.MaxSessionsPerUser = sec.Key("SSH_MAX_SESSIONS_PER_USER").MustInt(0)
+	SSH.IdleTimeout = sec.Key("SSH_IDLE_TIMEOUT").MustDuration(0)
+	SSH.MaxSessionDuration = sec.Key("SSH_MAX_SESSION_DURATION").MustDuration(0)
 
 	if err = Cfg.Section("oauth2").MapTo(&OAuth2); err != nil {
 		log.Fatal("Failed to OAuth2 settings: %v", err)
@@ -857,6 +874,7 @@ func NewContext() {
 
 	newAttachmentService()
 	newLFSService()
+	newPackagesService()
 
 	timeFormatKey := Cfg.Section("time").Key("FORMAT").MustString("")
 	if timeFormatKey != "" {
@@ -947,6 +965,18 @@ func NewContext() {
 		log.Warn("Mirror.DefaultInterval is less than Mirror.MinInterval")
 		Mirror.DefaultInterval = time.Hour * 8
 	}
+	if bandwidth := sec.Key("MAX_BANDWIDTH").String(); bandwidth != "" {
+		size, err := humanize.ParseBytes(bandwidth)
+		if err != nil {
+			log.Fatal("Failed to parse mirror.MAX_BANDWIDTH %q: %v", bandwidth, err)
+		}
+		Mirror.MaxBandwidth = int64(size)
+	}
+	Mirror.SyncWindows, err = util.ParseTimeWindows(sec.Key("SYNC_WINDOWS").String())
+	if err != nil {
+		log.Fatal("Failed to parse mirror.SYNC_WINDOWS: %v", err)
+	}
+	Mirror.NotifyAfterFailures = sec.Key("NOTIFY_AFTER_FAILURES").MustInt(0)
 
 	Langs = Cfg.Section("i18n").Key("LANGS").Strings(",")
 	if len(Langs) == 0 {
@@ -1194,8 +1224,12 @@ func NewServices() {
 	newMailService()
 	newRegisterMailService()
 	newNotifyMailService()
+	newIncomingEmailService()
+	newProxyService()
 	newWebhookService()
+	newAntiSpamService()
 	newMigrationsService()
+	newPagesService()
 	newIndexerService()
 	newTaskService()
 	NewQueueService()
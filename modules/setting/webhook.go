@@ -6,6 +6,7 @@ package setting
 
 import (
 	"net/url"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -21,6 +22,20 @@ var (
 		ProxyURL       string
 		ProxyURLFixed  *url.URL
 		ProxyHosts     []string
+		// AllowPlaintextHTTP allows new webhooks to deliver their payloads over unencrypted HTTP
+		AllowPlaintextHTTP bool
+		// DeprecatedTypes lists the webhook types reported as deprecated by the endpoint audit
+		DeprecatedTypes []string
+		// AllowedHostList restricts the hosts webhooks may deliver to, see the hostmatcher module for the syntax
+		AllowedHostList string
+		// BlockedHostList lists the hosts webhooks may never deliver to
+		BlockedHostList string
+		// EventRetention is how long the events of the repositories are kept to be replayed, they are not recorded if 0
+		EventRetention time.Duration
+		// DisableAfterFailures disables the webhooks failing this many consecutive deliveries, never if 0
+		DisableAfterFailures int
+		// DisableAfterDays disables the webhooks whose deliveries keep failing for this many days, never if 0
+		DisableAfterDays int
 	}{
 		QueueLength:    1000,
 		DeliverTimeout: 5,
@@ -28,6 +43,10 @@ var (
 		PagingNum:      10,
 		ProxyURL:       "",
 		ProxyHosts:     []string{},
+
+		AllowPlaintextHTTP: true,
+		AllowedHostList:    "external",
+		EventRetention:     72 * time.Hour,
 	}
 )
 
@@ -48,4 +67,11 @@ func newWebhookService() {
 		}
 	}
 	Webhook.ProxyHosts = sec.Key("PROXY_HOSTS").Strings(",")
+	Webhook.AllowPlaintextHTTP = sec.Key("ALLOW_PLAINTEXT_HTTP").MustBool(true)
+	Webhook.DeprecatedTypes = sec.Key("DEPRECATED_TYPES").Strings(",")
+	Webhook.AllowedHostList = sec.Key("ALLOWED_HOST_LIST").MustString("external")
+	Webhook.BlockedHostList = sec.Key("BLOCKED_HOST_LIST").MustString("")
+	Webhook.EventRetention = sec.Key("EVENT_RETENTION").MustDuration(72 * time.Hour)
+	Webhook.DisableAfterFailures = sec.Key("DISABLE_AFTER_FAILURES").MustInt(0)
+	Webhook.DisableAfterDays = sec.Key("DISABLE_AFTER_DAYS").MustInt(0)
 }
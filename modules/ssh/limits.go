@@ -0,0 +1,189 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
+
+	"github.com/gliderlabs/ssh"
+)
+
+const (
+	giteaConn     = contextKey("gitea-conn")
+	giteaKeyOwner = contextKey("gitea-key-owner")
+)
+
+var (
+	errTooManySessions = errors.New("too many concurrent sessions")
+	errShuttingDown    = errors.New("the server is shutting down")
+)
+
+// keyOwner returns the identifier the sessions of a key are counted by, deploy keys are counted on their own
+func keyOwner(key *models.PublicKey) int64 {
+	if key.Type == models.KeyTypeDeploy {
+		return -key.ID
+	}
+	return key.OwnerID
+}
+
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// trackedConn is a connection counted by the connection tracker until it is closed
+type trackedConn struct {
+	net.Conn
+	tracker *connTracker
+	ip      string
+	once    sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.tracker.removeConn(c)
+	})
+	return c.Conn.Close()
+}
+
+// connTracker limits the concurrent connections per IP and sessions per user of the server
+// and drains them when shutting down
+type connTracker struct {
+	lock       sync.Mutex
+	maxPerIP   int
+	maxPerUser int
+	draining   bool
+	// number of running sessions of each connection
+	conns   map[*trackedConn]int
+	perIP   map[string]int
+	perUser map[int64]int
+}
+
+func newConnTracker(maxPerIP, maxPerUser int) *connTracker {
+	return &connTracker{
+		maxPerIP:   maxPerIP,
+		maxPerUser: maxPerUser,
+		conns:      make(map[*trackedConn]int),
+		perIP:      make(map[string]int),
+		perUser:    make(map[int64]int),
+	}
+}
+
+// connCallback tracks a new connection, it returns nil to refuse it
+func (t *connTracker) connCallback(ctx ssh.Context, conn net.Conn) net.Conn {
+	ip := remoteIP(conn.RemoteAddr())
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.draining {
+		return nil
+	}
+	if t.maxPerIP > 0 && t.perIP[ip] >= t.maxPerIP {
+		log.Warn("SSH: Refused connection from %s: too many concurrent connections", ip)
+		metrics.ObserveSSHRejection("ip_limit")
+		return nil
+	}
+
+	tc := &trackedConn{Conn: conn, tracker: t, ip: ip}
+	t.conns[tc] = 0
+	t.perIP[ip]++
+	ctx.SetValue(giteaConn, tc)
+	metrics.ObserveSSHConnection(true)
+	return tc
+}
+
+func (t *connTracker) removeConn(c *trackedConn) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.conns, c)
+	if t.perIP[c.ip]--; t.perIP[c.ip] <= 0 {
+		delete(t.perIP, c.ip)
+	}
+	metrics.ObserveSSHConnection(false)
+}
+
+// startSession counts a session of the owner of a key on the connection,
+// the returned function has to be called once it ends
+func (t *connTracker) startSession(conn *trackedConn, owner int64) (func(), error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.draining {
+		return nil, errShuttingDown
+	}
+	if t.maxPerUser > 0 && t.perUser[owner] >= t.maxPerUser {
+		metrics.ObserveSSHRejection("user_limit")
+		return nil, errTooManySessions
+	}
+
+	t.perUser[owner]++
+	if conn != nil {
+		t.conns[conn]++
+	}
+	metrics.ObserveSSHSession(true)
+
+	return func() {
+		t.lock.Lock()
+		if t.perUser[owner]--; t.perUser[owner] <= 0 {
+			delete(t.perUser, owner)
+		}
+		closeConn := false
+		if sessions, ok := t.conns[conn]; ok {
+			t.conns[conn] = sessions - 1
+			closeConn = t.draining && sessions == 1
+		}
+		t.lock.Unlock()
+		metrics.ObserveSSHSession(false)
+
+		if closeConn {
+			_ = conn.Close()
+		}
+	}, nil
+}
+
+// drain refuses new connections and sessions and closes the connections without a running session,
+// the others are closed once their sessions end
+func (t *connTracker) drain() {
+	t.lock.Lock()
+	t.draining = true
+	idle := make([]*trackedConn, 0, len(t.conns))
+	for conn, sessions := range t.conns {
+		if sessions == 0 {
+			idle = append(idle, conn)
+		}
+	}
+	t.lock.Unlock()
+
+	log.Info("SSH: Closing %d idle connections", len(idle))
+	for _, conn := range idle {
+		_ = conn.Close()
+	}
+}
+
+// handleSession runs the session if the limits allow it
+func (t *connTracker) handleSession(session ssh.Session) {
+	conn, _ := session.Context().Value(giteaConn).(*trackedConn)
+	owner, _ := session.Context().Value(giteaKeyOwner).(int64)
+
+	release, err := t.startSession(conn, owner)
+	if err != nil {
+		log.Warn("SSH: Refused session of key %d from %s: %v", session.Context().Value(giteaKeyID), session.RemoteAddr(), err)
+		_, _ = fmt.Fprintf(session.Stderr(), "Gitea: %v\n", err)
+		_ = session.Exit(1)
+		return
+	}
+	defer release()
+
+	sessionHandler(session)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ssh
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeContext only implements the SetValue method of ssh.Context
+type fakeContext struct {
+	ssh.Context
+	values map[interface{}]interface{}
+}
+
+func (ctx *fakeContext) SetValue(key, value interface{}) {
+	ctx.values[key] = value
+}
+
+// fakeConn is a connection from addr
+type fakeConn struct {
+	net.Conn
+	addr   net.Addr
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func connect(t *connTracker, ip string) (*trackedConn, *fakeConn) {
+	conn := &fakeConn{addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 2222}}
+	ctx := &fakeContext{values: make(map[interface{}]interface{})}
+	tc, _ := t.connCallback(ctx, conn).(*trackedConn)
+	if tc != nil && ctx.values[giteaConn] != tc {
+		return nil, conn
+	}
+	return tc, conn
+}
+
+func TestConnTracker_Limits(t *testing.T) {
+	tracker := newConnTracker(2, 1)
+
+	conn1, _ := connect(tracker, "10.0.0.1")
+	conn2, _ := connect(tracker, "10.0.0.1")
+	assert.NotNil(t, conn1)
+	assert.NotNil(t, conn2)
+	conn3, _ := connect(tracker, "10.0.0.1")
+	assert.Nil(t, conn3)
+	conn4, _ := connect(tracker, "10.0.0.2")
+	assert.NotNil(t, conn4)
+
+	assert.NoError(t, conn1.Close())
+	assert.NoError(t, conn1.Close())
+	conn3, _ = connect(tracker, "10.0.0.1")
+	assert.NotNil(t, conn3)
+	assert.Equal(t, 2, tracker.perIP["10.0.0.1"])
+
+	release, err := tracker.startSession(conn2, 1)
+	assert.NoError(t, err)
+	_, err = tracker.startSession(conn3, 1)
+	assert.Equal(t, errTooManySessions, err)
+	release2, err := tracker.startSession(conn3, 2)
+	assert.NoError(t, err)
+
+	release()
+	release, err = tracker.startSession(conn3, 1)
+	assert.NoError(t, err)
+	release()
+	release2()
+	assert.Empty(t, tracker.perUser)
+}
+
+func TestConnTracker_Drain(t *testing.T) {
+	tracker := newConnTracker(0, 0)
+
+	idle, idleConn := connect(tracker, "10.0.0.1")
+	busy, busyConn := connect(tracker, "10.0.0.1")
+	assert.NotNil(t, idle)
+	assert.NotNil(t, busy)
+	release, err := tracker.startSession(busy, 1)
+	assert.NoError(t, err)
+
+	tracker.drain()
+	assert.True(t, idleConn.closed)
+	assert.False(t, busyConn.closed)
+
+	refused, _ := connect(tracker, "10.0.0.2")
+	assert.Nil(t, refused)
+	_, err = tracker.startSession(busy, 1)
+	assert.Equal(t, errShuttingDown, err)
+
+	release()
+	assert.True(t, busyConn.closed)
+	assert.Empty(t, tracker.conns)
+	assert.Empty(t, tracker.perIP)
+}
@@ -22,6 +22,7 @@ import (
 	"syscall"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -71,11 +72,21 @@ func sessionHandler(session ssh.Session) {
 	ctx, cancel := context.WithCancel(session.Context())
 	defer cancel()
 
+	// the command is killed if it does not finish before the shutdown is forced
+	go func() {
+		select {
+		case <-graceful.GetManager().IsHammer():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	cmd := exec.CommandContext(ctx, setting.AppPath, args...)
 	cmd.Env = append(
 		os.Environ(),
 		"SSH_ORIGINAL_COMMAND="+command,
 		"SKIP_MINWINSVC=1",
+		sshConnectionEnv(session.RemoteAddr(), session.LocalAddr()),
 	)
 
 	stdout, err := cmd.StdoutPipe()
@@ -145,6 +156,13 @@ func sessionHandler(session ssh.Session) {
 	}
 }
 
+// sshConnectionEnv returns the SSH_CONNECTION variable OpenSSH sets with the addresses of the connection
+func sshConnectionEnv(remoteAddr, localAddr net.Addr) string {
+	remoteIP, remotePort, _ := net.SplitHostPort(remoteAddr.String())
+	localIP, localPort, _ := net.SplitHostPort(localAddr.String())
+	return fmt.Sprintf("SSH_CONNECTION=%s %s %s %s", remoteIP, remotePort, localIP, localPort)
+}
+
 func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 	if log.IsDebug() { // <- FingerprintSHA256 is kinda expensive so only calculate it if necessary
 		log.Debug("Handle Public Key: Fingerprint: %s from %s", gossh.FingerprintSHA256(key), ctx.RemoteAddr())
@@ -180,6 +198,10 @@ func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 				log.Error("SearchPublicKeyByContentExact: %v", err)
 				return false
 			}
+			if pkey.IsExpired() {
+				log.Debug("Principal Rejected: %s Expired Principal: %s", ctx.RemoteAddr(), principal)
+				continue principalLoop
+			}
 
 			c := &gossh.CertChecker{
 				IsUserAuthority: func(auth gossh.PublicKey) bool {
@@ -216,6 +238,7 @@ func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 				log.Debug("Successfully authenticated: %s Certificate Fingerprint: %s Principal: %s", ctx.RemoteAddr(), gossh.FingerprintSHA256(key), principal)
 			}
 			ctx.SetValue(giteaKeyID, pkey.ID)
+			ctx.SetValue(giteaKeyOwner, keyOwner(pkey))
 
 			return true
 		}
@@ -243,11 +266,19 @@ func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 		log.Error("SearchPublicKeyByContent: %v", err)
 		return false
 	}
+	if pkey.IsExpired() {
+		if log.IsWarn() {
+			log.Warn("Expired public key: %s from %s", gossh.FingerprintSHA256(key), ctx.RemoteAddr())
+			log.Warn("Failed authentication attempt from %s", ctx.RemoteAddr())
+		}
+		return false
+	}
 
 	if log.IsDebug() { // <- FingerprintSHA256 is kinda expensive so only calculate it if necessary
 		log.Debug("Successfully authenticated: %s Public Key Fingerprint: %s", ctx.RemoteAddr(), gossh.FingerprintSHA256(key))
 	}
 	ctx.SetValue(giteaKeyID, pkey.ID)
+	ctx.SetValue(giteaKeyOwner, keyOwner(pkey))
 
 	return true
 }
@@ -263,10 +294,14 @@ func sshConnectionFailed(conn net.Conn, err error) {
 
 // Listen starts a SSH server listens on given port.
 func Listen(host string, port int, ciphers []string, keyExchanges []string, macs []string) {
+	tracker := newConnTracker(setting.SSH.MaxConnectionsPerIP, setting.SSH.MaxSessionsPerUser)
 	srv := ssh.Server{
 		Addr:             fmt.Sprintf("%s:%d", host, port),
 		PublicKeyHandler: publicKeyHandler,
-		Handler:          sessionHandler,
+		Handler:          tracker.handleSession,
+		ConnCallback:     tracker.connCallback,
+		IdleTimeout:      setting.SSH.IdleTimeout,
+		MaxTimeout:       setting.SSH.MaxSessionDuration,
 		ServerConfigCallback: func(ctx ssh.Context) *gossh.ServerConfig {
 			config := &gossh.ServerConfig{}
 			config.KeyExchanges = keyExchanges
@@ -316,7 +351,7 @@ func Listen(host string, port int, ciphers []string, keyExchanges []string, macs
 		}
 	}
 
-	go listen(&srv)
+	go listen(&srv, tracker.drain)
 
 }
 
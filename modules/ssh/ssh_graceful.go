@@ -12,10 +12,11 @@ import (
 	"github.com/gliderlabs/ssh"
 )
 
-func listen(server *ssh.Server) {
+func listen(server *ssh.Server, onShutdown func()) {
 	gracefulServer := graceful.NewServer("tcp", server.Addr, "SSH")
 	gracefulServer.PerWriteTimeout = setting.SSH.PerWriteTimeout
 	gracefulServer.PerWritePerKbTimeout = setting.SSH.PerWritePerKbTimeout
+	gracefulServer.OnShutdown = onShutdown
 
 	err := gracefulServer.ListenAndServe(server.Serve)
 	if err != nil {
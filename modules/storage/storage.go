@@ -95,6 +95,35 @@ func Clean(storage ObjectStorage) error {
 	})
 }
 
+// Concatenate stores the objects at srcPaths one after another as a single object at dstPath,
+// the sources are left untouched. If size is unknown set -1
+func Concatenate(objStorage ObjectStorage, dstPath string, srcPaths []string, size int64) (int64, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		for _, srcPath := range srcPaths {
+			if err := copyObject(pw, objStorage, srcPath); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+
+	return objStorage.Save(dstPath, pr, size)
+}
+
+func copyObject(w io.Writer, objStorage ObjectStorage, p string) error {
+	obj, err := objStorage.Open(p)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	_, err = io.Copy(w, obj)
+	return err
+}
+
 // SaveFrom saves data to the ObjectStorage with path p from the callback
 func SaveFrom(objStorage ObjectStorage, p string, callback func(w io.Writer) error) error {
 	pr, pw := io.Pipe()
@@ -124,6 +153,9 @@ var (
 
 	// RepoArchives represents repository archives storage
 	RepoArchives ObjectStorage
+
+	// Packages represents the files of the package registry
+	Packages ObjectStorage
 )
 
 // Init init the stoarge
@@ -144,7 +176,11 @@ func Init() error {
 		return err
 	}
 
-	return initRepoArchives()
+	if err := initRepoArchives(); err != nil {
+		return err
+	}
+
+	return initPackages()
 }
 
 // NewStorage takes a storage type and some config and returns an ObjectStorage or an error
@@ -189,3 +225,9 @@ func initRepoArchives() (err error) {
 	RepoArchives, err = NewStorage(setting.RepoArchive.Storage.Type, &setting.RepoArchive.Storage)
 	return
 }
+
+func initPackages() (err error) {
+	log.Info("Initialising Packages storage with type: %s", setting.Packages.Storage.Type)
+	Packages, err = NewStorage(setting.Packages.Storage.Type, &setting.Packages.Storage)
+	return
+}
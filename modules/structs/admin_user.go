@@ -45,4 +45,6 @@ type EditUserOption struct {
 	AllowCreateOrganization *bool   `json:"allow_create_organization"`
 	Restricted              *bool   `json:"restricted"`
 	Visibility              string  `json:"visibility" binding:"In(,public,limited,private)"`
+	// maximum total size of the repositories in MiB, -1 means use the global default
+	SizeQuota *int64 `json:"size_quota"`
 }
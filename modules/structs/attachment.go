@@ -26,3 +26,27 @@ type Attachment struct {
 type EditAttachmentOptions struct {
 	Name string `json:"name"`
 }
+
+// CreateAttachmentUploadOption options for starting a resumable upload of an attachment
+// swagger:model
+type CreateAttachmentUploadOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+	// size of the file in bytes
+	// required: true
+	Size int64 `json:"size" binding:"Required"`
+}
+
+// AttachmentUpload represents a resumable upload of an attachment sent in chunks
+// swagger:model
+type AttachmentUpload struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// number of bytes received so far, the next chunk must start at this offset
+	Received int64 `json:"received"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
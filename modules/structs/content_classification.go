@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ContentClassificationAction is the decision of the content classifier
+type ContentClassificationAction string
+
+const (
+	// ContentClassificationAllow publishes the content
+	ContentClassificationAllow ContentClassificationAction = "allow"
+	// ContentClassificationFlag publishes the content and reports it to the moderators
+	ContentClassificationFlag ContentClassificationAction = "flag"
+	// ContentClassificationBlock rejects the content
+	ContentClassificationBlock ContentClassificationAction = "block"
+)
+
+// ContentClassificationRequest is sent to the content classifier of the instance
+// before an issue, a pull request or a comment is published
+type ContentClassificationRequest struct {
+	// issue, pull_request or comment
+	Kind string `json:"kind"`
+	// true if existing content is edited
+	IsEdit bool `json:"is_edit"`
+	// full name of the repository
+	Repository string `json:"repository"`
+	Author     *User  `json:"author"`
+	// title of the issue or the pull request, empty for comments
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// ContentClassificationResponse is returned by the content classifier
+type ContentClassificationResponse struct {
+	Action ContentClassificationAction `json:"action"`
+	// message shown to the author when the content is blocked
+	Message string `json:"message"`
+}
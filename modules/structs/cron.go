@@ -13,4 +13,14 @@ type Cron struct {
 	Next      time.Time `json:"next"`
 	Prev      time.Time `json:"prev"`
 	ExecTimes int64     `json:"exec_times"`
+	Enabled   bool      `json:"enabled"`
+	Running   bool      `json:"running"`
+	// start of the last run, scheduled or started by hand
+	LastRun *time.Time `json:"last_run"`
+	// duration of the last run in milliseconds
+	LastDuration int64 `json:"last_duration"`
+	// enum: success,error,aborted
+	LastStatus string `json:"last_status"`
+	// error of the last run if it failed or was aborted
+	LastMessage string `json:"last_message"`
 }
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Deployment represents an environment deployed for a pull request, e.g. a review app
+type Deployment struct {
+	ID int64 `json:"id"`
+	// index of the pull request the environment was deployed for
+	PullRequest    int64  `json:"pull_request"`
+	Environment    string `json:"environment"`
+	EnvironmentURL string `json:"environment_url"`
+	SHA            string `json:"sha"`
+	// false once the environment has been torn down
+	Active  bool  `json:"active"`
+	Creator *User `json:"creator"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateDeploymentOption options for registering the deployment of an environment for a pull request.
+// The deployment of an environment replaces the previous one of the same pull request.
+type CreateDeploymentOption struct {
+	// index of the pull request the environment was deployed for
+	// required: true
+	PullRequest int64 `json:"pull_request" binding:"Required"`
+	// name of the environment, e.g. "review"
+	// required: true
+	Environment string `json:"environment" binding:"Required;MaxSize(255)"`
+	// URL of the deployed environment
+	// required: true
+	EnvironmentURL string `json:"environment_url" binding:"Required;ValidUrl"`
+	// deployed commit, defaults to the head commit of the pull request
+	SHA string `json:"sha" binding:"MaxSize(40)"`
+}
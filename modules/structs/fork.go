@@ -9,3 +9,18 @@ type CreateForkOption struct {
 	// organization name, if forking into an organization
 	Organization *string `json:"organization"`
 }
+
+// ForkNetworkEntry represents a repository of a fork network
+type ForkNetworkEntry struct {
+	Repository *Repository `json:"repository"`
+	// full name of the repository it is a fork of, empty for the root of the network
+	Parent string `json:"parent"`
+	// distance to the root of the network
+	Depth int `json:"depth"`
+	// number of commits the default branch is ahead of the default branch of the parent,
+	// null if it can't be compared
+	AheadBy *int `json:"ahead_by"`
+	// number of commits the default branch is behind the default branch of the parent,
+	// null if it can't be compared
+	BehindBy *int `json:"behind_by"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// GitOperation represents a fetch or a push of a repository recorded in the audit trail
+type GitOperation struct {
+	ID int64 `json:"id"`
+	// id of the repository, kept when the repository is deleted
+	RepoID int64 `json:"repository_id"`
+	// full name of the repository, empty if it has been deleted
+	RepoFullName string `json:"repository"`
+	IsWiki       bool   `json:"wiki"`
+	// login of the user, empty for anonymous operations
+	UserName string `json:"user"`
+	// id of the SSH key used, if any
+	KeyID int64 `json:"key_id"`
+	// id of the access token used, if any
+	TokenID int64 `json:"token_id"`
+	// enum: fetch,push,archive
+	Type string `json:"type"`
+	// enum: ssh,http
+	Protocol string `json:"protocol"`
+	RemoteIP string `json:"remote_ip"`
+	// references updated by a push, as "<old commit> <new commit> <ref>"
+	Refs          []string `json:"refs"`
+	BytesReceived int64    `json:"bytes_received"`
+	BytesSent     int64    `json:"bytes_sent"`
+	DurationMs    int64    `json:"duration_ms"`
+	Finished      bool     `json:"finished"`
+	Succeeded     bool     `json:"succeeded"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
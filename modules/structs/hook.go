@@ -26,6 +26,10 @@ type Hook struct {
 	Config map[string]string `json:"config"`
 	Events []string          `json:"events"`
 	Active bool              `json:"active"`
+	// Health is "healthy", "failing", "disabled" after the deliveries kept failing, or "unknown" before any delivery
+	Health string `json:"health"`
+	// ConsecutiveFailures is the number of deliveries which failed in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
@@ -37,6 +41,8 @@ type HookList []*Hook
 
 // CreateHookOptionConfig has all config options in it
 // required are "content_type" and "url" Required
+// "payload_version" selects the schema of the payloads of gitea and gogs hooks, the latest one by default
+// "secret" signs the payloads with HMAC-SHA256, the signature is sent in the "signature_header" header, X-Gitea-Signature by default
 type CreateHookOptionConfig map[string]string
 
 // CreateHookOption options when create a hook
@@ -60,6 +66,51 @@ type EditHookOption struct {
 	Active       *bool             `json:"active"`
 }
 
+// HookDelivery represents a delivery of a webhook payload
+type HookDelivery struct {
+	ID        int64  `json:"id"`
+	UUID      string `json:"uuid"`
+	Event     string `json:"event"`
+	Delivered bool   `json:"delivered"`
+	Succeeded bool   `json:"succeeded"`
+	// swagger:strfmt date-time
+	DeliveredAt *time.Time            `json:"delivered_at"`
+	Request     *HookDeliveryRequest  `json:"request"`
+	Response    *HookDeliveryResponse `json:"response"`
+}
+
+// HookDeliveryRequest represents the request sent by a webhook delivery
+type HookDeliveryRequest struct {
+	URL        string            `json:"url"`
+	HTTPMethod string            `json:"http_method"`
+	Headers    map[string]string `json:"headers"`
+	Payload    string            `json:"payload"`
+}
+
+// HookDeliveryResponse represents the response received by a webhook delivery
+type HookDeliveryResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+// RepoEvent represents a recent event of a repository which can be replayed into its webhooks
+type RepoEvent struct {
+	ID    int64  `json:"id"`
+	Event string `json:"event"`
+	// the payload of the event in the format of the gitea webhooks
+	Payload string `json:"payload"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// ReplayRepoEventOption options to replay an event of a repository
+type ReplayRepoEventOption struct {
+	// id of the webhook of the repository to deliver the event to, whatever its events and branch filter.
+	// If it is not set, the event is delivered to the active webhooks as when it happened.
+	HookID int64 `json:"hook_id"`
+}
+
 // Payloader payload is some part of one hook
 type Payloader interface {
 	JSONPayload() ([]byte, error)
@@ -111,6 +162,8 @@ var (
 	_ Payloader = &IssueCommentPayload{}
 	_ Payloader = &PullRequestPayload{}
 	_ Payloader = &RepositoryPayload{}
+	_ Payloader = &RepositorySettingsPayload{}
+	_ Payloader = &DeploymentPayload{}
 	_ Payloader = &ReleasePayload{}
 )
 
@@ -376,6 +429,10 @@ type ChangesPayload struct {
 	Title *ChangesFromPayload `json:"title,omitempty"`
 	Body  *ChangesFromPayload `json:"body,omitempty"`
 	Ref   *ChangesFromPayload `json:"ref,omitempty"`
+	// Private is the previous visibility of a repository
+	Private *ChangesFromPayload `json:"private,omitempty"`
+	// Permission is the previous permission of a collaborator
+	Permission *ChangesFromPayload `json:"permission,omitempty"`
 }
 
 // __________      .__  .__    __________                                     __
@@ -438,3 +495,68 @@ func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
 	return json.MarshalIndent(p, "", " ")
 }
+
+// HookRepoSettingsAction an action that changes the settings of a repo
+type HookRepoSettingsAction string
+
+const (
+	// HookRepoSettingsBranchProtectionCreated branch protection created
+	HookRepoSettingsBranchProtectionCreated HookRepoSettingsAction = "branch_protection_created"
+	// HookRepoSettingsBranchProtectionUpdated branch protection updated
+	HookRepoSettingsBranchProtectionUpdated HookRepoSettingsAction = "branch_protection_updated"
+	// HookRepoSettingsBranchProtectionDeleted branch protection deleted
+	HookRepoSettingsBranchProtectionDeleted HookRepoSettingsAction = "branch_protection_deleted"
+	// HookRepoSettingsCollaboratorAdded collaborator added
+	HookRepoSettingsCollaboratorAdded HookRepoSettingsAction = "collaborator_added"
+	// HookRepoSettingsCollaboratorUpdated permission of a collaborator changed
+	HookRepoSettingsCollaboratorUpdated HookRepoSettingsAction = "collaborator_updated"
+	// HookRepoSettingsCollaboratorRemoved collaborator removed
+	HookRepoSettingsCollaboratorRemoved HookRepoSettingsAction = "collaborator_removed"
+	// HookRepoSettingsVisibilityChanged repository made private or public
+	HookRepoSettingsVisibilityChanged HookRepoSettingsAction = "visibility_changed"
+)
+
+// RepositorySettingsPayload payload for webhooks of repository settings changes
+type RepositorySettingsPayload struct {
+	Action     HookRepoSettingsAction `json:"action"`
+	Repository *Repository            `json:"repository"`
+	Sender     *User                  `json:"sender"`
+	// BranchProtection is set for branch protection actions, it holds the removed rule on deletion
+	BranchProtection *BranchProtection `json:"branch_protection,omitempty"`
+	// Collaborator is set for collaborator actions
+	Collaborator *User `json:"collaborator,omitempty"`
+	// Permission is the permission of the collaborator after the change
+	Permission string          `json:"permission,omitempty"`
+	Changes    *ChangesPayload `json:"changes,omitempty"`
+}
+
+// JSONPayload JSON representation of the payload
+func (p *RepositorySettingsPayload) JSONPayload() ([]byte, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.MarshalIndent(p, "", " ")
+}
+
+// HookDeploymentAction an action that happens to a deployment
+type HookDeploymentAction string
+
+const (
+	// HookDeploymentCreated environment deployed for a pull request
+	HookDeploymentCreated HookDeploymentAction = "created"
+	// HookDeploymentTornDown environment to tear down, its pull request was closed or merged
+	HookDeploymentTornDown HookDeploymentAction = "torn_down"
+)
+
+// DeploymentPayload payload for webhooks of the deployments of pull requests
+type DeploymentPayload struct {
+	Action      HookDeploymentAction `json:"action"`
+	Deployment  *Deployment          `json:"deployment"`
+	PullRequest *PullRequest         `json:"pull_request"`
+	Repository  *Repository          `json:"repository"`
+	Sender      *User                `json:"sender"`
+}
+
+// JSONPayload JSON representation of the payload
+func (p *DeploymentPayload) JSONPayload() ([]byte, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.MarshalIndent(p, "", " ")
+}
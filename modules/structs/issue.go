@@ -60,6 +60,7 @@ type Issue struct {
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
 	Comments int       `json:"comments"`
+	Votes    int       `json:"votes"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -71,6 +72,7 @@ type Issue struct {
 
 	PullRequest *PullRequestMeta `json:"pull_request"`
 	Repo        *RepositoryMeta  `json:"repository"`
+	Reactions   *ReactionSummary `json:"reactions"`
 }
 
 // CreateIssueOption options to create one issue
@@ -123,12 +125,14 @@ type IssueDeadline struct {
 // IssueTemplate represents an issue template for a repository
 // swagger:model
 type IssueTemplate struct {
-	Name     string   `json:"name" yaml:"name"`
-	Title    string   `json:"title" yaml:"title"`
-	About    string   `json:"about" yaml:"about"`
-	Labels   []string `json:"labels" yaml:"labels"`
-	Content  string   `json:"content" yaml:"-"`
-	FileName string   `json:"file_name" yaml:"-"`
+	Name   string   `json:"name" yaml:"name"`
+	Title  string   `json:"title" yaml:"title"`
+	About  string   `json:"about" yaml:"about"`
+	Labels []string `json:"labels" yaml:"labels"`
+	// user names of the assignees
+	Assignees []string `json:"assignees" yaml:"assignees"`
+	Content   string   `json:"content" yaml:"-"`
+	FileName  string   `json:"file_name" yaml:"-"`
 }
 
 // Valid checks whether an IssueTemplate is considered valid, e.g. at least name and about
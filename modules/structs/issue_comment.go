@@ -22,6 +22,8 @@ type Comment struct {
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
+
+	Reactions *ReactionSummary `json:"reactions"`
 }
 
 // CreateIssueCommentOption options for creating a comment on an issue
@@ -20,3 +20,10 @@ type Reaction struct {
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 }
+
+// ReactionSummary contains the number of reactions of an issue or a comment
+type ReactionSummary struct {
+	TotalCount int64 `json:"total_count"`
+	// number of reactions by allowed reaction type
+	Counts map[string]int64 `json:"counts"`
+}
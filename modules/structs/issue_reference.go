@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueReferenceGraph represents the cross-references between issues, pull requests and commits
+type IssueReferenceGraph struct {
+	Nodes []*IssueReferenceNode `json:"nodes"`
+	Edges []*IssueReferenceEdge `json:"edges"`
+}
+
+// IssueReferenceNode represents an issue, a pull request or a commit of a reference graph
+type IssueReferenceNode struct {
+	// unique identifier of the node in the graph, "issue:<id>" or "commit:<sha>"
+	ID string `json:"id"`
+	// enum: issue,pull,commit
+	Type       string          `json:"type"`
+	Repository *RepositoryMeta `json:"repository,omitempty"`
+	Index      int64           `json:"number,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	State      StateType       `json:"state,omitempty"`
+	HTMLURL    string          `json:"html_url,omitempty"`
+	SHA        string          `json:"sha,omitempty"`
+}
+
+// IssueReferenceEdge represents a reference from one node of a graph to another
+type IssueReferenceEdge struct {
+	// the node the reference was made from
+	From string `json:"from"`
+	// the referenced node
+	To string `json:"to"`
+	// where the reference was made: in the description of an issue or a pull request, in a comment or in a commit message
+	// enum: issue,pull,comment,commit
+	Type string `json:"type"`
+	// effect of the reference on the referenced node once resolved
+	// enum: none,closes,reopens
+	Action string `json:"action"`
+	// id of the comment the reference was made in, only set for comment references
+	CommentID int64 `json:"comment_id,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
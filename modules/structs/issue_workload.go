@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// WorkloadAge counts open issues and pull requests by the time elapsed since they were created
+type WorkloadAge struct {
+	UnderWeek    int64 `json:"under_week"`
+	UnderMonth   int64 `json:"under_month"`
+	UnderQuarter int64 `json:"under_quarter"`
+	Older        int64 `json:"older"`
+}
+
+// AssigneeWorkload represents the open issues and pull requests of an assignee
+type AssigneeWorkload struct {
+	Assignee   *User       `json:"assignee"`
+	OpenIssues int64       `json:"open_issues"`
+	OpenPulls  int64       `json:"open_pulls"`
+	Age        WorkloadAge `json:"age"`
+	// number of open pull requests waiting for a review of the assignee
+	PendingReviews int64 `json:"pending_reviews"`
+}
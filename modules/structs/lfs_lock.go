@@ -63,3 +63,23 @@ type LFSLockError struct {
 type LFSLockDeleteRequest struct {
 	Force bool `json:"force"`
 }
+
+// RepoLFSLock represents a LFS lock of a repository
+// for use with the repository API.
+type RepoLFSLock struct {
+	ID    int64  `json:"id"`
+	Path  string `json:"path"`
+	Owner *User  `json:"owner"`
+	// Stale is true if the owner of the lock has been removed
+	Stale bool `json:"stale"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// UnlockRepoLFSLocksOption options for unlocking several LFS locks of a repository
+type UnlockRepoLFSLocksOption struct {
+	// IDs of the locks to unlock
+	IDs []int64 `json:"ids"`
+	// unlock all the locks whose owners have been removed
+	Stale bool `json:"stale"`
+}
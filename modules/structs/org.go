@@ -15,6 +15,9 @@ type Organization struct {
 	Location                  string `json:"location"`
 	Visibility                string `json:"visibility"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// members who are not enrolled in two-factor authentication can't be added to teams
+	// and only have the access of other users to the repositories
+	RequireTwoFactor bool `json:"require_two_factor"`
 }
 
 // CreateOrgOption options for creating an organization
@@ -43,4 +46,6 @@ type EditOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess *bool  `json:"repo_admin_change_team_access"`
+	// can only be enabled by users who are enrolled in two-factor authentication
+	RequireTwoFactor *bool `json:"require_two_factor"`
 }
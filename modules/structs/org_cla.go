@@ -0,0 +1,45 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// CLA represents the current version of the contributor license agreement of an organization
+type CLA struct {
+	Version int    `json:"version"`
+	Content string `json:"content"`
+	// whether pull requests are blocked until all their commit authors have signed the agreement
+	Enforced bool `json:"enforced"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CLASignatureStatus represents whether a user has signed the current version of the contributor license agreement
+type CLASignatureStatus struct {
+	User    *User `json:"user"`
+	Version int   `json:"version"`
+	Signed  bool  `json:"signed"`
+	// latest version signed by the user, 0 if the user has never signed the agreement
+	SignedVersion int `json:"signed_version"`
+	// swagger:strfmt date-time
+	SignedAt *time.Time `json:"signed_at"`
+}
+
+// CLAAuthor represents a commit author of a pull request and whether it has signed the contributor license agreement
+type CLAAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// the user matching the email of the author, null if there is none
+	User   *User `json:"user"`
+	Signed bool  `json:"signed"`
+}
+
+// PullRequestCLAStatus represents whether the commit authors of a pull request have signed the current version
+// of the contributor license agreement of the organization owning its base repository
+type PullRequestCLAStatus struct {
+	Version int          `json:"version"`
+	Signed  bool         `json:"signed"`
+	Authors []*CLAAuthor `json:"authors"`
+}
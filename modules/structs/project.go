@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Project represents a project board of a repository
+type Project struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       StateType `json:"state"`
+	// enum: none,basic_kanban,bug_triage
+	BoardType    string `json:"board_type"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Closed *time.Time `json:"closed_at"`
+}
+
+// CreateProjectOption options for creating a project
+type CreateProjectOption struct {
+	// required:true
+	Title       string `json:"title" binding:"Required;MaxSize(100)"`
+	Description string `json:"description"`
+	// the columns the project is created with
+	// enum: none,basic_kanban,bug_triage
+	BoardType string `json:"board_type"`
+}
+
+// EditProjectOption options for editing a project
+type EditProjectOption struct {
+	Title       *string `json:"title" binding:"MaxSize(100)"`
+	Description *string `json:"description"`
+	// enum: open,closed
+	State *string `json:"state"`
+}
+
+// ProjectBoard represents a column of a project
+type ProjectBoard struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// whether issues not assigned to a column are shown in this one
+	Default bool `json:"default"`
+	Sorting int  `json:"sorting"`
+	// the event moving issues of the project to this column
+	// enum: none,close,merge
+	AutoMove string `json:"auto_move"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateProjectBoardOption options for creating a project column
+type CreateProjectBoardOption struct {
+	// required:true
+	Title string `json:"title" binding:"Required;MaxSize(100)"`
+	// enum: none,close,merge
+	AutoMove string `json:"auto_move"`
+}
+
+// EditProjectBoardOption options for editing a project column
+type EditProjectBoardOption struct {
+	Title   *string `json:"title" binding:"MaxSize(100)"`
+	Sorting *int8   `json:"sorting"`
+	// enum: none,close,merge
+	AutoMove *string `json:"auto_move"`
+}
+
+// MoveProjectIssueOption options for moving an issue to a project column
+type MoveProjectIssueOption struct {
+	// index of the issue or pull request, it is added to the project if it is not in it yet
+	// required:true
+	Issue int64 `json:"issue" binding:"Required"`
+}
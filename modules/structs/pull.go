@@ -39,6 +39,11 @@ type PullRequest struct {
 	Head      *PRBranchInfo `json:"head"`
 	MergeBase string        `json:"merge_base"`
 
+	// whether the poster has no other merged pull request in the base repository
+	IsFirstTimeContributor bool `json:"is_first_time_contributor"`
+	// whether the poster is neither the owner, a collaborator nor an organization member of the base repository
+	IsExternalContribution bool `json:"is_external_contribution"`
+
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
 
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PullAudit represents the signed audit bundle of a merged pull request
+type PullAudit struct {
+	Bundle *PullAuditBundle `json:"bundle"`
+	// compact JWS whose "bundle" claim holds the bundle, it can be verified with the keys published at keys_url
+	Signature string `json:"signature"`
+	Algorithm string `json:"algorithm"`
+	KeysURL   string `json:"keys_url"`
+}
+
+// PullAuditBundle represents the change management record of a merged pull request
+type PullAuditBundle struct {
+	Repository   string `json:"repository"`
+	Index        int64  `json:"index"`
+	Title        string `json:"title"`
+	HTMLURL      string `json:"html_url"`
+	Poster       string `json:"poster"`
+	Base         string `json:"base"`
+	Head         string `json:"head"`
+	HeadCommitID string `json:"head_commit_id"`
+	MergeBase    string `json:"merge_base"`
+	MergedBy     string `json:"merged_by"`
+	// swagger:strfmt date-time
+	Merged       time.Time               `json:"merged_at"`
+	MergeCommit  *PullAuditCommit        `json:"merge_commit"`
+	Commits      []*PullAuditCommit      `json:"commits"`
+	Approvals    []*PullAuditApproval    `json:"approvals"`
+	StatusChecks []*PullAuditStatusCheck `json:"status_checks"`
+	// swagger:strfmt date-time
+	Generated time.Time `json:"generated_at"`
+}
+
+// PullAuditApproval represents an approving review of a merged pull request
+type PullAuditApproval struct {
+	Reviewer  string `json:"reviewer"`
+	CommitID  string `json:"commit_id"`
+	Official  bool   `json:"official"`
+	Stale     bool   `json:"stale"`
+	Dismissed bool   `json:"dismissed"`
+	// swagger:strfmt date-time
+	Submitted time.Time `json:"submitted_at"`
+}
+
+// PullAuditStatusCheck represents the latest status of a context on the head commit of a merged pull request
+type PullAuditStatusCheck struct {
+	Context     string            `json:"context"`
+	State       CommitStatusState `json:"status"`
+	Description string            `json:"description"`
+	TargetURL   string            `json:"target_url"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// PullAuditCommit represents a commit of a merged pull request and the verification of its signature
+type PullAuditCommit struct {
+	SHA            string `json:"sha"`
+	Author         string `json:"author"`
+	AuthorEmail    string `json:"author_email"`
+	Committer      string `json:"committer"`
+	CommitterEmail string `json:"committer_email"`
+	// swagger:strfmt date-time
+	Authored time.Time `json:"authored_at"`
+	// swagger:strfmt date-time
+	Committed time.Time `json:"committed_at"`
+	Verified  bool      `json:"verified"`
+	Reason    string    `json:"reason"`
+	Signer    string    `json:"signer"`
+	SignerKey string    `json:"signer_key"`
+}
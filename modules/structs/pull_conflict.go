@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// MergeConflictRequest is sent to the conflict resolver of the instance
+// when merging a pull request leaves conflicted files
+type MergeConflictRequest struct {
+	// full name of the base repository
+	Repository  string               `json:"repository"`
+	PullRequest int64                `json:"pull_request"`
+	BaseBranch  string               `json:"base_branch"`
+	HeadBranch  string               `json:"head_branch"`
+	MergeStyle  string               `json:"merge_style"`
+	Files       []*MergeConflictFile `json:"files"`
+}
+
+// MergeConflictFile represents a conflicted file of a merge, the versions are
+// base64 encoded and null if the file does not exist on that side
+type MergeConflictFile struct {
+	Path string `json:"path"`
+	// version of the merge base
+	Base []byte `json:"base"`
+	// version of the base branch
+	Ours []byte `json:"ours"`
+	// version of the head branch
+	Theirs []byte `json:"theirs"`
+}
+
+// MergeConflictResolution is returned by the conflict resolver, it has to resolve every conflicted file
+type MergeConflictResolution struct {
+	Files []*MergeConflictResolvedFile `json:"files"`
+}
+
+// MergeConflictResolvedFile represents the resolution of a conflicted file
+type MergeConflictResolvedFile struct {
+	Path string `json:"path"`
+	// base64 encoded content of the file, ignored if the file is deleted
+	Content []byte `json:"content"`
+	Deleted bool   `json:"deleted"`
+}
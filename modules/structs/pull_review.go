@@ -38,6 +38,8 @@ type PullReview struct {
 	Official          bool            `json:"official"`
 	Dismissed         bool            `json:"dismissed"`
 	CodeCommentsCount int             `json:"comments_count"`
+	// the review checklist of the repository as filled in by the reviewer
+	Checklist []*PullReviewChecklistItem `json:"checklist"`
 	// swagger:strfmt date-time
 	Submitted time.Time `json:"submitted_at"`
 
@@ -45,6 +47,12 @@ type PullReview struct {
 	HTMLPullURL string `json:"pull_request_url"`
 }
 
+// PullReviewChecklistItem represents an item of the review checklist of a repository
+type PullReviewChecklistItem struct {
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
 // PullReviewComment represents a comment on a pull request review
 type PullReviewComment struct {
 	ID       int64  `json:"id"`
@@ -75,6 +83,8 @@ type CreatePullReviewOptions struct {
 	Body     string                    `json:"body"`
 	CommitID string                    `json:"commit_id"`
 	Comments []CreatePullReviewComment `json:"comments"`
+	// the items of the review checklist checked by the reviewer
+	Checklist []string `json:"checklist"`
 }
 
 // CreatePullReviewComment represent a review comment for creation api
@@ -92,6 +102,8 @@ type CreatePullReviewComment struct {
 type SubmitPullReviewOptions struct {
 	Event ReviewStateType `json:"event"`
 	Body  string          `json:"body"`
+	// the items of the review checklist checked by the reviewer
+	Checklist []string `json:"checklist"`
 }
 
 // DismissPullReviewOptions are options to dismiss a pull review
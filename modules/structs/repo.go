@@ -25,6 +25,8 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Count the :+1: reactions to issues as votes and allow sorting by them (Built-in issue tracker)
+	EnableIssueVoting bool `json:"enable_issue_voting"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -34,8 +36,10 @@ type ExternalTracker struct {
 	ExternalTrackerURL string `json:"external_tracker_url"`
 	// External Issue Tracker URL Format. Use the placeholders {user}, {repo} and {index} for the username, repository name and issue index.
 	ExternalTrackerFormat string `json:"external_tracker_format"`
-	// External Issue Tracker Number Format, either `numeric` or `alphanumeric`
+	// External Issue Tracker Number Format, either `numeric`, `alphanumeric` or `regexp`
 	ExternalTrackerStyle string `json:"external_tracker_style"`
+	// External Issue Tracker Regular Expression Pattern matching the references to the tracker when the style is `regexp`, its first group is used as the issue index
+	ExternalTrackerRegexpPattern string `json:"external_tracker_regexp_pattern"`
 }
 
 // ExternalWiki represents setting for external wiki
@@ -91,8 +95,31 @@ type Repository struct {
 	AllowSquash               bool             `json:"allow_squash_merge"`
 	DefaultMergeStyle         string           `json:"default_merge_style"`
 	AvatarURL                 string           `json:"avatar_url"`
+	AccentColor               string           `json:"accent_color"`
 	Internal                  bool             `json:"internal"`
 	MirrorInterval            string           `json:"mirror_interval"`
+	// swagger:strfmt date-time
+	MirrorUpdated *time.Time `json:"mirror_updated,omitempty"`
+	// MirrorNextUpdate is the time of the next scheduled synchronization, it is omitted if the mirror isn't synchronized periodically
+	// swagger:strfmt date-time
+	MirrorNextUpdate *time.Time `json:"mirror_next_update,omitempty"`
+	// MirrorLastError is the error of the last failed synchronization, it is only shown to users allowed to synchronize the mirror
+	MirrorLastError string `json:"mirror_last_error,omitempty"`
+	// MirrorLastErrorAt is the time of the last failed synchronization, it is only shown to users allowed to synchronize the mirror
+	// swagger:strfmt date-time
+	MirrorLastErrorAt *time.Time `json:"mirror_last_error_at,omitempty"`
+	// MirrorFailureCount is the number of synchronizations which failed in a row, it is only shown to users allowed to synchronize the mirror
+	MirrorFailureCount int           `json:"mirror_failure_count,omitempty"`
+	ReadmePath         string        `json:"readme_path"`
+	ShowWikiHome       bool          `json:"show_wiki_home"`
+	RepoTransfer       *RepoTransfer `json:"repo_transfer,omitempty"`
+}
+
+// RepoTransfer represents a pending repo transfer
+type RepoTransfer struct {
+	Doer      *User   `json:"doer"`
+	Recipient *User   `json:"recipient"`
+	Teams     []*Team `json:"teams"`
 }
 
 // CreateRepoOption options when creating repository
@@ -136,6 +163,8 @@ type EditRepoOption struct {
 	Description *string `json:"description,omitempty" binding:"MaxSize(255)"`
 	// a URL with more information about the repository.
 	Website *string `json:"website,omitempty" binding:"MaxSize(255)"`
+	// accent color of the repository as #rrggbb, an empty string removes it
+	AccentColor *string `json:"accent_color,omitempty"`
 	// either `true` to make the repository private or `false` to make it public.
 	// Note: you will get a 422 error if the organization restricts changing repository visibility to organization
 	// owners and a non-owner tries to change the value of private.
@@ -154,6 +183,8 @@ type EditRepoOption struct {
 	ExternalWiki *ExternalWiki `json:"external_wiki,omitempty"`
 	// sets the default branch for this repository.
 	DefaultBranch *string `json:"default_branch,omitempty"`
+	// renames the default branch of this repository, along with the branch protection, the open pull requests and the issues referencing it.
+	RenameDefaultBranch *string `json:"rename_default_branch,omitempty"`
 	// either `true` to allow pull requests, or `false` to prevent pull request.
 	HasPullRequests *bool `json:"has_pull_requests,omitempty"`
 	// either `true` to enable project unit, or `false` to disable them.
@@ -180,6 +211,10 @@ type EditRepoOption struct {
 	Archived *bool `json:"archived,omitempty"`
 	// set to a string like `8h30m0s` to set the mirror interval time
 	MirrorInterval *string `json:"mirror_interval,omitempty"`
+	// file or directory rendered on the repository home page, an empty string detects the README automatically
+	ReadmePath *string `json:"readme_path,omitempty"`
+	// set to `true` to render the wiki home page instead of the README on the repository home page
+	ShowWikiHome *bool `json:"show_wiki_home,omitempty"`
 }
 
 // GenerateRepoOption options when creating repository using a template
@@ -210,6 +245,23 @@ type GenerateRepoOption struct {
 	Avatar bool `json:"avatar"`
 	// include labels in template repo
 	Labels bool `json:"labels"`
+	// include the units and their settings of the template repo
+	Units bool `json:"units"`
+}
+
+// RenameBranchRepoOption options when renaming a branch in a repository
+// swagger:model
+type RenameBranchRepoOption struct {
+	// Name of the branch to rename
+	//
+	// required: true
+	OldBranchName string `json:"old_branch_name" binding:"Required;GitRefName;MaxSize(100)"`
+
+	// New name of the branch
+	//
+	// required: true
+	// unique: true
+	NewBranchName string `json:"new_branch_name" binding:"Required;GitRefName;MaxSize(100)"`
 }
 
 // CreateBranchRepoOption options when creating a branch in a repository
@@ -242,12 +294,13 @@ type GitServiceType int
 
 // enumerate all GitServiceType
 const (
-	NotMigrated     GitServiceType = iota // 0 not migrated from external sites
-	PlainGitService                       // 1 plain git service
-	GithubService                         // 2 github.com
-	GiteaService                          // 3 gitea service
-	GitlabService                         // 4 gitlab service
-	GogsService                           // 5 gogs service
+	NotMigrated      GitServiceType = iota // 0 not migrated from external sites
+	PlainGitService                        // 1 plain git service
+	GithubService                          // 2 github.com
+	GiteaService                           // 3 gitea service
+	GitlabService                          // 4 gitlab service
+	GogsService                            // 5 gogs service
+	BitbucketService                       // 6 bitbucket service
 )
 
 // Name represents the service type's name
@@ -267,6 +320,8 @@ func (gt GitServiceType) Title() string {
 		return "GitLab"
 	case GogsService:
 		return "Gogs"
+	case BitbucketService:
+		return "Bitbucket"
 	case PlainGitService:
 		return "Git"
 	}
@@ -285,7 +340,7 @@ type MigrateRepoOptions struct {
 	// required: true
 	RepoName string `json:"repo_name" binding:"Required;AlphaDashDot;MaxSize(100)"`
 
-	// enum: git,github,gitea,gitlab
+	// enum: git,github,gitea,gitlab,gogs,bitbucket
 	Service      string `json:"service"`
 	AuthUsername string `json:"auth_username"`
 	AuthPassword string `json:"auth_password"`
@@ -322,5 +377,6 @@ var (
 		GitlabService,
 		GiteaService,
 		GogsService,
+		BitbucketService,
 	}
 )
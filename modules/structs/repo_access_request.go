@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoAccessRequest represents a request of a user to access a private repository
+type RepoAccessRequest struct {
+	ID int64 `json:"id"`
+	// full name of the repository
+	RepoFullName string `json:"repository"`
+	User         *User  `json:"user"`
+	Message      string `json:"message"`
+	// enum: pending,approved,rejected
+	State string `json:"state"`
+	// permission granted when the request was approved
+	// enum: read,write,admin
+	Permission string `json:"permission,omitempty"`
+	Reviewer   *User  `json:"reviewer,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Reviewed *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// CreateRepoAccessRequestOption options for requesting access to a private repository
+type CreateRepoAccessRequestOption struct {
+	// message to the administrators of the repository
+	Message string `json:"message" binding:"MaxSize(1000)"`
+}
+
+// ApproveRepoAccessRequestOption options for approving a request to access a repository
+type ApproveRepoAccessRequestOption struct {
+	// permission granted to the user
+	// required: true
+	// enum: read,write,admin
+	Permission string `json:"permission" binding:"Required;In(read,write,admin)"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// UnadoptedRepositoriesOption options to adopt or delete unadopted repositories in bulk
+type UnadoptedRepositoriesOption struct {
+	// required: true
+	// enum: adopt,delete
+	Action string `json:"action" binding:"Required;In(adopt,delete)"`
+	// unadopted repositories as owner/name
+	// required: true
+	Repositories []string `json:"repositories" binding:"Required"`
+}
+
+// UnadoptedRepositoryResult represents the outcome of adopting or deleting an unadopted repository
+type UnadoptedRepositoryResult struct {
+	Repository string `json:"repository"`
+	Success    bool   `json:"success"`
+	// reason of the failure, empty on success
+	Error string `json:"error,omitempty"`
+}
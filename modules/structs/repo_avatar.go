@@ -0,0 +1,13 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// UpdateRepoAvatarOption options when updating the avatar of a repository
+type UpdateRepoAvatarOption struct {
+	// image of the avatar as a base64 encoded string
+	Image string `json:"image"`
+	// set to `true` to generate the avatar from the name of the repository instead, image is ignored then
+	Generate bool `json:"generate"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// FileBlame represents the blame of a file, its ranges cover the lines of the file in order
+type FileBlame struct {
+	Path string `json:"path"`
+	// commit the blame was made at
+	SHA    string        `json:"sha"`
+	Ranges []*BlameRange `json:"ranges"`
+}
+
+// BlameRange represents consecutive lines of a file last changed by the same commit
+type BlameRange struct {
+	// first line of the range, starting at 1
+	StartLine int `json:"start_line"`
+	// last line of the range, inclusive
+	EndLine int          `json:"end_line"`
+	Commit  *BlameCommit `json:"commit"`
+}
+
+// BlameCommit contains information of the commit which last changed the lines of a blame range
+type BlameCommit struct {
+	SHA       string      `json:"sha"`
+	URL       string      `json:"url"`
+	HTMLURL   string      `json:"html_url"`
+	Author    *CommitUser `json:"author"`
+	Committer *CommitUser `json:"committer"`
+	Message   string      `json:"message"`
+}
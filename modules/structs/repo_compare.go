@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Compare represents the comparison between two revisions
+type Compare struct {
+	BaseCommit   string         `json:"base_commit"`
+	HeadCommit   string         `json:"head_commit"`
+	MergeBase    string         `json:"merge_base"`
+	TotalCommits int            `json:"total_commits"`
+	Commits      []*Commit      `json:"commits"`
+	ChangedFiles int            `json:"changed_files"`
+	Additions    int            `json:"additions"`
+	Deletions    int            `json:"deletions"`
+	Files        []*ChangedFile `json:"files"`
+}
+
+// ChangedFile store information about a file changed between two revisions
+type ChangedFile struct {
+	Filename string `json:"filename"`
+	// enum: added,deleted,modified
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	IsBinary  bool   `json:"is_binary"`
+}
+
+// CompareRemoteOption options for comparing a ref of a remote repository with a ref of the repository
+type CompareRemoteOption struct {
+	// branch, tag or commit of the repository to compare with, defaults to the default branch
+	Base string `json:"base"`
+	// URL of the remote repository
+	// required: true
+	RemoteAddress string `json:"remote_address" binding:"Required"`
+	// branch or tag of the remote repository, defaults to its HEAD
+	RemoteRef    string `json:"remote_ref"`
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+}
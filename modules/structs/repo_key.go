@@ -38,4 +38,10 @@ type CreateKeyOption struct {
 	//
 	// required: false
 	ReadOnly bool `json:"read_only"`
+	// Expiry date of a user key, the key never expires if it is empty.
+	// Ignored for deploy keys.
+	//
+	// required: false
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
 }
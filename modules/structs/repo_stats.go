@@ -0,0 +1,73 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ContributorStats represents the weekly activity of a contributor of the default branch
+type ContributorStats struct {
+	// user of the commit author, null if the email does not belong to a user
+	Author *User  `json:"author"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	// total number of commits
+	Total int64                   `json:"total"`
+	Weeks []*ContributorWeekStats `json:"weeks"`
+}
+
+// ContributorWeekStats represents the activity of a contributor during a week
+type ContributorWeekStats struct {
+	// start of the week as unix timestamp, weeks start on Sunday at midnight UTC
+	Week      int64 `json:"w"`
+	Additions int64 `json:"a"`
+	Deletions int64 `json:"d"`
+	Commits   int64 `json:"c"`
+}
+
+// CommitActivity represents the number of commits of the default branch during a week
+type CommitActivity struct {
+	// number of commits of each day of the week, starting on Sunday
+	Days  []int64 `json:"days"`
+	Total int64   `json:"total"`
+	// start of the week as unix timestamp, weeks start on Sunday at midnight UTC
+	Week int64 `json:"week"`
+}
+
+// ReviewerStats represents the review statistics of a reviewer
+type ReviewerStats struct {
+	Reviewer *User `json:"reviewer"`
+	// number of submitted reviews
+	Reviews          int64 `json:"reviews"`
+	Approvals        int64 `json:"approvals"`
+	ChangesRequested int64 `json:"changes_requested"`
+	// number of code comments made in the reviews
+	Comments int64 `json:"comments"`
+	// average time in seconds between a review request, or the creation of the pull request
+	// if the reviewer was not requested, and the first review following it
+	AverageTurnaround int64 `json:"average_turnaround"`
+}
+
+// DirectoryStats represents the size and the history intensity of the directories of a repository,
+// they help choosing the patterns of a sparse checkout
+type DirectoryStats struct {
+	// SHA of the commit the stats are computed for
+	SHA string `json:"sha"`
+	// commits before this time are not counted
+	// swagger:strfmt date-time
+	Since       time.Time        `json:"since"`
+	Directories []*DirectoryStat `json:"directories"`
+}
+
+// DirectoryStat represents the size and the history intensity of a directory
+type DirectoryStat struct {
+	Path string `json:"path"`
+	// total size in bytes of the files below the directory
+	Size      int64 `json:"size"`
+	FileCount int64 `json:"file_count"`
+	// number of commits since the given time which changed files below the directory
+	CommitCount int64 `json:"commit_count"`
+}
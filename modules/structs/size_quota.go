@@ -0,0 +1,14 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// SizeQuota represents the size quota of the repositories of a user or an organization, in bytes
+type SizeQuota struct {
+	// -1 means no limit
+	Limit int64 `json:"limit"`
+	Used  int64 `json:"used"`
+	// -1 means no limit
+	Remaining int64 `json:"remaining"`
+}
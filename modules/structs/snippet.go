@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Snippet represents a set of files shared by a user
+type Snippet struct {
+	ID          int64  `json:"id"`
+	UUID        string `json:"uuid"`
+	Owner       *User  `json:"owner"`
+	Description string `json:"description"`
+	// enum: public,unlisted,private
+	Visibility  string         `json:"visibility"`
+	HTMLURL     string         `json:"html_url"`
+	Files       []*SnippetFile `json:"files"`
+	NumComments int            `json:"comments_count"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SnippetFile represents a file of a snippet
+type SnippetFile struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	RawURL  string `json:"raw_url"`
+	Content string `json:"content,omitempty"`
+}
+
+// SnippetFileOption options for a file of a snippet
+type SnippetFileOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	Content string `json:"content"`
+}
+
+// CreateSnippetOption options for creating a snippet
+type CreateSnippetOption struct {
+	Description string `json:"description" binding:"MaxSize(255)"`
+	// enum: public,unlisted,private
+	Visibility string `json:"visibility" binding:"In(,public,unlisted,private)"`
+	// required: true
+	Files []*SnippetFileOption `json:"files" binding:"Required"`
+}
+
+// EditSnippetOption options for editing a snippet, the files replace all current files when given
+type EditSnippetOption struct {
+	Description *string `json:"description" binding:"MaxSize(255)"`
+	// enum: public,unlisted,private
+	Visibility *string              `json:"visibility" binding:"OmitEmpty;In(public,unlisted,private)"`
+	Files      []*SnippetFileOption `json:"files"`
+}
+
+// SnippetComment represents a comment on a snippet
+type SnippetComment struct {
+	ID     int64  `json:"id"`
+	Poster *User  `json:"user"`
+	Body   string `json:"body"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateSnippetCommentOption options for commenting on a snippet
+type CreateSnippetCommentOption struct {
+	// required: true
+	Body string `json:"body" binding:"Required"`
+}
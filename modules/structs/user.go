@@ -45,6 +45,9 @@ type User struct {
 	Description string `json:"description"`
 	// User visibility level option: public, limited, private
 	Visibility string `json:"visibility"`
+	// Is the user enrolled in two-factor authentication, only listed for the owners of an organization
+	// in the members of its teams
+	TwoFactorEnabled *bool `json:"two_factor_enabled,omitempty"`
 
 	// user counts
 	Followers    int `json:"followers_count"`
@@ -76,6 +79,8 @@ type UserSettings struct {
 	// Privacy
 	HideEmail    bool `json:"hide_email"`
 	HideActivity bool `json:"hide_activity"`
+	// HideActivityFromAnonymous hides the activity and the heatmap from users who are not signed in
+	HideActivityFromAnonymous bool `json:"hide_activity_from_anonymous"`
 }
 
 // UserSettingsOptions represents options to change user settings
@@ -91,4 +96,6 @@ type UserSettingsOptions struct {
 	// Privacy
 	HideEmail    *bool `json:"hide_email"`
 	HideActivity *bool `json:"hide_activity"`
+	// HideActivityFromAnonymous hides the activity and the heatmap from users who are not signed in
+	HideActivityFromAnonymous *bool `json:"hide_activity_from_anonymous"`
 }
@@ -16,6 +16,8 @@ type AccessToken struct {
 	Name           string `json:"name"`
 	Token          string `json:"sha1"`
 	TokenLastEight string `json:"token_last_eight"`
+	// empty if the token is not restricted
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // AccessTokenList represents a list of API access token.
@@ -26,23 +28,61 @@ type AccessTokenList []*AccessToken
 // swagger:parameters userCreateToken
 type CreateAccessTokenOption struct {
 	Name string `json:"name" binding:"Required"`
+	// "write:status" restricts the token to create and read commit statuses,
+	// the token is not restricted if no scopes are given
+	Scopes []string `json:"scopes"`
+}
+
+// OrgAccessToken represents an API access token of an organization
+type OrgAccessToken struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Token          string `json:"sha1"`
+	TokenLastEight string `json:"token_last_eight"`
+	// enum: admin:org,write:package,write:status
+	Scopes []string `json:"scopes"`
+	// login of the owner who created the token
+	Creator string `json:"creator"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// null if the token never expires
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
+}
+
+// CreateOrgAccessTokenOption options when create an access token of an organization
+type CreateOrgAccessTokenOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+	// "admin:org" allows to use the administration API of the organization,
+	// "write:package" allows to publish and read the packages of the organization,
+	// "write:status" allows to create and read the commit statuses of the repositories of the organization
+	// required: true
+	Scopes []string `json:"scopes" binding:"Required"`
+	// the token never expires if it is not set
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
 }
 
 // CreateOAuth2ApplicationOptions holds options to create an oauth2 application
 type CreateOAuth2ApplicationOptions struct {
-	Name         string   `json:"name" binding:"Required"`
-	RedirectURIs []string `json:"redirect_uris" binding:"Required"`
+	Name               string   `json:"name" binding:"Required"`
+	RedirectURIs       []string `json:"redirect_uris" binding:"Required"`
+	ConfidentialClient bool     `json:"confidential_client"`
+	EnableDeviceFlow   bool     `json:"enable_device_flow"`
 }
 
 // OAuth2Application represents an OAuth2 application.
 // swagger:response OAuth2Application
 type OAuth2Application struct {
-	ID           int64     `json:"id"`
-	Name         string    `json:"name"`
-	ClientID     string    `json:"client_id"`
-	ClientSecret string    `json:"client_secret"`
-	RedirectURIs []string  `json:"redirect_uris"`
-	Created      time.Time `json:"created"`
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	ClientID           string    `json:"client_id"`
+	ClientSecret       string    `json:"client_secret"`
+	RedirectURIs       []string  `json:"redirect_uris"`
+	ConfidentialClient bool      `json:"confidential_client"`
+	EnableDeviceFlow   bool      `json:"enable_device_flow"`
+	Created            time.Time `json:"created"`
 }
 
 // OAuth2ApplicationList represents a list of OAuth2 applications.
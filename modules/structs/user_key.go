@@ -16,8 +16,12 @@ type PublicKey struct {
 	Title       string `json:"title,omitempty"`
 	Fingerprint string `json:"fingerprint,omitempty"`
 	// swagger:strfmt date-time
-	Created  time.Time `json:"created_at,omitempty"`
-	Owner    *User     `json:"user,omitempty"`
-	ReadOnly bool      `json:"read_only,omitempty"`
-	KeyType  string    `json:"key_type,omitempty"`
+	Created time.Time `json:"created_at,omitempty"`
+	// swagger:strfmt date-time
+	Expires  *time.Time `json:"expires_at,omitempty"`
+	Owner    *User      `json:"user,omitempty"`
+	ReadOnly bool       `json:"read_only,omitempty"`
+	KeyType  string     `json:"key_type,omitempty"`
+	// how the key was added: web, api, import or unknown
+	CreatedFrom string `json:"created_from,omitempty"`
 }
@@ -27,6 +27,8 @@ func handleCreateError(owner *models.User, err error) error {
 	switch {
 	case models.IsErrReachLimitOfRepo(err):
 		return fmt.Errorf("You have already reached your limit of %d repositories", owner.MaxCreationLimit())
+	case models.IsErrSizeQuotaExceeded(err):
+		return fmt.Errorf("The size quota of %s is exceeded", owner.Name)
 	case models.IsErrRepoAlreadyExist(err):
 		return errors.New("The repository name is already used")
 	case models.IsErrNameReserved(err):
@@ -126,6 +128,10 @@ func runMigrateTask(t *models.Task) (err error) {
 		err = errors.New("The repository name is already used")
 		return
 	}
+	if models.IsErrSizeQuotaExceeded(err) {
+		err = handleCreateError(t.Owner, err)
+		return
+	}
 
 	// remoteAddr may contain credentials, so we sanitize it
 	err = util.NewStringURLSanitizedError(err, opts.CloneAddr, true)
@@ -0,0 +1,182 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tasklog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// MaxLines is the number of lines kept in the buffer of a log
+const MaxLines = 1000
+
+// Line represents a line of the log of a task
+type Line struct {
+	Offset  int64     `json:"offset"`
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	// Progress is the completion of the task in percent, -1 if the line is not a progress marker
+	Progress int `json:"progress"`
+}
+
+// Log is the buffered log of the last run of a task, the methods may be called on a nil Log
+type Log struct {
+	Name string
+	// RunID identifies the run of the task among the runs of all tasks
+	RunID int64
+
+	lock    sync.Mutex
+	lines   []*Line
+	next    int64
+	done    bool
+	changed chan struct{}
+}
+
+func newLog(name string, runID int64) *Log {
+	return &Log{
+		Name:    name,
+		RunID:   runID,
+		changed: make(chan struct{}),
+	}
+}
+
+func (l *Log) write(level log.Level, progress int, format string, v ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.done {
+		return
+	}
+	l.lines = append(l.lines, &Line{
+		Offset:   l.next,
+		Time:     time.Now(),
+		Level:    level.String(),
+		Message:  fmt.Sprintf(format, v...),
+		Progress: progress,
+	})
+	l.next++
+	if len(l.lines) > MaxLines {
+		l.lines = l.lines[len(l.lines)-MaxLines:]
+	}
+	l.notify()
+}
+
+// notify wakes up the readers waiting for new lines, the lock must be held
+func (l *Log) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Info writes an info line
+func (l *Log) Info(format string, v ...interface{}) {
+	l.write(log.INFO, -1, format, v...)
+}
+
+// Warn writes a warning line
+func (l *Log) Warn(format string, v ...interface{}) {
+	l.write(log.WARN, -1, format, v...)
+}
+
+// Error writes an error line
+func (l *Log) Error(format string, v ...interface{}) {
+	l.write(log.ERROR, -1, format, v...)
+}
+
+// Progress writes a progress marker of done items out of total
+func (l *Log) Progress(done, total int, format string, v ...interface{}) {
+	progress := 100
+	if total > 0 && done < total {
+		progress = done * 100 / total
+	}
+	l.write(log.INFO, progress, format, v...)
+}
+
+// Close marks the end of the run of the task
+func (l *Log) Close() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.done {
+		l.done = true
+		l.notify()
+	}
+}
+
+// Since returns the buffered lines starting at offset, a channel closed when the log changes
+// and whether the run of the task has ended.
+// Lines which have already left the buffer are skipped.
+func (l *Log) Since(offset int64) ([]*Line, <-chan struct{}, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lines := l.lines
+	if len(lines) > 0 && offset > lines[0].Offset {
+		skip := offset - lines[0].Offset
+		if skip > int64(len(lines)) {
+			skip = int64(len(lines))
+		}
+		lines = lines[skip:]
+	}
+	return append([]*Line(nil), lines...), l.changed, l.done
+}
+
+// Manager keeps the log of the last run of every task
+type Manager struct {
+	lock  sync.Mutex
+	logs  map[string]*Log
+	runID int64
+}
+
+var manager = &Manager{
+	logs: make(map[string]*Log),
+}
+
+// GetManager returns the task log manager
+func GetManager() *Manager {
+	return manager
+}
+
+// Start starts a new log for a run of the named task, replacing the log of its previous run
+func (m *Manager) Start(name string) *Log {
+	m.lock.Lock()
+	m.runID++
+	l := newLog(name, m.runID)
+	previous := m.logs[name]
+	m.logs[name] = l
+	m.lock.Unlock()
+
+	previous.Close()
+	return l
+}
+
+// Get returns the log of the last run of the named task or nil
+func (m *Manager) Get(name string) *Log {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.logs[name]
+}
+
+type contextKey struct{}
+
+// WithLog returns a context carrying the task log
+func WithLog(ctx context.Context, l *Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the task log of the context, which may be nil
+func FromContext(ctx context.Context) *Log {
+	l, _ := ctx.Value(contextKey{}).(*Log)
+	return l
+}
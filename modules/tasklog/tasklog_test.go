@@ -0,0 +1,57 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tasklog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	l := GetManager().Start("test")
+	assert.Equal(t, l, FromContext(WithLog(context.Background(), l)))
+
+	l.Info("started %s", "task")
+	l.Progress(1, 4, "checked %d", 1)
+	lines, changed, done := l.Since(0)
+	assert.False(t, done)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "started task", lines[0].Message)
+		assert.Equal(t, -1, lines[0].Progress)
+		assert.EqualValues(t, 1, lines[1].Offset)
+		assert.Equal(t, 25, lines[1].Progress)
+	}
+
+	l.Error("failed")
+	select {
+	case <-changed:
+	default:
+		assert.Fail(t, "changed channel not closed")
+	}
+	lines, _, _ = l.Since(2)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "error", lines[0].Level)
+	}
+
+	for i := 0; i < MaxLines; i++ {
+		l.Info("line %d", i)
+	}
+	lines, _, _ = l.Since(0)
+	assert.Len(t, lines, MaxLines)
+	assert.EqualValues(t, 3, lines[0].Offset)
+
+	next := GetManager().Start("test")
+	_, _, done = l.Since(0)
+	assert.True(t, done)
+	assert.Equal(t, next, GetManager().Get("test"))
+	assert.Greater(t, next.RunID, l.RunID)
+
+	var nilLog *Log
+	nilLog.Info("ignored")
+	nilLog.Close()
+	assert.Nil(t, FromContext(context.Background()))
+}
@@ -241,6 +241,9 @@ func NewFuncMap() []template.FuncMap {
 		"DisableWebhooks": func() bool {
 			return setting.DisableWebhooks
 		},
+		"EnablePages": func() bool {
+			return setting.Pages.Enabled
+		},
 		"DisableImportLocal": func() bool {
 			return !setting.ImportLocalPaths
 		},
@@ -940,6 +943,8 @@ func MigrationIcon(hostname string) string {
 	switch hostname {
 	case "github.com":
 		return "fa-github"
+	case "bitbucket.org":
+		return "fa-bitbucket"
 	default:
 		return "fa-git-alt"
 	}
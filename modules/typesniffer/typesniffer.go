@@ -26,6 +26,11 @@ type SniffedType struct {
 	contentType string
 }
 
+// GetMimeType returns the mime type without parameters
+func (ct SniffedType) GetMimeType() string {
+	return strings.TrimSpace(strings.SplitN(ct.contentType, ";", 2)[0])
+}
+
 // IsText etects if content format is plain text.
 func (ct SniffedType) IsText() bool {
 	return strings.Contains(ct.contentType, "text/")
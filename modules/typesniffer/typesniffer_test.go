@@ -20,6 +20,11 @@ func TestDetectContentTypeLongerThanSniffLen(t *testing.T) {
 	assert.NotEqual(t, "image/svg+xml", DetectContentType([]byte(`<!-- `+strings.Repeat("x", sniffLen)+` --><svg></svg>`)).contentType)
 }
 
+func TestGetMimeType(t *testing.T) {
+	assert.Equal(t, "text/plain", DetectContentType([]byte("lorem ipsum")).GetMimeType())
+	assert.Equal(t, "image/svg+xml", DetectContentType([]byte("<svg></svg>")).GetMimeType())
+}
+
 func TestIsTextFile(t *testing.T) {
 	assert.True(t, DetectContentType([]byte{}).IsText())
 	assert.True(t, DetectContentType([]byte("lorem ipsum")).IsText())
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a daily period of time, in minutes since midnight.
+// A window whose end is before its start spans midnight.
+type TimeWindow struct {
+	Start int
+	End   int
+}
+
+// Contains returns if the time of day of t is within the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+func (w TimeWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ParseTimeWindows parses a comma separated list of windows like "22:00-06:00, 12:00-13:00"
+func ParseTimeWindows(s string) ([]TimeWindow, error) {
+	var windows []TimeWindow
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid time window %q", part)
+		}
+		start, err := parseTimeOfDay(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of time window %q: %v", part, err)
+		}
+		end, err := parseTimeOfDay(bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of time window %q: %v", part, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("empty time window %q", part)
+		}
+		windows = append(windows, TimeWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+// InTimeWindows returns if t is within one of the windows, or if there are no windows
+func InTimeWindows(windows []TimeWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
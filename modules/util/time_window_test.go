@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeWindows(t *testing.T) {
+	windows, err := ParseTimeWindows("")
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	windows, err = ParseTimeWindows("22:00-06:00, 12:30-13:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []TimeWindow{{Start: 22 * 60, End: 6 * 60}, {Start: 12*60 + 30, End: 13 * 60}}, windows)
+	assert.Equal(t, "22:00-06:00", windows[0].String())
+
+	for _, s := range []string{"22:00", "22:00-25:00", "a-b", "10:00-10:00", "01:00-02:00-03:00"} {
+		_, err = ParseTimeWindows(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestInTimeWindows(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 6, 1, hour, minute, 0, 0, time.UTC)
+	}
+	windows := []TimeWindow{{Start: 22 * 60, End: 6 * 60}, {Start: 12*60 + 30, End: 13 * 60}}
+
+	assert.True(t, InTimeWindows(nil, at(15, 0)))
+	assert.True(t, InTimeWindows(windows, at(23, 0)))
+	assert.True(t, InTimeWindows(windows, at(0, 0)))
+	assert.True(t, InTimeWindows(windows, at(5, 59)))
+	assert.False(t, InTimeWindows(windows, at(6, 0)))
+	assert.True(t, InTimeWindows(windows, at(12, 30)))
+	assert.False(t, InTimeWindows(windows, at(13, 0)))
+	assert.False(t, InTimeWindows(windows, at(21, 59)))
+}
@@ -113,3 +113,12 @@ func IsValidExternalTrackerURLFormat(uri string) bool {
 
 	return true
 }
+
+// IsValidExternalTrackerRegexpPattern checks if the pattern matching the references to an external tracker is a non empty valid regular expression
+func IsValidExternalTrackerRegexpPattern(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	_, err := regexp.Compile(pattern)
+	return err == nil
+}
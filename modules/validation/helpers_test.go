@@ -155,3 +155,10 @@ func Test_IsValidExternalTrackerURLFormat(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsValidExternalTrackerRegexpPattern(t *testing.T) {
+	assert.True(t, IsValidExternalTrackerRegexpPattern(`JIRA-\d+`))
+	assert.True(t, IsValidExternalTrackerRegexpPattern(`(?:JIRA|BUG)-(\d+)`))
+	assert.False(t, IsValidExternalTrackerRegexpPattern(""))
+	assert.False(t, IsValidExternalTrackerRegexpPattern(`JIRA-(\d+`))
+}
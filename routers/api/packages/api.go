@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/packages/pypi"
+	"code.gitea.io/gitea/services/auth"
+
+	"gitea.com/go-chi/session"
+)
+
+// reqPackageAccess requires the given access mode to the packages of the owner,
+// anonymous users are asked to authenticate as package clients only send their credentials when challenged
+func reqPackageAccess(accessMode models.AccessMode) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.Package.AccessMode >= accessMode {
+			return
+		}
+		if ctx.IsSigned {
+			ctx.Error(http.StatusForbidden, "reqPackageAccess", "user should have a permission to access the packages")
+			return
+		}
+		ctx.Resp.Header().Set("WWW-Authenticate", `Basic realm="Gitea Package API"`)
+		ctx.Error(http.StatusUnauthorized, "reqPackageAccess", "authentication is required")
+	}
+}
+
+// reqPackageAuth applies the rules of the API to authenticated writes: passwords of users
+// enrolled in two-factor authentication need a passcode and session cookies need a CSRF token
+func reqPackageAuth() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if true == ctx.Data["IsApiToken"] {
+			return
+		}
+		if ctx.Context.IsBasicAuth {
+			ctx.CheckForOTP()
+			return
+		}
+		if ctx.IsSigned {
+			ctx.RequireCSRF()
+		}
+	}
+}
+
+// Routes registers the routes of the package registries
+func Routes() *web.Route {
+	var m = web.NewRoute()
+
+	m.Use(session.Sessioner(session.Options{
+		Provider:       setting.SessionConfig.Provider,
+		ProviderConfig: setting.SessionConfig.ProviderConfig,
+		CookieName:     setting.SessionConfig.CookieName,
+		CookiePath:     setting.SessionConfig.CookiePath,
+		Gclifetime:     setting.SessionConfig.Gclifetime,
+		Maxlifetime:    setting.SessionConfig.Maxlifetime,
+		Secure:         setting.SessionConfig.Secure,
+		SameSite:       setting.SessionConfig.SameSite,
+		Domain:         setting.SessionConfig.Domain,
+	}))
+	m.Use(context.APIContexter())
+	m.Use(context.APIAuth(auth.NewGroup(auth.Methods()...)))
+	m.Use(context.ToggleAPI(&context.ToggleOptions{
+		SignInRequired: setting.Service.RequireSignInView,
+	}))
+
+	m.Group("/{username}", func() {
+		m.Group("/pypi", func() {
+			m.Post("", reqPackageAuth(), reqPackageAccess(models.AccessModeWrite), pypi.UploadPackageFile)
+			m.Get("/files/{id}/{version}/{filename}", pypi.DownloadPackageFile)
+			m.Get("/simple", pypi.PackageIndex)
+			m.Get("/simple/{id}", pypi.PackageMetadata)
+		}, reqPackageAccess(models.AccessModeRead))
+	}, context.PackageAssignmentAPI())
+
+	return m
+}
@@ -0,0 +1,230 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/routers/common"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	tplSimpleIndex  base.TplName = "api/packages/pypi/simple"
+	tplPackageIndex base.TplName = "api/packages/pypi/package"
+	packageType                  = models.PackageTypePyPI
+)
+
+// fileLink is a link of the simple index of a package
+type fileLink struct {
+	Name           string
+	URL            string
+	HashSHA256     string
+	RequiresPython string
+}
+
+func registryURL(ctx *context.APIContext) string {
+	return fmt.Sprintf("%sapi/packages/%s/pypi", setting.AppURL, url.PathEscape(ctx.Package.Owner.Name))
+}
+
+// PackageIndex lists the packages of the owner as described by PEP 503
+func PackageIndex(ctx *context.APIContext) {
+	packages, err := models.GetPackagesByOwner(ctx.Package.Owner.ID, packageType)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackagesByOwner", err)
+		return
+	}
+
+	ctx.Data["RegistryURL"] = registryURL(ctx)
+	ctx.Data["Packages"] = packages
+	ctx.HTML(http.StatusOK, tplSimpleIndex)
+}
+
+// PackageMetadata lists the files of all versions of a package as described by PEP 503
+func PackageMetadata(ctx *context.APIContext) {
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, packageType, pypi_module.NormalizeName(ctx.Params("id")))
+	if err != nil {
+		if models.IsErrPackageNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return
+	}
+
+	files, err := models.GetPackageFiles(p.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageFiles", err)
+		return
+	}
+
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	links := make([]*fileLink, 0, len(files))
+	for _, f := range files {
+		link := &fileLink{
+			Name:       f.Name,
+			URL:        fmt.Sprintf("%s/files/%s/%s/%s", registryURL(ctx), url.PathEscape(p.Name), url.PathEscape(f.Version.Version), url.PathEscape(f.Name)),
+			HashSHA256: f.HashSHA256,
+		}
+		if f.Version.MetadataJSON != "" {
+			var metadata pypi_module.Metadata
+			if err := json.Unmarshal([]byte(f.Version.MetadataJSON), &metadata); err != nil {
+				log.Error("Unable to parse the metadata of %s %s: %v", p.Name, f.Version.Version, err)
+			}
+			link.RequiresPython = metadata.RequiresPython
+		}
+		links = append(links, link)
+	}
+
+	ctx.Data["Package"] = p
+	ctx.Data["Links"] = links
+	ctx.HTML(http.StatusOK, tplPackageIndex)
+}
+
+// DownloadPackageFile serves the content of a package file
+func DownloadPackageFile(ctx *context.APIContext) {
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, packageType, pypi_module.NormalizeName(ctx.Params("id")))
+	if err != nil {
+		if models.IsErrPackageNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return
+	}
+
+	pf, err := models.GetPackageFile(p.ID, ctx.Params("version"), ctx.Params("filename"))
+	if err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageFile", err)
+		}
+		return
+	}
+
+	if setting.Packages.ServeDirect {
+		// If we have a signed url (S3, object storage), redirect to this directly.
+		u, err := storage.Packages.URL(pf.RelativePath(), pf.Name)
+		if u != nil && err == nil {
+			ctx.Redirect(u.String())
+			return
+		}
+	}
+
+	fr, err := storage.Packages.Open(pf.RelativePath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Open", err)
+		return
+	}
+	defer fr.Close()
+
+	if err = common.ServeData(ctx.Context, pf.Name, pf.Size, fr); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ServeData", err)
+	}
+}
+
+// UploadPackageFile adds a distribution uploaded with the legacy PyPI upload API used by twine
+func UploadPackageFile(ctx *context.APIContext) {
+	file, header, err := ctx.Req.FormFile("content")
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "FormFile", err)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > setting.Packages.MaxSize<<20 {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Sprintf("the file must not be larger than %d MB", setting.Packages.MaxSize))
+		return
+	}
+
+	name := ctx.Req.FormValue("name")
+	version := ctx.Req.FormValue("version")
+	switch {
+	case !pypi_module.IsValidName(name):
+		ctx.Error(http.StatusBadRequest, "", "invalid package name")
+		return
+	case !pypi_module.IsValidVersion(version):
+		ctx.Error(http.StatusBadRequest, "", "invalid package version")
+		return
+	case !pypi_module.IsValidFilename(header.Filename):
+		ctx.Error(http.StatusBadRequest, "", "invalid distribution file name")
+		return
+	}
+
+	h := sha256.New()
+	if _, err = io.Copy(h, file); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Copy", err)
+		return
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	if digest := ctx.Req.FormValue("sha256_digest"); digest != "" && !strings.EqualFold(digest, hash) {
+		ctx.Error(http.StatusBadRequest, "", "the sha256 digest does not match the file")
+		return
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Seek", err)
+		return
+	}
+
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	metadata, err := json.Marshal(&pypi_module.Metadata{
+		Summary:        ctx.Req.FormValue("summary"),
+		Description:    ctx.Req.FormValue("description"),
+		Author:         ctx.Req.FormValue("author"),
+		AuthorEmail:    ctx.Req.FormValue("author_email"),
+		License:        ctx.Req.FormValue("license"),
+		HomePage:       ctx.Req.FormValue("home_page"),
+		Keywords:       ctx.Req.FormValue("keywords"),
+		RequiresPython: ctx.Req.FormValue("requires_python"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Marshal", err)
+		return
+	}
+
+	pf := &models.PackageFile{
+		Name:       header.Filename,
+		Size:       header.Size,
+		HashSHA256: hash,
+	}
+	if _, err = storage.Packages.Save(pf.RelativePath(), file, header.Size); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Save", err)
+		return
+	}
+
+	if err = models.AddPackageFile(&models.AddPackageFileOptions{
+		Owner:        ctx.Package.Owner,
+		Creator:      ctx.User,
+		Type:         packageType,
+		Name:         pypi_module.NormalizeName(name),
+		Version:      version,
+		MetadataJSON: string(metadata),
+		File:         pf,
+	}); err != nil {
+		if models.IsErrPackageFileAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", "the file already exists")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddPackageFile", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
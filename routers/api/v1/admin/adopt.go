@@ -11,7 +11,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -176,3 +178,45 @@ func DeleteUnadoptedRepository(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// AdoptOrDeleteUnadoptedRepositories adopts or deletes unadopted repositories in bulk
+func AdoptOrDeleteUnadoptedRepositories(ctx *context.APIContext) {
+	// swagger:operation POST /admin/unadopted admin adminAdoptOrDeleteUnadoptedRepositories
+	// ---
+	// summary: Adopt or delete unadopted repositories in bulk
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UnadoptedRepositoriesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UnadoptedRepositoryResultList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.UnadoptedRepositoriesOption)
+
+	var errs map[string]error
+	if form.Action == "adopt" {
+		errs = repository.AdoptUnadoptedRepositories(ctx.User, form.Repositories)
+	} else {
+		errs = repository.DeleteUnadoptedRepositories(ctx.User, form.Repositories)
+	}
+
+	results := make([]*api.UnadoptedRepositoryResult, 0, len(form.Repositories))
+	for _, dir := range form.Repositories {
+		result := &api.UnadoptedRepositoryResult{Repository: dir, Success: true}
+		if err, ok := errs[dir]; ok {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	ctx.JSON(http.StatusOK, results)
+}
@@ -43,19 +43,42 @@ func ListCronTasks(ctx *context.APIContext) {
 		tasks = tasks[start:end]
 	}
 
-	res := make([]structs.Cron, len(tasks))
+	res := make([]*structs.Cron, len(tasks))
 	for i, task := range tasks {
-		res[i] = structs.Cron{
-			Name:      task.Name,
-			Schedule:  task.Spec,
-			Next:      task.Next,
-			Prev:      task.Prev,
-			ExecTimes: task.ExecTimes,
-		}
+		res[i] = toAPICron(task)
 	}
 	ctx.JSON(http.StatusOK, res)
 }
 
+// GetCronTask api for getting a cron task
+func GetCronTask(ctx *context.APIContext) {
+	// swagger:operation GET /admin/cron/{task} admin adminCronGet
+	// ---
+	// summary: Get a cron task
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: task
+	//   in: path
+	//   description: task to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Cron"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	for _, task := range cron.ListTasks() {
+		if task.Name == ctx.Params(":task") {
+			ctx.JSON(http.StatusOK, toAPICron(task))
+			return
+		}
+	}
+	ctx.NotFound()
+}
+
 // PostCronTask api for getting cron tasks
 func PostCronTask(ctx *context.APIContext) {
 	// swagger:operation POST /admin/cron/{task} admin adminCronRun
@@ -84,3 +107,22 @@ func PostCronTask(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+func toAPICron(task *cron.TaskTableRow) *structs.Cron {
+	apiCron := &structs.Cron{
+		Name:         task.Name,
+		Schedule:     task.Spec,
+		Next:         task.Next,
+		Prev:         task.Prev,
+		ExecTimes:    task.ExecTimes,
+		Enabled:      task.Enabled,
+		Running:      task.Running,
+		LastDuration: task.LastDuration.Milliseconds(),
+		LastStatus:   string(task.LastStatus),
+		LastMessage:  task.LastMessage,
+	}
+	if !task.LastRun.IsZero() {
+		apiCron.LastRun = &task.LastRun
+	}
+	return apiCron
+}
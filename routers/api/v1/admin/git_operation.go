@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListGitOperations list the git fetches and pushes of all repositories
+func ListGitOperations(ctx *context.APIContext) {
+	// swagger:operation GET /admin/git_operations admin adminListGitOperations
+	// ---
+	// summary: List the git fetches and pushes of all repositories, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: user
+	//   in: query
+	//   description: login of the user who did the operations
+	//   type: string
+	// - name: ip
+	//   in: query
+	//   description: remote IP address the operations came from
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: type of the operations
+	//   type: string
+	//   enum: [fetch, push, archive]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitOperationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListGitOperations(ctx, 0)
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListExpiringPublicKeys list the user SSH keys which expire soon
+func ListExpiringPublicKeys(ctx *context.APIContext) {
+	// swagger:operation GET /admin/keys/expiring admin adminListExpiringPublicKeys
+	// ---
+	// summary: List the SSH keys of all users which expire within the given number of days, soonest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: days
+	//   in: query
+	//   description: number of days from now to look ahead, defaults to 30
+	//   type: integer
+	// - name: expired
+	//   in: query
+	//   description: also include keys which have already expired
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PublicKeyList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	days := 30
+	if ctx.Query("days") != "" {
+		days = ctx.QueryInt("days")
+		if days <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "days must be a positive number")
+			return
+		}
+	}
+
+	now := time.Now()
+	after := timeutil.TimeStamp(now.Unix())
+	if ctx.QueryBool("expired") {
+		after = 0
+	}
+	before := timeutil.TimeStamp(now.AddDate(0, 0, days).Unix())
+
+	keys, err := models.ListExpiringPublicKeys(after, before, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListExpiringPublicKeys", err)
+		return
+	}
+
+	apiLink := setting.AppURL + "api/v1/user/keys/"
+	owners := make(map[int64]*models.User)
+	apiKeys := make([]*api.PublicKey, len(keys))
+	for i, key := range keys {
+		owner, ok := owners[key.OwnerID]
+		if !ok {
+			owner, err = models.GetUserByID(key.OwnerID)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+				return
+			}
+			owners[key.OwnerID] = owner
+		}
+
+		apiKeys[i] = convert.ToPublicKey(apiLink, key)
+		apiKeys[i].KeyType = "user"
+		apiKeys[i].Owner = convert.ToUser(owner, ctx.User)
+		apiKeys[i].ReadOnly = key.Mode == models.AccessModeRead
+		apiKeys[i].CreatedFrom = key.CreatedFrom.String()
+	}
+
+	ctx.JSON(http.StatusOK, &apiKeys)
+}
@@ -233,6 +233,9 @@ func EditUser(ctx *context.APIContext) {
 	if form.MaxRepoCreation != nil {
 		u.MaxRepoCreation = *form.MaxRepoCreation
 	}
+	if form.SizeQuota != nil {
+		u.SizeQuota = *form.SizeQuota
+	}
 	if form.AllowCreateOrganization != nil {
 		u.AllowCreateOrganization = *form.AllowCreateOrganization
 	}
@@ -81,6 +81,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/org"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/settings"
+	"code.gitea.io/gitea/routers/api/v1/snippet"
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/services/auth"
@@ -118,10 +119,54 @@ func sudo() func(ctx *context.APIContext) {
 				return
 			}
 		}
+
+		viewAs := ctx.Query("view_as")
+		if len(viewAs) == 0 {
+			viewAs = ctx.Req.Header.Get("View-As")
+		}
+
+		if len(viewAs) > 0 {
+			if !ctx.IsSigned || !ctx.User.IsAdmin {
+				ctx.JSON(http.StatusForbidden, map[string]string{
+					"message": "Only administrators allowed to view as other users.",
+				})
+				return
+			}
+			user, err := models.GetUserByName(viewAs)
+			if err != nil {
+				if models.IsErrUserNotExist(err) {
+					ctx.NotFound()
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+				}
+				return
+			}
+			// the web view as mode is recorded when it starts, a request of the API is all its view as mode
+			if err := models.CreateNotice(models.NoticeViewAs, "Admin %s requested %s %s through the API as %s", ctx.User.Name, ctx.Req.Method, ctx.Req.URL.Path, user.Name); err != nil {
+				ctx.Error(http.StatusInternalServerError, "CreateNotice", err)
+				return
+			}
+			if !ctx.ViewAs(user) {
+				ctx.JSON(http.StatusForbidden, map[string]string{
+					"message": "Only read-only requests are allowed while viewing as another user.",
+				})
+				return
+			}
+		}
 	}
 }
 
 func repoAssignment() func(ctx *context.APIContext) {
+	return assignRepository(false)
+}
+
+// transferRepoAssignment is repoAssignment letting the users who can accept the pending transfer of the repository through,
+// as the recipient of the transfer of a private repository has no access to it
+func transferRepoAssignment() func(ctx *context.APIContext) {
+	return assignRepository(true)
+}
+
+func assignRepository(allowTransferRecipient bool) func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
 		userName := ctx.Params("username")
 		repoName := ctx.Params("reponame")
@@ -180,13 +225,27 @@ func repoAssignment() func(ctx *context.APIContext) {
 			return
 		}
 
-		if !ctx.Repo.HasAccess() {
+		if !ctx.Repo.HasAccess() && !(allowTransferRecipient && canAcceptPendingTransfer(ctx)) {
 			ctx.NotFound()
 			return
 		}
 	}
 }
 
+func canAcceptPendingTransfer(ctx *context.APIContext) bool {
+	if !ctx.IsSigned || ctx.Repo.Repository.Status != models.RepositoryPendingTransfer {
+		return false
+	}
+	repoTransfer, err := models.GetPendingRepositoryTransfer(ctx.Repo.Repository)
+	if err != nil {
+		if !models.IsErrNoPendingTransfer(err) {
+			log.Error("GetPendingRepositoryTransfer: %v", err)
+		}
+		return false
+	}
+	return repoTransfer.CanUserAcceptTransfer(ctx.User)
+}
+
 // Contexter middleware already checks token for user sign in process.
 func reqToken() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -299,6 +358,10 @@ func reqOrgOwnership() func(ctx *context.APIContext) {
 			ctx.Error(http.StatusInternalServerError, "", "reqOrgOwnership: unprepared context")
 			return
 		}
+		// an organization authenticated with one of its access tokens administrates itself
+		if ctx.User.ID == orgID {
+			return
+		}
 
 		isOwner, err := models.IsOrganizationOwner(orgID, ctx.User.ID)
 		if err != nil {
@@ -327,6 +390,9 @@ func reqTeamMembership() func(ctx *context.APIContext) {
 		}
 
 		var orgID = ctx.Org.Team.OrgID
+		if ctx.User.ID == orgID {
+			return
+		}
 		isOwner, err := models.IsOrganizationOwner(orgID, ctx.User.ID)
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, "IsOrganizationOwner", err)
@@ -368,6 +434,9 @@ func reqOrgMembership() func(ctx *context.APIContext) {
 			ctx.Error(http.StatusInternalServerError, "", "reqOrgMembership: unprepared context")
 			return
 		}
+		if ctx.User.ID == orgID {
+			return
+		}
 
 		if isMember, err := models.IsOrganizationMember(orgID, ctx.User.ID); err != nil {
 			ctx.Error(http.StatusInternalServerError, "IsOrganizationMember", err)
@@ -402,6 +471,16 @@ func reqWebhooksEnabled() func(ctx *context.APIContext) {
 	}
 }
 
+// reqLFSServer requires the LFS server to be enabled
+func reqLFSServer() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !setting.LFS.StartServer {
+			ctx.NotFound()
+			return
+		}
+	}
+}
+
 func orgAssignment(args ...bool) func(ctx *context.APIContext) {
 	var (
 		assignOrg  bool
@@ -522,6 +601,13 @@ func mustEnableIssuesOrPulls(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableAccessRequests(ctx *context.APIContext) {
+	if !setting.Repository.EnableAccessRequests {
+		ctx.NotFound()
+		return
+	}
+}
+
 func mustNotBeArchived(ctx *context.APIContext) {
 	if ctx.Repo.Repository.IsArchived {
 		ctx.NotFound()
@@ -607,9 +693,24 @@ func Routes() *web.Route {
 			m.Get("/new", notify.NewAvailable)
 			m.Combo("/threads/{id}").
 				Get(notify.GetThread).
-				Patch(notify.ReadThread)
+				Patch(notify.ReadThread).
+				Put(notify.MarkThreadRead)
 		}, reqToken())
 
+		// Snippets
+		m.Group("/snippets", func() {
+			m.Combo("").Get(snippet.ListSnippets).
+				Post(reqToken(), bind(api.CreateSnippetOption{}), snippet.CreateSnippet)
+			m.Group("/{uuid}", func() {
+				m.Combo("").Get(snippet.GetSnippet).
+					Patch(reqToken(), bind(api.EditSnippetOption{}), snippet.EditSnippet).
+					Delete(reqToken(), snippet.DeleteSnippet)
+				m.Combo("/comments").Get(snippet.ListComments).
+					Post(reqToken(), bind(api.CreateSnippetCommentOption{}), snippet.CreateComment)
+				m.Delete("/comments/{id}", reqToken(), snippet.DeleteComment)
+			})
+		}, reqExploreSignIn())
+
 		// Users
 		m.Group("/users", func() {
 			m.Get("/search", reqExploreSignIn(), user.Search)
@@ -622,6 +723,7 @@ func Routes() *web.Route {
 				}
 
 				m.Get("/repos", reqExploreSignIn(), user.ListUserRepos)
+				m.Get("/snippets", reqExploreSignIn(), snippet.ListUserSnippets)
 				m.Group("/tokens", func() {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
@@ -642,6 +744,10 @@ func Routes() *web.Route {
 				})
 
 				m.Get("/starred", user.GetStarredRepos)
+				m.Group("/starlists", func() {
+					m.Get("", user.ListStarLists)
+					m.Get("/{id}/repos", user.ListStarListRepos)
+				})
 
 				m.Get("/subscriptions", user.GetWatchedRepos)
 			})
@@ -653,6 +759,8 @@ func Routes() *web.Route {
 				m.Get("", user.GetUserSettings)
 				m.Patch("", bind(api.UserSettingsOptions{}), user.UpdateUserSettings)
 			}, reqToken())
+			m.Get("/quota", reqToken(), user.GetSizeQuota)
+			m.Get("/access_requests", reqToken(), mustEnableAccessRequests, user.ListMyAccessRequests)
 			m.Combo("/emails").Get(user.ListEmails).
 				Post(bind(api.CreateEmailOption{}), user.AddEmail).
 				Delete(bind(api.DeleteEmailOption{}), user.DeleteEmail)
@@ -686,6 +794,14 @@ func Routes() *web.Route {
 					Delete(user.DeleteGPGKey)
 			})
 
+			m.Group("/hooks", func() {
+				m.Combo("").Get(user.ListHooks).
+					Post(bind(api.CreateHookOption{}), user.CreateHook)
+				m.Combo("/{id}").Get(user.GetHook).
+					Patch(bind(api.EditHookOption{}), user.EditHook).
+					Delete(user.DeleteHook)
+			}, reqToken(), reqWebhooksEnabled())
+
 			m.Get("/gpg_key_token", user.GetVerificationToken)
 			m.Post("/gpg_key_verify", bind(api.VerifyGPGKeyOption{}), user.VerifyUserGPGKey)
 
@@ -700,6 +816,18 @@ func Routes() *web.Route {
 					m.Delete("", user.Unstar)
 				}, repoAssignment())
 			})
+			m.Group("/starlists", func() {
+				m.Combo("").Get(user.ListMyStarLists).
+					Post(bind(api.CreateStarListOption{}), user.CreateStarList)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(user.GetMyStarList).
+						Patch(bind(api.EditStarListOption{}), user.EditStarList).
+						Delete(user.DeleteStarList)
+					m.Combo("/repos/{username}/{reponame}", repoAssignment()).
+						Put(user.AddStarListRepo).
+						Delete(user.RemoveStarListRepo)
+				})
+			})
 			m.Get("/times", repo.ListMyTrackedTimes)
 
 			m.Get("/stopwatches", repo.GetStopwatches)
@@ -718,15 +846,35 @@ func Routes() *web.Route {
 			m.Get("/search", repo.Search)
 
 			m.Get("/issues/search", repo.SearchIssues)
+			m.Get("/issues/good_first", repo.ListGoodFirstIssues)
 
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
 
+			// access is requested to repositories the user has no access to
+			m.Post("/{username}/{reponame}/access_requests", reqToken(), mustEnableAccessRequests, bind(api.CreateRepoAccessRequestOption{}), repo.CreateAccessRequest)
+			// the recipient of the transfer of a private repository has no access to it
+			m.Group("/{username}/{reponame}/transfer", func() {
+				m.Post("/accept", repo.AcceptTransfer)
+				m.Post("/reject", repo.RejectTransfer)
+			}, reqToken(), transferRepoAssignment())
+
 			m.Group("/{username}/{reponame}", func() {
 				m.Combo("").Get(reqAnyRepoReader(), repo.Get).
 					Delete(reqToken(), reqOwner(), repo.Delete).
 					Patch(reqToken(), reqAdmin(), bind(api.EditRepoOption{}), repo.Edit)
 				m.Post("/generate", reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.GenerateRepoOption{}), repo.Generate)
-				m.Post("/transfer", reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer)
+				m.Combo("/avatar", reqToken(), reqAdmin()).Post(bind(api.UpdateRepoAvatarOption{}), repo.UpdateAvatar).
+					Delete(repo.DeleteAvatar)
+				m.Combo("/transfer").Post(reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer).
+					Delete(reqToken(), reqOwner(), repo.CancelTransfer)
+				m.Group("/access_requests", func() {
+					m.Get("", repo.ListAccessRequests)
+					m.Group("/{id}", func() {
+						m.Get("", repo.GetAccessRequest)
+						m.Post("/approve", bind(api.ApproveRepoAccessRequestOption{}), repo.ApproveAccessRequest)
+						m.Post("/reject", repo.RejectAccessRequest)
+					})
+				}, reqToken(), reqAdmin(), mustEnableAccessRequests)
 				m.Combo("/notifications").
 					Get(reqToken(), notify.ListRepoNotifications).
 					Put(reqToken(), notify.ReadRepoNotifications)
@@ -746,8 +894,19 @@ func Routes() *web.Route {
 							Patch(bind(api.EditHookOption{}), repo.EditHook).
 							Delete(repo.DeleteHook)
 						m.Post("/tests", context.RepoRefForAPI, repo.TestHook)
+						m.Group("/deliveries", func() {
+							m.Get("", repo.ListHookDeliveries)
+							m.Get("/{delivery_id}", repo.GetHookDelivery)
+							m.Post("/{delivery_id}/redeliver", repo.RedeliverHookDelivery)
+						})
+					})
+					m.Group("/events", func() {
+						m.Get("", repo.ListRepoEvents)
+						m.Get("/{event_id}", repo.GetRepoEvent)
+						m.Post("/{event_id}/replay", bind(api.ReplayRepoEventOption{}), repo.ReplayRepoEvent)
 					})
 				}, reqToken(), reqAdmin(), reqWebhooksEnabled())
+				m.Get("/git_operations", reqToken(), reqAdmin(), repo.ListGitOperations)
 				m.Group("/collaborators", func() {
 					m.Get("", reqAnyRepoReader(), repo.ListCollaborators)
 					m.Combo("/{collaborator}").Get(reqAnyRepoReader(), repo.IsCollaborator).
@@ -763,14 +922,20 @@ func Routes() *web.Route {
 						Delete(reqAdmin(), repo.DeleteTeam)
 				}, reqToken())
 				m.Get("/raw/*", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetRawFile)
+				m.Get("/blame/*", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetBlame)
 				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), repo.CompareDiff)
+				m.Post("/compare_remote", reqToken(), reqRepoWriter(models.UnitTypeCode), bind(api.CompareRemoteOption{}), repo.CompareRemote)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Get("/forks/network", reqRepoReader(models.UnitTypeCode), repo.GetForkNetwork)
+				m.Post("/detach", reqToken(), reqOwner(), repo.DetachFork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
 					m.Delete("/*", context.ReferencesGitRepo(false), reqRepoWriter(models.UnitTypeCode), repo.DeleteBranch)
 					m.Post("", reqRepoWriter(models.UnitTypeCode), bind(api.CreateBranchRepoOption{}), repo.CreateBranch)
+					m.Post("/rename", reqToken(), reqAdmin(), context.ReferencesGitRepo(false), bind(api.RenameBranchRepoOption{}), repo.RenameBranch)
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/branch_protections", func() {
 					m.Get("", repo.ListBranchProtections)
@@ -781,6 +946,20 @@ func Routes() *web.Route {
 						m.Delete("", repo.DeleteBranchProtection)
 					})
 				}, reqToken(), reqAdmin())
+				m.Group("/tag_protections", func() {
+					m.Get("", repo.ListTagProtection)
+					m.Post("", bind(api.CreateTagProtectionOption{}), repo.CreateTagProtection)
+					m.Group("/{id}", func() {
+						m.Get("", repo.GetTagProtection)
+						m.Patch("", bind(api.EditTagProtectionOption{}), repo.EditTagProtection)
+						m.Delete("", repo.DeleteTagProtection)
+					})
+				}, reqToken(), reqAdmin())
+				m.Group("/lfs/locks", func() {
+					m.Get("", repo.ListLFSLocks)
+					m.Post("/unlock", bind(api.UnlockRepoLFSLocksOption{}), repo.UnlockLFSLocks)
+					m.Delete("/{id}", repo.UnlockLFSLock)
+				}, reqToken(), reqAdmin(), reqLFSServer())
 				m.Group("/tags", func() {
 					m.Get("", repo.ListTags)
 					m.Get("/*", repo.GetTag)
@@ -800,6 +979,7 @@ func Routes() *web.Route {
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Get("/votes", repo.ListTopVotedIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/{id}", func() {
@@ -836,7 +1016,20 @@ func Routes() *web.Route {
 								Delete(repo.ResetIssueTime)
 							m.Delete("/{id}", repo.DeleteTime)
 						}, reqToken())
+						m.Group("/assets", func() {
+							m.Combo("").Get(repo.ListIssueAttachments).
+								Post(reqToken(), mustNotBeArchived, repo.CreateIssueAttachment)
+							m.Combo("/{asset}").Get(repo.GetIssueAttachment).
+								Delete(reqToken(), mustNotBeArchived, repo.DeleteIssueAttachment)
+							m.Group("/uploads", func() {
+								m.Post("", bind(api.CreateAttachmentUploadOption{}), repo.CreateIssueAttachmentUpload)
+								m.Combo("/{uuid}").Get(repo.GetIssueAttachmentUpload).
+									Put(repo.UploadIssueAttachmentChunk).
+									Delete(repo.DeleteIssueAttachmentUpload)
+							}, reqToken(), mustNotBeArchived)
+						})
 						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Get("/references", repo.GetIssueReferenceGraph)
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
@@ -861,7 +1054,7 @@ func Routes() *web.Route {
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteLabel)
 				})
-				m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
+				m.Post("/markdown", bind(api.MarkdownOption{}), misc.RepoMarkdown)
 				m.Post("/markdown/raw", misc.MarkdownRaw)
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
@@ -870,6 +1063,25 @@ func Routes() *web.Route {
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
 				})
+				m.Group("/projects", func() {
+					m.Combo("").Get(repo.ListProjects).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.CreateProjectOption{}), repo.CreateProject)
+					m.Group("/{id}", func() {
+						m.Combo("").Get(repo.GetProject).
+							Patch(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.EditProjectOption{}), repo.EditProject).
+							Delete(reqToken(), reqRepoWriter(models.UnitTypeProjects), repo.DeleteProject)
+						m.Group("/boards", func() {
+							m.Combo("").Get(repo.ListProjectBoards).
+								Post(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.CreateProjectBoardOption{}), repo.CreateProjectBoard)
+							m.Group("/{board_id}", func() {
+								m.Combo("").Patch(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.EditProjectBoardOption{}), repo.EditProjectBoard).
+									Delete(reqToken(), reqRepoWriter(models.UnitTypeProjects), repo.DeleteProjectBoard)
+								m.Combo("/issues").Get(repo.ListProjectBoardIssues).
+									Post(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.MoveProjectIssueOption{}), repo.MoveProjectIssue)
+							})
+						})
+					})
+				}, reqRepoReader(models.UnitTypeProjects))
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
@@ -910,8 +1122,11 @@ func Routes() *web.Route {
 						m.Get(".patch", repo.DownloadPullPatch)
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
+						m.Get("/audit", repo.GetPullRequestAudit)
+						m.Get("/cla", repo.GetPullRequestCLAStatus)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
+							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest).
+							Delete(reqToken(), mustNotBeArchived, repo.CancelScheduledAutoMerge)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -927,11 +1142,18 @@ func Routes() *web.Route {
 								m.Post("/undismissals", reqToken(), repo.UnDismissPullReview)
 							})
 						})
+						m.Get("/review_checklist", repo.GetPullReviewChecklist)
 						m.Combo("/requested_reviewers").
 							Delete(reqToken(), bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(reqToken(), bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
 					})
 				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				m.Group("/deployments", func() {
+					m.Combo("").Get(repo.ListDeployments).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeCode), bind(api.CreateDeploymentOption{}), repo.CreateDeployment)
+					m.Combo("/{id}").Get(repo.GetDeployment).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeCode), repo.DeleteDeployment)
+				}, mustAllowPulls, reqRepoReader(models.UnitTypePullRequests))
 				m.Group("/statuses", func() {
 					m.Combo("/{sha}").Get(repo.GetCommitStatuses).
 						Post(reqToken(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
@@ -972,7 +1194,16 @@ func Routes() *web.Route {
 					}, reqAdmin())
 				}, reqAnyRepoReader())
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
+				m.Get("/pull_request_template", context.ReferencesGitRepo(false), repo.GetPullRequestTemplate)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
+				m.Group("/stats", func() {
+					m.Get("/contributors", repo.GetContributorStats)
+					m.Get("/commit_activity", repo.GetCommitActivity)
+					m.Get("/code_frequency", repo.GetCodeFrequency)
+					m.Get("/directories", repo.GetDirectoryStats)
+				}, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				m.Get("/workload", mustEnableIssuesOrPulls, repo.GetWorkload)
+				m.Get("/stats/reviewers", reqRepoReader(models.UnitTypePullRequests), repo.GetReviewerStats)
 			}, repoAssignment())
 		})
 
@@ -987,6 +1218,14 @@ func Routes() *web.Route {
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Get("/workload", org.GetWorkload)
+			m.Get("/stats/reviewers", org.GetReviewerStats)
+			m.Get("/mirrors", org.ListMirrors)
+			m.Get("/quota", reqToken(), reqOrgMembership(), org.GetSizeQuota)
+			m.Group("/cla", func() {
+				m.Get("", org.GetCLA)
+				m.Get("/signatures/{username}", org.GetCLASignatureStatus)
+			})
 			m.Group("/members", func() {
 				m.Get("", org.ListMembers)
 				m.Combo("/{username}").Get(org.IsMember).
@@ -1017,6 +1256,11 @@ func Routes() *web.Route {
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
 			}, reqToken(), reqOrgOwnership(), reqWebhooksEnabled())
+			m.Group("/tokens", func() {
+				m.Combo("").Get(org.ListAccessTokens).
+					Post(bind(api.CreateOrgAccessTokenOption{}), org.CreateAccessToken)
+				m.Delete("/{id}", org.DeleteAccessToken)
+			}, reqToken(), reqOrgOwnership())
 		}, orgAssignment(true))
 		m.Group("/teams/{teamid}", func() {
 			m.Combo("").Get(org.GetTeam).
@@ -1040,8 +1284,11 @@ func Routes() *web.Route {
 		m.Group("/admin", func() {
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
-				m.Post("/{task}", admin.PostCronTask)
+				m.Combo("/{task}").Get(admin.GetCronTask).
+					Post(admin.PostCronTask)
 			})
+			m.Get("/git_operations", admin.ListGitOperations)
+			m.Get("/keys/expiring", admin.ListExpiringPublicKeys)
 			m.Get("/orgs", admin.GetAllOrgs)
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
@@ -1059,7 +1306,8 @@ func Routes() *web.Route {
 				})
 			})
 			m.Group("/unadopted", func() {
-				m.Get("", admin.ListUnadoptedRepositories)
+				m.Combo("").Get(admin.ListUnadoptedRepositories).
+					Post(bind(api.UnadoptedRepositoriesOption{}), admin.AdoptOrDeleteUnadoptedRepositories)
 				m.Post("/{username}/{reponame}", admin.AdoptRepository)
 				m.Delete("/{username}/{reponame}", admin.DeleteUnadoptedRepository)
 			})
@@ -39,8 +39,50 @@ func Markdown(ctx *context.APIContext) {
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 
+	renderMarkdown(ctx, web.GetForm(ctx).(*api.MarkdownOption))
+}
+
+// RepoMarkdown render markdown document to HTML in the context of a repository
+func RepoMarkdown(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/markdown repository repoRenderMarkdown
+	// ---
+	// summary: Render a markdown document as HTML in the context of a repository
+	// description: Issue references and commit SHAs are resolved against the repository, as are relative links unless a context is given.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MarkdownOption"
+	// consumes:
+	// - application/json
+	// produces:
+	//     - text/html
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MarkdownRender"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
 	form := web.GetForm(ctx).(*api.MarkdownOption)
+	if len(form.Context) == 0 {
+		form.Context = ctx.Repo.Repository.HTMLURL()
+	}
+	renderMarkdown(ctx, form)
+}
 
+func renderMarkdown(ctx *context.APIContext, form *api.MarkdownOption) {
 	if ctx.HasAPIError() {
 		ctx.Error(http.StatusUnprocessableEntity, "", ctx.GetErrMsg())
 		return
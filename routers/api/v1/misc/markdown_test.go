@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -178,3 +179,34 @@ func TestAPI_RenderRaw(t *testing.T) {
 		resp.Body.Reset()
 	}
 }
+
+func TestAPI_RenderRepoMarkdown(t *testing.T) {
+	setting.AppURL = AppURL
+
+	requrl, _ := url.Parse(util.URLJoin(AppURL, "api", "v1", "repos", Repo, "markdown"))
+	req := &http.Request{
+		Method: "POST",
+		URL:    requrl,
+	}
+	m, resp := createContext(req)
+	m.Repo = &context.Repository{
+		Repository: &models.Repository{
+			OwnerName: "gogits",
+			Name:      "gogs",
+			RenderingMetas: map[string]string{
+				"user": "gogits",
+				"repo": "gogs",
+				"mode": "comment",
+			},
+		},
+	}
+	ctx := wrap(m)
+
+	web.SetForm(ctx, &api.MarkdownOption{
+		Mode: "comment",
+		Text: "See #12 and [the guide](docs/guide.md)",
+	})
+	RepoMarkdown(ctx)
+	assert.Equal(t, `<p>See <a href="`+AppSubURL+`issues/12" class="ref-issue" rel="nofollow">#12</a> and <a href="`+AppSubURL+`docs/guide.md" rel="nofollow">the guide</a></p>
+`, resp.Body.String())
+}
@@ -95,6 +95,38 @@ func ReadThread(ctx *context.APIContext) {
 	ctx.Status(http.StatusResetContent)
 }
 
+// MarkThreadRead mark notification as read by ID, clients which can not send PATCH requests use it
+func MarkThreadRead(ctx *context.APIContext) {
+	// swagger:operation PUT /notifications/threads/{id} notification notifyMarkThreadRead
+	// ---
+	// summary: Mark notification thread as read by ID
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of notification thread
+	//   type: string
+	//   required: true
+	// - name: to-status
+	//   in: query
+	//   description: Status to mark notifications as
+	//   type: string
+	//   default: read
+	//   required: false
+	// responses:
+	//   "205":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ReadThread(ctx)
+}
+
 func getThread(ctx *context.APIContext) *models.Notification {
 	n, err := models.GetNotificationByID(ctx.ParamsInt64(":id"))
 	if err != nil {
@@ -0,0 +1,113 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/user"
+)
+
+// getCurrentCLA returns the current contributor license agreement of the organization of the context,
+// it writes the error to the context and returns nil if there is none
+func getCurrentCLA(ctx *context.APIContext) *models.CLA {
+	if !models.HasOrgOrUserVisible(ctx.Org.Organization, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return nil
+	}
+
+	cla, err := models.GetCurrentCLA(ctx.Org.Organization.ID)
+	if err != nil {
+		if models.IsErrCLANotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCurrentCLA", err)
+		}
+		return nil
+	}
+	return cla
+}
+
+// GetCLA returns the current contributor license agreement of an organization
+func GetCLA(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/cla organization orgGetCLA
+	// ---
+	// summary: Get the current version of the contributor license agreement of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CLA"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	cla := getCurrentCLA(ctx)
+	if cla == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToCLA(cla, ctx.Org.Organization))
+}
+
+// GetCLASignatureStatus returns whether a user has signed the current contributor license agreement of an organization
+func GetCLASignatureStatus(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/cla/signatures/{username} organization orgGetCLASignatureStatus
+	// ---
+	// summary: Check whether a user has signed the current version of the contributor license agreement of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CLASignatureStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	cla := getCurrentCLA(ctx)
+	if cla == nil {
+		return
+	}
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	signature, err := models.GetLatestCLASignature(ctx.Org.Organization.ID, u.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLatestCLASignature", err)
+		return
+	}
+
+	status := &api.CLASignatureStatus{
+		User:    convert.ToUser(u, ctx.User),
+		Version: cla.Version,
+	}
+	if signature != nil {
+		signedAt := signature.SignedUnix.AsTime()
+		status.Signed = signature.Version == cla.Version
+		status.SignedVersion = signature.Version
+		status.SignedAt = &signedAt
+	}
+	ctx.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMirrors list the mirrors of an organization with their synchronization status
+func ListMirrors(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/mirrors organization orgListMirrors
+	// ---
+	// summary: List an organization's mirrors with their last synchronization and next scheduled run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: failing
+	//   in: query
+	//   description: if true only mirrors whose last synchronization failed are listed, if false only the others
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !models.HasOrgOrUserVisible(ctx.Org.Organization, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	opts := utils.GetListOptions(ctx)
+	repos, count, err := models.SearchRepository(&models.SearchRepoOptions{
+		ListOptions:   opts,
+		Actor:         ctx.User,
+		OwnerID:       ctx.Org.Organization.ID,
+		Private:       ctx.IsSigned,
+		Mirror:        util.OptionalBoolTrue,
+		MirrorFailing: ctx.QueryOptionalBool("failing"),
+		OrderBy:       models.SearchOrderByAlphabetically,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepository", err)
+		return
+	}
+
+	apiRepos := make([]*api.Repository, 0, len(repos))
+	for i := range repos {
+		access, err := models.AccessLevel(ctx.User, repos[i])
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos = append(apiRepos, convert.ToRepo(repos[i], access))
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
@@ -255,8 +255,22 @@ func Edit(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Organization"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	form := web.GetForm(ctx).(*api.EditOrgOption)
 	org := ctx.Org.Organization
+	if form.RequireTwoFactor != nil && *form.RequireTwoFactor && !org.RequireTwoFactor {
+		// Owners can't lock themselves out of the organization
+		has, err := models.HasTwoFactorByUID(ctx.User.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "HasTwoFactorByUID", err)
+			return
+		} else if !has {
+			ctx.Error(http.StatusUnprocessableEntity, "", models.ErrTwoFactorRequired{OrgID: org.ID, UID: ctx.User.ID})
+			return
+		}
+	}
+
 	org.FullName = form.FullName
 	org.Description = form.Description
 	org.Website = form.Website
@@ -267,9 +281,12 @@ func Edit(ctx *context.APIContext) {
 	if form.RepoAdminChangeTeamAccess != nil {
 		org.RepoAdminChangeTeamAccess = *form.RepoAdminChangeTeamAccess
 	}
+	if form.RequireTwoFactor != nil {
+		org.RequireTwoFactor = *form.RequireTwoFactor
+	}
 	if err := models.UpdateUserCols(org,
 		"full_name", "description", "website", "location",
-		"visibility", "repo_admin_change_team_access",
+		"visibility", "repo_admin_change_team_access", "require_two_factor",
 	); err != nil {
 		ctx.Error(http.StatusInternalServerError, "EditOrganization", err)
 		return
@@ -301,3 +318,24 @@ func Delete(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// GetSizeQuota returns the size quota of an organization
+func GetSizeQuota(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/quota organization orgGetSizeQuota
+	// ---
+	// summary: Get the size quota of the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SizeQuota"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	utils.GetSizeQuota(ctx, ctx.Org.Organization)
+}
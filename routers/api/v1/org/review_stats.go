@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetReviewerStats list the review statistics of each reviewer across an organization's repositories
+func GetReviewerStats(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/stats/reviewers organization orgGetReviewerStats
+	// ---
+	// summary: Get the review statistics of each reviewer across an organization's repositories, the most active first
+	// description: The statistics are updated periodically by a background job and may miss the latest reviews.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewerStatsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !models.HasOrgOrUserVisible(ctx.Org.Organization, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	stats, err := models.GetOrgReviewerStats(ctx.Org.Organization, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOrgReviewerStats", err)
+		return
+	}
+
+	apiStats := make([]*api.ReviewerStats, len(stats))
+	for i := range stats {
+		apiStats[i] = convert.ToReviewerStats(stats[i], ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiStats)
+}
@@ -335,9 +335,36 @@ func GetTeamMembers(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "GetTeamMembers", err)
 		return
 	}
+
+	// The two-factor authentication status of the members is only shown to the owners of the organization
+	isOwner := ctx.User.IsAdmin
+	if !isOwner {
+		isOwner, err = models.IsOrganizationOwner(team.OrgID, ctx.User.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsOrganizationOwner", err)
+			return
+		}
+	}
+	var twoFactorStatus map[int64]bool
+	if isOwner {
+		uids := make([]int64, len(team.Members))
+		for i, member := range team.Members {
+			uids[i] = member.ID
+		}
+		twoFactorStatus, err = models.GetTwoFactorStatusByUIDs(uids)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetTwoFactorStatusByUIDs", err)
+			return
+		}
+	}
+
 	members := make([]*api.User, len(team.Members))
 	for i, member := range team.Members {
 		members[i] = convert.ToUser(member, ctx.User)
+		if isOwner {
+			enabled := twoFactorStatus[member.ID]
+			members[i].TwoFactorEnabled = &enabled
+		}
 	}
 	ctx.JSON(http.StatusOK, members)
 }
@@ -407,13 +434,19 @@ func AddTeamMember(ctx *context.APIContext) {
 	//     "$ref": "#/responses/empty"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	u := user.GetUserByParams(ctx)
 	if ctx.Written() {
 		return
 	}
 	if err := ctx.Org.Team.AddMember(u.ID); err != nil {
-		ctx.Error(http.StatusInternalServerError, "AddMember", err)
+		if models.IsErrTwoFactorRequired(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddMember", err)
+		}
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -0,0 +1,185 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListAccessTokens list the access tokens of an organization
+func ListAccessTokens(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/tokens organization orgListAccessTokens
+	// ---
+	// summary: List an organization's access tokens
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgAccessTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{
+		UserID:      ctx.Org.Organization.ID,
+		ListOptions: utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListAccessTokens", err)
+		return
+	}
+
+	apiTokens := make([]*api.OrgAccessToken, len(tokens))
+	for i, t := range tokens {
+		if err := t.LoadCreator(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadCreator", err)
+			return
+		}
+		apiTokens[i] = convert.ToOrgAccessToken(t)
+	}
+	ctx.JSON(http.StatusOK, &apiTokens)
+}
+
+// CreateAccessToken create an access token for an organization
+func CreateAccessToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/tokens organization orgCreateAccessToken
+	// ---
+	// summary: Create an access token for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrgAccessTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/OrgAccessToken"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateOrgAccessTokenOption)
+
+	// an organization token must not be able to create other tokens
+	if ctx.User.IsOrganization() {
+		ctx.Error(http.StatusForbidden, "", "organization tokens can only be created by an owner of the organization")
+		return
+	}
+
+	scopes := make([]string, 0, len(form.Scopes))
+	for _, scope := range form.Scopes {
+		if !models.IsValidAccessTokenScope(scope) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid scope: %s", scope))
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+
+	t := &models.AccessToken{
+		UID:       ctx.Org.Organization.ID,
+		CreatorID: ctx.User.ID,
+		Name:      form.Name,
+		Scope:     strings.Join(scopes, ","),
+	}
+	if form.Expires != nil {
+		if !form.Expires.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "expiration date must be in the future")
+			return
+		}
+		t.ExpiresUnix = timeutil.TimeStamp(form.Expires.Unix())
+	}
+
+	exist, err := models.AccessTokenByNameExists(t)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if exist {
+		ctx.Error(http.StatusBadRequest, "AccessTokenByNameExists", errors.New("access token name has been used already"))
+		return
+	}
+
+	if err := models.NewAccessToken(t); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
+		return
+	}
+	t.Creator = ctx.User
+	ctx.JSON(http.StatusCreated, convert.ToOrgAccessToken(t))
+}
+
+// DeleteAccessToken delete an access token of an organization
+func DeleteAccessToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/tokens/{id} organization orgDeleteAccessToken
+	// ---
+	// summary: Delete an access token of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the token to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteAccessTokenByID(ctx.ParamsInt64(":id"), ctx.Org.Organization.ID); err != nil {
+		if models.IsErrAccessTokenNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteAccessTokenByID", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetWorkload list the open issues and pull requests of each assignee across an organization's repositories
+func GetWorkload(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/workload organization orgGetWorkload
+	// ---
+	// summary: Get the open issues and pull requests of each assignee across an organization's repositories, busiest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AssigneeWorkloadList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !models.HasOrgOrUserVisible(ctx.Org.Organization, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	workloads, err := models.GetOrgAssigneeWorkloads(ctx.Org.Organization, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOrgAssigneeWorkloads", err)
+		return
+	}
+
+	apiWorkloads := make([]*api.AssigneeWorkload, len(workloads))
+	for i := range workloads {
+		apiWorkloads[i] = convert.ToAssigneeWorkload(workloads[i], ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiWorkloads)
+}
@@ -0,0 +1,303 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// CreateAccessRequest requests access to a private repository the authenticated user has no access to
+func CreateAccessRequest(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/access_requests repository repoCreateAccessRequest
+	// ---
+	// summary: Request access to a private repository of an organization the authenticated user is a member of
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoAccessRequestOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoAccessRequest"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateRepoAccessRequestOption)
+
+	owner, err := models.GetUserByName(ctx.Params(":username"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+	repo, err := models.GetRepositoryByName(owner.ID, ctx.Params(":reponame"))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByName", err)
+		}
+		return
+	}
+	repo.Owner = owner
+
+	if canRequest, err := repo_service.CanRequestAccess(ctx.User, repo); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CanRequestAccess", err)
+		return
+	} else if !canRequest {
+		ctx.NotFound()
+		return
+	}
+
+	req, err := repo_service.RequestAccess(ctx.User, repo, form.Message)
+	if err != nil {
+		if models.IsErrRepoAccessRequestAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", "an access request to this repository is already pending")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RequestAccess", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToRepoAccessRequest(req, ctx.User))
+}
+
+// ListAccessRequests list the requests to access a repository
+func ListAccessRequests(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/access_requests repository repoListAccessRequests
+	// ---
+	// summary: List the requests to access a repository, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: state of the requests, all of them by default
+	//   type: string
+	//   enum: [pending, approved, rejected, all]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAccessRequestList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListRepoAccessRequests(ctx, ctx.Repo.Repository.ID, 0)
+}
+
+func getAccessRequest(ctx *context.APIContext) *models.RepoAccessRequest {
+	req, err := models.GetRepoAccessRequestByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoAccessRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoAccessRequestByID", err)
+		}
+		return nil
+	}
+	if err := req.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return nil
+	}
+	return req
+}
+
+// GetAccessRequest get a request to access a repository
+func GetAccessRequest(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/access_requests/{id} repository repoGetAccessRequest
+	// ---
+	// summary: Get a request to access a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the access request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAccessRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	req := getAccessRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoAccessRequest(req, ctx.User))
+}
+
+// ApproveAccessRequest approve a request to access a repository
+func ApproveAccessRequest(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/access_requests/{id}/approve repository repoApproveAccessRequest
+	// ---
+	// summary: Approve a request to access a repository, its user becomes a collaborator with the given permission
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the access request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApproveRepoAccessRequestOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAccessRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ApproveRepoAccessRequestOption)
+	req := getAccessRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.ApproveAccessRequest(ctx.User, req, models.ParseAccessMode(form.Permission)); err != nil {
+		if models.IsErrRepoAccessRequestNotPending(err) {
+			ctx.Error(http.StatusConflict, "", "the access request has already been reviewed")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ApproveAccessRequest", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoAccessRequest(req, ctx.User))
+}
+
+// RejectAccessRequest reject a request to access a repository
+func RejectAccessRequest(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/access_requests/{id}/reject repository repoRejectAccessRequest
+	// ---
+	// summary: Reject a request to access a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the access request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAccessRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	req := getAccessRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.RejectAccessRequest(ctx.User, req); err != nil {
+		if models.IsErrRepoAccessRequestNotPending(err) {
+			ctx.Error(http.StatusConflict, "", "the access request has already been reviewed")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RejectAccessRequest", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoAccessRequest(req, ctx.User))
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetBlame returns the commits which last changed each line of a file
+func GetBlame(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/blame/{filepath} repository repoGetBlame
+	// ---
+	// summary: Get the commits which last changed the lines of a file
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileBlame"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if ctx.Repo.Repository.IsEmpty || len(ctx.Repo.TreePath) == 0 {
+		ctx.NotFound()
+		return
+	}
+
+	commit := ctx.Repo.Commit
+	if ref := ctx.QueryTrim("ref"); len(ref) > 0 {
+		var err error
+		commit, err = ctx.Repo.GitRepo.GetCommit(ref)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+	}
+
+	entry, err := commit.GetTreeEntryByPath(ctx.Repo.TreePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetTreeEntryByPath", err)
+		}
+		return
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		ctx.NotFound()
+		return
+	}
+
+	blameReader, err := git.CreateBlameReader(ctx, ctx.Repo.Repository.RepoPath(), commit.ID.String(), ctx.Repo.TreePath)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateBlameReader", err)
+		return
+	}
+	defer blameReader.Close()
+
+	blame := &api.FileBlame{
+		Path:   ctx.Repo.TreePath,
+		SHA:    commit.ID.String(),
+		Ranges: make([]*api.BlameRange, 0, 10),
+	}
+	commits := make(map[string]*api.BlameCommit)
+	line := 1
+	for {
+		part, err := blameReader.NextPart()
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "NextPart", err)
+			return
+		}
+		if part == nil {
+			break
+		}
+
+		blameCommit, ok := commits[part.Sha]
+		if !ok {
+			c, err := ctx.Repo.GitRepo.GetCommit(part.Sha)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+				return
+			}
+			blameCommit = &api.BlameCommit{
+				SHA:       part.Sha,
+				URL:       util.URLJoin(ctx.Repo.Repository.APIURL(), "git/commits", part.Sha),
+				HTMLURL:   util.URLJoin(ctx.Repo.Repository.HTMLURL(), "commit", part.Sha),
+				Author:    convert.ToCommitUser(c.Author),
+				Committer: convert.ToCommitUser(c.Committer),
+				Message:   c.CommitMessage,
+			}
+			commits[part.Sha] = blameCommit
+		}
+
+		blame.Ranges = append(blame.Ranges, &api.BlameRange{
+			StartLine: line,
+			EndLine:   line + len(part.Lines) - 1,
+			Commit:    blameCommit,
+		})
+		line += len(part.Lines)
+	}
+
+	ctx.JSON(http.StatusOK, blame)
+}
@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/notification"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
@@ -225,6 +226,94 @@ func CreateBranch(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, br)
 }
 
+// renameBranchError writes the error of renaming a branch to the context
+func renameBranchError(ctx *context.APIContext, err error) {
+	switch {
+	case models.IsErrBranchDoesNotExist(err):
+		ctx.NotFound(err)
+	case models.IsErrBranchAlreadyExists(err), models.IsErrBranchNameConflict(err), models.IsErrTagAlreadyExists(err):
+		ctx.Error(http.StatusConflict, "", "A branch or tag with the new name already exists.")
+	case errors.Is(err, repo_service.ErrBranchNameInvalid):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	default:
+		ctx.Error(http.StatusInternalServerError, "RenameBranch", err)
+	}
+}
+
+// RenameBranch renames a branch of a repository
+func RenameBranch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/branches/rename repository repoRenameBranch
+	// ---
+	// summary: Rename a branch, along with the default branch, the branch protection, the open pull requests and the issues referencing it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RenameBranchRepoOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: A branch or tag with the new name already exists.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.RenameBranchRepoOption)
+	if ctx.Repo.Repository.IsArchived {
+		ctx.Error(http.StatusForbidden, "", "Repository is archived.")
+		return
+	}
+
+	if err := repo_service.RenameBranch(ctx.User, ctx.Repo.Repository, ctx.Repo.GitRepo, opt.OldBranchName, opt.NewBranchName); err != nil {
+		renameBranchError(ctx, err)
+		return
+	}
+
+	branch, err := repo_module.GetBranch(ctx.Repo.Repository, opt.NewBranchName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranch", err)
+		return
+	}
+
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+
+	branchProtection, err := ctx.Repo.Repository.GetBranchProtection(branch.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtection", err)
+		return
+	}
+
+	br, err := convert.ToBranch(ctx.Repo.Repository, branch, commit, branchProtection, ctx.User, ctx.Repo.IsAdmin())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convert.ToBranch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, br)
+}
+
 // ListBranches list all the branches of a repository
 func ListBranches(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/branches repository repoListBranches
@@ -500,6 +589,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		RequireSignedCommits:          form.RequireSignedCommits,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      form.RequireCodeOwnerApproval,
 	}
 
 	err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
@@ -530,6 +620,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "New branch protection not found", err)
 		return
 	}
+	notification.NotifyUpdateBranchProtection(ctx.User, ctx.Repo.Repository, bp, true)
 
 	ctx.JSON(http.StatusCreated, convert.ToBranchProtection(bp))
 
@@ -648,6 +739,10 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.RequireCodeOwnerApproval != nil {
+		protectBranch.RequireCodeOwnerApproval = *form.RequireCodeOwnerApproval
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = models.GetUserIDsByNames(form.PushWhitelistUsernames, false)
@@ -762,6 +857,7 @@ func EditBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "New branch protection not found", err)
 		return
 	}
+	notification.NotifyUpdateBranchProtection(ctx.User, ctx.Repo.Repository, bp, false)
 
 	ctx.JSON(http.StatusOK, convert.ToBranchProtection(bp))
 }
@@ -811,6 +907,7 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteProtectedBranch", err)
 		return
 	}
+	notification.NotifyDeleteBranchProtection(ctx.User, ctx.Repo.Repository, bp)
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/notification"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -161,6 +162,12 @@ func AddCollaborator(ctx *context.APIContext) {
 		return
 	}
 
+	oldCollaboration, err := ctx.Repo.Repository.GetCollaboration(collaborator.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCollaboration", err)
+		return
+	}
+
 	if err := ctx.Repo.Repository.AddCollaborator(collaborator); err != nil {
 		ctx.Error(http.StatusInternalServerError, "AddCollaborator", err)
 		return
@@ -173,6 +180,17 @@ func AddCollaborator(ctx *context.APIContext) {
 		}
 	}
 
+	collaboration, err := ctx.Repo.Repository.GetCollaboration(collaborator.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCollaboration", err)
+		return
+	}
+	if oldCollaboration == nil {
+		notification.NotifyAddCollaborator(ctx.User, ctx.Repo.Repository, collaborator, collaboration.Mode)
+	} else if oldCollaboration.Mode != collaboration.Mode {
+		notification.NotifyChangeCollaboratorAccessMode(ctx.User, ctx.Repo.Repository, collaborator, oldCollaboration.Mode, collaboration.Mode)
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -215,10 +233,19 @@ func DeleteCollaborator(ctx *context.APIContext) {
 		return
 	}
 
+	isCollaborator, err := ctx.Repo.Repository.IsCollaborator(collaborator.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsCollaborator", err)
+		return
+	}
+
 	if err := ctx.Repo.Repository.DeleteCollaboration(collaborator.ID); err != nil {
 		ctx.Error(http.StatusInternalServerError, "DeleteCollaboration", err)
 		return
 	}
+	if isCollaborator {
+		notification.NotifyRemoveCollaborator(ctx.User, ctx.Repo.Repository, collaborator)
+	}
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -0,0 +1,285 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// CompareDiff compare two branches, tags or commits
+func CompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareDiff
+	// ---
+	// summary: Get the commits and the changed files between two branches, tags or commits
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: compared revisions, formatted as `base...head` or `base...owner:head` to compare with a fork
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of the commits
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the commits
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	infos := strings.SplitN(ctx.Params("*"), "...", 2)
+	if len(infos) != 2 || infos[0] == "" || infos[1] == "" {
+		ctx.NotFound()
+		return
+	}
+	baseRef, headRef := infos[0], infos[1]
+
+	baseRepo := ctx.Repo.Repository
+	headRepo := baseRepo
+	headGitRepo := ctx.Repo.GitRepo
+
+	// If the head has an owner, it is a ref of the owner's fork
+	if headInfos := strings.SplitN(headRef, ":", 2); len(headInfos) == 2 {
+		headUser, err := models.GetUserByName(headInfos[0])
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		headRef = headInfos[1]
+
+		if headUser.ID != baseRepo.OwnerID {
+			var has bool
+			headRepo, has = models.HasForkedRepo(headUser.ID, baseRepo.ID)
+			if !has {
+				ctx.NotFound()
+				return
+			}
+
+			// user should have permission to read the code of the fork too
+			perm, err := models.GetUserRepoPermission(headRepo, ctx.User)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return
+			}
+			if !perm.CanRead(models.UnitTypeCode) {
+				ctx.NotFound()
+				return
+			}
+
+			headGitRepo, err = git.OpenRepository(headRepo.RepoPath())
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+				return
+			}
+			defer headGitRepo.Close()
+		}
+	}
+
+	if !isValidRef(ctx.Repo.GitRepo, baseRef) || !isValidRef(headGitRepo, headRef) {
+		ctx.NotFound()
+		return
+	}
+
+	compareInfo, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseRef, headRef)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
+		return
+	}
+
+	respondCompare(ctx, headRepo, headGitRepo, compareInfo)
+}
+
+// CompareRemote compares a ref of a remote repository with a branch, tag or commit of the repository
+func CompareRemote(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/compare_remote repository repoCompareRemote
+	// ---
+	// summary: Get the commits and the changed files between a ref of the repository and a ref of a remote repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CompareRemoteOption"
+	// - name: page
+	//   in: query
+	//   description: page number of the commits
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the commits
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CompareRemoteOption)
+
+	if setting.Repository.DisableMigrations {
+		ctx.Error(http.StatusForbidden, "MigrationsGlobalDisabled", fmt.Errorf("the site administrator has disabled migrations"))
+		return
+	}
+
+	remoteAddr, err := forms.ParseRemoteAddr(form.RemoteAddress, form.AuthUsername, form.AuthPassword)
+	if err == nil {
+		err = migrations.IsMigrateURLAllowed(remoteAddr, ctx.User)
+	}
+	if err != nil {
+		handleRemoteAddrError(ctx, err)
+		return
+	}
+
+	baseRef := form.Base
+	if baseRef == "" {
+		baseRef = ctx.Repo.Repository.DefaultBranch
+	}
+	if !isValidRef(ctx.Repo.GitRepo, baseRef) {
+		ctx.NotFound()
+		return
+	}
+	remoteRef := form.RemoteRef
+	if remoteRef == "" {
+		remoteRef = "HEAD"
+	}
+
+	compareGitRepo, compareInfo, cleanup, err := ctx.Repo.GitRepo.GetRemoteCompareInfo(remoteAddr, baseRef, remoteRef, time.Duration(setting.Git.Timeout.Pull)*time.Second)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid remote ref: %s", remoteRef))
+		} else if git.IsErrFetchRemote(err) {
+			sanitizer := util.NewStringURLSanitizer(remoteAddr, true)
+			ctx.Error(http.StatusUnprocessableEntity, "", sanitizer.Replace(fmt.Sprintf("unable to fetch %s from the remote repository: %s", remoteRef, strings.TrimSpace(err.(*git.ErrFetchRemote).StdErr))))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRemoteCompareInfo", err)
+		}
+		return
+	}
+	defer cleanup()
+
+	respondCompare(ctx, ctx.Repo.Repository, compareGitRepo, compareInfo)
+}
+
+// respondCompare writes the commits and the changed files of the comparison to the response
+func respondCompare(ctx *context.APIContext, headRepo *models.Repository, headGitRepo *git.Repository, compareInfo *git.CompareInfo) {
+	numFiles, additions, deletions, err := headGitRepo.GetDiffShortStat(compareInfo.MergeBase, compareInfo.HeadCommitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffShortStat", err)
+		return
+	}
+	fileStats, err := headGitRepo.GetDiffFileStats(compareInfo.MergeBase, compareInfo.HeadCommitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffFileStats", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	start, end := listOptions.GetStartEnd()
+	if end > compareInfo.Commits.Len() {
+		end = compareInfo.Commits.Len()
+	}
+	if start > end {
+		start = end
+	}
+
+	userCache := make(map[string]*models.User)
+	apiCommits := make([]*api.Commit, 0, end-start)
+	i := 0
+	for e := compareInfo.Commits.Front(); e != nil && i < end; e = e.Next() {
+		if i >= start {
+			apiCommit, err := convert.ToCommit(headRepo, e.Value.(*git.Commit), userCache)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+				return
+			}
+			apiCommits = append(apiCommits, apiCommit)
+		}
+		i++
+	}
+
+	files := make([]*api.ChangedFile, 0, len(fileStats))
+	for _, stat := range fileStats {
+		files = append(files, &api.ChangedFile{
+			Filename:  stat.Name,
+			Status:    stat.Status,
+			Additions: stat.Additions,
+			Deletions: stat.Deletions,
+			IsBinary:  stat.IsBinary,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, &api.Compare{
+		BaseCommit:   compareInfo.BaseCommitID,
+		HeadCommit:   compareInfo.HeadCommitID,
+		MergeBase:    compareInfo.MergeBase,
+		TotalCommits: compareInfo.Commits.Len(),
+		Commits:      apiCommits,
+		ChangedFiles: numFiles,
+		Additions:    additions,
+		Deletions:    deletions,
+		Files:        files,
+	})
+}
+
+// isValidRef checks if the given ref is a branch, a tag or a commit of the repository
+func isValidRef(gitRepo *git.Repository, ref string) bool {
+	if gitRepo.IsBranchExist(ref) || gitRepo.IsTagExist(ref) {
+		return true
+	}
+	_, err := gitRepo.GetCommit(ref)
+	return err == nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	deployment_service "code.gitea.io/gitea/services/deployment"
+)
+
+// ListDeployments lists the environments deployed for the pull requests of a repository
+func ListDeployments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/deployments repository repoListDeployments
+	// ---
+	// summary: List the environments deployed for the pull requests of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: pull_request
+	//   in: query
+	//   description: index of the pull request to list the deployments of
+	//   type: integer
+	//   format: int64
+	// - name: active
+	//   in: query
+	//   description: only list the deployments which have not been torn down
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeploymentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := models.FindDeploymentsOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.Repo.Repository.ID,
+		OnlyActive:  ctx.QueryBool("active"),
+	}
+	if index := ctx.QueryInt64("pull_request"); index > 0 {
+		pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, index)
+		if err != nil {
+			if models.IsErrPullRequestNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+			}
+			return
+		}
+		opts.PullID = pr.ID
+	}
+
+	deployments, err := models.FindDeployments(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindDeployments", err)
+		return
+	}
+	count, err := models.CountDeployments(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountDeployments", err)
+		return
+	}
+
+	pulls := make(map[int64]*models.PullRequest)
+	apiDeployments := make([]*api.Deployment, 0, len(deployments))
+	for _, d := range deployments {
+		pr, ok := pulls[d.PullID]
+		if !ok {
+			if pr, err = models.GetPullRequestByID(d.PullID); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByID", err)
+				return
+			}
+			pulls[d.PullID] = pr
+		}
+		apiDeployments = append(apiDeployments, convert.ToDeployment(d, pr))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprint(count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, apiDeployments)
+}
+
+// GetDeployment gets an environment deployed for a pull request of a repository
+func GetDeployment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/deployments/{id} repository repoGetDeployment
+	// ---
+	// summary: Get an environment deployed for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deployment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Deployment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d, pr := getDeploymentByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDeployment(d, pr))
+}
+
+// CreateDeployment registers an environment deployed for a pull request of a repository
+func CreateDeployment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/deployments repository repoCreateDeployment
+	// ---
+	// summary: Register an environment deployed for a pull request, e.g. a review app
+	// description: The environment replaces the previous deployment of the same environment for the pull request. It is shown on the pull request until the pull request is closed or merged, webhooks are then notified to tear it down.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateDeploymentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Deployment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateDeploymentOption)
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, form.PullRequest)
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	d, err := deployment_service.CreateDeployment(ctx.User, pr, form.Environment, form.EnvironmentURL, form.SHA)
+	if err != nil {
+		if errors.Is(err, deployment_service.ErrPullRequestClosed) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateDeployment", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateDeployment", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDeployment(d, pr))
+}
+
+// DeleteDeployment deletes an environment deployed for a pull request of a repository
+func DeleteDeployment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/deployments/{id} repository repoDeleteDeployment
+	// ---
+	// summary: Delete an environment deployed for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deployment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteDeployment(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrDeploymentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteDeployment", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getDeploymentByParams(ctx *context.APIContext) (*models.Deployment, *models.PullRequest) {
+	d, err := models.GetDeploymentByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrDeploymentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDeploymentByID", err)
+		}
+		return nil, nil
+	}
+	pr, err := models.GetPullRequestByID(d.PullID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPullRequestByID", err)
+		return nil, nil
+	}
+	return d, pr
+}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -130,3 +131,114 @@ func CreateFork(ctx *context.APIContext) {
 	//TODO change back to 201
 	ctx.JSON(http.StatusAccepted, convert.ToRepo(fork, models.AccessModeOwner))
 }
+
+// GetForkNetwork list the fork network of a repo
+func GetForkNetwork(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/forks/network repository repoGetForkNetwork
+	// ---
+	// summary: List the repositories of the fork network of a repository, with the divergence of every fork from its parent
+	// description: The network starts with its topmost repository readable by the user, every fork is listed after its parent.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkNetwork"
+
+	listOptions := utils.GetListOptions(ctx)
+	entries, total, err := repo_service.GetForkNetwork(ctx.User, ctx.Repo.Repository, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetForkNetwork", err)
+		return
+	}
+
+	apiEntries := make([]*api.ForkNetworkEntry, len(entries))
+	for i, entry := range entries {
+		access, err := models.AccessLevel(ctx.User, entry.Repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiEntries[i] = &api.ForkNetworkEntry{
+			Repository: convert.ToRepo(entry.Repo, access),
+			Depth:      entry.Depth,
+		}
+		if entry.Parent != nil {
+			apiEntries[i].Parent = entry.Parent.FullName()
+		}
+		if entry.Divergence != nil {
+			apiEntries[i].AheadBy = &entry.Divergence.Ahead
+			apiEntries[i].BehindBy = &entry.Divergence.Behind
+		}
+	}
+
+	ctx.SetLinkHeader(total, listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", total))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, apiEntries)
+}
+
+// DetachFork convert a fork to a regular repository
+func DetachFork(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/detach repository repoDetachFork
+	// ---
+	// summary: Detach a fork from its parent repository, converting it to a regular repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repo := ctx.Repo.Repository
+	if !repo.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork")
+		return
+	}
+
+	if err := models.ConvertForkToNormalRepository(repo); err != nil {
+		if models.IsErrForkHasOpenPullRequests(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ConvertForkToNormalRepository", err)
+		}
+		return
+	}
+	log.Trace("Repository converted from fork to regular: %s", repo.FullName())
+
+	ctx.JSON(http.StatusOK, convert.ToRepo(repo, models.AccessModeOwner))
+}
@@ -7,6 +7,7 @@ package repo
 
 import (
 	"net/http"
+	"strconv"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -269,3 +270,349 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListHookDeliveries list the deliveries of a hook of a repository
+func ListHookDeliveries(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/{id}/deliveries repository repoListHookDeliveries
+	// ---
+	// summary: List the deliveries of a hook in a repository, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookDeliveryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	tasks, err := models.FindHookTasks(hook.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindHookTasks", err)
+		return
+	}
+	count, err := models.CountHookTasks(hook.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountHookTasks", err)
+		return
+	}
+
+	apiDeliveries := make([]*api.HookDelivery, len(tasks))
+	for i := range tasks {
+		apiDeliveries[i] = convert.ToHookDelivery(tasks[i])
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiDeliveries)
+}
+
+// getHookDeliveryByParams returns the delivery ":delivery_id" of the hook ":id" of the repository
+func getHookDeliveryByParams(ctx *context.APIContext) (*models.Webhook, *models.HookTask) {
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return nil, nil
+	}
+
+	task, err := models.GetHookTaskByID(hook.ID, ctx.ParamsInt64(":delivery_id"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetHookTaskByID", err)
+		}
+		return nil, nil
+	}
+	return hook, task
+}
+
+// GetHookDelivery get a delivery of a hook of a repository
+func GetHookDelivery(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/{id}/deliveries/{delivery_id} repository repoGetHookDelivery
+	// ---
+	// summary: Get a delivery of a hook in a repository with its request and response
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: delivery_id
+	//   in: path
+	//   description: id of the delivery
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	_, task := getHookDeliveryByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHookDelivery(task))
+}
+
+// RedeliverHookDelivery delivers the payload of a delivery of a hook of a repository again
+func RedeliverHookDelivery(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/deliveries/{delivery_id}/redeliver repository repoRedeliverHookDelivery
+	// ---
+	// summary: Deliver the payload of a delivery of a hook in a repository again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: delivery_id
+	//   in: path
+	//   description: id of the delivery to redeliver
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, task := getHookDeliveryByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	newTask, err := webhook.ReplayHookTask(hook, task.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReplayHookTask", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToHookDelivery(newTask))
+}
+
+// ListRepoEvents list the recent events of a repository
+func ListRepoEvents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/events repository repoListEvents
+	// ---
+	// summary: List the recent push, issue and pull request events of a repository which can be replayed, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoEventList"
+
+	listOptions := utils.GetListOptions(ctx)
+	events, err := models.FindRepoEvents(ctx.Repo.Repository.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoEvents", err)
+		return
+	}
+	count, err := models.CountRepoEvents(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountRepoEvents", err)
+		return
+	}
+
+	apiEvents := make([]*api.RepoEvent, len(events))
+	for i := range events {
+		apiEvents[i] = convert.ToRepoEvent(events[i])
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiEvents)
+}
+
+// getRepoEventByParams returns the event ":event_id" of the repository
+func getRepoEventByParams(ctx *context.APIContext) *models.RepoEvent {
+	event, err := models.GetRepoEventByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":event_id"))
+	if err != nil {
+		if models.IsErrRepoEventNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoEventByID", err)
+		}
+		return nil
+	}
+	return event
+}
+
+// GetRepoEvent get a recent event of a repository
+func GetRepoEvent(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/events/{event_id} repository repoGetEvent
+	// ---
+	// summary: Get a recent event of a repository with its payload
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: event_id
+	//   in: path
+	//   description: id of the event
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoEvent"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	event := getRepoEventByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoEvent(event))
+}
+
+// ReplayRepoEvent delivers a recent event of a repository again
+func ReplayRepoEvent(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/events/{event_id}/replay repository repoReplayEvent
+	// ---
+	// summary: Deliver a recent event of a repository again, to one of its webhooks or to all of them as when it happened
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: event_id
+	//   in: path
+	//   description: id of the event to replay
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReplayRepoEventOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.ReplayRepoEventOption)
+	event := getRepoEventByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var hook *models.Webhook
+	if form.HookID != 0 {
+		var err error
+		hook, err = utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, form.HookID)
+		if err != nil {
+			return
+		}
+	}
+
+	task, err := webhook.ReplayRepoEvent(ctx.Repo.Repository, event, hook)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReplayRepoEvent", err)
+		return
+	}
+	if task == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToHookDelivery(task))
+}
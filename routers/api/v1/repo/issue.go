@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
@@ -23,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/antispam"
 	issue_service "code.gitea.io/gitea/services/issue"
 )
 
@@ -55,6 +57,14 @@ func SearchIssues(ctx *context.APIContext) {
 	//   description: repository to prioritize in the results
 	//   type: integer
 	//   format: int64
+	// - name: repo_ids
+	//   in: query
+	//   description: only search in these repositories. Repositories the user has no access to are discarded
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: integer
+	//     format: int64
 	// - name: type
 	//   in: query
 	//   description: filter by type (issues / pulls) if set
@@ -75,6 +85,10 @@ func SearchIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: filter (issues / pulls) assigned to you, default is false
 	//   type: boolean
+	// - name: assigned_by
+	//   in: query
+	//   description: filter (issues / pulls) assigned to
+	//   type: string
 	// - name: created
 	//   in: query
 	//   description: filter (issues / pulls) created by you, default is false
@@ -98,6 +112,10 @@ func SearchIssues(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	before, since, err := utils.GetQueryBeforeSince(ctx)
 	if err != nil {
@@ -137,6 +155,33 @@ func SearchIssues(ctx *context.APIContext) {
 		return
 	}
 
+	// restrict the search to the requested repositories the user has access to
+	if filterRepoIDs := ctx.QueryStrings("repo_ids"); len(filterRepoIDs) > 0 {
+		requestedIDs, err := base.StringsToInt64s(filterRepoIDs)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "StringsToInt64s", err)
+			return
+		}
+		accessible := base.Int64sToMap(repoIDs)
+		repoIDs = make([]int64, 0, len(requestedIDs))
+		for _, id := range requestedIDs {
+			if accessible[id] {
+				repoIDs = append(repoIDs, id)
+			}
+		}
+		if len(repoIDs) == 0 {
+			ctx.Header().Set("X-Total-Count", "0")
+			ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+			ctx.JSON(http.StatusOK, []*api.Issue{})
+			return
+		}
+	}
+
+	assignedByID := getUserIDForFilter(ctx, "assigned_by")
+	if ctx.Written() {
+		return
+	}
+
 	var issues []*models.Issue
 	var filteredCount int64
 
@@ -201,6 +246,7 @@ func SearchIssues(ctx *context.APIContext) {
 			IsPull:             isPull,
 			UpdatedBeforeUnix:  before,
 			UpdatedAfterUnix:   since,
+			AssigneeID:         assignedByID,
 		}
 
 		// Filter for: Created by User, Assigned to User, Mentioning User, Review of User Requested
@@ -237,6 +283,42 @@ func SearchIssues(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
 }
 
+// ListGoodFirstIssues list the beginner-friendly issues across the public repositories
+func ListGoodFirstIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/issues/good_first issue issueListGoodFirstIssues
+	// ---
+	// summary: List the open issues of public repositories labelled as beginner-friendly
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	listOptions := utils.GetListOptions(ctx)
+	issues, count, err := models.FindGoodFirstIssues(models.FindGoodFirstIssuesOptions{
+		ListOptions: listOptions,
+		LabelNames:  setting.Repository.Issue.GoodFirstIssueLabels,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindGoodFirstIssues", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
+
 // ListIssues list the issues of a repository
 func ListIssues(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues issue issueListIssues
@@ -600,6 +682,9 @@ func CreateIssue(ctx *context.APIContext) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewIssue", err)
 		return
@@ -664,6 +749,8 @@ func EditIssue(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	//   "412":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditIssueOption)
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
@@ -696,6 +783,18 @@ func EditIssue(ctx *context.APIContext) {
 	if form.Body != nil {
 		issue.Content = *form.Body
 	}
+
+	var flaggedContent *antispam.Content
+	if len(form.Title) > 0 || form.Body != nil {
+		if flaggedContent, err = issue_service.CheckEditedContent(issue, ctx.User, issue.Title, issue.Content); err != nil {
+			if antispam.IsErrContentBlocked(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "CheckEditedContent", err)
+			return
+		}
+	}
 	if form.Ref != nil {
 		err = issue_service.ChangeIssueRef(issue, ctx.User, *form.Ref)
 		if err != nil {
@@ -767,6 +866,9 @@ func EditIssue(ctx *context.APIContext) {
 	if titleChanged {
 		notification.NotifyIssueChangeTitle(ctx.User, issue, oldTitle)
 	}
+	if flaggedContent != nil {
+		antispam.Flag(flaggedContent, issue.HTMLURL())
+	}
 
 	if statusChangeComment != nil {
 		notification.NotifyIssueChangeStatus(ctx.User, issue, statusChangeComment, issue.IsClosed)
@@ -0,0 +1,675 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/upload"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListIssueAttachments lists all attachments of the issue
+func ListIssueAttachments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/assets issue issueListIssueAttachments
+	// ---
+	// summary: List issue's attachments
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, false)
+	if ctx.Written() {
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByIssueID(issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAttachmentsByIssueID", err)
+		return
+	}
+	apiAttachments := make([]*api.Attachment, len(attachments))
+	for i := range attachments {
+		apiAttachments[i] = convert.ToReleaseAttachment(attachments[i])
+	}
+	ctx.JSON(http.StatusOK, apiAttachments)
+}
+
+// GetIssueAttachment gets a single attachment of the issue
+func GetIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/assets/{attachment_id} issue issueGetIssueAttachment
+	// ---
+	// summary: Get an issue attachment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Attachment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, false)
+	if ctx.Written() {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToReleaseAttachment(attach))
+}
+
+// CreateIssueAttachment uploads an attachment to the issue in a single request
+func CreateIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/assets issue issueCreateIssueAttachment
+	// ---
+	// summary: Create an issue attachment
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the attachment
+	//   type: string
+	//   required: false
+	// - name: attachment
+	//   in: formData
+	//   description: attachment to upload
+	//   type: file
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	file, header, err := ctx.Req.FormFile("attachment")
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "FormFile", err)
+		return
+	}
+	defer file.Close()
+
+	buf := make([]byte, 1024)
+	n, _ := file.Read(buf)
+	if n > 0 {
+		buf = buf[:n]
+	}
+
+	if err := upload.Verify(buf, header.Filename, setting.Attachment.AllowedTypes); err != nil {
+		ctx.Error(http.StatusBadRequest, "DetectContentType", err)
+		return
+	}
+
+	var filename = header.Filename
+	if query := ctx.Query("name"); query != "" {
+		filename = query
+	}
+
+	attach, err := models.NewAttachment(&models.Attachment{
+		UploaderID: ctx.User.ID,
+		Name:       filename,
+		IssueID:    issue.ID,
+	}, buf, file)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewAttachment", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToReleaseAttachment(attach))
+}
+
+// DeleteIssueAttachment deletes an attachment of the issue
+func DeleteIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/assets/{attachment_id} issue issueDeleteIssueAttachment
+	// ---
+	// summary: Delete an issue attachment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteAttachment(attach, true); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateIssueAttachmentUpload starts a resumable upload of an attachment to the issue
+func CreateIssueAttachmentUpload(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/assets/uploads issue issueCreateIssueAttachmentUpload
+	// ---
+	// summary: Start a resumable upload of an issue attachment
+	// description: The file is then sent in chunks, an interrupted upload is resumed from the received offset.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAttachmentUploadOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/AttachmentUpload"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateAttachmentUploadOption)
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Size <= 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "size must be positive")
+		return
+	}
+	if form.Size > setting.Attachment.MaxSize*1024*1024 {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", fmt.Sprintf("attachments are limited to %d MiB", setting.Attachment.MaxSize))
+		return
+	}
+
+	u := &models.AttachmentUpload{
+		IssueID:    issue.ID,
+		UploaderID: ctx.User.ID,
+		Name:       form.Name,
+		Size:       form.Size,
+	}
+	if err := models.NewAttachmentUpload(u); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewAttachmentUpload", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAttachmentUpload(u))
+}
+
+// GetIssueAttachmentUpload returns the progress of a resumable upload
+func GetIssueAttachmentUpload(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/assets/uploads/{uuid} issue issueGetIssueAttachmentUpload
+	// ---
+	// summary: Get the progress of a resumable upload of an issue attachment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the upload
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentUpload"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	u := getIssueAttachmentUpload(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAttachmentUpload(u))
+}
+
+// UploadIssueAttachmentChunk receives a chunk of a resumable upload
+func UploadIssueAttachmentChunk(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/assets/uploads/{uuid} issue issueUploadIssueAttachmentChunk
+	// ---
+	// summary: Send a chunk of a resumable upload of an issue attachment
+	// description: The chunk must start at the offset received so far, the attachment is created once the last chunk is received.
+	// consumes:
+	// - application/octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the upload
+	//   type: string
+	//   required: true
+	// - name: Content-Range
+	//   in: header
+	//   description: range of the chunk within the file, e.g. `bytes 0-1048575/4194304`
+	//   type: string
+	//   required: true
+	// - name: Digest
+	//   in: header
+	//   description: optional SHA-256 checksum of the chunk, e.g. `SHA-256=<base64 digest>`, the chunk is rejected on mismatch
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: content of the chunk
+	//   required: true
+	//   schema:
+	//     type: string
+	//     format: binary
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentUpload"
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/AttachmentUpload"
+	//   "416":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	u := getIssueAttachmentUpload(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	start, end, total, err := parseContentRange(ctx.Req.Header.Get("Content-Range"))
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "", err)
+		return
+	}
+	if total != u.Size || end >= u.Size {
+		ctx.Error(http.StatusRequestedRangeNotSatisfiable, "", fmt.Sprintf("the range does not fit in the %d bytes of the upload", u.Size))
+		return
+	}
+
+	checksum, err := parseDigestSHA256(ctx.Req.Header.Get("Digest"))
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "", err)
+		return
+	}
+
+	defer ctx.Req.Body.Close()
+	body := bufio.NewReaderSize(ctx.Req.Body, 1024)
+	if start == 0 {
+		buf, _ := body.Peek(1024)
+		if err := upload.Verify(buf, u.Name, setting.Attachment.AllowedTypes); err != nil {
+			ctx.Error(http.StatusBadRequest, "DetectContentType", err)
+			return
+		}
+	}
+
+	if err := u.AppendChunk(body, start, end-start+1, checksum); err != nil {
+		if models.IsErrAttachmentUploadOffsetMismatch(err) {
+			// let the client know where to resume from
+			u.Received = err.(models.ErrAttachmentUploadOffsetMismatch).Received
+			ctx.JSON(http.StatusConflict, convert.ToAttachmentUpload(u))
+		} else if models.IsErrAttachmentUploadChunkInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AppendChunk", err)
+		}
+		return
+	}
+
+	if !u.IsComplete() {
+		ctx.JSON(http.StatusOK, convert.ToAttachmentUpload(u))
+		return
+	}
+
+	attach, err := u.Complete()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Complete", err)
+		return
+	}
+	log.Trace("Resumable upload %s completed as attachment %s", u.UUID, attach.UUID)
+	ctx.JSON(http.StatusCreated, convert.ToReleaseAttachment(attach))
+}
+
+// DeleteIssueAttachmentUpload aborts a resumable upload
+func DeleteIssueAttachmentUpload(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/assets/uploads/{uuid} issue issueDeleteIssueAttachmentUpload
+	// ---
+	// summary: Abort a resumable upload of an issue attachment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the upload
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx, true)
+	if ctx.Written() {
+		return
+	}
+
+	u := getIssueAttachmentUpload(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteAttachmentUpload(u); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachmentUpload", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getIssueForAttachments loads the issue of the request, checking the doer can edit it if the attachments are modified
+func getIssueForAttachments(ctx *context.APIContext, edit bool) *models.Issue {
+	if !setting.Attachment.Enabled {
+		ctx.NotFound("Attachment is not enabled")
+		return nil
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	if edit && !issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "user should be the poster of the issue or have write access")
+		return nil
+	}
+	return issue
+}
+
+func getIssueAttachment(ctx *context.APIContext, issue *models.Issue) *models.Attachment {
+	attach, err := models.GetAttachmentByID(ctx.ParamsInt64(":asset"))
+	if err != nil {
+		if models.IsErrAttachmentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAttachmentByID", err)
+		}
+		return nil
+	}
+	if attach.IssueID != issue.ID || attach.CommentID != 0 {
+		log.Info("User requested attachment is not in issue, issue_id %v, attachment_id: %v", issue.ID, attach.ID)
+		ctx.NotFound()
+		return nil
+	}
+	return attach
+}
+
+func getIssueAttachmentUpload(ctx *context.APIContext, issue *models.Issue) *models.AttachmentUpload {
+	u, err := models.GetAttachmentUploadByUUID(ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrAttachmentUploadNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAttachmentUploadByUUID", err)
+		}
+		return nil
+	}
+	if u.IssueID != issue.ID || u.UploaderID != ctx.User.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return u
+}
+
+// parseContentRange parses a header like "bytes 0-1023/4096"
+func parseContentRange(header string) (start, end, total int64, err error) {
+	invalid := fmt.Errorf("invalid Content-Range header: %q", header)
+
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, 0, 0, invalid
+	}
+	rangeAndTotal := strings.SplitN(strings.TrimPrefix(header, "bytes "), "/", 2)
+	if len(rangeAndTotal) != 2 {
+		return 0, 0, 0, invalid
+	}
+	bounds := strings.SplitN(rangeAndTotal[0], "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, 0, invalid
+	}
+	if start, err = strconv.ParseInt(bounds[0], 10, 64); err != nil {
+		return 0, 0, 0, invalid
+	}
+	if end, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+		return 0, 0, 0, invalid
+	}
+	if total, err = strconv.ParseInt(rangeAndTotal[1], 10, 64); err != nil {
+		return 0, 0, 0, invalid
+	}
+	if start < 0 || end < start || total <= end {
+		return 0, 0, 0, invalid
+	}
+	return start, end, total, nil
+}
+
+// parseDigestSHA256 returns the SHA-256 checksum of a header like "SHA-256=<base64>", if any
+func parseDigestSHA256(header string) ([]byte, error) {
+	for _, digest := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(digest), "=", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "SHA-256") {
+			continue
+		}
+		checksum, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil || len(checksum) != 32 {
+			return nil, fmt.Errorf("invalid SHA-256 digest: %q", parts[1])
+		}
+		return checksum, nil
+	}
+	return nil, nil
+}
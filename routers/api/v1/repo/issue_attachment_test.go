@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContentRange(t *testing.T) {
+	start, end, total, err := parseContentRange("bytes 1024-2047/4096")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1024, start)
+	assert.EqualValues(t, 2047, end)
+	assert.EqualValues(t, 4096, total)
+
+	for _, header := range []string{
+		"",
+		"bytes */4096",
+		"bytes 0-1023",
+		"bytes 1024-1023/4096",
+		"bytes 0-4096/4096",
+		"items 0-1023/4096",
+	} {
+		_, _, _, err := parseContentRange(header)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestParseDigestSHA256(t *testing.T) {
+	sum := sha256.Sum256([]byte("chunk"))
+	encoded := base64.StdEncoding.EncodeToString(sum[:])
+
+	checksum, err := parseDigestSHA256("sha-256=" + encoded)
+	assert.NoError(t, err)
+	assert.EqualValues(t, sum[:], checksum)
+
+	checksum, err = parseDigestSHA256("MD5=HUXZLQLMuI/KZ5KDcJPcOA==, SHA-256=" + encoded)
+	assert.NoError(t, err)
+	assert.EqualValues(t, sum[:], checksum)
+
+	checksum, err = parseDigestSHA256("")
+	assert.NoError(t, err)
+	assert.Nil(t, checksum)
+
+	_, err = parseDigestSHA256("SHA-256=not-base64")
+	assert.Error(t, err)
+}
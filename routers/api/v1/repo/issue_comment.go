@@ -15,6 +15,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/antispam"
 	comment_service "code.gitea.io/gitea/services/comments"
 )
 
@@ -84,11 +85,10 @@ func ListIssueComments(ctx *context.APIContext) {
 		return
 	}
 
-	apiComments := make([]*api.Comment, len(comments))
-	for i, comment := range comments {
+	for _, comment := range comments {
 		comment.Issue = issue
-		apiComments[i] = convert.ToComment(comments[i])
 	}
+	apiComments := convert.ToCommentList(comments)
 	ctx.JSON(http.StatusOK, &apiComments)
 }
 
@@ -155,7 +155,6 @@ func ListRepoIssueComments(ctx *context.APIContext) {
 		return
 	}
 
-	apiComments := make([]*api.Comment, len(comments))
 	if err := models.CommentList(comments).LoadIssues(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "LoadIssues", err)
 		return
@@ -168,9 +167,7 @@ func ListRepoIssueComments(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "LoadRepositories", err)
 		return
 	}
-	for i := range comments {
-		apiComments[i] = convert.ToComment(comments[i])
-	}
+	apiComments := convert.ToCommentList(comments)
 	ctx.JSON(http.StatusOK, &apiComments)
 }
 
@@ -209,6 +206,8 @@ func CreateIssueComment(ctx *context.APIContext) {
 	//     "$ref": "#/responses/Comment"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	form := web.GetForm(ctx).(*api.CreateIssueCommentOption)
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -223,6 +222,10 @@ func CreateIssueComment(ctx *context.APIContext) {
 
 	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Body, nil)
 	if err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "CreateIssueComment", err)
 		return
 	}
@@ -337,6 +340,8 @@ func EditIssueComment(ctx *context.APIContext) {
 	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditIssueCommentOption)
 	editIssueComment(ctx, *form)
@@ -387,6 +392,8 @@ func EditIssueCommentDeprecated(ctx *context.APIContext) {
 	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditIssueCommentOption)
 	editIssueComment(ctx, *form)
@@ -414,6 +421,10 @@ func editIssueComment(ctx *context.APIContext, form api.EditIssueCommentOption)
 	oldContent := comment.Content
 	comment.Content = form.Body
 	if err := comment_service.UpdateComment(comment, ctx.User, oldContent); err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "UpdateComment", err)
 		return
 	}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+)
+
+// GetIssueReferenceGraph returns the cross-references from and to an issue
+func GetIssueReferenceGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/references issue issueGetReferenceGraph
+	// ---
+	// summary: Get the graph of the cross-references from and to an issue or a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: depth
+	//   in: query
+	//   description: number of references to follow from the issue, between 1 and 5, defaults to 1
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueReferenceGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	depth := ctx.QueryInt("depth")
+	if depth <= 0 {
+		depth = 1
+	} else if depth > models.MaxIssueReferenceGraphDepth {
+		depth = models.MaxIssueReferenceGraphDepth
+	}
+
+	graph, err := models.GetIssueReferenceGraph(issue, ctx.User, depth)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueReferenceGraph", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueReferenceGraph(graph))
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListTopVotedIssues list the issues of a repository with the most votes
+func ListTopVotedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/votes issue issueListTopVotedIssues
+	// ---
+	// summary: List a repository's issues with the most votes first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: whether issue is open or closed
+	//   type: string
+	//   enum: [closed, open, all]
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls), defaults to issues
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if !ctx.Repo.Repository.IsVotingEnabled() {
+		ctx.NotFound("IsVotingEnabled")
+		return
+	}
+	isPull := ctx.Query("type") == "pulls"
+	if !ctx.Repo.CanReadIssuesOrPulls(isPull) {
+		ctx.NotFound()
+		return
+	}
+
+	var isClosed util.OptionalBool
+	switch ctx.Query("state") {
+	case "closed":
+		isClosed = util.OptionalBoolTrue
+	case "all":
+		isClosed = util.OptionalBoolNone
+	default:
+		isClosed = util.OptionalBoolFalse
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	issuesOpt := &models.IssuesOptions{
+		ListOptions: listOptions,
+		RepoIDs:     []int64{ctx.Repo.Repository.ID},
+		IsClosed:    isClosed,
+		IsPull:      util.OptionalBoolOf(isPull),
+		SortType:    "mostvotes",
+	}
+
+	issues, err := models.Issues(issuesOpt)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Issues", err)
+		return
+	}
+
+	issuesOpt.ListOptions = models.ListOptions{
+		Page: -1,
+	}
+	count, err := models.CountIssues(issuesOpt)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountIssues", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetWorkload list the open issues and pull requests of each assignee of a repository
+func GetWorkload(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/workload repository repoGetWorkload
+	// ---
+	// summary: Get the open issues and pull requests of each assignee of a repository, busiest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AssigneeWorkloadList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	var issueRepoIDs, pullRepoIDs []int64
+	if ctx.Repo.CanRead(models.UnitTypeIssues) {
+		issueRepoIDs = []int64{ctx.Repo.Repository.ID}
+	}
+	if ctx.Repo.CanRead(models.UnitTypePullRequests) {
+		pullRepoIDs = []int64{ctx.Repo.Repository.ID}
+	}
+
+	workloads, err := models.GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAssigneeWorkloads", err)
+		return
+	}
+
+	apiWorkloads := make([]*api.AssigneeWorkload, len(workloads))
+	for i := range workloads {
+		apiWorkloads[i] = convert.ToAssigneeWorkload(workloads[i], ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiWorkloads)
+}
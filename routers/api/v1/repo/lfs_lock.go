@@ -0,0 +1,166 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListLFSLocks lists the LFS locks of a repository
+func ListLFSLocks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/lfs/locks repository repoListLFSLocks
+	// ---
+	// summary: List the LFS locks of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoLFSLockList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listOptions := utils.GetListOptions(ctx)
+	lfsLocks, err := models.GetLFSLockByRepoID(ctx.Repo.Repository.ID, listOptions.Page, listOptions.PageSize)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLFSLockByRepoID", err)
+		return
+	}
+	count, err := models.CountLFSLockByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountLFSLockByRepoID", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, toRepoLFSLocks(lfsLocks, ctx.User))
+}
+
+// UnlockLFSLock forcibly unlocks a LFS lock of a repository
+func UnlockLFSLock(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/lfs/locks/{id} repository repoUnlockLFSLock
+	// ---
+	// summary: Forcibly unlock a LFS lock of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the lock
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	lfsLocks, err := models.DeleteLFSLocksByIDs(ctx.Repo.Repository.ID, []int64{ctx.ParamsInt64(":id")})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteLFSLocksByIDs", err)
+		return
+	}
+	if len(lfsLocks) == 0 {
+		ctx.NotFound()
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnlockLFSLocks forcibly unlocks several LFS locks of a repository
+func UnlockLFSLocks(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/lfs/locks/unlock repository repoUnlockLFSLocks
+	// ---
+	// summary: Forcibly unlock several LFS locks of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UnlockRepoLFSLocksOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoLFSLockList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.UnlockRepoLFSLocksOption)
+
+	unlocked, err := models.DeleteLFSLocksByIDs(ctx.Repo.Repository.ID, form.IDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteLFSLocksByIDs", err)
+		return
+	}
+	if form.Stale {
+		stale, err := models.DeleteStaleLFSLocks(ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteStaleLFSLocks", err)
+			return
+		}
+		unlocked = append(unlocked, stale...)
+	}
+
+	ctx.JSON(http.StatusOK, toRepoLFSLocks(unlocked, ctx.User))
+}
+
+func toRepoLFSLocks(lfsLocks []*models.LFSLock, doer *models.User) []*api.RepoLFSLock {
+	apiLocks := make([]*api.RepoLFSLock, len(lfsLocks))
+	for i := range lfsLocks {
+		apiLocks[i] = convert.ToRepoLFSLock(lfsLocks[i], doer)
+	}
+	return apiLocks
+}
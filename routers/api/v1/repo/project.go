@@ -0,0 +1,712 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListProjects list the projects of a repository
+func ListProjects(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects issue issueListProjects
+	// ---
+	// summary: List a repository's projects
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: Project state, Recognised values are open, closed and all. Defaults to "open"
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectList"
+
+	var isClosed util.OptionalBool = util.OptionalBoolFalse
+	switch ctx.Query("state") {
+	case string(api.StateClosed):
+		isClosed = util.OptionalBoolTrue
+	case string(api.StateAll):
+		isClosed = util.OptionalBoolNone
+	}
+
+	projects, count, err := models.GetProjects(models.ProjectSearchOptions{
+		RepoID:   ctx.Repo.Repository.ID,
+		Page:     ctx.QueryInt("page"),
+		IsClosed: isClosed,
+		Type:     models.ProjectTypeRepository,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjects", err)
+		return
+	}
+
+	apiProjects := make([]*api.Project, len(projects))
+	for i := range projects {
+		apiProjects[i] = convert.ToAPIProject(projects[i])
+	}
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+	ctx.JSON(http.StatusOK, &apiProjects)
+}
+
+// GetProject get a project of a repository
+func GetProject(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id} issue issueGetProject
+	// ---
+	// summary: Get a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProject(project))
+}
+
+// CreateProject create a project in a repository
+func CreateProject(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects issue issueCreateProject
+	// ---
+	// summary: Create a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Project"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateProjectOption)
+
+	boardType := models.ProjectBoardTypeNone
+	if form.BoardType != "" {
+		var ok bool
+		if boardType, ok = models.ProjectBoardTypeFromName(form.BoardType); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown board type: %s", form.BoardType))
+			return
+		}
+	}
+
+	project := &models.Project{
+		Title:       strings.TrimSpace(form.Title),
+		Description: form.Description,
+		RepoID:      ctx.Repo.Repository.ID,
+		CreatorID:   ctx.User.ID,
+		BoardType:   boardType,
+		Type:        models.ProjectTypeRepository,
+	}
+	if err := models.NewProject(project); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewProject", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIProject(project))
+}
+
+// EditProject edit a project of a repository
+func EditProject(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/projects/{id} issue issueEditProject
+	// ---
+	// summary: Update a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditProjectOption)
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.State != nil && *form.State != string(api.StateOpen) && *form.State != string(api.StateClosed) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown state: %s", *form.State))
+		return
+	}
+
+	if form.Title != nil && strings.TrimSpace(*form.Title) != "" {
+		project.Title = strings.TrimSpace(*form.Title)
+	}
+	if form.Description != nil {
+		project.Description = *form.Description
+	}
+	if err := models.UpdateProject(project); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateProject", err)
+		return
+	}
+
+	if form.State != nil {
+		isClosed := *form.State == string(api.StateClosed)
+		if isClosed != project.IsClosed {
+			if err := models.ChangeProjectStatus(project, isClosed); err != nil {
+				ctx.Error(http.StatusInternalServerError, "ChangeProjectStatus", err)
+				return
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProject(project))
+}
+
+// DeleteProject delete a project of a repository
+func DeleteProject(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id} issue issueDeleteProject
+	// ---
+	// summary: Delete a project
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectByID(project.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteProjectByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListProjectBoards list the columns of a project
+func ListProjectBoards(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/boards issue issueListProjectBoards
+	// ---
+	// summary: List the columns of a project
+	// description: The first column is the default one, it has the id 0 when no column of the project was chosen
+	//              to hold the issues not assigned to a column.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectBoardList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	boards, err := models.GetProjectBoards(project.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoards", err)
+		return
+	}
+
+	apiBoards := make([]*api.ProjectBoard, len(boards))
+	for i := range boards {
+		apiBoards[i] = convert.ToAPIProjectBoard(boards[i])
+	}
+	ctx.JSON(http.StatusOK, &apiBoards)
+}
+
+// CreateProjectBoard create a column in a project
+func CreateProjectBoard(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects/{id}/boards issue issueCreateProjectBoard
+	// ---
+	// summary: Create a column in a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectBoardOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectBoard"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateProjectBoardOption)
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	autoMove := models.ProjectBoardAutoMoveNone
+	if form.AutoMove != "" {
+		var ok bool
+		if autoMove, ok = models.ProjectBoardAutoMoveFromName(form.AutoMove); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown automatic move event: %s", form.AutoMove))
+			return
+		}
+	}
+
+	board := &models.ProjectBoard{
+		ProjectID: project.ID,
+		Title:     strings.TrimSpace(form.Title),
+		CreatorID: ctx.User.ID,
+	}
+	if err := models.NewProjectBoard(board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewProjectBoard", err)
+		return
+	}
+	if autoMove != models.ProjectBoardAutoMoveNone {
+		if err := models.SetProjectBoardAutoMove(board, autoMove); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetProjectBoardAutoMove", err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIProjectBoard(board))
+}
+
+// EditProjectBoard edit a column of a project
+func EditProjectBoard(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/projects/{id}/boards/{board_id} issue issueEditProjectBoard
+	// ---
+	// summary: Update a column of a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board_id
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectBoardOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectBoard"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditProjectBoardOption)
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardByParams(ctx, project, false)
+	if ctx.Written() {
+		return
+	}
+
+	autoMove := board.AutoMove
+	if form.AutoMove != nil {
+		var ok bool
+		if autoMove, ok = models.ProjectBoardAutoMoveFromName(*form.AutoMove); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown automatic move event: %s", *form.AutoMove))
+			return
+		}
+	}
+
+	if form.Title != nil && strings.TrimSpace(*form.Title) != "" {
+		board.Title = strings.TrimSpace(*form.Title)
+	}
+	if form.Sorting != nil {
+		board.Sorting = *form.Sorting
+	}
+	if err := models.UpdateProjectBoard(board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateProjectBoard", err)
+		return
+	}
+	if autoMove != board.AutoMove {
+		if err := models.SetProjectBoardAutoMove(board, autoMove); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetProjectBoardAutoMove", err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProjectBoard(board))
+}
+
+// DeleteProjectBoard delete a column of a project
+func DeleteProjectBoard(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id}/boards/{board_id} issue issueDeleteProjectBoard
+	// ---
+	// summary: Delete a column of a project
+	// description: The issues of the column are moved to the default column.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board_id
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardByParams(ctx, project, false)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectBoardByID(board.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteProjectBoardByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListProjectBoardIssues list the issues in a column of a project
+func ListProjectBoardIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/boards/{board_id}/issues issue issueListProjectBoardIssues
+	// ---
+	// summary: List the issues in a column of a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board_id
+	//   in: path
+	//   description: id of the column, 0 for the issues not assigned to a column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardByParams(ctx, project, true)
+	if ctx.Written() {
+		return
+	}
+
+	issues, err := board.LoadIssues()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssues", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
+
+// MoveProjectIssue move an issue to a column of a project
+func MoveProjectIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects/{id}/boards/{board_id}/issues issue issueMoveProjectIssue
+	// ---
+	// summary: Move an issue to a column of a project
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board_id
+	//   in: path
+	//   description: id of the column, 0 for the issues not assigned to a column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MoveProjectIssueOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.MoveProjectIssueOption)
+	project := getProjectByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardByParams(ctx, project, true)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, form.Issue)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if issue.ProjectID() != project.ID {
+		if err := models.ChangeProjectAssign(issue, ctx.User, project.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeProjectAssign", err)
+			return
+		}
+	}
+	if err := models.MoveIssueAcrossProjectBoards(issue, board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "MoveIssueAcrossProjectBoards", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getProjectByParams returns the project of the repository identified by the id parameter
+func getProjectByParams(ctx *context.APIContext) *models.Project {
+	project, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectByID", err)
+		}
+		return nil
+	}
+	if project.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return project
+}
+
+// getProjectBoardByParams returns the column of the project identified by the board_id parameter,
+// the column 0 holds the issues not assigned to a column when allowUncategorized is set
+func getProjectBoardByParams(ctx *context.APIContext, project *models.Project, allowUncategorized bool) *models.ProjectBoard {
+	boardID := ctx.ParamsInt64(":board_id")
+	if boardID == 0 && allowUncategorized {
+		return &models.ProjectBoard{
+			ProjectID: project.ID,
+			Title:     "Uncategorized",
+			Default:   true,
+		}
+	}
+
+	board, err := models.GetProjectBoard(boardID)
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectBoard", err)
+		}
+		return nil
+	}
+	if board.ProjectID != project.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return board
+}
@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/antispam"
 	"code.gitea.io/gitea/services/forms"
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
@@ -310,7 +311,7 @@ func CreatePullRequest(ctx *context.APIContext) {
 	defer headGitRepo.Close()
 
 	// Check if another PR exists with the same targets
-	existingPr, err := models.GetUnmergedPullRequest(headRepo.ID, ctx.Repo.Repository.ID, headBranch, baseBranch)
+	existingPr, err := models.GetUnmergedPullRequest(headRepo.ID, ctx.Repo.Repository.ID, headBranch, baseBranch, models.PullRequestFlowGithub)
 	if err != nil {
 		if !models.IsErrPullRequestNotExist(err) {
 			ctx.Error(http.StatusInternalServerError, "GetUnmergedPullRequest", err)
@@ -431,6 +432,9 @@ func CreatePullRequest(ctx *context.APIContext) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewPullRequest", err)
 		return
@@ -514,6 +518,18 @@ func EditPullRequest(ctx *context.APIContext) {
 		issue.Content = form.Body
 	}
 
+	var flaggedContent *antispam.Content
+	if len(form.Title) > 0 || len(form.Body) > 0 {
+		if flaggedContent, err = issue_service.CheckEditedContent(issue, ctx.User, issue.Title, issue.Content); err != nil {
+			if antispam.IsErrContentBlocked(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "ContentBlocked", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "CheckEditedContent", err)
+			return
+		}
+	}
+
 	// Update or remove deadline if set
 	if form.Deadline != nil || form.RemoveDeadline != nil {
 		var deadlineUnix timeutil.TimeStamp
@@ -599,6 +615,9 @@ func EditPullRequest(ctx *context.APIContext) {
 	if titleChanged {
 		notification.NotifyIssueChangeTitle(ctx.User, issue, oldTitle)
 	}
+	if flaggedContent != nil {
+		antispam.Flag(flaggedContent, issue.HTMLURL())
+	}
 
 	if statusChangeComment != nil {
 		notification.NotifyIssueChangeStatus(ctx.User, issue, statusChangeComment, issue.IsClosed)
@@ -720,6 +739,8 @@ func MergePullRequest(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/empty"
+	//   "202":
+	//     description: pull request has been scheduled to be merged when its checks succeed
 	//   "405":
 	//     "$ref": "#/responses/empty"
 	//   "409":
@@ -794,7 +815,11 @@ func MergePullRequest(ctx *context.APIContext) {
 		return
 	}
 
-	if !pr.CanAutoMerge() {
+	if len(form.Do) == 0 {
+		form.Do = string(models.MergeStyleMerge)
+	}
+
+	if !pull_service.CanMergeWithStyle(pr, models.MergeStyle(form.Do)) {
 		ctx.Error(http.StatusMethodNotAllowed, "PR not in mergeable state", "Please try again later")
 		return
 	}
@@ -804,12 +829,16 @@ func MergePullRequest(ctx *context.APIContext) {
 		return
 	}
 
+	// the pull request is scheduled to be merged when it is not ready yet and its checks should succeed first
+	scheduleMerge := false
 	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
 		if !models.IsErrNotAllowedToMerge(err) {
 			ctx.Error(http.StatusInternalServerError, "CheckPRReadyToMerge", err)
 			return
 		}
-		if form.ForceMerge != nil && *form.ForceMerge {
+		if form.MergeWhenChecksSucceed {
+			scheduleMerge = true
+		} else if form.ForceMerge != nil && *form.ForceMerge {
 			if isRepoAdmin, err := models.IsUserRepoAdmin(pr.BaseRepo, ctx.User); err != nil {
 				ctx.Error(http.StatusInternalServerError, "IsUserRepoAdmin", err)
 				return
@@ -831,10 +860,6 @@ func MergePullRequest(ctx *context.APIContext) {
 		return
 	}
 
-	if len(form.Do) == 0 {
-		form.Do = string(models.MergeStyleMerge)
-	}
-
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
 		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
@@ -850,6 +875,19 @@ func MergePullRequest(ctx *context.APIContext) {
 		message += "\n\n" + form.MergeMessageField
 	}
 
+	if scheduleMerge {
+		if err := models.ScheduleAutoMerge(ctx.User, pr, models.MergeStyle(form.Do), message); err != nil {
+			if models.IsErrPullAlreadyScheduledToAutoMerge(err) {
+				ctx.Error(http.StatusConflict, "ScheduleAutoMerge", "pull request is already scheduled to be merged when its checks succeed")
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "ScheduleAutoMerge", err)
+			return
+		}
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+
 	if err := pull_service.Merge(pr, ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", models.MergeStyle(form.Do)))
@@ -881,7 +919,7 @@ func MergePullRequest(ctx *context.APIContext) {
 
 	log.Trace("Pull request merged: %d", pr.ID)
 
-	if form.DeleteBranchAfterMerge {
+	if form.DeleteBranchAfterMerge && pr.Flow == models.PullRequestFlowGithub && pr.HeadRepo != nil {
 		var headRepo *git.Repository
 		if ctx.Repo != nil && ctx.Repo.Repository != nil && ctx.Repo.Repository.ID == pr.HeadRepoID && ctx.Repo.GitRepo != nil {
 			headRepo = ctx.Repo.GitRepo
@@ -915,6 +953,77 @@ func MergePullRequest(ctx *context.APIContext) {
 	ctx.Status(http.StatusOK)
 }
 
+// CancelScheduledAutoMerge cancels the scheduled merge of a pull request
+func CancelScheduledAutoMerge(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/merge repository repoCancelScheduledAutoMerge
+	// ---
+	// summary: Cancel the scheduled merge of a pull request when its checks succeed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	scheduled, err := models.GetScheduledMergeByPullID(pr.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetScheduledMergeByPullID", err)
+		return
+	} else if scheduled == nil {
+		ctx.NotFound()
+		return
+	}
+
+	// the merge may be canceled by the user who scheduled it or by anybody allowed to merge
+	if scheduled.DoerID != ctx.User.ID {
+		allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+			return
+		}
+		if !allowedMerge {
+			ctx.Error(http.StatusForbidden, "CancelScheduledAutoMerge", "User not allowed to cancel the scheduled merge")
+			return
+		}
+	}
+
+	if err := models.RemoveScheduledAutoMerge(ctx.User, pr); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemoveScheduledAutoMerge", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
 func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption) (*models.User, *models.Repository, *git.Repository, *git.CompareInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
 
@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// GetPullRequestAudit returns the signed audit bundle of a merged pull request
+func GetPullRequestAudit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/audit repository repoGetPullRequestAudit
+	// ---
+	// summary: Get a signed bundle of the approvals, status checks and commit signatures of a merged pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullAudit"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	if !pr.HasMerged {
+		ctx.Error(http.StatusUnprocessableEntity, "", "pull request has not been merged")
+		return
+	}
+
+	bundle, err := pull_service.GetAuditBundle(ctx.Repo.GitRepo, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAuditBundle", err)
+		return
+	}
+	signature, err := pull_service.SignAuditBundle(bundle, oauth2.DefaultSigningKey)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SignAuditBundle", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.PullAudit{
+		Bundle:    bundle,
+		Signature: signature,
+		Algorithm: oauth2.DefaultSigningKey.SigningMethod().Alg(),
+		KeysURL:   setting.AppURL + "login/oauth/keys",
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// GetPullRequestCLAStatus returns whether the commit authors of a pull request have signed the contributor license agreement
+func GetPullRequestCLAStatus(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/cla repository repoGetPullRequestCLAStatus
+	// ---
+	// summary: Check whether the commit authors of a pull request have signed the contributor license agreement of the organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestCLAStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	status, err := pull_service.GetCLAStatus(pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCLAStatus", err)
+		return
+	}
+	if status == nil {
+		// The organization does not enforce a contributor license agreement
+		ctx.NotFound()
+		return
+	}
+
+	unsigned := make(map[*pull_service.CLAAuthor]bool, len(status.Unsigned))
+	for _, author := range status.Unsigned {
+		unsigned[author] = true
+	}
+	apiStatus := &api.PullRequestCLAStatus{
+		Version: status.CLA.Version,
+		Signed:  status.IsSigned(),
+		Authors: make([]*api.CLAAuthor, 0, len(status.Authors)),
+	}
+	for _, author := range status.Authors {
+		apiAuthor := &api.CLAAuthor{
+			Name:   author.Name,
+			Email:  author.Email,
+			Signed: !unsigned[author],
+		}
+		if author.User != nil {
+			apiAuthor.User = convert.ToUser(author.User, ctx.User)
+		}
+		apiStatus.Authors = append(apiStatus.Authors, apiAuthor)
+	}
+	ctx.JSON(http.StatusOK, apiStatus)
+}
@@ -335,6 +335,11 @@ func CreatePullReview(ctx *context.APIContext) {
 		opts.CommitID = headCommitID
 	}
 
+	// validate all review comments before creating any of them
+	if !validatePullReviewComments(ctx, pr, opts.CommitID, opts.Comments) {
+		return
+	}
+
 	// create review comments
 	for _, c := range opts.Comments {
 		line := c.NewLineNum
@@ -359,7 +364,7 @@ func CreatePullReview(ctx *context.APIContext) {
 	}
 
 	// create review and associate all pending review comments
-	review, _, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID, nil)
+	review, _, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID, opts.Checklist, nil)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
@@ -447,7 +452,7 @@ func SubmitPullReview(ctx *context.APIContext) {
 	}
 
 	// create review and associate all pending review comments
-	review, _, err = pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, headCommitID, nil)
+	review, _, err = pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, headCommitID, opts.Checklist, nil)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
@@ -463,6 +468,56 @@ func SubmitPullReview(ctx *context.APIContext) {
 }
 
 // preparePullReviewType return ReviewType and false or nil and true if an error happen
+// validatePullReviewComments checks that every comment has a body and targets a line of a file
+// existing in the reviewed commit, or in the merge base for comments on old lines
+func validatePullReviewComments(ctx *context.APIContext, pr *models.PullRequest, commitID string, comments []api.CreatePullReviewComment) bool {
+	if len(comments) == 0 {
+		return true
+	}
+
+	headCommit, err := ctx.Repo.GitRepo.GetCommit(commitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("commit %s does not exist", commitID))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return false
+	}
+	var baseCommit *git.Commit
+
+	for i, c := range comments {
+		if len(strings.TrimSpace(c.Body)) == 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("comment %d has no body", i))
+			return false
+		}
+		if c.OldLineNum <= 0 && c.NewLineNum <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("comment %d requires old_position or new_position", i))
+			return false
+		}
+
+		commit := headCommit
+		if c.OldLineNum > 0 {
+			if baseCommit == nil {
+				if baseCommit, err = ctx.Repo.GitRepo.GetCommit(pr.MergeBase); err != nil {
+					ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+					return false
+				}
+			}
+			commit = baseCommit
+		}
+		if _, err := commit.GetTreeEntryByPath(c.Path); err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("comment %d: file %s does not exist", i, c.Path))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTreeEntryByPath", err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
 func preparePullReviewType(ctx *context.APIContext, pr *models.PullRequest, event api.ReviewStateType, body string, hasComments bool) (models.ReviewType, bool) {
 	if err := pr.LoadIssue(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
@@ -893,3 +948,54 @@ func dismissReview(ctx *context.APIContext, msg string, isDismiss bool) {
 	}
 	ctx.JSON(http.StatusOK, apiReview)
 }
+
+// GetPullReviewChecklist gets the items of the review checklist of a pull request
+func GetPullReviewChecklist(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/review_checklist repository repoGetPullReviewChecklist
+	// ---
+	// summary: Get the items of the review checklist defined in the base branch of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewChecklist"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	items, err := pull_service.GetReviewChecklist(ctx.Repo.GitRepo, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewChecklist", err)
+		return
+	}
+	if items == nil {
+		items = []string{}
+	}
+	ctx.JSON(http.StatusOK, items)
+}
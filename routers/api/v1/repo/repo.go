@@ -16,6 +16,8 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -52,7 +54,7 @@ func Search(ctx *context.APIContext) {
 	// parameters:
 	// - name: q
 	//   in: query
-	//   description: keyword
+	//   description: keyword, `topic:<name>` qualifiers restrict the results to the repositories with the topic
 	//   type: string
 	// - name: topic
 	//   in: query
@@ -364,6 +366,7 @@ func Generate(ctx *context.APIContext) {
 		Webhooks:    form.Webhooks,
 		Avatar:      form.Avatar,
 		IssueLabels: form.Labels,
+		Units:       form.Units,
 	}
 
 	if !opts.IsValid() {
@@ -600,6 +603,8 @@ func Edit(ctx *context.APIContext) {
 	//     "$ref": "#/responses/Repository"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     description: A branch or tag with the new name of the default branch already exists.
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 
@@ -672,6 +677,15 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		repo.Website = *opts.Website
 	}
 
+	if opts.AccentColor != nil {
+		if len(*opts.AccentColor) > 0 && !models.LabelColorPattern.MatchString(*opts.AccentColor) {
+			err := fmt.Errorf("bad color code: %s", *opts.AccentColor)
+			ctx.Error(http.StatusUnprocessableEntity, "AccentColor", err)
+			return err
+		}
+		repo.AccentColor = *opts.AccentColor
+	}
+
 	visibilityChanged := false
 	if opts.Private != nil {
 		// Visibility of forked repository is forced sync with base repository.
@@ -708,6 +722,13 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		defer ctx.Repo.GitRepo.Close()
 	}
 
+	if opts.RenameDefaultBranch != nil && !repo.IsEmpty {
+		if err := repo_service.RenameBranch(ctx.User, repo, ctx.Repo.GitRepo, repo.DefaultBranch, *opts.RenameDefaultBranch); err != nil {
+			renameBranchError(ctx, err)
+			return err
+		}
+	}
+
 	// Default branch only updated if changed and exist or the repository is empty
 	if opts.DefaultBranch != nil && repo.DefaultBranch != *opts.DefaultBranch && (repo.IsEmpty || ctx.Repo.GitRepo.IsBranchExist(*opts.DefaultBranch)) {
 		if !repo.IsEmpty {
@@ -725,6 +746,9 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		ctx.Error(http.StatusInternalServerError, "UpdateRepository", err)
 		return err
 	}
+	if visibilityChanged {
+		notification.NotifyChangeRepositoryVisibility(ctx.User, repo)
+	}
 
 	log.Trace("Repository basic settings updated: %s/%s", owner.Name, repo.Name)
 	return nil
@@ -751,14 +775,20 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL format", err)
 				return err
 			}
+			if opts.ExternalTracker.ExternalTrackerStyle == markup.IssueNameStyleRegexp && !validation.IsValidExternalTrackerRegexpPattern(opts.ExternalTracker.ExternalTrackerRegexpPattern) {
+				err := fmt.Errorf("External tracker regular expression pattern not valid")
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker regular expression pattern", err)
+				return err
+			}
 
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalTracker,
 				Config: &models.ExternalTrackerConfig{
-					ExternalTrackerURL:    opts.ExternalTracker.ExternalTrackerURL,
-					ExternalTrackerFormat: opts.ExternalTracker.ExternalTrackerFormat,
-					ExternalTrackerStyle:  opts.ExternalTracker.ExternalTrackerStyle,
+					ExternalTrackerURL:           opts.ExternalTracker.ExternalTrackerURL,
+					ExternalTrackerFormat:        opts.ExternalTracker.ExternalTrackerFormat,
+					ExternalTrackerStyle:         opts.ExternalTracker.ExternalTrackerStyle,
+					ExternalTrackerRegexpPattern: opts.ExternalTracker.ExternalTrackerRegexpPattern,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
@@ -771,6 +801,7 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					EnableVoting:                     opts.InternalTracker.EnableIssueVoting,
 				}
 			} else if unit, err := repo.GetUnit(models.UnitTypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -907,6 +938,27 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 		}
 	}
 
+	if opts.ReadmePath != nil || opts.ShowWikiHome != nil {
+		unit, err := repo.GetUnit(models.UnitTypeCode)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "GetUnit", fmt.Errorf("code unit is not enabled"))
+			return err
+		}
+		config := unit.CodeConfig()
+		if opts.ReadmePath != nil {
+			config.ReadmePath = models.CleanHomeReadmePath(*opts.ReadmePath)
+		}
+		if opts.ShowWikiHome != nil {
+			config.ShowWikiHome = *opts.ShowWikiHome
+		}
+
+		units = append(units, models.RepoUnit{
+			RepoID: repo.ID,
+			Type:   models.UnitTypeCode,
+			Config: config,
+		})
+	}
+
 	if err := models.UpdateRepositoryUnits(repo, units, deleteUnitTypes); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
 		return err
@@ -1050,3 +1102,35 @@ func GetIssueTemplates(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, ctx.IssueTemplatesFromDefaultBranch())
 }
+
+// GetPullRequestTemplate returns the pull request template of a repository
+func GetPullRequestTemplate(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pull_request_template repository repoGetPullRequestTemplate
+	// ---
+	// summary: Get the pull request template of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueTemplate"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	it := ctx.PullRequestTemplateFromDefaultBranch()
+	if it == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, it)
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetReviewerStats list the review statistics of each reviewer of a repository
+func GetReviewerStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/reviewers repository repoGetReviewerStats
+	// ---
+	// summary: Get the review statistics of each reviewer of a repository, the most active first
+	// description: The statistics are updated periodically by a background job and may miss the latest reviews.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewerStatsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	stats, err := models.GetReviewerStats([]int64{ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewerStats", err)
+		return
+	}
+
+	apiStats := make([]*api.ReviewerStats, len(stats))
+	for i := range stats {
+		apiStats[i] = convert.ToReviewerStats(stats[i], ctx.User)
+	}
+	ctx.JSON(http.StatusOK, &apiStats)
+}
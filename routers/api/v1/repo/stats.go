@@ -0,0 +1,242 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// maxDirectoryStatsDepth is the maximum depth of the directories of the directory stats
+const maxDirectoryStatsDepth = 5
+
+// getCommitStats returns the changes of the commits of the default branch,
+// it responds with 204 if the repository is empty
+func getCommitStats(ctx *context.APIContext) ([]*git.CommitStat, bool) {
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Status(http.StatusNoContent)
+		return nil, false
+	}
+	commits, err := repo_service.GetCommitStats(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitStats", err)
+		return nil, false
+	}
+	return commits, true
+}
+
+// GetContributorStats returns the weekly activity of the contributors of the default branch
+func GetContributorStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/contributors repository repoGetContributorStats
+	// ---
+	// summary: Get the weekly additions, deletions and commits of each contributor of the default branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContributorStatsList"
+	//   "204":
+	//     description: the repository is empty
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	commits, ok := getCommitStats(ctx)
+	if !ok {
+		return
+	}
+
+	contributors, err := repo_service.GetContributorStats(commits, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetContributorStats", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, contributors)
+}
+
+// GetCommitActivity returns the daily number of commits of the default branch during the last year
+func GetCommitActivity(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/commit_activity repository repoGetCommitActivity
+	// ---
+	// summary: Get the number of commits of the default branch of each day of the last year, grouped by week
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitActivityList"
+	//   "204":
+	//     description: the repository is empty
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	commits, ok := getCommitStats(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, repo_service.GetCommitActivity(commits, time.Now()))
+}
+
+// GetCodeFrequency returns the weekly additions and deletions of the default branch
+func GetCodeFrequency(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/code_frequency repository repoGetCodeFrequency
+	// ---
+	// summary: Get the weekly additions and deletions of the default branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeFrequency"
+	//   "204":
+	//     description: the repository is empty
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	commits, ok := getCommitStats(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, repo_service.GetCodeFrequency(commits))
+}
+
+// GetDirectoryStats returns the size and the history intensity of the directories of a repository
+func GetDirectoryStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/directories repository repoGetDirectoryStats
+	// ---
+	// summary: Get the size and the number of recent commits of the directories of a repository, e.g. to choose sparse-checkout patterns
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: directory to get the stats of the subdirectories of, defaults to the root directory
+	//   type: string
+	// - name: depth
+	//   in: query
+	//   description: number of directory levels below path to report, between 1 and 5, defaults to 1
+	//   type: integer
+	// - name: since
+	//   in: query
+	//   description: only count commits after this date, defaults to one year ago
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DirectoryStats"
+	//   "204":
+	//     description: the repository is empty
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	depth := 1
+	if ctx.Query("depth") != "" {
+		depth = ctx.QueryInt("depth")
+		if depth < 1 || depth > maxDirectoryStatsDepth {
+			ctx.Error(http.StatusUnprocessableEntity, "", "depth must be between 1 and 5")
+			return
+		}
+	}
+
+	_, sinceUnix, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+	since := time.Now().AddDate(-1, 0, 0)
+	if sinceUnix != 0 {
+		since = time.Unix(sinceUnix, 0)
+	}
+
+	ref := ctx.QueryTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	stats, err := ctx.Repo.GitRepo.GetDirectoryStats(commit.ID.String(), ctx.QueryTrim("path"), depth, since)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDirectoryStats", err)
+		return
+	}
+
+	directories := make([]*api.DirectoryStat, len(stats))
+	for i, stat := range stats {
+		directories[i] = &api.DirectoryStat{
+			Path:        stat.Path,
+			Size:        stat.Size,
+			FileCount:   stat.FileCount,
+			CommitCount: stat.CommitCount,
+		}
+	}
+	ctx.JSON(http.StatusOK, &api.DirectoryStats{
+		SHA:         commit.ID.String(),
+		Since:       since.UTC(),
+		Directories: directories,
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -260,3 +261,293 @@ func DeleteTag(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListTagProtection lists tag protections for a repo
+func ListTagProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tag_protections repository repoListTagProtection
+	// ---
+	// summary: List tag protections for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtectionList"
+
+	pts, err := ctx.Repo.Repository.GetProtectedTags()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedTags", err)
+		return
+	}
+	apiPts := make([]*api.TagProtection, len(pts))
+	for i := range pts {
+		apiPts[i] = convert.ToTagProtection(pts[i])
+	}
+
+	ctx.JSON(http.StatusOK, apiPts)
+}
+
+// GetTagProtection gets a tag protection
+func GetTagProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tag_protections/{id} repository repoGetTagProtection
+	// ---
+	// summary: Get a specific tag protection for the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the tag protection
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pt := getTagProtectionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToTagProtection(pt))
+}
+
+// CreateTagProtection creates a tag protection for a repo
+func CreateTagProtection(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/tag_protections repository repoCreateTagProtection
+	// ---
+	// summary: Create a tag protection for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateTagProtectionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/TagProtection"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateTagProtectionOption)
+
+	pt := &models.ProtectedTag{
+		RepoID:      ctx.Repo.Repository.ID,
+		NamePattern: strings.TrimSpace(form.NamePattern),
+	}
+	if !setTagProtectionAllowlists(ctx, pt, form.WhitelistUsernames, form.WhitelistTeams) {
+		return
+	}
+
+	if err := models.InsertProtectedTag(pt); err != nil {
+		ctx.Error(http.StatusInternalServerError, "InsertProtectedTag", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToTagProtection(pt))
+}
+
+// EditTagProtection edits a tag protection for a repo
+func EditTagProtection(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/tag_protections/{id} repository repoEditTagProtection
+	// ---
+	// summary: Edit a tag protection for a repository. Only fields that are set will be changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the tag protection
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditTagProtectionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pt := getTagProtectionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditTagProtectionOption)
+
+	if form.NamePattern != nil {
+		pt.NamePattern = strings.TrimSpace(*form.NamePattern)
+	}
+	whitelistUsernames, err := models.GetUserNamesByIDs(pt.AllowlistUserIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserNamesByIDs", err)
+		return
+	}
+	if form.WhitelistUsernames != nil {
+		whitelistUsernames = form.WhitelistUsernames
+	}
+	whitelistTeams, err := models.GetTeamNamesByID(pt.AllowlistTeamIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTeamNamesByID", err)
+		return
+	}
+	if form.WhitelistTeams != nil {
+		whitelistTeams = form.WhitelistTeams
+	}
+	if !setTagProtectionAllowlists(ctx, pt, whitelistUsernames, whitelistTeams) {
+		return
+	}
+
+	if err := models.UpdateProtectedTag(pt); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateProtectedTag", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToTagProtection(pt))
+}
+
+// DeleteTagProtection deletes a tag protection for a repo
+func DeleteTagProtection(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/tag_protections/{id} repository repoDeleteTagProtection
+	// ---
+	// summary: Delete a specific tag protection for the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the tag protection
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pt := getTagProtectionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProtectedTag(pt); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteProtectedTag", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getTagProtectionByParams(ctx *context.APIContext) *models.ProtectedTag {
+	pt, err := models.GetProtectedTagByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedTagByID", err)
+		return nil
+	}
+	if pt == nil || pt.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return pt
+}
+
+// setTagProtectionAllowlists validates the name pattern of pt and resolves the allowed users and teams
+func setTagProtectionAllowlists(ctx *context.APIContext, pt *models.ProtectedTag, usernames, teams []string) bool {
+	if pt.NamePattern == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "NamePattern", "name pattern must not be empty")
+		return false
+	}
+	if err := pt.EnsureCompiledPattern(); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "EnsureCompiledPattern", err)
+		return false
+	}
+
+	var err error
+	pt.AllowlistUserIDs, err = models.GetUserIDsByNames(usernames, false)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
+			return false
+		}
+		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
+		return false
+	}
+
+	pt.AllowlistTeamIDs = nil
+	if ctx.Repo.Owner.IsOrganization() {
+		pt.AllowlistTeamIDs, err = models.GetTeamIDsByNames(ctx.Repo.Owner.ID, teams, false)
+		if err != nil {
+			if models.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
+				return false
+			}
+			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
+			return false
+		}
+	} else if len(teams) > 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "WhitelistTeams", "teams can only be allowed on repositories owned by an organization")
+		return false
+	}
+	return true
+}
@@ -120,3 +120,168 @@ func Transfer(ctx *context.APIContext) {
 	log.Trace("Repository transferred: %s -> %s", ctx.Repo.Repository.FullName(), newOwner.Name)
 	ctx.JSON(http.StatusAccepted, convert.ToRepo(ctx.Repo.Repository, models.AccessModeAdmin))
 }
+
+// AcceptTransfer accept a repo transfer
+func AcceptTransfer(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/transfer/accept repository acceptRepoTransfer
+	// ---
+	// summary: Accept a repo transfer
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to transfer
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to transfer
+	//   type: string
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repoTransfer := getAcceptableRepoTransfer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.TransferOwnership(repoTransfer.Doer, repoTransfer.Recipient, ctx.Repo.Repository, repoTransfer.Teams); err != nil {
+		if models.IsErrRepoAlreadyExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "TransferOwnership", err)
+			return
+		}
+		ctx.InternalServerError(err)
+		return
+	}
+
+	// the recipient may have had no access to the repository before the transfer
+	repo, err := models.GetRepositoryByID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	perm, err := models.GetUserRepoPermission(repo, ctx.User)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	log.Trace("Repository transfer accepted: %s -> %s", repo.FullName(), repoTransfer.Recipient.Name)
+	ctx.JSON(http.StatusAccepted, convert.ToRepo(repo, perm.AccessMode))
+}
+
+// RejectTransfer reject a repo transfer
+func RejectTransfer(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/transfer/reject repository rejectRepoTransfer
+	// ---
+	// summary: Reject a repo transfer
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to transfer
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to transfer
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getAcceptableRepoTransfer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.CancelRepositoryTransfer(ctx.Repo.Repository); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	log.Trace("Repository transfer rejected: %s", ctx.Repo.Repository.FullName())
+	ctx.Status(http.StatusNoContent)
+}
+
+// CancelTransfer cancel a repo transfer started by the owner of the repo
+func CancelTransfer(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/transfer repository cancelRepoTransfer
+	// ---
+	// summary: Cancel a pending repo transfer
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to transfer
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to transfer
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if _, err := models.GetPendingRepositoryTransfer(ctx.Repo.Repository); err != nil {
+		if models.IsErrNoPendingTransfer(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.InternalServerError(err)
+		return
+	}
+
+	if err := models.CancelRepositoryTransfer(ctx.Repo.Repository); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	log.Trace("Repository transfer cancelled: %s", ctx.Repo.Repository.FullName())
+	ctx.Status(http.StatusNoContent)
+}
+
+// getAcceptableRepoTransfer returns the pending transfer of the repo if the signed in user can accept or reject it,
+// otherwise it writes the error to ctx
+func getAcceptableRepoTransfer(ctx *context.APIContext) *models.RepoTransfer {
+	repoTransfer, err := models.GetPendingRepositoryTransfer(ctx.Repo.Repository)
+	if err != nil {
+		if models.IsErrNoPendingTransfer(err) {
+			ctx.NotFound()
+			return nil
+		}
+		ctx.InternalServerError(err)
+		return nil
+	}
+
+	if err := repoTransfer.LoadAttributes(); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+
+	if !repoTransfer.CanUserAcceptTransfer(ctx.User) {
+		ctx.Error(http.StatusForbidden, "CanUserAcceptTransfer", "user does not have the permission to accept or reject the transfer")
+		return nil
+	}
+	return repoTransfer
+}
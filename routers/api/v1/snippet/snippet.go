@@ -0,0 +1,470 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/user"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	snippet_service "code.gitea.io/gitea/services/snippet"
+)
+
+// getSnippetByParams returns the snippet by ":uuid", hiding private snippets
+// from everyone but their owner and site admins.
+func getSnippetByParams(ctx *context.APIContext) *models.Snippet {
+	s, err := models.GetSnippetByUUID(ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrSnippetNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSnippetByUUID", err)
+		}
+		return nil
+	}
+	if !s.CanRead(ctx.User) {
+		ctx.NotFound()
+		return nil
+	}
+	if err = s.LoadOwner(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadOwner", err)
+		return nil
+	}
+	return s
+}
+
+// toAPISnippet converts the snippet with its files, their content is only included if asked for
+func toAPISnippet(ctx *context.APIContext, s *models.Snippet, withContent bool) *api.Snippet {
+	files, err := snippet_service.GetFiles(s)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFiles", err)
+		return nil
+	}
+
+	apiFiles := make([]*api.SnippetFile, len(files))
+	for i, f := range files {
+		apiFiles[i] = &api.SnippetFile{
+			Name:   f.Name,
+			Size:   int64(len(f.Content)),
+			RawURL: s.HTMLURL() + "/raw/" + url.PathEscape(f.Name),
+		}
+		if withContent {
+			apiFiles[i].Content = string(f.Content)
+		}
+	}
+	return convert.ToSnippet(s, apiFiles, ctx.User)
+}
+
+func toServiceFiles(opts []*api.SnippetFileOption) []*snippet_service.File {
+	files := make([]*snippet_service.File, len(opts))
+	for i, f := range opts {
+		files[i] = &snippet_service.File{Name: f.Name, Content: []byte(f.Content)}
+	}
+	return files
+}
+
+func listSnippets(ctx *context.APIContext, ownerID int64) {
+	opts := utils.GetListOptions(ctx)
+	snippets, count, err := models.SearchSnippets(&models.SearchSnippetOptions{
+		ListOptions: opts,
+		OwnerID:     ownerID,
+		Actor:       ctx.User,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchSnippets", err)
+		return
+	}
+
+	apiSnippets := make([]*api.Snippet, len(snippets))
+	for i, s := range snippets {
+		if err = s.LoadOwner(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadOwner", err)
+			return
+		}
+		if apiSnippets[i] = toAPISnippet(ctx, s, false); ctx.Written() {
+			return
+		}
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiSnippets)
+}
+
+// ListSnippets lists the public snippets and those of the authenticated user
+func ListSnippets(ctx *context.APIContext) {
+	// swagger:operation GET /snippets snippet snippetList
+	// ---
+	// summary: List the public snippets and the snippets of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SnippetList"
+
+	listSnippets(ctx, 0)
+}
+
+// ListUserSnippets lists the snippets of the given user
+func ListUserSnippets(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/snippets user userListSnippets
+	// ---
+	// summary: List the snippets of the given user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SnippetList"
+
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	listSnippets(ctx, u.ID)
+}
+
+// CreateSnippet creates a snippet for the authenticated user
+func CreateSnippet(ctx *context.APIContext) {
+	// swagger:operation POST /snippets snippet snippetCreate
+	// ---
+	// summary: Create a snippet
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateSnippetOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Snippet"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateSnippetOption)
+	visibility, _ := models.SnippetVisibilityFromString(form.Visibility)
+	s, err := snippet_service.CreateSnippet(ctx.User, form.Description, visibility, toServiceFiles(form.Files))
+	if err != nil {
+		if snippet_service.IsErrInvalidFile(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateSnippet", err)
+		}
+		return
+	}
+
+	apiSnippet := toAPISnippet(ctx, s, true)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiSnippet)
+}
+
+// GetSnippet gets a snippet with the content of its files
+func GetSnippet(ctx *context.APIContext) {
+	// swagger:operation GET /snippets/{uuid} snippet snippetGet
+	// ---
+	// summary: Get a snippet with the content of its files
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Snippet"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	apiSnippet := toAPISnippet(ctx, s, true)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, apiSnippet)
+}
+
+// EditSnippet edits a snippet
+func EditSnippet(ctx *context.APIContext) {
+	// swagger:operation PATCH /snippets/{uuid} snippet snippetEdit
+	// ---
+	// summary: Edit a snippet, the given files replace all its files
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSnippetOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Snippet"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditSnippetOption)
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !s.CanWrite(ctx.User) {
+		ctx.Error(http.StatusForbidden, "", "only the owner can edit a snippet")
+		return
+	}
+
+	if len(form.Files) > 0 {
+		if err := snippet_service.UpdateSnippetFiles(ctx.User, s, toServiceFiles(form.Files)); err != nil {
+			if snippet_service.IsErrInvalidFile(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "UpdateSnippetFiles", err)
+			}
+			return
+		}
+	}
+	if form.Description != nil {
+		s.Description = *form.Description
+	}
+	if form.Visibility != nil && len(*form.Visibility) > 0 {
+		s.Visibility, _ = models.SnippetVisibilityFromString(*form.Visibility)
+	}
+	if err := models.UpdateSnippet(s); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateSnippet", err)
+		return
+	}
+
+	apiSnippet := toAPISnippet(ctx, s, true)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, apiSnippet)
+}
+
+// DeleteSnippet deletes a snippet
+func DeleteSnippet(ctx *context.APIContext) {
+	// swagger:operation DELETE /snippets/{uuid} snippet snippetDelete
+	// ---
+	// summary: Delete a snippet
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !s.CanWrite(ctx.User) {
+		ctx.Error(http.StatusForbidden, "", "only the owner can delete a snippet")
+		return
+	}
+	if err := snippet_service.DeleteSnippet(s); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteSnippet", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListComments lists the comments of a snippet
+func ListComments(ctx *context.APIContext) {
+	// swagger:operation GET /snippets/{uuid}/comments snippet snippetListComments
+	// ---
+	// summary: List the comments of a snippet
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SnippetCommentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	comments, err := models.GetSnippetComments(s.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSnippetComments", err)
+		return
+	}
+
+	apiComments := make([]*api.SnippetComment, len(comments))
+	for i, c := range comments {
+		if err = c.LoadPoster(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadPoster", err)
+			return
+		}
+		apiComments[i] = convert.ToSnippetComment(c, ctx.User)
+	}
+
+	ctx.Header().Set("X-Total-Count", strconv.Itoa(s.NumComments))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+	ctx.JSON(http.StatusOK, &apiComments)
+}
+
+// CreateComment comments on a snippet
+func CreateComment(ctx *context.APIContext) {
+	// swagger:operation POST /snippets/{uuid}/comments snippet snippetCreateComment
+	// ---
+	// summary: Comment on a snippet
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateSnippetCommentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SnippetComment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.CreateSnippetCommentOption)
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	c, err := models.CreateSnippetComment(s, ctx.User, form.Body)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateSnippetComment", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToSnippetComment(c, ctx.User))
+}
+
+// DeleteComment deletes a comment of a snippet
+func DeleteComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /snippets/{uuid}/comments/{id} snippet snippetDeleteComment
+	// ---
+	// summary: Delete a comment of a snippet
+	// parameters:
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the snippet
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getSnippetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	c, err := models.GetSnippetCommentByID(s.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrSnippetCommentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSnippetCommentByID", err)
+		}
+		return
+	}
+	// the poster and the owner of the snippet can delete a comment
+	if c.PosterID != ctx.User.ID && !s.CanWrite(ctx.User) {
+		ctx.Error(http.StatusForbidden, "", "only the poster or the snippet owner can delete a comment")
+		return
+	}
+
+	if err = models.DeleteSnippetComment(c); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteSnippetComment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
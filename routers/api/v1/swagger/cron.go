@@ -14,3 +14,10 @@ type swaggerResponseCronList struct {
 	// in:body
 	Body []api.Cron `json:"body"`
 }
+
+// Cron
+// swagger:response Cron
+type swaggerResponseCron struct {
+	// in:body
+	Body api.Cron `json:"body"`
+}
@@ -64,6 +64,34 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// Project
+// swagger:response Project
+type swaggerResponseProject struct {
+	// in:body
+	Body api.Project `json:"body"`
+}
+
+// ProjectList
+// swagger:response ProjectList
+type swaggerResponseProjectList struct {
+	// in:body
+	Body []api.Project `json:"body"`
+}
+
+// ProjectBoard
+// swagger:response ProjectBoard
+type swaggerResponseProjectBoard struct {
+	// in:body
+	Body api.ProjectBoard `json:"body"`
+}
+
+// ProjectBoardList
+// swagger:response ProjectBoardList
+type swaggerResponseProjectBoardList struct {
+	// in:body
+	Body []api.ProjectBoard `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
@@ -92,6 +120,13 @@ type swaggerIssueTemplates struct {
 	Body []api.IssueTemplate `json:"body"`
 }
 
+// IssueTemplate
+// swagger:response IssueTemplate
+type swaggerIssueTemplate struct {
+	// in:body
+	Body api.IssueTemplate `json:"body"`
+}
+
 // StopWatch
 // swagger:response StopWatch
 type swaggerResponseStopWatch struct {
@@ -106,6 +141,20 @@ type swaggerResponseStopWatchList struct {
 	Body []api.StopWatch `json:"body"`
 }
 
+// AssigneeWorkloadList
+// swagger:response AssigneeWorkloadList
+type swaggerResponseAssigneeWorkloadList struct {
+	// in:body
+	Body []api.AssigneeWorkload `json:"body"`
+}
+
+// IssueReferenceGraph
+// swagger:response IssueReferenceGraph
+type swaggerResponseIssueReferenceGraph struct {
+	// in:body
+	Body api.IssueReferenceGraph `json:"body"`
+}
+
 // Reaction
 // swagger:response Reaction
 type swaggerReaction struct {
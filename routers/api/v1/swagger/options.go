@@ -27,6 +27,8 @@ type swaggerParameterBodies struct {
 	CreateHookOption api.CreateHookOption
 	// in:body
 	EditHookOption api.EditHookOption
+	// in:body
+	ReplayRepoEventOption api.ReplayRepoEventOption
 
 	// in:body
 	EditGitHookOption api.EditGitHookOption
@@ -62,6 +64,17 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditMilestoneOption api.EditMilestoneOption
 
+	// in:body
+	CreateProjectOption api.CreateProjectOption
+	// in:body
+	EditProjectOption api.EditProjectOption
+	// in:body
+	CreateProjectBoardOption api.CreateProjectBoardOption
+	// in:body
+	EditProjectBoardOption api.EditProjectBoardOption
+	// in:body
+	MoveProjectIssueOption api.MoveProjectIssueOption
+
 	// in:body
 	CreateOrgOption api.CreateOrgOption
 	// in:body
@@ -107,12 +120,21 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditUserOption api.EditUserOption
 
+	// in:body
+	UnadoptedRepositoriesOption api.UnadoptedRepositoriesOption
+
+	// in:body
+	UpdateRepoAvatarOption api.UpdateRepoAvatarOption
+
 	// in:body
 	MigrateRepoForm forms.MigrateRepoForm
 
 	// in:body
 	EditAttachmentOptions api.EditAttachmentOptions
 
+	// in:body
+	CreateAttachmentUploadOption api.CreateAttachmentUploadOption
+
 	// in:body
 	CreateFileOptions api.CreateFileOptions
 
@@ -140,6 +162,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditBranchProtectionOption api.EditBranchProtectionOption
 
+	// in:body
+	CreateTagProtectionOption api.CreateTagProtectionOption
+
+	// in:body
+	EditTagProtectionOption api.EditTagProtectionOption
+
+	// in:body
+	UnlockRepoLFSLocksOption api.UnlockRepoLFSLocksOption
+
 	// in:body
 	CreateOAuth2ApplicationOptions api.CreateOAuth2ApplicationOptions
 
@@ -158,6 +189,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	MigrateRepoOptions api.MigrateRepoOptions
 
+	// in:body
+	CompareRemoteOption api.CompareRemoteOption
+
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
 
@@ -166,4 +200,28 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UserSettingsOptions api.UserSettingsOptions
+
+	// in:body
+	CreateStarListOption api.CreateStarListOption
+	// in:body
+	EditStarListOption api.EditStarListOption
+
+	// in:body
+	CreateSnippetOption api.CreateSnippetOption
+	// in:body
+	EditSnippetOption api.EditSnippetOption
+	// in:body
+	CreateSnippetCommentOption api.CreateSnippetCommentOption
+
+	// in:body
+	CreateOrgAccessTokenOption api.CreateOrgAccessTokenOption
+
+	// in:body
+	CreateRepoAccessRequestOption api.CreateRepoAccessRequestOption
+
+	// in:body
+	ApproveRepoAccessRequestOption api.ApproveRepoAccessRequestOption
+
+	// in:body
+	CreateDeploymentOption api.CreateDeploymentOption
 }
@@ -35,3 +35,31 @@ type swaggerResponseTeamList struct {
 	// in:body
 	Body []api.Team `json:"body"`
 }
+
+// OrgAccessToken
+// swagger:response OrgAccessToken
+type swaggerResponseOrgAccessToken struct {
+	// in:body
+	Body api.OrgAccessToken `json:"body"`
+}
+
+// OrgAccessTokenList
+// swagger:response OrgAccessTokenList
+type swaggerResponseOrgAccessTokenList struct {
+	// in:body
+	Body []api.OrgAccessToken `json:"body"`
+}
+
+// CLA
+// swagger:response CLA
+type swaggerResponseCLA struct {
+	// in:body
+	Body api.CLA `json:"body"`
+}
+
+// CLASignatureStatus
+// swagger:response CLASignatureStatus
+type swaggerResponseCLASignatureStatus struct {
+	// in:body
+	Body api.CLASignatureStatus `json:"body"`
+}
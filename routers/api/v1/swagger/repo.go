@@ -22,6 +22,13 @@ type swaggerResponseRepositoryList struct {
 	Body []api.Repository `json:"body"`
 }
 
+// ForkNetwork
+// swagger:response ForkNetwork
+type swaggerResponseForkNetwork struct {
+	// in:body
+	Body []api.ForkNetworkEntry `json:"body"`
+}
+
 // Branch
 // swagger:response Branch
 type swaggerResponseBranch struct {
@@ -50,6 +57,27 @@ type swaggerResponseBranchProtectionList struct {
 	Body []api.BranchProtection `json:"body"`
 }
 
+// TagProtection
+// swagger:response TagProtection
+type swaggerResponseTagProtection struct {
+	// in:body
+	Body api.TagProtection `json:"body"`
+}
+
+// RepoLFSLockList
+// swagger:response RepoLFSLockList
+type swaggerResponseRepoLFSLockList struct {
+	// in:body
+	Body []api.RepoLFSLock `json:"body"`
+}
+
+// TagProtectionList
+// swagger:response TagProtectionList
+type swaggerResponseTagProtectionList struct {
+	// in:body
+	Body []api.TagProtection `json:"body"`
+}
+
 // TagList
 // swagger:response TagList
 type swaggerResponseTagList struct {
@@ -99,6 +127,41 @@ type swaggerResponseHookList struct {
 	Body []api.Hook `json:"body"`
 }
 
+// HookDelivery
+// swagger:response HookDelivery
+type swaggerResponseHookDelivery struct {
+	// in:body
+	Body api.HookDelivery `json:"body"`
+}
+
+// HookDeliveryList
+// swagger:response HookDeliveryList
+type swaggerResponseHookDeliveryList struct {
+	// in:body
+	Body []api.HookDelivery `json:"body"`
+}
+
+// RepoEvent
+// swagger:response RepoEvent
+type swaggerResponseRepoEvent struct {
+	// in:body
+	Body api.RepoEvent `json:"body"`
+}
+
+// GitOperationList
+// swagger:response GitOperationList
+type swaggerResponseGitOperationList struct {
+	// in:body
+	Body []api.GitOperation `json:"body"`
+}
+
+// RepoEventList
+// swagger:response RepoEventList
+type swaggerResponseRepoEventList struct {
+	// in:body
+	Body []api.RepoEvent `json:"body"`
+}
+
 // GitHook
 // swagger:response GitHook
 type swaggerResponseGitHook struct {
@@ -155,6 +218,27 @@ type swaggerResponsePullReviewList struct {
 	Body []api.PullReview `json:"body"`
 }
 
+// PullReviewChecklist
+// swagger:response PullReviewChecklist
+type swaggerResponsePullReviewChecklist struct {
+	// in:body
+	Body []string `json:"body"`
+}
+
+// PullAudit
+// swagger:response PullAudit
+type swaggerResponsePullAudit struct {
+	// in:body
+	Body api.PullAudit `json:"body"`
+}
+
+// PullRequestCLAStatus
+// swagger:response PullRequestCLAStatus
+type swaggerResponsePullRequestCLAStatus struct {
+	// in:body
+	Body api.PullRequestCLAStatus `json:"body"`
+}
+
 // PullComment
 // swagger:response PullReviewComment
 type swaggerPullReviewComment struct {
@@ -211,6 +295,13 @@ type swaggerResponseAttachment struct {
 	Body api.Attachment `json:"body"`
 }
 
+// AttachmentUpload
+// swagger:response AttachmentUpload
+type swaggerResponseAttachmentUpload struct {
+	// in: body
+	Body api.AttachmentUpload `json:"body"`
+}
+
 // GitTreeResponse
 // swagger:response GitTreeResponse
 type swaggerGitTreeResponse struct {
@@ -310,9 +401,93 @@ type swaggerLanguageStatistics struct {
 	Body map[string]int64 `json:"body"`
 }
 
+// ContributorStatsList
+// swagger:response ContributorStatsList
+type swaggerContributorStatsList struct {
+	// in: body
+	Body []api.ContributorStats `json:"body"`
+}
+
+// ReviewerStatsList
+// swagger:response ReviewerStatsList
+type swaggerReviewerStatsList struct {
+	// in: body
+	Body []api.ReviewerStats `json:"body"`
+}
+
+// DirectoryStats
+// swagger:response DirectoryStats
+type swaggerDirectoryStats struct {
+	// in: body
+	Body api.DirectoryStats `json:"body"`
+}
+
+// CommitActivityList
+// swagger:response CommitActivityList
+type swaggerCommitActivityList struct {
+	// in: body
+	Body []api.CommitActivity `json:"body"`
+}
+
+// CodeFrequency is a list of [week, additions, deletions], the deletions are negative
+// swagger:response CodeFrequency
+type swaggerCodeFrequency struct {
+	// in: body
+	Body [][]int64 `json:"body"`
+}
+
 // CombinedStatus
 // swagger:response CombinedStatus
 type swaggerCombinedStatus struct {
 	// in: body
 	Body api.CombinedStatus `json:"body"`
 }
+
+// Compare
+// swagger:response Compare
+type swaggerCompare struct {
+	// in: body
+	Body api.Compare `json:"body"`
+}
+
+// FileBlame
+// swagger:response FileBlame
+type swaggerFileBlame struct {
+	// in: body
+	Body api.FileBlame `json:"body"`
+}
+
+// UnadoptedRepositoryResultList
+// swagger:response UnadoptedRepositoryResultList
+type swaggerResponseUnadoptedRepositoryResultList struct {
+	// in:body
+	Body []api.UnadoptedRepositoryResult `json:"body"`
+}
+
+// RepoAccessRequest
+// swagger:response RepoAccessRequest
+type swaggerResponseRepoAccessRequest struct {
+	// in:body
+	Body api.RepoAccessRequest `json:"body"`
+}
+
+// RepoAccessRequestList
+// swagger:response RepoAccessRequestList
+type swaggerResponseRepoAccessRequestList struct {
+	// in:body
+	Body []api.RepoAccessRequest `json:"body"`
+}
+
+// Deployment
+// swagger:response Deployment
+type swaggerResponseDeployment struct {
+	// in:body
+	Body api.Deployment `json:"body"`
+}
+
+// DeploymentList
+// swagger:response DeploymentList
+type swaggerResponseDeploymentList struct {
+	// in:body
+	Body []api.Deployment `json:"body"`
+}
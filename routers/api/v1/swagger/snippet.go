@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Snippet
+// swagger:response Snippet
+type swaggerResponseSnippet struct {
+	// in:body
+	Body api.Snippet `json:"body"`
+}
+
+// SnippetList
+// swagger:response SnippetList
+type swaggerResponseSnippetList struct {
+	// in:body
+	Body []api.Snippet `json:"body"`
+}
+
+// SnippetComment
+// swagger:response SnippetComment
+type swaggerResponseSnippetComment struct {
+	// in:body
+	Body api.SnippetComment `json:"body"`
+}
+
+// SnippetCommentList
+// swagger:response SnippetCommentList
+type swaggerResponseSnippetCommentList struct {
+	// in:body
+	Body []api.SnippetComment `json:"body"`
+}
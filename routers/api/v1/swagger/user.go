@@ -49,3 +49,24 @@ type swaggerResponseUserSettings struct {
 	// in:body
 	Body []api.UserSettings `json:"body"`
 }
+
+// SizeQuota
+// swagger:response SizeQuota
+type swaggerResponseSizeQuota struct {
+	// in:body
+	Body api.SizeQuota `json:"body"`
+}
+
+// StarList
+// swagger:response StarList
+type swaggerResponseStarList struct {
+	// in:body
+	Body api.StarList `json:"body"`
+}
+
+// StarListList
+// swagger:response StarListList
+type swaggerResponseStarListList struct {
+	// in:body
+	Body []api.StarList `json:"body"`
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMyAccessRequests lists the requests of the authenticated user to access repositories
+func ListMyAccessRequests(ctx *context.APIContext) {
+	// swagger:operation GET /user/access_requests user userListAccessRequests
+	// ---
+	// summary: List the requests of the authenticated user to access private repositories, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: state
+	//   in: query
+	//   description: state of the requests, all of them by default
+	//   type: string
+	//   enum: [pending, approved, rejected, all]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAccessRequestList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListRepoAccessRequests(ctx, 0, ctx.User.ID)
+}
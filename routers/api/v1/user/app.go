@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -57,6 +58,9 @@ func ListAccessTokens(ctx *context.APIContext) {
 			Name:           tokens[i].Name,
 			TokenLastEight: tokens[i].TokenLastEight,
 		}
+		for _, scope := range tokens[i].Scopes() {
+			apiTokens[i].Scopes = append(apiTokens[i].Scopes, string(scope))
+		}
 	}
 	ctx.JSON(http.StatusOK, &apiTokens)
 }
@@ -85,17 +89,34 @@ func CreateAccessToken(ctx *context.APIContext) {
 	//     properties:
 	//       name:
 	//         type: string
+	//       scopes:
+	//         description: "\"write:status\" restricts the token to create and read commit statuses,
+	//           the token is not restricted if no scopes are given"
+	//         type: array
+	//         items:
+	//           type: string
+	//           enum: [write:status]
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/AccessToken"
 	//   "400":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.CreateAccessTokenOption)
 
+	for _, scope := range form.Scopes {
+		if !models.IsValidPersonalAccessTokenScope(scope) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid scope: %s", scope))
+			return
+		}
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: strings.Join(form.Scopes, ","),
 	}
 
 	exist, err := models.AccessTokenByNameExists(t)
@@ -117,6 +138,7 @@ func CreateAccessToken(ctx *context.APIContext) {
 		Token:          t.Token,
 		ID:             t.ID,
 		TokenLastEight: t.TokenLastEight,
+		Scopes:         form.Scopes,
 	})
 }
 
@@ -209,9 +231,11 @@ func CreateOauth2Application(ctx *context.APIContext) {
 	data := web.GetForm(ctx).(*api.CreateOAuth2ApplicationOptions)
 
 	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
-		Name:         data.Name,
-		UserID:       ctx.User.ID,
-		RedirectURIs: data.RedirectURIs,
+		Name:               data.Name,
+		UserID:             ctx.User.ID,
+		RedirectURIs:       data.RedirectURIs,
+		ConfidentialClient: data.ConfidentialClient,
+		EnableDeviceFlow:   data.EnableDeviceFlow,
 	})
 	if err != nil {
 		ctx.Error(http.StatusBadRequest, "", "error creating oauth2 application")
@@ -357,10 +381,12 @@ func UpdateOauth2Application(ctx *context.APIContext) {
 	data := web.GetForm(ctx).(*api.CreateOAuth2ApplicationOptions)
 
 	app, err := models.UpdateOAuth2Application(models.UpdateOAuth2ApplicationOptions{
-		Name:         data.Name,
-		UserID:       ctx.User.ID,
-		ID:           appID,
-		RedirectURIs: data.RedirectURIs,
+		Name:               data.Name,
+		UserID:             ctx.User.ID,
+		ID:                 appID,
+		RedirectURIs:       data.RedirectURIs,
+		ConfidentialClient: data.ConfidentialClient,
+		EnableDeviceFlow:   data.EnableDeviceFlow,
 	})
 	if err != nil {
 		if models.IsErrOauthClientIDInvalid(err) || models.IsErrOAuthApplicationNotFound(err) {
@@ -11,9 +11,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
-	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 // ListEmails list all of the authenticated user's email addresses
@@ -68,16 +68,8 @@ func AddEmail(ctx *context.APIContext) {
 		return
 	}
 
-	emails := make([]*models.EmailAddress, len(form.Emails))
-	for i := range form.Emails {
-		emails[i] = &models.EmailAddress{
-			UID:         ctx.User.ID,
-			Email:       form.Emails[i],
-			IsActivated: !setting.Service.RegisterEmailConfirm,
-		}
-	}
-
-	if err := models.AddEmailAddresses(emails); err != nil {
+	emails, err := user_service.AddEmailAddresses(ctx.User, form.Emails)
+	if err != nil {
 		if models.IsErrEmailAlreadyUsed(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", "Email address has been used: "+err.(models.ErrEmailAlreadyUsed).Email)
 		} else if models.IsErrEmailInvalid(err) {
@@ -230,11 +230,17 @@ func Follow(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
 
 	target := GetUserByParams(ctx)
 	if ctx.Written() {
 		return
 	}
+	if !models.HasOrgOrUserVisible(target, ctx.User) {
+		ctx.NotFound()
+		return
+	}
 	if err := models.FollowUser(ctx.User.ID, target.ID); err != nil {
 		ctx.Error(http.StatusInternalServerError, "FollowUser", err)
 		return
@@ -6,12 +6,14 @@ package user
 
 import (
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -37,6 +39,7 @@ func appendPrivateInformation(apiKey *api.PublicKey, key *models.PublicKey, defa
 		apiKey.KeyType = "unknown"
 	}
 	apiKey.ReadOnly = key.Mode == models.AccessModeRead
+	apiKey.CreatedFrom = key.CreatedFrom.String()
 	return apiKey, nil
 }
 
@@ -191,7 +194,16 @@ func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid
 		return
 	}
 
-	key, err := models.AddPublicKey(uid, form.Title, content, 0)
+	var expires timeutil.TimeStamp
+	if form.Expires != nil {
+		if !form.Expires.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "expires_at must be in the future")
+			return
+		}
+		expires = timeutil.TimeStamp(form.Expires.Unix())
+	}
+
+	key, err := models.AddPublicKey(uid, form.Title, content, 0, models.KeySourceAPI, expires)
 	if err != nil {
 		repo.HandleAddKeyError(ctx, err)
 		return
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
 // GetUserSettings returns user settings
@@ -73,6 +74,9 @@ func UpdateUserSettings(ctx *context.APIContext) {
 	if form.HideActivity != nil {
 		ctx.User.KeepActivityPrivate = *form.HideActivity
 	}
+	if form.HideActivityFromAnonymous != nil {
+		ctx.User.KeepActivitySignedInOnly = *form.HideActivityFromAnonymous
+	}
 
 	if err := models.UpdateUser(ctx.User); err != nil {
 		ctx.InternalServerError(err)
@@ -81,3 +85,16 @@ func UpdateUserSettings(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, convert.User2UserSettings(ctx.User))
 }
+
+// GetSizeQuota returns the size quota of the authenticated user
+func GetSizeQuota(ctx *context.APIContext) {
+	// swagger:operation GET /user/quota user userGetSizeQuota
+	// ---
+	// summary: Get the size quota of the repositories of the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SizeQuota"
+	utils.GetSizeQuota(ctx, ctx.User)
+}
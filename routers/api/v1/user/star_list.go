@@ -0,0 +1,386 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+func listStarLists(ctx *context.APIContext, u *models.User) {
+	includePrivate := ctx.User.ID == u.ID || ctx.User.IsAdmin
+	lists, err := models.GetStarListsByUserID(u.ID, includePrivate)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStarListsByUserID", err)
+		return
+	}
+
+	apiLists := make([]*api.StarList, len(lists))
+	for i := range lists {
+		apiLists[i] = convert.ToStarList(lists[i])
+	}
+	ctx.JSON(http.StatusOK, &apiLists)
+}
+
+// getStarListByParams returns the star list of the given owner by ":id",
+// hiding private lists from everyone but the owner and site admins.
+func getStarListByParams(ctx *context.APIContext, owner *models.User) *models.StarList {
+	list, err := models.GetStarListByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrStarListNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetStarListByID", err)
+		}
+		return nil
+	}
+	if list.UserID != owner.ID || (list.IsPrivate && ctx.User.ID != owner.ID && !ctx.User.IsAdmin) {
+		ctx.NotFound()
+		return nil
+	}
+	return list
+}
+
+// ListStarLists lists the star lists of the given user
+func ListStarLists(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/starlists user userListStarLists
+	// ---
+	// summary: List the star lists of the given user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarListList"
+
+	user := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	listStarLists(ctx, user)
+}
+
+// ListStarListRepos lists the repositories of a star list of the given user
+func ListStarListRepos(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/starlists/{id}/repos user userListStarListRepos
+	// ---
+	// summary: List the repositories in a star list of the given user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	user := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	list := getStarListByParams(ctx, user)
+	if ctx.Written() {
+		return
+	}
+
+	opts := utils.GetListOptions(ctx)
+	repos, count, err := models.SearchRepository(&models.SearchRepoOptions{
+		ListOptions: opts,
+		Actor:       ctx.User,
+		Private:     true,
+		StarListID:  list.ID,
+		OrderBy:     models.SearchOrderByAlphabetically,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepository", err)
+		return
+	}
+
+	apiRepos := make([]*api.Repository, len(repos))
+	for i := range repos {
+		access, err := models.AccessLevel(ctx.User, repos[i])
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos[i] = convert.ToRepo(repos[i], access)
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
+
+// ListMyStarLists lists the star lists of the authenticated user
+func ListMyStarLists(ctx *context.APIContext) {
+	// swagger:operation GET /user/starlists user userCurrentListStarLists
+	// ---
+	// summary: List the star lists of the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarListList"
+
+	listStarLists(ctx, ctx.User)
+}
+
+// CreateStarList creates a star list for the authenticated user
+func CreateStarList(ctx *context.APIContext) {
+	// swagger:operation POST /user/starlists user userCurrentCreateStarList
+	// ---
+	// summary: Create a star list for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateStarListOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/StarList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateStarListOption)
+	list := &models.StarList{
+		UserID:      ctx.User.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		IsPrivate:   form.Private,
+	}
+	if err := models.CreateStarList(list); err != nil {
+		if models.IsErrStarListAlreadyExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateStarList", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToStarList(list))
+}
+
+// GetMyStarList gets a star list of the authenticated user
+func GetMyStarList(ctx *context.APIContext) {
+	// swagger:operation GET /user/starlists/{id} user userCurrentGetStarList
+	// ---
+	// summary: Get a star list of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarListByParams(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStarList(list))
+}
+
+// EditStarList edits a star list of the authenticated user
+func EditStarList(ctx *context.APIContext) {
+	// swagger:operation PATCH /user/starlists/{id} user userCurrentEditStarList
+	// ---
+	// summary: Edit a star list of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditStarListOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StarList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditStarListOption)
+	list := getStarListByParams(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil && len(*form.Name) > 0 {
+		list.Name = *form.Name
+	}
+	if form.Description != nil {
+		list.Description = *form.Description
+	}
+	if form.Private != nil {
+		list.IsPrivate = *form.Private
+	}
+	if err := models.UpdateStarList(list); err != nil {
+		if models.IsErrStarListAlreadyExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateStarList", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStarList(list))
+}
+
+// DeleteStarList deletes a star list of the authenticated user
+func DeleteStarList(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/starlists/{id} user userCurrentDeleteStarList
+	// ---
+	// summary: Delete a star list of the authenticated user, the repositories in it stay starred
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarListByParams(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteStarList(list); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddStarListRepo adds a repository to a star list of the authenticated user
+func AddStarListRepo(ctx *context.APIContext) {
+	// swagger:operation PUT /user/starlists/{id}/repos/{owner}/{repo} user userCurrentAddStarListRepo
+	// ---
+	// summary: Add a repository to a star list of the authenticated user, starring it if needed
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarListByParams(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	if err := models.AddRepoToStarList(list, ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "AddRepoToStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveStarListRepo removes a repository from a star list of the authenticated user
+func RemoveStarListRepo(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/starlists/{id}/repos/{owner}/{repo} user userCurrentRemoveStarListRepo
+	// ---
+	// summary: Remove a repository from a star list of the authenticated user, it stays starred
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the star list
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	list := getStarListByParams(ctx, ctx.User)
+	if ctx.Written() {
+		return
+	}
+	if err := models.RemoveRepoFromStarList(list, ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemoveRepoFromStarList", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
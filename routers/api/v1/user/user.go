@@ -9,10 +9,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -33,6 +35,18 @@ func Search(ctx *context.APIContext) {
 	//   description: ID of the user to search for
 	//   type: integer
 	//   format: int64
+	// - name: fuzzy
+	//   in: query
+	//   description: match the keyword characters in order, allowing other characters in between
+	//   type: boolean
+	// - name: org
+	//   in: query
+	//   description: only return members of this organization
+	//   type: string
+	// - name: collaborator_of
+	//   in: query
+	//   description: only return collaborators of this repository, given as "owner/repo"
+	//   type: string
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -61,9 +75,18 @@ func Search(ctx *context.APIContext) {
 		Keyword:     strings.Trim(ctx.Query("q"), " "),
 		UID:         ctx.QueryInt64("uid"),
 		Type:        models.UserTypeIndividual,
+		IsFuzzy:     ctx.QueryBool("fuzzy"),
 		ListOptions: listOptions,
 	}
 
+	if err := applySearchFilters(ctx, opts); err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"ok":    false,
+			"error": err.Error(),
+		})
+		return
+	}
+
 	users, maxResults, err := models.SearchUsers(opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -83,6 +106,54 @@ func Search(ctx *context.APIContext) {
 	})
 }
 
+// applySearchFilters resolves the org and collaborator_of query parameters
+// into search options. Filters the doer is not allowed to see match nobody.
+func applySearchFilters(ctx *context.APIContext, opts *models.SearchUserOptions) error {
+	if orgName := ctx.Query("org"); len(orgName) > 0 {
+		org, err := models.GetOrgByName(orgName)
+		if err != nil {
+			if !models.IsErrOrgNotExist(err) {
+				return err
+			}
+			opts.OrgID = -1
+		} else if !models.HasOrgOrUserVisible(org, ctx.User) {
+			opts.OrgID = -1
+		} else {
+			opts.OrgID = org.ID
+			isMember := false
+			if ctx.User != nil {
+				if isMember, err = org.IsOrgMember(ctx.User.ID); err != nil {
+					return err
+				}
+			}
+			opts.OrgPublicOnly = !isMember && !ctx.IsUserSiteAdmin()
+		}
+	}
+
+	if fullName := ctx.Query("collaborator_of"); len(fullName) > 0 {
+		opts.CollaboratorRepoID = -1
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			return err
+		}
+		if perm.HasAccess() {
+			opts.CollaboratorRepoID = repo.ID
+		}
+	}
+	return nil
+}
+
 // GetInfo get user's information
 func GetInfo(ctx *context.APIContext) {
 	// swagger:operation GET /users/{username} user userGet
@@ -143,21 +214,82 @@ func GetUserHeatmapData(ctx *context.APIContext) {
 	//   description: username of user to get
 	//   type: string
 	//   required: true
+	// - name: from
+	//   in: query
+	//   description: start of the time range, a date (YYYY-MM-DD) in the timezone or a time in RFC 3339 format, defaults to one year ago
+	//   type: string
+	// - name: to
+	//   in: query
+	//   description: end of the time range, a date (YYYY-MM-DD, inclusive) in the timezone or a time in RFC 3339 format (exclusive)
+	//   type: string
+	// - name: timezone
+	//   in: query
+	//   description: IANA name of the timezone (e.g. Europe/Berlin) to group the contributions by day, they are grouped by 15 minutes if not set
+	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/UserHeatmapData"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	user := GetUserByParams(ctx)
 	if ctx.Written() {
 		return
 	}
 
-	heatmap, err := models.GetUserHeatmapDataByUser(user, ctx.User)
+	loc := time.UTC
+	timezone := ctx.Query("timezone")
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid timezone: %s", timezone))
+			return
+		}
+	}
+
+	var opts models.UserHeatmapOptions
+	var err error
+	if opts.From, err = parseHeatmapTime(ctx.Query("from"), loc, false); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid from: %v", err))
+		return
+	}
+	if opts.To, err = parseHeatmapTime(ctx.Query("to"), loc, true); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid to: %v", err))
+		return
+	}
+	if opts.From > 0 && opts.To > 0 && opts.From >= opts.To {
+		ctx.Error(http.StatusUnprocessableEntity, "", "from must be before to")
+		return
+	}
+
+	heatmap, err := models.GetUserHeatmapDataByUserInRange(user, ctx.User, opts)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "GetUserHeatmapDataByUser", err)
+		ctx.Error(http.StatusInternalServerError, "GetUserHeatmapDataByUserInRange", err)
 		return
 	}
+	if timezone != "" {
+		heatmap = models.GroupUserHeatmapDataByDay(heatmap, loc)
+	}
 	ctx.JSON(http.StatusOK, heatmap)
 }
+
+// parseHeatmapTime parses a date in loc or a time in RFC 3339 format,
+// if endOfDay is set a date is moved to the end of the day.
+func parseHeatmapTime(value string, loc *time.Location, endOfDay bool) (timeutil.TimeStamp, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return timeutil.TimeStamp(t.Unix()), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+	return timeutil.TimeStamp(t.Unix()), nil
+}
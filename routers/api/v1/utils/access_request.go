@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListRepoAccessRequests writes the access requests to the repository repoID or of the user userID,
+// filtered by the state query parameter
+func ListRepoAccessRequests(ctx *context.APIContext, repoID, userID int64) {
+	opts := &models.FindRepoAccessRequestsOptions{
+		ListOptions: GetListOptions(ctx),
+		RepoID:      repoID,
+		UserID:      userID,
+	}
+	if state := ctx.Query("state"); state != "" && state != "all" {
+		status, ok := models.ParseRepoAccessRequestStatus(state)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid state")
+			return
+		}
+		opts.Statuses = []models.RepoAccessRequestStatus{status}
+	}
+
+	requests, count, err := models.FindRepoAccessRequests(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoAccessRequests", err)
+		return
+	}
+	if err := requests.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	apiRequests := make([]*api.RepoAccessRequest, len(requests))
+	for i := range requests {
+		apiRequests[i] = convert.ToRepoAccessRequest(requests[i], ctx.User)
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiRequests)
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListGitOperations writes the git operations of the repository repoID, or of all repositories if it is 0,
+// filtered by the user, ip and type query parameters
+func ListGitOperations(ctx *context.APIContext, repoID int64) {
+	opts := &models.FindGitOperationsOptions{
+		ListOptions: GetListOptions(ctx),
+		RepoID:      repoID,
+		RemoteIP:    ctx.Query("ip"),
+	}
+	if typ := ctx.Query("type"); typ != "" {
+		opts.Type = models.GitOperationType(typ)
+		if opts.Type != models.GitOperationFetch && opts.Type != models.GitOperationPush && opts.Type != models.GitOperationArchive {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid type")
+			return
+		}
+	}
+	if userName := ctx.Query("user"); userName != "" {
+		user, err := models.GetUserByName(userName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		opts.UserID = user.ID
+	}
+
+	ops, count, err := models.FindGitOperations(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindGitOperations", err)
+		return
+	}
+	if err := ops.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	apiOps := make([]*api.GitOperation, len(ops))
+	for i := range ops {
+		apiOps[i] = convert.ToGitOperation(ops[i])
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiOps)
+}
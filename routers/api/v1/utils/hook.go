@@ -12,9 +12,11 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/utils"
+	"code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/webhook"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -66,6 +68,14 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid content type")
 		return false
 	}
+	if _, err := models.ToHookPayloadVersion(form.Config["payload_version"]); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid payload version")
+		return false
+	}
+	if !models.IsValidSignatureHeader(form.Config["signature_header"]) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid signature header")
+		return false
+	}
 	return true
 }
 
@@ -78,6 +88,14 @@ func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	}
 }
 
+// AddUserHook add a hook to the signed in user. Writes to `ctx` accordingly
+func AddUserHook(ctx *context.APIContext, form *api.CreateHookOption) {
+	hook, ok := addHook(ctx, form, ctx.User.ID, 0)
+	if ok {
+		ctx.JSON(http.StatusCreated, convert.ToHook(ctx.User.HomeLink(), hook))
+	}
+}
+
 // AddRepoHook add a hook to a repo. Writes to `ctx` accordingly
 func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	repo := ctx.Repo
@@ -101,13 +119,16 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
+	payloadVersion, _ := models.ToHookPayloadVersion(form.Config["payload_version"])
 	w := &models.Webhook{
-		OrgID:       orgID,
-		RepoID:      repoID,
-		URL:         form.Config["url"],
-		ContentType: models.ToHookContentType(form.Config["content_type"]),
-		Secret:      form.Config["secret"],
-		HTTPMethod:  "POST",
+		OrgID:           orgID,
+		RepoID:          repoID,
+		URL:             form.Config["url"],
+		ContentType:     models.ToHookContentType(form.Config["content_type"]),
+		PayloadVersion:  payloadVersion,
+		Secret:          form.Config["secret"],
+		SignatureHeader: form.Config["signature_header"],
+		HTTPMethod:      "POST",
 		HookEvent: &models.HookEvent{
 			ChooseEvents: true,
 			HookEvents: models.HookEvents{
@@ -127,7 +148,9 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				PullRequestComment:   pullHook(form.Events, string(models.HookEventPullRequestComment)),
 				PullRequestReview:    pullHook(form.Events, "pull_request_review"),
 				PullRequestSync:      pullHook(form.Events, string(models.HookEventPullRequestSync)),
+				Deployment:           util.IsStringInSlice(string(models.HookEventDeployment), form.Events, true),
 				Repository:           util.IsStringInSlice(string(models.HookEventRepository), form.Events, true),
+				RepositorySettings:   util.IsStringInSlice(string(models.HookEventRepositorySettings), form.Events, true),
 				Release:              util.IsStringInSlice(string(models.HookEventRelease), form.Events, true),
 			},
 			BranchFilter: form.BranchFilter,
@@ -161,6 +184,11 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		w.Meta = string(meta)
 	}
 
+	if !setting.Webhook.AllowPlaintextHTTP && audit.IsPlaintextURL(w.URL) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "New webhooks must deliver their payloads over HTTPS")
+		return nil, false
+	}
+
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
 		return nil, false
@@ -188,6 +216,22 @@ func EditOrgHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64
 	ctx.JSON(http.StatusOK, convert.ToHook(org.HomeLink(), updated))
 }
 
+// EditUserHook edit webhook `w` of the signed in user according to `form`. Writes to `ctx` accordingly
+func EditUserHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
+	hook, err := GetOrgHook(ctx, ctx.User.ID, hookID)
+	if err != nil {
+		return
+	}
+	if !editHook(ctx, form, hook) {
+		return
+	}
+	updated, err := GetOrgHook(ctx, ctx.User.ID, hookID)
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(ctx.User.HomeLink(), updated))
+}
+
 // EditRepoHook edit webhook `w` according to `form`. Writes to `ctx` accordingly
 func EditRepoHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
 	repo := ctx.Repo
@@ -219,6 +263,24 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 			}
 			w.ContentType = models.ToHookContentType(ct)
 		}
+		if pv, ok := form.Config["payload_version"]; ok {
+			payloadVersion, err := models.ToHookPayloadVersion(pv)
+			if err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "", "Invalid payload version")
+				return false
+			}
+			w.PayloadVersion = payloadVersion
+		}
+		if secret, ok := form.Config["secret"]; ok {
+			w.Secret = secret
+		}
+		if header, ok := form.Config["signature_header"]; ok {
+			if !models.IsValidSignatureHeader(header) {
+				ctx.Error(http.StatusUnprocessableEntity, "", "Invalid signature header")
+				return false
+			}
+			w.SignatureHeader = header
+		}
 
 		if w.Type == models.SLACK {
 			if channel, ok := form.Config["channel"]; ok {
@@ -255,7 +317,9 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.IssueComment = util.IsStringInSlice(string(models.HookEventIssueComment), form.Events, true)
 	w.Push = util.IsStringInSlice(string(models.HookEventPush), form.Events, true)
 	w.PullRequest = util.IsStringInSlice(string(models.HookEventPullRequest), form.Events, true)
+	w.Deployment = util.IsStringInSlice(string(models.HookEventDeployment), form.Events, true)
 	w.Repository = util.IsStringInSlice(string(models.HookEventRepository), form.Events, true)
+	w.RepositorySettings = util.IsStringInSlice(string(models.HookEventRepositorySettings), form.Events, true)
 	w.Release = util.IsStringInSlice(string(models.HookEventRelease), form.Events, true)
 	w.BranchFilter = form.BranchFilter
 
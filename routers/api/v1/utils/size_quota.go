@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetSizeQuota writes the size quota of the repositories of the user or the organization u
+func GetSizeQuota(ctx *context.APIContext, u *models.User) {
+	used, err := u.UsedSize()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UsedSize", err)
+		return
+	}
+	remaining, err := u.RemainingSize()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemainingSize", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.SizeQuota{
+		Limit:     u.SizeQuotaLimit(),
+		Used:      used,
+		Remaining: remaining,
+	})
+}
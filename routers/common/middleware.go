@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/chi-middleware/proxy"
@@ -27,6 +29,8 @@ func Middlewares() []func(http.Handler) http.Handler {
 		},
 	}
 
+	handlers = append(handlers, context.RequestIDer())
+
 	if setting.ReverseProxyLimit > 0 {
 		opt := proxy.NewForwardedHeadersOptions().
 			WithForwardLimit(setting.ReverseProxyLimit).
@@ -43,6 +47,16 @@ func Middlewares() []func(http.Handler) http.Handler {
 
 	handlers = append(handlers, middleware.StripSlashes)
 
+	if setting.Metrics.Enabled {
+		handlers = append(handlers, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				start := time.Now()
+				next.ServeHTTP(resp, req)
+				metrics.ObserveHTTPRequest(req.Method, resp.(context.ResponseWriter).Status(), start)
+			})
+		})
+	}
+
 	if !setting.DisableRouterLog && setting.RouterLogLevel != log.NONE {
 		if log.GetLogger("router").GetLevel() <= setting.RouterLogLevel {
 			handlers = append(handlers, LoggerHandler(setting.RouterLogLevel))
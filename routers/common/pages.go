@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	pages_service "code.gitea.io/gitea/services/pages"
+)
+
+// PagesDomainHandler serves the sites of public repositories on their verified custom domains,
+// requests for any other host are passed on
+func PagesDomainHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		if host == "" || host == strings.ToLower(setting.Domain) || net.ParseIP(host) != nil {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		p, err := models.GetRepoPagesByDomain(host)
+		if err != nil {
+			if !models.IsErrRepoPagesNotExist(err) {
+				log.Error("GetRepoPagesByDomain(%s): %v", host, err)
+			}
+			next.ServeHTTP(resp, req)
+			return
+		}
+		if err = p.LoadRepo(); err != nil {
+			log.Error("LoadRepo(%d): %v", p.RepoID, err)
+			http.Error(resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if err = p.Repo.GetOwner(); err != nil {
+			log.Error("GetOwner(%d): %v", p.RepoID, err)
+			http.Error(resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		// there is no session on a custom domain, so only sites anyone may see are served there
+		if p.Repo.IsPrivate || !p.Repo.Owner.Visibility.IsPublic() || !p.Repo.UnitEnabled(models.UnitTypeCode) {
+			http.NotFound(resp, req)
+			return
+		}
+
+		pages_service.ServeSite(resp, req, p, req.URL.Path)
+	})
+}
@@ -39,6 +39,24 @@ func ServeBlob(ctx *context.Context, blob *git.Blob) error {
 	return ServeData(ctx, ctx.Repo.TreePath, blob.Size(), dataRc)
 }
 
+// IsInlineMediaType returns true if files of the mime type and extension may be displayed by the browser,
+// the allowed types are configured by [repository.media] INLINE_TYPES and ATTACHMENT_EXTENSIONS
+func IsInlineMediaType(mimeType, fileExtension string) bool {
+	for _, ext := range setting.Repository.Media.AttachmentExtensions {
+		if strings.EqualFold(strings.TrimSpace(ext), fileExtension) {
+			return false
+		}
+	}
+	mimeType = strings.ToLower(mimeType)
+	for _, allowed := range setting.Repository.Media.InlineTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mimeType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
 // ServeData download file from io.Reader
 func ServeData(ctx *context.Context, name string, size int64, reader io.Reader) error {
 	buf := make([]byte, 1024)
@@ -63,37 +81,50 @@ func ServeData(ctx *context.Context, name string, size int64, reader io.Reader)
 	name = strings.ReplaceAll(name, ",", " ")
 
 	st := typesniffer.DetectContentType(buf)
+	fileExtension := strings.ToLower(filepath.Ext(name))
 
 	mappedMimeType := ""
 	if setting.MimeTypeMap.Enabled {
-		fileExtension := strings.ToLower(filepath.Ext(name))
 		mappedMimeType = setting.MimeTypeMap.Map[fileExtension]
 	}
+
+	var contentType string
 	if st.IsText() || ctx.QueryBool("render") {
 		cs, err := charset.DetectEncoding(buf)
 		if err != nil {
 			log.Error("Detect raw file %s charset failed: %v, using by default utf-8", name, err)
 			cs = "utf-8"
 		}
-		if mappedMimeType == "" {
-			mappedMimeType = "text/plain"
+		contentType = mappedMimeType
+		if contentType == "" {
+			contentType = "text/plain"
 		}
-		ctx.Resp.Header().Set("Content-Type", mappedMimeType+"; charset="+strings.ToLower(cs))
+		ctx.Resp.Header().Set("Content-Type", contentType+"; charset="+strings.ToLower(cs))
 	} else {
-		ctx.Resp.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
-		if mappedMimeType != "" {
-			ctx.Resp.Header().Set("Content-Type", mappedMimeType)
+		contentType = mappedMimeType
+		if contentType == "" {
+			contentType = st.GetMimeType()
+		}
+		if st.IsSvgImage() {
+			contentType = typesniffer.SvgMimeType
 		}
-		if (st.IsImage() || st.IsPDF()) && (setting.UI.SVG.Enabled || !st.IsSvgImage()) {
+		ctx.Resp.Header().Set("Content-Type", contentType)
+	}
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// Plain text is always safe to display, other types are only displayed by the browser
+	// if they are allowed, as HTML, XML or SVG documents could run scripts.
+	if contentType == "text/plain" || (IsInlineMediaType(contentType, fileExtension) && (setting.UI.SVG.Enabled || !st.IsSvgImage())) {
+		if contentType != "text/plain" {
+			ctx.Resp.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
 			ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, name))
-			if st.IsSvgImage() {
-				ctx.Resp.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
-				ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
-				ctx.Resp.Header().Set("Content-Type", typesniffer.SvgMimeType)
-			}
-		} else {
-			ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
 		}
+		if st.IsSvgImage() {
+			ctx.Resp.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+		}
+	} else {
+		ctx.Resp.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+		ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
 	}
 
 	_, err = ctx.Resp.Write(buf)
@@ -0,0 +1,21 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInlineMediaType(t *testing.T) {
+	assert.True(t, IsInlineMediaType("image/png", ".png"))
+	assert.True(t, IsInlineMediaType("Image/PNG", ".png"))
+	assert.True(t, IsInlineMediaType("application/pdf", ".pdf"))
+	assert.False(t, IsInlineMediaType("text/html", ".txt"))
+	assert.False(t, IsInlineMediaType("application/octet-stream", ".bin"))
+	// the extension takes precedence
+	assert.False(t, IsInlineMediaType("image/png", ".html"))
+}
@@ -29,16 +29,23 @@ import (
 	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/web"
+	packages_router "code.gitea.io/gitea/routers/api/packages"
 	apiv1 "code.gitea.io/gitea/routers/api/v1"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/routers/private"
 	web_routers "code.gitea.io/gitea/routers/web"
 	"code.gitea.io/gitea/services/archiver"
 	"code.gitea.io/gitea/services/auth"
+	"code.gitea.io/gitea/services/automerge"
+	deployment_service "code.gitea.io/gitea/services/deployment"
 	"code.gitea.io/gitea/services/mailer"
+	"code.gitea.io/gitea/services/mailer/incoming"
+	markup_service "code.gitea.io/gitea/services/markup"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	pages_service "code.gitea.io/gitea/services/pages"
 	pull_service "code.gitea.io/gitea/services/pull"
 	"code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/services/triage"
 	"code.gitea.io/gitea/services/webhook"
 )
 
@@ -88,6 +95,7 @@ func GlobalInit(ctx context.Context) {
 	highlight.NewContext()
 	external.RegisterRenderers()
 	markup.Init()
+	markup_service.Init()
 
 	if setting.EnableSQLite3 {
 		log.Info("SQLite3 Supported")
@@ -118,12 +126,25 @@ func GlobalInit(ctx context.Context) {
 	if err := pull_service.Init(); err != nil {
 		log.Fatal("Failed to initialize test pull requests queue: %v", err)
 	}
+	if err := automerge.Init(); err != nil {
+		log.Fatal("Failed to initialize auto merge queue: %v", err)
+	}
+	if err := triage.Init(); err != nil {
+		log.Fatal("Failed to initialize issue triage queue: %v", err)
+	}
 	if err := task.Init(); err != nil {
 		log.Fatal("Failed to initialize task scheduler: %v", err)
 	}
+	if err := pages_service.Init(); err != nil {
+		log.Fatal("Failed to initialize pages deploy queue: %v", err)
+	}
+	deployment_service.Init()
 	if err := repo_migrations.Init(); err != nil {
 		log.Fatal("Failed to initialize repository migrations: %v", err)
 	}
+	if err := incoming.Init(); err != nil {
+		log.Fatal("Failed to start the LMTP server: %v", err)
+	}
 	eventsource.GetManager().Init()
 
 	if setting.SSH.StartBuiltinServer {
@@ -143,9 +164,13 @@ func NormalRoutes() *web.Route {
 	for _, middle := range common.Middlewares() {
 		r.Use(middle)
 	}
+	if setting.Pages.Enabled && setting.Pages.AllowCustomDomains {
+		r.Use(common.PagesDomainHandler)
+	}
 
 	r.Mount("/", web_routers.Routes())
 	r.Mount("/api/v1", apiv1.Routes())
+	r.Mount("/api/packages", packages_router.Routes())
 	r.Mount("/api/internal", private.Routes())
 	return r
 }
@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	gitea_context "code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
@@ -164,12 +165,105 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		return
 	}
 
+	// The objects received are in the quarantine directory until the push is accepted,
+	// pushes only deleting references are let through so that space can be freed
+	if opts.PullRequestID == 0 && opts.GitQuarantinePath != "" {
+		pushSize, err := util.GetDirectorySize(opts.GitQuarantinePath)
+		if err != nil {
+			log.Error("Unable to get the size of the quarantine directory of %-v Error: %v", repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		if pushSize > 0 {
+			if err := repo.GetOwner(); err != nil {
+				log.Error("Unable to get owner of %-v Error: %v", repo, err)
+				ctx.JSON(http.StatusInternalServerError, private.Response{
+					Err: err.Error(),
+				})
+				return
+			}
+			if err := repo.Owner.CheckSizeQuota(pushSize); err != nil {
+				if models.IsErrSizeQuotaExceeded(err) {
+					quotaErr := err.(models.ErrSizeQuotaExceeded)
+					log.Warn("Forbidden: Push of %d bytes to %-v exceeds the size quota of %s", pushSize, repo, repo.OwnerName)
+					ctx.JSON(http.StatusForbidden, private.Response{
+						Err: fmt.Sprintf("The size quota of %s is exceeded: %s used of %s, this push adds %s",
+							repo.OwnerName, base.FileSize(quotaErr.Used), base.FileSize(quotaErr.Quota), base.FileSize(pushSize)),
+					})
+					return
+				}
+				log.Error("Unable to check the size quota of %s Error: %v", repo.OwnerName, err)
+				ctx.JSON(http.StatusInternalServerError, private.Response{
+					Err: err.Error(),
+				})
+				return
+			}
+		}
+	}
+
+	// Users who can only read the code are let through serv to push to refs/for/<branch>,
+	// so everything else they push has to be rejected here. Deploy keys have had their
+	// mode checked already, and merges are checked against the branch protection below.
+	var pusherPerm *models.Permission
+	if !opts.IsDeployKey && opts.PullRequestID == 0 {
+		pusher, err := models.GetUserByID(opts.UserID)
+		if err != nil {
+			log.Error("Unable to get pusher %d for %-v Error: %v", opts.UserID, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		perm, err := models.GetUserRepoPermission(repo, pusher)
+		if err != nil {
+			log.Error("Unable to get permissions of %-v in %-v Error: %v", pusher, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		pusherPerm = &perm
+	}
+
 	// Iterate across the provided old commit IDs
 	for i := range opts.OldCommitIDs {
 		oldCommitID := opts.OldCommitIDs[i]
 		newCommitID := opts.NewCommitIDs[i]
 		refFullName := opts.RefFullNames[i]
 
+		if strings.HasPrefix(refFullName, git.ForPrefix) {
+			if pusherPerm == nil || !repo.AllowsPulls() || !pusherPerm.CanPushForPullRequest() {
+				log.Warn("Forbidden: User %d is not allowed to create pull requests in %-v", opts.UserID, repo)
+				ctx.JSON(http.StatusForbidden, private.Response{
+					Err: "You are not allowed to create pull requests in this repository",
+				})
+				return
+			}
+			if newCommitID == git.EmptySHA {
+				ctx.JSON(http.StatusForbidden, private.Response{
+					Err: fmt.Sprintf("%s cannot be deleted", refFullName),
+				})
+				return
+			}
+			if _, _, err := pull_service.ParseAGitRef(gitRepo, refFullName, opts.GitPushOptions[private.GitPushOptionTopic]); err != nil {
+				ctx.JSON(http.StatusForbidden, private.Response{
+					Err: err.Error(),
+				})
+				return
+			}
+			continue
+		}
+
+		if pusherPerm != nil && !pusherPerm.CanWrite(models.UnitTypeCode) {
+			log.Warn("Forbidden: User %d is not allowed to push to %s in %-v", opts.UserID, refFullName, repo)
+			ctx.JSON(http.StatusForbidden, private.Response{
+				Err: fmt.Sprintf("You are not allowed to push to %s, push to %s<branch> to create a pull request instead", refFullName, git.ForPrefix),
+			})
+			return
+		}
+
 		if strings.HasPrefix(refFullName, git.BranchPrefix) {
 			branchName := strings.TrimPrefix(refFullName, git.BranchPrefix)
 			if branchName == repo.DefaultBranch && newCommitID == git.EmptySHA {
@@ -180,6 +274,26 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 				return
 			}
 
+			// Reject changes to files locked by another user, merges of pull requests included
+			// as the head of a pull request from a fork or AGit has never been pushed to this repository.
+			if setting.LFS.StartServer && newCommitID != git.EmptySHA {
+				locks, err := checkLFSLocks(repo, oldCommitID, newCommitID, opts.UserID, opts.PullRequestID != 0, env)
+				if err != nil {
+					log.Error("Unable to check LFS locks for commits from %s to %s in %-v: %v", oldCommitID, newCommitID, repo, err)
+					ctx.JSON(http.StatusInternalServerError, private.Response{
+						Err: fmt.Sprintf("Unable to check LFS locks for commits from %s to %s: %v", oldCommitID, newCommitID, err),
+					})
+					return
+				}
+				if len(locks) > 0 {
+					log.Warn("Forbidden: User %d is not allowed to change %d file(s) locked by other users in branch: %s in %-v", opts.UserID, len(locks), branchName, repo)
+					ctx.JSON(http.StatusForbidden, private.Response{
+						Err: formatLFSLocksError(branchName, locks),
+					})
+					return
+				}
+			}
+
 			protectBranch, err := models.GetProtectedBranchBy(repo.ID, branchName)
 			if err != nil {
 				log.Error("Unable to get protected branch: %s in %-v Error: %v", branchName, repo, err)
@@ -392,11 +506,6 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 				})
 				return
 			}
-		} else {
-			log.Error("Unexpected ref: %s", refFullName)
-			ctx.JSON(http.StatusInternalServerError, private.Response{
-				Err: fmt.Sprintf("Unexpected ref: %s", refFullName),
-			})
 		}
 	}
 
@@ -409,6 +518,12 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	ownerName := ctx.Params(":owner")
 	repoName := ctx.Params(":repo")
 
+	if opts.GitOperationID > 0 {
+		if err := models.AddGitOperationRefs(opts.GitOperationID, opts.OldCommitIDs, opts.NewCommitIDs, opts.RefFullNames); err != nil {
+			log.Error("Unable to add the updated references of %s/%s to git operation %d: %v", ownerName, repoName, opts.GitOperationID, err)
+		}
+	}
+
 	var repo *models.Repository
 	updates := make([]*repo_module.PushUpdateOptions, 0, len(opts.OldCommitIDs))
 	wasEmpty := false
@@ -494,6 +609,20 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 
 		branch := git.RefEndName(opts.RefFullNames[i])
 
+		if newCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.ForPrefix) {
+			result, err := agitPostReceive(ownerName, repoName, opts, refFullName, newCommitID)
+			if err != nil {
+				log.Error("Failed to create or update pull request for %s in %s/%s Error: %v", refFullName, ownerName, repoName, err)
+				ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+					Err:          fmt.Sprintf("Failed to create or update pull request for %s: %v", refFullName, err),
+					RepoWasEmpty: wasEmpty,
+				})
+				return
+			}
+			results = append(results, *result)
+			continue
+		}
+
 		if newCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.BranchPrefix) {
 			if repo == nil {
 				var err error
@@ -537,7 +666,7 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 				continue
 			}
 
-			pr, err := models.GetUnmergedPullRequest(repo.ID, baseRepo.ID, branch, baseRepo.DefaultBranch)
+			pr, err := models.GetUnmergedPullRequest(repo.ID, baseRepo.ID, branch, baseRepo.DefaultBranch, models.PullRequestFlowGithub)
 			if err != nil && !models.IsErrPullRequestNotExist(err) {
 				log.Error("Failed to get active PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseRepo.DefaultBranch, err)
 				ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
@@ -574,6 +703,38 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	})
 }
 
+// agitPostReceive creates or updates the AGit pull request of a push to refs/for/<branch>
+func agitPostReceive(ownerName, repoName string, opts *private.HookOptions, refFullName, newCommitID string) (*private.HookPostReceiveBranchResult, error) {
+	repo, err := models.GetRepositoryByOwnerAndName(ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	pusher, err := models.GetUserByID(opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	pr, created, err := pull_service.PushAGit(repo, gitRepo, pusher, refFullName, newCommitID, pull_service.AGitOptions{
+		Topic:       opts.GitPushOptions[private.GitPushOptionTopic],
+		Title:       opts.GitPushOptions[private.GitPushOptionTitle],
+		Description: opts.GitPushOptions[private.GitPushOptionDescription],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &private.HookPostReceiveBranchResult{
+		Message: true,
+		Created: created,
+		Branch:  pr.HeadBranch,
+		URL:     fmt.Sprintf("%s/pulls/%d", repo.HTMLURL(), pr.Index),
+	}, nil
+}
+
 // SetDefaultBranch updates the default branch
 func SetDefaultBranch(ctx *gitea_context.PrivateContext) {
 	ownerName := ctx.Params(":owner")
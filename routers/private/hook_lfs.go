@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// checkLFSLocks returns the LFS locks held by users other than userID on paths
+// changed by the commits pushed to the repository, or by the merge of a pull request if isMerge is set
+func checkLFSLocks(repo *models.Repository, oldCommitID, newCommitID string, userID int64, isMerge bool, env []string) ([]*models.LFSLock, error) {
+	locks, err := models.GetLFSLockByRepoID(repo.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedPaths := make(map[string]*models.LFSLock, len(locks))
+	for _, lock := range locks {
+		if lock.OwnerID != userID {
+			lockedPaths[strings.ToLower(lock.Path)] = lock
+		}
+	}
+	if len(lockedPaths) == 0 {
+		return nil, nil
+	}
+
+	var args []string
+	if isMerge && oldCommitID != git.EmptySHA {
+		// The commits of a pull request are already referenced by its refs/pull head, whether they were checked or not,
+		// so look at all the files the merge changes in the branch
+		args = []string{"diff", "--name-only", "--no-renames", "-z", oldCommitID, newCommitID}
+	} else {
+		// Only look at the commits which are new to the repository, the others have already been checked
+		args = []string{"log", "--name-only", "--no-renames", "--format=", "-z", newCommitID}
+		if oldCommitID != git.EmptySHA {
+			args = append(args, "^"+oldCommitID)
+		}
+		args = append(args, "--not", "--all")
+	}
+	stdout, err := git.NewCommand(args...).RunInDirWithEnv(repo.RepoPath(), env)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the files changed from %s to %s: %v", oldCommitID, newCommitID, err)
+	}
+
+	violated := make([]*models.LFSLock, 0, len(lockedPaths))
+	for _, path := range strings.Split(stdout, "\x00") {
+		path = strings.TrimSpace(path)
+		if lock, ok := lockedPaths[strings.ToLower(path)]; ok && path != "" {
+			violated = append(violated, lock)
+			delete(lockedPaths, strings.ToLower(path))
+		}
+	}
+	return violated, nil
+}
+
+// formatLFSLocksError lists the locks preventing a push
+func formatLFSLocksError(branchName string, locks []*models.LFSLock) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "branch %s: cannot push changes to files locked by other users:", branchName)
+	for _, lock := range locks {
+		owner := "unknown"
+		if lock.Owner != nil {
+			owner = lock.Owner.Name
+		}
+		fmt.Fprintf(&sb, "\n  %s (locked by %s, lock #%d)", lock.Path, owner, lock.ID)
+	}
+	return sb.String()
+}
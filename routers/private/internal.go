@@ -61,6 +61,7 @@ func Routes() *web.Route {
 	r.Post("/hook/set-default-branch/{owner}/{repo}/{branch}", SetDefaultBranch)
 	r.Get("/serv/none/{keyid}", ServNoCommand)
 	r.Get("/serv/command/{keyid}/{owner}/{repo}", ServCommand)
+	r.Post("/serv/operation/{id}/finish", bind(private.GitOperationFinishOptions{}), FinishGitOperation)
 	r.Post("/manager/shutdown", Shutdown)
 	r.Post("/manager/restart", Restart)
 	r.Post("/manager/flush-queues", bind(private.FlushOptions{}), FlushQueues)
@@ -70,6 +71,8 @@ func Routes() *web.Route {
 	r.Post("/manager/add-logger", bind(private.LoggerOptions{}), AddLogger)
 	r.Post("/manager/remove-logger/{group}/{name}", RemoveLogger)
 	r.Post("/mail/send", SendEmail)
+	r.Post("/mail/register-notify", bind(private.RegisterNotifyEmails{}), SendRegisterNotifyEmails)
+	r.Post("/mail/receive", ReceiveEmail)
 	r.Post("/restore_repo", RestoreRepo)
 
 	return r
@@ -6,6 +6,7 @@
 package private
 
 import (
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
@@ -59,5 +60,11 @@ func AuthorizedPublicKeyByContent(ctx *context.PrivateContext) {
 		})
 		return
 	}
+	if publicKey.IsExpired() {
+		ctx.JSON(http.StatusUnauthorized, private.Response{
+			Err: fmt.Sprintf("Key: %d has expired", publicKey.ID),
+		})
+		return
+	}
 	ctx.PlainText(http.StatusOK, []byte(publicKey.AuthorizedString()))
 }
@@ -14,7 +14,9 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/mailer"
+	"code.gitea.io/gitea/services/mailer/incoming"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -88,3 +90,49 @@ func sendEmail(ctx *context.PrivateContext, subject, message string, to []string
 
 	ctx.PlainText(http.StatusOK, []byte(wasSent))
 }
+
+// SendRegisterNotifyEmails pushes the emails notifying users of the creation of their accounts to mail queue
+func SendRegisterNotifyEmails(ctx *context.PrivateContext) {
+	if setting.MailService == nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: "Mail service is not enabled.",
+		})
+		return
+	}
+
+	opts := web.GetForm(ctx).(*private.RegisterNotifyEmails)
+	for _, uname := range opts.Usernames {
+		user, err := models.GetUserByName(uname)
+		if err != nil {
+			err := fmt.Sprintf("Failed to get user information: %v", err)
+			log.Error(err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err,
+			})
+			return
+		}
+		mailer.SendRegisterNotifyMail(user)
+	}
+
+	ctx.PlainText(http.StatusOK, []byte(strconv.Itoa(len(opts.Usernames))))
+}
+
+// ReceiveEmail handles an email sent to the incoming address of a repository
+func ReceiveEmail(ctx *context.PrivateContext) {
+	rd := ctx.Req.Body
+	defer rd.Close()
+	if err := incoming.ReceiveEmail(rd); err != nil {
+		if incoming.IsErrRejected(err) {
+			ctx.JSON(http.StatusUnprocessableEntity, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		log.Error("ReceiveEmail: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte("received"))
+}
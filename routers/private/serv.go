@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
 	repo_service "code.gitea.io/gitea/services/repository"
 	wiki_service "code.gitea.io/gitea/services/wiki"
 )
@@ -43,6 +44,12 @@ func ServNoCommand(ctx *context.PrivateContext) {
 		})
 		return
 	}
+	if key.IsExpired() {
+		ctx.JSON(http.StatusUnauthorized, private.Response{
+			Err: fmt.Sprintf("Key: %d has expired", keyID),
+		})
+		return
+	}
 	results.Key = key
 
 	if key.Type == models.KeyTypeUser || key.Type == models.KeyTypePrincipal {
@@ -187,6 +194,13 @@ func ServCommand(ctx *context.PrivateContext) {
 		})
 		return
 	}
+	if key.IsExpired() {
+		ctx.JSON(http.StatusUnauthorized, private.ErrServCommand{
+			Results: results,
+			Err:     fmt.Sprintf("Key: %d has expired", keyID),
+		})
+		return
+	}
 	results.KeyName = key.Name
 	results.KeyID = key.ID
 	results.UserID = key.OwnerID
@@ -300,7 +314,9 @@ func ServCommand(ctx *context.PrivateContext) {
 
 			userMode := perm.UnitAccessMode(unitType)
 
-			if userMode < mode {
+			// Readers may push to refs/for/<branch> to create AGit pull requests,
+			// the pre-receive hook rejects all their other updates
+			if userMode < mode && !(mode == models.AccessModeWrite && unitType == models.UnitTypeCode && repo.AllowsPulls() && perm.CanPushForPullRequest()) {
 				log.Error("Failed authentication attempt for %s with key %s (not authorized to %s %s/%s) from %s", user.Name, key.Name, modeString, ownerName, repoName, ctx.RemoteAddr())
 				ctx.JSON(http.StatusUnauthorized, private.ErrServCommand{
 					Results: results,
@@ -377,6 +393,27 @@ func ServCommand(ctx *context.PrivateContext) {
 			return
 		}
 	}
+	if opType := gitOperationType(ctx.QueryStrings("verb")); opType != "" {
+		op := &models.GitOperation{
+			RepoID:   repo.ID,
+			IsWiki:   results.IsWiki,
+			UserID:   results.UserID,
+			KeyID:    results.KeyID,
+			Type:     opType,
+			Protocol: models.GitOperationProtocolSSH,
+			RemoteIP: ctx.Query("remote_ip"),
+		}
+		if err := models.CreateGitOperation(op); err != nil {
+			log.Error("Unable to record the git operation of %-v in %-v Error: %v", key, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.ErrServCommand{
+				Results: results,
+				Err:     fmt.Sprintf("Unable to record the git operation in %s/%s Error: %v", ownerName, repoName, err),
+			})
+			return
+		}
+		results.GitOperationID = op.ID
+	}
+
 	log.Debug("Serv Results:\nIsWiki: %t\nIsDeployKey: %t\nKeyID: %d\tKeyName: %s\nUserName: %s\nUserID: %d\nOwnerName: %s\nRepoName: %s\nRepoID: %d",
 		results.IsWiki,
 		results.IsDeployKey,
@@ -391,3 +428,26 @@ func ServCommand(ctx *context.PrivateContext) {
 	ctx.JSON(http.StatusOK, results)
 	// We will update the keys in a different call.
 }
+
+// gitOperationType returns the type of the operation of the git command among verbs, if any
+func gitOperationType(verbs []string) models.GitOperationType {
+	for _, verb := range verbs {
+		if opType := models.GitOperationTypeFromService(verb); opType != "" {
+			return opType
+		}
+	}
+	return ""
+}
+
+// FinishGitOperation records the end of a git operation started by ServCommand
+func FinishGitOperation(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.GitOperationFinishOptions)
+	if err := models.FinishGitOperation(ctx.ParamsInt64(":id"), opts.BytesReceived, opts.BytesSent, opts.Duration, opts.Succeeded); err != nil {
+		log.Error("Unable to finish git operation %d: %v", ctx.ParamsInt64(":id"), err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte("success"))
+}
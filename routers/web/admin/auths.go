@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth/ldap"
@@ -180,6 +181,11 @@ func parseOAuth2Config(form forms.AuthenticationForm) *models.OAuth2Config {
 		OpenIDConnectAutoDiscoveryURL: form.OpenIDConnectAutoDiscoveryURL,
 		CustomURLMapping:              customURLMapping,
 		IconURL:                       form.Oauth2IconURL,
+		AllowedEmailDomains:           models.ParseOAuth2AllowList(form.Oauth2AllowedEmailDomains),
+		AllowedAccounts:               models.ParseOAuth2AllowList(form.Oauth2AllowedAccounts),
+		GroupClaimName:                strings.TrimSpace(form.Oauth2GroupClaimName),
+		RequiredGroup:                 strings.TrimSpace(form.Oauth2RequiredGroup),
+		RequireRegistrationApproval:   form.Oauth2RequireApproval,
 	}
 }
 
@@ -0,0 +1,83 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/diskusage"
+)
+
+const (
+	tplDiskUsage base.TplName = "admin/disk_usage"
+
+	// diskUsageTopConsumers is the number of owners and repositories listed as top consumers
+	diskUsageTopConsumers = 20
+)
+
+// diskUsagePeriods are the periods the growth of the disk usage can be shown for
+var diskUsagePeriods = map[string]time.Duration{
+	"month":   30 * 24 * time.Hour,
+	"quarter": 90 * 24 * time.Hour,
+	"year":    365 * 24 * time.Hour,
+}
+
+// DiskUsage shows the owners and the repositories using the most disk space and the growth of their disk usage
+func DiskUsage(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.disk_usage")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminDiskUsage"] = true
+
+	period := ctx.Query("period")
+	if _, ok := diskUsagePeriods[period]; !ok {
+		period = "quarter"
+	}
+	since := timeutil.TimeStamp(time.Now().Add(-diskUsagePeriods[period]).Unix())
+	ctx.Data["Period"] = period
+	ctx.Data["Periods"] = []string{"month", "quarter", "year"}
+
+	var ownerID int64
+	if ownerName := ctx.Query("owner"); ownerName != "" {
+		owner, err := models.GetUserByName(ownerName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByName", err)
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
+			return
+		}
+		ctx.Data["Owner"] = owner
+		ownerID = owner.ID
+	} else {
+		owners, err := diskusage.TopOwners(diskUsageTopConsumers, since)
+		if err != nil {
+			ctx.ServerError("TopOwners", err)
+			return
+		}
+		ctx.Data["TopOwners"] = owners
+	}
+
+	repos, err := diskusage.TopRepos(ownerID, diskUsageTopConsumers, since)
+	if err != nil {
+		ctx.ServerError("TopRepos", err)
+		return
+	}
+	ctx.Data["TopRepos"] = repos
+
+	trend, err := diskusage.Trend(ownerID, since)
+	if err != nil {
+		ctx.ServerError("Trend", err)
+		return
+	}
+	ctx.Data["Trend"] = trend
+
+	ctx.HTML(http.StatusOK, tplDiskUsage)
+}
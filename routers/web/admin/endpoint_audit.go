@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/audit"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+const tplEndpointAudit base.TplName = "admin/endpoint_audit"
+
+// EndpointAudit lists the webhooks, authentication sources and mirrors using plaintext connections or deprecated services
+func EndpointAudit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.endpoint_audit")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminEndpointAudit"] = true
+
+	findings, err := audit.Scan(ctx.Req.Context())
+	if err != nil {
+		ctx.ServerError("audit.Scan", err)
+		return
+	}
+	ctx.Data["Findings"] = findings
+	ctx.Data["NumOwners"] = len(audit.GroupByOwner(findings))
+	ctx.Data["AllowPlaintextHTTP"] = setting.Webhook.AllowPlaintextHTTP
+	ctx.Data["MailerEnabled"] = setting.MailService != nil
+
+	ctx.HTML(http.StatusOK, tplEndpointAudit)
+}
+
+// EndpointAuditNotify sends the reported endpoints to the owners of the configurations
+func EndpointAuditNotify(ctx *context.Context) {
+	if setting.MailService == nil {
+		ctx.Flash.Error(ctx.Tr("admin.endpoint_audit.mailer_disabled"))
+		ctx.Redirect(setting.AppSubURL + "/admin/endpoints")
+		return
+	}
+
+	findings, err := audit.Scan(ctx.Req.Context())
+	if err != nil {
+		ctx.ServerError("audit.Scan", err)
+		return
+	}
+
+	notified := 0
+	for _, ownerFindings := range audit.GroupByOwner(findings) {
+		owner := ownerFindings[0].Owner
+		if err := mailer.SendEndpointAuditMail(owner, ownerFindings); err != nil {
+			log.Error("SendEndpointAuditMail[%d]: %v", owner.ID, err)
+			continue
+		}
+		notified++
+	}
+	log.Trace("Endpoint audit notified %d owners [by %s]", notified, ctx.User.Name)
+
+	ctx.Flash.Success(ctx.Tr("admin.endpoint_audit.notify_success", notified))
+	ctx.Redirect(setting.AppSubURL + "/admin/endpoints")
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+const (
+	tplPendingRegistrations base.TplName = "admin/user/pending"
+)
+
+// PendingRegistrations lists the accounts of OAuth2 sources waiting for an approval
+func PendingRegistrations(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.users.pending_registrations")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminUsers"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	registrations, count, err := models.FindPendingRegistrations(models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.UserPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("FindPendingRegistrations", err)
+		return
+	}
+	if err := registrations.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	ctx.Data["Registrations"] = registrations
+	ctx.Data["Total"] = count
+	ctx.Data["Page"] = context.NewPagination(int(count), setting.UI.Admin.UserPagingNum, page, 5)
+
+	ctx.HTML(http.StatusOK, tplPendingRegistrations)
+}
+
+func getPendingRegistration(ctx *context.Context) *models.PendingRegistration {
+	r, err := models.GetPendingRegistrationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrPendingRegistrationNotExist(err) {
+			ctx.NotFound("GetPendingRegistrationByID", nil)
+		} else {
+			ctx.ServerError("GetPendingRegistrationByID", err)
+		}
+		return nil
+	}
+	if err := r.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return nil
+	}
+	return r
+}
+
+// ApprovePendingRegistration allows the account of a pending registration to sign in
+func ApprovePendingRegistration(ctx *context.Context) {
+	r := getPendingRegistration(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.ApprovePendingRegistration(r); err != nil {
+		if models.IsErrPendingRegistrationNotExist(err) {
+			ctx.NotFound("ApprovePendingRegistration", nil)
+		} else {
+			ctx.ServerError("ApprovePendingRegistration", err)
+		}
+		return
+	}
+	log.Trace("Registration approved by admin (%s): %s", ctx.User.Name, r.User.Name)
+
+	if setting.MailService != nil {
+		mailer.SendRegisterNotifyMail(r.User)
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.users.registration_approved", r.User.Name))
+	ctx.Redirect(setting.AppSubURL + "/admin/users/pending")
+}
+
+// RejectPendingRegistration deletes the account of a pending registration
+func RejectPendingRegistration(ctx *context.Context) {
+	r := getPendingRegistration(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteUser(r.User); err != nil {
+		ctx.ServerError("DeleteUser", err)
+		return
+	}
+	log.Trace("Registration rejected by admin (%s): %s", ctx.User.Name, r.User.Name)
+
+	ctx.Flash.Success(ctx.Tr("admin.users.registration_rejected", r.User.Name))
+	ctx.Redirect(setting.AppSubURL + "/admin/users/pending")
+}
@@ -16,7 +16,6 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/explore"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
@@ -107,60 +106,36 @@ func UnadoptedRepos(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplUnadoptedRepos)
 }
 
-// AdoptOrDeleteRepository adopts or deletes a repository
+// AdoptOrDeleteRepository adopts or deletes one or more unadopted repositories
 func AdoptOrDeleteRepository(ctx *context.Context) {
-	dir := ctx.Query("id")
+	dirs := ctx.QueryStrings("id")
 	action := ctx.Query("action")
 	page := ctx.QueryInt("page")
 	q := ctx.Query("q")
 
-	dirSplit := strings.SplitN(dir, "/", 2)
-	if len(dirSplit) != 2 {
+	var errs map[string]error
+	switch action {
+	case "adopt":
+		errs = repository.AdoptUnadoptedRepositories(ctx.User, dirs)
+	case "delete":
+		errs = repository.DeleteUnadoptedRepositories(ctx.User, dirs)
+	default:
 		ctx.Redirect(setting.AppSubURL + "/admin/repos")
 		return
 	}
 
-	ctxUser, err := models.GetUserByName(dirSplit[0])
-	if err != nil {
-		if models.IsErrUserNotExist(err) {
-			log.Debug("User does not exist: %s", dirSplit[0])
-			ctx.Redirect(setting.AppSubURL + "/admin/repos")
-			return
+	failed := make([]string, 0, len(errs))
+	for _, dir := range dirs {
+		if _, ok := errs[dir]; ok {
+			failed = append(failed, dir)
 		}
-		ctx.ServerError("GetUserByName", err)
-		return
-	}
-
-	repoName := dirSplit[1]
-
-	// check not a repo
-	has, err := models.IsRepositoryExist(ctxUser, repoName)
-	if err != nil {
-		ctx.ServerError("IsRepositoryExist", err)
-		return
-	}
-	isDir, err := util.IsDir(models.RepoPath(ctxUser.Name, repoName))
-	if err != nil {
-		ctx.ServerError("IsDir", err)
-		return
 	}
-	if has || !isDir {
-		// Fallthrough to failure mode
-	} else if action == "adopt" {
-		if _, err := repository.AdoptRepository(ctx.User, ctxUser, models.CreateRepoOptions{
-			Name:      dirSplit[1],
-			IsPrivate: true,
-		}); err != nil {
-			ctx.ServerError("repository.AdoptRepository", err)
-			return
-		}
-		ctx.Flash.Success(ctx.Tr("repo.adopt_preexisting_success", dir))
-	} else if action == "delete" {
-		if err := repository.DeleteUnadoptedRepository(ctx.User, ctxUser, dirSplit[1]); err != nil {
-			ctx.ServerError("repository.AdoptRepository", err)
-			return
-		}
-		ctx.Flash.Success(ctx.Tr("repo.delete_preexisting_success", dir))
+	if len(failed) > 0 {
+		ctx.Flash.Error(ctx.Tr("admin.repos.unadopted."+action+"_failed", strings.Join(failed, ", ")))
+	} else if len(dirs) == 1 {
+		ctx.Flash.Success(ctx.Tr("repo."+action+"_preexisting_success", dirs[0]))
+	} else if len(dirs) > 1 {
+		ctx.Flash.Success(ctx.Tr("admin.repos.unadopted."+action+"_success", len(dirs)))
 	}
 	ctx.Redirect(setting.AppSubURL + "/admin/repos/unadopted?search=true&q=" + url.QueryEscape(q) + "&page=" + strconv.Itoa(page))
 }
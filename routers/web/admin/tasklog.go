@@ -0,0 +1,133 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/cron"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/tasklog"
+)
+
+const tplTaskLog base.TplName = "admin/tasklog"
+
+// taskLogPollInterval is the interval of the pings, which also look for a new run of the task
+var taskLogPollInterval = 5 * time.Second
+
+// TaskLog shows the log of the last run of a task
+func TaskLog(ctx *context.Context) {
+	name := ctx.Params(":name")
+	if cron.GetTask(name) == nil {
+		ctx.NotFound("GetTask", nil)
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("admin.monitor.task_log")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminMonitor"] = true
+	ctx.Data["TaskName"] = name
+	ctx.HTML(http.StatusOK, tplTaskLog)
+}
+
+// parseTaskLogEventID parses the ID of the last event received by a reconnecting client
+func parseTaskLogEventID(id string) (runID, offset int64) {
+	fields := strings.SplitN(id, "-", 2)
+	if len(fields) != 2 {
+		return 0, 0
+	}
+	runID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+	offset, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+	return runID, offset + 1
+}
+
+// TaskLogEvents streams the log of a task as server-sent events, resuming after the Last-Event-ID of a reconnecting client
+func TaskLogEvents(ctx *context.Context) {
+	name := ctx.Params(":name")
+	if cron.GetTask(name) == nil {
+		ctx.NotFound("GetTask", nil)
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	runID, offset := parseTaskLogEventID(ctx.Req.Header.Get("Last-Event-ID"))
+	sentDone := false
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+	ticker := time.NewTicker(taskLogPollInterval)
+	defer ticker.Stop()
+
+	write := func(event *eventsource.Event) bool {
+		if _, err := event.WriteTo(ctx.Resp); err != nil {
+			log.Error("Unable to write to the task log stream of %s: %v", name, err)
+			return false
+		}
+		return true
+	}
+
+	for {
+		var changed <-chan struct{}
+		if l := tasklog.GetManager().Get(name); l != nil {
+			if l.RunID != runID {
+				runID, offset, sentDone = l.RunID, 0, false
+				if !write(&eventsource.Event{Name: "start", Data: strconv.FormatInt(runID, 10)}) {
+					return
+				}
+			}
+
+			lines, ch, done := l.Since(offset)
+			for _, line := range lines {
+				if !write(&eventsource.Event{
+					Name: "log",
+					ID:   fmt.Sprintf("%d-%d", runID, line.Offset),
+					Data: line,
+				}) {
+					return
+				}
+				offset = line.Offset + 1
+			}
+			if !done {
+				changed = ch
+			} else if !sentDone {
+				if !write(&eventsource.Event{Name: "done", Data: strconv.FormatInt(runID, 10)}) {
+					return
+				}
+				sentDone = true
+			}
+			ctx.Resp.Flush()
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+			if !write(&eventsource.Event{Name: "ping"}) {
+				return
+			}
+			ctx.Resp.Flush()
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		}
+	}
+}
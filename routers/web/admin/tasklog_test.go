@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTaskLogEventID(t *testing.T) {
+	runID, offset := parseTaskLogEventID("3-41")
+	assert.EqualValues(t, 3, runID)
+	assert.EqualValues(t, 42, offset)
+
+	for _, id := range []string{"", "3", "a-1", "3-b"} {
+		runID, offset = parseTaskLogEventID(id)
+		assert.Zero(t, runID)
+		assert.Zero(t, offset)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+)
+
+const tplInstanceTemplates base.TplName = "admin/repo/templates"
+
+func renderInstanceTemplates(ctx *context.Context) bool {
+	ctx.Data["Title"] = ctx.Tr("admin.repositories")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminRepositories"] = true
+
+	templates, err := models.GetInstanceTemplates()
+	if err != nil {
+		ctx.ServerError("GetInstanceTemplates", err)
+		return false
+	}
+	ctx.Data["InstanceTemplates"] = templates
+	return true
+}
+
+// InstanceTemplates lists the repositories of the template catalog
+func InstanceTemplates(ctx *context.Context) {
+	if !renderInstanceTemplates(ctx) {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplInstanceTemplates)
+}
+
+// InstanceTemplatesPost adds a repository to the template catalog or updates its entry
+func InstanceTemplatesPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.AdminInstanceTemplateForm)
+	if !renderInstanceTemplates(ctx) {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplInstanceTemplates)
+		return
+	}
+
+	names := strings.SplitN(strings.TrimSpace(form.RepoName), "/", 2)
+	if len(names) != 2 {
+		ctx.Data["Err_RepoName"] = true
+		ctx.RenderWithErr(ctx.Tr("admin.repos.templates.repo_not_exist"), tplInstanceTemplates, form)
+		return
+	}
+	repo, err := models.GetRepositoryByOwnerAndName(names[0], names[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Data["Err_RepoName"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.repos.templates.repo_not_exist"), tplInstanceTemplates, form)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	t, err := models.GetInstanceTemplateByRepoID(repo.ID)
+	if err != nil {
+		if !models.IsErrInstanceTemplateNotExist(err) {
+			ctx.ServerError("GetInstanceTemplateByRepoID", err)
+			return
+		}
+		t = &models.InstanceTemplate{RepoID: repo.ID}
+	}
+	t.Category = strings.TrimSpace(form.Category)
+	t.Description = form.Description
+	if err := models.SaveInstanceTemplate(t); err != nil {
+		ctx.ServerError("SaveInstanceTemplate", err)
+		return
+	}
+	log.Trace("Template catalog entry saved: %s", repo.FullName())
+
+	ctx.Flash.Success(ctx.Tr("admin.repos.templates.save_success", repo.FullName()))
+	ctx.Redirect(setting.AppSubURL + "/admin/repos/templates")
+}
+
+// DeleteInstanceTemplate removes a repository from the template catalog
+func DeleteInstanceTemplate(ctx *context.Context) {
+	if err := models.DeleteInstanceTemplate(ctx.QueryInt64("id")); err != nil {
+		ctx.ServerError("DeleteInstanceTemplate", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.repos.templates.delete_success"))
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/admin/repos/templates",
+	})
+}
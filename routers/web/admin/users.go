@@ -36,6 +36,13 @@ func Users(ctx *context.Context) {
 	ctx.Data["PageIsAdmin"] = true
 	ctx.Data["PageIsAdminUsers"] = true
 
+	pendingCount, err := models.CountPendingRegistrations()
+	if err != nil {
+		ctx.ServerError("CountPendingRegistrations", err)
+		return
+	}
+	ctx.Data["PendingRegistrationCount"] = pendingCount
+
 	explore.RenderUserSearch(ctx, &models.SearchUserOptions{
 		Actor: ctx.User,
 		Type:  models.UserTypeIndividual,
@@ -311,6 +318,7 @@ func EditUserPost(ctx *context.Context) {
 	u.Website = form.Website
 	u.Location = form.Location
 	u.MaxRepoCreation = form.MaxRepoCreation
+	u.SizeQuota = form.SizeQuota
 	u.IsActive = form.Active
 	u.IsAdmin = form.Admin
 	u.IsRestricted = form.Restricted
@@ -377,3 +385,45 @@ func DeleteUser(ctx *context.Context) {
 		"redirect": setting.AppSubURL + "/admin/users",
 	})
 }
+
+// ViewAsUser lets the admin view the site as the user
+func ViewAsUser(ctx *context.Context) {
+	u, err := models.GetUserByID(ctx.ParamsInt64(":userid"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound("GetUserByID", err)
+		} else {
+			ctx.ServerError("GetUserByID", err)
+		}
+		return
+	}
+	if u.IsOrganization() {
+		ctx.NotFound("ViewAsUser", nil)
+		return
+	}
+	if u.ID == ctx.User.ID {
+		ctx.Flash.Error(ctx.Tr("admin.users.view_as_self"))
+		ctx.Redirect(setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"))
+		return
+	}
+
+	if err := ctx.StartViewAs(u); err != nil {
+		ctx.ServerError("StartViewAs", err)
+		return
+	}
+	ctx.Redirect(setting.AppSubURL + "/")
+}
+
+// StopViewAs ends viewing the site as another user and goes back to their account
+func StopViewAs(ctx *context.Context) {
+	u, err := ctx.StopViewAs()
+	if err != nil {
+		ctx.ServerError("StopViewAs", err)
+		return
+	}
+	if u == nil {
+		ctx.Redirect(setting.AppSubURL + "/")
+		return
+	}
+	ctx.Redirect(setting.AppSubURL + "/admin/users/" + strconv.FormatInt(u.ID, 10))
+}
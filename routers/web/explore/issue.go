@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package explore
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	// tplExploreGoodFirstIssues explore good first issues page template
+	tplExploreGoodFirstIssues base.TplName = "explore/issues"
+)
+
+// GoodFirstIssues render explore good first issues page
+func GoodFirstIssues(ctx *context.Context) {
+	ctx.Data["UsersIsDisabled"] = setting.Service.Explore.DisableUsersPage
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreGoodFirstIssues"] = true
+	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	opts := models.FindGoodFirstIssuesOptions{
+		ListOptions: models.ListOptions{Page: page, PageSize: setting.UI.ExplorePagingNum},
+		LabelNames:  setting.Repository.Issue.GoodFirstIssueLabels,
+	}
+	issues, count, err := models.FindGoodFirstIssues(opts)
+	if err != nil {
+		ctx.ServerError("FindGoodFirstIssues", err)
+		return
+	}
+	ctx.Data["Issues"] = issues
+	ctx.Data["Total"] = count
+
+	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
+	pager.SetDefaultParams(ctx)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplExploreGoodFirstIssues)
+}
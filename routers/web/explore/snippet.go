@@ -0,0 +1,57 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package explore
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	// tplExploreSnippets explore snippets page template
+	tplExploreSnippets base.TplName = "explore/snippets"
+)
+
+// Snippets render explore snippets page
+func Snippets(ctx *context.Context) {
+	ctx.Data["UsersIsDisabled"] = setting.Service.Explore.DisableUsersPage
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreSnippets"] = true
+	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	opts := &models.SearchSnippetOptions{
+		ListOptions: models.ListOptions{Page: page, PageSize: setting.UI.ExplorePagingNum},
+		Actor:       ctx.User,
+	}
+	snippets, count, err := models.SearchSnippets(opts)
+	if err != nil {
+		ctx.ServerError("SearchSnippets", err)
+		return
+	}
+	for _, s := range snippets {
+		if err = s.LoadOwner(); err != nil {
+			ctx.ServerError("LoadOwner", err)
+			return
+		}
+	}
+	ctx.Data["Snippets"] = snippets
+	ctx.Data["Total"] = count
+
+	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
+	pager.SetDefaultParams(ctx)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplExploreSnippets)
+}
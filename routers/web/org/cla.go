@@ -0,0 +1,205 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+const (
+	// tplCLA template path for render the contributor license agreement
+	tplCLA base.TplName = "org/cla"
+	// tplSettingsCLA template path for render contributor license agreement settings
+	tplSettingsCLA base.TplName = "org/settings/cla"
+)
+
+// loadCLAData renders the current contributor license agreement of the organization and whether the user signed it
+func loadCLAData(ctx *context.Context) *models.CLA {
+	org := ctx.Org.Organization
+	if !models.HasOrgOrUserVisible(org, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return nil
+	}
+
+	cla, err := models.GetCurrentCLA(org.ID)
+	if err != nil {
+		if models.IsErrCLANotExist(err) {
+			ctx.NotFound("GetCurrentCLA", err)
+		} else {
+			ctx.ServerError("GetCurrentCLA", err)
+		}
+		return nil
+	}
+
+	ctx.Data["Title"] = ctx.Tr("org.cla")
+	ctx.Data["PageIsOrgCLA"] = true
+	ctx.Data["CLA"] = cla
+	ctx.Data["CLAContent"], err = markdown.RenderString(&markup.RenderContext{
+		URLPrefix: org.HomeLink(),
+	}, cla.Content)
+	if err != nil {
+		ctx.ServerError("RenderString", err)
+		return nil
+	}
+
+	if ctx.IsSigned {
+		signature, err := models.GetLatestCLASignature(org.ID, ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("GetLatestCLASignature", err)
+			return nil
+		}
+		ctx.Data["Signature"] = signature
+		ctx.Data["HasSigned"] = signature != nil && signature.Version == cla.Version
+		ctx.Data["full_name"] = ctx.User.FullName
+	}
+	return cla
+}
+
+// CLA render the contributor license agreement of the organization
+func CLA(ctx *context.Context) {
+	if loadCLAData(ctx) == nil {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplCLA)
+}
+
+// CLAPost response for signing the contributor license agreement of the organization
+func CLAPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.SignCLAForm)
+	cla := loadCLAData(ctx)
+	if cla == nil {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplCLA)
+		return
+	}
+	if !form.Agree {
+		ctx.Data["Err_Agree"] = true
+		ctx.RenderWithErr(ctx.Tr("org.cla.must_agree"), tplCLA, form)
+		return
+	}
+
+	if _, err := models.SignCLA(cla, ctx.User, strings.TrimSpace(form.FullName)); err != nil {
+		ctx.ServerError("SignCLA", err)
+		return
+	}
+	if err := pull_service.RecheckCLAStatuses(ctx.Org.Organization); err != nil {
+		log.Error("RecheckCLAStatuses: %v", err)
+	}
+
+	ctx.Flash.Success(ctx.Tr("org.cla.signed", cla.Version))
+	ctx.Redirect(ctx.Org.OrgLink + "/cla")
+}
+
+// loadCLASettingsData loads the versions of the contributor license agreement of the organization
+// and the signatures of the current one
+func loadCLASettingsData(ctx *context.Context) {
+	org := ctx.Org.Organization
+	ctx.Data["Title"] = ctx.Tr("org.cla")
+	ctx.Data["PageIsOrgSettingsCLA"] = true
+
+	versions, err := models.GetCLAVersions(org.ID)
+	if err != nil {
+		ctx.ServerError("GetCLAVersions", err)
+		return
+	}
+	ctx.Data["Versions"] = versions
+	ctx.Data["enforce"] = org.EnforceCLA
+	if len(versions) == 0 {
+		return
+	}
+	ctx.Data["content"] = versions[0].Content
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	signatures, total, err := models.GetCLASignatures(org.ID, versions[0].Version, models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.MembersPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetCLASignatures", err)
+		return
+	}
+	ctx.Data["Signatures"] = signatures
+	ctx.Data["SignatureCount"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.MembersPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+}
+
+// SettingsCLA render the contributor license agreement settings of the organization
+func SettingsCLA(ctx *context.Context) {
+	loadCLASettingsData(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplSettingsCLA)
+}
+
+// SettingsCLAPost response for updating the contributor license agreement of the organization,
+// changing its content publishes a new version that all contributors have to sign again
+func SettingsCLAPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.OrgCLAForm)
+	org := ctx.Org.Organization
+
+	loadCLASettingsData(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsCLA)
+		return
+	}
+
+	content := strings.TrimSpace(form.Content)
+	versions := ctx.Data["Versions"].([]*models.CLA)
+	if content == "" && form.Enforce {
+		ctx.Data["Err_Content"] = true
+		ctx.RenderWithErr(ctx.Tr("org.settings.cla_required"), tplSettingsCLA, form)
+		return
+	}
+
+	changed := false
+	if content != "" && (len(versions) == 0 || content != versions[0].Content) {
+		if _, err := models.PublishCLA(org.ID, content); err != nil {
+			ctx.ServerError("PublishCLA", err)
+			return
+		}
+		changed = true
+	}
+	if org.EnforceCLA != form.Enforce {
+		org.EnforceCLA = form.Enforce
+		if err := models.UpdateUserCols(org, "enforce_cla"); err != nil {
+			ctx.ServerError("UpdateUserCols", err)
+			return
+		}
+		changed = true
+	}
+	if changed {
+		if err := pull_service.RecheckCLAStatuses(org); err != nil {
+			log.Error("RecheckCLAStatuses: %v", err)
+		}
+	}
+
+	ctx.Flash.Success(ctx.Tr("org.settings.update_setting_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/cla")
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplReviewStats base.TplName = "org/review_stats"
+
+// ReviewStats render the review statistics of each reviewer across the organization's repositories
+func ReviewStats(ctx *context.Context) {
+	if models.UnitTypePullRequests.UnitGlobalDisabled() {
+		ctx.NotFound("ReviewStats", nil)
+		return
+	}
+
+	org := ctx.Org.Organization
+	if !models.HasOrgOrUserVisible(org, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("org.review_stats")
+	ctx.Data["PageIsOrgReviewStats"] = true
+
+	stats, err := models.GetOrgReviewerStats(org, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetOrgReviewerStats", err)
+		return
+	}
+	ctx.Data["ReviewerStats"] = stats
+
+	ctx.HTML(http.StatusOK, tplReviewStats)
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const tplRoadmap base.TplName = "org/roadmap"
+
+// roadmapRepo is a repository listed on the roadmap page
+type roadmapRepo struct {
+	*models.Repository
+	Selected bool
+	Link     string
+}
+
+// Roadmap render the milestones of the organization's repositories
+func Roadmap(ctx *context.Context) {
+	if models.UnitTypeIssues.UnitGlobalDisabled() && models.UnitTypePullRequests.UnitGlobalDisabled() {
+		ctx.NotFound("Roadmap", nil)
+		return
+	}
+
+	org := ctx.Org.Organization
+	if !models.HasOrgOrUserVisible(org, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("org.roadmap")
+	ctx.Data["PageIsOrgRoadmap"] = true
+
+	repos, _, err := models.SearchRepository(&models.SearchRepoOptions{
+		Actor:         ctx.User,
+		OwnerID:       org.ID,
+		Private:       ctx.IsSigned,
+		HasMilestones: util.OptionalBoolTrue,
+	})
+	if err != nil {
+		ctx.ServerError("SearchRepository", err)
+		return
+	}
+
+	selectedIDs := make(map[int64]bool)
+	for _, id := range strings.Split(ctx.Query("repos"), ",") {
+		if repoID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64); err == nil && repoID > 0 {
+			selectedIDs[repoID] = true
+		}
+	}
+
+	// Only keep the repositories whose milestones the user can see
+	readableRepos := make([]*models.Repository, 0, len(repos))
+	for _, repo := range repos {
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if perm.CanRead(models.UnitTypeIssues) || perm.CanRead(models.UnitTypePullRequests) {
+			readableRepos = append(readableRepos, repo)
+		}
+	}
+
+	// Show the milestones of the selected repositories, or of all of them if none is selected
+	var selected []int64
+	for _, repo := range readableRepos {
+		if selectedIDs[repo.ID] {
+			selected = append(selected, repo.ID)
+		}
+	}
+	shown := selected
+	if len(shown) == 0 {
+		for _, repo := range readableRepos {
+			shown = append(shown, repo.ID)
+		}
+	}
+
+	isShowClosed := ctx.Query("state") == "closed"
+	state := "open"
+	if isShowClosed {
+		state = "closed"
+	}
+
+	roadmapRepos := make([]*roadmapRepo, 0, len(readableRepos))
+	for _, repo := range readableRepos {
+		isSelected := util.IsInt64InSlice(repo.ID, selected)
+		toggled := make([]int64, 0, len(selected)+1)
+		for _, id := range selected {
+			if id != repo.ID {
+				toggled = append(toggled, id)
+			}
+		}
+		if !isSelected {
+			toggled = append(toggled, repo.ID)
+		}
+		roadmapRepos = append(roadmapRepos, &roadmapRepo{
+			Repository: repo,
+			Selected:   isSelected,
+			Link:       fmt.Sprintf("%s/roadmap?repos=%s&state=%s", ctx.Org.OrgLink, strings.Join(base.Int64sToStrings(toggled), ","), state),
+		})
+	}
+
+	milestones, err := getRoadmapMilestones(org.ID, shown, isShowClosed)
+	if err != nil {
+		ctx.ServerError("getRoadmapMilestones", err)
+		return
+	}
+	if err := milestones.LoadRepos(); err != nil {
+		ctx.ServerError("LoadRepos", err)
+		return
+	}
+	for _, milestone := range milestones {
+		milestone.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
+			URLPrefix: milestone.Repo.Link(),
+			Metas:     milestone.Repo.ComposeMetas(),
+		}, milestone.Content)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+	}
+
+	ctx.Data["Repos"] = roadmapRepos
+	ctx.Data["ReposParam"] = strings.Join(base.Int64sToStrings(selected), ",")
+	ctx.Data["Milestones"] = milestones
+	ctx.Data["IsShowClosed"] = isShowClosed
+	ctx.HTML(http.StatusOK, tplRoadmap)
+}
+
+// getRoadmapMilestones returns the milestones of the given repositories, caching the cross repository query
+func getRoadmapMilestones(orgID int64, repoIDs []int64, isClosed bool) (models.MilestoneList, error) {
+	key := fmt.Sprintf("roadmap_%d_%t_%s", orgID, isClosed, strings.Join(base.Int64sToStrings(repoIDs), ","))
+
+	value, err := cache.GetString(key, func() (string, error) {
+		milestoneIDs, err := models.GetMilestoneIDsByRepoIDs(repoIDs, isClosed)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(base.Int64sToStrings(milestoneIDs), ","), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	milestoneIDs := make([]int64, 0, 10)
+	for _, id := range strings.Split(value, ",") {
+		if milestoneID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			milestoneIDs = append(milestoneIDs, milestoneID)
+		}
+	}
+	return models.GetMilestonesByIDs(milestoneIDs)
+}
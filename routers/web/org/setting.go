@@ -8,15 +8,18 @@ package org
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	userSetting "code.gitea.io/gitea/routers/web/user/setting"
 	"code.gitea.io/gitea/services/forms"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 const (
@@ -28,6 +31,8 @@ const (
 	tplSettingsHooks base.TplName = "org/settings/hooks"
 	// tplSettingsLabels template path for render labels settings
 	tplSettingsLabels base.TplName = "org/settings/labels"
+	// tplSettingsTokens template path for render access tokens settings
+	tplSettingsTokens base.TplName = "org/settings/tokens"
 )
 
 // Settings render the main settings page
@@ -36,6 +41,15 @@ func Settings(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["CurrentVisibility"] = ctx.Org.Organization.Visibility
 	ctx.Data["RepoAdminChangeTeamAccess"] = ctx.Org.Organization.RepoAdminChangeTeamAccess
+	ctx.Data["RequireTwoFactor"] = ctx.Org.Organization.RequireTwoFactor
+	if ctx.Org.Organization.RequireTwoFactor {
+		members, err := ctx.Org.Organization.GetMembersWithoutTwoFactor()
+		if err != nil {
+			ctx.ServerError("GetMembersWithoutTwoFactor", err)
+			return
+		}
+		ctx.Data["MembersWithoutTwoFactor"] = members
+	}
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
@@ -54,6 +68,19 @@ func SettingsPost(ctx *context.Context) {
 	org := ctx.Org.Organization
 	nameChanged := org.Name != form.Name
 
+	// Owners can't lock themselves out of the organization
+	if form.RequireTwoFactor && !org.RequireTwoFactor {
+		has, err := models.HasTwoFactorByUID(ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("HasTwoFactorByUID", err)
+			return
+		} else if !has {
+			ctx.Data["RepoAdminChangeTeamAccess"] = form.RepoAdminChangeTeamAccess
+			ctx.RenderWithErr(ctx.Tr("org.settings.require_two_factor_not_enrolled"), tplSettingsOptions, &form)
+			return
+		}
+	}
+
 	// Check if organization name has been changed.
 	if org.LowerName != strings.ToLower(form.Name) {
 		isExist, err := models.IsUserExist(org.ID, form.Name)
@@ -64,10 +91,16 @@ func SettingsPost(ctx *context.Context) {
 			ctx.Data["OrgName"] = true
 			ctx.RenderWithErr(ctx.Tr("form.username_been_taken"), tplSettingsOptions, &form)
 			return
-		} else if err = models.ChangeUserName(org, form.Name); err != nil {
+		} else if err = user_service.RenameUser(ctx.User, org, form.Name); err != nil {
 			if err == models.ErrUserNameIllegal {
 				ctx.Data["OrgName"] = true
 				ctx.RenderWithErr(ctx.Tr("form.illegal_username"), tplSettingsOptions, &form)
+			} else if models.IsErrNameReserved(err) {
+				ctx.Data["OrgName"] = true
+				ctx.RenderWithErr(ctx.Tr("org.form.name_reserved", form.Name), tplSettingsOptions, &form)
+			} else if user_service.IsErrUsernameChangeCooldown(err) {
+				ctx.Data["OrgName"] = true
+				ctx.RenderWithErr(ctx.Tr("form.username_change_cooldown", err.(user_service.ErrUsernameChangeCooldown).Until.Format("2006-01-02 15:04")), tplSettingsOptions, &form)
 			} else {
 				ctx.ServerError("ChangeUserName", err)
 			}
@@ -85,6 +118,7 @@ func SettingsPost(ctx *context.Context) {
 
 	if ctx.User.IsAdmin {
 		org.MaxRepoCreation = form.MaxRepoCreation
+		org.SizeQuota = form.SizeQuota
 	}
 
 	org.FullName = form.FullName
@@ -92,6 +126,7 @@ func SettingsPost(ctx *context.Context) {
 	org.Website = form.Website
 	org.Location = form.Location
 	org.RepoAdminChangeTeamAccess = form.RepoAdminChangeTeamAccess
+	org.RequireTwoFactor = form.RequireTwoFactor
 
 	visibilityChanged := form.Visibility != org.Visibility
 	org.Visibility = form.Visibility
@@ -217,3 +252,108 @@ func Labels(ctx *context.Context) {
 	ctx.Data["LabelTemplates"] = models.LabelTemplates
 	ctx.HTML(http.StatusOK, tplSettingsLabels)
 }
+
+// Tokens render the access tokens of the organization
+func Tokens(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.tokens")
+	ctx.Data["PageIsOrgSettingsTokens"] = true
+
+	loadTokensData(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplSettingsTokens)
+}
+
+// TokensPost response for creating an access token of the organization
+func TokensPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.NewOrgAccessTokenForm)
+	ctx.Data["Title"] = ctx.Tr("org.settings.tokens")
+	ctx.Data["PageIsOrgSettingsTokens"] = true
+
+	loadTokensData(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsTokens)
+		return
+	}
+
+	scopes := make([]string, 0, len(form.Scopes))
+	for _, scope := range form.Scopes {
+		if models.IsValidAccessTokenScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		ctx.RenderWithErr(ctx.Tr("org.settings.token_scopes_required"), tplSettingsTokens, form)
+		return
+	}
+
+	t := &models.AccessToken{
+		UID:       ctx.Org.Organization.ID,
+		CreatorID: ctx.User.ID,
+		Name:      form.Name,
+		Scope:     strings.Join(scopes, ","),
+	}
+	if form.Expires != "" {
+		expires, err := time.ParseInLocation("2006-01-02", form.Expires, setting.DefaultUILocation)
+		if err != nil || !expires.After(time.Now()) {
+			ctx.Data["Err_Expires"] = true
+			ctx.RenderWithErr(ctx.Tr("org.settings.token_expiration_invalid"), tplSettingsTokens, form)
+			return
+		}
+		t.ExpiresUnix = timeutil.TimeStamp(expires.Unix())
+	}
+
+	exist, err := models.AccessTokenByNameExists(t)
+	if err != nil {
+		ctx.ServerError("AccessTokenByNameExists", err)
+		return
+	}
+	if exist {
+		ctx.Flash.Error(ctx.Tr("settings.generate_token_name_duplicate", t.Name))
+		ctx.Redirect(ctx.Org.OrgLink + "/settings/tokens")
+		return
+	}
+
+	if err := models.NewAccessToken(t); err != nil {
+		ctx.ServerError("NewAccessToken", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.generate_token_success"))
+	ctx.Flash.Info(t.Token)
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/tokens")
+}
+
+// DeleteToken response for deleting an access token of the organization
+func DeleteToken(ctx *context.Context) {
+	if err := models.DeleteAccessTokenByID(ctx.QueryInt64("id"), ctx.Org.Organization.ID); err != nil {
+		ctx.Flash.Error("DeleteAccessTokenByID: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.delete_token_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": ctx.Org.OrgLink + "/settings/tokens",
+	})
+}
+
+func loadTokensData(ctx *context.Context) {
+	tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{UserID: ctx.Org.Organization.ID})
+	if err != nil {
+		ctx.ServerError("ListAccessTokens", err)
+		return
+	}
+	for _, t := range tokens {
+		if err := t.LoadCreator(); err != nil {
+			ctx.ServerError("LoadCreator", err)
+			return
+		}
+	}
+	ctx.Data["Tokens"] = tokens
+	ctx.Data["TokenScopes"] = models.AccessTokenScopes
+}
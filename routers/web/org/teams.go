@@ -109,6 +109,8 @@ func TeamsAction(ctx *context.Context) {
 	if err != nil {
 		if models.IsErrLastOrgOwner(err) {
 			ctx.Flash.Error(ctx.Tr("form.last_org_owner"))
+		} else if models.IsErrTwoFactorRequired(err) {
+			ctx.Flash.Error(ctx.Tr("org.teams.add_two_factor_required"))
 		} else {
 			log.Error("Action(%s): %v", ctx.Params(":action"), err)
 			ctx.JSON(http.StatusOK, map[string]interface{}{
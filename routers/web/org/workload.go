@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplWorkload base.TplName = "org/workload"
+
+// Workload render the open issues and pull requests of each assignee across the organization's repositories
+func Workload(ctx *context.Context) {
+	if models.UnitTypeIssues.UnitGlobalDisabled() && models.UnitTypePullRequests.UnitGlobalDisabled() {
+		ctx.NotFound("Workload", nil)
+		return
+	}
+
+	org := ctx.Org.Organization
+	if !models.HasOrgOrUserVisible(org, ctx.User) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("org.workload")
+	ctx.Data["PageIsOrgWorkload"] = true
+
+	workloads, err := models.GetOrgAssigneeWorkloads(org, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetOrgAssigneeWorkloads", err)
+		return
+	}
+	ctx.Data["Workloads"] = workloads
+
+	ctx.HTML(http.StatusOK, tplWorkload)
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+const (
+	tplSettingsAccessRequests base.TplName = "repo/settings/access_requests"
+)
+
+// RequestAccessPost requests access to a repository the signed in user has no access to
+func RequestAccessPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.RepoAccessRequestForm)
+
+	owner, err := models.GetUserByName(ctx.Params(":username"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound("GetUserByName", nil)
+		} else {
+			ctx.ServerError("GetUserByName", err)
+		}
+		return
+	}
+	repo, err := models.GetRepositoryByName(owner.ID, ctx.Params(":reponame"))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByName", nil)
+		} else {
+			ctx.ServerError("GetRepositoryByName", err)
+		}
+		return
+	}
+	repo.Owner = owner
+
+	if canRequest, err := repo_service.CanRequestAccess(ctx.User, repo); err != nil {
+		ctx.ServerError("CanRequestAccess", err)
+		return
+	} else if !canRequest {
+		ctx.NotFound("CanRequestAccess", nil)
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(repo.Link())
+		return
+	}
+
+	if _, err := repo_service.RequestAccess(ctx.User, repo, form.Message); err != nil {
+		if models.IsErrRepoAccessRequestAlreadyExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.access_request.already_pending"))
+			ctx.Redirect(repo.Link())
+			return
+		}
+		ctx.ServerError("RequestAccess", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.access_request.success"))
+	ctx.Redirect(repo.Link())
+}
+
+// SettingsAccessRequests lists the requests to access the repository
+func SettingsAccessRequests(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.access_requests")
+	ctx.Data["PageIsSettingsAccessRequests"] = true
+
+	state := ctx.QueryTrim("state")
+	status, ok := models.ParseRepoAccessRequestStatus(state)
+	if !ok {
+		state = models.RepoAccessRequestPending.String()
+	}
+	ctx.Data["State"] = state
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	requests, count, err := models.FindRepoAccessRequests(&models.FindRepoAccessRequestsOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.IssuePagingNum,
+		},
+		RepoID:   ctx.Repo.Repository.ID,
+		Statuses: []models.RepoAccessRequestStatus{status},
+	})
+	if err != nil {
+		ctx.ServerError("FindRepoAccessRequests", err)
+		return
+	}
+	if err := requests.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	ctx.Data["AccessRequests"] = requests
+
+	pager := context.NewPagination(int(count), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParamString("state", state)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplSettingsAccessRequests)
+}
+
+func getRepoAccessRequest(ctx *context.Context) *models.RepoAccessRequest {
+	req, err := models.GetRepoAccessRequestByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoAccessRequestNotExist(err) {
+			ctx.NotFound("GetRepoAccessRequestByID", nil)
+		} else {
+			ctx.ServerError("GetRepoAccessRequestByID", err)
+		}
+		return nil
+	}
+	return req
+}
+
+// SettingsAccessRequestApprove approves a request to access the repository with the chosen permission
+func SettingsAccessRequestApprove(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ApproveRepoAccessRequestForm)
+	req := getRepoAccessRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings/access_requests")
+		return
+	}
+
+	if err := repo_service.ApproveAccessRequest(ctx.User, req, models.ParseAccessMode(form.Permission)); err != nil {
+		if models.IsErrRepoAccessRequestNotPending(err) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.access_requests.not_pending"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings/access_requests")
+			return
+		}
+		ctx.ServerError("ApproveAccessRequest", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.access_requests.approve_success", req.User.Name))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/access_requests")
+}
+
+// SettingsAccessRequestReject rejects a request to access the repository
+func SettingsAccessRequestReject(ctx *context.Context) {
+	req := getRepoAccessRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.RejectAccessRequest(ctx.User, req); err != nil {
+		if models.IsErrRepoAccessRequestNotPending(err) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.access_requests.not_pending"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings/access_requests")
+			return
+		}
+		ctx.ServerError("RejectAccessRequest", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.access_requests.reject_success", req.User.Name))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/access_requests")
+}
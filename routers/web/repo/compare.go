@@ -22,6 +22,7 @@ import (
 	csv_module "code.gitea.io/gitea/modules/csv"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/services/gitdiff"
@@ -640,7 +641,7 @@ func CompareDiff(ctx *context.Context) {
 	ctx.Data["HeadTags"] = headTags
 
 	if ctx.Data["PageIsComparePull"] == true {
-		pr, err := models.GetUnmergedPullRequest(headRepo.ID, ctx.Repo.Repository.ID, headBranch, baseBranch)
+		pr, err := models.GetUnmergedPullRequest(headRepo.ID, ctx.Repo.Repository.ID, headBranch, baseBranch, models.PullRequestFlowGithub)
 		if err != nil {
 			if !models.IsErrPullRequestNotExist(err) {
 				ctx.ServerError("GetUnmergedPullRequest", err)
@@ -671,12 +672,28 @@ func CompareDiff(ctx *context.Context) {
 	ctx.Data["RequireTribute"] = true
 	ctx.Data["RequireSimpleMDE"] = true
 	ctx.Data["PullRequestWorkInProgressPrefixes"] = setting.Repository.PullRequest.WorkInProgressPrefixes
-	setTemplateIfExists(ctx, pullRequestTemplateKey, nil, pullRequestTemplateCandidates)
+	setTemplateIfExists(ctx, pullRequestTemplateKey, nil, context.PullRequestTemplateCandidates)
 	ctx.Data["IsAttachmentEnabled"] = setting.Attachment.Enabled
 	upload.AddUploadContext(ctx, "comment")
 
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWrite(models.UnitTypePullRequests)
 
+	// Point first-time contributors to the contributing guide
+	if ctx.IsSigned {
+		count, err := models.CountPullRequestsByPoster(ctx.Repo.Repository.ID, ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("CountPullRequestsByPoster", err)
+			return
+		}
+		if count == 0 {
+			ctx.Data["ContributingGuideLink"], err = repo_module.GetContributingGuideLink(ctx.Repo.Repository, ctx.Repo.GitRepo)
+			if err != nil {
+				ctx.ServerError("GetContributingGuideLink", err)
+				return
+			}
+		}
+	}
+
 	ctx.HTML(http.StatusOK, tplCompare)
 }
 
@@ -684,6 +684,8 @@ func UploadFilePost(ctx *context.Context) {
 		} else if models.IsErrRepoFileAlreadyExists(err) {
 			ctx.Data["Err_TreePath"] = true
 			ctx.RenderWithErr(ctx.Tr("repo.editor.file_already_exists", form.TreePath), tplUploadFile, &form)
+		} else if models.IsErrUploadCommitTooLarge(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.editor.upload_commit_too_large", base.FileSize(err.(models.ErrUploadCommitTooLarge).MaxSize)), tplUploadFile, &form)
 		} else if git.IsErrBranchNotExist(err) {
 			branchErr := err.(git.ErrBranchNotExist)
 			ctx.RenderWithErr(ctx.Tr("repo.editor.branch_does_not_exist", branchErr.Name), tplUploadFile, &form)
@@ -758,7 +760,12 @@ func UploadFileToServer(ctx *context.Context) {
 		return
 	}
 
-	name := cleanUploadFileName(header.Filename)
+	// files of a dropped directory come with their path relative to it
+	name := header.Filename
+	if fullPath := ctx.Req.FormValue("full_path"); fullPath != "" {
+		name = fullPath
+	}
+	name = cleanUploadFileName(name)
 	if len(name) == 0 {
 		ctx.Error(http.StatusInternalServerError, "Upload file name is invalid")
 		return
@@ -10,7 +10,9 @@ import (
 	"compress/gzip"
 	gocontext "context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -198,7 +200,9 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 				return
 			}
 
-			if !perm.CanAccess(accessMode, unitType) {
+			// Readers may push to refs/for/<branch> to create AGit pull requests,
+			// the pre-receive hook rejects all their other updates
+			if !perm.CanAccess(accessMode, unitType) && !(accessMode == models.AccessModeWrite && unitType == models.UnitTypeCode && repo.AllowsPulls() && perm.CanPushForPullRequest()) {
 				ctx.HandleText(http.StatusForbidden, "User permission denied")
 				return
 			}
@@ -293,7 +297,23 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 		dir = models.RepoPath(username, wikiRepoName)
 	}
 
-	return &serviceHandler{cfg, w, r, dir, cfg.Env}
+	operation := &models.GitOperation{
+		RepoID:   repo.ID,
+		IsWiki:   isWiki,
+		Protocol: models.GitOperationProtocolHTTP,
+		RemoteIP: ctx.RemoteAddr(),
+	}
+	if host, _, err := net.SplitHostPort(operation.RemoteIP); err == nil {
+		operation.RemoteIP = host
+	}
+	if ctx.User != nil {
+		operation.UserID = ctx.User.ID
+	}
+	if tokenID, ok := ctx.Data["ApiTokenID"].(int64); ok {
+		operation.TokenID = tokenID
+	}
+
+	return &serviceHandler{cfg, w, r, dir, cfg.Env, operation}
 }
 
 var (
@@ -350,6 +370,8 @@ type serviceHandler struct {
 	r       *http.Request
 	dir     string
 	environ []string
+	// operation is the GitOperation recorded for the RPCs
+	operation *models.GitOperation
 }
 
 func (h *serviceHandler) setHeaderNoCache() {
@@ -442,6 +464,16 @@ func hasAccess(service string, h serviceHandler, checkContentType bool) bool {
 	return getConfigSetting(service, h.dir)
 }
 
+// serviceConfigArgs returns the git configuration arguments for the service,
+// upload-pack serves filtered fetches (partial clones) unless they are disabled.
+// Arbitrary objects are never served by their ID, they could be unreachable leftovers of deleted branches.
+func serviceConfigArgs(service string) []string {
+	if service != "upload-pack" || setting.Git.DisablePartialClone || git.CheckGitVersionAtLeast("2.22") != nil {
+		return []string{}
+	}
+	return []string{"-c", "uploadpack.allowFilter=true"}
+}
+
 func serviceRPC(h serviceHandler, service string) {
 	defer func() {
 		if err := h.r.Body.Close(); err != nil {
@@ -477,25 +509,66 @@ func serviceRPC(h serviceHandler, service string) {
 		h.environ = append(h.environ, "GIT_PROTOCOL="+protocol)
 	}
 
+	operation := *h.operation
+	operation.Type = models.GitOperationTypeFromService(service)
+	if err := models.CreateGitOperation(&operation); err != nil {
+		log.Error("Unable to record the git operation in %s: %v", h.dir, err)
+		h.w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.environ = append(h.environ, models.EnvGitOperationID+fmt.Sprintf("=%d", operation.ID))
+
 	ctx, cancel := gocontext.WithCancel(git.DefaultContext)
 	defer cancel()
 	var stderr bytes.Buffer
-	cmd := exec.CommandContext(ctx, git.GitExecutable, service, "--stateless-rpc", h.dir)
+	stdin := &countingReader{r: reqBody}
+	stdout := &countingWriter{w: h.w}
+	args := append(serviceConfigArgs(service), service, "--stateless-rpc", h.dir)
+	cmd := exec.CommandContext(ctx, git.GitExecutable, args...)
 	cmd.Dir = h.dir
 	cmd.Env = append(os.Environ(), h.environ...)
-	cmd.Stdout = h.w
-	cmd.Stdin = reqBody
+	cmd.Stdout = stdout
+	cmd.Stdin = stdin
 	cmd.Stderr = &stderr
 
-	pid := process.GetManager().Add(fmt.Sprintf("%s %s %s [repo_path: %s]", git.GitExecutable, service, "--stateless-rpc", h.dir), cancel)
+	pid := process.GetManager().Add(fmt.Sprintf("%s %s [repo_path: %s]", git.GitExecutable, strings.Join(args, " "), h.dir), cancel)
 	defer process.GetManager().Remove(pid)
 
-	if err := cmd.Run(); err != nil {
+	start := time.Now()
+	err = cmd.Run()
+	if err := models.FinishGitOperation(operation.ID, stdin.n, stdout.n, time.Since(start), err == nil); err != nil {
+		log.Error("Unable to finish git operation %d: %v", operation.ID, err)
+	}
+	if err != nil {
 		log.Error("Fail to serve RPC(%s) in %s: %v - %s", service, h.dir, err, stderr.String())
 		return
 	}
 }
 
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // ServiceUploadPack implements Git Smart HTTP protocol
 func ServiceUploadPack(ctx *context.Context) {
 	h := httpBase(ctx)
@@ -551,7 +624,8 @@ func GetInfoRefs(ctx *context.Context) {
 		}
 		h.environ = append(os.Environ(), h.environ...)
 
-		refs, err := git.NewCommand(service, "--stateless-rpc", "--advertise-refs", ".").RunInDirTimeoutEnv(h.environ, -1, h.dir)
+		args := append(serviceConfigArgs(service), service, "--stateless-rpc", "--advertise-refs", ".")
+		refs, err := git.NewCommand(args...).RunInDirTimeoutEnv(h.environ, -1, h.dir)
 		if err != nil {
 			log.Error(fmt.Sprintf("%v - %s", err, string(refs)))
 		}
@@ -29,6 +29,7 @@ import (
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/antispam"
 	comment_service "code.gitea.io/gitea/services/comments"
 	"code.gitea.io/gitea/services/forms"
 	issue_service "code.gitea.io/gitea/services/issue"
@@ -266,6 +267,13 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	ctx.Data["Issues"] = issues
 	ctx.Data["CommitStatus"] = commitStatus
 
+	if isPullOption == util.OptionalBoolTrue {
+		if ctx.Data["FirstTimeContributors"], ctx.Data["ExternalContributions"], err = getContributorIndicators(repo, issues); err != nil {
+			ctx.ServerError("getContributorIndicators", err)
+			return
+		}
+	}
+
 	// Get assignees.
 	ctx.Data["Assignees"], err = repo.GetAssignees()
 	if err != nil {
@@ -331,6 +339,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	ctx.Data["SelectLabels"] = selectLabels
 	ctx.Data["ViewType"] = viewType
 	ctx.Data["SortType"] = sortType
+	ctx.Data["IsVotingEnabled"] = repo.IsVotingEnabled()
 	ctx.Data["MilestoneID"] = milestoneID
 	ctx.Data["AssigneeID"] = assigneeID
 	ctx.Data["IsShowClosed"] = isShowClosed
@@ -759,6 +768,23 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 			}
 			ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
 			ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
+
+			assigneeIDs := make([]string, 0, len(meta.Assignees))
+			selectedAssignees := make(map[int64]bool, len(meta.Assignees))
+			if assignees, ok := ctx.Data["Assignees"].([]*models.User); ok {
+				for _, metaAssignee := range meta.Assignees {
+					for _, assignee := range assignees {
+						if strings.EqualFold(assignee.Name, metaAssignee) {
+							selectedAssignees[assignee.ID] = true
+							assigneeIDs = append(assigneeIDs, fmt.Sprintf("%d", assignee.ID))
+							break
+						}
+					}
+				}
+			}
+			ctx.Data["SelectedAssignees"] = selectedAssignees
+			ctx.Data["HasSelectedAssignee"] = len(assigneeIDs) > 0
+			ctx.Data["assignee_ids"] = strings.Join(assigneeIDs, ",")
 			return
 		}
 	}
@@ -780,6 +806,7 @@ func NewIssue(ctx *context.Context) {
 
 	ctx.Data["IsProjectsEnabled"] = ctx.Repo.CanRead(models.UnitTypeProjects)
 	ctx.Data["IsAttachmentEnabled"] = setting.Attachment.Enabled
+	ctx.Data["IncomingEmailAddress"] = ctx.Repo.Repository.IncomingSubmissionAddress(ctx.User)
 	upload.AddUploadContext(ctx, "comment")
 
 	milestoneID := ctx.QueryInt64("milestone")
@@ -979,6 +1006,9 @@ func NewIssuePost(ctx *context.Context) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if antispam.IsErrContentBlocked(err) {
+			ctx.RenderWithErr(contentBlockedMessage(ctx, err), tplIssueNew, form)
+			return
 		}
 		ctx.ServerError("NewIssue", err)
 		return
@@ -1042,6 +1072,43 @@ func getBranchData(ctx *context.Context, issue *models.Issue) {
 	}
 }
 
+// getContributorIndicators returns which pull requests come from first-time contributors
+// and which ones from non-members of the repository, by issue ID
+func getContributorIndicators(repo *models.Repository, issues []*models.Issue) (firstTime, external map[int64]bool, err error) {
+	posterIDs := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		if issue.IsPull && !util.IsInt64InSlice(issue.PosterID, posterIDs) {
+			posterIDs = append(posterIDs, issue.PosterID)
+		}
+	}
+	mergedCounts, err := models.CountMergedPullRequestsByPosters(repo.ID, posterIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isMember := make(map[int64]bool, len(posterIDs))
+	for _, posterID := range posterIDs {
+		if isMember[posterID], err = repo.IsContributorMember(posterID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	firstTime = make(map[int64]bool, len(issues))
+	external = make(map[int64]bool, len(issues))
+	for _, issue := range issues {
+		if !issue.IsPull || issue.PullRequest == nil {
+			continue
+		}
+		count := mergedCounts[issue.PosterID]
+		if issue.PullRequest.HasMerged {
+			count--
+		}
+		firstTime[issue.ID] = count <= 0
+		external[issue.ID] = !isMember[issue.PosterID]
+	}
+	return firstTime, external, nil
+}
+
 // ViewIssue render issue view page
 func ViewIssue(ctx *context.Context) {
 	if ctx.Params(":type") == "issues" {
@@ -1219,7 +1286,7 @@ func ViewIssue(ctx *context.Context) {
 		}
 	}
 
-	if ctx.IsSigned {
+	if ctx.IsSigned && ctx.ViewAsAdmin == nil {
 		// Update issue-user.
 		if err = issue.ReadBy(ctx.User.ID); err != nil {
 			ctx.ServerError("ReadBy", err)
@@ -1517,6 +1584,14 @@ func ViewIssue(ctx *context.Context) {
 				ctx.Data["MergeStyle"] = ""
 			}
 		}
+		if !pull.HasMerged && !issue.IsClosed {
+			if claStatus, err := pull_service.GetCLAStatus(pull); err != nil {
+				log.Error("GetCLAStatus: %v", err)
+			} else if claStatus != nil {
+				ctx.Data["CLAStatus"] = claStatus
+				ctx.Data["IsBlockedByCLA"] = !claStatus.IsSigned()
+			}
+		}
 		if err = pull.LoadProtectedBranch(); err != nil {
 			ctx.ServerError("LoadProtectedBranch", err)
 			return
@@ -1527,6 +1602,12 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["IsBlockedByRejection"] = pull.ProtectedBranch.MergeBlockedByRejectedReview(pull)
 			ctx.Data["IsBlockedByOfficialReviewRequests"] = pull.ProtectedBranch.MergeBlockedByOfficialReviewRequests(pull)
 			ctx.Data["IsBlockedByOutdatedBranch"] = pull.ProtectedBranch.MergeBlockedByOutdatedBranch(pull)
+			isBlockedByCodeOwners, err := pull_service.MergeBlockedByCodeOwners(pull)
+			if err != nil {
+				ctx.ServerError("MergeBlockedByCodeOwners", err)
+				return
+			}
+			ctx.Data["IsBlockedByCodeOwners"] = isBlockedByCodeOwners
 			ctx.Data["GrantedApprovals"] = cnt
 			ctx.Data["RequireSigned"] = pull.ProtectedBranch.RequireSignedCommits
 			ctx.Data["ChangedProtectedFiles"] = pull.ChangedProtectedFiles
@@ -1551,6 +1632,7 @@ func ViewIssue(ctx *context.Context) {
 		}
 		ctx.Data["IsPullBranchDeletable"] = canDelete &&
 			pull.HeadRepo != nil &&
+			pull.Flow == models.PullRequestFlowGithub &&
 			git.IsBranchExist(pull.HeadRepo.RepoPath(), pull.HeadBranch) &&
 			(!pull.HasMerged || ctx.Data["HeadBranchCommitID"] == ctx.Data["PullHeadCommitID"])
 
@@ -1569,6 +1651,36 @@ func ViewIssue(ctx *context.Context) {
 		}
 
 		ctx.Data["StillCanManualMerge"] = stillCanManualMerge()
+
+		if !pull.HasMerged && !issue.IsClosed {
+			ctx.Data["PullDeployments"], err = models.FindDeployments(models.FindDeploymentsOptions{
+				RepoID:     repo.ID,
+				PullID:     pull.ID,
+				OnlyActive: true,
+			})
+			if err != nil {
+				ctx.ServerError("FindDeployments", err)
+				return
+			}
+
+			scheduledMerge, err := models.GetScheduledMergeByPullID(pull.ID)
+			if err != nil {
+				ctx.ServerError("GetScheduledMergeByPullID", err)
+				return
+			}
+			if scheduledMerge != nil {
+				if err := scheduledMerge.LoadDoer(); err != nil && !models.IsErrUserNotExist(err) {
+					ctx.ServerError("LoadDoer", err)
+					return
+				}
+				if scheduledMerge.Doer == nil {
+					scheduledMerge.Doer = models.NewGhostUser()
+				}
+				ctx.Data["PullAutoMerge"] = scheduledMerge
+				allowMerge, _ := ctx.Data["AllowMerge"].(bool)
+				ctx.Data["CanCancelAutoMerge"] = ctx.IsSigned && (allowMerge || scheduledMerge.DoerID == ctx.User.ID)
+			}
+		}
 	}
 
 	// Get Dependencies
@@ -1597,6 +1709,19 @@ func ViewIssue(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplIssueView)
 }
 
+// contentBlockedMessage returns the message shown to the author of content blocked by the content classifier
+func contentBlockedMessage(ctx *context.Context, err error) string {
+	blocked := err.(antispam.ErrContentBlocked)
+	switch {
+	case blocked.Unavailable:
+		return ctx.Tr("repo.issues.content_classifier_unavailable")
+	case blocked.Message != "":
+		return ctx.Tr("repo.issues.content_blocked_message", blocked.Message)
+	default:
+		return ctx.Tr("repo.issues.content_blocked")
+	}
+}
+
 // GetActionIssue will return the issue which is used in the context.
 func GetActionIssue(ctx *context.Context) *models.Issue {
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
@@ -1677,6 +1802,10 @@ func UpdateIssueTitle(ctx *context.Context) {
 	}
 
 	if err := issue_service.ChangeTitle(issue, ctx.User, title); err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, contentBlockedMessage(ctx, err))
+			return
+		}
 		ctx.ServerError("ChangeTitle", err)
 		return
 	}
@@ -1724,6 +1853,10 @@ func UpdateIssueContent(ctx *context.Context) {
 
 	content := ctx.Query("content")
 	if err := issue_service.ChangeContent(issue, ctx.User, content); err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, contentBlockedMessage(ctx, err))
+			return
+		}
 		ctx.ServerError("ChangeContent", err)
 		return
 	}
@@ -2047,7 +2180,7 @@ func NewComment(ctx *context.Context) {
 			if form.Status == "reopen" && issue.IsPull {
 				pull := issue.PullRequest
 				var err error
-				pr, err = models.GetUnmergedPullRequest(pull.HeadRepoID, pull.BaseRepoID, pull.HeadBranch, pull.BaseBranch)
+				pr, err = models.GetUnmergedPullRequest(pull.HeadRepoID, pull.BaseRepoID, pull.HeadBranch, pull.BaseBranch, pull.Flow)
 				if err != nil {
 					if !models.IsErrPullRequestNotExist(err) {
 						ctx.ServerError("GetUnmergedPullRequest", err)
@@ -2108,6 +2241,10 @@ func NewComment(ctx *context.Context) {
 
 	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Content, attachments)
 	if err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Flash.Error(contentBlockedMessage(ctx, err))
+			return
+		}
 		ctx.ServerError("CreateIssueComment", err)
 		return
 	}
@@ -2152,6 +2289,10 @@ func UpdateCommentContent(ctx *context.Context) {
 		return
 	}
 	if err = comment_service.UpdateComment(comment, ctx.User, oldContent); err != nil {
+		if antispam.IsErrContentBlocked(err) {
+			ctx.Error(http.StatusUnprocessableEntity, contentBlockedMessage(ctx, err))
+			return
+		}
 		ctx.ServerError("UpdateComment", err)
 		return
 	}
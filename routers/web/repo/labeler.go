@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+const (
+	tplSettingsLabeler base.TplName = "repo/settings/labeler"
+)
+
+// SettingsLabeler renders the labeler rules of the repository and previews the labels they would add to a pull request
+func SettingsLabeler(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.labeler")
+	ctx.Data["PageIsSettingsLabeler"] = true
+	ctx.Data["LabelerConfigPath"] = pull_service.LabelerConfigPath
+
+	rules, err := pull_service.GetLabelerRules(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	if err != nil {
+		ctx.Data["LabelerError"] = err.Error()
+		ctx.HTML(http.StatusOK, tplSettingsLabeler)
+		return
+	}
+	ctx.Data["LabelerRules"] = rules
+
+	index := ctx.QueryInt64("pull")
+	if index <= 0 {
+		ctx.HTML(http.StatusOK, tplSettingsLabeler)
+		return
+	}
+	ctx.Data["PullIndex"] = index
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, index)
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.Data["LabelerError"] = ctx.Tr("repo.settings.labeler.pull_not_exist", index)
+			ctx.HTML(http.StatusOK, tplSettingsLabeler)
+		} else {
+			ctx.ServerError("GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	files, labelNames, err := pull_service.GetLabelerChanges(pr, ctx.Repo.GitRepo, rules)
+	if err != nil {
+		ctx.ServerError("GetLabelerChanges", err)
+		return
+	}
+
+	matched, missing, err := pull_service.FindLabelerLabels(ctx.Repo.Repository, labelNames)
+	if err != nil {
+		ctx.ServerError("FindLabelerLabels", err)
+		return
+	}
+
+	ctx.Data["PullRequest"] = pr
+	ctx.Data["ChangedFiles"] = files
+	ctx.Data["MatchedLabels"] = matched
+	ctx.Data["MissingLabels"] = strings.Join(missing, ", ")
+	ctx.HTML(http.StatusOK, tplSettingsLabeler)
+}
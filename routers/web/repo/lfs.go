@@ -85,6 +85,13 @@ func LFSLocks(ctx *context.Context) {
 	}
 	ctx.Data["Total"] = total
 
+	stale, err := models.CountStaleLFSLockByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("LFSLocks", err)
+		return
+	}
+	ctx.Data["StaleTotal"] = stale
+
 	pager := context.NewPagination(int(total), setting.UI.ExplorePagingNum, page, 5)
 	ctx.Data["Title"] = ctx.Tr("repo.settings.lfs_locks")
 	ctx.Data["PageIsSettingsLFS"] = true
@@ -245,6 +252,42 @@ func LFSUnlock(ctx *context.Context) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/lfs/locks")
 }
 
+// LFSUnlockSelected forcibly unlocks the selected LFS locks
+func LFSUnlockSelected(ctx *context.Context) {
+	if !setting.LFS.StartServer {
+		ctx.NotFound("LFSUnlockSelected", nil)
+		return
+	}
+	ids := make([]int64, 0, 10)
+	for _, s := range ctx.QueryStrings("ids") {
+		if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	lfsLocks, err := models.DeleteLFSLocksByIDs(ctx.Repo.Repository.ID, ids)
+	if err != nil {
+		ctx.ServerError("DeleteLFSLocksByIDs", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.settings.lfs_locks_unlocked", len(lfsLocks)))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/lfs/locks")
+}
+
+// LFSUnlockStale forcibly unlocks the LFS locks whose owners have been removed
+func LFSUnlockStale(ctx *context.Context) {
+	if !setting.LFS.StartServer {
+		ctx.NotFound("LFSUnlockStale", nil)
+		return
+	}
+	lfsLocks, err := models.DeleteStaleLFSLocks(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("DeleteStaleLFSLocks", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.settings.lfs_locks_unlocked", len(lfsLocks)))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/lfs/locks")
+}
+
 // LFSFileGet serves a single LFS file
 func LFSFileGet(ctx *context.Context) {
 	if !setting.LFS.StartServer {
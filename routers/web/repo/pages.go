@@ -0,0 +1,186 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	pages_service "code.gitea.io/gitea/services/pages"
+)
+
+const (
+	tplSettingsPages base.TplName = "repo/settings/pages"
+)
+
+var pagesDomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+
+// ServePages serves the deployed static site of the repository
+func ServePages(ctx *context.Context) {
+	p, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if models.IsErrRepoPagesNotExist(err) {
+			ctx.NotFound("GetRepoPages", nil)
+		} else {
+			ctx.ServerError("GetRepoPages", err)
+		}
+		return
+	}
+
+	// the site shares the origin of this instance, so its scripts must not act on behalf of its visitors
+	ctx.Resp.Header().Set("Content-Security-Policy", "sandbox allow-scripts allow-forms allow-popups allow-modals")
+	pages_service.ServeSite(ctx.Resp, ctx.Req, p, ctx.Params("*"))
+}
+
+func setPagesContext(ctx *context.Context) *models.RepoPages {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.pages")
+	ctx.Data["PageIsSettingsPages"] = true
+	ctx.Data["AllowCustomDomains"] = setting.Pages.AllowCustomDomains
+	ctx.Data["PagesURL"] = ctx.Repo.Repository.HTMLURL() + "/pages/"
+
+	p, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrRepoPagesNotExist(err) {
+			ctx.ServerError("GetRepoPages", err)
+			return nil
+		}
+		ctx.Data["branch"] = setting.Pages.DefaultBranch
+		return nil
+	}
+
+	ctx.Data["Pages"] = p
+	ctx.Data["branch"] = p.Branch
+	ctx.Data["folder"] = p.Folder
+	ctx.Data["domain"] = p.Domain
+	if p.Domain != "" && setting.Pages.AllowCustomDomains && p.DomainVerified {
+		ctx.Data["PagesDomainURL"] = strings.SplitN(setting.AppURL, ":", 2)[0] + "://" + p.Domain + "/"
+	}
+	return p
+}
+
+// SettingsPages renders the settings of the pages of the repository
+func SettingsPages(ctx *context.Context) {
+	setPagesContext(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsPages)
+}
+
+// SettingsPagesPost enables the pages of the repository or updates their settings, and deploys them
+func SettingsPagesPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.RepoPagesForm)
+	p := setPagesContext(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsPages)
+		return
+	}
+
+	if p == nil {
+		p = &models.RepoPages{RepoID: ctx.Repo.Repository.ID}
+	}
+	p.Branch = strings.TrimSpace(form.Branch)
+	p.Folder = strings.Trim(strings.TrimSpace(form.Folder), "/")
+	if setting.Pages.AllowCustomDomains {
+		p.Domain = strings.ToLower(strings.TrimSpace(form.Domain))
+		if p.Domain != "" && (!pagesDomainPattern.MatchString(p.Domain) || p.Domain == strings.ToLower(setting.Domain)) {
+			ctx.Data["Err_Domain"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.pages.domain_invalid"), tplSettingsPages, form)
+			return
+		}
+	}
+
+	if err := models.SaveRepoPages(p); err != nil {
+		if models.IsErrPagesDomainInUse(err) {
+			ctx.Data["Err_Domain"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.pages.domain_in_use", p.Domain), tplSettingsPages, form)
+			return
+		}
+		ctx.ServerError("SaveRepoPages", err)
+		return
+	}
+	if err := pages_service.AddToQueue(p); err != nil {
+		ctx.ServerError("AddToQueue", err)
+		return
+	}
+
+	log.Trace("Pages of repository updated: %s", ctx.Repo.Repository.FullName())
+	ctx.Flash.Success(ctx.Tr("repo.settings.pages.update_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/pages")
+}
+
+// SettingsPagesDeploy deploys the pages of the repository again
+func SettingsPagesDeploy(ctx *context.Context) {
+	p, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if models.IsErrRepoPagesNotExist(err) {
+			ctx.NotFound("GetRepoPages", nil)
+		} else {
+			ctx.ServerError("GetRepoPages", err)
+		}
+		return
+	}
+	if err = pages_service.AddToQueue(p); err != nil {
+		ctx.ServerError("AddToQueue", err)
+		return
+	}
+
+	ctx.Flash.Info(ctx.Tr("repo.settings.pages.deploy_queued"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/pages")
+}
+
+// SettingsPagesVerify checks the DNS record proving the custom domain of the pages belongs to the repository admins
+func SettingsPagesVerify(ctx *context.Context) {
+	p, err := models.GetRepoPages(ctx.Repo.Repository.ID)
+	if err != nil {
+		if models.IsErrRepoPagesNotExist(err) {
+			ctx.NotFound("GetRepoPages", nil)
+		} else {
+			ctx.ServerError("GetRepoPages", err)
+		}
+		return
+	}
+	if p.Domain == "" || !setting.Pages.AllowCustomDomains {
+		ctx.NotFound("VerifyDomain", nil)
+		return
+	}
+
+	verified, err := pages_service.VerifyDomain(p)
+	if err != nil {
+		ctx.ServerError("VerifyDomain", err)
+		return
+	}
+	if verified {
+		log.Trace("Pages domain of repository %s verified: %s", ctx.Repo.Repository.FullName(), p.Domain)
+		ctx.Flash.Success(ctx.Tr("repo.settings.pages.domain_verify_success", p.Domain))
+	} else {
+		ctx.Flash.Error(ctx.Tr("repo.settings.pages.domain_verify_failed", p.DomainVerificationRecord()))
+	}
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/pages")
+}
+
+// SettingsPagesDelete disables the pages of the repository
+func SettingsPagesDelete(ctx *context.Context) {
+	if err := pages_service.DeleteSite(ctx.Repo.Repository.ID); err != nil {
+		ctx.ServerError("DeleteSite", err)
+		return
+	}
+
+	log.Trace("Pages of repository disabled: %s", ctx.Repo.Repository.FullName())
+	ctx.Flash.Success(ctx.Tr("repo.settings.pages.deletion_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/pages")
+}
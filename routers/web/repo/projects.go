@@ -520,6 +520,20 @@ func EditProjectBoard(ctx *context.Context) {
 		return
 	}
 
+	if form.AutoMove != nil {
+		autoMove, ok := models.ProjectBoardAutoMoveFromName(*form.AutoMove)
+		if !ok {
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"message": fmt.Sprintf("Unknown automatic move event: %s", *form.AutoMove),
+			})
+			return
+		}
+		if err := models.SetProjectBoardAutoMove(board, autoMove); err != nil {
+			ctx.ServerError("SetProjectBoardAutoMove", err)
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"ok": true,
 	})
@@ -29,6 +29,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/routers/utils"
+	"code.gitea.io/gitea/services/antispam"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/gitdiff"
 	pull_service "code.gitea.io/gitea/services/pull"
@@ -45,17 +46,6 @@ const (
 	pullRequestTemplateKey = "PullRequestTemplate"
 )
 
-var (
-	pullRequestTemplateCandidates = []string{
-		"PULL_REQUEST_TEMPLATE.md",
-		"pull_request_template.md",
-		".gitea/PULL_REQUEST_TEMPLATE.md",
-		".gitea/pull_request_template.md",
-		".github/PULL_REQUEST_TEMPLATE.md",
-		".github/pull_request_template.md",
-	}
-)
-
 func getRepository(ctx *context.Context, repoID int64) *models.Repository {
 	repo, err := models.GetRepositoryByID(repoID)
 	if err != nil {
@@ -282,7 +272,7 @@ func checkPullInfo(ctx *context.Context) *models.Issue {
 		return nil
 	}
 
-	if ctx.IsSigned {
+	if ctx.IsSigned && ctx.ViewAsAdmin == nil {
 		// Update issue-user.
 		if err = issue.ReadBy(ctx.User.ID); err != nil {
 			ctx.ServerError("ReadBy", err)
@@ -297,7 +287,7 @@ func setMergeTarget(ctx *context.Context, pull *models.PullRequest) {
 	if ctx.Repo.Owner.Name == pull.MustHeadUserName() {
 		ctx.Data["HeadTarget"] = pull.HeadBranch
 	} else if pull.HeadRepo == nil {
-		ctx.Data["HeadTarget"] = pull.MustHeadUserName() + ":" + pull.HeadBranch
+		ctx.Data["HeadTarget"] = "<deleted>:" + pull.HeadBranch
 	} else {
 		ctx.Data["HeadTarget"] = pull.MustHeadUserName() + "/" + pull.HeadRepo.Name + ":" + pull.HeadBranch
 	}
@@ -427,12 +417,12 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		}
 		defer headGitRepo.Close()
 
-		headBranchExist = headGitRepo.IsBranchExist(pull.HeadBranch)
+		headBranchExist = headGitRepo.IsReferenceExist(pull.GetHeadRefName())
 
 		if headBranchExist {
-			headBranchSha, err = headGitRepo.GetBranchCommitID(pull.HeadBranch)
+			headBranchSha, err = headGitRepo.GetRefCommitID(pull.GetHeadRefName())
 			if err != nil {
-				ctx.ServerError("GetBranchCommitID", err)
+				ctx.ServerError("GetRefCommitID", err)
 				return nil
 			}
 		}
@@ -444,7 +434,6 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 			ctx.ServerError("IsUserAllowedToUpdate", err)
 			return nil
 		}
-		ctx.Data["GetCommitMessages"] = pull_service.GetSquashMergeCommitMessages(pull)
 	}
 
 	sha, err := baseGitRepo.GetRefCommitID(pull.GetGitRefName())
@@ -467,6 +456,15 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		return nil
 	}
 
+	// The head of a pull request whose head repository has been deleted is kept in its pull request ref
+	if pull.HeadRepo == nil {
+		headBranchExist = true
+		headBranchSha = sha
+	}
+	if headBranchExist {
+		ctx.Data["GetCommitMessages"] = pull_service.GetSquashMergeCommitMessages(pull)
+	}
+
 	commitStatuses, err := models.GetLatestCommitStatus(repo.ID, sha, models.ListOptions{})
 	if err != nil {
 		ctx.ServerError("GetLatestCommitStatus", err)
@@ -493,12 +491,12 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 	ctx.Data["HeadBranchCommitID"] = headBranchSha
 	ctx.Data["PullHeadCommitID"] = sha
 
-	if pull.HeadRepo == nil || !headBranchExist || headBranchSha != sha {
+	if pull.HeadRepo == nil {
+		ctx.Data["HeadTarget"] = "<deleted>:" + pull.HeadBranch
+	} else if !headBranchExist || headBranchSha != sha {
 		ctx.Data["IsPullRequestBroken"] = true
 		if pull.IsSameRepo() {
 			ctx.Data["HeadTarget"] = pull.HeadBranch
-		} else if pull.HeadRepo == nil {
-			ctx.Data["HeadTarget"] = "<deleted>:" + pull.HeadBranch
 		} else {
 			ctx.Data["HeadTarget"] = pull.HeadRepo.OwnerName + ":" + pull.HeadBranch
 		}
@@ -529,6 +527,7 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 	if pull.IsFilesConflicted() {
 		ctx.Data["IsPullFilesConflicted"] = true
 		ctx.Data["ConflictedFiles"] = pull.ConflictedFiles
+		ctx.Data["CanResolveConflicts"] = pull_service.GetConflictResolver() != nil
 	}
 
 	ctx.Data["NumCommits"] = compareInfo.Commits.Len()
@@ -691,6 +690,11 @@ func ViewPullFiles(ctx *context.Context) {
 		ctx.ServerError("GetCurrentReview", err)
 		return
 	}
+	ctx.Data["ReviewChecklist"], err = pull_service.GetReviewChecklist(ctx.Repo.GitRepo, pull)
+	if err != nil {
+		ctx.ServerError("GetReviewChecklist", err)
+		return
+	}
 	getBranchData(ctx, issue)
 	ctx.Data["IsIssuePoster"] = ctx.IsSigned && issue.IsPoster(ctx.User.ID)
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
@@ -701,6 +705,49 @@ func ViewPullFiles(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplPullFiles)
 }
 
+// SearchPullFiles searches the names and changed lines of the files of a pull request
+func SearchPullFiles(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pull := issue.PullRequest
+
+	gitRepo := ctx.Repo.GitRepo
+	headCommitID, err := gitRepo.GetRefCommitID(pull.GetGitRefName())
+	if err != nil {
+		ctx.ServerError("GetRefCommitID", err)
+		return
+	}
+	mergeBase := pull.MergeBase
+	if mergeBase == "" {
+		mergeBase, _, err = gitRepo.GetMergeBase("", git.BranchPrefix+pull.BaseBranch, headCommitID)
+		if err != nil {
+			ctx.ServerError("GetMergeBase", err)
+			return
+		}
+	}
+
+	diff, err := gitdiff.GetDiffRangeWithWhitespaceBehavior(gitRepo.Path,
+		mergeBase, headCommitID, setting.Git.MaxGitDiffLines,
+		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
+		gitdiff.GetWhitespaceFlag(ctx.Data["WhitespaceBehavior"].(string)))
+	if err != nil {
+		ctx.ServerError("GetDiffRangeWithWhitespaceBehavior", err)
+		return
+	}
+
+	results, hasMore := diff.Search(strings.TrimSpace(ctx.Query("q")), gitdiff.SearchContextLines, gitdiff.SearchMaxFiles)
+	if results == nil {
+		results = []*gitdiff.DiffSearchResult{}
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"files":         results,
+		"has_more":      hasMore,
+		"is_incomplete": diff.IsIncomplete,
+	})
+}
+
 // UpdatePullRequest merge PR's baseBranch into headBranch
 func UpdatePullRequest(ctx *context.Context) {
 	issue := checkPullInfo(ctx)
@@ -826,7 +873,7 @@ func MergePullRequest(ctx *context.Context) {
 		return
 	}
 
-	if !pr.CanAutoMerge() {
+	if !pull_service.CanMergeWithStyle(pr, models.MergeStyle(form.Do)) {
 		ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
 		return
@@ -838,12 +885,16 @@ func MergePullRequest(ctx *context.Context) {
 		return
 	}
 
+	// the pull request is scheduled to be merged when it is not ready yet and its checks should succeed first
+	scheduleMerge := false
 	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
 		if !models.IsErrNotAllowedToMerge(err) {
 			ctx.ServerError("Merge PR status", err)
 			return
 		}
-		if isRepoAdmin, err := models.IsUserRepoAdmin(pr.BaseRepo, ctx.User); err != nil {
+		if form.MergeWhenChecksSucceed {
+			scheduleMerge = true
+		} else if isRepoAdmin, err := models.IsUserRepoAdmin(pr.BaseRepo, ctx.User); err != nil {
 			ctx.ServerError("IsUserRepoAdmin", err)
 			return
 		} else if !isRepoAdmin {
@@ -891,6 +942,21 @@ func MergePullRequest(ctx *context.Context) {
 		return
 	}
 
+	if scheduleMerge {
+		if err := models.ScheduleAutoMerge(ctx.User, pr, models.MergeStyle(form.Do), message); err != nil {
+			if models.IsErrPullAlreadyScheduledToAutoMerge(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.auto_merge_already_scheduled"))
+				ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+				return
+			}
+			ctx.ServerError("ScheduleAutoMerge", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.auto_merge_newly_scheduled"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+		return
+	}
+
 	if err = pull_service.Merge(pr, ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
@@ -966,7 +1032,7 @@ func MergePullRequest(ctx *context.Context) {
 
 	log.Trace("Pull request merged: %d", pr.ID)
 
-	if form.DeleteBranchAfterMerge {
+	if form.DeleteBranchAfterMerge && pr.Flow == models.PullRequestFlowGithub && pr.HeadRepo != nil {
 		var headRepo *git.Repository
 		if ctx.Repo != nil && ctx.Repo.Repository != nil && pr.HeadRepoID == ctx.Repo.Repository.ID && ctx.Repo.GitRepo != nil {
 			headRepo = ctx.Repo.GitRepo
@@ -984,6 +1050,45 @@ func MergePullRequest(ctx *context.Context) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
 }
 
+// CancelAutoMergePullRequest cancels the scheduled merge of a pull request
+func CancelAutoMergePullRequest(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pr := issue.PullRequest
+
+	scheduled, err := models.GetScheduledMergeByPullID(pr.ID)
+	if err != nil {
+		ctx.ServerError("GetScheduledMergeByPullID", err)
+		return
+	} else if scheduled == nil {
+		ctx.NotFound("GetScheduledMergeByPullID", nil)
+		return
+	}
+
+	// the merge may be canceled by the user who scheduled it or by anybody allowed to merge
+	if scheduled.DoerID != ctx.User.ID {
+		allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+		if err != nil {
+			ctx.ServerError("IsUserAllowedToMerge", err)
+			return
+		}
+		if !allowedMerge {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.update_not_allowed"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+			return
+		}
+	}
+
+	if err := models.RemoveScheduledAutoMerge(ctx.User, pr); err != nil {
+		ctx.ServerError("RemoveScheduledAutoMerge", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.pulls.auto_merge_canceled_schedule"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+}
+
 func stopTimerIfAvailable(user *models.User, issue *models.Issue) error {
 
 	if models.StopwatchExists(user.ID, issue.ID) {
@@ -1078,6 +1183,9 @@ func CompareAndPullRequestPost(ctx *context.Context) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if antispam.IsErrContentBlocked(err) {
+			ctx.RenderWithErr(contentBlockedMessage(ctx, err), tplCompareDiff, form)
+			return
 		} else if git.IsErrPushRejected(err) {
 			pushrejErr := err.(*git.ErrPushRejected)
 			message := pushrejErr.Message
@@ -1153,8 +1261,8 @@ func CleanUpPullRequest(ctx *context.Context) {
 
 	pr := issue.PullRequest
 
-	// Don't cleanup unmerged and unclosed PRs
-	if !pr.HasMerged && !issue.IsClosed {
+	// Don't cleanup unmerged and unclosed PRs, nor AGit PRs which have no head branch
+	if (!pr.HasMerged && !issue.IsClosed) || pr.Flow == models.PullRequestFlowAGit {
 		ctx.NotFound("CleanUpPullRequest", nil)
 		return
 	}
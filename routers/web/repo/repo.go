@@ -146,9 +146,28 @@ func Create(ctx *context.Context) {
 		if err == nil && templateRepo.CheckUnitUser(ctxUser, models.UnitTypeCode) {
 			ctx.Data["repo_template"] = templateID
 			ctx.Data["repo_template_name"] = templateRepo.Name
+
+			// Templates of the catalog are generated with their content and settings by default
+			if _, err := models.GetInstanceTemplateByRepoID(templateID); err == nil {
+				ctx.Data["git_content"] = true
+				ctx.Data["topics"] = true
+				ctx.Data["avatar"] = true
+				ctx.Data["labels"] = true
+				ctx.Data["units"] = true
+			} else if !models.IsErrInstanceTemplateNotExist(err) {
+				ctx.ServerError("GetInstanceTemplateByRepoID", err)
+				return
+			}
 		}
 	}
 
+	numInstanceTemplates, err := models.CountInstanceTemplates()
+	if err != nil {
+		ctx.ServerError("CountInstanceTemplates", err)
+		return
+	}
+	ctx.Data["HasInstanceTemplates"] = numInstanceTemplates > 0
+
 	ctx.Data["CanCreateRepo"] = ctx.User.CanCreateRepo()
 	ctx.Data["MaxCreationLimit"] = ctx.User.MaxCreationLimit()
 
@@ -222,6 +241,7 @@ func CreatePost(ctx *context.Context) {
 			Webhooks:    form.Webhooks,
 			Avatar:      form.Avatar,
 			IssueLabels: form.Labels,
+			Units:       form.Units,
 		}
 
 		if !opts.IsValid() {
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplReviewStats base.TplName = "repo/review_stats"
+
+// ReviewStats render the review statistics of each reviewer of the repository
+func ReviewStats(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.review_stats")
+	ctx.Data["PageIsActivity"] = true
+
+	stats, err := models.GetReviewerStats([]int64{ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.ServerError("GetReviewerStats", err)
+		return
+	}
+	ctx.Data["ReviewerStats"] = stats
+
+	ctx.HTML(http.StatusOK, tplReviewStats)
+}
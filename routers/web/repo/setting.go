@@ -20,7 +20,9 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -34,6 +36,8 @@ import (
 	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	repo_service "code.gitea.io/gitea/services/repository"
+
+	"github.com/dustin/go-humanize"
 )
 
 const (
@@ -59,6 +63,10 @@ func Settings(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 
+	if unit, err := ctx.Repo.Repository.GetUnit(models.UnitTypeCode); err == nil {
+		ctx.Data["CodeConfig"] = unit.CodeConfig()
+	}
+
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
@@ -76,6 +84,11 @@ func SettingsPost(ctx *context.Context) {
 			ctx.HTML(http.StatusOK, tplSettingsOptions)
 			return
 		}
+		if len(form.AccentColor) > 0 && !models.LabelColorPattern.MatchString(form.AccentColor) {
+			ctx.Data["Err_AccentColor"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.accent_color_invalid"), tplSettingsOptions, &form)
+			return
+		}
 
 		newRepoName := form.RepoName
 		// Check if repository name has been changed.
@@ -119,6 +132,7 @@ func SettingsPost(ctx *context.Context) {
 		repo.LowerName = strings.ToLower(newRepoName)
 		repo.Description = form.Description
 		repo.Website = form.Website
+		repo.AccentColor = form.AccentColor
 		repo.IsTemplate = form.Template
 
 		// Visibility of forked repository is forced sync with base repository.
@@ -138,6 +152,9 @@ func SettingsPost(ctx *context.Context) {
 			ctx.ServerError("UpdateRepository", err)
 			return
 		}
+		if visibilityChanged {
+			notification.NotifyChangeRepositoryVisibility(ctx.User, repo)
+		}
 		log.Trace("Repository basic settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
 
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
@@ -158,6 +175,27 @@ func SettingsPost(ctx *context.Context) {
 			ctx.Data["Err_Interval"] = true
 			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
 		} else {
+			syncWindows, err := util.ParseTimeWindows(form.MirrorSyncWindows)
+			if err != nil {
+				ctx.Data["Err_MirrorSyncWindows"] = true
+				ctx.RenderWithErr(ctx.Tr("repo.mirror_sync_windows_invalid"), tplSettingsOptions, &form)
+				return
+			}
+			var maxBandwidth uint64
+			if bandwidth := strings.TrimSpace(form.MirrorMaxBandwidth); bandwidth != "" {
+				if maxBandwidth, err = humanize.ParseBytes(bandwidth); err != nil {
+					ctx.Data["Err_MirrorMaxBandwidth"] = true
+					ctx.RenderWithErr(ctx.Tr("repo.mirror_max_bandwidth_invalid"), tplSettingsOptions, &form)
+					return
+				}
+			}
+
+			windows := make([]string, 0, len(syncWindows))
+			for _, w := range syncWindows {
+				windows = append(windows, w.String())
+			}
+			ctx.Repo.Mirror.SyncWindows = strings.Join(windows, ", ")
+			ctx.Repo.Mirror.MaxBandwidth = int64(maxBandwidth)
 			ctx.Repo.Mirror.EnablePrune = form.EnablePrune
 			ctx.Repo.Mirror.Interval = interval
 			if interval != 0 {
@@ -372,13 +410,19 @@ func SettingsPost(ctx *context.Context) {
 				ctx.Redirect(repo.Link() + "/settings")
 				return
 			}
+			if form.TrackerIssueStyle == markup.IssueNameStyleRegexp && !validation.IsValidExternalTrackerRegexpPattern(form.ExternalTrackerRegexpPattern) {
+				ctx.Flash.Error(ctx.Tr("repo.settings.tracker_issue_style.regexp_pattern_error"))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalTracker,
 				Config: &models.ExternalTrackerConfig{
-					ExternalTrackerURL:    form.ExternalTrackerURL,
-					ExternalTrackerFormat: form.TrackerURLFormat,
-					ExternalTrackerStyle:  form.TrackerIssueStyle,
+					ExternalTrackerURL:           form.ExternalTrackerURL,
+					ExternalTrackerFormat:        form.TrackerURLFormat,
+					ExternalTrackerStyle:         form.TrackerIssueStyle,
+					ExternalTrackerRegexpPattern: form.ExternalTrackerRegexpPattern,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
@@ -390,6 +434,7 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					EnableVoting:                     form.EnableIssueVoting,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
@@ -425,6 +470,7 @@ func SettingsPost(ctx *context.Context) {
 					AutodetectManualMerge:         form.EnableAutodetectManualMerge,
 					DefaultDeleteBranchAfterMerge: form.DefaultDeleteBranchAfterMerge,
 					DefaultMergeStyle:             models.MergeStyle(form.PullsDefaultMergeStyle),
+					WelcomeFirstTimeContributors:  form.WelcomeFirstTimeContributors,
 				},
 			})
 		} else if !models.UnitTypePullRequests.UnitGlobalDisabled() {
@@ -446,6 +492,25 @@ func SettingsPost(ctx *context.Context) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
 
+	case "home_page":
+		unit, err := repo.GetUnit(models.UnitTypeCode)
+		if err != nil {
+			ctx.NotFound("GetUnit", err)
+			return
+		}
+
+		config := unit.CodeConfig()
+		config.ReadmePath = models.CleanHomeReadmePath(form.HomeReadmePath)
+		config.ShowWikiHome = form.HomeShowWiki
+		if err := models.UpdateRepoUnit(unit); err != nil {
+			ctx.ServerError("UpdateRepoUnit", err)
+			return
+		}
+		log.Trace("Repository home page settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	case "signing":
 		changed := false
 
@@ -538,9 +603,12 @@ func SettingsPost(ctx *context.Context) {
 			return
 		}
 
-		repo.IsFork = false
-		repo.ForkID = 0
-		if err := models.UpdateRepository(repo, false); err != nil {
+		if err := models.ConvertForkToNormalRepository(repo); err != nil {
+			if models.IsErrForkHasOpenPullRequests(err) {
+				ctx.Flash.Error(ctx.Tr("repo.settings.convert_fork_open_pulls", err.(models.ErrForkHasOpenPullRequests).Count))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
 			log.Error("Unable to update repository %-v whilst converting from fork", repo)
 			ctx.ServerError("Convert Fork", err)
 			return
@@ -815,6 +883,7 @@ func CollaborationPost(ctx *context.Context) {
 		ctx.ServerError("AddCollaborator", err)
 		return
 	}
+	notification.NotifyAddCollaborator(ctx.User, ctx.Repo.Repository, u, models.AccessModeWrite)
 
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.User, ctx.Repo.Repository)
@@ -826,19 +895,53 @@ func CollaborationPost(ctx *context.Context) {
 
 // ChangeCollaborationAccessMode response for changing access of a collaboration
 func ChangeCollaborationAccessMode(ctx *context.Context) {
-	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(
-		ctx.QueryInt64("uid"),
-		models.AccessMode(ctx.QueryInt("mode"))); err != nil {
+	uid := ctx.QueryInt64("uid")
+	mode := models.AccessMode(ctx.QueryInt("mode"))
+	collaboration, err := ctx.Repo.Repository.GetCollaboration(uid)
+	if err != nil {
+		log.Error("GetCollaboration: %v", err)
+		return
+	} else if collaboration == nil {
+		return
+	}
+
+	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(uid, mode); err != nil {
 		log.Error("ChangeCollaborationAccessMode: %v", err)
+		return
+	}
+
+	// Invalid modes are discarded by ChangeCollaborationAccessMode
+	if collaboration.Mode == mode || mode <= models.AccessModeNone || mode > models.AccessModeOwner {
+		return
+	}
+	u, err := models.GetUserByID(uid)
+	if err != nil {
+		log.Error("GetUserByID: %v", err)
+		return
 	}
+	notification.NotifyChangeCollaboratorAccessMode(ctx.User, ctx.Repo.Repository, u, collaboration.Mode, mode)
 }
 
 // DeleteCollaboration delete a collaboration for a repository
 func DeleteCollaboration(ctx *context.Context) {
-	if err := ctx.Repo.Repository.DeleteCollaboration(ctx.QueryInt64("id")); err != nil {
+	uid := ctx.QueryInt64("id")
+	isCollaborator, err := ctx.Repo.Repository.IsCollaborator(uid)
+	if err != nil {
+		ctx.ServerError("IsCollaborator", err)
+		return
+	}
+
+	if err := ctx.Repo.Repository.DeleteCollaboration(uid); err != nil {
 		ctx.Flash.Error("DeleteCollaboration: " + err.Error())
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.settings.remove_collaborator_success"))
+		if isCollaborator {
+			if u, err := models.GetUserByID(uid); err != nil {
+				log.Error("GetUserByID: %v", err)
+			} else {
+				notification.NotifyRemoveCollaborator(ctx.User, ctx.Repo.Repository, u)
+			}
+		}
 	}
 
 	ctx.JSON(http.StatusOK, map[string]interface{}{
@@ -1135,6 +1238,16 @@ func SettingsAvatar(ctx *context.Context) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings")
 }
 
+// SettingsGenerateAvatar replaces the repository avatar by one generated from its name
+func SettingsGenerateAvatar(ctx *context.Context) {
+	if err := ctx.Repo.Repository.GenerateAvatar(); err != nil {
+		ctx.Flash.Error(fmt.Sprintf("GenerateAvatar: %v", err))
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_avatar_success"))
+	}
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+}
+
 // SettingsDeleteAvatar delete repository avatar
 func SettingsDeleteAvatar(ctx *context.Context) {
 	if err := ctx.Repo.Repository.DeleteAvatar(); err != nil {
@@ -5,6 +5,7 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -15,10 +16,12 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 // ProtectedBranch render the page to protect the repository
@@ -89,6 +92,29 @@ func ProtectedBranchPost(ctx *context.Context) {
 
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(setting.AppSubURL + ctx.Req.URL.Path)
+	case "rename_branch":
+		from := ctx.Query("from")
+		to := strings.TrimSpace(ctx.Query("to"))
+		if err := repo_service.RenameBranch(ctx.User, repo, ctx.Repo.GitRepo, from, to); err != nil {
+			switch {
+			case models.IsErrBranchDoesNotExist(err):
+				ctx.Flash.Error(ctx.Tr("repo.settings.rename_branch_from_not_exist", from))
+			case models.IsErrBranchAlreadyExists(err), models.IsErrBranchNameConflict(err), models.IsErrTagAlreadyExists(err):
+				ctx.Flash.Error(ctx.Tr("repo.settings.rename_branch_to_exist", to))
+			case errors.Is(err, repo_service.ErrBranchNameInvalid):
+				ctx.Flash.Error(ctx.Tr("repo.settings.rename_branch_invalid", to))
+			default:
+				ctx.ServerError("RenameBranch", err)
+				return
+			}
+			ctx.Redirect(setting.AppSubURL + ctx.Req.URL.Path)
+			return
+		}
+
+		log.Trace("Branch %s of repository %s/%s renamed to %s", from, ctx.Repo.Owner.Name, repo.Name, to)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.rename_branch_success", from, to))
+		ctx.Redirect(setting.AppSubURL + ctx.Req.URL.Path)
 	default:
 		ctx.NotFound("", nil)
 	}
@@ -187,7 +213,8 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 	}
 
 	if f.Protected {
-		if protectBranch == nil {
+		isNew := protectBranch == nil
+		if isNew {
 			// No options found, create defaults.
 			protectBranch = &models.ProtectedBranch{
 				RepoID:     ctx.Repo.Repository.ID,
@@ -254,6 +281,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 		protectBranch.RequireSignedCommits = f.RequireSignedCommits
 		protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 		protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+		protectBranch.RequireCodeOwnerApproval = f.RequireCodeOwnerApproval
 
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
@@ -271,6 +299,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 			ctx.ServerError("CheckPrsForBaseBranch", err)
 			return
 		}
+		notification.NotifyUpdateBranchProtection(ctx.User, ctx.Repo.Repository, protectBranch, isNew)
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_protect_branch_success", branch))
 		ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
 	} else {
@@ -279,6 +308,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 				ctx.ServerError("DeleteProtectedBranch", err)
 				return
 			}
+			notification.NotifyDeleteBranchProtection(ctx.User, ctx.Repo.Repository, protectBranch)
 		}
 		ctx.Flash.Success(ctx.Tr("repo.settings.remove_protected_branch_success", branch))
 		ctx.Redirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
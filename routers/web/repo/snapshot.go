@@ -0,0 +1,137 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	snapshot_service "code.gitea.io/gitea/services/snapshot"
+)
+
+const (
+	tplSettingsSnapshots base.TplName = "repo/settings/snapshots"
+)
+
+func setSnapshotsContext(ctx *context.Context) *models.RepoSnapshot {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.snapshots")
+	ctx.Data["PageIsSettingsSnapshots"] = true
+
+	s, err := models.GetRepoSnapshot(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrRepoSnapshotNotExist(err) {
+			ctx.ServerError("GetRepoSnapshot", err)
+			return nil
+		}
+		ctx.Data["branch"] = ctx.Repo.Repository.DefaultBranch
+		ctx.Data["tag_prefix"] = "nightly-"
+		return nil
+	}
+
+	rels, err := s.GetReleases()
+	if err != nil {
+		ctx.ServerError("GetReleases", err)
+		return nil
+	}
+	tags := make([]*models.Release, 0, len(rels))
+	for _, rel := range rels {
+		if snapshot_service.IsSnapshotTag(s, rel.TagName) {
+			tags = append(tags, rel)
+		}
+	}
+
+	ctx.Data["Snapshot"] = s
+	ctx.Data["SnapshotTags"] = tags
+	ctx.Data["branch"] = s.Branch
+	ctx.Data["tag_prefix"] = s.TagPrefix
+	ctx.Data["retention"] = s.Retention
+	ctx.Data["generate_archives"] = s.GenerateArchives
+	return s
+}
+
+// SettingsSnapshots renders the settings of the scheduled snapshots of the repository
+func SettingsSnapshots(ctx *context.Context) {
+	setSnapshotsContext(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsSnapshots)
+}
+
+// SettingsSnapshotsPost enables the scheduled snapshots of the repository or updates their settings
+func SettingsSnapshotsPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.RepoSnapshotForm)
+	s := setSnapshotsContext(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsSnapshots)
+		return
+	}
+
+	branch := strings.TrimSpace(form.Branch)
+	if !ctx.Repo.GitRepo.IsBranchExist(branch) {
+		ctx.Data["Err_Branch"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.snapshots.branch_not_exist", branch), tplSettingsSnapshots, form)
+		return
+	}
+
+	if s == nil {
+		s = &models.RepoSnapshot{RepoID: ctx.Repo.Repository.ID}
+	}
+	s.Branch = branch
+	s.TagPrefix = strings.TrimSpace(form.TagPrefix)
+	s.Retention = form.Retention
+	s.GenerateArchives = form.GenerateArchives
+	if err := snapshot_service.SaveSettings(s); err != nil {
+		ctx.ServerError("SaveSettings", err)
+		return
+	}
+
+	log.Trace("Snapshots of repository updated: %s", ctx.Repo.Repository.FullName())
+	ctx.Flash.Success(ctx.Tr("repo.settings.snapshots.update_success", s.ProtectedTagPattern()))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/snapshots")
+}
+
+// SettingsSnapshotsCreate takes the snapshot of the day now instead of waiting for the schedule
+func SettingsSnapshotsCreate(ctx *context.Context) {
+	s, err := models.GetRepoSnapshot(ctx.Repo.Repository.ID)
+	if err != nil {
+		if models.IsErrRepoSnapshotNotExist(err) {
+			ctx.NotFound("GetRepoSnapshot", nil)
+		} else {
+			ctx.ServerError("GetRepoSnapshot", err)
+		}
+		return
+	}
+	if err := snapshot_service.CreateSnapshot(s, time.Now()); err != nil {
+		ctx.ServerError("CreateSnapshot", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.snapshots.create_success", s.LastTagName))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/snapshots")
+}
+
+// SettingsSnapshotsDelete disables the scheduled snapshots of the repository
+func SettingsSnapshotsDelete(ctx *context.Context) {
+	if err := models.DeleteRepoSnapshot(ctx.Repo.Repository.ID); err != nil {
+		ctx.ServerError("DeleteRepoSnapshot", err)
+		return
+	}
+
+	log.Trace("Snapshots of repository disabled: %s", ctx.Repo.Repository.FullName())
+	ctx.Flash.Success(ctx.Tr("repo.settings.snapshots.deletion_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/snapshots")
+}
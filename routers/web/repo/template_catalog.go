@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplTemplateCatalog base.TplName = "repo/template_catalog"
+
+// TemplateCategory is a category of the template catalog
+type TemplateCategory struct {
+	Name      string
+	Templates []*models.InstanceTemplate
+}
+
+// TemplateCatalog lists the template repositories of the instance by category
+func TemplateCatalog(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.template.catalog")
+
+	ctxUser := checkContextUser(ctx, ctx.QueryInt64("org"))
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["ContextUser"] = ctxUser
+
+	templates, err := models.GetInstanceTemplates()
+	if err != nil {
+		ctx.ServerError("GetInstanceTemplates", err)
+		return
+	}
+
+	categories := make([]*TemplateCategory, 0, len(templates))
+	for _, t := range templates {
+		if !t.Repo.CheckUnitUser(ctxUser, models.UnitTypeCode) {
+			continue
+		}
+		if len(categories) == 0 || categories[len(categories)-1].Name != t.Category {
+			categories = append(categories, &TemplateCategory{Name: t.Category})
+		}
+		category := categories[len(categories)-1]
+		category.Templates = append(category.Templates, t)
+	}
+	ctx.Data["Categories"] = categories
+
+	ctx.HTML(http.StatusOK, tplTemplateCatalog)
+}
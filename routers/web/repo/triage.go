@@ -0,0 +1,261 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	triage_service "code.gitea.io/gitea/services/triage"
+)
+
+const (
+	tplSettingsTriage base.TplName = "repo/settings/triage"
+)
+
+func setTriageContext(ctx *context.Context) error {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.triage")
+	ctx.Data["PageIsSettingsTriage"] = true
+
+	rules, err := models.GetIssueTriageRules(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueTriageRules", err)
+		return err
+	}
+	ctx.Data["TriageRules"] = rules
+
+	labels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", models.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetLabelsByRepoID", err)
+		return err
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		orgLabels, err := models.GetLabelsByOrgID(ctx.Repo.Owner.ID, "", models.ListOptions{})
+		if err != nil {
+			ctx.ServerError("GetLabelsByOrgID", err)
+			return err
+		}
+		labels = append(labels, orgLabels...)
+
+		if err := ctx.Repo.Owner.GetTeams(&models.SearchTeamOptions{}); err != nil {
+			ctx.ServerError("GetTeams", err)
+			return err
+		}
+		ctx.Data["Teams"] = ctx.Repo.Owner.Teams
+	}
+	ctx.Data["Labels"] = labels
+	return nil
+}
+
+func selectTriageRuleByContext(ctx *context.Context) *models.IssueTriageRule {
+	id := ctx.QueryInt64("id")
+	if id == 0 {
+		id = ctx.ParamsInt64(":id")
+	}
+
+	rule, err := models.GetIssueTriageRuleByID(ctx.Repo.Repository.ID, id)
+	if err != nil {
+		ctx.ServerError("GetIssueTriageRuleByID", err)
+		return nil
+	}
+	if rule == nil {
+		ctx.NotFound("", fmt.Errorf("IssueTriageRule[%v] not associated to repository %v", id, ctx.Repo.Repository))
+	}
+	return rule
+}
+
+// fillTriageRule validates the form and fills the rule with it, it renders the error and returns false if it is invalid
+func fillTriageRule(ctx *context.Context, form *forms.IssueTriageRuleForm, rule *models.IssueTriageRule) bool {
+	rule.Condition = models.IssueTriageConditionFromName(form.Condition)
+	rule.Pattern = ""
+	rule.LabelID = 0
+	rule.TeamID = 0
+	rule.Comment = strings.TrimSpace(form.Comment)
+
+	switch rule.Condition {
+	case models.IssueTriageTitleMatches:
+		rule.Pattern = strings.TrimSpace(form.Pattern)
+		if _, err := regexp.Compile(rule.Pattern); err != nil || rule.Pattern == "" {
+			ctx.Data["Err_Pattern"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.triage.invalid_pattern"), tplSettingsTriage, form)
+			return false
+		}
+	case models.IssueTriageMissingSections:
+		rule.Pattern = form.Sections
+		if len(rule.Sections()) == 0 {
+			ctx.Data["Err_Sections"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.triage.sections_required"), tplSettingsTriage, form)
+			return false
+		}
+		rule.Pattern = strings.Join(rule.Sections(), "\n")
+	case models.IssueTriageAuthorIsMember:
+		if !ctx.Repo.Owner.IsOrganization() {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.triage.org_only"), tplSettingsTriage, form)
+			return false
+		}
+	}
+
+	if form.LabelID > 0 {
+		label, err := models.GetLabelByID(form.LabelID)
+		if err != nil && !models.IsErrLabelNotExist(err) {
+			ctx.ServerError("GetLabelByID", err)
+			return false
+		}
+		if label == nil || (label.RepoID != ctx.Repo.Repository.ID && label.OrgID != ctx.Repo.Owner.ID) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.triage.invalid_label"), tplSettingsTriage, form)
+			return false
+		}
+		rule.LabelID = label.ID
+	}
+
+	if form.TeamID > 0 {
+		team, err := models.GetTeamByID(form.TeamID)
+		if err != nil && !models.IsErrTeamNotExist(err) {
+			ctx.ServerError("GetTeamByID", err)
+			return false
+		}
+		if team == nil || team.OrgID != ctx.Repo.Owner.ID {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.triage.invalid_team"), tplSettingsTriage, form)
+			return false
+		}
+		rule.TeamID = team.ID
+	}
+
+	if rule.LabelID == 0 && rule.TeamID == 0 && rule.Comment == "" {
+		ctx.RenderWithErr(ctx.Tr("repo.settings.triage.action_required"), tplSettingsTriage, form)
+		return false
+	}
+	return true
+}
+
+// SettingsTriage renders the triage rules of the issues of the repository
+func SettingsTriage(ctx *context.Context) {
+	if setTriageContext(ctx) != nil {
+		return
+	}
+	ctx.Data["condition"] = models.IssueTriageTitleMatches.Name()
+	ctx.Data["label_id"] = int64(0)
+	ctx.Data["team_id"] = int64(0)
+
+	ctx.HTML(http.StatusOK, tplSettingsTriage)
+}
+
+// SettingsTriagePost adds a triage rule to the repository
+func SettingsTriagePost(ctx *context.Context) {
+	if setTriageContext(ctx) != nil {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsTriage)
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.IssueTriageRuleForm)
+	rule := &models.IssueTriageRule{RepoID: ctx.Repo.Repository.ID}
+	if !fillTriageRule(ctx, form, rule) {
+		return
+	}
+
+	if err := models.InsertIssueTriageRule(rule); err != nil {
+		ctx.ServerError("InsertIssueTriageRule", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/triage")
+}
+
+// SettingsTriageEdit renders the page to edit a triage rule
+func SettingsTriageEdit(ctx *context.Context) {
+	if setTriageContext(ctx) != nil {
+		return
+	}
+	ctx.Data["PageIsEditTriageRule"] = true
+
+	rule := selectTriageRuleByContext(ctx)
+	if rule == nil {
+		return
+	}
+
+	ctx.Data["condition"] = rule.Condition.Name()
+	switch rule.Condition {
+	case models.IssueTriageTitleMatches:
+		ctx.Data["pattern"] = rule.Pattern
+	case models.IssueTriageMissingSections:
+		ctx.Data["sections"] = rule.Pattern
+	}
+	ctx.Data["label_id"] = rule.LabelID
+	ctx.Data["team_id"] = rule.TeamID
+	ctx.Data["comment"] = rule.Comment
+
+	ctx.HTML(http.StatusOK, tplSettingsTriage)
+}
+
+// SettingsTriageEditPost updates a triage rule
+func SettingsTriageEditPost(ctx *context.Context) {
+	if setTriageContext(ctx) != nil {
+		return
+	}
+	ctx.Data["PageIsEditTriageRule"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsTriage)
+		return
+	}
+
+	rule := selectTriageRuleByContext(ctx)
+	if rule == nil {
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.IssueTriageRuleForm)
+	if !fillTriageRule(ctx, form, rule) {
+		return
+	}
+
+	if err := models.UpdateIssueTriageRule(rule); err != nil {
+		ctx.ServerError("UpdateIssueTriageRule", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/triage")
+}
+
+// SettingsTriageDelete deletes a triage rule
+func SettingsTriageDelete(ctx *context.Context) {
+	rule := selectTriageRuleByContext(ctx)
+	if rule == nil {
+		return
+	}
+
+	if err := models.DeleteIssueTriageRule(rule); err != nil {
+		ctx.ServerError("DeleteIssueTriageRule", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.triage.deleted"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/triage")
+}
+
+// SettingsTriageExport downloads the triage rules of the repository as YAML
+func SettingsTriageExport(ctx *context.Context) {
+	content, err := triage_service.ExportRules(ctx.Repo.Repository)
+	if err != nil {
+		ctx.ServerError("ExportRules", err)
+		return
+	}
+
+	ctx.ServeStream(bytes.NewReader(content), ctx.Repo.Repository.Name+"-triage.yml")
+}
@@ -31,6 +31,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/typesniffer"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 const (
@@ -127,6 +128,53 @@ func getReadmeFileFromPath(commit *git.Commit, treePath string) (*namedBlob, err
 	return readmeFile, nil
 }
 
+// getHomeReadmeFile returns the file configured to be rendered on the repository home page,
+// together with the directory its relative links are resolved against.
+// The path may point to a file or to a directory containing a README.
+func getHomeReadmeFile(commit *git.Commit, readmePath string) (*namedBlob, string, error) {
+	entry, err := commit.GetTreeEntryByPath(readmePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+
+	if entry.IsDir() {
+		readmeFile, err := getReadmeFileFromPath(commit, readmePath)
+		if err != nil || readmeFile == nil {
+			return nil, "", err
+		}
+		readmeFile.name = readmePath + "/" + readmeFile.name
+		return readmeFile, readmePath, nil
+	}
+
+	isSymlink := entry.IsLink()
+	target := entry
+	if isSymlink {
+		target, err = entry.FollowLinks()
+		if err != nil {
+			if git.IsErrBadLink(err) {
+				return nil, "", nil
+			}
+			return nil, "", err
+		}
+	}
+	if !target.IsExecutable() && !target.IsRegular() {
+		return nil, "", nil
+	}
+
+	dir := path.Dir(readmePath)
+	if dir == "." {
+		dir = ""
+	}
+	return &namedBlob{
+		readmePath,
+		isSymlink,
+		target.Blob(),
+	}, dir, nil
+}
+
 func renderDirectory(ctx *context.Context, treeLink string) {
 	tree, err := ctx.Repo.Commit.SubTree(ctx.Repo.TreePath)
 	if err != nil {
@@ -143,7 +191,7 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 
 	var c *git.LastCommitCache
 	if setting.CacheService.LastCommit.Enabled && ctx.Repo.CommitsCount >= setting.CacheService.LastCommit.CommitsCount {
-		c = git.NewLastCommitCache(ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, setting.LastCommitCacheTTLSeconds, cache.GetCache())
+		c = git.NewLastCommitCache(ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, setting.LastCommitCacheTTLSeconds, cache.GetLastCommitCache())
 	}
 
 	var latestCommit *git.Commit
@@ -232,6 +280,28 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 		}
 	}
 
+	var codeConfig *models.CodeConfig
+	if ctx.Repo.TreePath == "" {
+		if unit, err := ctx.Repo.Repository.GetUnit(models.UnitTypeCode); err == nil {
+			codeConfig = unit.CodeConfig()
+		}
+	}
+
+	if codeConfig != nil && codeConfig.ReadmePath != "" {
+		homeReadme, dir, err := getHomeReadmeFile(ctx.Repo.Commit, codeConfig.ReadmePath)
+		if err != nil {
+			ctx.ServerError("getHomeReadmeFile", err)
+			return
+		}
+		// Fall back to the detected README if the configured path is missing on this branch
+		if homeReadme != nil {
+			readmeFile = homeReadme
+			if dir != "" {
+				readmeTreelink = treeLink + "/" + dir
+			}
+		}
+	}
+
 	if ctx.Repo.TreePath == "" && readmeFile == nil {
 		for _, entry := range docsEntries {
 			if entry == nil {
@@ -250,6 +320,16 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 		}
 	}
 
+	if codeConfig != nil && codeConfig.ShowWikiHome {
+		rendered := renderWikiHome(ctx)
+		if ctx.Written() {
+			return
+		}
+		if rendered {
+			readmeFile = nil
+		}
+	}
+
 	if readmeFile != nil {
 		ctx.Data["RawFileLink"] = ""
 		ctx.Data["ReadmeInList"] = true
@@ -633,7 +713,7 @@ func Home(ctx *context.Context) {
 			return
 		}
 
-		if ctx.IsSigned {
+		if ctx.IsSigned && ctx.ViewAsAdmin == nil {
 			// Set repo notification-status read if unread
 			if err := ctx.Repo.Repository.ReadBy(ctx.User.ID); err != nil {
 				ctx.ServerError("ReadBy", err)
@@ -793,24 +873,25 @@ func Stars(ctx *context.Context) {
 	RenderUserCards(ctx, ctx.Repo.Repository.NumStars, ctx.Repo.Repository.GetStargazers, tplWatchers)
 }
 
-// Forks render repository's forked users
+// Forks render the fork network of the repository
 func Forks(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repos.forks")
 
-	// TODO: need pagination
-	forks, err := ctx.Repo.Repository.GetForks(models.ListOptions{})
-	if err != nil {
-		ctx.ServerError("GetForks", err)
-		return
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
 	}
 
-	for _, fork := range forks {
-		if err = fork.GetOwner(); err != nil {
-			ctx.ServerError("GetOwner", err)
-			return
-		}
+	network, total, err := repo_service.GetForkNetwork(ctx.User, ctx.Repo.Repository, models.ListOptions{
+		Page:     page,
+		PageSize: models.ItemsPerPage,
+	})
+	if err != nil {
+		ctx.ServerError("GetForkNetwork", err)
+		return
 	}
-	ctx.Data["Forks"] = forks
+	ctx.Data["ForkNetwork"] = network
+	ctx.Data["Page"] = context.NewPagination(total, models.ItemsPerPage, page, 5)
 
 	ctx.HTML(http.StatusOK, tplForks)
 }
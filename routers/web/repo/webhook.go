@@ -21,6 +21,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/webhook"
 	jsoniter "github.com/json-iterator/go"
@@ -30,6 +31,7 @@ const (
 	tplHooks        base.TplName = "repo/settings/webhook/base"
 	tplHookNew      base.TplName = "repo/settings/webhook/new"
 	tplOrgHookNew   base.TplName = "org/settings/hook_new"
+	tplUserHookNew  base.TplName = "user/settings/hook_new"
 	tplAdminHookNew base.TplName = "admin/hook_new"
 )
 
@@ -61,7 +63,7 @@ type orgRepoCtx struct {
 	NewTemplate     base.TplName
 }
 
-// getOrgRepoCtx determines whether this is a repo, organization, or admin (both default and system) context.
+// getOrgRepoCtx determines whether this is a repo, organization, user, or admin (both default and system) context.
 func getOrgRepoCtx(ctx *context.Context) (*orgRepoCtx, error) {
 	if len(ctx.Repo.RepoLink) > 0 {
 		return &orgRepoCtx{
@@ -81,6 +83,16 @@ func getOrgRepoCtx(ctx *context.Context) (*orgRepoCtx, error) {
 		}, nil
 	}
 
+	if ctx.Data["PageIsUserSettings"] == true {
+		// webhooks of a user are stored like those of an organization
+		return &orgRepoCtx{
+			OrgID:       ctx.User.ID,
+			Link:        path.Join(setting.AppSubURL, "/user/settings/hooks"),
+			LinkNew:     path.Join(setting.AppSubURL, "/user/settings/hooks"),
+			NewTemplate: tplUserHookNew,
+		}, nil
+	}
+
 	if ctx.User.IsAdmin {
 		// Are we looking at default webhooks?
 		if ctx.Params(":configType") == "default-hooks" {
@@ -176,7 +188,9 @@ func ParseHookEvent(form forms.WebhookForm) *models.HookEvent {
 			PullRequestComment:   form.PullRequestComment,
 			PullRequestReview:    form.PullRequestReview,
 			PullRequestSync:      form.PullRequestSync,
+			Deployment:           form.Deployment,
 			Repository:           form.Repository,
+			RepositorySettings:   form.RepositorySettings,
 		},
 		BranchFilter: form.BranchFilter,
 	}
@@ -207,19 +221,25 @@ func GiteaHooksNewPost(ctx *context.Context) {
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
 		contentType = models.ContentTypeForm
 	}
+	payloadVersion := toHookPayloadVersion(form.PayloadVersion)
 
 	w := &models.Webhook{
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		HTTPMethod:      form.HTTPMethod,
 		ContentType:     contentType,
+		PayloadVersion:  payloadVersion,
 		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.GITEA,
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -232,6 +252,25 @@ func GiteaHooksNewPost(ctx *context.Context) {
 	ctx.Redirect(orCtx.Link)
 }
 
+// checkPlaintextWebhook renders the form with an error if the webhook would deliver its payloads over plaintext HTTP
+// while new webhooks are required to use HTTPS
+func checkPlaintextWebhook(ctx *context.Context, orCtx *orgRepoCtx, w *models.Webhook, form interface{}) bool {
+	if setting.Webhook.AllowPlaintextHTTP || !audit.IsPlaintextURL(w.URL) {
+		return true
+	}
+	ctx.Data["Err_PayloadURL"] = true
+	ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.plaintext_http_blocked"), orCtx.NewTemplate, form)
+	return false
+}
+
+// toHookPayloadVersion returns the payload version selected in a form, the latest one if it is unknown
+func toHookPayloadVersion(version int) models.HookPayloadVersion {
+	if version < int(models.PayloadVersionLegacy) || version > int(models.PayloadVersionLatest) {
+		return models.PayloadVersionLatest
+	}
+	return models.HookPayloadVersion(version)
+}
+
 // GogsHooksNewPost response for creating webhook
 func GogsHooksNewPost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.NewGogshookForm)
@@ -262,18 +301,24 @@ func newGogsWebhookPost(ctx *context.Context, form forms.NewGogshookForm, kind m
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
 		contentType = models.ContentTypeForm
 	}
+	payloadVersion := toHookPayloadVersion(form.PayloadVersion)
 
 	w := &models.Webhook{
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     contentType,
+		PayloadVersion:  payloadVersion,
 		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            kind,
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -320,6 +365,8 @@ func DiscordHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.DISCORD,
@@ -327,6 +374,9 @@ func DiscordHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -363,6 +413,8 @@ func DingtalkHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.DINGTALK,
@@ -370,6 +422,9 @@ func DingtalkHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -416,6 +471,8 @@ func TelegramHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage?chat_id=%s", form.BotToken, form.ChatID),
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.TELEGRAM,
@@ -423,6 +480,9 @@ func TelegramHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -472,6 +532,8 @@ func MatrixHooksNewPost(ctx *context.Context) {
 		URL:             fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message", form.HomeserverURL, form.RoomID),
 		ContentType:     models.ContentTypeJSON,
 		HTTPMethod:      "PUT",
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.MATRIX,
@@ -479,6 +541,9 @@ func MatrixHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -515,6 +580,8 @@ func MSTeamsHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.MSTEAMS,
@@ -522,6 +589,9 @@ func MSTeamsHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -576,6 +646,8 @@ func SlackHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.SLACK,
@@ -583,6 +655,9 @@ func SlackHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -619,6 +694,8 @@ func FeishuHooksNewPost(ctx *context.Context) {
 		RepoID:          orCtx.RepoID,
 		URL:             form.PayloadURL,
 		ContentType:     models.ContentTypeJSON,
+		Secret:          form.Secret,
+		SignatureHeader: form.SignatureHeader,
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		Type:            models.FEISHU,
@@ -626,6 +703,9 @@ func FeishuHooksNewPost(ctx *context.Context) {
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
+	if !checkPlaintextWebhook(ctx, orCtx, w, form) {
+		return
+	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
@@ -652,7 +732,7 @@ func checkWebhook(ctx *context.Context) (*orgRepoCtx, *models.Webhook) {
 	if orCtx.RepoID > 0 {
 		w, err = models.GetWebhookByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
 	} else if orCtx.OrgID > 0 {
-		w, err = models.GetWebhookByOrgID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+		w, err = models.GetWebhookByOrgID(orCtx.OrgID, ctx.ParamsInt64(":id"))
 	} else if orCtx.IsAdmin {
 		w, err = models.GetSystemOrDefaultWebhook(ctx.ParamsInt64(":id"))
 	}
@@ -721,10 +801,13 @@ func WebHooksEditPost(ctx *context.Context) {
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
 		contentType = models.ContentTypeForm
 	}
+	payloadVersion := toHookPayloadVersion(form.PayloadVersion)
 
 	w.URL = form.PayloadURL
 	w.ContentType = contentType
+	w.PayloadVersion = payloadVersion
 	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	w.HTTPMethod = form.HTTPMethod
@@ -762,10 +845,13 @@ func GogsHooksEditPost(ctx *context.Context) {
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
 		contentType = models.ContentTypeForm
 	}
+	payloadVersion := toHookPayloadVersion(form.PayloadVersion)
 
 	w.URL = form.PayloadURL
 	w.ContentType = contentType
+	w.PayloadVersion = payloadVersion
 	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -818,6 +904,8 @@ func SlackHooksEditPost(ctx *context.Context) {
 
 	w.URL = form.PayloadURL
 	w.Meta = string(meta)
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -862,6 +950,8 @@ func DiscordHooksEditPost(ctx *context.Context) {
 
 	w.URL = form.PayloadURL
 	w.Meta = string(meta)
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -895,6 +985,8 @@ func DingtalkHooksEditPost(ctx *context.Context) {
 	}
 
 	w.URL = form.PayloadURL
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -937,6 +1029,8 @@ func TelegramHooksEditPost(ctx *context.Context) {
 	}
 	w.Meta = string(meta)
 	w.URL = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage?chat_id=%s", form.BotToken, form.ChatID)
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -982,6 +1076,8 @@ func MatrixHooksEditPost(ctx *context.Context) {
 	w.Meta = string(meta)
 	w.URL = fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message", form.HomeserverURL, form.RoomID)
 
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -1015,6 +1111,8 @@ func MSTeamsHooksEditPost(ctx *context.Context) {
 	}
 
 	w.URL = form.PayloadURL
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -1048,6 +1146,8 @@ func FeishuHooksEditPost(ctx *context.Context) {
 	}
 
 	w.URL = form.PayloadURL
+	w.Secret = form.Secret
+	w.SignatureHeader = form.SignatureHeader
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
@@ -1119,6 +1219,47 @@ func TestWebhook(ctx *context.Context) {
 	}
 }
 
+// RedeliverWebhook adds a past delivery of a webhook to the delivery queue again
+func RedeliverWebhook(ctx *context.Context) {
+	w, err := models.GetWebhookByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.NotFound("GetWebhookByRepoID", nil)
+		} else {
+			ctx.ServerError("GetWebhookByRepoID", err)
+		}
+		return
+	}
+
+	if _, err = webhook.ReplayHookTask(w, ctx.ParamsInt64(":delivery_id")); err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.NotFound("ReplayHookTask", nil)
+		} else {
+			ctx.ServerError("ReplayHookTask", err)
+		}
+		return
+	}
+	ctx.Flash.Info(ctx.Tr("repo.settings.webhook.redeliver_success"))
+	ctx.Redirect(fmt.Sprintf("%s/settings/hooks/%d", ctx.Repo.RepoLink, w.ID))
+}
+
+// EnableWebhook enables again a webhook disabled because its deliveries kept failing
+func EnableWebhook(ctx *context.Context) {
+	orCtx, w := checkWebhook(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	w.IsActive = true
+	if err := models.UpdateWebhook(w); err != nil {
+		ctx.ServerError("UpdateWebhook", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.webhook_enabled"))
+	ctx.Redirect(orCtx.Link)
+}
+
 // DeleteWebhook delete a webhook
 func DeleteWebhook(ctx *context.Context) {
 	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
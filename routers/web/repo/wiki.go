@@ -132,6 +132,51 @@ func wikiContentsByName(ctx *context.Context, commit *git.Commit, wikiName strin
 	return wikiContentsByEntry(ctx, entry), entry, pageFilename, false
 }
 
+// renderWikiHome renders the wiki home page in place of the README on the repository
+// home page. It returns false if there is no wiki home page to render.
+func renderWikiHome(ctx *context.Context) bool {
+	if !ctx.Repo.CanRead(models.UnitTypeWiki) || !ctx.Repo.Repository.HasWiki() {
+		return false
+	}
+
+	wikiRepo, commit, err := findWikiRepoCommit(ctx)
+	if wikiRepo != nil {
+		defer wikiRepo.Close()
+	}
+	if err != nil {
+		if !git.IsErrNotExist(err) && !ctx.Written() {
+			ctx.ServerError("GetBranchCommit", err)
+		}
+		return false
+	}
+
+	data, entry, _, _ := wikiContentsByName(ctx, commit, "Home")
+	if entry == nil || ctx.Written() {
+		return false
+	}
+
+	var buf strings.Builder
+	if err := markdown.Render(&markup.RenderContext{
+		Ctx:       ctx,
+		URLPrefix: ctx.Repo.RepoLink,
+		Metas:     ctx.Repo.Repository.ComposeDocumentMetas(),
+		IsWiki:    true,
+	}, bytes.NewReader(data), &buf); err != nil {
+		ctx.ServerError("Render", err)
+		return false
+	}
+
+	ctx.Data["RawFileLink"] = ""
+	ctx.Data["ReadmeInList"] = true
+	ctx.Data["ReadmeExist"] = true
+	ctx.Data["FileIsText"] = true
+	ctx.Data["FileName"] = entry.Name()
+	ctx.Data["IsMarkup"] = true
+	ctx.Data["MarkupType"] = markdown.MarkupName
+	ctx.Data["FileContent"] = buf.String()
+	return true
+}
+
 func renderViewPage(ctx *context.Context) (*git.Repository, *git.TreeEntry) {
 	wikiRepo, commit, err := findWikiRepoCommit(ctx)
 	if err != nil {
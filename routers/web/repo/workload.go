@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplWorkload base.TplName = "repo/workload"
+
+// Workload render the open issues and pull requests of each assignee of the repository
+func Workload(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.workload")
+	ctx.Data["PageIsActivity"] = true
+
+	var issueRepoIDs, pullRepoIDs []int64
+	if ctx.Repo.CanRead(models.UnitTypeIssues) {
+		issueRepoIDs = []int64{ctx.Repo.Repository.ID}
+	}
+	if ctx.Repo.CanRead(models.UnitTypePullRequests) {
+		pullRepoIDs = []int64{ctx.Repo.Repository.ID}
+	}
+
+	workloads, err := models.GetAssigneeWorkloads(issueRepoIDs, pullRepoIDs)
+	if err != nil {
+		ctx.ServerError("GetAssigneeWorkloads", err)
+		return
+	}
+	ctx.Data["Workloads"] = workloads
+
+	ctx.HTML(http.StatusOK, tplWorkload)
+}
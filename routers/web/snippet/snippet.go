@@ -0,0 +1,253 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/common"
+	"code.gitea.io/gitea/services/forms"
+	snippet_service "code.gitea.io/gitea/services/snippet"
+)
+
+const (
+	tplSnippetNew  base.TplName = "snippet/new"
+	tplSnippetView base.TplName = "snippet/view"
+)
+
+// renderedFile is a file of a snippet prepared for display
+type renderedFile struct {
+	Name       string
+	RawLink    string
+	IsMarkup   bool
+	MarkupType string
+	Rendered   string
+	Lines      map[int]string
+	IsTooLarge bool
+	IsBinary   bool
+}
+
+// getSnippet returns the snippet by ":uuid", hiding private snippets
+// from everyone but their owner and site admins.
+func getSnippet(ctx *context.Context) *models.Snippet {
+	s, err := models.GetSnippetByUUID(ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrSnippetNotExist(err) {
+			ctx.NotFound("GetSnippetByUUID", err)
+		} else {
+			ctx.ServerError("GetSnippetByUUID", err)
+		}
+		return nil
+	}
+	if !s.CanRead(ctx.User) {
+		ctx.NotFound("CanRead", nil)
+		return nil
+	}
+	if err = s.LoadOwner(); err != nil {
+		ctx.ServerError("LoadOwner", err)
+		return nil
+	}
+	return s
+}
+
+func linesBytesCount(s []byte) int {
+	nl := []byte{'\n'}
+	n := bytes.Count(s, nl)
+	if len(s) > 0 && !bytes.HasSuffix(s, nl) {
+		n++
+	}
+	return n
+}
+
+func renderFile(ctx *context.Context, s *models.Snippet, f *snippet_service.File) (*renderedFile, error) {
+	rf := &renderedFile{
+		Name:    f.Name,
+		RawLink: s.RawLink(f.Name),
+	}
+	switch {
+	case int64(len(f.Content)) >= setting.UI.MaxDisplayFileSize:
+		rf.IsTooLarge = true
+	case !typesniffer.DetectContentType(f.Content).IsText():
+		rf.IsBinary = true
+	case markup.Type(f.Name) != "":
+		rf.IsMarkup = true
+		rf.MarkupType = markup.Type(f.Name)
+		var result strings.Builder
+		if err := markup.Render(&markup.RenderContext{
+			Ctx:       ctx,
+			Filename:  f.Name,
+			URLPrefix: s.Link(),
+		}, bytes.NewReader(f.Content), &result); err != nil {
+			return nil, err
+		}
+		rf.Rendered = result.String()
+	default:
+		rf.Lines = highlight.File(linesBytesCount(f.Content), f.Name, f.Content)
+	}
+	return rf, nil
+}
+
+// New renders the page to create a snippet
+func New(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("snippet.new")
+	ctx.Data["visibility"] = models.SnippetVisibilityPublic.String()
+	ctx.HTML(http.StatusOK, tplSnippetNew)
+}
+
+// NewPost creates a snippet
+func NewPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateSnippetForm)
+	ctx.Data["Title"] = ctx.Tr("snippet.new")
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSnippetNew)
+		return
+	}
+
+	visibility, _ := models.SnippetVisibilityFromString(form.Visibility)
+	s, err := snippet_service.CreateSnippet(ctx.User, form.Description, visibility, []*snippet_service.File{
+		{Name: form.FileName, Content: []byte(form.Content)},
+	})
+	if err != nil {
+		if snippet_service.IsErrInvalidFile(err) {
+			ctx.Data["Err_FileName"] = true
+			ctx.RenderWithErr(ctx.Tr("snippet.invalid_file", err.(snippet_service.ErrInvalidFile).Reason), tplSnippetNew, form)
+			return
+		}
+		ctx.ServerError("CreateSnippet", err)
+		return
+	}
+	ctx.Redirect(s.Link())
+}
+
+// View renders a snippet with its files and comments
+func View(ctx *context.Context) {
+	s := getSnippet(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	files, err := snippet_service.GetFiles(s)
+	if err != nil {
+		ctx.ServerError("GetFiles", err)
+		return
+	}
+	renderedFiles := make([]*renderedFile, 0, len(files))
+	for _, f := range files {
+		rf, err := renderFile(ctx, s, f)
+		if err != nil {
+			ctx.ServerError("renderFile", err)
+			return
+		}
+		renderedFiles = append(renderedFiles, rf)
+	}
+
+	comments, err := models.GetSnippetComments(s.ID, models.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetSnippetComments", err)
+		return
+	}
+	for _, c := range comments {
+		if err = c.LoadPoster(); err != nil {
+			ctx.ServerError("LoadPoster", err)
+			return
+		}
+	}
+	renderedComments := make(map[int64]string, len(comments))
+	for _, c := range comments {
+		renderedComments[c.ID], err = markdown.RenderString(&markup.RenderContext{
+			URLPrefix: s.Link(),
+		}, c.Content)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+	}
+
+	ctx.Data["Title"] = s.Owner.Name + "/" + s.UUID
+	if len(renderedFiles) > 0 {
+		ctx.Data["Title"] = s.Owner.Name + "/" + renderedFiles[0].Name
+	}
+	ctx.Data["Snippet"] = s
+	ctx.Data["Files"] = renderedFiles
+	ctx.Data["Comments"] = comments
+	ctx.Data["RenderedComments"] = renderedComments
+	ctx.Data["CanWrite"] = s.CanWrite(ctx.User)
+	ctx.HTML(http.StatusOK, tplSnippetView)
+}
+
+// Raw serves the raw content of a file of a snippet
+func Raw(ctx *context.Context) {
+	s := getSnippet(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	f, err := snippet_service.GetFile(s, ctx.Params(":filename"))
+	if err != nil {
+		if snippet_service.IsErrFileNotExist(err) {
+			ctx.NotFound("GetFile", err)
+		} else {
+			ctx.ServerError("GetFile", err)
+		}
+		return
+	}
+	if err = common.ServeData(ctx, f.Name, int64(len(f.Content)), bytes.NewReader(f.Content)); err != nil {
+		ctx.ServerError("ServeData", err)
+	}
+}
+
+// CommentPost comments on a snippet
+func CommentPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateSnippetCommentForm)
+	s := getSnippet(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(s.Link())
+		return
+	}
+
+	c, err := models.CreateSnippetComment(s, ctx.User, form.Content)
+	if err != nil {
+		ctx.ServerError("CreateSnippetComment", err)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s#comment-%d", s.Link(), c.ID))
+}
+
+// Delete deletes a snippet
+func Delete(ctx *context.Context) {
+	s := getSnippet(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !s.CanWrite(ctx.User) {
+		ctx.NotFound("CanWrite", nil)
+		return
+	}
+
+	if err := snippet_service.DeleteSnippet(s); err != nil {
+		ctx.ServerError("DeleteSnippet", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("snippet.deletion_success"))
+	ctx.Redirect(setting.AppSubURL + "/explore/snippets")
+}
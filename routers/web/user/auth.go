@@ -50,6 +50,8 @@ const (
 	tplTwofaScratch   base.TplName = "user/auth/twofa_scratch"
 	tplLinkAccount    base.TplName = "user/auth/link_account"
 	tplU2F            base.TplName = "user/auth/u2f"
+	// tplPendingRegistration template path for accounts waiting for the approval of an administrator
+	tplPendingRegistration base.TplName = "user/auth/pending_registration"
 )
 
 // AutoSignIn reads cookie and try to auto-login.
@@ -617,6 +619,13 @@ func SignInOAuthCallback(ctx *context.Context) {
 	}
 
 	if u == nil {
+		if !loginSource.OAuth2().IsRegistrationAllowed(gothUser) {
+			log.Info("OAuth2 account %s of %s is not allowed to register", gothUser.UserID, loginSource.Name)
+			ctx.Flash.Error(ctx.Tr("auth.oauth_registration_not_allowed", loginSource.Name))
+			ctx.Redirect(setting.AppSubURL + "/user/login")
+			return
+		}
+
 		if !(setting.Service.DisableRegistration || setting.Service.AllowOnlyInternalRegistration) && setting.OAuth2Client.EnableAutoRegistration {
 			// create new user with details from oauth2 provider
 			var missingFields []string
@@ -657,6 +666,16 @@ func SignInOAuthCallback(ctx *context.Context) {
 			showLinkingLogin(ctx, gothUser)
 			return
 		}
+	} else if u.ProhibitLogin {
+		pending, err := models.IsRegistrationPending(u.ID)
+		if err != nil {
+			ctx.ServerError("IsRegistrationPending", err)
+			return
+		} else if pending {
+			ctx.Data["Title"] = ctx.Tr("auth.registration_pending")
+			ctx.HTML(http.StatusOK, tplPendingRegistration)
+			return
+		}
 	}
 
 	handleOAuth2SignIn(ctx, u, gothUser)
@@ -1057,6 +1076,12 @@ func LinkAccountPostRegister(ctx *context.Context) {
 	loginSource, err := models.GetActiveOAuth2LoginSourceByName(gothUser.Provider)
 	if err != nil {
 		ctx.ServerError("CreateUser", err)
+		return
+	}
+
+	if !loginSource.OAuth2().IsRegistrationAllowed(gothUser) {
+		ctx.RenderWithErr(ctx.Tr("auth.oauth_registration_not_allowed", loginSource.Name), tplLinkAccount, &form)
+		return
 	}
 
 	u := &models.User{
@@ -1313,6 +1338,24 @@ func handleUserCreated(ctx *context.Context, u *models.User, gothUser *goth.User
 		}
 	}
 
+	// Accounts of OAuth2 sources requiring an approval can't sign in until an administrator approves them
+	if gothUser != nil && u.LoginType == models.LoginOAuth2 && !u.IsAdmin {
+		loginSource, err := models.GetLoginSourceByID(u.LoginSource)
+		if err != nil {
+			ctx.ServerError("GetLoginSourceByID", err)
+			return
+		}
+		if loginSource.IsOAuth2() && loginSource.OAuth2().RequireRegistrationApproval {
+			if err := models.CreatePendingRegistration(u, loginSource.ID); err != nil {
+				ctx.ServerError("CreatePendingRegistration", err)
+				return
+			}
+			ctx.Data["Title"] = ctx.Tr("auth.registration_pending")
+			ctx.HTML(http.StatusOK, tplPendingRegistration)
+			return
+		}
+	}
+
 	// Send confirmation email
 	if !u.IsActive && u.ID > 1 {
 		mailer.SendActivateAccountMail(ctx.Locale, u)
@@ -158,6 +158,8 @@ func Dashboard(ctx *context.Context) {
 		OnlyPerformedBy: false,
 		IncludeDeleted:  false,
 		Date:            ctx.Query("date"),
+
+		IncludeFollowedOrgs: !ctxUser.IsOrganization(),
 	})
 
 	if ctx.Written() {
@@ -854,6 +856,9 @@ func ShowSSHKeys(ctx *context.Context, uid int64) {
 
 	var buf bytes.Buffer
 	for i := range keys {
+		if keys[i].IsExpired() {
+			continue
+		}
 		buf.WriteString(keys[i].OmitEmail())
 		buf.WriteString("\n")
 	}
@@ -116,3 +116,24 @@ func TestMilestonesForSpecificRepo(t *testing.T) {
 	assert.Len(t, ctx.Data["Milestones"], 1)
 	assert.Len(t, ctx.Data["Repos"], 2) // both repo 42 and 1 have milestones and both are owned by user 2
 }
+
+func TestTrackedTimes(t *testing.T) {
+	setting.UI.IssuePagingNum = 10
+	setting.Service.EnableTimetracking = true
+	assert.NoError(t, models.LoadFixtures())
+
+	ctx := test.MockContext(t, "user/times")
+	test.LoadUser(t, ctx, 2)
+	TrackedTimes(ctx)
+	assert.EqualValues(t, http.StatusOK, ctx.Resp.Status())
+	assert.EqualValues(t, 3666, ctx.Data["TotalTime"])
+	assert.EqualValues(t, 1, ctx.Data["Repo"].(*models.Repository).ID)
+	assert.Len(t, ctx.Data["TrackedTimes"], 3)
+
+	ctx = test.MockContext(t, "user/times")
+	test.LoadUser(t, ctx, 2)
+	ctx.Req.Form.Set("repo", "2")
+	TrackedTimes(ctx)
+	assert.EqualValues(t, http.StatusOK, ctx.Resp.Status())
+	assert.Len(t, ctx.Data["TrackedTimes"], 1)
+}
@@ -31,6 +31,7 @@ import (
 const (
 	tplGrantAccess base.TplName = "user/auth/grant"
 	tplGrantError  base.TplName = "user/auth/grant_error"
+	tplGrantDevice base.TplName = "user/auth/grant_device"
 )
 
 // TODO move error and responses to SDK or models
@@ -83,6 +84,14 @@ const (
 	AccessTokenErrorCodeUnsupportedGrantType = "unsupported_grant_type"
 	// AccessTokenErrorCodeInvalidScope represents an error code specified in RFC 6749
 	AccessTokenErrorCodeInvalidScope = "invalid_scope"
+	// AccessTokenErrorCodeAuthorizationPending represents an error code specified in RFC 8628
+	AccessTokenErrorCodeAuthorizationPending = "authorization_pending"
+	// AccessTokenErrorCodeSlowDown represents an error code specified in RFC 8628
+	AccessTokenErrorCodeSlowDown = "slow_down"
+	// AccessTokenErrorCodeAccessDenied represents an error code specified in RFC 8628
+	AccessTokenErrorCodeAccessDenied = "access_denied"
+	// AccessTokenErrorCodeExpiredToken represents an error code specified in RFC 8628
+	AccessTokenErrorCodeExpiredToken = "expired_token"
 )
 
 // AccessTokenError represents an error response specified in RFC 6749
@@ -559,10 +568,12 @@ func AccessTokenOAuth(ctx *context.Context) {
 		handleRefreshToken(ctx, form, signingKey)
 	case "authorization_code":
 		handleAuthorizationCode(ctx, form, signingKey)
+	case deviceCodeGrantType:
+		handleDeviceCode(ctx, form, signingKey)
 	default:
 		handleAccessTokenError(ctx, AccessTokenError{
 			ErrorCode:        AccessTokenErrorCodeUnsupportedGrantType,
-			ErrorDescription: "Only refresh_token or authorization_code grant type is supported",
+			ErrorDescription: "Only refresh_token, authorization_code or device_code grant type is supported",
 		})
 	}
 }
@@ -0,0 +1,265 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// deviceCodeGrantType is the grant type of the device authorization grant specified in RFC 8628
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorizationResponse represents a successful device authorization response specified in RFC 8628
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// DeviceAuthorizationOAuth manages the requests of devices for a device code and a user code
+func DeviceAuthorizationOAuth(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.DeviceAuthorizationForm)
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if models.IsErrOauthClientIDInvalid(err) {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidClient,
+				ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+			})
+			return
+		}
+		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
+		return
+	}
+	if !authenticateDeviceClient(ctx, app, form.ClientSecret) {
+		return
+	}
+
+	code, err := app.CreateDeviceCode(form.Scope)
+	if err != nil {
+		ctx.ServerError("CreateDeviceCode", err)
+		return
+	}
+	verificationURI := setting.AppURL + "login/oauth/device"
+	ctx.JSON(http.StatusOK, &DeviceAuthorizationResponse{
+		DeviceCode:              code.DeviceCode,
+		UserCode:                code.FormattedUserCode(),
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(code.FormattedUserCode()),
+		ExpiresIn:               setting.OAuth2.DeviceCodeExpirationTime,
+		Interval:                models.OAuth2DeviceCodeInterval,
+	})
+}
+
+// authenticateDeviceClient checks that the application has opted in to the device authorization grant
+// and that a confidential client has sent its secret, it responds with an error and returns false otherwise
+func authenticateDeviceClient(ctx *context.Context, app *models.OAuth2Application, clientSecret string) bool {
+	if !app.EnableDeviceFlow {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "the client is not allowed to use the device authorization grant",
+		})
+		return false
+	}
+	// public clients cannot keep a secret, but a public client sending one must send the right one
+	if (app.ConfidentialClient || clientSecret != "") && !app.ValidateClientSecret([]byte(clientSecret)) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return false
+	}
+	return true
+}
+
+// getPendingDeviceCode returns the pending device authorization request of a user code,
+// it renders the verification page with an error and returns nil if there is none
+func getPendingDeviceCode(ctx *context.Context, userCode string) (*models.OAuth2DeviceCode, *models.OAuth2Application) {
+	code, err := models.GetOAuth2DeviceCodeByUserCode(userCode)
+	if err != nil && !models.IsErrOAuth2DeviceCodeNotExist(err) {
+		ctx.ServerError("GetOAuth2DeviceCodeByUserCode", err)
+		return nil, nil
+	}
+	if err != nil || code.IsExpired() || !code.IsPending() {
+		ctx.Data["UserCode"] = userCode
+		ctx.RenderWithErr(ctx.Tr("auth.device_code_invalid"), tplGrantDevice, nil)
+		return nil, nil
+	}
+
+	app, err := models.GetOAuth2ApplicationByID(code.ApplicationID)
+	if err != nil {
+		ctx.ServerError("GetOAuth2ApplicationByID", err)
+		return nil, nil
+	}
+	if err := app.LoadUser(); err != nil {
+		ctx.ServerError("LoadUser", err)
+		return nil, nil
+	}
+	return code, app
+}
+
+// DeviceOAuth shows the page where users enter the user code shown by a device and grant it access
+func DeviceOAuth(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("auth.device_title")
+	userCode := ctx.Query("user_code")
+	if userCode == "" {
+		ctx.HTML(http.StatusOK, tplGrantDevice)
+		return
+	}
+
+	code, app := getPendingDeviceCode(ctx, userCode)
+	if code == nil {
+		return
+	}
+	ctx.Data["DeviceCode"] = code
+	ctx.Data["Application"] = app
+	ctx.Data["ApplicationUserLink"] = "<a href=\"" + html.EscapeString(setting.AppURL) + html.EscapeString(url.PathEscape(app.User.LowerName)) + "\">@" + html.EscapeString(app.User.Name) + "</a>"
+	ctx.HTML(http.StatusOK, tplGrantDevice)
+}
+
+// DeviceOAuthPost grants or denies access to the device of a user code
+func DeviceOAuthPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.DeviceGrantForm)
+	ctx.Data["Title"] = ctx.Tr("auth.device_title")
+	code, app := getPendingDeviceCode(ctx, form.UserCode)
+	if code == nil {
+		return
+	}
+
+	if !form.Granted {
+		if _, err := code.Deny(); err != nil {
+			ctx.ServerError("Deny", err)
+			return
+		}
+		ctx.Flash.Info(ctx.Tr("auth.device_denied", app.Name))
+		ctx.Redirect(setting.AppSubURL + "/login/oauth/device")
+		return
+	}
+
+	grant, err := app.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("GetGrantByUserID", err)
+		return
+	}
+	if grant == nil {
+		if grant, err = app.CreateGrant(ctx.User.ID, code.Scope); err != nil {
+			ctx.ServerError("CreateGrant", err)
+			return
+		}
+	}
+	if ok, err := code.Authorize(grant); err != nil {
+		ctx.ServerError("Authorize", err)
+		return
+	} else if !ok {
+		ctx.Data["UserCode"] = form.UserCode
+		ctx.RenderWithErr(ctx.Tr("auth.device_code_invalid"), tplGrantDevice, nil)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("auth.device_granted", app.Name))
+	ctx.Redirect(setting.AppSubURL + "/login/oauth/device")
+}
+
+func handleDeviceCode(ctx *context.Context, form forms.AccessTokenForm, signingKey oauth2.JWTSigningKey) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+		})
+		return
+	}
+	if !authenticateDeviceClient(ctx, app, form.ClientSecret) {
+		return
+	}
+	code, err := models.GetOAuth2DeviceCodeByDeviceCode(form.DeviceCode)
+	if err != nil && !models.IsErrOAuth2DeviceCodeNotExist(err) {
+		log.Error("GetOAuth2DeviceCodeByDeviceCode: %v", err)
+	}
+	if err != nil || code.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "invalid grant",
+		})
+		return
+	}
+	if code.IsExpired() {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeExpiredToken,
+			ErrorDescription: "the device code has expired",
+		})
+		return
+	}
+	if code.IsDenied {
+		if _, err := code.Invalidate(); err != nil {
+			log.Error("Unable to invalidate denied device code %d: %v", code.ID, err)
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeAccessDenied,
+			ErrorDescription: "the user denied the authorization request",
+		})
+		return
+	}
+	if code.GrantID == 0 {
+		if ok, err := code.Poll(); err != nil {
+			log.Error("Unable to record the poll of device code %d: %v", code.ID, err)
+		} else if !ok {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeSlowDown,
+				ErrorDescription: fmt.Sprintf("poll at most every %d seconds", models.OAuth2DeviceCodeInterval),
+			})
+			return
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeAuthorizationPending,
+			ErrorDescription: "the user has not entered the user code yet",
+		})
+		return
+	}
+
+	grant, err := models.GetOAuth2GrantByID(code.GrantID)
+	if err != nil || grant == nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "grant does not exist",
+		})
+		return
+	}
+	// the id token is signed with the client secret when the signing algorithm is symmetric
+	if form.ClientSecret == "" && signingKey.IsSymmetric() && grant.ScopeContains("openid") {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "the client secret is required to sign the id token",
+		})
+		return
+	}
+	// remove the device code from database to deny duplicate usage
+	if ok, err := code.Invalidate(); err != nil || !ok {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "invalid grant",
+		})
+		return
+	}
+	resp, tokenErr := newAccessTokenResponse(grant, signingKey)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
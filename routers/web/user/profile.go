@@ -109,8 +109,9 @@ func Profile(ctx *context.Context) {
 	ctx.Data["PageIsUserProfile"] = true
 	ctx.Data["Owner"] = ctxUser
 	ctx.Data["OpenIDs"] = openIDs
+	ctx.Data["IsActivityVisible"] = ctxUser.IsActivityVisibleToUser(ctx.User)
 
-	if setting.Service.EnableUserHeatmap {
+	if setting.Service.EnableUserHeatmap && ctxUser.IsActivityVisibleToUser(ctx.User) {
 		data, err := models.GetUserHeatmapDataByUser(ctxUser, ctx.User)
 		if err != nil {
 			ctx.ServerError("GetUserHeatmapDataByUser", err)
@@ -227,6 +228,32 @@ func Profile(ctx *context.Context) {
 		}
 	case "stars":
 		ctx.Data["PageIsProfileStarList"] = true
+
+		isOwner := ctx.IsSigned && ctx.User.ID == ctxUser.ID
+		starLists, err := models.GetStarListsByUserID(ctxUser.ID, isOwner)
+		if err != nil {
+			ctx.ServerError("GetStarListsByUserID", err)
+			return
+		}
+		ctx.Data["StarLists"] = starLists
+		ctx.Data["IsStarListOwner"] = isOwner
+
+		var starListID int64
+		if listID := ctx.QueryInt64("list"); listID > 0 {
+			for _, list := range starLists {
+				if list.ID == listID {
+					starListID = list.ID
+					ctx.Data["StarList"] = list
+					break
+				}
+			}
+			if starListID == 0 {
+				ctx.NotFound("StarList", nil)
+				return
+			}
+			ctx.Data["StarListID"] = starListID
+		}
+
 		repos, count, err = models.SearchRepository(&models.SearchRepoOptions{
 			ListOptions: models.ListOptions{
 				PageSize: setting.UI.User.RepoPagingNum,
@@ -237,6 +264,7 @@ func Profile(ctx *context.Context) {
 			OrderBy:            orderBy,
 			Private:            ctx.IsSigned,
 			StarredByID:        ctxUser.ID,
+			StarListID:         starListID,
 			Collaborate:        util.OptionalBoolFalse,
 			TopicOnly:          topicOnly,
 			IncludeDescription: setting.UI.SearchRepoDescription,
@@ -305,6 +333,7 @@ func Profile(ctx *context.Context) {
 
 	pager := context.NewPagination(total, setting.UI.User.RepoPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
+	pager.AddParam(ctx, "list", "StarListID")
 	ctx.Data["Page"] = pager
 
 	ctx.Data["ShowUserEmail"] = len(ctxUser.Email) > 0 && ctx.IsSigned && (!ctxUser.KeepEmailPrivate || ctxUser.ID == ctx.User.ID)
@@ -322,6 +351,10 @@ func Action(ctx *context.Context) {
 	var err error
 	switch ctx.Params(":action") {
 	case "follow":
+		if !models.HasOrgOrUserVisible(u, ctx.User) {
+			ctx.NotFound("Action", nil)
+			return
+		}
 		err = models.FollowUser(ctx.User.ID, u.ID)
 	case "unfollow":
 		err = models.UnfollowUser(ctx.User.ID, u.ID)
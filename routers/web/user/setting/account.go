@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/mailer"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 const (
@@ -90,7 +91,11 @@ func EmailPost(ctx *context.Context) {
 
 	// Make emailaddress primary.
 	if ctx.Query("_method") == "PRIMARY" {
-		if err := models.MakeEmailPrimary(&models.EmailAddress{ID: ctx.QueryInt64("id")}); err != nil {
+		if err := user_service.MakeEmailPrimary(ctx.User, ctx.QueryInt64("id")); err != nil {
+			if models.IsErrEmailAddressNotExist(err) {
+				ctx.NotFound("MakeEmailPrimary", err)
+				return
+			}
 			ctx.ServerError("MakeEmailPrimary", err)
 			return
 		}
@@ -173,12 +178,8 @@ func EmailPost(ctx *context.Context) {
 		return
 	}
 
-	email := &models.EmailAddress{
-		UID:         ctx.User.ID,
-		Email:       form.Email,
-		IsActivated: !setting.Service.RegisterEmailConfirm,
-	}
-	if err := models.AddEmailAddress(email); err != nil {
+	emails, err := user_service.AddEmailAddresses(ctx.User, []string{form.Email})
+	if err != nil {
 		if models.IsErrEmailAlreadyUsed(err) {
 			loadAccountData(ctx)
 
@@ -194,9 +195,9 @@ func EmailPost(ctx *context.Context) {
 		return
 	}
 
-	// Send confirmation email
-	if setting.Service.RegisterEmailConfirm {
-		mailer.SendActivateEmailMail(ctx.User, email)
+	// A confirmation email has been sent
+	email := emails[0]
+	if !email.IsActivated {
 		if err := ctx.Cache.Put("MailResendLimit_"+ctx.User.LowerName, ctx.User.LowerName, 180); err != nil {
 			log.Error("Set cache(MailResendLimit) fail: %v", err)
 		}
@@ -305,7 +306,7 @@ func loadAccountData(ctx *context.Context) {
 	ctx.Data["Emails"] = emails
 	ctx.Data["EmailNotificationsPreference"] = ctx.User.EmailNotifications()
 	ctx.Data["ActivationsPending"] = pendingActivation
-	ctx.Data["CanAddEmails"] = !pendingActivation || !setting.Service.RegisterEmailConfirm
+	ctx.Data["CanAddEmails"] = !pendingActivation || !user_service.EmailVerificationRequired()
 
 	if setting.Service.UserDeleteWithCommentsMaxTime != 0 {
 		ctx.Data["UserDeleteWithCommentsMaxTime"] = setting.Service.UserDeleteWithCommentsMaxTime.String()
@@ -47,6 +47,9 @@ func ApplicationsPost(ctx *context.Context) {
 		UID:  ctx.User.ID,
 		Name: form.Name,
 	}
+	if form.StatusOnly {
+		t.Scope = string(models.AccessTokenScopeWriteStatus)
+	}
 
 	exist, err := models.AccessTokenByNameExists(t)
 	if err != nil {
@@ -7,11 +7,13 @@ package setting
 
 import (
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 )
@@ -159,7 +161,21 @@ func KeysPost(ctx *context.Context) {
 			return
 		}
 
-		if _, err = models.AddPublicKey(ctx.User.ID, form.Title, content, 0); err != nil {
+		var expires timeutil.TimeStamp
+		if form.Expires != "" {
+			t, err := time.ParseInLocation("2006-01-02", form.Expires, setting.DefaultUILocation)
+			if err != nil || !t.After(time.Now()) {
+				loadKeysData(ctx)
+
+				ctx.Data["HasSSHError"] = true
+				ctx.Data["Err_Expires"] = true
+				ctx.RenderWithErr(ctx.Tr("settings.ssh_key_expiration_invalid"), tplSettingsKeys, &form)
+				return
+			}
+			expires = timeutil.TimeStamp(t.Unix())
+		}
+
+		if _, err = models.AddPublicKey(ctx.User.ID, form.Title, content, 0, models.KeySourceWeb, expires); err != nil {
 			ctx.Data["HasSSHError"] = true
 			switch {
 			case models.IsErrKeyAlreadyExist(err):
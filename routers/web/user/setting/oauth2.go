@@ -35,9 +35,11 @@ func OAuthApplicationsPost(ctx *context.Context) {
 	}
 	// TODO validate redirect URI
 	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
-		Name:         form.Name,
-		RedirectURIs: []string{form.RedirectURI},
-		UserID:       ctx.User.ID,
+		Name:               form.Name,
+		RedirectURIs:       []string{form.RedirectURI},
+		UserID:             ctx.User.ID,
+		ConfidentialClient: form.ConfidentialClient,
+		EnableDeviceFlow:   form.EnableDeviceFlow,
 	})
 	if err != nil {
 		ctx.ServerError("CreateOAuth2Application", err)
@@ -68,10 +70,12 @@ func OAuthApplicationsEdit(ctx *context.Context) {
 	// TODO validate redirect URI
 	var err error
 	if ctx.Data["App"], err = models.UpdateOAuth2Application(models.UpdateOAuth2ApplicationOptions{
-		ID:           ctx.ParamsInt64("id"),
-		Name:         form.Name,
-		RedirectURIs: []string{form.RedirectURI},
-		UserID:       ctx.User.ID,
+		ID:                 ctx.ParamsInt64("id"),
+		Name:               form.Name,
+		RedirectURIs:       []string{form.RedirectURI},
+		UserID:             ctx.User.ID,
+		ConfidentialClient: form.ConfidentialClient,
+		EnableDeviceFlow:   form.EnableDeviceFlow,
 	}); err != nil {
 		ctx.ServerError("UpdateOAuth2Application", err)
 		return
@@ -24,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/forms"
+	user_service "code.gitea.io/gitea/services/user"
 
 	"github.com/unknwon/i18n"
 )
@@ -53,8 +54,10 @@ func HandleUsernameChange(ctx *context.Context, user *models.User, newName strin
 
 	// Check if user name has been changed
 	if user.LowerName != strings.ToLower(newName) {
-		if err := models.ChangeUserName(user, newName); err != nil {
+		if err := user_service.RenameUser(ctx.User, user, newName); err != nil {
 			switch {
+			case user_service.IsErrUsernameChangeCooldown(err):
+				ctx.Flash.Error(ctx.Tr("form.username_change_cooldown", err.(user_service.ErrUsernameChangeCooldown).Until.Format("2006-01-02 15:04")))
 			case models.IsErrUserAlreadyExist(err):
 				ctx.Flash.Error(ctx.Tr("form.username_been_taken"))
 			case models.IsErrEmailAlreadyUsed(err):
@@ -115,6 +118,7 @@ func ProfilePost(ctx *context.Context) {
 	}
 	ctx.User.Description = form.Description
 	ctx.User.KeepActivityPrivate = form.KeepActivityPrivate
+	ctx.User.KeepActivitySignedInOnly = form.KeepActivitySignedInOnly
 	ctx.User.Visibility = form.Visibility
 	if err := models.UpdateUserSetting(ctx.User); err != nil {
 		if _, ok := err.(models.ErrEmailAlreadyUsed); ok {
@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// StarListPost creates a star list for the signed in user
+func StarListPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateStarListForm)
+	starsLink := ctx.User.HomeLink() + "?tab=stars"
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(starsLink)
+		return
+	}
+
+	list := &models.StarList{
+		UserID:      ctx.User.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		IsPrivate:   form.Private,
+	}
+	if err := models.CreateStarList(list); err != nil {
+		if models.IsErrStarListAlreadyExist(err) {
+			ctx.Flash.Error(ctx.Tr("user.star_list.already_exists", form.Name))
+			ctx.Redirect(starsLink)
+			return
+		}
+		ctx.ServerError("CreateStarList", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("user.star_list.create_success", list.Name))
+	ctx.Redirect(fmt.Sprintf("%s&list=%d", starsLink, list.ID))
+}
+
+// DeleteStarList deletes a star list of the signed in user
+func DeleteStarList(ctx *context.Context) {
+	list, err := models.GetStarListByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrStarListNotExist(err) {
+			ctx.NotFound("GetStarListByID", err)
+		} else {
+			ctx.ServerError("GetStarListByID", err)
+		}
+		return
+	}
+	if list.UserID != ctx.User.ID {
+		ctx.NotFound("DeleteStarList", nil)
+		return
+	}
+
+	if err := models.DeleteStarList(list); err != nil {
+		ctx.ServerError("DeleteStarList", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("user.star_list.delete_success", list.Name))
+	ctx.Redirect(ctx.User.HomeLink() + "?tab=stars")
+}
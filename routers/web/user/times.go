@@ -0,0 +1,121 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"sort"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const tplTrackedTimes base.TplName = "user/dashboard/times"
+
+// TrackedTimes render the time tracked by the signed in user across repositories
+func TrackedTimes(ctx *context.Context) {
+	if !setting.Service.EnableTimetracking {
+		ctx.NotFound("TrackedTimes", nil)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("your_tracked_time")
+	ctx.Data["PageIsTrackedTimesDashboard"] = true
+
+	totals, err := models.GetTrackedSecondsPerRepo(models.FindTrackedTimesOptions{UserID: ctx.User.ID})
+	if err != nil {
+		ctx.ServerError("GetTrackedSecondsPerRepo", err)
+		return
+	}
+	repoIDs := make([]int64, 0, len(totals))
+	for repoID := range totals {
+		repoIDs = append(repoIDs, repoID)
+	}
+	reposMap, err := models.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	// Only list repositories whose issues the user can still read
+	repos := make([]*models.Repository, 0, len(reposMap))
+	var totalTime int64
+	for _, repo := range reposMap {
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if !perm.CanRead(models.UnitTypeIssues) && !perm.CanRead(models.UnitTypePullRequests) {
+			continue
+		}
+		repos = append(repos, repo)
+		totalTime += totals[repo.ID]
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].FullName() < repos[j].FullName()
+	})
+
+	ctx.Data["Repos"] = repos
+	ctx.Data["Totals"] = totals
+	ctx.Data["TotalTime"] = totalTime
+	if len(repos) == 0 {
+		ctx.HTML(http.StatusOK, tplTrackedTimes)
+		return
+	}
+
+	// Show the times of the selected repository, or of the first one
+	repo := repos[0]
+	if repoID := ctx.QueryInt64("repo"); repoID > 0 {
+		repo = nil
+		for _, r := range repos {
+			if r.ID == repoID {
+				repo = r
+				break
+			}
+		}
+		if repo == nil {
+			ctx.NotFound("TrackedTimes", nil)
+			return
+		}
+	}
+	ctx.Data["Repo"] = repo
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	opts := models.FindTrackedTimesOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.IssuePagingNum,
+		},
+		UserID:       ctx.User.ID,
+		RepositoryID: repo.ID,
+	}
+	count, err := models.CountTrackedTimes(opts)
+	if err != nil {
+		ctx.ServerError("CountTrackedTimes", err)
+		return
+	}
+	trackedTimes, err := models.GetTrackedTimes(opts)
+	if err != nil {
+		ctx.ServerError("GetTrackedTimes", err)
+		return
+	}
+	if err := trackedTimes.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	ctx.Data["TrackedTimes"] = trackedTimes
+
+	pager := context.NewPagination(int(count), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParamString("repo", ctx.Query("repo"))
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplTrackedTimes)
+}
@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/routers/web/explore"
 	"code.gitea.io/gitea/routers/web/org"
 	"code.gitea.io/gitea/routers/web/repo"
+	"code.gitea.io/gitea/routers/web/snippet"
 	"code.gitea.io/gitea/routers/web/user"
 	userSetting "code.gitea.io/gitea/routers/web/user/setting"
 	"code.gitea.io/gitea/services/auth"
@@ -43,7 +44,6 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tstranex/u2f"
 )
 
@@ -140,8 +140,7 @@ func Routes() *web.Route {
 
 	// prometheus metrics endpoint - do not need to go through contexter
 	if setting.Metrics.Enabled {
-		c := metrics.NewCollector()
-		prometheus.MustRegister(c)
+		metrics.Register()
 
 		routes.Get("/metrics", append(common, Metrics)...)
 	}
@@ -216,6 +215,21 @@ func RegisterRoutes(m *web.Route) {
 		}
 	}
 
+	// pagesEnabled requires pages to be enabled by admin.
+	pagesEnabled := func(ctx *context.Context) {
+		if !setting.Pages.Enabled {
+			ctx.NotFound("PagesEnabled", nil)
+			return
+		}
+	}
+
+	accessRequestsEnabled := func(ctx *context.Context) {
+		if !setting.Repository.EnableAccessRequests {
+			ctx.NotFound("AccessRequestsEnabled", nil)
+			return
+		}
+	}
+
 	lfsServerEnabled := func(ctx *context.Context) {
 		if !setting.LFS.StartServer {
 			ctx.Error(http.StatusNotFound)
@@ -237,10 +251,23 @@ func RegisterRoutes(m *web.Route) {
 		m.Get("/users", explore.Users)
 		m.Get("/organizations", explore.Organizations)
 		m.Get("/code", explore.Code)
+		m.Get("/snippets", explore.Snippets)
+		m.Get("/good_first_issues", explore.GoodFirstIssues)
 	}, ignExploreSignIn)
+	m.Group("/snippets", func() {
+		m.Combo("/new", reqSignIn).Get(snippet.New).
+			Post(bindIgnErr(forms.CreateSnippetForm{}), snippet.NewPost)
+		m.Group("/{uuid}", func() {
+			m.Get("", snippet.View)
+			m.Get("/raw/{filename}", snippet.Raw)
+			m.Post("/comments", reqSignIn, bindIgnErr(forms.CreateSnippetCommentForm{}), snippet.CommentPost)
+			m.Post("/delete", reqSignIn, snippet.Delete)
+		})
+	}, ignSignIn)
 	m.Get("/issues", reqSignIn, user.Issues)
 	m.Get("/pulls", reqSignIn, user.Pulls)
 	m.Get("/milestones", reqSignIn, reqMilestonesDashboardPageEnabled, user.Milestones)
+	m.Post(context.ViewAsStopLink, reqSignIn, admin.StopViewAs)
 
 	// ***** START: User *****
 	m.Group("/user", func() {
@@ -292,8 +319,11 @@ func RegisterRoutes(m *web.Route) {
 		// TODO manage redirection
 		m.Post("/authorize", bindIgnErr(forms.AuthorizationForm{}), user.AuthorizeOAuth)
 	}, ignSignInAndCsrf, reqSignIn)
+	m.Combo("/login/oauth/device", reqSignIn).Get(user.DeviceOAuth).
+		Post(bindIgnErr(forms.DeviceGrantForm{}), user.DeviceOAuthPost)
 	m.Get("/login/oauth/userinfo", ignSignInAndCsrf, user.InfoOAuth)
 	m.Post("/login/oauth/access_token", CorsHandler(), bindIgnErr(forms.AccessTokenForm{}), ignSignInAndCsrf, user.AccessTokenOAuth)
+	m.Post("/login/oauth/device/code", CorsHandler(), bindIgnErr(forms.DeviceAuthorizationForm{}), ignSignInAndCsrf, user.DeviceAuthorizationOAuth)
 	m.Get("/login/oauth/keys", ignSignInAndCsrf, user.OIDCKeys)
 
 	m.Group("/user/settings", func() {
@@ -347,11 +377,42 @@ func RegisterRoutes(m *web.Route) {
 		m.Get("/organization", userSetting.Organization)
 		m.Get("/repos", userSetting.Repos)
 		m.Post("/repos/unadopted", userSetting.AdoptOrDeleteRepository)
+
+		m.Group("/hooks", func() {
+			m.Get("", userSetting.Webhooks)
+			m.Post("/delete", userSetting.DeleteWebhook)
+			m.Get("/{type}/new", repo.WebhooksNew)
+			m.Post("/gitea/new", bindIgnErr(forms.NewWebhookForm{}), repo.GiteaHooksNewPost)
+			m.Post("/gogs/new", bindIgnErr(forms.NewGogshookForm{}), repo.GogsHooksNewPost)
+			m.Post("/slack/new", bindIgnErr(forms.NewSlackHookForm{}), repo.SlackHooksNewPost)
+			m.Post("/discord/new", bindIgnErr(forms.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
+			m.Post("/dingtalk/new", bindIgnErr(forms.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
+			m.Post("/telegram/new", bindIgnErr(forms.NewTelegramHookForm{}), repo.TelegramHooksNewPost)
+			m.Post("/matrix/new", bindIgnErr(forms.NewMatrixHookForm{}), repo.MatrixHooksNewPost)
+			m.Post("/msteams/new", bindIgnErr(forms.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
+			m.Post("/feishu/new", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
+			m.Get("/{id}", repo.WebHooksEdit)
+			m.Post("/{id}/enable", repo.EnableWebhook)
+			m.Post("/gitea/{id}", bindIgnErr(forms.NewWebhookForm{}), repo.WebHooksEditPost)
+			m.Post("/gogs/{id}", bindIgnErr(forms.NewGogshookForm{}), repo.GogsHooksEditPost)
+			m.Post("/slack/{id}", bindIgnErr(forms.NewSlackHookForm{}), repo.SlackHooksEditPost)
+			m.Post("/discord/{id}", bindIgnErr(forms.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
+			m.Post("/dingtalk/{id}", bindIgnErr(forms.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
+			m.Post("/telegram/{id}", bindIgnErr(forms.NewTelegramHookForm{}), repo.TelegramHooksEditPost)
+			m.Post("/matrix/{id}", bindIgnErr(forms.NewMatrixHookForm{}), repo.MatrixHooksEditPost)
+			m.Post("/msteams/{id}", bindIgnErr(forms.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
+			m.Post("/feishu/{id}", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksEditPost)
+		}, webhooksEnabled)
 	}, reqSignIn, func(ctx *context.Context) {
 		ctx.Data["PageIsUserSettings"] = true
 		ctx.Data["AllThemes"] = setting.UI.Themes
 	})
 
+	m.Group("/user/starlists", func() {
+		m.Post("", bindIgnErr(forms.CreateStarListForm{}), user.StarListPost)
+		m.Post("/{id}/delete", user.DeleteStarList)
+	}, reqSignIn)
+
 	m.Group("/user", func() {
 		// r.Get("/feeds", binding.Bind(auth.FeedsForm{}), user.Feeds)
 		m.Get("/activate", user.Activate, reqSignIn)
@@ -365,6 +426,7 @@ func RegisterRoutes(m *web.Route) {
 		m.Post("/forgot_password", user.ForgotPasswdPost)
 		m.Post("/logout", user.SignOut)
 		m.Get("/task/{task}", user.TaskStatus)
+		m.Get("/times", reqSignIn, user.TrackedTimes)
 	})
 	// ***** END: User *****
 
@@ -381,6 +443,8 @@ func RegisterRoutes(m *web.Route) {
 		m.Group("/monitor", func() {
 			m.Get("", admin.Monitor)
 			m.Post("/cancel/{pid}", admin.MonitorCancel)
+			m.Get("/tasks/{name}", admin.TaskLog)
+			m.Get("/tasks/{name}/events", admin.TaskLogEvents)
 			m.Group("/queue/{qid}", func() {
 				m.Get("", admin.Queue)
 				m.Post("/set", admin.SetQueueSettings)
@@ -393,8 +457,12 @@ func RegisterRoutes(m *web.Route) {
 		m.Group("/users", func() {
 			m.Get("", admin.Users)
 			m.Combo("/new").Get(admin.NewUser).Post(bindIgnErr(forms.AdminCreateUserForm{}), admin.NewUserPost)
+			m.Get("/pending", admin.PendingRegistrations)
+			m.Post("/pending/{id}/approve", admin.ApprovePendingRegistration)
+			m.Post("/pending/{id}/reject", admin.RejectPendingRegistration)
 			m.Combo("/{userid}").Get(admin.EditUser).Post(bindIgnErr(forms.AdminEditUserForm{}), admin.EditUserPost)
 			m.Post("/{userid}/delete", admin.DeleteUser)
+			m.Post("/{userid}/view_as", admin.ViewAsUser)
 		})
 
 		m.Group("/emails", func() {
@@ -409,6 +477,8 @@ func RegisterRoutes(m *web.Route) {
 		m.Group("/repos", func() {
 			m.Get("", admin.Repos)
 			m.Combo("/unadopted").Get(admin.UnadoptedRepos).Post(admin.AdoptOrDeleteRepository)
+			m.Combo("/templates").Get(admin.InstanceTemplates).Post(bindIgnErr(forms.AdminInstanceTemplateForm{}), admin.InstanceTemplatesPost)
+			m.Post("/templates/delete", admin.DeleteInstanceTemplate)
 			m.Post("/delete", admin.DeleteRepo)
 		})
 
@@ -416,6 +486,7 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("", admin.DefaultOrSystemWebhooks)
 			m.Post("/delete", admin.DeleteDefaultOrSystemWebhook)
 			m.Get("/{id}", repo.WebHooksEdit)
+			m.Post("/{id}/enable", repo.EnableWebhook)
 			m.Post("/gitea/{id}", bindIgnErr(forms.NewWebhookForm{}), repo.WebHooksEditPost)
 			m.Post("/gogs/{id}", bindIgnErr(forms.NewGogshookForm{}), repo.GogsHooksEditPost)
 			m.Post("/slack/{id}", bindIgnErr(forms.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -440,6 +511,13 @@ func RegisterRoutes(m *web.Route) {
 			m.Post("/feishu/new", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 		})
 
+		m.Group("/endpoints", func() {
+			m.Get("", admin.EndpointAudit)
+			m.Post("/notify", admin.EndpointAuditNotify)
+		})
+
+		m.Get("/disk_usage", admin.DiskUsage)
+
 		m.Group("/auths", func() {
 			m.Get("", admin.Authentications)
 			m.Combo("/new").Get(admin.NewAuthSource).Post(bindIgnErr(forms.AuthenticationForm{}), admin.NewAuthSourcePost)
@@ -538,6 +616,7 @@ func RegisterRoutes(m *web.Route) {
 					m.Post("/msteams/new", bindIgnErr(forms.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 					m.Post("/feishu/new", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 					m.Get("/{id}", repo.WebHooksEdit)
+					m.Post("/{id}/enable", repo.EnableWebhook)
 					m.Post("/gitea/{id}", bindIgnErr(forms.NewWebhookForm{}), repo.WebHooksEditPost)
 					m.Post("/gogs/{id}", bindIgnErr(forms.NewGogshookForm{}), repo.GogsHooksEditPost)
 					m.Post("/slack/{id}", bindIgnErr(forms.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -557,16 +636,32 @@ func RegisterRoutes(m *web.Route) {
 					m.Post("/initialize", bindIgnErr(forms.InitializeLabelsForm{}), org.InitializeLabels)
 				})
 
+				m.Group("/tokens", func() {
+					m.Combo("").Get(org.Tokens).
+						Post(bindIgnErr(forms.NewOrgAccessTokenForm{}), org.TokensPost)
+					m.Post("/delete", org.DeleteToken)
+				})
+
+				m.Combo("/cla").Get(org.SettingsCLA).
+					Post(bindIgnErr(forms.OrgCLAForm{}), org.SettingsCLAPost)
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
 	}, reqSignIn)
+
+	m.Get("/org/{org}/roadmap", ignSignIn, context.OrgAssignment(), org.Roadmap)
+	m.Get("/org/{org}/workload", ignSignIn, context.OrgAssignment(), org.Workload)
+	m.Get("/org/{org}/review_stats", ignSignIn, context.OrgAssignment(), org.ReviewStats)
+	m.Get("/org/{org}/cla", ignSignIn, context.OrgAssignment(), org.CLA)
+	m.Post("/org/{org}/cla", reqSignIn, context.OrgAssignment(), bindIgnErr(forms.SignCLAForm{}), org.CLAPost)
 	// ***** END: Organization *****
 
 	// ***** START: Repository *****
 	m.Group("/repo", func() {
 		m.Get("/create", repo.Create)
 		m.Post("/create", bindIgnErr(forms.CreateRepoForm{}), repo.CreatePost)
+		m.Get("/templates", repo.TemplateCatalog)
 		m.Get("/migrate", repo.Migrate)
 		m.Post("/migrate", bindIgnErr(forms.MigrateRepoForm{}), repo.MigratePost)
 		m.Group("/fork", func() {
@@ -583,6 +678,7 @@ func RegisterRoutes(m *web.Route) {
 			m.Combo("").Get(repo.Settings).
 				Post(bindIgnErr(forms.RepoSettingForm{}), repo.SettingsPost)
 			m.Post("/avatar", bindIgnErr(forms.AvatarForm{}), repo.SettingsAvatar)
+			m.Post("/avatar/generate", repo.SettingsGenerateAvatar)
 			m.Post("/avatar/delete", repo.SettingsDeleteAvatar)
 
 			m.Group("/collaboration", func() {
@@ -595,6 +691,12 @@ func RegisterRoutes(m *web.Route) {
 				})
 			})
 
+			m.Group("/access_requests", func() {
+				m.Get("", repo.SettingsAccessRequests)
+				m.Post("/{id}/approve", bindIgnErr(forms.ApproveRepoAccessRequestForm{}), repo.SettingsAccessRequestApprove)
+				m.Post("/{id}/reject", repo.SettingsAccessRequestReject)
+			}, accessRequestsEnabled)
+
 			m.Group("/branches", func() {
 				m.Combo("").Get(repo.ProtectedBranch).Post(repo.ProtectedBranchPost)
 				m.Combo("/*").Get(repo.SettingsProtectedBranch).
@@ -609,6 +711,32 @@ func RegisterRoutes(m *web.Route) {
 				m.Post("/{id}", bindIgnErr(forms.ProtectTagForm{}), context.RepoMustNotBeArchived(), repo.EditProtectedTagPost)
 			})
 
+			m.Group("/pages", func() {
+				m.Combo("").Get(repo.SettingsPages).
+					Post(bindIgnErr(forms.RepoPagesForm{}), repo.SettingsPagesPost)
+				m.Post("/deploy", repo.SettingsPagesDeploy)
+				m.Post("/verify", repo.SettingsPagesVerify)
+				m.Post("/delete", repo.SettingsPagesDelete)
+			}, pagesEnabled, repo.MustBeNotEmpty)
+
+			m.Get("/labeler", repo.MustBeNotEmpty, repo.SettingsLabeler)
+
+			m.Group("/triage", func() {
+				m.Combo("").Get(repo.SettingsTriage).
+					Post(bindIgnErr(forms.IssueTriageRuleForm{}), context.RepoMustNotBeArchived(), repo.SettingsTriagePost)
+				m.Get("/export", repo.SettingsTriageExport)
+				m.Post("/delete", context.RepoMustNotBeArchived(), repo.SettingsTriageDelete)
+				m.Combo("/{id}").Get(repo.SettingsTriageEdit).
+					Post(bindIgnErr(forms.IssueTriageRuleForm{}), context.RepoMustNotBeArchived(), repo.SettingsTriageEditPost)
+			}, repo.MustEnableIssues)
+
+			m.Group("/snapshots", func() {
+				m.Combo("").Get(repo.SettingsSnapshots).
+					Post(bindIgnErr(forms.RepoSnapshotForm{}), repo.SettingsSnapshotsPost)
+				m.Post("/create", repo.SettingsSnapshotsCreate)
+				m.Post("/delete", repo.SettingsSnapshotsDelete)
+			}, repo.MustBeNotEmpty, context.RepoMustNotBeArchived())
+
 			m.Group("/hooks/git", func() {
 				m.Get("", repo.GitHooks)
 				m.Combo("/{name}").Get(repo.GitHooksEdit).
@@ -629,7 +757,9 @@ func RegisterRoutes(m *web.Route) {
 				m.Post("/msteams/new", bindIgnErr(forms.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 				m.Post("/feishu/new", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 				m.Get("/{id}", repo.WebHooksEdit)
+				m.Post("/{id}/enable", repo.EnableWebhook)
 				m.Post("/{id}/test", repo.TestWebhook)
+				m.Post("/{id}/deliveries/{delivery_id}/redeliver", repo.RedeliverWebhook)
 				m.Post("/gitea/{id}", bindIgnErr(forms.NewWebhookForm{}), repo.WebHooksEditPost)
 				m.Post("/gogs/{id}", bindIgnErr(forms.NewGogshookForm{}), repo.GogsHooksEditPost)
 				m.Post("/slack/{id}", bindIgnErr(forms.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -658,16 +788,20 @@ func RegisterRoutes(m *web.Route) {
 					m.Get("/", repo.LFSLocks)
 					m.Post("/", repo.LFSLockFile)
 					m.Post("/{lid}/unlock", repo.LFSUnlock)
+					m.Post("/unlock", repo.LFSUnlockSelected)
+					m.Post("/unlock_stale", repo.LFSUnlockStale)
 				})
 			})
 
 		}, func(ctx *context.Context) {
 			ctx.Data["PageIsSettings"] = true
 			ctx.Data["LFSStartServer"] = setting.LFS.StartServer
+			ctx.Data["EnableAccessRequests"] = setting.Repository.EnableAccessRequests
 		})
 	}, reqSignIn, context.RepoAssignment, context.UnitTypes(), reqRepoAdmin, context.RepoRef())
 
 	m.Post("/{username}/{reponame}/action/{action}", reqSignIn, context.RepoAssignment, context.UnitTypes(), repo.Action)
+	m.Post("/{username}/{reponame}/access_request", reqSignIn, accessRequestsEnabled, bindIgnErr(forms.RepoAccessRequestForm{}), repo.RequestAccessPost)
 
 	// Grouping for those endpoints not requiring authentication
 	m.Group("/{username}/{reponame}", func() {
@@ -890,6 +1024,9 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/{period}", repo.Activity)
 		}, context.RepoRef(), repo.MustBeNotEmpty, context.RequireRepoReaderOr(models.UnitTypePullRequests, models.UnitTypeIssues, models.UnitTypeReleases))
 
+		m.Get("/workload", context.RequireRepoReaderOr(models.UnitTypeIssues, models.UnitTypePullRequests), repo.Workload)
+		m.Get("/review_stats", context.RequireRepoReader(models.UnitTypePullRequests), repo.ReviewStats)
+
 		m.Group("/activity_author_data", func() {
 			m.Get("", repo.ActivityAuthors)
 			m.Get("/{period}", repo.ActivityAuthors)
@@ -913,10 +1050,12 @@ func RegisterRoutes(m *web.Route) {
 			m.Get(".patch", repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", context.RepoMustNotBeArchived(), bindIgnErr(forms.MergePullRequestForm{}), repo.MergePullRequest)
+			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
 			m.Post("/update", repo.UpdatePullRequest)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
+				m.Get("/search", context.RepoRef(), repo.SetWhitespaceBehavior, repo.SearchPullFiles)
 				m.Group("/reviews", func() {
 					m.Get("/new_comment", repo.RenderNewCodeCommentForm)
 					m.Post("/comments", bindIgnErr(forms.CodeCommentForm{}), repo.CreateCodeComment)
@@ -943,6 +1082,11 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownload)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
 
+		m.Group("/pages", func() {
+			m.Get("", repo.ServePages)
+			m.Get("/*", repo.ServePages)
+		}, pagesEnabled, reqRepoCodeReader)
+
 		m.Group("/commits", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RefCommits)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.RefCommits)
@@ -0,0 +1,214 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package antispam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Kind is the kind of the classified content
+type Kind string
+
+const (
+	// KindIssue is the title and the description of an issue
+	KindIssue Kind = "issue"
+	// KindPullRequest is the title and the description of a pull request
+	KindPullRequest Kind = "pull_request"
+	// KindComment is a comment of an issue or a pull request
+	KindComment Kind = "comment"
+)
+
+// Content represents text posted by a user which is classified before it is published
+type Content struct {
+	Kind   Kind
+	IsEdit bool
+	Doer   *models.User
+	Repo   *models.Repository
+	Title  string
+	Text   string
+}
+
+// ErrContentBlocked represents a "ContentBlocked" kind of error.
+type ErrContentBlocked struct {
+	// Message of the classifier shown to the author, if any
+	Message string
+	// Unavailable is true if the content is rejected because it can't be classified
+	Unavailable bool
+}
+
+// IsErrContentBlocked checks if an error is a ErrContentBlocked.
+func IsErrContentBlocked(err error) bool {
+	_, ok := err.(ErrContentBlocked)
+	return ok
+}
+
+func (err ErrContentBlocked) Error() string {
+	if err.Unavailable {
+		return "the content can't be checked right now, please try again later"
+	}
+	if err.Message == "" {
+		return "the content has been blocked by the content classifier"
+	}
+	return err.Message
+}
+
+// Classifier classifies the content posted by users
+type Classifier interface {
+	Classify(ctx context.Context, req *api.ContentClassificationRequest) (*api.ContentClassificationResponse, error)
+}
+
+// GetClassifier returns the content classifier of the instance, nil if there is none
+var GetClassifier = func() Classifier {
+	if setting.AntiSpam.ClassifierURL == "" {
+		return nil
+	}
+	return &httpClassifier{
+		url:    setting.AntiSpam.ClassifierURL,
+		token:  setting.AntiSpam.ClassifierToken,
+		client: getClient(),
+	}
+}
+
+var (
+	clientMutex   sync.Mutex
+	client        *http.Client
+	clientTimeout time.Duration
+)
+
+// getClient returns the HTTP client shared by the requests to the classifier so that their connections are reused,
+// it is only rebuilt when the configured timeout changes
+func getClient() *http.Client {
+	clientMutex.Lock()
+	defer clientMutex.Unlock()
+
+	if client == nil || clientTimeout != setting.AntiSpam.Timeout {
+		client = &http.Client{
+			Transport: &http.Transport{Proxy: proxy.Proxy()},
+			Timeout:   setting.AntiSpam.Timeout,
+		}
+		clientTimeout = setting.AntiSpam.Timeout
+	}
+	return client
+}
+
+// Check classifies the content before it is published. It returns an ErrContentBlocked if the classifier
+// blocks the content, and true if the content has to be flagged for moderation once it is published.
+// Failures of the classifier publish the content unless the instance is configured to fail closed.
+func Check(content *Content) (bool, error) {
+	classifier := GetClassifier()
+	if classifier == nil || content.Doer == nil || content.Doer.IsAdmin {
+		return false, nil
+	}
+
+	req := &api.ContentClassificationRequest{
+		Kind:    string(content.Kind),
+		IsEdit:  content.IsEdit,
+		Author:  convert.ToUser(content.Doer, nil),
+		Title:   content.Title,
+		Content: content.Text,
+	}
+	if content.Repo != nil {
+		req.Repository = content.Repo.FullName()
+	}
+
+	resp, err := classifier.Classify(graceful.GetManager().ShutdownContext(), req)
+	if err != nil {
+		log.Error("Unable to classify the %s of %s: %v", content.Kind, content.Doer.Name, err)
+		return false, classificationFailed()
+	}
+
+	switch resp.Action {
+	case api.ContentClassificationBlock:
+		log.Info("The content classifier blocked the %s of %s", content.Kind, content.Doer.Name)
+		return false, ErrContentBlocked{Message: resp.Message}
+	case api.ContentClassificationFlag:
+		return true, nil
+	case api.ContentClassificationAllow:
+		return false, nil
+	default:
+		log.Error("Unknown action %q of the content classifier for the %s of %s", resp.Action, content.Kind, content.Doer.Name)
+		return false, classificationFailed()
+	}
+}
+
+// classificationFailed returns the error to return when the content can't be classified, nil to publish it anyway
+func classificationFailed() error {
+	if setting.AntiSpam.FailOpen {
+		return nil
+	}
+	return ErrContentBlocked{Unavailable: true}
+}
+
+// Flag reports the published content flagged by the classifier to the moderators as a system notice
+func Flag(content *Content, link string) {
+	action := "posted"
+	if content.IsEdit {
+		action = "edited"
+	}
+	if err := models.CreateNotice(models.NoticeFlaggedContent, "The content classifier flagged the %s %s by %s: %s",
+		strings.ReplaceAll(string(content.Kind), "_", " "), action, content.Doer.Name, link); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
+
+// httpClassifier posts the content to an external service
+type httpClassifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (c *httpClassifier) Classify(ctx context.Context, content *api.ContentClassificationRequest) (*api.ContentClassificationResponse, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	body, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("the content classifier responded with %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	classification := &api.ContentClassificationResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(classification); err != nil {
+		return nil, fmt.Errorf("invalid response of the content classifier: %v", err)
+	}
+	return classification, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package antispam
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClassifier struct {
+	requests []*api.ContentClassificationRequest
+	response *api.ContentClassificationResponse
+	err      error
+}
+
+func (c *fakeClassifier) Classify(ctx context.Context, req *api.ContentClassificationRequest) (*api.ContentClassificationResponse, error) {
+	c.requests = append(c.requests, req)
+	return c.response, c.err
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	admin := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	content := &Content{Kind: KindIssue, Doer: doer, Repo: repo, Title: "title", Text: "text"}
+
+	// no classifier
+	flagged, err := Check(content)
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+
+	classifier := &fakeClassifier{}
+	defer func(get func() Classifier) {
+		GetClassifier = get
+	}(GetClassifier)
+	GetClassifier = func() Classifier {
+		return classifier
+	}
+	defer func(failOpen bool) {
+		setting.AntiSpam.FailOpen = failOpen
+	}(setting.AntiSpam.FailOpen)
+
+	classifier.response = &api.ContentClassificationResponse{Action: api.ContentClassificationAllow}
+	flagged, err = Check(content)
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+	if assert.Len(t, classifier.requests, 1) {
+		req := classifier.requests[0]
+		assert.Equal(t, "issue", req.Kind)
+		assert.False(t, req.IsEdit)
+		assert.Equal(t, "user2/repo1", req.Repository)
+		assert.Equal(t, "user2", req.Author.UserName)
+		assert.Equal(t, "title", req.Title)
+		assert.Equal(t, "text", req.Content)
+	}
+
+	classifier.response = &api.ContentClassificationResponse{Action: api.ContentClassificationFlag}
+	flagged, err = Check(content)
+	assert.NoError(t, err)
+	assert.True(t, flagged)
+
+	classifier.response = &api.ContentClassificationResponse{Action: api.ContentClassificationBlock, Message: "no spam"}
+	_, err = Check(content)
+	assert.True(t, IsErrContentBlocked(err))
+	assert.Equal(t, "no spam", err.Error())
+
+	// admins are never classified
+	flagged, err = Check(&Content{Kind: KindComment, Doer: admin, Repo: repo, Text: "text"})
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+	assert.Len(t, classifier.requests, 3)
+
+	// failures
+	classifier.response = &api.ContentClassificationResponse{Action: "unknown"}
+	setting.AntiSpam.FailOpen = true
+	flagged, err = Check(content)
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+
+	classifier.response, classifier.err = nil, fmt.Errorf("unreachable")
+	flagged, err = Check(content)
+	assert.NoError(t, err)
+	assert.False(t, flagged)
+
+	setting.AntiSpam.FailOpen = false
+	_, err = Check(content)
+	if assert.True(t, IsErrContentBlocked(err)) {
+		assert.True(t, err.(ErrContentBlocked).Unavailable)
+	}
+}
+
+func TestFlag(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	Flag(&Content{Kind: KindPullRequest, IsEdit: true, Doer: doer}, "https://try.gitea.io/user2/repo1/pulls/2")
+
+	models.AssertExistsAndLoadBean(t, &models.Notice{
+		Type:        models.NoticeFlaggedContent,
+		Description: "The content classifier flagged the pull request edited by user2: https://try.gitea.io/user2/repo1/pulls/2",
+	})
+}
+
+func TestHTTPClassifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+		req := &api.ContentClassificationRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		resp := &api.ContentClassificationResponse{Action: api.ContentClassificationAllow}
+		if req.Content == "spam" {
+			resp = &api.ContentClassificationResponse{Action: api.ContentClassificationBlock, Message: "spam"}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	classifier := &httpClassifier{url: server.URL, token: "secret", client: http.DefaultClient}
+	resp, err := classifier.Classify(context.Background(), &api.ContentClassificationRequest{Content: "spam"})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ContentClassificationBlock, resp.Action)
+	assert.Equal(t, "spam", resp.Message)
+
+	resp, err = classifier.Classify(context.Background(), &api.ContentClassificationRequest{Content: "ham"})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ContentClassificationAllow, resp.Action)
+
+	classifier.token = "wrong"
+	_, err = classifier.Classify(context.Background(), &api.ContentClassificationRequest{Content: "ham"})
+	assert.Error(t, err)
+}
+
+func TestGetClient(t *testing.T) {
+	defer func(timeout time.Duration) {
+		setting.AntiSpam.Timeout = timeout
+	}(setting.AntiSpam.Timeout)
+
+	setting.AntiSpam.Timeout = 5 * time.Second
+	c := getClient()
+	assert.Same(t, c, getClient())
+
+	setting.AntiSpam.Timeout = 10 * time.Second
+	assert.NotSame(t, c, getClient())
+	assert.Equal(t, 10*time.Second, getClient().Timeout)
+}
@@ -0,0 +1,310 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package audit reports the endpoints configured on the instance which use plaintext
+// connections or deprecated services.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// Kind is the kind of configuration an endpoint belongs to
+type Kind string
+
+// Kinds of audited configurations
+const (
+	KindWebhook    Kind = "webhook"
+	KindAuthSource Kind = "auth_source"
+	KindMirror     Kind = "mirror"
+	KindPushMirror Kind = "push_mirror"
+)
+
+// Reason tells why an endpoint is reported
+type Reason string
+
+// Reasons for reporting an endpoint
+const (
+	// ReasonPlaintext is reported for endpoints using unencrypted connections
+	ReasonPlaintext Reason = "plaintext"
+	// ReasonDeprecated is reported for webhooks of a deprecated type or payload version
+	ReasonDeprecated Reason = "deprecated"
+)
+
+// Finding is a reported endpoint
+type Finding struct {
+	Kind   Kind
+	Reason Reason
+	// Name describes the configuration, e.g. the type of a webhook or the name of an authentication source
+	Name string
+	// URL is the address of the endpoint without credentials
+	URL string
+	// Link is the page where the configuration can be changed
+	Link string
+	// Owner is the user or organization responsible for the configuration, it is nil for site-wide configurations
+	Owner *models.User
+	// Repo is the repository of the configuration, if any
+	Repo *models.Repository
+}
+
+// IsPlaintextURL returns true if rawURL uses a scheme without transport encryption
+func IsPlaintextURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "git", "ldap":
+		return true
+	}
+	return false
+}
+
+func sanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return util.NewStringURLSanitizer(rawURL, false).Replace(rawURL)
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Scan returns the reported endpoints of all webhooks, authentication sources and mirrors
+func Scan(ctx context.Context) ([]*Finding, error) {
+	findings := make([]*Finding, 0, 10)
+
+	hooks, err := scanWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, hooks...)
+
+	sources, err := scanAuthSources()
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, sources...)
+
+	mirrors, err := scanMirrors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, mirrors...), nil
+}
+
+func scanWebhooks(ctx context.Context) ([]*Finding, error) {
+	findings := make([]*Finding, 0, 10)
+	owners := make(map[int64]*models.User)
+	repos := make(map[int64]*models.Repository)
+
+	err := models.Iterate(
+		models.DefaultDBContext(),
+		new(models.Webhook),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before scanning the webhooks")
+			default:
+			}
+
+			w := bean.(*models.Webhook)
+			var reason Reason
+			switch {
+			case IsPlaintextURL(w.URL):
+				reason = ReasonPlaintext
+			case util.IsStringInSlice(w.Type, setting.Webhook.DeprecatedTypes, true),
+				(w.Type == models.GITEA || w.Type == models.GOGS) && w.PayloadVersion == models.PayloadVersionLegacy:
+				reason = ReasonDeprecated
+			default:
+				return nil
+			}
+
+			f := &Finding{
+				Kind:   KindWebhook,
+				Reason: reason,
+				Name:   w.Type,
+				URL:    sanitizeURL(w.URL),
+			}
+			switch {
+			case w.RepoID > 0:
+				repo, ok := repos[w.RepoID]
+				if !ok {
+					var err error
+					if repo, err = models.GetRepositoryByID(w.RepoID); err != nil {
+						if models.IsErrRepoNotExist(err) {
+							return nil
+						}
+						return err
+					}
+					if err = repo.GetOwner(); err != nil {
+						return err
+					}
+					repos[w.RepoID] = repo
+				}
+				f.Repo = repo
+				f.Owner = repo.Owner
+				f.Link = fmt.Sprintf("%s/settings/hooks/%d", repo.HTMLURL(), w.ID)
+			case w.OrgID > 0:
+				owner, ok := owners[w.OrgID]
+				if !ok {
+					var err error
+					if owner, err = models.GetUserByID(w.OrgID); err != nil {
+						if models.IsErrUserNotExist(err) {
+							return nil
+						}
+						return err
+					}
+					owners[w.OrgID] = owner
+				}
+				f.Owner = owner
+				if owner.IsOrganization() {
+					f.Link = fmt.Sprintf("%sorg/%s/settings/hooks/%d", setting.AppURL, url.PathEscape(owner.Name), w.ID)
+				} else {
+					f.Link = fmt.Sprintf("%suser/settings/hooks/%d", setting.AppURL, w.ID)
+				}
+			default:
+				f.Link = fmt.Sprintf("%sadmin/hooks/%d", setting.AppURL, w.ID)
+			}
+			findings = append(findings, f)
+			return nil
+		},
+	)
+	return findings, err
+}
+
+func scanAuthSources() ([]*Finding, error) {
+	sources, err := models.LoginSources()
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0, len(sources))
+	for _, source := range sources {
+		var address string
+		switch {
+		case source.IsLDAP() || source.IsDLDAP():
+			if source.UseTLS() {
+				continue
+			}
+			address = fmt.Sprintf("ldap://%s:%d", source.LDAP().Host, source.LDAP().Port)
+		case source.IsSMTP():
+			if source.UseTLS() {
+				continue
+			}
+			address = fmt.Sprintf("smtp://%s:%d", source.SMTP().Host, source.SMTP().Port)
+		case source.IsOAuth2():
+			cfg := source.OAuth2()
+			addresses := []string{cfg.OpenIDConnectAutoDiscoveryURL}
+			if cfg.CustomURLMapping != nil {
+				addresses = append(addresses, cfg.CustomURLMapping.AuthURL, cfg.CustomURLMapping.TokenURL,
+					cfg.CustomURLMapping.ProfileURL, cfg.CustomURLMapping.EmailURL)
+			}
+			for _, addr := range addresses {
+				if IsPlaintextURL(addr) {
+					address = addr
+					break
+				}
+			}
+			if address == "" {
+				continue
+			}
+		default:
+			continue
+		}
+
+		findings = append(findings, &Finding{
+			Kind:   KindAuthSource,
+			Reason: ReasonPlaintext,
+			Name:   source.Name,
+			URL:    sanitizeURL(address),
+			Link:   fmt.Sprintf("%sadmin/auths/%d", setting.AppURL, source.ID),
+		})
+	}
+	return findings, nil
+}
+
+func scanMirrors(ctx context.Context) ([]*Finding, error) {
+	findings := make([]*Finding, 0, 10)
+
+	scan := func(kind Kind, m models.RemoteMirrorer) error {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before scanning the mirrors")
+		default:
+		}
+
+		repo := m.GetRepository()
+		if repo == nil {
+			return nil
+		}
+		u, err := git.GetRemoteAddress(repo.RepoPath(), m.GetRemoteName())
+		if err != nil {
+			log.Warn("Unable to get the remote address of %s of %-v: %v", m.GetRemoteName(), repo, err)
+			return nil
+		}
+		if !IsPlaintextURL(u.String()) {
+			return nil
+		}
+		if err := repo.GetOwner(); err != nil {
+			return err
+		}
+		u.User = nil
+		findings = append(findings, &Finding{
+			Kind:   kind,
+			Reason: ReasonPlaintext,
+			Name:   repo.FullName(),
+			URL:    u.String(),
+			Link:   repo.HTMLURL() + "/settings",
+			Owner:  repo.Owner,
+			Repo:   repo,
+		})
+		return nil
+	}
+
+	if err := models.Iterate(
+		models.DefaultDBContext(),
+		new(models.Mirror),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			return scan(KindMirror, bean.(*models.Mirror))
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := models.Iterate(
+		models.DefaultDBContext(),
+		new(models.PushMirror),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			return scan(KindPushMirror, bean.(*models.PushMirror))
+		},
+	); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// GroupByOwner groups the findings of configurations which have an owner
+func GroupByOwner(findings []*Finding) map[int64][]*Finding {
+	groups := make(map[int64][]*Finding)
+	for _, f := range findings {
+		if f.Owner != nil {
+			groups[f.Owner.ID] = append(groups[f.Owner.ID], f)
+		}
+	}
+	return groups
+}
@@ -93,6 +93,51 @@ func isGitRawOrLFSPath(req *http.Request) bool {
 	return false
 }
 
+var commitStatusPathRe = regexp.MustCompile(`^/api/v1/repos/([^/]+)/[^/]+/(?:statuses/[^/]+|commits/.+/status(?:es)?)$`)
+
+// isCommitStatusRequest returns true if the request reads or creates the commit statuses of a repository owned by ownerName
+func isCommitStatusRequest(req *http.Request, path, ownerName string) bool {
+	if req.Method != "GET" && req.Method != "POST" {
+		return false
+	}
+	m := commitStatusPathRe.FindStringSubmatch(path)
+	if m == nil {
+		return false
+	}
+	// only statuses can be created, the statuses of a ref can only be read
+	if req.Method == "POST" && !strings.Contains(path, "/statuses/") {
+		return false
+	}
+	return ownerName == "" || m[1] == ownerName
+}
+
+// isTokenRequestAllowed returns true if the request is within the scopes of the access token,
+// unrestricted personal access tokens are allowed everything
+func isTokenRequestAllowed(req *http.Request, token *models.AccessToken, ownerName string) bool {
+	if !token.IsRestricted() {
+		return true
+	}
+	path := strings.ToLower(req.URL.Path)
+	ownerName = strings.ToLower(ownerName)
+	if !token.IsOrgToken() {
+		// a personal token may post statuses to any repository its owner has access to
+		return token.HasScope(models.AccessTokenScopeWriteStatus) && isCommitStatusRequest(req, path, "")
+	}
+	if token.HasScope(models.AccessTokenScopeAdminOrg) &&
+		(hasPathPrefix(path, "/api/v1/orgs/"+ownerName) || strings.HasPrefix(path, "/api/v1/teams/")) {
+		return true
+	}
+	if token.HasScope(models.AccessTokenScopeWriteStatus) && isCommitStatusRequest(req, path, ownerName) {
+		return true
+	}
+	return token.HasScope(models.AccessTokenScopeWritePackage) && hasPathPrefix(path, "/api/packages/"+ownerName)
+}
+
+// hasPathPrefix returns true if path is prefix or one of its sub paths
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
 // handleSignIn clears existing session variables and stores new ones for the specified user object
 func handleSignIn(resp http.ResponseWriter, req *http.Request, sess SessionStore, user *models.User) {
 	_ = sess.Delete("openid_verified_uri")
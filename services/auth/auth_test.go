@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
 )
 
@@ -126,3 +127,73 @@ func Test_isGitRawOrLFSPath(t *testing.T) {
 	}
 	setting.LFS.StartServer = origLFSStartServer
 }
+
+func Test_isTokenRequestAllowed(t *testing.T) {
+	personal := &models.AccessToken{UID: 2}
+	adminToken := &models.AccessToken{UID: 3, CreatorID: 2, Scope: string(models.AccessTokenScopeAdminOrg)}
+	packageToken := &models.AccessToken{UID: 3, CreatorID: 2, Scope: string(models.AccessTokenScopeWritePackage)}
+
+	tests := []struct {
+		path  string
+		token *models.AccessToken
+		want  bool
+	}{
+		{"/api/v1/user", personal, true},
+		{"/api/v1/orgs/org3", adminToken, true},
+		{"/api/v1/orgs/Org3/teams", adminToken, true},
+		{"/api/v1/teams/7/members/user2", adminToken, true},
+		{"/api/v1/orgs/org30", adminToken, false},
+		{"/api/v1/orgs/org4", adminToken, false},
+		{"/api/v1/user", adminToken, false},
+		{"/api/v1/repos/org3/repo3", adminToken, false},
+		{"/org3/repo3/git-receive-pack", adminToken, false},
+		{"/api/packages/org3/pypi", adminToken, false},
+		{"/api/packages/org3/pypi", packageToken, true},
+		{"/api/packages/org4/pypi", packageToken, false},
+		{"/api/v1/orgs/org3", packageToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if got := isTokenRequestAllowed(req, tt.token, "org3"); got != tt.want {
+				t.Errorf("isTokenRequestAllowed(%q, %q) = %v, want %v", tt.path, tt.token.Scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isTokenRequestAllowed_status(t *testing.T) {
+	personal := &models.AccessToken{UID: 2}
+	statusToken := &models.AccessToken{UID: 2, Scope: string(models.AccessTokenScopeWriteStatus)}
+	orgStatusToken := &models.AccessToken{UID: 3, CreatorID: 2, Scope: string(models.AccessTokenScopeWriteStatus)}
+
+	tests := []struct {
+		method string
+		path   string
+		token  *models.AccessToken
+		want   bool
+	}{
+		{"GET", "/api/v1/repos/org3/repo3/contents/README.md", personal, true},
+		{"POST", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d", statusToken, true},
+		{"GET", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d", statusToken, true},
+		{"GET", "/api/v1/repos/org3/repo3/commits/feature/branch/status", statusToken, true},
+		{"GET", "/api/v1/repos/org3/repo3/commits/master/statuses", statusToken, true},
+		{"POST", "/api/v1/repos/org3/repo3/commits/master/statuses", statusToken, false},
+		{"DELETE", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d", statusToken, false},
+		{"GET", "/api/v1/repos/user2/repo1/contents/README.md", statusToken, false},
+		{"GET", "/api/v1/repos/user2/repo1/raw/README.md", statusToken, false},
+		{"GET", "/user2/repo1/info/refs", statusToken, false},
+		{"GET", "/api/v1/user", statusToken, false},
+		{"POST", "/api/v1/repos/org3/repo3/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d", orgStatusToken, true},
+		{"POST", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d", orgStatusToken, false},
+		{"GET", "/api/v1/orgs/org3", orgStatusToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if got := isTokenRequestAllowed(req, tt.token, "org3"); got != tt.want {
+				t.Errorf("isTokenRequestAllowed(%s %q, %q) = %v, want %v", tt.method, tt.path, tt.token.Scope, got, tt.want)
+			}
+		})
+	}
+}
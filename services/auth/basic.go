@@ -99,6 +99,10 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 			log.Error("GetUserByID:  %v", err)
 			return nil
 		}
+		if !isTokenRequestAllowed(req, token, u.Name) {
+			log.Trace("Basic Authorization: AccessToken[%d] of %s is not allowed for %s", token.ID, u.Name, req.URL.Path)
+			return nil
+		}
 
 		token.UpdatedUnix = timeutil.TimeStampNow()
 		if err = models.UpdateAccessToken(token); err != nil {
@@ -106,6 +110,7 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 		}
 
 		store.GetData()["IsApiToken"] = true
+		store.GetData()["ApiTokenID"] = token.ID
 		return u
 	} else if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
 		log.Error("GetAccessTokenBySha: %v", err)
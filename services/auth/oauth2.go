@@ -104,11 +104,22 @@ func (o *OAuth2) userIDFromToken(req *http.Request, store DataStore) int64 {
 		}
 		return 0
 	}
+	if t.IsRestricted() {
+		owner, err := models.GetUserByID(t.UID)
+		if err != nil {
+			log.Error("GetUserByID: %v", err)
+			return 0
+		}
+		if !isTokenRequestAllowed(req, t, owner.Name) {
+			return 0
+		}
+	}
 	t.UpdatedUnix = timeutil.TimeStampNow()
 	if err = models.UpdateAccessToken(t); err != nil {
 		log.Error("UpdateAccessToken: %v", err)
 	}
 	store.GetData()["IsApiToken"] = true
+	store.GetData()["ApiTokenID"] = t.ID
 	return t.UID
 }
 
@@ -0,0 +1,164 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package automerge
+
+import (
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// prAutoMergeQueue represents a queue to merge the pull requests scheduled to be merged when their checks succeed
+var prAutoMergeQueue queue.UniqueQueue
+
+// Init creates and runs the auto merge queue and listens to reviews
+func Init() error {
+	prAutoMergeQueue = queue.CreateUniqueQueue("pr_auto_merge", handle, "").(queue.UniqueQueue)
+	if prAutoMergeQueue == nil {
+		return fmt.Errorf("Unable to create pr_auto_merge Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(prAutoMergeQueue.Run)
+
+	notification.RegisterNotifier(&autoMergeNotifier{})
+	return nil
+}
+
+// handle merges the pull requests of the passed IDs if they are scheduled and ready to be merged
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		id, _ := strconv.ParseInt(datum.(string), 10, 64)
+		if err := handlePull(id); err != nil {
+			log.Error("pr_auto_merge handlePull[%d]: %v", id, err)
+		}
+	}
+}
+
+// addToQueue adds the pull request to the auto merge queue
+func addToQueue(pr *models.PullRequest) {
+	if err := prAutoMergeQueue.Push(strconv.FormatInt(pr.ID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding pull request %d to the auto merge queue: %v", pr.ID, err)
+	}
+}
+
+// StartPRCheckAndAutoMerge retests the mergeability of the pull request if it is scheduled to be merged
+func StartPRCheckAndAutoMerge(pr *models.PullRequest) {
+	if pr == nil || pr.HasMerged {
+		return
+	}
+	scheduled, err := models.GetScheduledMergeByPullID(pr.ID)
+	if err != nil {
+		log.Error("GetScheduledMergeByPullID[%d]: %v", pr.ID, err)
+		return
+	} else if scheduled == nil {
+		return
+	}
+	addToQueue(pr)
+}
+
+// StartPRCheckAndAutoMergeBySHA retests the mergeability of the pull requests into or from the repository
+// which are scheduled to be merged and whose head is the commit
+func StartPRCheckAndAutoMergeBySHA(sha string, repo *models.Repository) error {
+	scheduled, err := models.GetScheduledMergesByRepoID(repo.ID)
+	if err != nil || len(scheduled) == 0 {
+		return err
+	}
+
+	for _, s := range scheduled {
+		pr, err := models.GetPullRequestByID(s.PullID)
+		if err != nil {
+			return err
+		}
+		if err := pr.LoadBaseRepo(); err != nil {
+			return err
+		}
+		headCommitID, err := git.GetFullCommitID(pr.BaseRepo.RepoPath(), pr.GetGitRefName())
+		if err != nil {
+			log.Error("Unable to get the head commit of pull request %d: %v", pr.ID, err)
+			continue
+		}
+		if headCommitID == sha {
+			addToQueue(pr)
+		}
+	}
+	return nil
+}
+
+// handlePull merges the pull request on behalf of the user who scheduled its merge if it is ready to be merged
+func handlePull(pullID int64) error {
+	scheduled, err := models.GetScheduledMergeByPullID(pullID)
+	if err != nil || scheduled == nil {
+		return err
+	}
+
+	pr, err := models.GetPullRequestByID(pullID)
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			return models.RemoveScheduledAutoMerge(nil, &models.PullRequest{ID: pullID})
+		}
+		return err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return models.RemoveScheduledAutoMerge(nil, pr)
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	pr.Issue.Repo = pr.BaseRepo
+
+	if err := scheduled.LoadDoer(); err != nil {
+		if models.IsErrUserNotExist(err) {
+			return models.RemoveScheduledAutoMerge(nil, pr)
+		}
+		return err
+	}
+	doer := scheduled.Doer
+
+	perm, err := models.GetUserRepoPermission(pr.BaseRepo, doer)
+	if err != nil {
+		return err
+	}
+	if allowed, err := pull_service.IsUserAllowedToMerge(pr, perm, doer); err != nil {
+		return err
+	} else if !allowed {
+		log.Info("%-v is no longer allowed to merge pull request %d, canceling its scheduled merge", doer, pr.ID)
+		return models.RemoveScheduledAutoMerge(doer, pr)
+	}
+
+	if !pull_service.CanMergeWithStyle(pr, scheduled.MergeStyle) || pr.IsWorkInProgress() {
+		return nil
+	}
+	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
+		if models.IsErrNotAllowedToMerge(err) {
+			log.Trace("Pull request %d is not ready to be merged yet: %v", pr.ID, err)
+			return nil
+		}
+		return err
+	}
+	if noDeps, err := models.IssueNoDependenciesLeft(pr.Issue); err != nil || !noDeps {
+		return err
+	}
+
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer baseGitRepo.Close()
+
+	if err := pull_service.Merge(pr, doer, baseGitRepo, scheduled.MergeStyle, scheduled.Message); err != nil {
+		return err
+	}
+	log.Trace("Pull request %d merged when its checks succeeded", pr.ID)
+	return models.RemoveScheduledAutoMerge(nil, pr)
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package automerge
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type autoMergeNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &autoMergeNotifier{}
+)
+
+// NotifyPullRequestReview retests the mergeability of the pull request when it is approved
+func (*autoMergeNotifier) NotifyPullRequestReview(pr *models.PullRequest, review *models.Review, comment *models.Comment, mentions []*models.User) {
+	if review.Type == models.ReviewTypeApprove {
+		StartPRCheckAndAutoMerge(pr)
+	}
+}
@@ -7,10 +7,22 @@ package comments
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/services/antispam"
 )
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *models.User, repo *models.Repository, issue *models.Issue, content string, attachments []string) (*models.Comment, error) {
+	classified := &antispam.Content{
+		Kind: antispam.KindComment,
+		Doer: doer,
+		Repo: repo,
+		Text: content,
+	}
+	flagged, err := antispam.Check(classified)
+	if err != nil {
+		return nil, err
+	}
+
 	comment, err := models.CreateComment(&models.CreateCommentOptions{
 		Type:        models.CommentTypeComment,
 		Doer:        doer,
@@ -27,17 +39,41 @@ func CreateIssueComment(doer *models.User, repo *models.Repository, issue *model
 		return nil, err
 	}
 	notification.NotifyCreateIssueComment(doer, repo, issue, comment, mentions)
+	if flagged {
+		antispam.Flag(classified, comment.HTMLURL())
+	}
 
 	return comment, nil
 }
 
 // UpdateComment updates information of comment.
 func UpdateComment(c *models.Comment, doer *models.User, oldContent string) error {
+	if err := c.LoadIssue(); err != nil {
+		return err
+	}
+	if err := c.Issue.LoadRepo(); err != nil {
+		return err
+	}
+	classified := &antispam.Content{
+		Kind:   antispam.KindComment,
+		IsEdit: true,
+		Doer:   doer,
+		Repo:   c.Issue.Repo,
+		Text:   c.Content,
+	}
+	flagged, err := antispam.Check(classified)
+	if err != nil {
+		return err
+	}
+
 	if err := models.UpdateComment(c, doer); err != nil {
 		return err
 	}
 
 	notification.NotifyUpdateComment(doer, c, oldContent)
+	if flagged {
+		antispam.Flag(classified, c.HTMLURL())
+	}
 
 	return nil
 }
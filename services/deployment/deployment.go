@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deployment
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// ErrPullRequestClosed is returned when an environment is deployed for a closed or merged pull request
+var ErrPullRequestClosed = errors.New("pull request is closed")
+
+// Init listens to closed and merged pull requests to tear their environments down
+func Init() {
+	notification.RegisterNotifier(&deploymentNotifier{})
+}
+
+// CreateDeployment registers the environment deployed for the pull request at the URL,
+// replacing the previous deployment of the environment, and notifies it to webhooks.
+// The deployed commit defaults to the head commit of the pull request.
+func CreateDeployment(doer *models.User, pr *models.PullRequest, environment, url, sha string) (*models.Deployment, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return nil, err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil, ErrPullRequestClosed
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+
+	if len(sha) == 0 {
+		var err error
+		if sha, err = git.GetFullCommitID(pr.BaseRepo.RepoPath(), pr.GetGitRefName()); err != nil {
+			return nil, err
+		}
+	}
+
+	deployment := &models.Deployment{
+		RepoID:      pr.BaseRepoID,
+		PullID:      pr.ID,
+		Environment: environment,
+		URL:         url,
+		SHA:         sha,
+		CreatorID:   doer.ID,
+		Creator:     doer,
+	}
+	if _, err := models.CreateOrUpdateDeployment(deployment); err != nil {
+		return nil, err
+	}
+
+	notification.NotifyCreateDeployment(doer, pr, deployment)
+	return deployment, nil
+}
+
+// TearDownDeployments marks the environments deployed for the pull request as torn down
+// and notifies webhooks so they can be removed
+func TearDownDeployments(doer *models.User, pr *models.PullRequest) error {
+	deployments, err := models.TearDownDeployments(pr)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		notification.NotifyTearDownDeployment(doer, pr, deployment)
+	}
+	return nil
+}
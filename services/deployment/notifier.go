@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deployment
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type deploymentNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &deploymentNotifier{}
+)
+
+func (*deploymentNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
+	if !issue.IsPull || !isClosed {
+		return
+	}
+	if err := issue.LoadPullRequest(); err != nil {
+		log.Error("LoadPullRequest[%d]: %v", issue.ID, err)
+		return
+	}
+	if err := TearDownDeployments(doer, issue.PullRequest); err != nil {
+		log.Error("TearDownDeployments[%d]: %v", issue.PullRequest.ID, err)
+	}
+}
+
+func (*deploymentNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := TearDownDeployments(doer, pr); err != nil {
+		log.Error("TearDownDeployments[%d]: %v", pr.ID, err)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package diskusage
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// Usage represents the disk space used by the repositories of an owner or by a repository
+type Usage struct {
+	Owner    *models.User
+	Repo     *models.Repository
+	Size     int64
+	NumRepos int64
+	// Growth of the size since the first sample of the period, only valid if HasGrowth is true
+	Growth    int64
+	HasGrowth bool
+}
+
+// FormattedGrowth returns the growth of the size in a human readable form with its sign
+func (u *Usage) FormattedGrowth() string {
+	return formatGrowth(u.Growth)
+}
+
+// TrendPoint represents the total size of repositories when the disk usage was sampled
+type TrendPoint struct {
+	SampledUnix timeutil.TimeStamp
+	Size        int64
+	// Growth of the size since the previous sample, only valid if HasGrowth is true
+	Growth    int64
+	HasGrowth bool
+}
+
+// FormattedGrowth returns the growth of the size in a human readable form with its sign
+func (p *TrendPoint) FormattedGrowth() string {
+	return formatGrowth(p.Growth)
+}
+
+func formatGrowth(growth int64) string {
+	if growth < 0 {
+		return "-" + base.FileSize(-growth)
+	}
+	return "+" + base.FileSize(growth)
+}
+
+// SampleDiskUsage records the size of every repository, alerts about the owners whose repositories
+// grew above the alert threshold since the last sample and deletes the samples older than the passed duration
+func SampleDiskUsage(ctx context.Context, olderThan time.Duration) error {
+	lastSampledUnix, err := models.GetLastRepoSizeSampleTime()
+	if err != nil {
+		return err
+	}
+	sampledUnix := timeutil.TimeStampNow()
+	if sampledUnix <= lastSampledUnix {
+		sampledUnix = lastSampledUnix + 1
+	}
+	if err := models.SampleRepoSizes(ctx, sampledUnix); err != nil {
+		return err
+	}
+
+	if setting.Repository.DiskUsageAlertThreshold > 0 {
+		if err := alertOwners(lastSampledUnix, sampledUnix); err != nil {
+			return err
+		}
+	}
+	return models.DeleteOldRepoSizeSamples(ctx, olderThan)
+}
+
+// alertOwners alerts the administrators and the owners whose repositories grew above the alert threshold between two samples
+func alertOwners(lastSampledUnix, sampledUnix timeutil.TimeStamp) error {
+	threshold := setting.Repository.DiskUsageAlertThreshold * 1024 * 1024
+
+	lastSizes := map[int64]int64{}
+	if lastSampledUnix > 0 {
+		var err error
+		if lastSizes, err = models.GetSampledOwnerSizes(lastSampledUnix); err != nil {
+			return err
+		}
+	}
+	sizes, err := models.GetSampledOwnerSizes(sampledUnix)
+	if err != nil {
+		return err
+	}
+
+	for ownerID, size := range sizes {
+		if size < threshold || lastSizes[ownerID] >= threshold {
+			continue
+		}
+		owner, err := models.GetUserByID(ownerID)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		log.Info("The repositories of %s use %s, above the disk usage alert threshold", owner.Name, base.FileSize(size))
+		if err := models.CreateNotice(models.NoticeRepository, "The repositories of %s use %s, above the disk usage alert threshold of %s",
+			owner.Name, base.FileSize(size), base.FileSize(threshold)); err != nil {
+			return err
+		}
+		if setting.MailService != nil {
+			if err := mailer.SendDiskUsageAlertMail(owner, size, threshold); err != nil {
+				log.Error("SendDiskUsageAlertMail[%d]: %v", owner.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TopOwners returns the owners whose repositories use the most disk space with their growth since the passed time
+func TopOwners(limit int, since timeutil.TimeStamp) ([]*Usage, error) {
+	ownerUsages, err := models.GetTopOwnersByDiskUsage(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	firstSampledUnix, err := models.GetFirstRepoSizeSampleTimeSince(since)
+	if err != nil {
+		return nil, err
+	}
+	firstSizes := map[int64]int64{}
+	if firstSampledUnix > 0 {
+		if firstSizes, err = models.GetSampledOwnerSizes(firstSampledUnix); err != nil {
+			return nil, err
+		}
+	}
+
+	usages := make([]*Usage, 0, len(ownerUsages))
+	for _, ownerUsage := range ownerUsages {
+		usage := &Usage{
+			Owner:    ownerUsage.Owner,
+			Size:     ownerUsage.Size,
+			NumRepos: ownerUsage.NumRepos,
+		}
+		if firstSize, ok := firstSizes[ownerUsage.OwnerID]; ok {
+			usage.Growth = ownerUsage.Size - firstSize
+			usage.HasGrowth = true
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// TopRepos returns the repositories of the owner, of all owners if 0, using the most disk space
+// with their growth since the passed time
+func TopRepos(ownerID int64, limit int, since timeutil.TimeStamp) ([]*Usage, error) {
+	repos, err := models.GetTopReposBySize(ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	firstSampledUnix, err := models.GetFirstRepoSizeSampleTimeSince(since)
+	if err != nil {
+		return nil, err
+	}
+	firstSizes := map[int64]int64{}
+	if firstSampledUnix > 0 && len(repos) > 0 {
+		repoIDs := make([]int64, 0, len(repos))
+		for _, repo := range repos {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+		if firstSizes, err = models.GetSampledRepoSizes(firstSampledUnix, repoIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	usages := make([]*Usage, 0, len(repos))
+	for _, repo := range repos {
+		usage := &Usage{
+			Owner: repo.Owner,
+			Repo:  repo,
+			Size:  repo.Size,
+		}
+		if firstSize, ok := firstSizes[repo.ID]; ok {
+			usage.Growth = repo.Size - firstSize
+			usage.HasGrowth = true
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// Trend returns the total size of the repositories of the owner, of all the repositories if 0,
+// each time the disk usage was sampled since the passed time
+func Trend(ownerID int64, since timeutil.TimeStamp) ([]*TrendPoint, error) {
+	samples, err := models.GetDiskUsageTrend(ownerID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*TrendPoint, 0, len(samples))
+	for i, sample := range samples {
+		point := &TrendPoint{
+			SampledUnix: sample.SampledUnix,
+			Size:        sample.Size,
+		}
+		if i > 0 {
+			point.Growth = sample.Size - samples[i-1].Size
+			point.HasGrowth = true
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package diskusage
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleDiskUsage(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	defer func(threshold int64) { setting.Repository.DiskUsageAlertThreshold = threshold }(setting.Repository.DiskUsageAlertThreshold)
+	setting.Repository.DiskUsageAlertThreshold = 1
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	repo.Size = 512 * 1024
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "size"))
+	numNotices := models.CountNotices()
+
+	assert.NoError(t, SampleDiskUsage(context.Background(), 0))
+	assert.Equal(t, numNotices, models.CountNotices())
+
+	// The owner crosses the threshold
+	repo.Size = 2 * 1024 * 1024
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "size"))
+	assert.NoError(t, SampleDiskUsage(context.Background(), 0))
+	assert.Equal(t, numNotices+1, models.CountNotices())
+
+	// The owner is only alerted again after its usage went below the threshold
+	assert.NoError(t, SampleDiskUsage(context.Background(), 0))
+	assert.Equal(t, numNotices+1, models.CountNotices())
+
+	trend, err := Trend(repo.OwnerID, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, trend, 3) {
+		assert.False(t, trend[0].HasGrowth)
+		assert.True(t, trend[1].HasGrowth)
+		assert.EqualValues(t, 3*512*1024, trend[1].Growth)
+		assert.Equal(t, "+1.5 MiB", trend[1].FormattedGrowth())
+		assert.Equal(t, "+0 B", trend[2].FormattedGrowth())
+	}
+
+	owners, err := TopOwners(1, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, owners, 1) {
+		assert.EqualValues(t, repo.OwnerID, owners[0].Owner.ID)
+		assert.True(t, owners[0].HasGrowth)
+		assert.EqualValues(t, 3*512*1024, owners[0].Growth)
+	}
+
+	repos, err := TopRepos(0, 1, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, repo.ID, repos[0].Repo.ID)
+		assert.EqualValues(t, 3*512*1024, repos[0].Growth)
+	}
+}
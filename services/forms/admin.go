@@ -43,6 +43,7 @@ type AdminEditUserForm struct {
 	Website                 string `binding:"ValidUrl;MaxSize(255)"`
 	Location                string `binding:"MaxSize(50)"`
 	MaxRepoCreation         int
+	SizeQuota               int64
 	Active                  bool
 	Admin                   bool
 	Restricted              bool
@@ -71,3 +72,16 @@ func (f *AdminDashboardForm) Validate(req *http.Request, errs binding.Errors) bi
 	ctx := context.GetContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// AdminInstanceTemplateForm form for adding a repository to the template catalog
+type AdminInstanceTemplateForm struct {
+	RepoName    string `binding:"Required"`
+	Category    string `binding:"Required;MaxSize(50)"`
+	Description string `binding:"MaxSize(255)"`
+}
+
+// Validate validates form fields
+func (f *AdminInstanceTemplateForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
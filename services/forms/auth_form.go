@@ -62,6 +62,11 @@ type AuthenticationForm struct {
 	Oauth2ProfileURL              string
 	Oauth2EmailURL                string
 	Oauth2IconURL                 string
+	Oauth2AllowedEmailDomains     string
+	Oauth2AllowedAccounts         string
+	Oauth2GroupClaimName          string
+	Oauth2RequiredGroup           string
+	Oauth2RequireApproval         bool
 	SSPIAutoCreateUsers           bool
 	SSPIAutoActivateUsers         bool
 	SSPIStripDomainNames          bool
@@ -45,7 +45,9 @@ type UpdateOrgSettingForm struct {
 	Location                  string `binding:"MaxSize(50)"`
 	Visibility                structs.VisibleType
 	MaxRepoCreation           int
+	SizeQuota                 int64
 	RepoAdminChangeTeamAccess bool
+	RequireTwoFactor          bool
 }
 
 // Validate validates the fields
@@ -54,6 +56,44 @@ func (f *UpdateOrgSettingForm) Validate(req *http.Request, errs binding.Errors)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// NewOrgAccessTokenForm form for creating an access token of an organization
+type NewOrgAccessTokenForm struct {
+	Name   string `binding:"Required;MaxSize(255)"`
+	Scopes []string
+	// Expires is a date formatted as 2006-01-02, the token never expires if it is empty
+	Expires string
+}
+
+// Validate validates the fields
+func (f *NewOrgAccessTokenForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// OrgCLAForm form for updating the contributor license agreement of an organization
+type OrgCLAForm struct {
+	Content string
+	Enforce bool
+}
+
+// Validate validates the fields
+func (f *OrgCLAForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// SignCLAForm form for signing the contributor license agreement of an organization
+type SignCLAForm struct {
+	FullName string `binding:"Required;MaxSize(255)"`
+	Agree    bool
+}
+
+// Validate validates the fields
+func (f *SignCLAForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ___________
 // \__    ___/___ _____    _____
 //   |    |_/ __ \\__  \  /     \
@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package forms
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// RepoAccessRequestForm form for requesting access to a repository
+type RepoAccessRequestForm struct {
+	Message string `binding:"MaxSize(1000)" locale:"repo.access_request.message"`
+}
+
+// Validate validates the fields
+func (f *RepoAccessRequestForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ApproveRepoAccessRequestForm form for approving a request to access a repository
+type ApproveRepoAccessRequestForm struct {
+	Permission string `binding:"Required;In(read,write,admin)"`
+}
+
+// Validate validates the fields
+func (f *ApproveRepoAccessRequestForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -48,6 +48,7 @@ type CreateRepoForm struct {
 	Webhooks     bool
 	Avatar       bool
 	Labels       bool
+	Units        bool
 	TrustModel   string
 }
 
@@ -116,12 +117,15 @@ type RepoSettingForm struct {
 	RepoName           string `binding:"Required;AlphaDashDot;MaxSize(100)"`
 	Description        string `binding:"MaxSize(255)"`
 	Website            string `binding:"ValidUrl;MaxSize(255)"`
+	AccentColor        string `binding:"MaxSize(7)"`
 	Interval           string
 	MirrorAddress      string
 	MirrorUsername     string
 	MirrorPassword     string
 	LFS                bool   `form:"mirror_lfs"`
 	LFSEndpoint        string `form:"mirror_lfs_endpoint"`
+	MirrorMaxBandwidth string
+	MirrorSyncWindows  string
 	PushMirrorID       string
 	PushMirrorAddress  string
 	PushMirrorUsername string
@@ -140,6 +144,7 @@ type RepoSettingForm struct {
 	ExternalTrackerURL                    string
 	TrackerURLFormat                      string
 	TrackerIssueStyle                     string
+	ExternalTrackerRegexpPattern          string
 	EnableCloseIssuesViaCommitInAnyBranch bool
 	EnableProjects                        bool
 	EnablePulls                           bool
@@ -152,11 +157,17 @@ type RepoSettingForm struct {
 	PullsDefaultMergeStyle                string
 	EnableAutodetectManualMerge           bool
 	DefaultDeleteBranchAfterMerge         bool
+	WelcomeFirstTimeContributors          bool
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
+	EnableIssueVoting                     bool
 	IsArchived                            bool
 
+	// Home page settings
+	HomeReadmePath string `binding:"MaxSize(255)"`
+	HomeShowWiki   bool
+
 	// Signing Settings
 	TrustModel string
 
@@ -196,6 +207,7 @@ type ProtectBranchForm struct {
 	BlockOnRejectedReviews        bool
 	BlockOnOfficialReviewRequests bool
 	BlockOnOutdatedBranch         bool
+	RequireCodeOwnerApproval      bool
 	DismissStaleApprovals         bool
 	RequireSignedCommits          bool
 	ProtectedFilePatterns         string
@@ -234,9 +246,13 @@ type WebhookForm struct {
 	PullRequestComment   bool
 	PullRequestReview    bool
 	PullRequestSync      bool
+	Deployment           bool
 	Repository           bool
+	RepositorySettings   bool
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
+	Secret               string
+	SignatureHeader      string `binding:"AlphaDash;MaxSize(255)"`
 }
 
 // PushOnly if the hook will be triggered when push
@@ -256,10 +272,10 @@ func (f WebhookForm) ChooseEvents() bool {
 
 // NewWebhookForm form for creating web hook
 type NewWebhookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	HTTPMethod  string `binding:"Required;In(POST,GET)"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL     string `binding:"Required;ValidUrl"`
+	HTTPMethod     string `binding:"Required;In(POST,GET)"`
+	ContentType    int    `binding:"Required"`
+	PayloadVersion int
 	WebhookForm
 }
 
@@ -271,9 +287,9 @@ func (f *NewWebhookForm) Validate(req *http.Request, errs binding.Errors) bindin
 
 // NewGogshookForm form for creating gogs hook
 type NewGogshookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL     string `binding:"Required;ValidUrl"`
+	ContentType    int    `binding:"Required"`
+	PayloadVersion int
 	WebhookForm
 }
 
@@ -484,8 +500,9 @@ type UserCreateProjectForm struct {
 
 // EditProjectBoardForm is a form for editing a project board
 type EditProjectBoardForm struct {
-	Title   string `binding:"Required;MaxSize(100)"`
-	Sorting int8
+	Title    string `binding:"Required;MaxSize(100)"`
+	Sorting  int8
+	AutoMove *string `json:"auto_move"`
 }
 
 //    _____  .__.__                   __
@@ -558,6 +575,7 @@ type MergePullRequestForm struct {
 	MergeCommitID          string // only used for manually-merged
 	ForceMerge             *bool  `json:"force_merge,omitempty"`
 	DeleteBranchAfterMerge bool   `json:"delete_branch_after_merge,omitempty"`
+	MergeWhenChecksSucceed bool   `json:"merge_when_checks_succeed,omitempty"`
 }
 
 // Validate validates the fields
@@ -586,10 +604,11 @@ func (f *CodeCommentForm) Validate(req *http.Request, errs binding.Errors) bindi
 
 // SubmitReviewForm for submitting a finished code review
 type SubmitReviewForm struct {
-	Content  string
-	Type     string `binding:"Required;In(approve,comment,reject)"`
-	CommitID string
-	Files    []string
+	Content   string
+	Type      string `binding:"Required;In(approve,comment,reject)"`
+	CommitID  string
+	Checklist []string
+	Files     []string
 }
 
 // Validate validates the fields
@@ -0,0 +1,27 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package forms
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// RepoPagesForm form for the settings of the pages of a repository
+type RepoPagesForm struct {
+	Branch string `binding:"Required;MaxSize(255)"`
+	Folder string `binding:"MaxSize(255)"`
+	Domain string `binding:"MaxSize(255)"`
+}
+
+// Validate validates the fields
+func (f *RepoPagesForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package forms
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// RepoSnapshotForm form for the settings of the scheduled snapshots of a repository
+type RepoSnapshotForm struct {
+	Branch           string `binding:"Required;MaxSize(255)" locale:"repo.settings.snapshots.branch"`
+	TagPrefix        string `binding:"Required;GitRefName;MaxSize(100)" locale:"repo.settings.snapshots.tag_prefix"`
+	Retention        int    `binding:"Range(0,1000)" locale:"repo.settings.snapshots.retention"`
+	GenerateArchives bool
+}
+
+// Validate validates the fields
+func (f *RepoSnapshotForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package forms
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// IssueTriageRuleForm form for a triage rule of the issues of a repository
+type IssueTriageRuleForm struct {
+	Condition string `binding:"Required;In(title_matches,missing_sections,author_is_member)" locale:"repo.settings.triage.condition"`
+	Pattern   string `binding:"MaxSize(255)" locale:"repo.settings.triage.pattern"`
+	Sections  string `binding:"MaxSize(2048)" locale:"repo.settings.triage.sections"`
+	LabelID   int64
+	TeamID    int64
+	Comment   string `binding:"MaxSize(65535)" locale:"repo.settings.triage.comment"`
+}
+
+// Validate validates the fields
+func (f *IssueTriageRuleForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -207,6 +207,9 @@ type AccessTokenForm struct {
 
 	// PKCE support
 	CodeVerifier string `json:"code_verifier"`
+
+	// device authorization grant support
+	DeviceCode string `json:"device_code"`
 }
 
 // Validate validates the fields
@@ -215,6 +218,31 @@ func (f *AccessTokenForm) Validate(req *http.Request, errs binding.Errors) bindi
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// DeviceAuthorizationForm for requesting a device code and a user code of the device authorization grant
+type DeviceAuthorizationForm struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Scope        string `json:"scope"`
+}
+
+// Validate validates the fields
+func (f *DeviceAuthorizationForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// DeviceGrantForm form for granting or denying access to a device with its user code
+type DeviceGrantForm struct {
+	UserCode string `binding:"Required"`
+	Granted  bool
+}
+
+// Validate validates the fields
+func (f *DeviceGrantForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //   __________________________________________.___ _______    ________  _________
 //  /   _____/\_   _____/\__    ___/\__    ___/|   |\      \  /  _____/ /   _____/
 //  \_____  \  |    __)_   |    |     |    |   |   |/   |   \/   \  ___ \_____  \
@@ -224,15 +252,16 @@ func (f *AccessTokenForm) Validate(req *http.Request, errs binding.Errors) bindi
 
 // UpdateProfileForm form for updating profile
 type UpdateProfileForm struct {
-	Name                string `binding:"AlphaDashDot;MaxSize(40)"`
-	FullName            string `binding:"MaxSize(100)"`
-	KeepEmailPrivate    bool
-	Website             string `binding:"ValidSiteUrl;MaxSize(255)"`
-	Location            string `binding:"MaxSize(50)"`
-	Language            string
-	Description         string `binding:"MaxSize(255)"`
-	Visibility          structs.VisibleType
-	KeepActivityPrivate bool
+	Name                     string `binding:"AlphaDashDot;MaxSize(40)"`
+	FullName                 string `binding:"MaxSize(100)"`
+	KeepEmailPrivate         bool
+	Website                  string `binding:"ValidSiteUrl;MaxSize(255)"`
+	Location                 string `binding:"MaxSize(50)"`
+	Language                 string
+	Description              string `binding:"MaxSize(255)"`
+	Visibility               structs.VisibleType
+	KeepActivityPrivate      bool
+	KeepActivitySignedInOnly bool
 }
 
 // Validate validates the fields
@@ -321,6 +350,44 @@ func (f *AddOpenIDForm) Validate(req *http.Request, errs binding.Errors) binding
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// CreateStarListForm form for creating a star list
+type CreateStarListForm struct {
+	Name        string `binding:"Required;MaxSize(50)"`
+	Description string `binding:"MaxSize(255)"`
+	Private     bool
+}
+
+// Validate validates the fields
+func (f *CreateStarListForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// CreateSnippetForm form for creating a snippet
+type CreateSnippetForm struct {
+	Description string `binding:"MaxSize(255)"`
+	Visibility  string `binding:"In(public,unlisted,private)"`
+	FileName    string `binding:"Required;MaxSize(255)"`
+	Content     string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *CreateSnippetForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// CreateSnippetCommentForm form for commenting on a snippet
+type CreateSnippetCommentForm struct {
+	Content string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *CreateSnippetCommentForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // AddKeyForm form for adding SSH/GPG key
 type AddKeyForm struct {
 	Type       string `binding:"OmitEmpty"`
@@ -329,6 +396,8 @@ type AddKeyForm struct {
 	Signature  string `binding:"OmitEmpty"`
 	KeyID      string `binding:"OmitEmpty"`
 	IsWritable bool
+	// Expires is a date formatted as 2006-01-02, the SSH key never expires if it is empty
+	Expires string
 }
 
 // Validate validates the fields
@@ -340,6 +409,8 @@ func (f *AddKeyForm) Validate(req *http.Request, errs binding.Errors) binding.Er
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
 	Name string `binding:"Required;MaxSize(255)"`
+	// StatusOnly restricts the token to create and read commit statuses
+	StatusOnly bool
 }
 
 // Validate validates the fields
@@ -350,8 +421,10 @@ func (f *NewAccessTokenForm) Validate(req *http.Request, errs binding.Errors) bi
 
 // EditOAuth2ApplicationForm form for editing oauth2 applications
 type EditOAuth2ApplicationForm struct {
-	Name        string `binding:"Required;MaxSize(255)" form:"application_name"`
-	RedirectURI string `binding:"Required" form:"redirect_uri"`
+	Name               string `binding:"Required;MaxSize(255)" form:"application_name"`
+	RedirectURI        string `binding:"Required" form:"redirect_uri"`
+	ConfidentialClient bool   `form:"confidential_client"`
+	EnableDeviceFlow   bool   `form:"enable_device_flow"`
 }
 
 // Validate validates the fields
@@ -0,0 +1,132 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"strings"
+)
+
+// SearchContextLines is the number of lines shown around a matched line of a diff search
+const SearchContextLines = 3
+
+// SearchMaxFiles is the maximum number of files returned by a diff search
+const SearchMaxFiles = 50
+
+// DiffSearchResult represents a file of a diff matching a search
+type DiffSearchResult struct {
+	Name        string            `json:"name"`
+	Index       int               `json:"index"`
+	NameMatched bool              `json:"name_matched"`
+	Hunks       []*DiffSearchHunk `json:"hunks"`
+}
+
+// DiffSearchHunk represents the matched lines of a section of a diff file with their context
+type DiffSearchHunk struct {
+	Header string            `json:"header"`
+	Lines  []*DiffSearchLine `json:"lines"`
+}
+
+// DiffSearchLine represents a line of a DiffSearchHunk
+type DiffSearchLine struct {
+	LeftIdx  int    `json:"left_idx"`
+	RightIdx int    `json:"right_idx"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	Matched  bool   `json:"matched"`
+}
+
+var diffSearchLineTypes = map[DiffLineType]string{
+	DiffLinePlain: "same",
+	DiffLineAdd:   "add",
+	DiffLineDel:   "del",
+}
+
+// Search returns the files of the diff whose name or changed content contains keyword, case insensitively,
+// the matched lines are returned with contextLines lines around them within their section.
+// At most limit files are returned, and whether there were more.
+func (diff *Diff) Search(keyword string, contextLines, limit int) (results []*DiffSearchResult, hasMore bool) {
+	keyword = strings.ToLower(keyword)
+	if keyword == "" {
+		return nil, false
+	}
+
+	for _, file := range diff.Files {
+		result := &DiffSearchResult{
+			Name:        file.Name,
+			Index:       file.Index,
+			NameMatched: strings.Contains(strings.ToLower(file.Name), keyword) || strings.Contains(strings.ToLower(file.OldName), keyword),
+		}
+		for _, section := range file.Sections {
+			if hunk := searchSection(section, keyword, contextLines); hunk != nil {
+				result.Hunks = append(result.Hunks, hunk)
+			}
+		}
+		if !result.NameMatched && len(result.Hunks) == 0 {
+			continue
+		}
+		if len(results) >= limit {
+			return results, true
+		}
+		results = append(results, result)
+	}
+	return results, false
+}
+
+func searchSection(section *DiffSection, keyword string, contextLines int) *DiffSearchHunk {
+	var header string
+	matched := make([]bool, len(section.Lines))
+	hasMatch := false
+	for i, line := range section.Lines {
+		if line.Type == DiffLineSection {
+			header = line.Content
+			continue
+		}
+		if strings.Contains(strings.ToLower(lineContent(line)), keyword) {
+			matched[i] = true
+			hasMatch = true
+		}
+	}
+	if !hasMatch {
+		return nil
+	}
+
+	hunk := &DiffSearchHunk{Header: header}
+	lastIncluded := -1
+	for i, line := range section.Lines {
+		if line.Type == DiffLineSection || !nearMatch(matched, i, contextLines) {
+			continue
+		}
+		if lastIncluded >= 0 && i > lastIncluded+1 {
+			// mark the skipped lines between two matches
+			hunk.Lines = append(hunk.Lines, &DiffSearchLine{Type: "skip"})
+		}
+		hunk.Lines = append(hunk.Lines, &DiffSearchLine{
+			LeftIdx:  line.LeftIdx,
+			RightIdx: line.RightIdx,
+			Type:     diffSearchLineTypes[line.Type],
+			Content:  lineContent(line),
+			Matched:  matched[i],
+		})
+		lastIncluded = i
+	}
+	return hunk
+}
+
+func nearMatch(matched []bool, idx, contextLines int) bool {
+	for i := idx - contextLines; i <= idx+contextLines; i++ {
+		if i >= 0 && i < len(matched) && matched[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// lineContent returns the content of a diff line without its +/- marker
+func lineContent(line *DiffLine) string {
+	if len(line.Content) == 0 {
+		return ""
+	}
+	return line.Content[1:]
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_Search(t *testing.T) {
+	var diff = `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,9 +1,9 @@
+ line 1
+ line 2
+ line 3
+ line 4
+-Needle old
++Needle new
+ line 6
+ line 7
+ line 8
+ line 9
+diff --git a/docs/needle.md b/docs/needle.md
+--- a/docs/needle.md
++++ b/docs/needle.md
+@@ -1,2 +1,2 @@
+-hay
++straw
+ hay
+`
+	result, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff))
+	assert.NoError(t, err)
+
+	results, hasMore := result.Search("NEEDLE", 1, 10)
+	assert.False(t, hasMore)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "README.md", results[0].Name)
+		assert.False(t, results[0].NameMatched)
+		if assert.Len(t, results[0].Hunks, 1) {
+			hunk := results[0].Hunks[0]
+			assert.Equal(t, "@@ -1,9 +1,9 @@", hunk.Header)
+			if assert.Len(t, hunk.Lines, 4) {
+				assert.Equal(t, "line 4", hunk.Lines[0].Content)
+				assert.Equal(t, "del", hunk.Lines[1].Type)
+				assert.True(t, hunk.Lines[1].Matched)
+				assert.Equal(t, 5, hunk.Lines[1].LeftIdx)
+				assert.Equal(t, "add", hunk.Lines[2].Type)
+				assert.Equal(t, "Needle new", hunk.Lines[2].Content)
+				assert.Equal(t, "line 6", hunk.Lines[3].Content)
+				assert.False(t, hunk.Lines[3].Matched)
+			}
+		}
+
+		assert.Equal(t, "docs/needle.md", results[1].Name)
+		assert.True(t, results[1].NameMatched)
+		assert.Empty(t, results[1].Hunks)
+	}
+
+	results, hasMore = result.Search("needle", 1, 1)
+	assert.True(t, hasMore)
+	assert.Len(t, results, 1)
+
+	results, _ = result.Search("hay", 0, 10)
+	if assert.Len(t, results, 1) && assert.Len(t, results[0].Hunks, 1) {
+		lines := results[0].Hunks[0].Lines
+		if assert.Len(t, lines, 3) {
+			assert.Equal(t, "del", lines[0].Type)
+			assert.Equal(t, "skip", lines[1].Type)
+			assert.Equal(t, "same", lines[2].Type)
+		}
+	}
+
+	results, _ = result.Search("", 1, 10)
+	assert.Empty(t, results)
+}
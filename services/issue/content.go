@@ -7,10 +7,16 @@ package issue
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/services/antispam"
 )
 
 // ChangeContent changes issue content, as the given user.
 func ChangeContent(issue *models.Issue, doer *models.User, content string) (err error) {
+	classified, flagged, err := checkIssueContent(issue, doer, issue.Title, content)
+	if err != nil {
+		return err
+	}
+
 	oldContent := issue.Content
 
 	if err := issue.ChangeContent(doer, content); err != nil {
@@ -18,6 +24,38 @@ func ChangeContent(issue *models.Issue, doer *models.User, content string) (err
 	}
 
 	notification.NotifyIssueChangeContent(doer, issue, oldContent)
+	if flagged {
+		antispam.Flag(classified, issue.HTMLURL())
+	}
 
 	return nil
 }
+
+// CheckEditedContent classifies the edited title and content of the issue before they are published,
+// it returns the classified content if it has to be flagged once published
+func CheckEditedContent(issue *models.Issue, doer *models.User, title, content string) (*antispam.Content, error) {
+	classified, flagged, err := checkIssueContent(issue, doer, title, content)
+	if err != nil || !flagged {
+		return nil, err
+	}
+	return classified, nil
+}
+
+func checkIssueContent(issue *models.Issue, doer *models.User, title, content string) (*antispam.Content, bool, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return nil, false, err
+	}
+	classified := &antispam.Content{
+		Kind:   antispam.KindIssue,
+		IsEdit: true,
+		Doer:   doer,
+		Repo:   issue.Repo,
+		Title:  title,
+		Text:   content,
+	}
+	if issue.IsPull {
+		classified.Kind = antispam.KindPullRequest
+	}
+	flagged, err := antispam.Check(classified)
+	return classified, flagged, err
+}
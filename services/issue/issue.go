@@ -9,13 +9,32 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/antispam"
 )
 
 // NewIssue creates new issue with labels for repository.
 func NewIssue(repo *models.Repository, issue *models.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64) error {
+	content := &antispam.Content{
+		Kind:  antispam.KindIssue,
+		Doer:  issue.Poster,
+		Repo:  repo,
+		Title: issue.Title,
+		Text:  issue.Content,
+	}
+	flagged, err := antispam.Check(content)
+	if err != nil {
+		return err
+	}
+
 	if err := models.NewIssue(repo, issue, labelIDs, uuids); err != nil {
 		return err
 	}
+	if flagged {
+		if err := issue.LoadRepo(); err != nil {
+			return err
+		}
+		antispam.Flag(content, issue.HTMLURL())
+	}
 
 	for _, assigneeID := range assigneeIDs {
 		if err := AddAssigneeIfNotAssigned(issue, issue.Poster, assigneeID); err != nil {
@@ -41,6 +60,11 @@ func NewIssue(repo *models.Repository, issue *models.Issue, labelIDs []int64, uu
 
 // ChangeTitle changes the title of this issue, as the given user.
 func ChangeTitle(issue *models.Issue, doer *models.User, title string) (err error) {
+	content, flagged, err := checkIssueContent(issue, doer, title, issue.Content)
+	if err != nil {
+		return err
+	}
+
 	oldTitle := issue.Title
 	issue.Title = title
 
@@ -49,6 +73,9 @@ func ChangeTitle(issue *models.Issue, doer *models.User, title string) (err erro
 	}
 
 	notification.NotifyIssueChangeTitle(doer, issue, oldTitle)
+	if flagged {
+		antispam.Flag(content, issue.HTMLURL())
+	}
 
 	return nil
 }
@@ -168,6 +168,22 @@ func BatchHandler(ctx *context.Context) {
 		return
 	}
 
+	// remainingSize is the size the new objects of an upload can take within the size quota of the owner
+	remainingSize := int64(-1)
+	if isUpload {
+		if err := repository.GetOwner(); err != nil {
+			log.Error("Unable to get owner of %s/%s. Error: %v", rc.User, rc.Repo, err)
+			writeStatus(ctx, http.StatusInternalServerError)
+			return
+		}
+		var err error
+		if remainingSize, err = repository.Owner.RemainingSize(); err != nil {
+			log.Error("Unable to get the remaining size quota of %s. Error: %v", rc.User, err)
+			writeStatus(ctx, http.StatusInternalServerError)
+			return
+		}
+	}
+
 	contentStore := lfs_module.NewContentStore()
 
 	var responseObjects []*lfs_module.ObjectResponse
@@ -212,6 +228,16 @@ func BatchHandler(ctx *context.Context) {
 					Message: fmt.Sprintf("Size must be less than or equal to %d", setting.LFS.MaxFileSize),
 				}
 			}
+			if err == nil && meta == nil && remainingSize > -1 {
+				if p.Size > remainingSize {
+					err = &lfs_module.ObjectError{
+						Code:    http.StatusUnprocessableEntity,
+						Message: fmt.Sprintf("The size quota of %s is exceeded", rc.User),
+					}
+				} else {
+					remainingSize -= p.Size
+				}
+			}
 
 			if exists {
 				if meta == nil {
@@ -270,6 +296,27 @@ func UploadHandler(ctx *context.Context) {
 		return
 	}
 
+	if _, err := repository.GetLFSMetaObjectByOid(p.Oid); err == models.ErrLFSObjectNotExist {
+		if err := repository.GetOwner(); err != nil {
+			log.Error("Unable to get owner of %s/%s. Error: %v", rc.User, rc.Repo, err)
+			writeStatus(ctx, http.StatusInternalServerError)
+			return
+		}
+		if err := repository.Owner.CheckSizeQuota(p.Size); err != nil {
+			if models.IsErrSizeQuotaExceeded(err) {
+				writeStatusMessage(ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("The size quota of %s is exceeded", rc.User))
+			} else {
+				log.Error("Unable to check the size quota of %s. Error: %v", rc.User, err)
+				writeStatus(ctx, http.StatusInternalServerError)
+			}
+			return
+		}
+	} else if err != nil {
+		log.Error("Unable to get LFS MetaObject [%s] for %s/%s. Error: %v", p.Oid, rc.User, rc.Repo, err)
+		writeStatus(ctx, http.StatusInternalServerError)
+		return
+	}
+
 	meta, err := models.NewLFSMetaObject(&models.LFSMetaObject{Pointer: p, RepositoryID: repository.ID})
 	if err != nil {
 		log.Error("Unable to create LFS MetaObject [%s] for %s/%s. Error: %v", p.Oid, rc.User, rc.Repo, err)
@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var authResultsCommentPattern = regexp.MustCompile(`\([^()]*\)`)
+
+// authResult is a result of an authentication method of an Authentication-Results header (RFC8601)
+type authResult struct {
+	Method     string
+	Result     string
+	Properties map[string]string
+}
+
+// parseAuthenticationResults returns the authserv-id and the results of an Authentication-Results header
+func parseAuthenticationResults(value string) (string, []*authResult) {
+	value = authResultsCommentPattern.ReplaceAllString(value, " ")
+	parts := strings.Split(value, ";")
+	fields := strings.Fields(parts[0])
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	results := make([]*authResult, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		method := strings.SplitN(fields[0], "=", 2)
+		if len(method) != 2 {
+			continue
+		}
+		result := &authResult{
+			Method:     strings.ToLower(method[0]),
+			Result:     strings.ToLower(method[1]),
+			Properties: make(map[string]string, len(fields)-1),
+		}
+		for _, field := range fields[1:] {
+			if property := strings.SplitN(field, "=", 2); len(property) == 2 {
+				result.Properties[strings.ToLower(property[0])] = strings.Trim(property[1], `"`)
+			}
+		}
+		results = append(results, result)
+	}
+	return strings.ToLower(fields[0]), results
+}
+
+// isAuthenticatedSender returns true if a trusted mail server has verified that the email was sent by address,
+// that is a passed DKIM signature whose agent identity (header.i) is the address of the From header.
+// DMARC, SPF and the signing domain only vouch for the domain, anybody with an account on it could pose as its users.
+// The mail server must remove the Authentication-Results headers with its authserv-id from the received emails.
+func isAuthenticatedSender(header mail.Header, address string) bool {
+	for _, value := range header["Authentication-Results"] {
+		authservID, results := parseAuthenticationResults(value)
+		if !isTrustedAuthservID(authservID) {
+			continue
+		}
+		for _, result := range results {
+			if result.Method == "dkim" && result.Result == "pass" && strings.EqualFold(result.Properties["header.i"], address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTrustedAuthservID(authservID string) bool {
+	for _, trusted := range setting.IncomingEmail.TrustedAuthservIDs {
+		if strings.EqualFold(trusted, authservID) {
+			return true
+		}
+	}
+	return false
+}
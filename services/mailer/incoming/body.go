@@ -0,0 +1,150 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var replyAttributionPattern = regexp.MustCompile(`(?i)\bwrote:\s*$`)
+
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// emailAttachment represents a file attached to an email
+type emailAttachment struct {
+	Name    string
+	Content []byte
+}
+
+// emailContent represents the plain text body and the attachments of an email
+type emailContent struct {
+	Body        string
+	Attachments []*emailAttachment
+
+	hasBody bool
+}
+
+type partHeader interface {
+	Get(key string) string
+}
+
+// readBody returns the plain text body and the attachments of the email
+func readBody(msg *mail.Message) (*emailContent, error) {
+	content := &emailContent{}
+	if err := readPart(msg.Body, msg.Header, content); err != nil {
+		return nil, err
+	}
+	content.Body = strings.ReplaceAll(content.Body, "\r\n", "\n")
+	return content, nil
+}
+
+func readPart(r io.Reader, header partHeader, content *emailContent) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := readPart(part, part.Header, content); err != nil {
+				return err
+			}
+		}
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	isAttachment := disposition == "attachment" || filename != ""
+	if !isAttachment && (mediaType != "text/plain" || content.hasBody) {
+		return nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if isAttachment {
+		name := path.Base(decodeHeader(filename))
+		if name == "" || name == "." || name == "/" {
+			name = "attachment"
+		}
+		content.Attachments = append(content.Attachments, &emailAttachment{Name: name, Content: data})
+		return nil
+	}
+	content.Body = string(data)
+	content.hasBody = true
+	return nil
+}
+
+// splitPatch splits the body of a patch generated by git format-patch into the commit message and the diff
+func splitPatch(body string) (description, diff string) {
+	if strings.HasPrefix(body, "---\n") {
+		return "", body
+	}
+	if idx := strings.Index(body, "\n---\n"); idx >= 0 {
+		return strings.TrimSpace(body[:idx]), body[idx+1:]
+	}
+	if loc := patchDiffPattern.FindStringIndex(body); loc != nil {
+		return strings.TrimSpace(body[:loc[0]]), body[loc[0]:]
+	}
+	return strings.TrimSpace(body), ""
+}
+
+// trimReply removes the signature and the quoted email at the end of a reply
+func trimReply(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if line == "-- " {
+			lines = lines[:i]
+			break
+		}
+	}
+
+	end := len(lines)
+	for end > 0 {
+		line := strings.TrimSpace(lines[end-1])
+		if line == "" || strings.HasPrefix(line, ">") {
+			end--
+			continue
+		}
+		break
+	}
+	if end < len(lines) && end > 0 && replyAttributionPattern.MatchString(lines[end-1]) {
+		end--
+	}
+	return strings.TrimSpace(strings.Join(lines[:end], "\n"))
+}
@@ -0,0 +1,513 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/upload"
+	comment_service "code.gitea.io/gitea/services/comments"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// PatchBranchPrefix is the prefix of the branches holding the patches received by email
+const PatchBranchPrefix = "patch/"
+
+var (
+	patchSubjectPattern   = regexp.MustCompile(`(?i)^\s*\[([^\]]*\bPATCH\b[^\]]*)\]\s*(.*)$`)
+	patchSeriesPattern    = regexp.MustCompile(`\b(\d+)/(\d+)$`)
+	patchDiffPattern      = regexp.MustCompile(`(?m)^diff --git `)
+	patchAuthorPattern    = regexp.MustCompile(`^From: ([^\n]+)\n`)
+	issueReferencePattern = regexp.MustCompile(`^(.+)/(issues|pulls)/(\d+)@(.+)$`)
+)
+
+// ErrRejected represents an email which cannot be accepted
+type ErrRejected struct {
+	Reason string
+}
+
+// IsErrRejected checks if an error is a ErrRejected.
+func IsErrRejected(err error) bool {
+	_, ok := err.(ErrRejected)
+	return ok
+}
+
+func (err ErrRejected) Error() string {
+	return fmt.Sprintf("email rejected: %s", err.Reason)
+}
+
+// ReceiveEmail handles an email sent to the incoming address of a repository.
+// The sender is identified by the personal address the email is sent to, or by its From header if a trusted mail server
+// verified a DKIM signature of that exact address.
+// Replies sent to the reply address of a notification are added as comments by the notified user,
+// patches generated by git format-patch are applied to a branch of the repository and opened as a pull request,
+// other emails replying to a pull request or an issue are added as comments and new threads are opened as issues.
+func ReceiveEmail(r io.Reader) error {
+	return receiveEmail(r, "")
+}
+
+// receiveEmail handles an email, the repository is chosen from recipient if set, from the headers of the email otherwise
+func receiveEmail(r io.Reader, recipient string) error {
+	if !setting.IncomingEmail.Enabled {
+		return ErrRejected{Reason: "incoming email is disabled"}
+	}
+
+	maxSize := setting.IncomingEmail.MaxEmailSize * 1024 * 1024
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > maxSize {
+		return ErrRejected{Reason: fmt.Sprintf("the email is larger than %d MiB", setting.IncomingEmail.MaxEmailSize)}
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return ErrRejected{Reason: fmt.Sprintf("unable to parse email: %v", err)}
+	}
+
+	repo, token, err := getRepository(msg.Header, recipient)
+	if err != nil {
+		return err
+	}
+
+	subject := decodeHeader(msg.Header.Get("Subject"))
+	content, err := readBody(msg)
+	if err != nil {
+		return ErrRejected{Reason: fmt.Sprintf("unable to read email body: %v", err)}
+	}
+
+	var doer *models.User
+	if token != "" {
+		userID, issueIndex, ok := repo.ParseIncomingReplyToken(token)
+		if !ok {
+			return ErrRejected{Reason: "invalid reply token"}
+		}
+		doer, err = getTokenUser(userID)
+		if err != nil {
+			return err
+		}
+		if issueIndex != 0 {
+			return receiveReply(doer, repo, issueIndex, content)
+		}
+	} else {
+		doer, err = getSender(msg.Header)
+		if err != nil {
+			return err
+		}
+	}
+
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return err
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		return ErrRejected{Reason: fmt.Sprintf("%s cannot access %s", doer.Name, repo.FullName())}
+	}
+
+	refs := getReferences(msg.Header)
+	issue, err := getThreadIssue(repo, refs)
+	if err != nil {
+		return err
+	}
+
+	body := content.Body
+	match := patchSubjectPattern.FindStringSubmatch(subject)
+	if match == nil || !patchDiffPattern.MatchString(body) {
+		if issue != nil {
+			return addComment(doer, perm, repo, issue, content)
+		}
+		if len(refs) > 0 {
+			return ErrRejected{Reason: "the email replies to neither an issue nor a pull request"}
+		}
+		return createIssue(doer, perm, repo, subject, content)
+	}
+
+	if series := patchSeriesPattern.FindStringSubmatch(match[1]); series != nil && series[1] == "0" {
+		log.Trace("Ignoring cover letter %q for %s", subject, repo.FullName())
+		return nil
+	}
+
+	if issue != nil && issue.IsPull {
+		if err := issue.LoadPullRequest(); err != nil {
+			return err
+		}
+		if !issue.IsClosed && issue.PullRequest.HeadRepoID == repo.ID && strings.HasPrefix(issue.PullRequest.HeadBranch, PatchBranchPrefix) {
+			if issue.PosterID != doer.ID && !perm.CanWrite(models.UnitTypeCode) {
+				return ErrRejected{Reason: fmt.Sprintf("%s cannot add patches to %s#%d", doer.Name, repo.FullName(), issue.Index)}
+			}
+			return applyPatch(doer, repo, msg.Header, match[2], body, issue.PullRequest.HeadBranch, issue.PullRequest.HeadBranch)
+		}
+	}
+
+	if !repo.CanEnablePulls() || !repo.UnitEnabled(models.UnitTypePullRequests) {
+		return ErrRejected{Reason: fmt.Sprintf("%s does not accept pull requests", repo.FullName())}
+	}
+
+	root := strings.Trim(msg.Header.Get("Message-ID"), "<> ")
+	if len(refs) > 0 {
+		root = refs[0]
+	}
+	if root == "" {
+		return ErrRejected{Reason: "the email has no Message-ID"}
+	}
+	return createPullRequest(doer, repo, msg.Header, match[2], body, patchBranchName(root))
+}
+
+// getRepository returns the repository addressed by recipient, or by one of the recipients of the email if it is empty,
+// and the reply token following the repository name in the address if any
+func getRepository(header mail.Header, recipient string) (*models.Repository, string, error) {
+	at := strings.LastIndex(setting.IncomingEmail.Address, "@")
+	prefix := strings.ToLower(setting.IncomingEmail.Address[:at] + "+")
+	domain := strings.ToLower(setting.IncomingEmail.Address[at:])
+
+	var recipients []*mail.Address
+	if recipient != "" {
+		recipients = []*mail.Address{{Address: recipient}}
+	} else {
+		for _, key := range []string{"Delivered-To", "X-Original-To", "To", "Cc"} {
+			if addresses, err := header.AddressList(key); err == nil {
+				recipients = append(recipients, addresses...)
+			}
+		}
+	}
+
+	for _, address := range recipients {
+		lower := strings.ToLower(address.Address)
+		if !strings.HasPrefix(lower, prefix) || !strings.HasSuffix(lower, domain) {
+			continue
+		}
+		fullName := address.Address[len(prefix) : len(address.Address)-len(domain)]
+		// owner and repository names cannot contain a '+', what follows it is a reply token
+		var token string
+		if idx := strings.Index(fullName, "+"); idx >= 0 {
+			fullName, token = fullName[:idx], fullName[idx+1:]
+		}
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				continue
+			}
+			return nil, "", err
+		}
+		if repo.IsArchived {
+			return nil, "", ErrRejected{Reason: fmt.Sprintf("%s is archived", repo.FullName())}
+		}
+		return repo, token, nil
+	}
+	return nil, "", ErrRejected{Reason: "no recipient is the address of a repository"}
+}
+
+// receiveReply adds a reply sent to a reply address as a comment of the user the address was generated for
+func receiveReply(doer *models.User, repo *models.Repository, issueIndex int64, content *emailContent) error {
+	issue, err := models.GetIssueByIndex(repo.ID, issueIndex)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return ErrRejected{Reason: fmt.Sprintf("%s#%d does not exist", repo.FullName(), issueIndex)}
+		}
+		return err
+	}
+
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return err
+	}
+	return addComment(doer, perm, repo, issue, content)
+}
+
+// getTokenUser returns the user a reply or submission address was generated for
+func getTokenUser(userID int64) (*models.User, error) {
+	doer, err := models.GetUserByID(userID)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return nil, ErrRejected{Reason: "the user of the reply token does not exist"}
+		}
+		return nil, err
+	}
+	if !doer.IsActive || doer.ProhibitLogin {
+		return nil, ErrRejected{Reason: fmt.Sprintf("%s is not allowed to send emails", doer.Name)}
+	}
+	return doer, nil
+}
+
+// getSender returns the user whose email address sent the email.
+// As the From header can be forged, it is only trusted if a trusted mail server authenticated the exact address.
+func getSender(header mail.Header) (*models.User, error) {
+	from, err := mail.ParseAddress(header.Get("From"))
+	if err != nil {
+		return nil, ErrRejected{Reason: fmt.Sprintf("invalid sender: %v", err)}
+	}
+	if !isAuthenticatedSender(header, from.Address) {
+		return nil, ErrRejected{Reason: fmt.Sprintf("the sender %s is not authenticated, the email must be sent to your personal address of the repository", from.Address)}
+	}
+	doer, err := models.GetUserByEmail(from.Address)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return nil, ErrRejected{Reason: fmt.Sprintf("no user has the email address %s", from.Address)}
+		}
+		return nil, err
+	}
+	if !doer.IsActive || doer.ProhibitLogin {
+		return nil, ErrRejected{Reason: fmt.Sprintf("%s is not allowed to send emails", doer.Name)}
+	}
+	return doer, nil
+}
+
+// getReferences returns the message ids the email replies to, from the root of the thread
+func getReferences(header mail.Header) []string {
+	refs := strings.Fields(header.Get("References"))
+	if inReplyTo := strings.TrimSpace(header.Get("In-Reply-To")); inReplyTo != "" {
+		refs = append(refs, strings.Fields(inReplyTo)...)
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if id := strings.Trim(ref, "<>"); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// getThreadIssue returns the issue or pull request of the repository the email thread is about
+func getThreadIssue(repo *models.Repository, refs []string) (*models.Issue, error) {
+	for _, ref := range refs {
+		if match := issueReferencePattern.FindStringSubmatch(ref); match != nil {
+			if !strings.EqualFold(match[1], repo.FullName()) || match[4] != setting.Domain {
+				continue
+			}
+			index, _ := strconv.ParseInt(match[3], 10, 64)
+			issue, err := models.GetIssueByIndex(repo.ID, index)
+			if err != nil {
+				if models.IsErrIssueNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			return issue, nil
+		}
+
+		prs, err := models.GetUnmergedPullRequestsByHeadInfo(repo.ID, patchBranchName(ref))
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.BaseRepoID != repo.ID {
+				continue
+			}
+			if err := pr.LoadIssue(); err != nil {
+				return nil, err
+			}
+			return pr.Issue, nil
+		}
+	}
+	return nil, nil
+}
+
+func patchBranchName(messageID string) string {
+	hash := sha1.Sum([]byte(messageID))
+	return PatchBranchPrefix + hex.EncodeToString(hash[:])[:12]
+}
+
+func addComment(doer *models.User, perm models.Permission, repo *models.Repository, issue *models.Issue, content *emailContent) error {
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return ErrRejected{Reason: fmt.Sprintf("%s cannot comment on %s#%d", doer.Name, repo.FullName(), issue.Index)}
+	}
+	if issue.IsLocked && !perm.CanWriteIssuesOrPulls(issue.IsPull) && !doer.IsAdmin {
+		return ErrRejected{Reason: fmt.Sprintf("%s#%d is locked", repo.FullName(), issue.Index)}
+	}
+
+	reply := trimReply(content.Body)
+	if reply == "" && len(content.Attachments) == 0 {
+		return ErrRejected{Reason: "the reply is empty"}
+	}
+	uuids, err := saveAttachments(doer, content.Attachments)
+	if err != nil {
+		return err
+	}
+	_, err = comment_service.CreateIssueComment(doer, repo, issue, reply, uuids)
+	return err
+}
+
+func createIssue(doer *models.User, perm models.Permission, repo *models.Repository, subject string, content *emailContent) error {
+	if !repo.UnitEnabled(models.UnitTypeIssues) || !perm.CanRead(models.UnitTypeIssues) {
+		return ErrRejected{Reason: fmt.Sprintf("%s cannot open issues in %s", doer.Name, repo.FullName())}
+	}
+
+	title := strings.TrimSpace(subject)
+	if title == "" {
+		return ErrRejected{Reason: "the email has no subject"}
+	}
+	if runes := []rune(title); len(runes) > 255 {
+		title = string(runes[:255])
+	}
+	uuids, err := saveAttachments(doer, content.Attachments)
+	if err != nil {
+		return err
+	}
+
+	issue := &models.Issue{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Title:    title,
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  trimReply(content.Body),
+	}
+	return issue_service.NewIssue(repo, issue, nil, uuids, nil)
+}
+
+// saveAttachments saves the files attached to an email as attachments uploaded by doer and returns their uuids
+func saveAttachments(doer *models.User, attachments []*emailAttachment) ([]string, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if !setting.Attachment.Enabled {
+		return nil, ErrRejected{Reason: "attachments are disabled"}
+	}
+	if len(attachments) > setting.Attachment.MaxFiles {
+		return nil, ErrRejected{Reason: fmt.Sprintf("too many attachments, at most %d are allowed", setting.Attachment.MaxFiles)}
+	}
+
+	uuids := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		if int64(len(attachment.Content)) > setting.Attachment.MaxSize*1024*1024 {
+			return nil, ErrRejected{Reason: fmt.Sprintf("%s is too large", attachment.Name)}
+		}
+		buf := attachment.Content
+		if len(buf) > 1024 {
+			buf = buf[:1024]
+		}
+		if err := upload.Verify(buf, attachment.Name, setting.Attachment.AllowedTypes); err != nil {
+			return nil, ErrRejected{Reason: err.Error()}
+		}
+
+		attach, err := models.NewAttachment(&models.Attachment{
+			UploaderID: doer.ID,
+			Name:       attachment.Name,
+		}, attachment.Content, bytes.NewReader(nil))
+		if err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, attach.UUID)
+	}
+	return uuids, nil
+}
+
+func applyPatch(doer *models.User, repo *models.Repository, header mail.Header, title, body, oldBranch, newBranch string) error {
+	opts, err := toApplyDiffPatchOptions(doer, header, title, body)
+	if err != nil {
+		return err
+	}
+	opts.OldBranch = oldBranch
+	opts.NewBranch = newBranch
+	if _, err := repofiles.ApplyDiffPatch(repo, doer, opts); err != nil {
+		if models.IsErrPatchNotApplicable(err) || models.IsErrUserCannotCommit(err) || models.IsErrBranchAlreadyExists(err) {
+			return ErrRejected{Reason: err.Error()}
+		}
+		return err
+	}
+	return nil
+}
+
+func createPullRequest(doer *models.User, repo *models.Repository, header mail.Header, title, body, branch string) error {
+	baseCommitID, err := getBranchCommitID(repo, repo.DefaultBranch)
+	if err != nil {
+		return err
+	}
+	if err := applyPatch(doer, repo, header, title, body, repo.DefaultBranch, branch); err != nil {
+		return err
+	}
+
+	description, _ := splitPatch(body)
+	pull := &models.Issue{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Title:    title,
+		PosterID: doer.ID,
+		Poster:   doer,
+		IsPull:   true,
+		Content:  description,
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: repo.ID,
+		BaseRepoID: repo.ID,
+		HeadBranch: branch,
+		BaseBranch: repo.DefaultBranch,
+		HeadRepo:   repo,
+		BaseRepo:   repo,
+		MergeBase:  baseCommitID,
+		Type:       models.PullRequestGitea,
+	}
+	return pull_service.NewPullRequest(repo, pull, nil, nil, pr, nil)
+}
+
+func toApplyDiffPatchOptions(doer *models.User, header mail.Header, title, body string) (*repofiles.ApplyDiffPatchOptions, error) {
+	description, diff := splitPatch(body)
+	message := title
+	if description != "" {
+		message += "\n\n" + description
+	}
+
+	opts := &repofiles.ApplyDiffPatchOptions{
+		Message: message,
+		Content: diff,
+		Committer: &repofiles.IdentityOptions{
+			Name:  doer.DisplayName(),
+			Email: doer.Email,
+		},
+	}
+
+	author, err := mail.ParseAddress(header.Get("From"))
+	if err != nil {
+		return nil, ErrRejected{Reason: fmt.Sprintf("invalid sender: %v", err)}
+	}
+	// git send-email puts the author in the body when the sender is somebody else
+	if from := patchAuthorPattern.FindStringSubmatch(description); from != nil {
+		if parsed, err := mail.ParseAddress(from[1]); err == nil {
+			author = parsed
+			opts.Message = title + "\n\n" + strings.TrimSpace(description[len(from[0]):])
+		}
+	}
+	opts.Author = &repofiles.IdentityOptions{
+		Name:  author.Name,
+		Email: author.Address,
+	}
+	if date, err := header.Date(); err == nil {
+		opts.Dates = &repofiles.CommitDateOptions{
+			Author:    date,
+			Committer: time.Now(),
+		}
+	}
+	return opts, nil
+}
+
+func getBranchCommitID(repo *models.Repository, branch string) (string, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+	return gitRepo.GetBranchCommitID(branch)
+}
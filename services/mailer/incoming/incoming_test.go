@@ -0,0 +1,297 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", "..", ".."))
+}
+
+const testPatch = `From: User Two <user2@example.com>
+Subject: [PATCH v2 1/2] Update README
+
+Explain the change.
+---
+ README.md | 1 +
+ 1 file changed, 1 insertion(+)
+
+diff --git a/README.md b/README.md
+index 4b4851a..b0d4a0a 100644
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,4 @@
+ # repo1
+
+ Description for repo1
++More
+--
+2.30.0
+`
+
+func TestSplitPatch(t *testing.T) {
+	msg, err := mail.ReadMessage(strings.NewReader(testPatch))
+	assert.NoError(t, err)
+	content, err := readBody(msg)
+	assert.NoError(t, err)
+	body := content.Body
+
+	match := patchSubjectPattern.FindStringSubmatch(msg.Header.Get("Subject"))
+	assert.Len(t, match, 3)
+	assert.Equal(t, "PATCH v2 1/2", match[1])
+	assert.Equal(t, "Update README", match[2])
+	assert.True(t, patchDiffPattern.MatchString(body))
+
+	description, diff := splitPatch(body)
+	assert.Equal(t, "Explain the change.", description)
+	assert.True(t, strings.HasPrefix(diff, "---\n README.md"))
+	assert.Contains(t, diff, "+More\n")
+}
+
+func TestReadBody(t *testing.T) {
+	raw := "Content-Type: multipart/alternative; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n" +
+		"Looks good=2C thanks!\r\n" +
+		"--b\r\nContent-Type: text/html\r\n\r\n<p>Looks good, thanks!</p>\r\n" +
+		"--b--\r\n"
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	assert.NoError(t, err)
+	content, err := readBody(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Looks good, thanks!", content.Body)
+	assert.Empty(t, content.Attachments)
+
+	raw = "Content-Type: multipart/mixed; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nSee the log.\r\n" +
+		"--b\r\nContent-Type: text/plain\r\nContent-Disposition: attachment; filename=\"../build.log\"\r\n\r\nerror\r\n" +
+		"--b\r\nContent-Type: image/png; name=\"screen.png\"\r\nContent-Transfer-Encoding: base64\r\n\r\naGVsbG8=\r\n" +
+		"--b--\r\n"
+	msg, err = mail.ReadMessage(strings.NewReader(raw))
+	assert.NoError(t, err)
+	content, err = readBody(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "See the log.", content.Body)
+	if assert.Len(t, content.Attachments, 2) {
+		assert.Equal(t, "build.log", content.Attachments[0].Name)
+		assert.Equal(t, "error", string(content.Attachments[0].Content))
+		assert.Equal(t, "screen.png", content.Attachments[1].Name)
+		assert.Equal(t, "hello", string(content.Attachments[1].Content))
+	}
+}
+
+func TestTrimReply(t *testing.T) {
+	assert.Equal(t, "Looks good.", trimReply("Looks good.\n\nOn Mon, 1 Feb 2021, User One wrote:\n> diff --git a/README.md\n> +More\n\n-- \nSignature\n"))
+	assert.Equal(t, "> +More\nWhy?", trimReply("> +More\nWhy?\n> -Less\n"))
+}
+
+func TestGetReferences(t *testing.T) {
+	header := mail.Header{
+		"References":  []string{"<root@example.com> <second@example.com>"},
+		"In-Reply-To": []string{"<third@example.com>"},
+	}
+	assert.Equal(t, []string{"root@example.com", "second@example.com", "third@example.com"}, getReferences(header))
+}
+
+func TestReceiveEmail(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSetting, oldDomain := setting.IncomingEmail, setting.Domain
+	defer func() {
+		setting.IncomingEmail, setting.Domain = oldSetting, oldDomain
+	}()
+	setting.Domain = "localhost"
+	setting.IncomingEmail.Enabled = true
+	setting.IncomingEmail.Address = "incoming@localhost"
+	setting.IncomingEmail.MaxEmailSize = 1
+	setting.IncomingEmail.TrustedAuthservIDs = []string{"mx.localhost"}
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Equal(t, "incoming+user2/repo1@localhost", repo.IncomingEmailAddress())
+
+	reply := "Authentication-Results: mx.localhost; dkim=pass (good signature) header.d=example.com header.i=user2@example.com; spf=fail smtp.mailfrom=example.com\r\n" +
+		"From: User Two <user2@example.com>\r\n" +
+		"To: incoming+user2/repo1@localhost\r\n" +
+		"Subject: Re: issue1\r\n" +
+		"Message-ID: <reply@example.com>\r\n" +
+		"In-Reply-To: <user2/repo1/issues/1@localhost>\r\n" +
+		"\r\n" +
+		"Replying by email.\r\n" +
+		"\r\n" +
+		"> content for the first issue\r\n"
+	assert.NoError(t, ReceiveEmail(strings.NewReader(reply)))
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, PosterID: 2, Content: "Replying by email."})
+
+	err := ReceiveEmail(strings.NewReader(strings.Replace(reply, "user2@example.com", "unknown@example.com", 1)))
+	assert.True(t, IsErrRejected(err))
+
+	// the From header is not trusted without the authentication results of a trusted mail server
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "header.i=user2@example.com", "header.i=user3@example.com", 1)))
+	assert.True(t, IsErrRejected(err))
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "header.i=user2@example.com", "header.i=@example.com", 1)))
+	assert.True(t, IsErrRejected(err))
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "mx.localhost;", "mx.example.org;", 1)))
+	assert.True(t, IsErrRejected(err))
+	err = ReceiveEmail(strings.NewReader(reply[strings.Index(reply, "From:"):]))
+	assert.True(t, IsErrRejected(err))
+
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "incoming+user2/repo1@localhost", "incoming+user2/unknown@localhost", 1)))
+	assert.True(t, IsErrRejected(err))
+
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "In-Reply-To: <user2/repo1/issues/1@", "In-Reply-To: <unknown@", 1)))
+	assert.True(t, IsErrRejected(err))
+
+	// emails too large are rejected instead of being truncated
+	err = ReceiveEmail(strings.NewReader(reply + strings.Repeat("x", 1024*1024)))
+	assert.True(t, IsErrRejected(err))
+}
+
+func TestReceiveEmail_ReplyToken(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSetting, oldDomain := setting.IncomingEmail, setting.Domain
+	defer func() {
+		setting.IncomingEmail, setting.Domain = oldSetting, oldDomain
+	}()
+	setting.Domain = "localhost"
+	setting.IncomingEmail.Enabled = true
+	setting.IncomingEmail.Address = "incoming@localhost"
+	setting.IncomingEmail.MaxEmailSize = 1
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	address := repo.IncomingReplyAddress(user, 1)
+	assert.True(t, strings.HasPrefix(address, "incoming+user2/repo1+2.1."))
+
+	_, token, err := getRepository(mail.Header{"To": []string{address}}, "")
+	assert.NoError(t, err)
+	userID, issueIndex, ok := repo.ParseIncomingReplyToken(token)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, userID)
+	assert.EqualValues(t, 1, issueIndex)
+	_, _, ok = repo.ParseIncomingReplyToken("2.2." + token[len("2.1."):])
+	assert.False(t, ok)
+
+	// the token identifies the user whatever the sender address
+	reply := "From: Somebody <unknown@example.com>\r\n" +
+		"To: " + address + "\r\n" +
+		"Subject: Re: issue1\r\n" +
+		"\r\n" +
+		"Replying with a token.\r\n"
+	assert.NoError(t, ReceiveEmail(strings.NewReader(reply)))
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, PosterID: 2, Content: "Replying with a token."})
+
+	err = ReceiveEmail(strings.NewReader(strings.Replace(reply, "+2.1.", "+2.2.", 1)))
+	assert.True(t, IsErrRejected(err))
+}
+
+func TestReceiveEmail_NewIssue(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSetting, oldAttachment, oldDomain := setting.IncomingEmail, setting.Attachment, setting.Domain
+	defer func() {
+		setting.IncomingEmail, setting.Attachment, setting.Domain = oldSetting, oldAttachment, oldDomain
+	}()
+	setting.Domain = "localhost"
+	setting.IncomingEmail.Enabled = true
+	setting.IncomingEmail.Address = "incoming@localhost"
+	setting.IncomingEmail.MaxEmailSize = 1
+	setting.Attachment.Enabled = true
+	setting.Attachment.AllowedTypes = "text/plain"
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	address := repo.IncomingSubmissionAddress(user)
+	assert.True(t, strings.HasPrefix(address, "incoming+user2/repo1+2.0."))
+
+	email := "From: Somebody <unknown@example.com>\r\n" +
+		"To: " + address + "\r\n" +
+		"Subject: Opened by email\r\n" +
+		"Message-ID: <new-issue@example.com>\r\n" +
+		"Content-Type: multipart/mixed; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nThe build fails.\r\n" +
+		"--b\r\nContent-Type: text/plain\r\nContent-Disposition: attachment; filename=build.log\r\n\r\nerror\r\n" +
+		"--b--\r\n"
+	assert.NoError(t, ReceiveEmail(strings.NewReader(email)))
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, Title: "Opened by email"}).(*models.Issue)
+	assert.EqualValues(t, 2, issue.PosterID)
+	assert.Equal(t, "The build fails.", issue.Content)
+	models.AssertExistsAndLoadBean(t, &models.Attachment{IssueID: issue.ID, Name: "build.log"})
+
+	setting.Attachment.AllowedTypes = "image/png"
+	err := ReceiveEmail(strings.NewReader(strings.Replace(email, "Opened by email", "Rejected attachment", 1)))
+	assert.True(t, IsErrRejected(err))
+	models.AssertNotExistsBean(t, &models.Issue{RepoID: 1, Title: "Rejected attachment"})
+}
+
+func TestReceiveEmail_PatchReply(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSetting, oldDomain := setting.IncomingEmail, setting.Domain
+	defer func() {
+		setting.IncomingEmail, setting.Domain = oldSetting, oldDomain
+	}()
+	setting.Domain = "localhost"
+	setting.IncomingEmail.Enabled = true
+	setting.IncomingEmail.Address = "incoming@localhost"
+	setting.IncomingEmail.MaxEmailSize = 1
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	pr.HeadBranch = PatchBranchPrefix + "0123456789ab"
+	assert.NoError(t, pr.UpdateCols("head_branch"))
+
+	// user4 can read repo1 but neither opened the pull request nor can write to the repository
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	email := "From: User Four <user4@example.com>\r\n" +
+		"To: " + repo.IncomingSubmissionAddress(user) + "\r\n" +
+		"In-Reply-To: <user2/repo1/pulls/3@localhost>\r\n" +
+		testPatch[strings.Index(testPatch, "Subject:"):]
+	err := ReceiveEmail(strings.NewReader(email))
+	assert.True(t, IsErrRejected(err))
+	assert.Contains(t, err.Error(), "cannot add patches")
+}
+
+func TestIsAuthenticatedSender(t *testing.T) {
+	oldSetting := setting.IncomingEmail
+	defer func() {
+		setting.IncomingEmail = oldSetting
+	}()
+	setting.IncomingEmail.TrustedAuthservIDs = []string{"mx.example.com"}
+
+	authservID, results := parseAuthenticationResults(`mx.example.com 1; dkim=pass (2048-bit key) header.d=example.org header.s="sel"; dmarc=fail header.from=example.org`)
+	assert.Equal(t, "mx.example.com", authservID)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "dkim", results[0].Method)
+		assert.Equal(t, "pass", results[0].Result)
+		assert.Equal(t, "sel", results[0].Properties["header.s"])
+		assert.Equal(t, "fail", results[1].Result)
+	}
+
+	for value, expected := range map[string]bool{
+		"mx.example.com; dkim=pass header.d=example.org header.i=User@Example.org":  true,
+		"mx.example.com; dkim=pass header.d=example.org header.i=other@example.org": false,
+		"mx.example.com; dkim=pass header.d=example.org header.i=@example.org":      false,
+		"mx.example.com; dkim=pass header.d=example.org":                            false,
+		"mx.example.com; dkim=fail header.d=example.org header.i=user@example.org":  false,
+		"mx.example.com; dmarc=pass header.from=example.org":                        false,
+		"mx.example.com; spf=pass smtp.mailfrom=user@example.org":                   false,
+		"mx.example.com; none": false,
+		"mx.example.net; dkim=pass header.d=example.org header.i=user@example.org": false,
+	} {
+		header := mail.Header{"Authentication-Results": []string{value}}
+		assert.Equal(t, expected, isAuthenticatedSender(header, "user@example.org"), value)
+	}
+}
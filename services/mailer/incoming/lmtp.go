@@ -0,0 +1,205 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// lmtpTimeout is the time a LMTP client has to send its next command
+const lmtpTimeout = 5 * time.Minute
+
+// Init starts the LMTP server receiving the emails sent to the incoming address if it is configured
+func Init() error {
+	if !setting.IncomingEmail.Enabled || setting.IncomingEmail.LMTPListenAddr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", setting.IncomingEmail.LMTPListenAddr)
+	if err != nil {
+		return err
+	}
+	log.Info("LMTP server listening on %s", listener.Addr())
+
+	go graceful.GetManager().RunWithShutdownContext(func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			_ = listener.Close()
+		}()
+		serveLMTP(ctx, listener)
+	})
+	return nil
+}
+
+func serveLMTP(ctx context.Context, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				log.Warn("Temporary error accepting LMTP connection: %v", err)
+				time.Sleep(time.Second)
+				continue
+			}
+			log.Error("Unable to accept LMTP connection: %v", err)
+			return
+		}
+		go handleLMTPConn(conn)
+	}
+}
+
+// handleLMTPConn handles a LMTP session as described in RFC 2033,
+// each email is received once per recipient so that the recipient selects the repository
+func handleLMTPConn(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	reply := func(format string, args ...interface{}) bool {
+		if err := tp.PrintfLine(format, args...); err != nil {
+			log.Debug("Unable to reply to LMTP client %s: %v", conn.RemoteAddr(), err)
+			return false
+		}
+		return true
+	}
+
+	var hasSender bool
+	var recipients []string
+	if !reply("220 %s LMTP Gitea ready", setting.Domain) {
+		return
+	}
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(lmtpTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg := line, ""
+		if idx := strings.IndexByte(line, ' '); idx >= 0 {
+			cmd, arg = line[:idx], strings.TrimSpace(line[idx+1:])
+		}
+
+		ok := true
+		switch strings.ToUpper(cmd) {
+		case "LHLO":
+			hasSender, recipients = false, nil
+			ok = reply("250-%s", setting.Domain) &&
+				reply("250-8BITMIME") &&
+				reply("250-ENHANCEDSTATUSCODES") &&
+				reply("250 SIZE %d", setting.IncomingEmail.MaxEmailSize*1024*1024)
+		case "MAIL":
+			if !strings.HasPrefix(strings.ToUpper(arg), "FROM:") {
+				ok = reply("501 5.5.4 Syntax: MAIL FROM:<address>")
+				break
+			}
+			hasSender, recipients = true, nil
+			ok = reply("250 2.1.0 OK")
+		case "RCPT":
+			if !hasSender {
+				ok = reply("503 5.5.1 MAIL first")
+				break
+			}
+			if !strings.HasPrefix(strings.ToUpper(arg), "TO:") {
+				ok = reply("501 5.5.4 Syntax: RCPT TO:<address>")
+				break
+			}
+			recipients = append(recipients, parsePath(arg[len("TO:"):]))
+			ok = reply("250 2.1.5 OK")
+		case "DATA":
+			if len(recipients) == 0 {
+				ok = reply("503 5.5.1 RCPT first")
+				break
+			}
+			if !reply("354 Start mail input; end with <CRLF>.<CRLF>") {
+				return
+			}
+			ok = receiveLMTPData(tp, recipients, reply)
+			hasSender, recipients = false, nil
+		case "RSET":
+			hasSender, recipients = false, nil
+			ok = reply("250 2.0.0 OK")
+		case "NOOP":
+			ok = reply("250 2.0.0 OK")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			ok = reply("500 5.5.2 Unknown command")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// receiveLMTPData reads an email and replies with the result of its delivery to each recipient
+func receiveLMTPData(tp *textproto.Conn, recipients []string, reply func(string, ...interface{}) bool) bool {
+	maxSize := setting.IncomingEmail.MaxEmailSize * 1024 * 1024
+	dr := tp.DotReader()
+	data, err := ioutil.ReadAll(io.LimitReader(dr, maxSize+1))
+	if err != nil {
+		return false
+	}
+	// the rest of an email too large has to be read before replying
+	if _, err := io.Copy(ioutil.Discard, dr); err != nil {
+		return false
+	}
+
+	for _, rcpt := range recipients {
+		if int64(len(data)) > maxSize {
+			if !reply("552 5.3.4 Message too big for system") {
+				return false
+			}
+			continue
+		}
+
+		err := receiveEmail(bytes.NewReader(data), rcpt)
+		switch {
+		case err == nil:
+			if !reply("250 2.0.0 <%s> Delivered", rcpt) {
+				return false
+			}
+		case IsErrRejected(err):
+			log.Debug("Email to %s rejected: %v", rcpt, err)
+			if !reply("550 5.7.1 <%s> %s", rcpt, strings.Join(strings.Fields(err.(ErrRejected).Reason), " ")) {
+				return false
+			}
+		default:
+			log.Error("Unable to receive email to %s: %v", rcpt, err)
+			if !reply("451 4.3.0 <%s> Internal error", rcpt) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parsePath returns the address of a path argument like "<user@example.com> SIZE=123"
+func parsePath(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, "<") {
+		if end := strings.IndexByte(arg, '>'); end >= 0 {
+			return arg[1:end]
+		}
+	}
+	if idx := strings.IndexByte(arg, ' '); idx >= 0 {
+		arg = arg[:idx]
+	}
+	return arg
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"net"
+	"net/textproto"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLMTPSession(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSetting, oldDomain := setting.IncomingEmail, setting.Domain
+	defer func() {
+		setting.IncomingEmail, setting.Domain = oldSetting, oldDomain
+	}()
+	setting.Domain = "localhost"
+	setting.IncomingEmail.Enabled = true
+	setting.IncomingEmail.Address = "incoming@localhost"
+	setting.IncomingEmail.MaxEmailSize = 1
+	setting.IncomingEmail.TrustedAuthservIDs = []string{"mx.localhost"}
+
+	server, client := net.Pipe()
+	go handleLMTPConn(server)
+	tp := textproto.NewConn(client)
+	defer tp.Close()
+
+	expect := func(code int, cmd string) {
+		if cmd != "" {
+			assert.NoError(t, tp.PrintfLine("%s", cmd))
+		}
+		_, _, err := tp.ReadResponse(code)
+		assert.NoError(t, err, cmd)
+	}
+
+	expect(220, "")
+	expect(250, "LHLO mx.localhost")
+	expect(503, "RCPT TO:<incoming+user2/repo1@localhost>")
+	expect(250, "MAIL FROM:<user2@example.com>")
+	expect(250, "RCPT TO:<incoming+user2/repo1@localhost>")
+	expect(250, "RCPT TO:<incoming+user2/unknown@localhost>")
+	expect(354, "DATA")
+
+	w := tp.DotWriter()
+	_, err := w.Write([]byte("Authentication-Results: mx.localhost; dkim=pass header.d=example.com header.i=user2@example.com\r\n" +
+		"From: User Two <user2@example.com>\r\n" +
+		"To: incoming+user2/repo1@localhost, incoming+user2/unknown@localhost\r\n" +
+		"Subject: Re: issue1\r\n" +
+		"In-Reply-To: <user2/repo1/issues/1@localhost>\r\n" +
+		"\r\n" +
+		"Replying over LMTP.\r\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// one reply per recipient
+	expect(250, "")
+	expect(550, "")
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, PosterID: 2, Content: "Replying over LMTP."})
+
+	expect(221, "QUIT")
+}
@@ -34,6 +34,7 @@ const (
 	mailAuthActivateEmail  base.TplName = "auth/activate_email"
 	mailAuthResetPassword  base.TplName = "auth/reset_passwd"
 	mailAuthRegisterNotify base.TplName = "auth/register_notify"
+	mailAuthEmailChanged   base.TplName = "auth/email_changed"
 
 	mailNotifyCollaborator base.TplName = "notify/collaborator"
 
@@ -154,6 +155,35 @@ func SendRegisterNotifyMail(u *models.User) {
 	SendAsync(msg)
 }
 
+// SendPrimaryEmailChangedMail notifies the previous primary email address of a user that it has been changed.
+func SendPrimaryEmailChangedMail(u *models.User, oldEmail string) {
+	locale := translation.NewLocale(u.Language)
+
+	data := map[string]interface{}{
+		"DisplayName": u.DisplayName(),
+		"Username":    u.Name,
+		"OldEmail":    oldEmail,
+		"Email":       u.Email,
+		"Language":    locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailAuthEmailChanged), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage([]string{oldEmail}, locale.Tr("mail.email_changed"), content.String())
+	msg.Info = fmt.Sprintf("UID: %d, primary email changed", u.ID)
+
+	SendAsync(msg)
+}
+
 // SendCollaboratorMail sends mail notification to new collaborator.
 func SendCollaboratorMail(u, doer *models.User, repo *models.Repository) {
 	locale := translation.NewLocale(u.Language)
@@ -302,13 +332,12 @@ func composeIssueCommentMessages(ctx *mailCommentContext, lang string, recipient
 func generateAdditionalHeaders(ctx *mailCommentContext, reason string, recipient *models.User) map[string]string {
 	repo := ctx.Issue.Repo
 
-	return map[string]string{
+	headers := map[string]string{
 		// https://datatracker.ietf.org/doc/html/rfc2919
 		"List-ID": fmt.Sprintf("%s <%s.%s.%s>", repo.FullName(), repo.Name, repo.OwnerName, setting.Domain),
 
 		// https://datatracker.ietf.org/doc/html/rfc2369
 		"List-Archive": fmt.Sprintf("<%s>", repo.HTMLURL()),
-		//"List-Post": https://github.com/go-gitea/gitea/pull/13585
 		//"List-Unsubscribe": https://github.com/go-gitea/gitea/issues/10808, https://github.com/go-gitea/gitea/issues/13283
 
 		"X-Gitea-Reason":            reason,
@@ -331,6 +360,14 @@ func generateAdditionalHeaders(ctx *mailCommentContext, reason string, recipient
 		"X-GitLab-Project-Path":       repo.FullName(),
 		"X-GitLab-Issue-IID":          strconv.FormatInt(ctx.Issue.Index, 10),
 	}
+
+	// Replies sent to the reply address of the recipient are added as their comments,
+	// the personal addresses let them reply and post from any address
+	if address := repo.IncomingSubmissionAddress(recipient); address != "" {
+		headers["List-Post"] = fmt.Sprintf("<mailto:%s>", address)
+		headers["Reply-To"] = repo.IncomingReplyAddress(recipient, ctx.Issue.Index)
+	}
+	return headers
 }
 
 func sanitizeSubject(subject string) string {
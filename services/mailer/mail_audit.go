@@ -0,0 +1,86 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+	"code.gitea.io/gitea/services/audit"
+)
+
+const mailNotifyEndpointAudit base.TplName = "notify/endpoint_audit"
+
+// SendEndpointAuditMail notifies the owner of configurations about the endpoints reported by the endpoint audit,
+// the owners team is notified for an organization
+func SendEndpointAuditMail(owner *models.User, findings []*audit.Finding) error {
+	langMap, err := getOwnerEmailsByLang(owner)
+	if err != nil {
+		return err
+	}
+
+	for lang, tos := range langMap {
+		if err := sendEndpointAuditMailPerLang(lang, owner, tos, findings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOwnerEmailsByLang returns the emails of the owner by language, those of the owners team for an organization
+func getOwnerEmailsByLang(owner *models.User) (map[string][]string, error) {
+	recipients := []*models.User{owner}
+	if owner.IsOrganization() {
+		team, err := owner.GetOwnerTeam()
+		if err != nil {
+			return nil, err
+		}
+		if recipients, err = models.GetTeamMembers(team.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		if user.Email == "" || !user.IsActive || user.ProhibitLogin {
+			continue
+		}
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+	return langMap, nil
+}
+
+func sendEndpointAuditMailPerLang(lang string, owner *models.User, emails []string, findings []*audit.Finding) error {
+	var (
+		locale  = translation.NewLocale(lang)
+		content bytes.Buffer
+	)
+
+	subject := locale.Tr("mail.endpoint_audit.subject", owner.DisplayName())
+	data := map[string]interface{}{
+		"Owner":    owner,
+		"Findings": findings,
+		"Subject":  subject,
+		"Language": locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyEndpointAudit), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage(emails, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, endpoint audit notification", owner.ID)
+
+	SendAsync(msg)
+	return nil
+}
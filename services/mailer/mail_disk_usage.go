@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const mailNotifyDiskUsageAlert base.TplName = "notify/disk_usage_alert"
+
+// SendDiskUsageAlertMail notifies the owner that its repositories grew above the disk usage alert threshold,
+// the owners team is notified for an organization
+func SendDiskUsageAlertMail(owner *models.User, size, threshold int64) error {
+	langMap, err := getOwnerEmailsByLang(owner)
+	if err != nil {
+		return err
+	}
+
+	for lang, tos := range langMap {
+		if err := sendDiskUsageAlertMailPerLang(lang, owner, tos, size, threshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendDiskUsageAlertMailPerLang(lang string, owner *models.User, emails []string, size, threshold int64) error {
+	var (
+		locale  = translation.NewLocale(lang)
+		content bytes.Buffer
+	)
+
+	subject := locale.Tr("mail.disk_usage_alert.subject", owner.DisplayName())
+	data := map[string]interface{}{
+		"Owner":     owner,
+		"Size":      base.FileSize(size),
+		"Threshold": base.FileSize(threshold),
+		"Subject":   subject,
+		"Language":  locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyDiskUsageAlert), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage(emails, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, disk usage alert", owner.ID)
+
+	SendAsync(msg)
+	return nil
+}
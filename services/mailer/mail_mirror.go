@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const mailNotifyMirrorSyncFailed base.TplName = "notify/mirror_sync_failed"
+
+// SendMirrorSyncFailedMail notifies the owner and the admin collaborators of a pull mirror that its syncs keep failing,
+// the owners team is notified for an organization
+func SendMirrorSyncFailedMail(m *models.Mirror) error {
+	repo := m.Repo
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	langMap, err := getOwnerEmailsByLang(repo.Owner)
+	if err != nil {
+		return err
+	}
+
+	collaborators, err := repo.GetCollaborators(models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, emails := range langMap {
+		for _, email := range emails {
+			seen[email] = true
+		}
+	}
+	for _, c := range collaborators {
+		if c.Collaboration.Mode < models.AccessModeAdmin || c.Email == "" || !c.IsActive || c.ProhibitLogin || seen[c.Email] {
+			continue
+		}
+		seen[c.Email] = true
+		langMap[c.Language] = append(langMap[c.Language], c.Email)
+	}
+
+	for lang, tos := range langMap {
+		if err := sendMirrorSyncFailedMailPerLang(lang, tos, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendMirrorSyncFailedMailPerLang(lang string, emails []string, m *models.Mirror) error {
+	var (
+		locale  = translation.NewLocale(lang)
+		content bytes.Buffer
+	)
+
+	subject := locale.Tr("mail.mirror_sync_failed.subject", m.Repo.FullName())
+	data := map[string]interface{}{
+		"Repo":     m.Repo,
+		"Mirror":   m,
+		"Link":     m.Repo.HTMLURL() + "/settings",
+		"Subject":  subject,
+		"Language": locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyMirrorSyncFailed), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage(emails, subject, content.String())
+	msg.Info = fmt.Sprintf("Repo: %d, mirror sync failed notification", m.RepoID)
+
+	SendAsync(msg)
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const mailNotifyWebhookDisabled base.TplName = "notify/webhook_disabled"
+
+// SendWebhookDisabledMail notifies the owner of a webhook that it has been disabled because its deliveries kept failing,
+// the owners team is notified for an organization
+func SendWebhookDisabledMail(owner *models.User, w *models.Webhook, link string) error {
+	langMap, err := getOwnerEmailsByLang(owner)
+	if err != nil {
+		return err
+	}
+
+	for lang, tos := range langMap {
+		if err := sendWebhookDisabledMailPerLang(lang, owner, tos, w, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendWebhookDisabledMailPerLang(lang string, owner *models.User, emails []string, w *models.Webhook, link string) error {
+	var (
+		locale  = translation.NewLocale(lang)
+		content bytes.Buffer
+	)
+
+	subject := locale.Tr("mail.webhook_disabled.subject", owner.DisplayName())
+	data := map[string]interface{}{
+		"Owner":    owner,
+		"Webhook":  w,
+		"Link":     link,
+		"Subject":  subject,
+		"Language": locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyWebhookDisabled), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage(emails, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, webhook %d disabled notification", owner.ID, w.ID)
+
+	SendAsync(msg)
+	return nil
+}
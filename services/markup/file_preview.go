@@ -0,0 +1,133 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	gohtml "html"
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// maxPreviewLines is the max number of lines shown in a file preview
+const maxPreviewLines = 50
+
+// Init sets the markup hooks which need to read repositories
+func Init() {
+	markup.RenderFilePreview = RenderFilePreview
+}
+
+// canPreviewRepo returns true if the code of the repository can be shown in the rendering context. As the reader
+// is unknown, only the code of public repositories and of the repository the content belongs to is shown.
+func canPreviewRepo(ctx *markup.RenderContext, repo *models.Repository) (bool, error) {
+	if !repo.UnitEnabled(models.UnitTypeCode) {
+		return false, nil
+	}
+	if strings.EqualFold(ctx.Metas["user"], repo.OwnerName) && strings.EqualFold(ctx.Metas["repo"], repo.Name) {
+		return true, nil
+	}
+	if err := repo.GetOwner(); err != nil {
+		return false, err
+	}
+	return !repo.IsPrivate && repo.Owner.Visibility.IsPublic(), nil
+}
+
+// RenderFilePreview renders the lines of a file at a commit as a syntax highlighted table
+func RenderFilePreview(ctx *markup.RenderContext, opts markup.FilePreviewOptions) (string, error) {
+	repo, err := models.GetRepositoryByOwnerAndName(opts.OwnerName, opts.RepoName)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if ok, err := canPreviewRepo(ctx, repo); err != nil || !ok {
+		return "", err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(opts.CommitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	entry, err := commit.GetTreeEntryByPath(opts.FilePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return "", nil
+	}
+	if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+		return "", nil
+	}
+
+	rd, err := entry.Blob().DataAsync()
+	if err != nil {
+		return "", err
+	}
+	content, err := ioutil.ReadAll(rd)
+	rd.Close()
+	if err != nil {
+		return "", err
+	}
+	if !typesniffer.DetectContentType(content).IsText() {
+		return "", nil
+	}
+
+	numLines := bytes.Count(content, []byte{'\n'})
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		numLines++
+	}
+	if opts.LineStart > numLines {
+		return "", nil
+	}
+	if opts.LineEnd > numLines {
+		opts.LineEnd = numLines
+	}
+	if opts.LineEnd-opts.LineStart >= maxPreviewLines {
+		opts.LineEnd = opts.LineStart + maxPreviewLines - 1
+	}
+	lines := highlight.File(numLines, opts.FilePath, content)
+
+	fileLink := fmt.Sprintf("%s/src/commit/%s/%s", repo.HTMLURL(), opts.CommitID, util.PathEscapeSegments(opts.FilePath))
+	lineRange := fmt.Sprintf("L%d", opts.LineStart)
+	if opts.LineEnd != opts.LineStart {
+		lineRange += fmt.Sprintf("-L%d", opts.LineEnd)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<div class="file-preview-box"><div class="file-preview-header">`)
+	fmt.Fprintf(&sb, `<a href="%s#%s">%s</a> `, gohtml.EscapeString(fileLink), lineRange,
+		gohtml.EscapeString(repo.FullName()+"/"+opts.FilePath))
+	fmt.Fprintf(&sb, `<span>%s</span> <a href="%s"><code>%s</code></a>`, lineRange,
+		gohtml.EscapeString(repo.HTMLURL()+"/commit/"+opts.CommitID), base.ShortSha(opts.CommitID))
+	sb.WriteString(`</div><table class="file-preview chroma"><tbody>`)
+	for line := opts.LineStart; line <= opts.LineEnd; line++ {
+		fmt.Fprintf(&sb, `<tr><td class="lines-num">%d</td><td class="lines-code"><code>%s</code></td></tr>`, line, lines[line])
+	}
+	sb.WriteString(`</tbody></table></div>`)
+	return sb.String(), nil
+}
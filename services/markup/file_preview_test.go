@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", ".."))
+}
+
+func TestRenderFilePreview(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	setting.Cfg = ini.Empty()
+
+	ctx := &markup.RenderContext{Metas: map[string]string{"user": "user3", "repo": "repo3"}}
+	opts := markup.FilePreviewOptions{
+		OwnerName: "user2",
+		RepoName:  "repo1",
+		CommitID:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		FilePath:  "README.md",
+		LineStart: 1,
+		LineEnd:   10,
+	}
+	preview, err := RenderFilePreview(ctx, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, preview, `<a href="https://try.gitea.io/user2/repo1/src/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md#L1-L3">user2/repo1/README.md</a>`)
+	assert.Contains(t, preview, `<td class="lines-num">3</td>`)
+	assert.NotContains(t, preview, `<td class="lines-num">4</td>`)
+
+	// lines out of the file
+	opts.LineStart, opts.LineEnd = 4, 4
+	preview, err = RenderFilePreview(ctx, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, preview)
+
+	// missing file
+	opts.LineStart, opts.LineEnd, opts.FilePath = 1, 1, "missing.md"
+	preview, err = RenderFilePreview(ctx, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, preview)
+
+	// private repository referenced from another repository
+	opts.RepoName, opts.FilePath = "repo2", "README.md"
+	preview, err = RenderFilePreview(ctx, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, preview)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/graceful"
@@ -24,6 +25,8 @@ var mirrorQueue = sync.NewUniqueQueue(setting.Repository.MirrorQueueLength)
 func Update(ctx context.Context) error {
 	log.Trace("Doing: Update")
 
+	now := time.Now()
+
 	handler := func(idx int, bean interface{}) error {
 		var item string
 		if m, ok := bean.(*models.Mirror); ok {
@@ -31,6 +34,10 @@ func Update(ctx context.Context) error {
 				log.Error("Disconnected mirror found: %d", m.ID)
 				return nil
 			}
+			if !m.InSyncWindow(now) {
+				log.Trace("Mirror %-v is outside of its sync windows", m.Repo)
+				return nil
+			}
 			item = fmt.Sprintf("pull %d", m.RepoID)
 		} else if m, ok := bean.(*models.PushMirror); ok {
 			if m.Repo == nil {
@@ -89,6 +96,7 @@ func syncMirrors(ctx context.Context) {
 
 // InitSyncMirrors initializes a go routine to sync the mirrors
 func InitSyncMirrors() {
+	initBandwidthLimiter()
 	go graceful.GetManager().RunWithShutdownContext(syncMirrors)
 }
 
@@ -15,11 +15,14 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/proxy"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
 )
 
 // gitShortEmptySha Git short empty SHA
@@ -158,11 +161,17 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 		log.Error("GetRemoteAddress Error %v", remoteErr)
 	}
 
+	env := proxy.EnvWithProxy(remoteAddr)
+	if throttle := startSyncThrottle(ctx, m, remoteAddr); throttle != nil {
+		defer throttle.Close()
+		env = throttle.Env()
+	}
+
 	stdoutBuilder := strings.Builder{}
 	stderrBuilder := strings.Builder{}
 	if err := git.NewCommand(gitArgs...).
 		SetDescription(fmt.Sprintf("Mirror.runSync: %s", m.Repo.FullName())).
-		RunInDirTimeoutPipeline(timeout, repoPath, &stdoutBuilder, &stderrBuilder); err != nil {
+		RunInDirTimeoutEnvPipeline(env, timeout, repoPath, &stdoutBuilder, &stderrBuilder); err != nil {
 		stdout := stdoutBuilder.String()
 		stderr := stderrBuilder.String()
 
@@ -178,6 +187,7 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 		if err = models.CreateRepositoryNotice(desc); err != nil {
 			log.Error("CreateRepositoryNotice: %v", err)
 		}
+		m.LastError = lastErrorMessage(stderrMessage, "git remote update failed")
 		return nil, false
 	}
 	output := stderrBuilder.String()
@@ -185,6 +195,7 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 	gitRepo, err := git.OpenRepository(repoPath)
 	if err != nil {
 		log.Error("OpenRepository: %v", err)
+		m.LastError = "unable to open the repository"
 		return nil, false
 	}
 
@@ -213,7 +224,7 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 		stdoutBuilder.Reset()
 		if err := git.NewCommand("remote", "update", "--prune", m.GetRemoteName()).
 			SetDescription(fmt.Sprintf("Mirror.runSync Wiki: %s ", m.Repo.FullName())).
-			RunInDirTimeoutPipeline(timeout, wikiPath, &stdoutBuilder, &stderrBuilder); err != nil {
+			RunInDirTimeoutEnvPipeline(env, timeout, wikiPath, &stdoutBuilder, &stderrBuilder); err != nil {
 			stdout := stdoutBuilder.String()
 			stderr := stderrBuilder.String()
 
@@ -234,6 +245,7 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 			if err = models.CreateRepositoryNotice(desc); err != nil {
 				log.Error("CreateRepositoryNotice: %v", err)
 			}
+			m.LastError = lastErrorMessage(stderrMessage, "git remote update of the wiki failed")
 			return nil, false
 		}
 		log.Trace("SyncMirrors [repo: %-v Wiki]: git remote update complete", m.Repo)
@@ -243,6 +255,7 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 	branches, _, err := repo_module.GetBranches(m.Repo, 0, 0)
 	if err != nil {
 		log.Error("GetBranches: %v", err)
+		m.LastError = "unable to list the branches"
 		return nil, false
 	}
 
@@ -251,9 +264,30 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 	}
 
 	m.UpdatedUnix = timeutil.TimeStampNow()
+	m.LastError = ""
+	m.LastErrorUnix = 0
+	m.FailureCount = 0
 	return parseRemoteUpdateOutput(output), true
 }
 
+// lastErrorMessage returns the last line of the sanitized git output to be shown to the users,
+// which usually contains the reason of the failure
+func lastErrorMessage(stderr, fallback string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if msg := strings.TrimSpace(lines[len(lines)-1]); msg != "" {
+		return msg
+	}
+	return fallback
+}
+
+// recordSyncFailure records a failed sync of the mirror and returns true if its administrators
+// should be notified because it has failed too many times in a row
+func recordSyncFailure(m *models.Mirror) bool {
+	m.LastErrorUnix = timeutil.TimeStampNow()
+	m.FailureCount++
+	return setting.Mirror.NotifyAfterFailures > 0 && m.FailureCount == setting.Mirror.NotifyAfterFailures
+}
+
 // SyncPullMirror starts the sync of the pull mirror and schedules the next run.
 func SyncPullMirror(ctx context.Context, repoID int64) bool {
 	log.Trace("SyncMirrors [repo_id: %v]", repoID)
@@ -273,8 +307,22 @@ func SyncPullMirror(ctx context.Context, repoID int64) bool {
 	}
 
 	log.Trace("SyncMirrors [repo: %-v]: Running Sync", m.Repo)
+	start := time.Now()
 	results, ok := runSync(ctx, m)
+	metrics.ObserveMirrorSync(start, ok)
 	if !ok {
+		// Keep the failure visible and retry at the next scheduled run instead of every cron run
+		notify := recordSyncFailure(m)
+		m.ScheduleNextUpdate()
+		if err = models.UpdateMirror(m); err != nil {
+			log.Error("UpdateMirror [%d]: %v", m.RepoID, err)
+			return false
+		}
+		if notify && setting.MailService != nil {
+			if err := mailer.SendMirrorSyncFailedMail(m); err != nil {
+				log.Error("SendMirrorSyncFailedMail [%d]: %v", m.RepoID, err)
+			}
+		}
 		return false
 	}
 
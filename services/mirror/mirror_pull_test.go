@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordSyncFailure(t *testing.T) {
+	defer func(n int) { setting.Mirror.NotifyAfterFailures = n }(setting.Mirror.NotifyAfterFailures)
+
+	m := &models.Mirror{}
+	setting.Mirror.NotifyAfterFailures = 0
+	assert.False(t, recordSyncFailure(m))
+	assert.EqualValues(t, 1, m.FailureCount)
+	assert.NotZero(t, m.LastErrorUnix)
+
+	setting.Mirror.NotifyAfterFailures = 3
+	assert.False(t, recordSyncFailure(m))
+	assert.True(t, recordSyncFailure(m))
+	assert.EqualValues(t, 3, m.FailureCount)
+	// The administrators are only notified once until a sync succeeds again
+	assert.False(t, recordSyncFailure(m))
+}
+
+func TestLastErrorMessage(t *testing.T) {
+	assert.Equal(t, "fatal: repository not found", lastErrorMessage("remote: Not Found\nfatal: repository not found\n", "fallback"))
+	assert.Equal(t, "fallback", lastErrorMessage("  \n", "fallback"))
+}
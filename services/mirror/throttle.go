@@ -0,0 +1,299 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+
+	"golang.org/x/time/rate"
+)
+
+// globalLimiter throttles the total bandwidth of the pull mirror syncs, it is nil if unlimited
+var globalLimiter *rate.Limiter
+
+func initBandwidthLimiter() {
+	if setting.Mirror.MaxBandwidth > 0 {
+		globalLimiter = newBandwidthLimiter(setting.Mirror.MaxBandwidth)
+	}
+}
+
+// newBandwidthLimiter returns a limiter allowing bytesPerSecond, with bursts of at most one second of traffic
+func newBandwidthLimiter(bytesPerSecond int64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+}
+
+// throttledConn is a connection whose reads and writes wait for the limiters
+type throttledConn struct {
+	net.Conn
+	ctx      context.Context
+	limiters []*rate.Limiter
+}
+
+// chunk returns the start of b that the limiters can allow at once
+func (c *throttledConn) chunk(b []byte) []byte {
+	for _, l := range c.limiters {
+		if l.Burst() < len(b) {
+			b = b[:l.Burst()]
+		}
+	}
+	return b
+}
+
+func (c *throttledConn) wait(n int) error {
+	for _, l := range c.limiters {
+		if err := l.WaitN(c.ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *throttledConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(c.chunk(b))
+	if n > 0 {
+		if waitErr := c.wait(n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
+
+func (c *throttledConn) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		chunk := c.chunk(b)
+		if err := c.wait(len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := c.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+// bufferedConn is a connection whose first bytes were already read into a bufio.Reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// throttlingProxy is a local HTTP proxy throttling the bandwidth of the git commands of a sync,
+// the connections go through the configured proxy if there is one
+type throttlingProxy struct {
+	ctx       context.Context
+	limiters  []*rate.Limiter
+	dialer    *net.Dialer
+	listener  net.Listener
+	server    *http.Server
+	transport *http.Transport
+}
+
+func startThrottlingProxy(ctx context.Context, limiters []*rate.Limiter) (*throttlingProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &throttlingProxy{
+		ctx:      ctx,
+		limiters: limiters,
+		dialer:   &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+		listener: listener,
+	}
+	p.transport = &http.Transport{
+		Proxy:       proxy.Proxy(),
+		DialContext: p.dial,
+	}
+	p.server = &http.Server{Handler: p}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Throttling proxy on %s failed: %v", listener.Addr(), err)
+		}
+	}()
+	return p, nil
+}
+
+// URL returns the URL of the proxy to hand to git
+func (p *throttlingProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Env returns the environment for git commands going through the proxy
+func (p *throttlingProxy) Env() []string {
+	// the proxy is on the loopback interface which may be excluded by no_proxy
+	return append(os.Environ(), "http_proxy="+p.URL(), "https_proxy="+p.URL(), "no_proxy=", "NO_PROXY=")
+}
+
+// Close stops the proxy and closes its connections
+func (p *throttlingProxy) Close() {
+	_ = p.server.Close()
+	p.transport.CloseIdleConnections()
+}
+
+func (p *throttlingProxy) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := p.dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledConn{Conn: conn, ctx: p.ctx, limiters: p.limiters}, nil
+}
+
+// dialTunnel connects to host, through a CONNECT request to the configured proxy if it applies
+func (p *throttlingProxy) dialTunnel(ctx context.Context, host string) (net.Conn, error) {
+	proxyURL, err := p.transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: host}})
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return p.dial(ctx, "tcp", host)
+	}
+
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		switch proxyURL.Scheme {
+		case "http":
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		case "https":
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		}
+	}
+	conn, err := p.dial(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http":
+	case "https":
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: host},
+		Host:   host,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username()+":"+password)))
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy responded to CONNECT with %s", resp.Status)
+	}
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+func (p *throttlingProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		// plain HTTP requests carry the absolute URL of the remote
+		(&httputil.ReverseProxy{
+			Director:  func(*http.Request) {},
+			Transport: p.transport,
+		}).ServeHTTP(w, r)
+		return
+	}
+
+	upstream, err := p.dialTunnel(r.Context(), r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buf, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		log.Error("Hijack: %v", err)
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, buf)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+	client.Close()
+	upstream.Close()
+	<-done
+}
+
+// startSyncThrottle starts a throttling proxy for the sync of the mirror from remoteAddr.
+// It returns nil if the bandwidth is unlimited or git does not connect to the remote over HTTP.
+func startSyncThrottle(ctx context.Context, m *models.Mirror, remoteAddr *url.URL) *throttlingProxy {
+	if remoteAddr == nil || !(strings.EqualFold(remoteAddr.Scheme, "http") || strings.EqualFold(remoteAddr.Scheme, "https")) {
+		return nil
+	}
+
+	limiters := make([]*rate.Limiter, 0, 2)
+	if m.MaxBandwidth > 0 {
+		limiters = append(limiters, newBandwidthLimiter(m.MaxBandwidth))
+	}
+	if globalLimiter != nil {
+		limiters = append(limiters, globalLimiter)
+	}
+	if len(limiters) == 0 {
+		return nil
+	}
+
+	p, err := startThrottlingProxy(ctx, limiters)
+	if err != nil {
+		log.Error("Unable to start the throttling proxy for mirror %-v, syncing without throttling: %v", m.Repo, err)
+		return nil
+	}
+	return p
+}
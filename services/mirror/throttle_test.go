@@ -0,0 +1,54 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestThrottlingProxy(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1024)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	})
+
+	for _, server := range []*httptest.Server{httptest.NewServer(handler), httptest.NewTLSServer(handler)} {
+		func() {
+			defer server.Close()
+
+			// the first 8 KiB are allowed at once, the others take a second
+			p, err := startThrottlingProxy(context.Background(), []*rate.Limiter{newBandwidthLimiter(8 * 1024)})
+			assert.NoError(t, err)
+			defer p.Close()
+
+			proxyURL, err := url.Parse(p.URL())
+			assert.NoError(t, err)
+			transport := server.Client().Transport.(*http.Transport).Clone()
+			transport.Proxy = http.ProxyURL(proxyURL)
+			client := &http.Client{Transport: transport}
+
+			start := time.Now()
+			resp, err := client.Get(server.URL)
+			if !assert.NoError(t, err, server.URL) {
+				return
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, content, body)
+			assert.True(t, time.Since(start) > 900*time.Millisecond, "%s was not throttled", server.URL)
+		}()
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repository"
+)
+
+type pagesNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &pagesNotifier{}
+)
+
+// deployOnPush queues the deployment of the site of the repository when its branch is pushed
+func deployOnPush(repo *models.Repository, opts *repository.PushUpdateOptions) {
+	if !opts.IsBranch() || opts.IsDelRef() {
+		return
+	}
+
+	p, err := models.GetRepoPages(repo.ID)
+	if err != nil {
+		if !models.IsErrRepoPagesNotExist(err) {
+			log.Error("GetRepoPages(%d): %v", repo.ID, err)
+		}
+		return
+	}
+	if p.Branch != opts.BranchName() {
+		return
+	}
+	if err = AddToQueue(p); err != nil {
+		log.Error("AddToQueue(%d): %v", repo.ID, err)
+	}
+}
+
+func (*pagesNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	deployOnPush(repo, opts)
+}
+
+func (*pagesNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	deployOnPush(repo, opts)
+}
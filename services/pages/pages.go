@@ -0,0 +1,284 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// deployQueue represents a queue to handle the deployments of sites
+var deployQueue queue.UniqueQueue
+
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		repoID := datum.(int64)
+		if err := Deploy(repoID); err != nil {
+			log.Error("pages deploy queue Deploy(%d) failed: %v", repoID, err)
+		}
+	}
+}
+
+// Init starts the deploy queue and listens to pushes when pages are enabled
+func Init() error {
+	if !setting.Pages.Enabled {
+		return nil
+	}
+
+	deployQueue = queue.CreateUniqueQueue("pages_deploy", handle, int64(0))
+	if deployQueue == nil {
+		return fmt.Errorf("Unable to create pages_deploy Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(deployQueue.Run)
+
+	notification.RegisterNotifier(&pagesNotifier{})
+	return nil
+}
+
+// AddToQueue marks the site as queued and adds it to the deploy queue
+func AddToQueue(p *models.RepoPages) error {
+	p.Status = models.PagesStatusQueued
+	if err := models.UpdateRepoPagesStatus(p); err != nil {
+		return err
+	}
+	if err := deployQueue.Push(p.RepoID); err != nil {
+		if err != queue.ErrAlreadyInQueue {
+			return err
+		}
+		log.Debug("Pages of repo ID: %d already queued", p.RepoID)
+	}
+	return nil
+}
+
+// Deploy copies the files of the branch of the site of a repository to the directory it is served from
+// and records the outcome, deployment errors caused by the content of the branch are not returned
+func Deploy(repoID int64) error {
+	p, err := models.GetRepoPages(repoID)
+	if err != nil {
+		if models.IsErrRepoPagesNotExist(err) {
+			// pages have been disabled since being queued
+			return nil
+		}
+		return err
+	}
+	if err = p.LoadRepo(); err != nil {
+		return err
+	}
+
+	commitID, size, err := deployBranch(p)
+	if err != nil {
+		log.Warn("Failed to deploy pages of %s: %v", p.Repo.FullName(), err)
+		p.Status = models.PagesStatusFailed
+		p.Message = err.Error()
+	} else {
+		p.Status = models.PagesStatusDeployed
+		p.CommitID = commitID
+		p.Size = size
+		p.Message = ""
+		p.DeployedUnix = timeutil.TimeStampNow()
+	}
+	return models.UpdateRepoPagesStatus(p)
+}
+
+func deployBranch(p *models.RepoPages) (string, int64, error) {
+	gitRepo, err := git.OpenRepository(p.Repo.RepoPath())
+	if err != nil {
+		return "", 0, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(p.Branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", 0, fmt.Errorf("branch %s does not exist", p.Branch)
+		}
+		return "", 0, err
+	}
+	tree := &commit.Tree
+	if folder := strings.Trim(p.Folder, "/"); folder != "" {
+		if tree, err = commit.SubTree(folder); err != nil {
+			if git.IsErrNotExist(err) {
+				return "", 0, fmt.Errorf("folder %s does not exist in branch %s", folder, p.Branch)
+			}
+			return "", 0, err
+		}
+	}
+
+	entries, err := tree.ListEntriesRecursive()
+	if err != nil {
+		return "", 0, err
+	}
+	var size int64
+	for _, entry := range entries {
+		if entry.IsRegular() || entry.IsExecutable() {
+			size += entry.Size()
+		}
+	}
+	if setting.Pages.MaxSize > 0 && size > setting.Pages.MaxSize*1024*1024 {
+		return "", 0, fmt.Errorf("site is larger than %d MB", setting.Pages.MaxSize)
+	}
+
+	if err = os.MkdirAll(setting.Pages.Path, os.ModePerm); err != nil {
+		return "", 0, err
+	}
+	tmpPath, err := ioutil.TempDir(setting.Pages.Path, fmt.Sprintf(".deploy-%d-", p.RepoID))
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() {
+		if err := util.RemoveAll(tmpPath); err != nil {
+			log.Error("Failed to remove %s: %v", tmpPath, err)
+		}
+	}()
+
+	for _, entry := range entries {
+		// symlinks could point outside of the site and submodules have no content
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			continue
+		}
+		if err = writeEntry(tmpPath, entry); err != nil {
+			return "", 0, err
+		}
+	}
+
+	// swap the deployed site for the new one so that it is never served half written
+	sitePath := p.SitePath()
+	oldPath := tmpPath + ".old"
+	if err = os.Rename(sitePath, oldPath); err != nil && !os.IsNotExist(err) {
+		return "", 0, err
+	}
+	if err = os.Rename(tmpPath, sitePath); err != nil {
+		return "", 0, err
+	}
+	if err = util.RemoveAll(oldPath); err != nil {
+		log.Error("Failed to remove %s: %v", oldPath, err)
+	}
+	return commit.ID.String(), size, nil
+}
+
+func writeEntry(basePath string, entry *git.TreeEntry) error {
+	name := path.Clean("/" + entry.Name())
+	if name == "/" {
+		return nil
+	}
+	fullPath := filepath.Join(basePath, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	rd, err := entry.Blob().DataAsync()
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+	f, err := os.Create(fullPath)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, rd); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ServeSite serves the file of the deployed site for the given URL path, directories are served
+// by their index.html and missing files by the 404.html of the site
+func ServeSite(w http.ResponseWriter, req *http.Request, p *models.RepoPages, urlPath string) {
+	if !p.IsDeployed() {
+		http.NotFound(w, req)
+		return
+	}
+
+	name := path.Clean("/" + urlPath)
+	fullPath := filepath.Join(p.SitePath(), filepath.FromSlash(name))
+	if fi, err := os.Stat(fullPath); err == nil && fi.IsDir() {
+		// relative links of the index.html of a folder need the trailing slash
+		if !strings.HasSuffix(req.URL.Path, "/") {
+			http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+		fullPath = filepath.Join(fullPath, "index.html")
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error("Failed to open %s: %v", fullPath, err)
+		}
+		serveNotFound(w, req, p)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		serveNotFound(w, req, p)
+		return
+	}
+	http.ServeContent(w, req, fi.Name(), fi.ModTime(), f)
+}
+
+func serveNotFound(w http.ResponseWriter, req *http.Request, p *models.RepoPages) {
+	f, err := os.Open(filepath.Join(p.SitePath(), "404.html"))
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	if _, err = io.Copy(w, f); err != nil {
+		log.Error("Failed to serve 404 page of pages of repo ID %d: %v", p.RepoID, err)
+	}
+}
+
+// DeleteSite disables the site of a repository and removes its deployed files
+func DeleteSite(repoID int64) error {
+	if err := models.DeleteRepoPages(repoID); err != nil {
+		return err
+	}
+	return util.RemoveAll(models.RepoPagesPath(repoID))
+}
+
+// lookupTXT is replaced in tests
+var lookupTXT = net.LookupTXT
+
+// VerifyDomain marks the custom domain of the site as verified if its DNS TXT verification record is published,
+// it returns false if the record is missing
+func VerifyDomain(p *models.RepoPages) (bool, error) {
+	if p.Domain == "" {
+		return false, nil
+	}
+
+	records, err := lookupTXT(p.DomainVerificationRecord())
+	if err != nil {
+		log.Debug("Unable to look up the verification record of the pages domain %s: %v", p.Domain, err)
+		return false, nil
+	}
+	expected := p.DomainVerificationValue()
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return true, models.VerifyRepoPagesDomain(p)
+		}
+	}
+	return false, nil
+}
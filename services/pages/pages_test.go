@@ -0,0 +1,106 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", ".."))
+}
+
+func TestDeploy(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	pagesPath, err := ioutil.TempDir("", "pages")
+	assert.NoError(t, err)
+	defer util.RemoveAll(pagesPath)
+	oldPath := setting.Pages.Path
+	setting.Pages.Path = pagesPath
+	defer func() {
+		setting.Pages.Path = oldPath
+	}()
+
+	p := &models.RepoPages{RepoID: 1, Branch: "does-not-exist"}
+	assert.NoError(t, models.SaveRepoPages(p))
+	assert.NoError(t, Deploy(1))
+	p = models.AssertExistsAndLoadBean(t, &models.RepoPages{RepoID: 1}).(*models.RepoPages)
+	assert.Equal(t, models.PagesStatusFailed, p.Status)
+	assert.False(t, p.IsDeployed())
+
+	p.Branch = "master"
+	assert.NoError(t, models.SaveRepoPages(p))
+	assert.NoError(t, Deploy(1))
+	p = models.AssertExistsAndLoadBean(t, &models.RepoPages{RepoID: 1}).(*models.RepoPages)
+	assert.Equal(t, models.PagesStatusDeployed, p.Status)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", p.CommitID)
+	exist, err := util.IsFile(filepath.Join(p.SitePath(), "README.md"))
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/README.md", nil)
+	ServeSite(resp, req, p, "README.md")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "repo1")
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/../../README.md", nil)
+	ServeSite(resp, req, p, "../../README.md")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/missing.html", nil)
+	ServeSite(resp, req, p, "missing.html")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	assert.NoError(t, DeleteSite(1))
+	models.AssertNotExistsBean(t, &models.RepoPages{RepoID: 1})
+	exist, err = util.IsExist(p.SitePath())
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func TestVerifyDomain(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	records := map[string][]string{}
+	oldLookupTXT := lookupTXT
+	lookupTXT = func(name string) ([]string, error) {
+		return records[name], nil
+	}
+	defer func() {
+		lookupTXT = oldLookupTXT
+	}()
+
+	p := &models.RepoPages{RepoID: 1, Branch: "gh-pages", Domain: "docs.example.com"}
+	assert.NoError(t, models.SaveRepoPages(p))
+	assert.Equal(t, "_gitea-pages.docs.example.com", p.DomainVerificationRecord())
+
+	records[p.DomainVerificationRecord()] = []string{"v=spf1 -all", "gitea-pages-verification=wrong"}
+	verified, err := VerifyDomain(p)
+	assert.NoError(t, err)
+	assert.False(t, verified)
+	models.AssertExistsAndLoadBean(t, &models.RepoPages{RepoID: 1, DomainVerified: false})
+
+	records[p.DomainVerificationRecord()] = append(records[p.DomainVerificationRecord()], p.DomainVerificationValue())
+	verified, err = VerifyDomain(p)
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	found, err := models.GetRepoPagesByDomain("docs.example.com")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, found.RepoID)
+}
@@ -0,0 +1,200 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// AGitOptions represents the push options of a push to refs/for/<branch>
+type AGitOptions struct {
+	Topic       string
+	Title       string
+	Description string
+}
+
+// ParseAGitRef returns the base branch and the topic of a push to refs/for/<branch>[/<topic>],
+// the base branch is the longest existing branch the ref starts with
+func ParseAGitRef(gitRepo *git.Repository, refFullName, topic string) (baseBranch, refTopic string, err error) {
+	rest := strings.TrimPrefix(refFullName, git.ForPrefix)
+	for candidate := rest; ; {
+		if gitRepo.IsBranchExist(candidate) {
+			baseBranch = candidate
+			refTopic = strings.TrimPrefix(rest[len(candidate):], "/")
+			break
+		}
+		idx := strings.LastIndex(candidate, "/")
+		if idx <= 0 {
+			return "", "", fmt.Errorf("no base branch found for %s", refFullName)
+		}
+		candidate = candidate[:idx]
+	}
+
+	if topic != "" {
+		refTopic = topic
+	}
+	if refTopic == "" {
+		return "", "", fmt.Errorf("no topic set for %s, push to %s%s/<topic> or use -o topic=<topic>", refFullName, git.ForPrefix, baseBranch)
+	}
+	return baseBranch, refTopic, nil
+}
+
+// AGitHeadBranch returns the head branch name of the AGit pull requests pushed by pusher for the topic
+func AGitHeadBranch(pusher *models.User, topic string) string {
+	if strings.HasPrefix(topic, pusher.LowerName+"/") {
+		return topic
+	}
+	return pusher.LowerName + "/" + topic
+}
+
+// PushAGit creates or updates the AGit pull request of a push to refs/for/<branch>, and removes the pushed ref
+func PushAGit(repo *models.Repository, gitRepo *git.Repository, pusher *models.User, refFullName, newCommitID string, opts AGitOptions) (pr *models.PullRequest, created bool, err error) {
+	defer func() {
+		if _, err := git.NewCommand("update-ref", "-d", refFullName).RunInDir(repo.RepoPath()); err != nil {
+			log.Error("Unable to remove %s from %-v: %v", refFullName, repo, err)
+		}
+	}()
+
+	baseBranch, topic, err := ParseAGitRef(gitRepo, refFullName, opts.Topic)
+	if err != nil {
+		return nil, false, err
+	}
+	headBranch := AGitHeadBranch(pusher, topic)
+
+	pr, err = models.GetUnmergedPullRequest(repo.ID, repo.ID, headBranch, baseBranch, models.PullRequestFlowAGit)
+	if err != nil && !models.IsErrPullRequestNotExist(err) {
+		return nil, false, fmt.Errorf("GetUnmergedPullRequest: %v", err)
+	}
+
+	if pr == nil {
+		pr, err = newAGitPullRequest(repo, gitRepo, pusher, headBranch, baseBranch, newCommitID, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		return pr, true, nil
+	}
+
+	if err := pr.LoadIssue(); err != nil {
+		return nil, false, fmt.Errorf("LoadIssue: %v", err)
+	}
+	if pr.Issue.PosterID != pusher.ID {
+		return nil, false, fmt.Errorf("pull request #%d was not pushed by %s", pr.Index, pusher.Name)
+	}
+
+	oldCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, false, fmt.Errorf("GetRefCommitID: %v", err)
+	}
+	if oldCommitID == newCommitID {
+		return pr, false, nil
+	}
+
+	pr.HeadCommitID = newCommitID
+	if err := UpdateRef(pr); err != nil {
+		return nil, false, err
+	}
+	syncAGitPullRequest(pusher, pr, oldCommitID, newCommitID)
+	return pr, false, nil
+}
+
+func newAGitPullRequest(repo *models.Repository, gitRepo *git.Repository, pusher *models.User, headBranch, baseBranch, newCommitID string, opts AGitOptions) (*models.PullRequest, error) {
+	commit, err := gitRepo.GetCommit(newCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("GetCommit: %v", err)
+	}
+
+	title, description := opts.Title, opts.Description
+	if title == "" {
+		title = commit.Summary()
+	}
+	if description == "" {
+		description = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(commit.Message()), commit.Summary()))
+	}
+
+	mergeBase, _, err := gitRepo.GetMergeBase("", git.BranchPrefix+baseBranch, newCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("GetMergeBase: %v", err)
+	}
+
+	prIssue := &models.Issue{
+		RepoID:   repo.ID,
+		Title:    title,
+		PosterID: pusher.ID,
+		Poster:   pusher,
+		IsPull:   true,
+		Content:  description,
+	}
+	pr := &models.PullRequest{
+		HeadRepoID:   repo.ID,
+		BaseRepoID:   repo.ID,
+		HeadBranch:   headBranch,
+		BaseBranch:   baseBranch,
+		HeadRepo:     repo,
+		BaseRepo:     repo,
+		MergeBase:    mergeBase,
+		Type:         models.PullRequestGitea,
+		Flow:         models.PullRequestFlowAGit,
+		HeadCommitID: newCommitID,
+	}
+	if err := NewPullRequest(repo, prIssue, nil, nil, pr, nil); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// syncAGitPullRequest does for an updated AGit pull request what AddTestPullRequestTask does for pushes to head branches
+func syncAGitPullRequest(pusher *models.User, pr *models.PullRequest, oldCommitID, newCommitID string) {
+	if err := pr.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		log.Error("LoadBaseRepo: %v", err)
+		return
+	}
+
+	changed, err := checkIfPRContentChanged(pr, oldCommitID, newCommitID)
+	if err != nil {
+		log.Error("checkIfPRContentChanged: %v", err)
+	}
+	if changed {
+		// Mark old reviews as stale if diff to mergebase has changed
+		if err := models.MarkReviewsAsStale(pr.IssueID); err != nil {
+			log.Error("MarkReviewsAsStale: %v", err)
+		}
+	}
+	if err := models.MarkReviewsAsNotStale(pr.IssueID, newCommitID); err != nil {
+		log.Error("MarkReviewsAsNotStale: %v", err)
+	}
+	if changed {
+		if err := dismissStaleApprovals(pr, pusher); err != nil {
+			log.Error("dismissStaleApprovals: %v", err)
+		}
+	}
+	divergence, err := GetDiverging(pr)
+	if err != nil {
+		log.Error("GetDiverging: %v", err)
+	} else if err := pr.UpdateCommitDivergence(divergence.Ahead, divergence.Behind); err != nil {
+		log.Error("UpdateCommitDivergence: %v", err)
+	}
+
+	pr.Issue.PullRequest = pr
+	notification.NotifyPullRequestSynchronized(pusher, pr)
+
+	AddToTaskQueue(pr)
+	AddToLabelerQueue(pr)
+	AddToCodeOwnersQueue(pr)
+	AddToCLAQueue(pr)
+	comment, err := models.CreatePushPullComment(pusher, pr, oldCommitID, newCommitID)
+	if err == nil && comment != nil {
+		notification.NotifyPullRequestPushCommits(pusher, pr, comment)
+	}
+}
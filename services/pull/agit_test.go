@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAGitRef(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	kases := []struct {
+		ref        string
+		topic      string
+		baseBranch string
+		refTopic   string
+	}{
+		{"refs/for/master/fix-typo", "", "master", "fix-typo"},
+		{"refs/for/master", "fix-typo", "master", "fix-typo"},
+		{"refs/for/master/ignored", "fix-typo", "master", "fix-typo"},
+		{"refs/for/feature/1/some/topic", "", "feature/1", "some/topic"},
+	}
+	for _, kase := range kases {
+		baseBranch, refTopic, err := ParseAGitRef(gitRepo, kase.ref, kase.topic)
+		assert.NoError(t, err, kase.ref)
+		assert.Equal(t, kase.baseBranch, baseBranch, kase.ref)
+		assert.Equal(t, kase.refTopic, refTopic, kase.ref)
+	}
+
+	_, _, err = ParseAGitRef(gitRepo, "refs/for/master", "")
+	assert.Error(t, err)
+	_, _, err = ParseAGitRef(gitRepo, "refs/for/no-such-branch/topic", "")
+	assert.Error(t, err)
+}
+
+func TestAGitHeadBranch(t *testing.T) {
+	user := &models.User{LowerName: "user2"}
+	assert.Equal(t, "user2/fix-typo", AGitHeadBranch(user, "fix-typo"))
+	assert.Equal(t, "user2/fix-typo", AGitHeadBranch(user, "user2/fix-typo"))
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// auditClaims are the claims of the JWS signing an audit bundle
+type auditClaims struct {
+	jwt.StandardClaims
+	Bundle *api.PullAuditBundle `json:"bundle"`
+}
+
+// GetAuditBundle collects the approvals, status checks and commit signatures of a merged pull request
+func GetAuditBundle(gitRepo *git.Repository, pr *models.PullRequest) (*api.PullAuditBundle, error) {
+	if !pr.HasMerged {
+		return nil, fmt.Errorf("pull request %d has not been merged", pr.ID)
+	}
+	if err := pr.LoadAttributes(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return nil, err
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return nil, err
+	}
+	pr.Issue.Repo = pr.BaseRepo
+
+	bundle := &api.PullAuditBundle{
+		Repository:   pr.BaseRepo.FullName(),
+		Index:        pr.Index,
+		Title:        pr.Issue.Title,
+		HTMLURL:      pr.Issue.HTMLURL(),
+		Poster:       pr.Issue.Poster.Name,
+		Base:         pr.BaseBranch,
+		Head:         pr.HeadBranch,
+		MergeBase:    pr.MergeBase,
+		MergedBy:     pr.Merger.Name,
+		Merged:       pr.MergedUnix.AsTime(),
+		Commits:      []*api.PullAuditCommit{},
+		Approvals:    []*api.PullAuditApproval{},
+		StatusChecks: []*api.PullAuditStatusCheck{},
+		Generated:    time.Now().UTC(),
+	}
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID[%s]: %v", pr.GetGitRefName(), err)
+	}
+	bundle.HeadCommitID = headCommitID
+
+	commits, err := gitRepo.CommitsBetweenIDs(headCommitID, pr.MergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetweenIDs: %v", err)
+	}
+	for e := commits.Front(); e != nil; e = e.Next() {
+		bundle.Commits = append(bundle.Commits, toAuditCommit(e.Value.(*git.Commit)))
+	}
+	if pr.MergedCommitID != "" {
+		mergeCommit, err := gitRepo.GetCommit(pr.MergedCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("GetCommit[%s]: %v", pr.MergedCommitID, err)
+		}
+		bundle.MergeCommit = toAuditCommit(mergeCommit)
+	}
+
+	reviews, err := models.FindReviews(models.FindReviewOptions{
+		Type:    models.ReviewTypeApprove,
+		IssueID: pr.IssueID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		if err := review.LoadReviewer(); err != nil {
+			return nil, err
+		}
+		bundle.Approvals = append(bundle.Approvals, &api.PullAuditApproval{
+			Reviewer:  review.Reviewer.Name,
+			CommitID:  review.CommitID,
+			Official:  review.Official,
+			Stale:     review.Stale,
+			Dismissed: review.Dismissed,
+			Submitted: review.UpdatedUnix.AsTime(),
+		})
+	}
+
+	statuses, err := models.GetLatestCommitStatus(pr.BaseRepoID, headCommitID, models.ListOptions{PageSize: setting.API.MaxResponseItems})
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		bundle.StatusChecks = append(bundle.StatusChecks, &api.PullAuditStatusCheck{
+			Context:     status.Context,
+			State:       status.State,
+			Description: status.Description,
+			TargetURL:   status.TargetURL,
+			Updated:     status.UpdatedUnix.AsTime(),
+		})
+	}
+
+	return bundle, nil
+}
+
+func toAuditCommit(c *git.Commit) *api.PullAuditCommit {
+	verification := models.ParseCommitWithSignature(c)
+	commit := &api.PullAuditCommit{
+		SHA:      c.ID.String(),
+		Verified: verification.Verified,
+		Reason:   verification.Reason,
+	}
+	if c.Author != nil {
+		commit.Author = c.Author.Name
+		commit.AuthorEmail = c.Author.Email
+		commit.Authored = c.Author.When
+	}
+	if c.Committer != nil {
+		commit.Committer = c.Committer.Name
+		commit.CommitterEmail = c.Committer.Email
+		commit.Committed = c.Committer.When
+	}
+	if verification.Verified {
+		if verification.SigningUser != nil {
+			commit.Signer = verification.SigningUser.Name
+		}
+		if verification.SigningKey != nil {
+			commit.SignerKey = verification.SigningKey.KeyID
+		}
+	}
+	return commit
+}
+
+// SignAuditBundle signs the audit bundle as a JWS with the given key
+func SignAuditBundle(bundle *api.PullAuditBundle, signingKey oauth2.JWTSigningKey) (string, error) {
+	claims := &auditClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:   setting.AppURL,
+			Subject:  bundle.HTMLURL,
+			IssuedAt: bundle.Generated.Unix(),
+		},
+		Bundle: bundle,
+	}
+	token := jwt.NewWithClaims(signingKey.SigningMethod(), claims)
+	signingKey.PreProcessToken(token)
+	return token.SignedString(signingKey.SignKey())
+}
+
+// VerifyAuditBundle checks the signature of a signed audit bundle and returns the bundle
+func VerifyAuditBundle(signed string, signingKey oauth2.JWTSigningKey) (*api.PullAuditBundle, error) {
+	parsed, err := jwt.ParseWithClaims(signed, &auditClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method == nil || token.Method.Alg() != signingKey.SigningMethod().Alg() {
+			return nil, fmt.Errorf("unexpected signing algo: %v", token.Header["alg"])
+		}
+		return signingKey.VerifyKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := parsed.Claims.(*auditClaims)
+	if !ok || !parsed.Valid {
+		return nil, fmt.Errorf("invalid audit bundle signature")
+	}
+	return claims.Bundle, nil
+}
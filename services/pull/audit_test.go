@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuditBundle(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+	assert.NoError(t, pr.LoadBaseRepo())
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	bundle, err := GetAuditBundle(gitRepo, pr)
+	assert.NoError(t, err)
+	assert.Equal(t, "user2/repo1", bundle.Repository)
+	assert.EqualValues(t, 2, bundle.Index)
+	assert.Equal(t, "user2", bundle.MergedBy)
+	assert.Equal(t, "4a357436d925b5c974181ff12a994538ddc5a269", bundle.HeadCommitID)
+	assert.Empty(t, bundle.Commits)
+	if assert.Len(t, bundle.Approvals, 1) {
+		assert.Equal(t, "user1", bundle.Approvals[0].Reviewer)
+		assert.False(t, bundle.Approvals[0].Dismissed)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signingKey, err := oauth2.CreateJWTSingingKey("RS256", key)
+	assert.NoError(t, err)
+
+	signed, err := SignAuditBundle(bundle, signingKey)
+	assert.NoError(t, err)
+	verified, err := VerifyAuditBundle(signed, signingKey)
+	assert.NoError(t, err)
+	assert.Equal(t, bundle.HeadCommitID, verified.HeadCommitID)
+	if assert.Len(t, verified.Approvals, 1) {
+		assert.Equal(t, "user1", verified.Approvals[0].Reviewer)
+		assert.True(t, bundle.Approvals[0].Submitted.Equal(verified.Approvals[0].Submitted))
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherSigningKey, err := oauth2.CreateJWTSingingKey("RS256", otherKey)
+	assert.NoError(t, err)
+	_, err = VerifyAuditBundle(signed, otherSigningKey)
+	assert.Error(t, err)
+
+	_, err = GetAuditBundle(gitRepo, models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest))
+	assert.Error(t, err)
+}
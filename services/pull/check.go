@@ -262,5 +262,11 @@ func Init() error {
 
 	go graceful.GetManager().RunWithShutdownFns(prQueue.Run)
 	go graceful.GetManager().RunWithShutdownContext(InitializePullRequests)
-	return nil
+	if err := initLabeler(); err != nil {
+		return err
+	}
+	if err := initCodeOwners(); err != nil {
+		return err
+	}
+	return initCLA()
 }
@@ -0,0 +1,272 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// CLAStatusContext is the context of the commit status reporting whether the commit authors
+// of a pull request have signed the contributor license agreement
+const CLAStatusContext = "cla"
+
+// claQueue represents a queue to update the CLA commit status of the pull requests
+var claQueue queue.UniqueQueue
+
+// CLAAuthor represents a commit author of a pull request
+type CLAAuthor struct {
+	Name  string
+	Email string
+	// User is the user of the email of the author, nil if there is none
+	User *models.User
+}
+
+// CLAStatus represents whether the commit authors of a pull request have signed the current version
+// of the contributor license agreement of the organization owning its base repository
+type CLAStatus struct {
+	CLA      *models.CLA
+	Authors  []*CLAAuthor
+	Unsigned []*CLAAuthor
+}
+
+// IsSigned returns true if all the commit authors have signed the contributor license agreement
+func (status *CLAStatus) IsSigned() bool {
+	return len(status.Unsigned) == 0
+}
+
+// GetCLAStatus returns whether the commit authors of the pull request have signed the contributor license
+// agreement of the organization owning its base repository, nil if the organization does not enforce one
+func GetCLAStatus(pr *models.PullRequest) (*CLAStatus, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.BaseRepo.GetOwner(); err != nil {
+		return nil, err
+	}
+	org := pr.BaseRepo.Owner
+	if !org.IsOrganization() || !org.EnforceCLA {
+		return nil, nil
+	}
+	cla, err := models.GetCurrentCLA(org.ID)
+	if err != nil {
+		if models.IsErrCLANotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	authors, err := getCommitAuthors(pr, gitRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(authors))
+	for _, author := range authors {
+		if author.User != nil {
+			userIDs = append(userIDs, author.User.ID)
+		}
+	}
+	signers, err := models.GetCLASignerIDs(org.ID, cla.Version, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &CLAStatus{CLA: cla, Authors: authors}
+	for _, author := range authors {
+		if author.User == nil || !signers[author.User.ID] {
+			status.Unsigned = append(status.Unsigned, author)
+		}
+	}
+	return status, nil
+}
+
+// getCommitAuthors returns the distinct authors of the commits of the pull request since its merge base
+func getCommitAuthors(pr *models.PullRequest, gitRepo *git.Repository) ([]*CLAAuthor, error) {
+	mergeBase := pr.MergeBase
+	if !pr.HasMerged {
+		// The merge base stored in the pull request may predate the last push
+		var err error
+		if mergeBase, _, err = gitRepo.GetMergeBase("", git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName()); err != nil {
+			return nil, fmt.Errorf("GetMergeBase: %v", err)
+		}
+	}
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID: %v", err)
+	}
+	commits, err := gitRepo.CommitsBetweenIDs(headCommitID, mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetweenIDs: %v", err)
+	}
+
+	authors := make([]*CLAAuthor, 0, 2)
+	seen := make(map[string]bool)
+	for e := commits.Front(); e != nil; e = e.Next() {
+		commit := e.Value.(*git.Commit)
+		if commit.Author == nil {
+			continue
+		}
+		email := strings.ToLower(commit.Author.Email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		author := &CLAAuthor{Name: commit.Author.Name, Email: commit.Author.Email}
+		if author.User, err = models.GetUserByEmail(email); err != nil {
+			if !models.IsErrUserNotExist(err) {
+				return nil, err
+			}
+			author.User = nil
+		}
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+// MergeBlockedByCLA returns true if some commit authors of the pull request have not signed
+// the contributor license agreement enforced by the organization owning its base repository
+func MergeBlockedByCLA(pr *models.PullRequest) (bool, error) {
+	status, err := GetCLAStatus(pr)
+	if err != nil || status == nil {
+		return false, err
+	}
+	return !status.IsSigned(), nil
+}
+
+// UpdateCLAStatus reports whether the commit authors of the pull request have signed the contributor
+// license agreement as a commit status of its head commit
+func UpdateCLAStatus(pr *models.PullRequest) error {
+	if pr.HasMerged {
+		return nil
+	}
+	status, err := GetCLAStatus(pr)
+	if err != nil {
+		return err
+	}
+	org := pr.BaseRepo.Owner
+	if !org.IsOrganization() {
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return err
+	}
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	gitRepo.Close()
+	if err != nil {
+		return fmt.Errorf("GetRefCommitID: %v", err)
+	}
+
+	latest, err := models.GetLatestCommitStatus(pr.BaseRepo.ID, headCommitID, models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var previous *models.CommitStatus
+	for _, s := range latest {
+		if s.Context == CLAStatusContext {
+			previous = s
+			break
+		}
+	}
+
+	commitStatus := &models.CommitStatus{
+		State:     api.CommitStatusSuccess,
+		TargetURL: setting.AppURL + "org/" + url.PathEscape(org.Name) + "/cla",
+		Context:   CLAStatusContext,
+	}
+	switch {
+	case status == nil:
+		// Only clear the failure reported before the organization stopped enforcing its agreement
+		if previous == nil || previous.State == api.CommitStatusSuccess {
+			return nil
+		}
+		commitStatus.Description = "The CLA is not enforced"
+	case status.IsSigned():
+		commitStatus.Description = fmt.Sprintf("All commit authors have signed version %d of the CLA", status.CLA.Version)
+	default:
+		commitStatus.State = api.CommitStatusFailure
+		commitStatus.Description = fmt.Sprintf("%d commit author(s) must sign version %d of the CLA", len(status.Unsigned), status.CLA.Version)
+	}
+	if previous != nil && previous.State == commitStatus.State && previous.Description == commitStatus.Description {
+		return nil
+	}
+
+	return models.NewCommitStatus(models.NewCommitStatusOptions{
+		Repo:         pr.BaseRepo,
+		Creator:      org,
+		SHA:          headCommitID,
+		CommitStatus: commitStatus,
+	})
+}
+
+// RecheckCLAStatuses updates the CLA commit status of the open pull requests into the repositories of the organization,
+// e.g. when a user signs its contributor license agreement
+func RecheckCLAStatuses(org *models.User) error {
+	ids, err := models.GetUnmergedPullRequestIDsByBaseOwner(org.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		addToCLAQueue(id)
+	}
+	return nil
+}
+
+// AddToCLAQueue adds the pull request to the queue updating its CLA commit status
+func AddToCLAQueue(pr *models.PullRequest) {
+	addToCLAQueue(pr.ID)
+}
+
+func addToCLAQueue(pullID int64) {
+	if err := claQueue.Push(strconv.FormatInt(pullID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding pull request %d to the CLA queue: %v", pullID, err)
+	}
+}
+
+// handleCLA updates the CLA commit status of the pull requests of the passed IDs
+func handleCLA(data ...queue.Data) {
+	for _, datum := range data {
+		id, _ := strconv.ParseInt(datum.(string), 10, 64)
+
+		pr, err := models.GetPullRequestByID(id)
+		if err != nil {
+			log.Error("GetPullRequestByID[%s]: %v", datum, err)
+			continue
+		}
+		if err := UpdateCLAStatus(pr); err != nil {
+			log.Error("UpdateCLAStatus[%d]: %v", pr.ID, err)
+		}
+	}
+}
+
+// initCLA creates and runs the CLA queue
+func initCLA() error {
+	claQueue = queue.CreateUniqueQueue("pr_cla", handleCLA, "").(queue.UniqueQueue)
+	if claQueue == nil {
+		return fmt.Errorf("Unable to create pr_cla Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(claQueue.Run)
+	return nil
+}
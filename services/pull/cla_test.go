@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCLAStatus(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	// The base repository is owned by a user
+	status, err := GetCLAStatus(models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest))
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 6}).(*models.PullRequest)
+	assert.NoError(t, pr.LoadBaseRepo())
+	_, err = git.NewCommand("update-ref", pr.GetGitRefName(), git.BranchPrefix+pr.HeadBranch).RunInDir(pr.BaseRepo.RepoPath())
+	assert.NoError(t, err)
+
+	// The organization does not enforce an agreement
+	status, err = GetCLAStatus(pr)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: pr.BaseRepo.OwnerID}).(*models.User)
+	org.EnforceCLA = true
+	assert.NoError(t, models.UpdateUserCols(org, "enforce_cla"))
+	pr.BaseRepo.Owner = org
+	cla, err := models.PublishCLA(org.ID, "I agree")
+	assert.NoError(t, err)
+
+	status, err = GetCLAStatus(pr)
+	assert.NoError(t, err)
+	if assert.NotNil(t, status) {
+		assert.False(t, status.IsSigned())
+		if assert.Len(t, status.Unsigned, 1) {
+			assert.Equal(t, "user2@example.com", status.Unsigned[0].Email)
+			assert.EqualValues(t, 2, status.Unsigned[0].User.ID)
+		}
+	}
+	blocked, err := MergeBlockedByCLA(pr)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+
+	_, err = models.SignCLA(cla, models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User), "User Two")
+	assert.NoError(t, err)
+	status, err = GetCLAStatus(pr)
+	assert.NoError(t, err)
+	if assert.NotNil(t, status) {
+		assert.True(t, status.IsSigned())
+		assert.Len(t, status.Authors, 1)
+	}
+
+	// A new version must be signed again
+	_, err = models.PublishCLA(org.ID, "I agree again")
+	assert.NoError(t, err)
+	blocked, err = MergeBlockedByCLA(pr)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+}
@@ -0,0 +1,425 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// CodeOwnersPaths are the paths, in order of precedence, where the code owners file is looked up in the base branch
+var CodeOwnersPaths = []string{"CODEOWNERS", ".gitea/CODEOWNERS", "docs/CODEOWNERS"}
+
+// maxCodeOwnersSize is the maximum size of the code owners file
+const maxCodeOwnersSize = 64 * 1024
+
+// codeOwnersQueue represents a queue to request reviews from the code owners of the pull requests
+var codeOwnersQueue queue.UniqueQueue
+
+// CodeOwnerRule assigns owners to the files matching its gitignore style pattern
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+
+	matcher *regexp.Regexp
+}
+
+// Match returns true if the file matches the pattern of the rule
+func (rule *CodeOwnerRule) Match(file string) bool {
+	return rule.matcher.MatchString(file)
+}
+
+// codeOwnerPatternToRegexp converts a gitignore style pattern to a regular expression matching file paths
+func codeOwnerPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	// Patterns containing a slash are relative to the root of the repository
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var buf strings.Builder
+	if anchored {
+		buf.WriteString("^")
+	} else {
+		buf.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			buf.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			buf.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			buf.WriteString("[^/]*")
+		case pattern[i] == '?':
+			buf.WriteString("[^/]")
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		buf.WriteString("/.*$")
+	} else {
+		buf.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(buf.String())
+}
+
+// ParseCodeOwners parses the rules of a code owners file, where each line holds a pattern followed by its owners:
+//
+//	*.go         @backend
+//	/docs/       @org/writers docs@example.com
+//
+// Invalid patterns are skipped. A pattern without owners removes the ownership of the files it matches.
+func ParseCodeOwners(content []byte) []*CodeOwnerRule {
+	var rules []*CodeOwnerRule
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule := &CodeOwnerRule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+
+		matcher, err := codeOwnerPatternToRegexp(rule.Pattern)
+		if err != nil {
+			log.Trace("Skipping invalid code owners pattern %q: %v", rule.Pattern, err)
+			continue
+		}
+		rule.matcher = matcher
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// GetCodeOwnerRules returns the code owner rules in the passed branch of the repository, or nil if there are none
+func GetCodeOwnerRules(gitRepo *git.Repository, branch string) ([]*CodeOwnerRule, error) {
+	if !gitRepo.IsBranchExist(branch) {
+		return nil, nil
+	}
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+	for _, treePath := range CodeOwnersPaths {
+		blob, err := commit.GetBlobByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		dataRc, err := blob.DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(dataRc, maxCodeOwnersSize))
+		dataRc.Close()
+		if err != nil {
+			return nil, err
+		}
+		return ParseCodeOwners(content), nil
+	}
+	return nil, nil
+}
+
+// MatchCodeOwnerRules returns, for each of the files, the last rule matching it which has owners
+func MatchCodeOwnerRules(rules []*CodeOwnerRule, files []string) []*CodeOwnerRule {
+	matched := make([]*CodeOwnerRule, 0, len(rules))
+	for _, file := range files {
+		var last *CodeOwnerRule
+		for _, rule := range rules {
+			if rule.Match(file) {
+				last = rule
+			}
+		}
+		if last == nil || len(last.Owners) == 0 {
+			continue
+		}
+
+		found := false
+		for _, rule := range matched {
+			if rule == last {
+				found = true
+				break
+			}
+		}
+		if !found {
+			matched = append(matched, last)
+		}
+	}
+	return matched
+}
+
+// codeOwners holds the users and teams a code owner rule resolves to
+type codeOwners struct {
+	Users []*models.User
+	Teams []*models.Team
+}
+
+// resolveCodeOwners resolves the owners of a rule to users and to teams of the organization owning the repository.
+// Owners which do not exist are ignored.
+func resolveCodeOwners(repo *models.Repository, owners []string) (*codeOwners, error) {
+	resolved := new(codeOwners)
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			u, err := models.GetUserByEmail(owner)
+			if err != nil {
+				if models.IsErrUserNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			resolved.Users = append(resolved.Users, u)
+			continue
+		}
+
+		name := strings.TrimPrefix(owner, "@")
+		if idx := strings.IndexByte(name, '/'); idx >= 0 {
+			if !repo.Owner.IsOrganization() || !strings.EqualFold(name[:idx], repo.Owner.Name) {
+				continue
+			}
+			team, err := models.GetTeam(repo.OwnerID, name[idx+1:])
+			if err != nil {
+				if models.IsErrTeamNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			resolved.Teams = append(resolved.Teams, team)
+			continue
+		}
+
+		u, err := models.GetUserByName(name)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if u.IsOrganization() {
+			continue
+		}
+		resolved.Users = append(resolved.Users, u)
+	}
+	return resolved, nil
+}
+
+// getPullRequestCodeOwners returns the resolved owners of the rules in the code owners file of the base branch
+// matching the files changed by the pull request
+func getPullRequestCodeOwners(pr *models.PullRequest) ([]*codeOwners, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	repo := pr.BaseRepo
+	if err := repo.GetOwner(); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	rules, err := GetCodeOwnerRules(gitRepo, pr.BaseBranch)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	files, err := getChangedFiles(pr, gitRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := MatchCodeOwnerRules(rules, files)
+	owners := make([]*codeOwners, 0, len(matched))
+	for _, rule := range matched {
+		resolved, err := resolveCodeOwners(repo, rule.Owners)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved.Users) > 0 || len(resolved.Teams) > 0 {
+			owners = append(owners, resolved)
+		}
+	}
+	return owners, nil
+}
+
+// RequestCodeOwnerReviews requests reviews from the code owners of the files changed by the pull request,
+// on behalf of its poster. Owners who already reviewed the pull request or can't read it are skipped.
+func RequestCodeOwnerReviews(pr *models.PullRequest) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.Issue.IsClosed {
+		return nil
+	}
+	owners, err := getPullRequestCodeOwners(pr)
+	if err != nil || len(owners) == 0 {
+		return err
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return err
+	}
+	issue := pr.Issue
+	issue.Repo = pr.BaseRepo
+	doer := issue.Poster
+
+	requested := make(map[int64]bool)
+	requestedTeams := make(map[int64]bool)
+	for _, owner := range owners {
+		for _, u := range owner.Users {
+			if u.ID == doer.ID || requested[u.ID] {
+				continue
+			}
+			requested[u.ID] = true
+
+			if _, err := models.GetReviewByIssueIDAndUserID(issue.ID, u.ID); err == nil {
+				continue
+			} else if !models.IsErrReviewNotExist(err) {
+				return err
+			}
+			perm, err := models.GetUserRepoPermission(issue.Repo, u)
+			if err != nil {
+				return err
+			}
+			if !perm.CanAccessAny(models.AccessModeRead, models.UnitTypePullRequests) {
+				continue
+			}
+			if _, err := issue_service.ReviewRequest(issue, doer, u, true); err != nil {
+				return fmt.Errorf("ReviewRequest[%d]: %v", u.ID, err)
+			}
+		}
+		for _, team := range owner.Teams {
+			if requestedTeams[team.ID] {
+				continue
+			}
+			requestedTeams[team.ID] = true
+
+			if issue.Repo.IsPrivate && !models.HasTeamRepo(team.OrgID, team.ID, issue.RepoID) {
+				continue
+			}
+			if _, err := issue_service.TeamReviewRequest(issue, doer, team, true); err != nil {
+				return fmt.Errorf("TeamReviewRequest[%d]: %v", team.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MergeBlockedByCodeOwners returns true if the protected branch requires the approval of the code owners
+// and one of the rules matching the files changed by the pull request has not been approved by any of its owners
+func MergeBlockedByCodeOwners(pr *models.PullRequest) (bool, error) {
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return false, err
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.RequireCodeOwnerApproval {
+		return false, nil
+	}
+
+	owners, err := getPullRequestCodeOwners(pr)
+	if err != nil || len(owners) == 0 {
+		return false, err
+	}
+
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return false, err
+	}
+	approvers := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type != models.ReviewTypeApprove {
+			continue
+		}
+		if pr.ProtectedBranch.DismissStaleApprovals && review.Stale {
+			continue
+		}
+		approvers = append(approvers, review.ReviewerID)
+	}
+
+	for _, owner := range owners {
+		if !isApprovedByCodeOwners(owner, approvers) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isApprovedByCodeOwners returns true if one of the approvers is one of the owners or a member of one of the teams
+func isApprovedByCodeOwners(owner *codeOwners, approvers []int64) bool {
+	for _, approver := range approvers {
+		for _, u := range owner.Users {
+			if u.ID == approver {
+				return true
+			}
+		}
+		for _, team := range owner.Teams {
+			if team.IsMember(approver) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddToCodeOwnersQueue adds the pull request to the queue requesting reviews from its code owners
+func AddToCodeOwnersQueue(pr *models.PullRequest) {
+	if err := codeOwnersQueue.Push(strconv.FormatInt(pr.ID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding pull request %d to the code owners queue: %v", pr.ID, err)
+	}
+}
+
+// handleCodeOwners requests reviews from the code owners of the pull requests of the passed IDs
+func handleCodeOwners(data ...queue.Data) {
+	for _, datum := range data {
+		id, _ := strconv.ParseInt(datum.(string), 10, 64)
+
+		pr, err := models.GetPullRequestByID(id)
+		if err != nil {
+			log.Error("GetPullRequestByID[%s]: %v", datum, err)
+			continue
+		}
+		if err := RequestCodeOwnerReviews(pr); err != nil {
+			log.Error("RequestCodeOwnerReviews[%d]: %v", pr.ID, err)
+		}
+	}
+}
+
+// initCodeOwners creates and runs the code owners queue
+func initCodeOwners() error {
+	codeOwnersQueue = queue.CreateUniqueQueue("pr_code_owners", handleCodeOwners, "").(queue.UniqueQueue)
+	if codeOwnersQueue == nil {
+		return fmt.Errorf("Unable to create pr_code_owners Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(codeOwnersQueue.Run)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	rules := ParseCodeOwners([]byte(`
+# Comment
+*                 @user1
+*.go              @user3/team1 # Backend
+/docs/            @user2 user4@example.com
+docs/**/*.md      @user5
+/docs/legal/
+build/
+`))
+	assert.Len(t, rules, 6)
+	assert.Equal(t, "*", rules[0].Pattern)
+	assert.Equal(t, []string{"@user1"}, rules[0].Owners)
+	assert.Equal(t, []string{"@user3/team1"}, rules[1].Owners)
+	assert.Equal(t, []string{"@user2", "user4@example.com"}, rules[2].Owners)
+	assert.Empty(t, rules[4].Owners)
+
+	assert.True(t, rules[0].Match("README.md"))
+	assert.True(t, rules[0].Match("models/repo.go"))
+	assert.True(t, rules[1].Match("main.go"))
+	assert.True(t, rules[1].Match("models/repo.go"))
+	assert.False(t, rules[1].Match("main.go.orig"))
+	assert.True(t, rules[2].Match("docs/README.md"))
+	assert.False(t, rules[2].Match("docs"))
+	assert.False(t, rules[2].Match("content/docs/README.md"))
+	assert.True(t, rules[3].Match("docs/README.md"))
+	assert.True(t, rules[3].Match("docs/content/doc/usage.md"))
+	assert.True(t, rules[5].Match("build/Makefile"))
+	assert.True(t, rules[5].Match("contrib/build/Makefile"))
+	assert.False(t, rules[5].Match("build"))
+
+	matched := MatchCodeOwnerRules(rules, []string{"main.go", "models/repo.go", "docs/content/doc/usage.md", "docs/legal/LICENSE", "README.md"})
+	assert.Equal(t, []*CodeOwnerRule{rules[1], rules[3], rules[0]}, matched)
+}
+
+func TestResolveCodeOwners(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+
+	owners, err := resolveCodeOwners(repo, []string{"@user2", "user4@example.com", "@user3", "@user3/team1", "@org6/owners", "@nonexistent", "@user3/nonexistent"})
+	assert.NoError(t, err)
+	if assert.Len(t, owners.Users, 2) {
+		assert.EqualValues(t, 2, owners.Users[0].ID)
+		assert.EqualValues(t, 4, owners.Users[1].ID)
+	}
+	if assert.Len(t, owners.Teams, 1) {
+		assert.EqualValues(t, 2, owners.Teams[0].ID)
+	}
+
+	assert.True(t, isApprovedByCodeOwners(owners, []int64{4}))
+	assert.False(t, isApprovedByCodeOwners(owners, []int64{5}))
+
+	teamOwners := &codeOwners{Teams: owners.Teams}
+	assert.True(t, isApprovedByCodeOwners(teamOwners, []int64{5, 2}))
+	assert.False(t, isApprovedByCodeOwners(teamOwners, []int64{5}))
+}
@@ -101,24 +101,32 @@ func GetPullRequestCommitStatusState(pr *models.PullRequest) (structs.CommitStat
 		return "", errors.Wrap(err, "LoadHeadRepo")
 	}
 
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", errors.Wrap(err, "LoadBaseRepo")
+	}
+
+	// The head of a pull request whose head repo has been deleted is kept in the base repo
+	headRepo := pr.HeadRepo
+	headRefName := pr.GetHeadRefName()
+	if headRepo == nil {
+		headRepo = pr.BaseRepo
+		headRefName = pr.GetGitRefName()
+	}
+
 	// check if all required status checks are successful
-	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	headGitRepo, err := git.OpenRepository(headRepo.RepoPath())
 	if err != nil {
 		return "", errors.Wrap(err, "OpenRepository")
 	}
 	defer headGitRepo.Close()
 
-	if !headGitRepo.IsBranchExist(pr.HeadBranch) {
+	if !headGitRepo.IsReferenceExist(headRefName) {
 		return "", errors.New("Head branch does not exist, can not merge")
 	}
 
-	sha, err := headGitRepo.GetBranchCommitID(pr.HeadBranch)
+	sha, err := headGitRepo.GetRefCommitID(headRefName)
 	if err != nil {
-		return "", errors.Wrap(err, "GetBranchCommitID")
-	}
-
-	if err := pr.LoadBaseRepo(); err != nil {
-		return "", errors.Wrap(err, "LoadBaseRepo")
+		return "", errors.Wrap(err, "GetRefCommitID")
 	}
 
 	commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepo.ID, sha, models.ListOptions{})
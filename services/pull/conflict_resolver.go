@@ -0,0 +1,265 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ConflictResolver resolves the conflicted files of a merge
+type ConflictResolver interface {
+	Resolve(ctx context.Context, req *api.MergeConflictRequest) (*api.MergeConflictResolution, error)
+}
+
+// GetConflictResolver returns the conflict resolver of the instance, nil if there is none
+var GetConflictResolver = func() ConflictResolver {
+	if setting.Repository.PullRequest.ConflictResolverURL == "" {
+		return nil
+	}
+	return &httpConflictResolver{
+		url:    setting.Repository.PullRequest.ConflictResolverURL,
+		token:  setting.Repository.PullRequest.ConflictResolverToken,
+		client: getConflictResolverClient(),
+	}
+}
+
+var (
+	conflictResolverMutex   sync.Mutex
+	conflictResolverClient  *http.Client
+	conflictResolverTimeout time.Duration
+)
+
+// getConflictResolverClient returns the HTTP client shared by the requests to the conflict resolver so that
+// their connections are reused, it is only rebuilt when the configured timeout changes
+func getConflictResolverClient() *http.Client {
+	conflictResolverMutex.Lock()
+	defer conflictResolverMutex.Unlock()
+
+	timeout := setting.Repository.PullRequest.ConflictResolverTimeout
+	if conflictResolverClient == nil || conflictResolverTimeout != timeout {
+		conflictResolverClient = &http.Client{
+			Transport: &http.Transport{Proxy: proxy.Proxy()},
+			Timeout:   timeout,
+		}
+		conflictResolverTimeout = timeout
+	}
+	return conflictResolverClient
+}
+
+// CanResolveConflicts returns if the conflicts of a pull request can be handed to the conflict
+// resolver when merging it with the given style, rebases are not supported
+func CanResolveConflicts(mergeStyle models.MergeStyle) bool {
+	return isResolvableMergeStyle(mergeStyle) && GetConflictResolver() != nil
+}
+
+// CanMergeWithStyle returns if the pull request can be merged with the given style once the other
+// checks pass, conflicted pull requests can be if their conflicts can be handed to the conflict resolver
+func CanMergeWithStyle(pr *models.PullRequest, mergeStyle models.MergeStyle) bool {
+	return pr.CanAutoMerge() || (pr.Status == models.PullRequestStatusConflict && CanResolveConflicts(mergeStyle))
+}
+
+func isResolvableMergeStyle(mergeStyle models.MergeStyle) bool {
+	return mergeStyle == models.MergeStyleMerge || mergeStyle == models.MergeStyleSquash
+}
+
+// httpConflictResolver posts the conflicts to an external service
+type httpConflictResolver struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (r *httpConflictResolver) Resolve(ctx context.Context, conflict *api.MergeConflictRequest) (*api.MergeConflictResolution, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	body, err := json.Marshal(conflict)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("the conflict resolver responded with %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	resolution := &api.MergeConflictResolution{}
+	if err = json.NewDecoder(resp.Body).Decode(resolution); err != nil {
+		return nil, fmt.Errorf("invalid response of the conflict resolver: %v", err)
+	}
+	return resolution, nil
+}
+
+// unmergedFile is a conflicted file of the index, the stages are 1 for the merge base,
+// 2 for our side and 3 for their side
+type unmergedFile struct {
+	path   string
+	modes  [4]string
+	hashes [4]string
+}
+
+func getUnmergedFiles(tmpBasePath string) ([]*unmergedFile, error) {
+	stdout, err := git.NewCommand("ls-files", "-u", "-z").RunInDirBytes(tmpBasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*unmergedFile, 0, 5)
+	for _, line := range bytes.Split(stdout, []byte{'\x00'}) {
+		if len(line) == 0 {
+			continue
+		}
+		// <mode> SP <object> SP <stage> TAB <file>
+		parts := strings.SplitN(string(line), "\t", 2)
+		fields := strings.Fields(parts[0])
+		if len(parts) != 2 || len(fields) != 3 || len(fields[2]) != 1 || fields[2][0] < '1' || fields[2][0] > '3' {
+			return nil, fmt.Errorf("unexpected unmerged entry: %q", line)
+		}
+		if len(files) == 0 || files[len(files)-1].path != parts[1] {
+			files = append(files, &unmergedFile{path: parts[1]})
+		}
+		stage := fields[2][0] - '0'
+		files[len(files)-1].modes[stage] = fields[0]
+		files[len(files)-1].hashes[stage] = fields[1]
+	}
+	return files, nil
+}
+
+func readBlob(tmpBasePath, hash string) ([]byte, error) {
+	if hash == "" {
+		return nil, nil
+	}
+	return git.NewCommand("cat-file", "blob", hash).RunInDirBytes(tmpBasePath)
+}
+
+// resolveConflicts hands the conflicted files left in the index by a failed merge to the conflict
+// resolver and stages its resolution. mergeErr is returned unchanged if there is no resolver or
+// nothing to resolve, an ErrMergeConflicts if the resolver fails.
+func resolveConflicts(pr *models.PullRequest, mergeStyle models.MergeStyle, tmpBasePath string, mergeErr error) error {
+	if !isResolvableMergeStyle(mergeStyle) {
+		return mergeErr
+	}
+	resolver := GetConflictResolver()
+	if resolver == nil {
+		return mergeErr
+	}
+
+	unmerged, err := getUnmergedFiles(tmpBasePath)
+	if err != nil {
+		log.Error("Unable to list the unmerged files in %s: %v", tmpBasePath, err)
+		return mergeErr
+	}
+	if len(unmerged) == 0 {
+		return mergeErr
+	}
+	conflictErr := func(reason string) error {
+		log.Warn("Unable to resolve the conflicts of %s#%d: %s", pr.BaseRepo.FullName(), pr.Index, reason)
+		if err, ok := mergeErr.(models.ErrMergeConflicts); ok {
+			err.StdErr += "\n" + reason
+			return err
+		}
+		return models.ErrMergeConflicts{Style: mergeStyle, StdErr: reason, Err: mergeErr}
+	}
+
+	conflict := &api.MergeConflictRequest{
+		Repository:  pr.BaseRepo.FullName(),
+		PullRequest: pr.Index,
+		BaseBranch:  pr.BaseBranch,
+		HeadBranch:  pr.HeadBranch,
+		MergeStyle:  string(mergeStyle),
+		Files:       make([]*api.MergeConflictFile, 0, len(unmerged)),
+	}
+	for _, f := range unmerged {
+		file := &api.MergeConflictFile{Path: f.path}
+		if file.Base, err = readBlob(tmpBasePath, f.hashes[1]); err != nil {
+			return fmt.Errorf("unable to read %s: %v", f.hashes[1], err)
+		}
+		if file.Ours, err = readBlob(tmpBasePath, f.hashes[2]); err != nil {
+			return fmt.Errorf("unable to read %s: %v", f.hashes[2], err)
+		}
+		if file.Theirs, err = readBlob(tmpBasePath, f.hashes[3]); err != nil {
+			return fmt.Errorf("unable to read %s: %v", f.hashes[3], err)
+		}
+		conflict.Files = append(conflict.Files, file)
+	}
+
+	var resolution *api.MergeConflictResolution
+	graceful.GetManager().RunWithShutdownContext(func(ctx context.Context) {
+		resolution, err = resolver.Resolve(ctx, conflict)
+	})
+	if err != nil {
+		return conflictErr(err.Error())
+	}
+
+	resolved := make(map[string]*api.MergeConflictResolvedFile, len(resolution.Files))
+	for _, f := range resolution.Files {
+		resolved[f.Path] = f
+	}
+	for _, f := range unmerged {
+		if _, ok := resolved[f.path]; !ok {
+			return conflictErr(fmt.Sprintf("the conflict resolver did not resolve %s", f.path))
+		}
+	}
+
+	for _, f := range unmerged {
+		if err := stageResolvedFile(tmpBasePath, f, resolved[f.path]); err != nil {
+			log.Error("Unable to stage the resolution of %s in %s: %v", f.path, tmpBasePath, err)
+			return fmt.Errorf("unable to stage the resolution of %s: %v", f.path, err)
+		}
+	}
+	log.Trace("Conflicts of %s#%d resolved by the conflict resolver", pr.BaseRepo.FullName(), pr.Index)
+	return nil
+}
+
+func stageResolvedFile(tmpBasePath string, f *unmergedFile, resolved *api.MergeConflictResolvedFile) error {
+	if resolved.Deleted {
+		_, err := git.NewCommand("update-index", "--force-remove", "--", f.path).RunInDir(tmpBasePath)
+		return err
+	}
+
+	mode := f.modes[2]
+	if mode == "" {
+		mode = f.modes[3]
+	}
+
+	var stdout, stderr strings.Builder
+	if err := git.NewCommand("hash-object", "-w", "--stdin").RunInDirFullPipeline(tmpBasePath, &stdout, &stderr, bytes.NewReader(resolved.Content)); err != nil {
+		return fmt.Errorf("hash-object: %v\n%s", err, stderr.String())
+	}
+	_, err := git.NewCommand("update-index", "--add", "--replace", "--cacheinfo", mode, strings.TrimSpace(stdout.String()), f.path).RunInDir(tmpBasePath)
+	return err
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConflictResolver struct {
+	requests   []*api.MergeConflictRequest
+	resolution *api.MergeConflictResolution
+}
+
+func (r *fakeConflictResolver) Resolve(ctx context.Context, req *api.MergeConflictRequest) (*api.MergeConflictResolution, error) {
+	r.requests = append(r.requests, req)
+	return r.resolution, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	stdout, err := git.NewCommand(args...).RunInDir(dir)
+	assert.NoError(t, err, "git %s", strings.Join(args, " "))
+	return stdout
+}
+
+func writeAndCommit(t *testing.T, dir, message string, files map[string]string) {
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		runGit(t, dir, "add", name)
+	}
+	runGit(t, dir, "-c", "user.name=Gitea", "-c", "user.email=gitea@example.com", "commit", "-m", message)
+}
+
+func TestResolveConflicts(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "conflict-resolver")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	runGit(t, tmpDir, "init")
+	writeAndCommit(t, tmpDir, "initial", map[string]string{"schema.json": "v1\n", "README": "readme\n"})
+	runGit(t, tmpDir, "branch", "tracking")
+	writeAndCommit(t, tmpDir, "ours", map[string]string{"schema.json": "v2\n", "added.txt": "ours\n"})
+	runGit(t, tmpDir, "checkout", "tracking")
+	writeAndCommit(t, tmpDir, "theirs", map[string]string{"schema.json": "v3\n", "added.txt": "theirs\n"})
+	runGit(t, tmpDir, "checkout", "-")
+
+	_, mergeErr := git.NewCommand("merge", "--no-ff", "--no-commit", "tracking").RunInDir(tmpDir)
+	assert.Error(t, mergeErr)
+
+	pr := &models.PullRequest{
+		Index:      3,
+		BaseBranch: "master",
+		HeadBranch: "feature",
+		BaseRepo:   &models.Repository{OwnerName: "user2", Name: "repo1"},
+	}
+
+	resolver := &fakeConflictResolver{}
+	defer func(get func() ConflictResolver) {
+		GetConflictResolver = get
+	}(GetConflictResolver)
+	GetConflictResolver = func() ConflictResolver {
+		return resolver
+	}
+
+	// unsupported merge style
+	assert.Equal(t, mergeErr, resolveConflicts(pr, models.MergeStyleRebase, tmpDir, mergeErr))
+	assert.Empty(t, resolver.requests)
+
+	// incomplete resolution
+	resolver.resolution = &api.MergeConflictResolution{
+		Files: []*api.MergeConflictResolvedFile{{Path: "schema.json", Content: []byte("v4\n")}},
+	}
+	err = resolveConflicts(pr, models.MergeStyleMerge, tmpDir, mergeErr)
+	assert.True(t, models.IsErrMergeConflicts(err))
+	if assert.Len(t, resolver.requests, 1) {
+		req := resolver.requests[0]
+		assert.Equal(t, "user2/repo1", req.Repository)
+		assert.EqualValues(t, 3, req.PullRequest)
+		assert.Equal(t, "merge", req.MergeStyle)
+		if assert.Len(t, req.Files, 2) {
+			assert.Equal(t, "added.txt", req.Files[0].Path)
+			assert.Nil(t, req.Files[0].Base)
+			assert.Equal(t, "ours\n", string(req.Files[0].Ours))
+			assert.Equal(t, "theirs\n", string(req.Files[0].Theirs))
+			assert.Equal(t, "schema.json", req.Files[1].Path)
+			assert.Equal(t, "v1\n", string(req.Files[1].Base))
+			assert.Equal(t, "v2\n", string(req.Files[1].Ours))
+			assert.Equal(t, "v3\n", string(req.Files[1].Theirs))
+		}
+	}
+
+	resolver.resolution.Files = append(resolver.resolution.Files, &api.MergeConflictResolvedFile{Path: "added.txt", Deleted: true})
+	assert.NoError(t, resolveConflicts(pr, models.MergeStyleMerge, tmpDir, mergeErr))
+	assert.Empty(t, runGit(t, tmpDir, "ls-files", "-u"))
+
+	runGit(t, tmpDir, "-c", "user.name=Gitea", "-c", "user.email=gitea@example.com", "commit", "-m", "merge")
+	assert.Equal(t, "v4\n", runGit(t, tmpDir, "show", "HEAD:schema.json"))
+	assert.Equal(t, "README\nschema.json\n", runGit(t, tmpDir, "ls-tree", "--name-only", "HEAD"))
+}
+
+func TestHTTPConflictResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+		req := &api.MergeConflictRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		resolution := &api.MergeConflictResolution{}
+		for _, f := range req.Files {
+			resolution.Files = append(resolution.Files, &api.MergeConflictResolvedFile{
+				Path:    f.Path,
+				Content: append(f.Ours, f.Theirs...),
+			})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resolution))
+	}))
+	defer server.Close()
+
+	req := &api.MergeConflictRequest{
+		Files: []*api.MergeConflictFile{{Path: "a.txt", Ours: []byte("a"), Theirs: []byte("b")}},
+	}
+
+	resolver := &httpConflictResolver{url: server.URL, token: "secret", client: http.DefaultClient}
+	resolution, err := resolver.Resolve(context.Background(), req)
+	assert.NoError(t, err)
+	if assert.Len(t, resolution.Files, 1) {
+		assert.Equal(t, "a.txt", resolution.Files[0].Path)
+		assert.Equal(t, "ab", string(resolution.Files[0].Content))
+	}
+
+	resolver.token = "wrong"
+	_, err = resolver.Resolve(context.Background(), req)
+	assert.Error(t, err)
+}
+
+func TestGetConflictResolverClient(t *testing.T) {
+	defer func(timeout time.Duration) {
+		setting.Repository.PullRequest.ConflictResolverTimeout = timeout
+	}(setting.Repository.PullRequest.ConflictResolverTimeout)
+
+	setting.Repository.PullRequest.ConflictResolverTimeout = 5 * time.Second
+	c := getConflictResolverClient()
+	assert.Same(t, c, getConflictResolverClient())
+
+	setting.Repository.PullRequest.ConflictResolverTimeout = 10 * time.Second
+	assert.NotSame(t, c, getConflictResolverClient())
+	assert.Equal(t, 10*time.Second, getConflictResolverClient().Timeout)
+}
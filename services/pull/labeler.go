@@ -0,0 +1,273 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"github.com/gobwas/glob"
+	"gopkg.in/yaml.v2"
+)
+
+// LabelerConfigPath is the path of the labeler rules in the default branch of a repository
+const LabelerConfigPath = ".gitea/labeler.yml"
+
+// maxLabelerConfigSize is the maximum size of the labeler rules file
+const maxLabelerConfigSize = 64 * 1024
+
+// labelerQueue represents a queue to label the pull requests by their changed files
+var labelerQueue queue.UniqueQueue
+
+// LabelerRule adds a label to the pull requests changing a file matching one of its globs
+type LabelerRule struct {
+	Label string
+	Globs []string
+
+	matchers []glob.Glob
+}
+
+// Match returns true if one of the files matches one of the globs of the rule
+func (rule *LabelerRule) Match(files []string) bool {
+	for _, file := range files {
+		for _, matcher := range rule.matchers {
+			if matcher.Match(file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseLabelerRules parses labeler rules mapping label names to lists of path globs:
+//
+//	documentation:
+//	  - docs/**
+//	  - "*.md"
+func ParseLabelerRules(content []byte) ([]*LabelerRule, error) {
+	var config yaml.MapSlice
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*LabelerRule, 0, len(config))
+	for _, item := range config {
+		label, ok := item.Key.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid label name: %v", item.Key)
+		}
+		rule := &LabelerRule{Label: label}
+
+		var globs []interface{}
+		switch value := item.Value.(type) {
+		case string:
+			globs = []interface{}{value}
+		case []interface{}:
+			globs = value
+		default:
+			return nil, fmt.Errorf("invalid globs of label %q", label)
+		}
+		for _, g := range globs {
+			pattern, ok := g.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid glob of label %q: %v", label, g)
+			}
+			matcher, err := glob.Compile(pattern, '/')
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob of label %q: %v", label, err)
+			}
+			rule.Globs = append(rule.Globs, pattern)
+			rule.matchers = append(rule.matchers, matcher)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// GetLabelerRules returns the labeler rules in the default branch of the repository, or nil if there are none
+func GetLabelerRules(repo *models.Repository, gitRepo *git.Repository) ([]*LabelerRule, error) {
+	if repo.IsEmpty || !gitRepo.IsBranchExist(repo.DefaultBranch) {
+		return nil, nil
+	}
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := commit.GetBlobByPath(LabelerConfigPath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(dataRc, maxLabelerConfigSize))
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelerRules(content)
+}
+
+// GetLabelerChanges returns the files changed by the pull request and the names of the labels the rules would add to it
+func GetLabelerChanges(pr *models.PullRequest, gitRepo *git.Repository, rules []*LabelerRule) (files, labels []string, err error) {
+	if files, err = getChangedFiles(pr, gitRepo); err != nil {
+		return nil, nil, err
+	}
+
+	for _, rule := range rules {
+		if rule.Match(files) {
+			labels = append(labels, rule.Label)
+		}
+	}
+	return files, labels, nil
+}
+
+// getChangedFiles returns the names of the files changed by the pull request since its merge base
+func getChangedFiles(pr *models.PullRequest, gitRepo *git.Repository) ([]string, error) {
+	mergeBase := pr.MergeBase
+	if !pr.HasMerged {
+		// The merge base stored in the pull request may predate the last push
+		var err error
+		if mergeBase, _, err = gitRepo.GetMergeBase("", git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName()); err != nil {
+			return nil, fmt.Errorf("GetMergeBase: %v", err)
+		}
+	}
+	stats, err := gitRepo.GetDiffFileStats(mergeBase, pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetDiffFileStats: %v", err)
+	}
+	files := make([]string, 0, len(stats))
+	for _, stat := range stats {
+		files = append(files, stat.Name)
+	}
+	return files, nil
+}
+
+// FindLabelerLabels returns the labels of the repository and of its organization with the passed names,
+// and the names matching none of them
+func FindLabelerLabels(repo *models.Repository, names []string) (labels []*models.Label, missing []string, err error) {
+	all, err := models.GetLabelsByRepoID(repo.ID, "", models.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return nil, nil, err
+	}
+	if repo.Owner.IsOrganization() {
+		orgLabels, err := models.GetLabelsByOrgID(repo.OwnerID, "", models.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, orgLabels...)
+	}
+
+	for _, name := range names {
+		var found *models.Label
+		for _, label := range all {
+			if label.Name == name {
+				found = label
+				break
+			}
+		}
+		if found != nil {
+			labels = append(labels, found)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return labels, missing, nil
+}
+
+// ApplyLabelerRules adds to the pull request the labels of the labeler rules matching its changed files
+func ApplyLabelerRules(pr *models.PullRequest) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	repo := pr.BaseRepo
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	rules, err := GetLabelerRules(repo, gitRepo)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	_, labelNames, err := GetLabelerChanges(pr, gitRepo, rules)
+	if err != nil || len(labelNames) == 0 {
+		return err
+	}
+
+	labels, _, err := FindLabelerLabels(repo, labelNames)
+	if err != nil {
+		return err
+	}
+	if err := pr.Issue.LoadLabels(); err != nil {
+		return err
+	}
+
+	toAdd := make([]*models.Label, 0, len(labels))
+	for _, label := range labels {
+		if !pr.Issue.HasLabel(label.ID) {
+			toAdd = append(toAdd, label)
+		}
+	}
+	if len(toAdd) == 0 {
+		return nil
+	}
+	return issue_service.AddLabels(pr.Issue, repo.Owner, toAdd)
+}
+
+// AddToLabelerQueue adds the pull request to the queue labelling it by its changed files
+func AddToLabelerQueue(pr *models.PullRequest) {
+	if err := labelerQueue.Push(strconv.FormatInt(pr.ID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding pull request %d to the labeler queue: %v", pr.ID, err)
+	}
+}
+
+// handleLabeler labels the pull requests of the passed IDs
+func handleLabeler(data ...queue.Data) {
+	for _, datum := range data {
+		id, _ := strconv.ParseInt(datum.(string), 10, 64)
+
+		pr, err := models.GetPullRequestByID(id)
+		if err != nil {
+			log.Error("GetPullRequestByID[%s]: %v", datum, err)
+			continue
+		}
+		if err := ApplyLabelerRules(pr); err != nil {
+			log.Error("ApplyLabelerRules[%d]: %v", pr.ID, err)
+		}
+	}
+}
+
+// initLabeler creates and runs the labeler queue
+func initLabeler() error {
+	labelerQueue = queue.CreateUniqueQueue("pr_labeler", handleLabeler, "").(queue.UniqueQueue)
+	if labelerQueue == nil {
+		return fmt.Errorf("Unable to create pr_labeler Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(labelerQueue.Run)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabelerRules(t *testing.T) {
+	rules, err := ParseLabelerRules([]byte(`
+documentation:
+  - docs/**
+  - "*.md"
+backend: "models/*.go"
+`))
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "documentation", rules[0].Label)
+	assert.Equal(t, []string{"docs/**", "*.md"}, rules[0].Globs)
+	assert.Equal(t, "backend", rules[1].Label)
+
+	assert.True(t, rules[0].Match([]string{"main.go", "docs/content/doc/usage.md"}))
+	assert.True(t, rules[0].Match([]string{"README.md"}))
+	assert.False(t, rules[0].Match([]string{"models/README.md"}))
+	assert.True(t, rules[1].Match([]string{"models/repo.go"}))
+	assert.False(t, rules[1].Match([]string{"models/migrations/v1.go"}))
+
+	_, err = ParseLabelerRules([]byte(`backend: {models: "*.go"}`))
+	assert.Error(t, err)
+	_, err = ParseLabelerRules([]byte(`backend: ["models/[*.go"]`))
+	assert.Error(t, err)
+}
+
+func TestFindLabelerLabels(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	labels, missing, err := FindLabelerLabels(repo, []string{"label1", "unknown"})
+	assert.NoError(t, err)
+	if assert.Len(t, labels, 1) {
+		assert.EqualValues(t, 1, labels[0].ID)
+	}
+	assert.Equal(t, []string{"unknown"}, missing)
+}
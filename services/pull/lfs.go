@@ -113,6 +113,12 @@ func createLFSMetaObjectsFromCatFileBatch(catFileBatchReader *io.PipeReader, wg
 			continue
 		}
 
+		// The objects of a deleted head repo cannot be verified and are not associated
+		if pr.HeadRepo == nil {
+			log.Warn("During merge of: %d in %-v, there is a pointer to LFS Oid: %s which cannot be associated as the head repo has been deleted", pr.Index, pr.BaseRepo, pointer.Oid)
+			continue
+		}
+
 		// Then we need to check that this pointer is in the db
 		if _, err := pr.HeadRepo.GetLFSMetaObjectByOid(pointer.Oid); err != nil {
 			if err == models.ErrLFSObjectNotExist {
@@ -18,13 +18,27 @@ import (
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/antispam"
 	issue_service "code.gitea.io/gitea/services/issue"
 	jsoniter "github.com/json-iterator/go"
 )
 
 // NewPullRequest creates new pull request with labels for repository.
 func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int64, uuids []string, pr *models.PullRequest, assigneeIDs []int64) error {
+	content := &antispam.Content{
+		Kind:  antispam.KindPullRequest,
+		Doer:  pull.Poster,
+		Repo:  repo,
+		Title: pull.Title,
+		Text:  pull.Content,
+	}
+	flagged, err := antispam.Check(content)
+	if err != nil {
+		return err
+	}
+
 	if err := TestPatch(pr); err != nil {
 		return err
 	}
@@ -49,7 +63,12 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 	pr.Issue = pull
 	pull.PullRequest = pr
 
-	if err := PushToBaseRepo(pr); err != nil {
+	if pr.Flow == models.PullRequestFlowAGit {
+		err = UpdateRef(pr)
+	} else {
+		err = PushToBaseRepo(pr)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -59,6 +78,15 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 	}
 
 	notification.NotifyNewPullRequest(pr, mentions)
+	AddToLabelerQueue(pr)
+	AddToCodeOwnersQueue(pr)
+	AddToCLAQueue(pr)
+	if flagged {
+		if err := pull.LoadRepo(); err != nil {
+			return err
+		}
+		antispam.Flag(content, pull.HTMLURL())
+	}
 	if len(pull.Labels) > 0 {
 		notification.NotifyIssueChangeLabels(pull.Poster, pull, pull.Labels, nil)
 	}
@@ -104,9 +132,51 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 		_, _ = models.CreateComment(ops)
 	}
 
+	if err := welcomeFirstTimeContributor(repo, pr, baseGitRepo); err != nil {
+		log.Error("welcomeFirstTimeContributor[%d]: %v", pr.ID, err)
+	}
+
 	return nil
 }
 
+// welcomeFirstTimeContributor comments on the first pull request of a user in the repository
+// with a link to the contributing guide, if the repository enabled it
+func welcomeFirstTimeContributor(repo *models.Repository, pr *models.PullRequest, baseGitRepo *git.Repository) error {
+	unit, err := repo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return err
+	}
+	if !unit.PullRequestsConfig().WelcomeFirstTimeContributors || pr.Issue.PosterID == repo.OwnerID {
+		return nil
+	}
+
+	count, err := models.CountPullRequestsByPoster(repo.ID, pr.Issue.PosterID)
+	if err != nil || count != 1 {
+		return err
+	}
+
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	guideLink, err := repo_module.GetContributingGuideLink(repo, baseGitRepo)
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("Thanks for your first pull request to %s, @%s! A maintainer will review it soon.", repo.FullName(), pr.Issue.Poster.Name)
+	if guideLink != "" {
+		content += fmt.Sprintf(" In the meantime, please make sure it follows the [contributing guide](%s).", guideLink)
+	}
+	_, err = models.CreateComment(&models.CreateCommentOptions{
+		Type:    models.CommentTypeComment,
+		Doer:    repo.Owner,
+		Repo:    repo,
+		Issue:   pr.Issue,
+		Content: content,
+	})
+	return err
+}
+
 // ChangeTargetBranch changes the target branch of this pull request, as the given user.
 func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch string) (err error) {
 	// Current target branch is already the same
@@ -146,7 +216,7 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 	}
 
 	// Check if pull request for the new target branch already exists
-	existingPr, err := models.GetUnmergedPullRequest(pr.HeadRepoID, pr.BaseRepoID, pr.HeadBranch, targetBranch)
+	existingPr, err := models.GetUnmergedPullRequest(pr.HeadRepoID, pr.BaseRepoID, pr.HeadBranch, targetBranch, pr.Flow)
 	if existingPr != nil {
 		return models.ErrPullRequestAlreadyExists{
 			ID:         existingPr.ID,
@@ -263,6 +333,11 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 						if err := models.MarkReviewsAsNotStale(pr.IssueID, newCommitID); err != nil {
 							log.Error("MarkReviewsAsNotStale: %v", err)
 						}
+						if changed {
+							if err := dismissStaleApprovals(pr, doer); err != nil {
+								log.Error("dismissStaleApprovals: %v", err)
+							}
+						}
 						divergence, err := GetDiverging(pr)
 						if err != nil {
 							log.Error("GetDiverging: %v", err)
@@ -288,6 +363,9 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 
 			AddToTaskQueue(pr)
+			AddToLabelerQueue(pr)
+			AddToCodeOwnersQueue(pr)
+			AddToCLAQueue(pr)
 			comment, err := models.CreatePushPullComment(doer, pr, oldCommitID, newCommitID)
 			if err == nil && comment != nil {
 				notification.NotifyPullRequestPushCommits(doer, pr, comment)
@@ -303,7 +381,7 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 		for _, pr := range prs {
 			divergence, err := GetDiverging(pr)
 			if err != nil {
-				if models.IsErrBranchDoesNotExist(err) && !git.IsBranchExist(pr.HeadRepo.RepoPath(), pr.HeadBranch) {
+				if models.IsErrBranchDoesNotExist(err) && pr.HeadRepo != nil && !git.IsBranchExist(pr.HeadRepo.RepoPath(), pr.HeadBranch) {
 					log.Warn("Cannot test PR %s/%d: head_branch %s no longer exists", pr.BaseRepo.Name, pr.IssueID, pr.HeadBranch)
 				} else {
 					log.Error("GetDiverging: %v", err)
@@ -350,7 +428,7 @@ func checkIfPRContentChanged(pr *models.PullRequest, oldCommitID, newCommitID st
 		}
 	}()
 	// To synchronize repo and get a base ref
-	_, base, err := headGitRepo.GetMergeBase(tmpRemote, pr.BaseBranch, pr.HeadBranch)
+	_, base, err := headGitRepo.GetMergeBase(tmpRemote, pr.BaseBranch, pr.GetHeadRefName())
 	if err != nil {
 		return false, fmt.Errorf("GetMergeBase: %v", err)
 	}
@@ -452,6 +530,21 @@ func pushToBaseRepoHelper(pr *models.PullRequest, prefixHeadBranch string) (err
 	return nil
 }
 
+// UpdateRef points the ref of an AGit pull request to its head commit
+func UpdateRef(pr *models.PullRequest) error {
+	log.Trace("UpdateRef[%d]: updating %s to %s", pr.ID, pr.GetGitRefName(), pr.HeadCommitID)
+	if err := pr.LoadBaseRepo(); err != nil {
+		log.Error("Unable to load base repository for PR[%d] Error: %v", pr.ID, err)
+		return err
+	}
+
+	_, err := git.NewCommand("update-ref", pr.GetGitRefName(), pr.HeadCommitID).RunInDir(pr.BaseRepo.RepoPath())
+	if err != nil {
+		log.Error("Unable to update ref %s in %-v to %s: %v", pr.GetGitRefName(), pr.BaseRepo, pr.HeadCommitID, err)
+	}
+	return err
+}
+
 type errlist []error
 
 func (errs errlist) Error() string {
@@ -547,25 +640,33 @@ func GetSquashMergeCommitMessages(pr *models.PullRequest) string {
 		return ""
 	}
 
-	if pr.HeadRepo == nil {
-		var err error
-		pr.HeadRepo, err = models.GetRepositoryByID(pr.HeadRepoID)
-		if err != nil {
-			log.Error("GetRepositoryById[%d]: %v", pr.HeadRepoID, err)
+	if err := pr.LoadHeadRepo(); err != nil {
+		log.Error("LoadHeadRepo: %v", err)
+		return ""
+	}
+
+	// Fall back to the pull request ref of the base repository if the head repository has been deleted
+	headRepo := pr.HeadRepo
+	headRefName := pr.GetHeadRefName()
+	if headRepo == nil {
+		if err := pr.LoadBaseRepo(); err != nil {
+			log.Error("LoadBaseRepo: %v", err)
 			return ""
 		}
+		headRepo = pr.BaseRepo
+		headRefName = pr.GetGitRefName()
 	}
 
-	gitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	gitRepo, err := git.OpenRepository(headRepo.RepoPath())
 	if err != nil {
 		log.Error("Unable to open head repository: Error: %v", err)
 		return ""
 	}
 	defer gitRepo.Close()
 
-	headCommit, err := gitRepo.GetBranchCommit(pr.HeadBranch)
+	headCommit, err := gitRepo.GetCommit(headRefName)
 	if err != nil {
-		log.Error("Unable to get head commit: %s Error: %v", pr.HeadBranch, err)
+		log.Error("Unable to get head commit: %s Error: %v", headRefName, err)
 		return ""
 	}
 
@@ -776,13 +877,20 @@ func IsHeadEqualWithBranch(pr *models.PullRequest, branchName string) (bool, err
 	if err = pr.LoadHeadRepo(); err != nil {
 		return false, err
 	}
+	if pr.HeadRepo == nil {
+		headCommit, err := baseGitRepo.GetCommit(pr.GetGitRefName())
+		if err != nil {
+			return false, err
+		}
+		return baseCommit.HasPreviousCommit(headCommit.ID)
+	}
 	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
 	if err != nil {
 		return false, err
 	}
 	defer headGitRepo.Close()
 
-	headCommit, err := headGitRepo.GetBranchCommit(pr.HeadBranch)
+	headCommit, err := headGitRepo.GetCommit(pr.GetHeadRefName())
 	if err != nil {
 		return false, err
 	}
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // CreateCodeComment creates a comment on the code line
@@ -100,7 +101,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 
 	if !isReview && !existsReview {
 		// Submit the review we've just created so the comment shows up in the issue view
-		if _, _, err = SubmitReview(doer, gitRepo, issue, models.ReviewTypeComment, "", latestCommitID, nil); err != nil {
+		if _, _, err = submitReview(doer, gitRepo, issue, models.ReviewTypeComment, "", latestCommitID, nil, nil); err != nil {
 			return nil, err
 		}
 	}
@@ -214,8 +215,22 @@ func createCodeComment(doer *models.User, repo *models.Repository, issue *models
 	})
 }
 
-// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
-func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string, attachmentUUIDs []string) (*models.Review, *models.Comment, error) {
+// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist,
+// the checklist of the repository is recorded with the items checked by the reviewer
+func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string, checked, attachmentUUIDs []string) (*models.Review, *models.Comment, error) {
+	pr, err := issue.GetPullRequest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := GetReviewChecklist(gitRepo, pr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return submitReview(doer, gitRepo, issue, reviewType, content, commitID, NewReviewChecklist(items, checked), attachmentUUIDs)
+}
+
+func submitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string, checklist []*models.ReviewChecklistItem, attachmentUUIDs []string) (*models.Review, *models.Comment, error) {
 	pr, err := issue.GetPullRequest()
 	if err != nil {
 		return nil, nil, err
@@ -240,7 +255,7 @@ func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issu
 		}
 	}
 
-	review, comm, err := models.SubmitReview(doer, issue, reviewType, content, commitID, stale, attachmentUUIDs)
+	review, comm, err := models.SubmitReview(doer, issue, reviewType, content, commitID, stale, checklist, attachmentUUIDs)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -318,3 +333,53 @@ func DismissReview(reviewID int64, message string, doer *models.User, isDismiss
 
 	return
 }
+
+// dismissStaleApprovals dismisses the official approvals which went stale and asks
+// their reviewers to review again, if the protected base branch dismisses stale approvals.
+// Dismissed approvals do not count for the merge gate anymore, even when the pushed
+// commits are reverted later.
+func dismissStaleApprovals(pr *models.PullRequest, doer *models.User) error {
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.DismissStaleApprovals {
+		return nil
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return fmt.Errorf("LoadIssue: %v", err)
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		return fmt.Errorf("LoadRepo: %v", err)
+	}
+
+	reviews, err := models.FindReviews(models.FindReviewOptions{
+		Type:         models.ReviewTypeApprove,
+		IssueID:      pr.IssueID,
+		OfficialOnly: true,
+	})
+	if err != nil {
+		return fmt.Errorf("FindReviews: %v", err)
+	}
+
+	for _, review := range reviews {
+		if !review.Stale || review.Dismissed {
+			continue
+		}
+		if err := review.LoadReviewer(); err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("LoadReviewer: %v", err)
+		}
+		if review.Reviewer == nil {
+			continue
+		}
+		if err := models.DismissReview(review, true); err != nil {
+			return fmt.Errorf("DismissReview: %v", err)
+		}
+		if _, err := issue_service.ReviewRequest(pr.Issue, doer, review.Reviewer, true); err != nil {
+			return fmt.Errorf("ReviewRequest: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ReviewChecklistFile is the file of the base branch defining the checklist of the reviews of a pull request
+const ReviewChecklistFile = ".gitea/review-checklist.yml"
+
+type reviewChecklistConfig struct {
+	Items []string `yaml:"items"`
+}
+
+// GetReviewChecklist returns the items of the review checklist defined in the base branch of the pull request
+func GetReviewChecklist(gitRepo *git.Repository, pr *models.PullRequest) ([]string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	commit, err := gitRepo.GetBranchCommit(pr.BaseBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(ReviewChecklistFile)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+		log.Warn("Review checklist of %s is too large", pr.BaseRepo.FullName())
+		return nil, nil
+	}
+
+	rd, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	// an invalid checklist must not prevent reviews
+	items, err := parseReviewChecklist(data)
+	if err != nil {
+		log.Warn("Review checklist of %s: %v", pr.BaseRepo.FullName(), err)
+		return nil, nil
+	}
+	return items, nil
+}
+
+func parseReviewChecklist(data []byte) ([]string, error) {
+	var config reviewChecklistConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", ReviewChecklistFile, err)
+	}
+
+	items := make([]string, 0, len(config.Items))
+	seen := make(map[string]bool, len(config.Items))
+	for _, item := range config.Items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// NewReviewChecklist returns the checklist of a review with the items checked by the reviewer,
+// checked items which are not part of the checklist are ignored
+func NewReviewChecklist(items, checked []string) []*models.ReviewChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	isChecked := make(map[string]bool, len(checked))
+	for _, item := range checked {
+		isChecked[strings.TrimSpace(item)] = true
+	}
+	checklist := make([]*models.ReviewChecklistItem, len(items))
+	for i, item := range items {
+		checklist[i] = &models.ReviewChecklistItem{
+			Text:    item,
+			Checked: isChecked[item],
+		}
+	}
+	return checklist
+}
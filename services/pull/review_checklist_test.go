@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReviewChecklist(t *testing.T) {
+	items, err := parseReviewChecklist([]byte(`items:
+  - Tests cover the change
+  - "  Documentation is updated "
+  - ""
+  - Tests cover the change
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tests cover the change", "Documentation is updated"}, items)
+
+	items, err = parseReviewChecklist([]byte(``))
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = parseReviewChecklist([]byte(`items: {`))
+	assert.Error(t, err)
+}
+
+func TestNewReviewChecklist(t *testing.T) {
+	assert.Nil(t, NewReviewChecklist(nil, []string{"a"}))
+
+	checklist := NewReviewChecklist([]string{"a", "b"}, []string{"b", "c"})
+	assert.Equal(t, []*models.ReviewChecklistItem{
+		{Text: "a", Checked: false},
+		{Text: "b", Checked: true},
+	}, checklist)
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDismissStaleApprovals(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+	assert.NoError(t, pr.LoadIssue())
+	assert.NoError(t, pr.LoadBaseRepo())
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	reviewer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	protectBranch := &models.ProtectedBranch{
+		RepoID:                pr.BaseRepoID,
+		BranchName:            pr.BaseBranch,
+		RequiredApprovals:     1,
+		DismissStaleApprovals: true,
+	}
+	assert.NoError(t, models.UpdateProtectBranch(pr.BaseRepo, protectBranch, models.WhitelistOptions{}))
+
+	approval, err := models.CreateReview(models.CreateReviewOptions{
+		Type:     models.ReviewTypeApprove,
+		Issue:    pr.Issue,
+		Reviewer: reviewer,
+		Official: true,
+		CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, protectBranch.GetGrantedApprovalsCount(pr))
+
+	// a push changing the content of the pull request
+	assert.NoError(t, models.MarkReviewsAsStale(pr.IssueID))
+	assert.NoError(t, dismissStaleApprovals(pr, doer))
+
+	approval = models.AssertExistsAndLoadBean(t, &models.Review{ID: approval.ID}).(*models.Review)
+	assert.True(t, approval.Dismissed)
+	assert.EqualValues(t, 0, protectBranch.GetGrantedApprovalsCount(pr))
+	models.AssertExistsAndLoadBean(t, &models.Review{IssueID: pr.IssueID, ReviewerID: reviewer.ID, Type: models.ReviewTypeRequest})
+
+	// the approval does not count again when the approved commit is pushed back
+	assert.NoError(t, models.MarkReviewsAsNotStale(pr.IssueID, approval.CommitID))
+	assert.EqualValues(t, 0, protectBranch.GetGrantedApprovalsCount(pr))
+}
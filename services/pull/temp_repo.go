@@ -22,11 +22,6 @@ func createTemporaryRepo(pr *models.PullRequest) (string, error) {
 	if err := pr.LoadHeadRepo(); err != nil {
 		log.Error("LoadHeadRepo: %v", err)
 		return "", fmt.Errorf("LoadHeadRepo: %v", err)
-	} else if pr.HeadRepo == nil {
-		log.Error("Pr %d HeadRepo %d does not exist", pr.ID, pr.HeadRepoID)
-		return "", &models.ErrRepoNotExist{
-			ID: pr.HeadRepoID,
-		}
 	} else if err := pr.LoadBaseRepo(); err != nil {
 		log.Error("LoadBaseRepo: %v", err)
 		return "", fmt.Errorf("LoadBaseRepo: %v", err)
@@ -35,10 +30,13 @@ func createTemporaryRepo(pr *models.PullRequest) (string, error) {
 		return "", &models.ErrRepoNotExist{
 			ID: pr.BaseRepoID,
 		}
-	} else if err := pr.HeadRepo.GetOwner(); err != nil {
-		log.Error("HeadRepo.GetOwner: %v", err)
-		return "", fmt.Errorf("HeadRepo.GetOwner: %v", err)
-	} else if err := pr.BaseRepo.GetOwner(); err != nil {
+	} else if pr.HeadRepo != nil {
+		if err := pr.HeadRepo.GetOwner(); err != nil {
+			log.Error("HeadRepo.GetOwner: %v", err)
+			return "", fmt.Errorf("HeadRepo.GetOwner: %v", err)
+		}
+	}
+	if err := pr.BaseRepo.GetOwner(); err != nil {
 		log.Error("BaseRepo.GetOwner: %v", err)
 		return "", fmt.Errorf("BaseRepo.GetOwner: %v", err)
 	}
@@ -51,7 +49,15 @@ func createTemporaryRepo(pr *models.PullRequest) (string, error) {
 	}
 
 	baseRepoPath := pr.BaseRepo.RepoPath()
-	headRepoPath := pr.HeadRepo.RepoPath()
+	headRepoPath := baseRepoPath
+	headRefName := pr.GetGitRefName()
+	if pr.HeadRepo != nil {
+		headRepoPath = pr.HeadRepo.RepoPath()
+		headRefName = pr.GetHeadRefName()
+	} else {
+		// The head repository has been deleted, its head is kept in the pull request ref of the base repository
+		log.Trace("Pr %d HeadRepo %d does not exist, using %s of %s", pr.ID, pr.HeadRepoID, headRefName, pr.BaseRepo.FullName())
+	}
 
 	if err := git.InitRepository(tmpBasePath, false); err != nil {
 		log.Error("git init tmpBasePath: %v", err)
@@ -121,36 +127,48 @@ func createTemporaryRepo(pr *models.PullRequest) (string, error) {
 	errbuf.Reset()
 
 	if err := addCacheRepo(tmpBasePath, headRepoPath); err != nil {
-		log.Error("Unable to add head repository to temporary repo [%s -> %s]: %v", pr.HeadRepo.FullName(), tmpBasePath, err)
+		log.Error("Unable to add head repository to temporary repo [%s -> %s]: %v", pr.HeadRepoFullName(), tmpBasePath, err)
 		if err := models.RemoveTemporaryPath(tmpBasePath); err != nil {
 			log.Error("CreateTempRepo: RemoveTemporaryPath: %s", err)
 		}
-		return "", fmt.Errorf("Unable to head base repository to temporary repo [%s -> tmpBasePath]: %v", pr.HeadRepo.FullName(), err)
+		return "", fmt.Errorf("Unable to head base repository to temporary repo [%s -> tmpBasePath]: %v", pr.HeadRepoFullName(), err)
 	}
 
 	if err := git.NewCommand("remote", "add", remoteRepoName, headRepoPath).RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("Unable to add head repository as head_repo [%s -> %s]: %v\n%s\n%s", pr.HeadRepo.FullName(), tmpBasePath, err, outbuf.String(), errbuf.String())
+		log.Error("Unable to add head repository as head_repo [%s -> %s]: %v\n%s\n%s", pr.HeadRepoFullName(), tmpBasePath, err, outbuf.String(), errbuf.String())
 		if err := models.RemoveTemporaryPath(tmpBasePath); err != nil {
 			log.Error("CreateTempRepo: RemoveTemporaryPath: %s", err)
 		}
-		return "", fmt.Errorf("Unable to add head repository as head_repo [%s -> tmpBasePath]: %v\n%s\n%s", pr.HeadRepo.FullName(), err, outbuf.String(), errbuf.String())
+		return "", fmt.Errorf("Unable to add head repository as head_repo [%s -> tmpBasePath]: %v\n%s\n%s", pr.HeadRepoFullName(), err, outbuf.String(), errbuf.String())
 	}
 	outbuf.Reset()
 	errbuf.Reset()
 
 	trackingBranch := "tracking"
+	if pr.Flow == models.PullRequestFlowAGit && pr.HeadCommitID != "" {
+		// The pull request is being created and has no ref yet, its head commit is available from the cache repo
+		if err := git.NewCommand("update-ref", git.BranchPrefix+trackingBranch, pr.HeadCommitID).RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+			if err := models.RemoveTemporaryPath(tmpBasePath); err != nil {
+				log.Error("CreateTempRepo: RemoveTemporaryPath: %s", err)
+			}
+			log.Error("Unable to set tracking to head commit [%s:%s in %s]: %v:\n%s\n%s", pr.HeadRepoFullName(), pr.HeadCommitID, tmpBasePath, err, outbuf.String(), errbuf.String())
+			return "", fmt.Errorf("Unable to set tracking to head commit [%s:%s in tmpBasePath]: %v\n%s\n%s", pr.HeadRepoFullName(), pr.HeadCommitID, err, outbuf.String(), errbuf.String())
+		}
+		return tmpBasePath, nil
+	}
+
 	// Fetch head branch
-	if err := git.NewCommand("fetch", "--no-tags", remoteRepoName, git.BranchPrefix+pr.HeadBranch+":"+trackingBranch).RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+	if err := git.NewCommand("fetch", "--no-tags", remoteRepoName, headRefName+":"+trackingBranch).RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
 		if err := models.RemoveTemporaryPath(tmpBasePath); err != nil {
 			log.Error("CreateTempRepo: RemoveTemporaryPath: %s", err)
 		}
-		if !git.IsBranchExist(pr.HeadRepo.RepoPath(), pr.HeadBranch) {
+		if pr.Flow == models.PullRequestFlowGithub && pr.HeadRepo != nil && !git.IsBranchExist(pr.HeadRepo.RepoPath(), pr.HeadBranch) {
 			return "", models.ErrBranchDoesNotExist{
 				BranchName: pr.HeadBranch,
 			}
 		}
-		log.Error("Unable to fetch head_repo head branch [%s:%s -> tracking in %s]: %v:\n%s\n%s", pr.HeadRepo.FullName(), pr.HeadBranch, tmpBasePath, err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("Unable to fetch head_repo head branch [%s:%s -> tracking in tmpBasePath]: %v\n%s\n%s", pr.HeadRepo.FullName(), pr.HeadBranch, err, outbuf.String(), errbuf.String())
+		log.Error("Unable to fetch head_repo head branch [%s:%s -> tracking in %s]: %v:\n%s\n%s", pr.HeadRepoFullName(), pr.HeadBranch, tmpBasePath, err, outbuf.String(), errbuf.String())
+		return "", fmt.Errorf("Unable to fetch head_repo head branch [%s:%s -> tracking in tmpBasePath]: %v\n%s\n%s", pr.HeadRepoFullName(), pr.HeadBranch, err, outbuf.String(), errbuf.String())
 	}
 	outbuf.Reset()
 	errbuf.Reset()
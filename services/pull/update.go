@@ -14,6 +14,10 @@ import (
 
 // Update updates pull request with base branch.
 func Update(pull *models.PullRequest, doer *models.User, message string) error {
+	if pull.Flow == models.PullRequestFlowAGit {
+		return fmt.Errorf("PR %d has no head branch to update", pull.Index)
+	}
+
 	//use merge functions but switch repo's and branch's
 	pr := &models.PullRequest{
 		HeadRepoID: pull.BaseRepoID,
@@ -48,7 +52,14 @@ func Update(pull *models.PullRequest, doer *models.User, message string) error {
 
 // IsUserAllowedToUpdate check if user is allowed to update PR with given permissions and branch protections
 func IsUserAllowedToUpdate(pull *models.PullRequest, user *models.User) (bool, error) {
-	if user == nil {
+	if user == nil || pull.Flow == models.PullRequestFlowAGit {
+		// AGit pull requests have no head branch to merge the base branch into
+		return false, nil
+	}
+	if err := pull.LoadHeadRepo(); err != nil {
+		return false, err
+	} else if pull.HeadRepo == nil {
+		// The head branch of a deleted head repository cannot be updated
 		return false, nil
 	}
 	headRepoPerm, err := models.GetUserRepoPermission(pull.HeadRepo, user)
@@ -19,6 +19,10 @@ import (
 )
 
 func createTag(gitRepo *git.Repository, rel *models.Release, msg string) (bool, error) {
+	return doCreateTag(gitRepo, rel, msg, true)
+}
+
+func doCreateTag(gitRepo *git.Repository, rel *models.Release, msg string, checkProtected bool) (bool, error) {
 	var created bool
 	// Only actual create when publish.
 	if !rel.IsDraft {
@@ -28,17 +32,19 @@ func createTag(gitRepo *git.Repository, rel *models.Release, msg string) (bool,
 				return false, err
 			}
 
-			protectedTags, err := rel.Repo.GetProtectedTags()
-			if err != nil {
-				return false, fmt.Errorf("GetProtectedTags: %v", err)
-			}
-			isAllowed, err := models.IsUserAllowedToControlTag(protectedTags, rel.TagName, rel.PublisherID)
-			if err != nil {
-				return false, err
-			}
-			if !isAllowed {
-				return false, models.ErrProtectedTagName{
-					TagName: rel.TagName,
+			if checkProtected {
+				protectedTags, err := rel.Repo.GetProtectedTags()
+				if err != nil {
+					return false, fmt.Errorf("GetProtectedTags: %v", err)
+				}
+				isAllowed, err := models.IsUserAllowedToControlTag(protectedTags, rel.TagName, rel.PublisherID)
+				if err != nil {
+					return false, err
+				}
+				if !isAllowed {
+					return false, models.ErrProtectedTagName{
+						TagName: rel.TagName,
+					}
 				}
 			}
 
@@ -135,6 +141,16 @@ func CreateRelease(gitRepo *git.Repository, rel *models.Release, attachmentUUIDs
 
 // CreateNewTag creates a new repository tag
 func CreateNewTag(doer *models.User, repo *models.Repository, commit, tagName, msg string) error {
+	return createNewTag(doer, repo, commit, tagName, msg, true)
+}
+
+// CreateSystemTag creates a new repository tag on behalf of the instance, the protected tags of the repository
+// do not apply to it so that it can create tags nobody else may push
+func CreateSystemTag(doer *models.User, repo *models.Repository, commit, tagName, msg string) error {
+	return createNewTag(doer, repo, commit, tagName, msg, false)
+}
+
+func createNewTag(doer *models.User, repo *models.Repository, commit, tagName, msg string, checkProtected bool) error {
 	isExist, err := models.IsReleaseExist(repo.ID, tagName)
 	if err != nil {
 		return err
@@ -162,7 +178,7 @@ func CreateNewTag(doer *models.User, repo *models.Repository, commit, tagName, m
 		IsTag:        true,
 	}
 
-	if _, err = createTag(gitRepo, rel, msg); err != nil {
+	if _, err = doCreateTag(gitRepo, rel, msg, checkProtected); err != nil {
 		return err
 	}
 
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// CanRequestAccess returns true if access requests are enabled and doer has no access to the repository
+// of an organization it is a member of
+func CanRequestAccess(doer *models.User, repo *models.Repository) (bool, error) {
+	if !setting.Repository.EnableAccessRequests {
+		return false, nil
+	}
+	if requestable, err := models.IsRepoAccessRequestable(doer, repo); err != nil || !requestable {
+		return false, err
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.AccessMode == models.AccessModeNone, nil
+}
+
+// RequestAccess creates a request of doer to access the repository and notifies its administrators
+func RequestAccess(doer *models.User, repo *models.Repository, message string) (*models.RepoAccessRequest, error) {
+	req := &models.RepoAccessRequest{
+		RepoID:  repo.ID,
+		UserID:  doer.ID,
+		Message: message,
+		Repo:    repo,
+		User:    doer,
+	}
+	if err := models.CreateRepoAccessRequest(req); err != nil {
+		return nil, err
+	}
+
+	notification.NotifyRepoAccessRequest(doer, req)
+	return req, nil
+}
+
+// ApproveAccessRequest approves a pending access request, its user becomes a collaborator with the given access mode
+func ApproveAccessRequest(doer *models.User, req *models.RepoAccessRequest, mode models.AccessMode) error {
+	if err := req.LoadAttributes(); err != nil {
+		return err
+	}
+	collaboration, err := req.Repo.GetCollaboration(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := models.ApproveRepoAccessRequest(req, doer, mode); err != nil {
+		return err
+	}
+
+	if collaboration == nil {
+		notification.NotifyAddCollaborator(doer, req.Repo, req.User, mode)
+	} else if collaboration.Mode != mode {
+		notification.NotifyChangeCollaboratorAccessMode(doer, req.Repo, req.User, collaboration.Mode, mode)
+	}
+	notification.NotifyRepoAccessRequestReviewed(doer, req)
+	return nil
+}
+
+// RejectAccessRequest rejects a pending access request
+func RejectAccessRequest(doer *models.User, req *models.RepoAccessRequest) error {
+	if err := req.LoadAttributes(); err != nil {
+		return err
+	}
+	if err := models.RejectRepoAccessRequest(req, doer); err != nil {
+		return err
+	}
+
+	notification.NotifyRepoAccessRequestReviewed(doer, req)
+	return nil
+}
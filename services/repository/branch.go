@@ -10,7 +10,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/validation"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
 
@@ -18,6 +20,7 @@ import (
 var (
 	ErrBranchIsDefault   = errors.New("branch is default")
 	ErrBranchIsProtected = errors.New("branch is protected")
+	ErrBranchNameInvalid = errors.New("branch name is invalid")
 )
 
 // DeleteBranch delete branch
@@ -70,3 +73,40 @@ func DeleteBranch(doer *models.User, repo *models.Repository, gitRepo *git.Repos
 
 	return nil
 }
+
+// RenameBranch renames a branch of the repository, along with the default branch, the branch protection,
+// the pull requests and the issues referencing it
+func RenameBranch(doer *models.User, repo *models.Repository, gitRepo *git.Repository, from, to string) error {
+	if from == to {
+		return nil
+	}
+	if to == "" || validation.GitRefNamePatternInvalid.MatchString(to) || !validation.CheckGitRefAdditionalRulesValid(to) {
+		return ErrBranchNameInvalid
+	}
+	if !gitRepo.IsBranchExist(from) {
+		return models.ErrBranchDoesNotExist{
+			BranchName: from,
+		}
+	}
+	if err := repo_module.CheckBranchName(repo, to); err != nil {
+		return err
+	}
+
+	if err := models.RenameBranch(repo, from, to, func(isDefault bool) error {
+		if err := gitRepo.RenameBranch(from, to); err != nil {
+			return err
+		}
+		if isDefault {
+			if err := gitRepo.SetDefaultBranch(to); err != nil && !git.IsErrUnsupportedVersion(err) {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notification.NotifyDeleteRef(doer, repo, "branch", git.BranchPrefix+from)
+	notification.NotifyCreateRef(doer, repo, "branch", git.BranchPrefix+to)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// maxForkNetworkSize is the maximum number of repositories listed in a fork network
+const maxForkNetworkSize = 1000
+
+// ForkNetworkEntry represents a repository of a fork network
+type ForkNetworkEntry struct {
+	Repo   *models.Repository
+	Parent *models.Repository
+	Depth  int
+	// Divergence of the default branch of the fork from the default branch of its parent,
+	// nil for the root of the network or if either branch does not exist
+	Divergence *git.DivergeObject
+}
+
+// GetForkNetwork returns a page of the repositories of the fork network of the repository which the doer can read,
+// starting with its root and listing every fork after its parent, and the total number of repositories of the network.
+// The divergences are only computed for the repositories of the page as each of them runs git in both repositories.
+func GetForkNetwork(doer *models.User, repo *models.Repository, listOptions models.ListOptions) ([]*ForkNetworkEntry, int, error) {
+	// The network is shown from the topmost repository the doer can read
+	root := repo
+	seen := map[int64]bool{root.ID: true}
+	for root.IsFork {
+		parent, err := models.GetRepositoryByID(root.ForkID)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				break
+			}
+			return nil, 0, err
+		}
+		if seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		if canRead, err := canReadRepo(doer, parent); err != nil {
+			return nil, 0, err
+		} else if !canRead {
+			break
+		}
+		root = parent
+	}
+	if err := root.GetOwner(); err != nil {
+		return nil, 0, err
+	}
+
+	entries := []*ForkNetworkEntry{{Repo: root}}
+	for i := 0; i < len(entries) && len(entries) < maxForkNetworkSize; i++ {
+		parent := entries[i]
+		forks, err := parent.Repo.GetForks(models.ListOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		children := make([]*ForkNetworkEntry, 0, len(forks))
+		for _, fork := range forks {
+			if canRead, err := canReadRepo(doer, fork); err != nil {
+				return nil, 0, err
+			} else if !canRead {
+				continue
+			}
+			if err := fork.GetOwner(); err != nil {
+				return nil, 0, err
+			}
+			children = append(children, &ForkNetworkEntry{
+				Repo:   fork,
+				Parent: parent.Repo,
+				Depth:  parent.Depth + 1,
+			})
+		}
+
+		// Insert the forks right after their parent
+		entries = append(entries[:i+1], append(children, entries[i+1:]...)...)
+	}
+	if len(entries) > maxForkNetworkSize {
+		entries = entries[:maxForkNetworkSize]
+	}
+
+	start, end := listOptions.GetStartEnd()
+	if end > len(entries) {
+		end = len(entries)
+	}
+	if start > end {
+		start = end
+	}
+	page := entries[start:end]
+	for _, entry := range page {
+		if entry.Parent != nil {
+			entry.Divergence = getForkDivergence(entry.Parent, entry.Repo)
+		}
+	}
+	return page, len(entries), nil
+}
+
+// canReadRepo returns true if the doer can read the code of the repository
+func canReadRepo(doer *models.User, repo *models.Repository) (bool, error) {
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanRead(models.UnitTypeCode), nil
+}
+
+// getForkDivergence returns the divergence of the default branch of the fork from the default branch of its parent
+func getForkDivergence(parent, fork *models.Repository) *git.DivergeObject {
+	if parent.IsEmpty || fork.IsEmpty {
+		return nil
+	}
+	parentCommitID, err := git.GetFullCommitID(parent.RepoPath(), git.BranchPrefix+parent.DefaultBranch)
+	if err != nil {
+		return nil
+	}
+	forkCommitID, err := git.GetFullCommitID(fork.RepoPath(), git.BranchPrefix+fork.DefaultBranch)
+	if err != nil {
+		return nil
+	}
+	divergence, err := git.GetDivergingCommitsBetweenRepos(parent.RepoPath(), parentCommitID, fork.RepoPath(), forkCommitID)
+	if err != nil {
+		log.Error("GetDivergingCommitsBetweenRepos[%s, %s]: %v", parent.FullName(), fork.FullName(), err)
+		return nil
+	}
+	return &divergence
+}
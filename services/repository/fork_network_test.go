@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetForkNetwork(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 10}).(*models.Repository)
+
+	entries, total, err := GetForkNetwork(doer, repo, models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	if assert.Len(t, entries, 2) {
+		assert.EqualValues(t, 10, entries[0].Repo.ID)
+		assert.Nil(t, entries[0].Parent)
+		assert.EqualValues(t, 11, entries[1].Repo.ID)
+		assert.EqualValues(t, 10, entries[1].Parent.ID)
+		assert.Equal(t, 1, entries[1].Depth)
+	}
+
+	entries, total, err = GetForkNetwork(doer, repo, models.ListOptions{Page: 2, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, 11, entries[0].Repo.ID)
+	}
+
+	entries, total, err = GetForkNetwork(doer, repo, models.ListOptions{Page: 3, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Empty(t, entries)
+}
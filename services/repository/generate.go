@@ -68,6 +68,13 @@ func GenerateRepository(doer, owner *models.User, templateRepo *models.Repositor
 			}
 		}
 
+		// Units and their settings
+		if opts.Units {
+			if err = models.GenerateUnits(ctx, templateRepo, generateRepo); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		if generateRepo != nil && generateRepo.ID > 0 {
@@ -0,0 +1,173 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// commitActivityWeeks is the number of weeks covered by the commit activity
+const commitActivityWeeks = 52
+
+const week = 7 * 24 * time.Hour
+
+// weekStart returns the start of the week of t, weeks start on Sunday at midnight UTC
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day()-int(t.Weekday()), 0, 0, 0, 0, time.UTC)
+}
+
+// GetCommitStats returns the changes of the commits of the default branch,
+// they are cached until the branch moves
+func GetCommitStats(repo *models.Repository, gitRepo *git.Repository) ([]*git.CommitStat, error) {
+	commitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	value, err := cache.GetString(fmt.Sprintf("repo_stats_%d_%s", repo.ID, commitID), func() (string, error) {
+		commits, err := gitRepo.GetCommitStats(commitID)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(commits)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*git.CommitStat
+	if err := json.Unmarshal([]byte(value), &commits); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// weekRange returns the start of the weeks of the oldest and the newest commits
+func weekRange(commits []*git.CommitStat) (first, last time.Time) {
+	for i, c := range commits {
+		start := weekStart(c.AuthorTime)
+		if i == 0 || start.Before(first) {
+			first = start
+		}
+		if i == 0 || start.After(last) {
+			last = start
+		}
+	}
+	return first, last
+}
+
+// GetContributorStats returns the activity of the authors of the commits for every week
+// between the oldest and the newest commit, ordered by number of commits
+func GetContributorStats(commits []*git.CommitStat, doer *models.User) ([]*api.ContributorStats, error) {
+	contributors := make([]*api.ContributorStats, 0, 10)
+	if len(commits) == 0 {
+		return contributors, nil
+	}
+	first, last := weekRange(commits)
+	weeks := int(last.Sub(first)/week) + 1
+
+	byAuthor := make(map[string]*api.ContributorStats)
+	for _, c := range commits {
+		key := c.AuthorEmail
+		if key == "" {
+			key = c.AuthorName
+		}
+		contributor, ok := byAuthor[key]
+		if !ok {
+			contributor = &api.ContributorStats{
+				Name:  c.AuthorName,
+				Email: c.AuthorEmail,
+				Weeks: make([]*api.ContributorWeekStats, weeks),
+			}
+			for i := range contributor.Weeks {
+				contributor.Weeks[i] = &api.ContributorWeekStats{Week: first.Add(time.Duration(i) * week).Unix()}
+			}
+			byAuthor[key] = contributor
+			contributors = append(contributors, contributor)
+		}
+		w := contributor.Weeks[weekStart(c.AuthorTime).Sub(first)/week]
+		w.Additions += c.Additions
+		w.Deletions += c.Deletions
+		w.Commits++
+		contributor.Total++
+	}
+
+	for _, contributor := range contributors {
+		if contributor.Email == "" {
+			continue
+		}
+		user, err := models.GetUserByEmail(contributor.Email)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		contributor.Author = convert.ToUser(user, doer)
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Total > contributors[j].Total
+	})
+	return contributors, nil
+}
+
+// GetCommitActivity returns the number of commits of each day of the last 52 weeks up to now
+func GetCommitActivity(commits []*git.CommitStat, now time.Time) []*api.CommitActivity {
+	first := weekStart(now).Add(-(commitActivityWeeks - 1) * week)
+	activity := make([]*api.CommitActivity, commitActivityWeeks)
+	for i := range activity {
+		activity[i] = &api.CommitActivity{
+			Days: make([]int64, 7),
+			Week: first.Add(time.Duration(i) * week).Unix(),
+		}
+	}
+
+	for _, c := range commits {
+		if c.AuthorTime.Before(first) {
+			continue
+		}
+		i := int(weekStart(c.AuthorTime).Sub(first) / week)
+		if i >= commitActivityWeeks {
+			continue
+		}
+		activity[i].Days[c.AuthorTime.UTC().Weekday()]++
+		activity[i].Total++
+	}
+	return activity
+}
+
+// GetCodeFrequency returns the additions and the deletions, as a negative number, of every week
+// between the oldest and the newest commit as [week, additions, deletions]
+func GetCodeFrequency(commits []*git.CommitStat) [][]int64 {
+	frequency := make([][]int64, 0, 10)
+	if len(commits) == 0 {
+		return frequency
+	}
+	first, last := weekRange(commits)
+	for start := first; !start.After(last); start = start.Add(week) {
+		frequency = append(frequency, []int64{start.Unix(), 0, 0})
+	}
+
+	for _, c := range commits {
+		w := frequency[weekStart(c.AuthorTime).Sub(first)/week]
+		w[1] += c.Additions
+		w[2] -= c.Deletions
+	}
+	return frequency
+}
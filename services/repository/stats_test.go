@@ -0,0 +1,96 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCommitStats = []*git.CommitStat{
+	// Sunday 2021-06-13
+	{AuthorName: "User Two", AuthorEmail: "user2@example.com", AuthorTime: time.Date(2021, 6, 13, 10, 0, 0, 0, time.UTC), Additions: 5, Deletions: 1},
+	// Saturday 2021-06-12
+	{AuthorName: "Someone", AuthorEmail: "someone@example.com", AuthorTime: time.Date(2021, 6, 12, 23, 0, 0, 0, time.UTC), Additions: 2},
+	// Tuesday 2021-06-01
+	{AuthorName: "User Two", AuthorEmail: "user2@example.com", AuthorTime: time.Date(2021, 6, 1, 8, 0, 0, 0, time.UTC), Additions: 10, Deletions: 3},
+	{AuthorName: "User Two", AuthorEmail: "user2@example.com", AuthorTime: time.Date(2021, 6, 1, 9, 0, 0, 0, time.UTC), Deletions: 4},
+}
+
+var (
+	testWeek1 = time.Date(2021, 5, 30, 0, 0, 0, 0, time.UTC).Unix()
+	testWeek2 = time.Date(2021, 6, 6, 0, 0, 0, 0, time.UTC).Unix()
+	testWeek3 = time.Date(2021, 6, 13, 0, 0, 0, 0, time.UTC).Unix()
+)
+
+func TestGetContributorStats(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	contributors, err := GetContributorStats(testCommitStats, nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, contributors, 2) {
+		return
+	}
+
+	assert.EqualValues(t, 3, contributors[0].Total)
+	if assert.NotNil(t, contributors[0].Author) {
+		assert.Equal(t, "user2", contributors[0].Author.UserName)
+	}
+	if assert.Len(t, contributors[0].Weeks, 3) {
+		assert.Equal(t, testWeek1, contributors[0].Weeks[0].Week)
+		assert.EqualValues(t, 2, contributors[0].Weeks[0].Commits)
+		assert.EqualValues(t, 10, contributors[0].Weeks[0].Additions)
+		assert.EqualValues(t, 7, contributors[0].Weeks[0].Deletions)
+		assert.Equal(t, testWeek2, contributors[0].Weeks[1].Week)
+		assert.EqualValues(t, 0, contributors[0].Weeks[1].Commits)
+		assert.Equal(t, testWeek3, contributors[0].Weeks[2].Week)
+		assert.EqualValues(t, 1, contributors[0].Weeks[2].Commits)
+	}
+
+	assert.EqualValues(t, 1, contributors[1].Total)
+	assert.Nil(t, contributors[1].Author)
+	assert.Equal(t, "someone@example.com", contributors[1].Email)
+	if assert.Len(t, contributors[1].Weeks, 3) {
+		assert.EqualValues(t, 1, contributors[1].Weeks[1].Commits)
+	}
+
+	contributors, err = GetContributorStats(nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, contributors)
+}
+
+func TestGetCommitActivity(t *testing.T) {
+	activity := GetCommitActivity(testCommitStats, time.Date(2021, 6, 16, 0, 0, 0, 0, time.UTC))
+	if !assert.Len(t, activity, 52) {
+		return
+	}
+	assert.Equal(t, testWeek3, activity[51].Week)
+	assert.EqualValues(t, 1, activity[51].Total)
+	assert.Equal(t, []int64{1, 0, 0, 0, 0, 0, 0}, activity[51].Days)
+	assert.Equal(t, []int64{0, 0, 0, 0, 0, 0, 1}, activity[50].Days)
+	assert.Equal(t, testWeek1, activity[49].Week)
+	assert.Equal(t, []int64{0, 0, 2, 0, 0, 0, 0}, activity[49].Days)
+	assert.EqualValues(t, 0, activity[0].Total)
+
+	// the commits are more than a year old
+	activity = GetCommitActivity(testCommitStats, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+	for _, w := range activity {
+		assert.EqualValues(t, 0, w.Total)
+	}
+}
+
+func TestGetCodeFrequency(t *testing.T) {
+	assert.Equal(t, [][]int64{
+		{testWeek1, 10, -7},
+		{testWeek2, 2, 0},
+		{testWeek3, 5, -1},
+	}, GetCodeFrequency(testCommitStats))
+	assert.Empty(t, GetCodeFrequency(nil))
+}
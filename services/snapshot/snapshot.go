@@ -0,0 +1,161 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	archiver_service "code.gitea.io/gitea/services/archiver"
+	release_service "code.gitea.io/gitea/services/release"
+)
+
+// tagDateLayout is the layout of the date appended to the prefix of the snapshot tags
+const tagDateLayout = "20060102"
+
+// archiveExtensions are the formats of the archives generated for the snapshots
+var archiveExtensions = []string{".zip", ".tar.gz"}
+
+// TagName returns the name of the snapshot tag taken at t
+func TagName(s *models.RepoSnapshot, t time.Time) string {
+	return s.TagPrefix + t.UTC().Format(tagDateLayout)
+}
+
+// IsSnapshotTag returns true if the tag was named by the snapshots
+func IsSnapshotTag(s *models.RepoSnapshot, tagName string) bool {
+	return regexp.MustCompile("^" + regexp.QuoteMeta(s.TagPrefix) + `\d{8}$`).MatchString(tagName)
+}
+
+// SaveSettings enables the snapshots of a repository or updates their settings,
+// and protects the snapshot tags from everybody so that they are immutable
+func SaveSettings(s *models.RepoSnapshot) error {
+	if err := models.SaveRepoSnapshot(s); err != nil {
+		return err
+	}
+	if err := s.LoadRepo(); err != nil {
+		return err
+	}
+
+	protectedTags, err := s.Repo.GetProtectedTags()
+	if err != nil {
+		return err
+	}
+	for _, pt := range protectedTags {
+		if pt.NamePattern == s.ProtectedTagPattern() {
+			return nil
+		}
+	}
+	return models.InsertProtectedTag(&models.ProtectedTag{
+		RepoID:      s.RepoID,
+		NamePattern: s.ProtectedTagPattern(),
+	})
+}
+
+// CreateSnapshot tags the head of the branch of the snapshots unless it was already tagged on this day,
+// generates the archives of the tag if enabled and deletes the snapshots exceeding the retention
+func CreateSnapshot(s *models.RepoSnapshot, now time.Time) error {
+	if err := s.LoadRepo(); err != nil {
+		return err
+	}
+	repo := s.Repo
+	if repo.IsArchived || repo.IsEmpty {
+		return nil
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	tagName := TagName(s, now)
+	if !gitRepo.IsTagExist(tagName) {
+		commitID, err := gitRepo.GetBranchCommitID(s.Branch)
+		if err != nil {
+			return fmt.Errorf("GetBranchCommitID: %v", err)
+		}
+		msg := fmt.Sprintf("Snapshot of %s on %s", s.Branch, now.UTC().Format("2006-01-02"))
+		if err := release_service.CreateSystemTag(repo.Owner, repo, commitID, tagName, msg); err != nil {
+			return fmt.Errorf("CreateSystemTag: %v", err)
+		}
+		log.Info("Created snapshot %s of %s in %s", tagName, s.Branch, repo.FullName())
+	}
+
+	if s.GenerateArchives {
+		for _, ext := range archiveExtensions {
+			req, err := archiver_service.NewRequest(repo.ID, gitRepo, tagName+ext)
+			if err != nil {
+				return fmt.Errorf("NewRequest: %v", err)
+			}
+			if _, err := archiver_service.ArchiveRepository(req); err != nil {
+				return fmt.Errorf("ArchiveRepository: %v", err)
+			}
+		}
+	}
+
+	s.LastTagName = tagName
+	s.LastSnapshotUnix = timeutil.TimeStamp(now.Unix())
+	if err := models.UpdateRepoSnapshotLast(s); err != nil {
+		return err
+	}
+	return deleteExpiredSnapshots(s)
+}
+
+// deleteExpiredSnapshots deletes the oldest snapshot tags exceeding the retention
+func deleteExpiredSnapshots(s *models.RepoSnapshot) error {
+	if s.Retention <= 0 {
+		return nil
+	}
+
+	rels, err := s.GetReleases()
+	if err != nil {
+		return err
+	}
+	var kept int
+	for _, rel := range rels {
+		if !IsSnapshotTag(s, rel.TagName) {
+			continue
+		}
+		kept++
+		if kept <= s.Retention {
+			continue
+		}
+		if err := release_service.DeleteReleaseByID(rel.ID, s.Repo.Owner, true); err != nil {
+			return fmt.Errorf("DeleteReleaseByID: %v", err)
+		}
+		log.Info("Deleted expired snapshot %s in %s", rel.TagName, s.Repo.FullName())
+	}
+	return nil
+}
+
+// CreateSnapshots creates the snapshots of all repositories with enabled snapshots
+func CreateSnapshots(ctx context.Context) error {
+	snapshots, err := models.GetRepoSnapshots()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, s := range snapshots {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before snapshot of repository %d", s.RepoID)
+		default:
+		}
+		if err := CreateSnapshot(s, now); err != nil {
+			log.Error("CreateSnapshot[%d]: %v", s.RepoID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", ".."))
+}
+
+func TestTagName(t *testing.T) {
+	s := &models.RepoSnapshot{TagPrefix: "nightly-"}
+	assert.Equal(t, "nightly-20210302", TagName(s, time.Date(2021, 3, 2, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, IsSnapshotTag(s, "nightly-20210302"))
+	assert.False(t, IsSnapshotTag(s, "nightly-build"))
+	assert.False(t, IsSnapshotTag(s, "v1.0"))
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	s := &models.RepoSnapshot{RepoID: 1, Branch: "master", TagPrefix: "nightly-", Retention: 2}
+	assert.NoError(t, SaveSettings(s))
+	models.AssertExistsAndLoadBean(t, &models.ProtectedTag{RepoID: 1, NamePattern: "nightly-*"})
+	assert.NoError(t, SaveSettings(s))
+	assert.EqualValues(t, 1, models.GetCount(t, &models.ProtectedTag{RepoID: 1, NamePattern: "nightly-*"}))
+
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, CreateSnapshot(s, day.AddDate(0, 0, i)))
+	}
+	// taking the snapshot of the same day again does nothing
+	assert.NoError(t, CreateSnapshot(s, day.AddDate(0, 0, 2)))
+
+	s, err := models.GetRepoSnapshot(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "nightly-20210303", s.LastTagName)
+
+	rels, err := s.GetReleases()
+	assert.NoError(t, err)
+	if assert.Len(t, rels, 2) {
+		assert.Equal(t, "nightly-20210303", rels[0].TagName)
+		assert.Equal(t, "nightly-20210302", rels[1].TagName)
+	}
+
+	assert.NoError(t, s.LoadRepo())
+	gitRepo, err := git.OpenRepository(s.Repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+	assert.True(t, gitRepo.IsTagExist("nightly-20210302"))
+	assert.False(t, gitRepo.IsTagExist("nightly-20210301"))
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+)
+
+// snippetBranch is the branch the files of a snippet are committed to
+const snippetBranch = "master"
+
+var snippetWorkingPool = sync.NewExclusivePool()
+
+// File represents a file of a snippet
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// ErrInvalidFile represents a "InvalidFile" kind of error.
+type ErrInvalidFile struct {
+	Name   string
+	Reason string
+}
+
+// IsErrInvalidFile checks if an error is a ErrInvalidFile.
+func IsErrInvalidFile(err error) bool {
+	_, ok := err.(ErrInvalidFile)
+	return ok
+}
+
+func (err ErrInvalidFile) Error() string {
+	return fmt.Sprintf("invalid snippet file [name: %s]: %s", err.Name, err.Reason)
+}
+
+// ErrFileNotExist represents a "FileNotExist" kind of error.
+type ErrFileNotExist struct {
+	Name string
+}
+
+// IsErrFileNotExist checks if an error is a ErrFileNotExist.
+func IsErrFileNotExist(err error) bool {
+	_, ok := err.(ErrFileNotExist)
+	return ok
+}
+
+func (err ErrFileNotExist) Error() string {
+	return fmt.Sprintf("snippet file does not exist [name: %s]", err.Name)
+}
+
+func validateFiles(files []*File) error {
+	if len(files) == 0 {
+		return ErrInvalidFile{Reason: "a snippet needs at least one file"}
+	}
+	if setting.Repository.Upload.MaxFiles > 0 && len(files) > setting.Repository.Upload.MaxFiles {
+		return ErrInvalidFile{Reason: fmt.Sprintf("a snippet can't have more than %d files", setting.Repository.Upload.MaxFiles)}
+	}
+
+	names := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f.Name == "" || f.Name == "." || f.Name == ".." || strings.ContainsAny(f.Name, "/\\") {
+			return ErrInvalidFile{Name: f.Name, Reason: "file names can't be empty or contain path separators"}
+		}
+		if names[strings.ToLower(f.Name)] {
+			return ErrInvalidFile{Name: f.Name, Reason: "duplicate file name"}
+		}
+		names[strings.ToLower(f.Name)] = true
+		if int64(len(f.Content)) > setting.Repository.Upload.FileMaxSize*1024*1024 {
+			return ErrInvalidFile{Name: f.Name, Reason: fmt.Sprintf("file is larger than %d MB", setting.Repository.Upload.FileMaxSize)}
+		}
+	}
+	return nil
+}
+
+// commitFiles replaces the files of the snippet repository by the given ones in a new commit
+func commitFiles(doer *models.User, repoPath string, files []*File, message string) error {
+	gitRepo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	if err = gitRepo.EmptyIndex(); err != nil {
+		return fmt.Errorf("EmptyIndex: %v", err)
+	}
+	for _, f := range files {
+		objectHash, err := gitRepo.HashObject(bytes.NewReader(f.Content))
+		if err != nil {
+			return fmt.Errorf("HashObject: %v", err)
+		}
+		if err = gitRepo.AddObjectToIndex("100644", objectHash, f.Name); err != nil {
+			return fmt.Errorf("AddObjectToIndex: %v", err)
+		}
+	}
+	tree, err := gitRepo.WriteTree()
+	if err != nil {
+		return fmt.Errorf("WriteTree: %v", err)
+	}
+
+	opts := git.CommitTreeOpts{Message: message, NoGPGSign: true}
+	if parent, err := gitRepo.GetBranchCommitID(snippetBranch); err == nil {
+		opts.Parents = []string{parent}
+	}
+	sig := doer.NewGitSig()
+	commitHash, err := gitRepo.CommitTree(sig, sig, tree, opts)
+	if err != nil {
+		return fmt.Errorf("CommitTree: %v", err)
+	}
+
+	if _, err = git.NewCommand("update-ref", git.BranchPrefix+snippetBranch, commitHash.String()).RunInDir(repoPath); err != nil {
+		return fmt.Errorf("update-ref: %v", err)
+	}
+	return nil
+}
+
+// CreateSnippet creates a snippet with the given files for the doer
+func CreateSnippet(doer *models.User, description string, visibility models.SnippetVisibility, files []*File) (*models.Snippet, error) {
+	if err := validateFiles(files); err != nil {
+		return nil, err
+	}
+
+	s := &models.Snippet{
+		UUID:        strings.ReplaceAll(gouuid.New().String(), "-", ""),
+		OwnerID:     doer.ID,
+		Owner:       doer,
+		Description: description,
+		Visibility:  visibility,
+	}
+
+	repoPath := s.RepoPath()
+	if err := os.MkdirAll(filepath.Dir(repoPath), os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := git.InitRepository(repoPath, true); err != nil {
+		return nil, fmt.Errorf("InitRepository: %v", err)
+	}
+	if _, err := git.NewCommand("symbolic-ref", "HEAD", git.BranchPrefix+snippetBranch).RunInDir(repoPath); err != nil {
+		return nil, fmt.Errorf("symbolic-ref: %v", err)
+	}
+
+	if err := commitFiles(doer, repoPath, files, "Create snippet"); err != nil {
+		removeRepo(repoPath)
+		return nil, err
+	}
+	if err := models.InsertSnippet(s); err != nil {
+		removeRepo(repoPath)
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpdateSnippetFiles replaces the files of a snippet by the given ones
+func UpdateSnippetFiles(doer *models.User, s *models.Snippet, files []*File) error {
+	if err := validateFiles(files); err != nil {
+		return err
+	}
+
+	snippetWorkingPool.CheckIn(s.UUID)
+	defer snippetWorkingPool.CheckOut(s.UUID)
+
+	if err := commitFiles(doer, s.RepoPath(), files, "Update snippet"); err != nil {
+		return err
+	}
+	return models.TouchSnippet(s)
+}
+
+// GetFiles returns the files of a snippet in alphabetical order
+func GetFiles(s *models.Snippet) ([]*File, error) {
+	gitRepo, err := git.OpenRepository(s.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(snippetBranch)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := commit.Tree.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*File, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsRegular() {
+			continue
+		}
+		rd, err := entry.Blob().DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(rd)
+		rd.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &File{Name: entry.Name(), Content: content})
+	}
+	return files, nil
+}
+
+// GetFile returns the file of a snippet with given name
+func GetFile(s *models.Snippet, name string) (*File, error) {
+	files, err := GetFiles(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, ErrFileNotExist{name}
+}
+
+// DeleteSnippet deletes a snippet, its comments and its repository
+func DeleteSnippet(s *models.Snippet) error {
+	if err := models.DeleteSnippet(s); err != nil {
+		return err
+	}
+	removeRepo(s.RepoPath())
+	return nil
+}
+
+func removeRepo(repoPath string) {
+	if err := util.RemoveAll(repoPath); err != nil {
+		log.Warn("Failed to remove snippet repository %s: %v", repoPath, err)
+		if err = models.CreateRepositoryNotice("Failed to remove snippet repository %s: %v", repoPath, err); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+	}
+}
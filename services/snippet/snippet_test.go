@@ -0,0 +1,89 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", ".."))
+}
+
+func TestSnippet(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	other := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+
+	_, err := CreateSnippet(owner, "", models.SnippetVisibilityPublic, []*File{{Name: "a/b.go"}})
+	assert.True(t, IsErrInvalidFile(err))
+	_, err = CreateSnippet(owner, "", models.SnippetVisibilityPublic, []*File{{Name: "a.go"}, {Name: "A.go"}})
+	assert.True(t, IsErrInvalidFile(err))
+
+	s, err := CreateSnippet(owner, "hello", models.SnippetVisibilityPrivate, []*File{
+		{Name: "main.go", Content: []byte("package main\n")},
+		{Name: "README.md", Content: []byte("# Hello\n")},
+	})
+	assert.NoError(t, err)
+	exist, err := util.IsExist(s.RepoPath())
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	files, err := GetFiles(s)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "README.md", files[0].Name)
+		assert.Equal(t, "package main\n", string(files[1].Content))
+	}
+
+	assert.NoError(t, UpdateSnippetFiles(owner, s, []*File{{Name: "main.go", Content: []byte("package foo\n")}}))
+	f, err := GetFile(s, "main.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "package foo\n", string(f.Content))
+	_, err = GetFile(s, "README.md")
+	assert.True(t, IsErrFileNotExist(err))
+
+	// private snippets are neither visible nor listed to others
+	assert.True(t, s.CanRead(owner))
+	assert.False(t, s.CanRead(other))
+	assert.False(t, s.CanRead(nil))
+	_, count, err := models.SearchSnippets(&models.SearchSnippetOptions{Actor: other})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	_, count, err = models.SearchSnippets(&models.SearchSnippetOptions{Actor: owner})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// unlisted snippets are visible to everyone but only listed to their owner
+	s.Visibility = models.SnippetVisibilityUnlisted
+	assert.NoError(t, models.UpdateSnippet(s))
+	assert.True(t, s.CanRead(nil))
+	_, count, err = models.SearchSnippets(&models.SearchSnippetOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	c, err := models.CreateSnippetComment(s, other, "nice")
+	assert.NoError(t, err)
+	s, err = models.GetSnippetByUUID(s.UUID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, s.NumComments)
+	assert.NoError(t, models.DeleteSnippetComment(c))
+	s, err = models.GetSnippetByUUID(s.UUID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, s.NumComments)
+
+	assert.NoError(t, DeleteSnippet(s))
+	_, err = models.GetSnippetByUUID(s.UUID)
+	assert.True(t, models.IsErrSnippetNotExist(err))
+	exist, err = util.IsExist(s.RepoPath())
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
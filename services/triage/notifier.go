@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package triage
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type triageNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &triageNotifier{}
+)
+
+// NotifyNewIssue triages the opened issue
+func (*triageNotifier) NotifyNewIssue(issue *models.Issue, mentions []*models.User) {
+	if !issue.IsPull {
+		addToQueue(issue)
+	}
+}
+
+// NotifyIssueChangeTitle triages the issue again when its title is edited
+func (*triageNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string) {
+	if !issue.IsPull {
+		addToQueue(issue)
+	}
+}
+
+// NotifyIssueChangeContent triages the issue again when its description is edited
+func (*triageNotifier) NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string) {
+	if !issue.IsPull {
+		addToQueue(issue)
+	}
+}
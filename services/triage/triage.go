@@ -0,0 +1,227 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	comment_service "code.gitea.io/gitea/services/comments"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// triageQueue represents a queue to apply the triage rules to the opened and edited issues
+	triageQueue queue.UniqueQueue
+
+	commentRegexp = regexp.MustCompile(`(?s)<!--.*?-->`)
+	headingRegexp = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+)
+
+// Init creates and runs the triage queue and listens to opened and edited issues
+func Init() error {
+	triageQueue = queue.CreateUniqueQueue("issue_triage", handle, "").(queue.UniqueQueue)
+	if triageQueue == nil {
+		return fmt.Errorf("Unable to create issue_triage Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(triageQueue.Run)
+
+	notification.RegisterNotifier(&triageNotifier{})
+	return nil
+}
+
+// handle applies the triage rules to the issues of the passed IDs
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		id, _ := strconv.ParseInt(datum.(string), 10, 64)
+
+		issue, err := models.GetIssueByID(id)
+		if err != nil {
+			log.Error("GetIssueByID[%s]: %v", datum, err)
+			continue
+		}
+		if err := Triage(issue); err != nil {
+			log.Error("Triage[%d]: %v", issue.ID, err)
+		}
+	}
+}
+
+// addToQueue adds the issue to the triage queue
+func addToQueue(issue *models.Issue) {
+	if err := triageQueue.Push(strconv.FormatInt(issue.ID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding issue %d to the triage queue: %v", issue.ID, err)
+	}
+}
+
+// MissingSections returns the sections absent from the markdown content or left empty.
+// Sections are introduced by headings, and HTML comments left by issue templates are not content.
+func MissingSections(content string, sections []string) []string {
+	filled := make(map[string]bool)
+	var current string
+	for _, line := range strings.Split(commentRegexp.ReplaceAllString(content, ""), "\n") {
+		if match := headingRegexp.FindStringSubmatch(line); match != nil {
+			current = strings.ToLower(match[1])
+			continue
+		}
+		if current != "" && strings.TrimSpace(line) != "" {
+			filled[current] = true
+		}
+	}
+
+	var missing []string
+	for _, section := range sections {
+		if !filled[strings.ToLower(section)] {
+			missing = append(missing, section)
+		}
+	}
+	return missing
+}
+
+// Match returns true if the issue fulfills the condition of the rule
+func Match(rule *models.IssueTriageRule, issue *models.Issue) (bool, error) {
+	switch rule.Condition {
+	case models.IssueTriageTitleMatches:
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(issue.Title), nil
+	case models.IssueTriageMissingSections:
+		return len(MissingSections(issue.Content, rule.Sections())) > 0, nil
+	case models.IssueTriageAuthorIsMember:
+		if err := issue.LoadRepo(); err != nil {
+			return false, err
+		}
+		if err := issue.Repo.GetOwner(); err != nil {
+			return false, err
+		}
+		if !issue.Repo.Owner.IsOrganization() || issue.PosterID <= 0 {
+			return false, nil
+		}
+		return issue.Repo.Owner.IsOrgMember(issue.PosterID)
+	default:
+		return false, fmt.Errorf("unknown triage condition %d", rule.Condition)
+	}
+}
+
+// Triage applies to the issue the actions of the triage rules of its repository it matches,
+// once per rule and issue
+func Triage(issue *models.Issue) error {
+	if issue.IsPull {
+		return nil
+	}
+	rules, err := models.GetIssueTriageRules(issue.RepoID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	if err := issue.LoadRepo(); err != nil {
+		return err
+	}
+	if err := issue.Repo.GetOwner(); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		matched, err := Match(rule, issue)
+		if err != nil {
+			log.Error("Unable to match triage rule %d with issue %d: %v", rule.ID, issue.ID, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		if applied, err := models.MarkIssueTriageRuleApplied(rule.ID, issue.ID); err != nil {
+			return err
+		} else if !applied {
+			continue
+		}
+		if err := apply(rule, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply labels, comments on and assigns the issue as configured by the rule, on behalf of the repository owner
+func apply(rule *models.IssueTriageRule, issue *models.Issue) error {
+	doer := issue.Repo.Owner
+
+	if rule.Label != nil && !issue.HasLabel(rule.Label.ID) {
+		if err := issue_service.AddLabel(issue, doer, rule.Label); err != nil {
+			return err
+		}
+	}
+
+	if rule.Comment != "" {
+		if _, err := comment_service.CreateIssueComment(doer, issue.Repo, issue, rule.Comment, nil); err != nil {
+			return err
+		}
+	}
+
+	if rule.Team != nil && rule.Team.OrgID == issue.Repo.OwnerID {
+		members, err := models.GetTeamMembers(rule.Team.ID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := issue_service.AddAssigneeIfNotAssigned(issue, doer, member.ID); err != nil {
+				if models.IsErrUserDoesNotHaveAccessToRepo(err) {
+					continue
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExportedRule is a triage rule as exported to YAML
+type ExportedRule struct {
+	Condition string   `yaml:"condition"`
+	Pattern   string   `yaml:"pattern,omitempty"`
+	Sections  []string `yaml:"sections,omitempty"`
+	Label     string   `yaml:"label,omitempty"`
+	Comment   string   `yaml:"comment,omitempty"`
+	Team      string   `yaml:"team,omitempty"`
+}
+
+// ExportRules returns the triage rules of the repository as YAML, referencing labels and teams by name
+func ExportRules(repo *models.Repository) ([]byte, error) {
+	rules, err := models.GetIssueTriageRules(repo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]*ExportedRule, 0, len(rules))
+	for _, rule := range rules {
+		e := &ExportedRule{
+			Condition: rule.Condition.Name(),
+			Comment:   rule.Comment,
+		}
+		switch rule.Condition {
+		case models.IssueTriageTitleMatches:
+			e.Pattern = rule.Pattern
+		case models.IssueTriageMissingSections:
+			e.Sections = rule.Sections()
+		}
+		if rule.Label != nil {
+			e.Label = rule.Label.Name
+		}
+		if rule.Team != nil {
+			e.Team = rule.Team.Name
+		}
+		exported = append(exported, e)
+	}
+	return yaml.Marshal(exported)
+}
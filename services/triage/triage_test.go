@@ -0,0 +1,111 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package triage
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingSections(t *testing.T) {
+	sections := []string{"Description", "Steps to reproduce", "Logs"}
+
+	content := `## Description
+It crashes.
+
+<!-- How can we reproduce it? -->
+### Steps To Reproduce ###
+
+## Logs
+<!-- Paste the logs here -->
+`
+	assert.Equal(t, []string{"Steps to reproduce", "Logs"}, MissingSections(content, sections))
+	assert.Equal(t, sections, MissingSections("It crashes.", sections))
+	assert.Empty(t, MissingSections("# description\nIt crashes.\n# Steps to reproduce\nRun it.\n# Logs\npanic", sections))
+}
+
+func TestMatch(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	matched, err := Match(&models.IssueTriageRule{Condition: models.IssueTriageTitleMatches, Pattern: "^issue[0-9]$"}, issue)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	matched, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageTitleMatches, Pattern: "(?i)crash"}, issue)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	_, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageTitleMatches, Pattern: "("}, issue)
+	assert.Error(t, err)
+
+	matched, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageMissingSections, Pattern: "Logs"}, issue)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	// the repository of issue 1 is not owned by an organization
+	matched, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageAuthorIsMember}, issue)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	issue = models.AssertExistsAndLoadBean(t, &models.Issue{ID: 6}).(*models.Issue)
+	issue.PosterID = 2
+	matched, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageAuthorIsMember}, issue)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	issue.PosterID = 5
+	matched, err = Match(&models.IssueTriageRule{Condition: models.IssueTriageAuthorIsMember}, issue)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestTriage(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	rule := &models.IssueTriageRule{
+		RepoID:    1,
+		Condition: models.IssueTriageMissingSections,
+		Pattern:   "Steps to reproduce",
+		LabelID:   2,
+		Comment:   "Please describe how to reproduce the issue.",
+	}
+	assert.NoError(t, models.InsertIssueTriageRule(rule))
+	assert.NoError(t, models.InsertIssueTriageRule(&models.IssueTriageRule{
+		RepoID:    1,
+		Condition: models.IssueTriageTitleMatches,
+		Pattern:   "crash",
+		Comment:   "Please attach the logs.",
+	}))
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.False(t, issue.HasLabel(2))
+	assert.NoError(t, Triage(issue))
+	assert.True(t, issue.HasLabel(2))
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, Type: models.CommentTypeComment, Content: rule.Comment})
+	models.AssertNotExistsBean(t, &models.Comment{IssueID: 1, Type: models.CommentTypeComment, Content: "Please attach the logs."})
+	models.AssertExistsAndLoadBean(t, &models.IssueTriageRun{RuleID: rule.ID, IssueID: 1})
+
+	// the actions of a rule are applied once
+	label := models.AssertExistsAndLoadBean(t, &models.Label{ID: 2}).(*models.Label)
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NoError(t, issue_service.RemoveLabel(issue, doer, label))
+	assert.NoError(t, Triage(issue))
+	assert.False(t, issue.HasLabel(2))
+	models.AssertCount(t, &models.Comment{IssueID: 1, Type: models.CommentTypeComment, Content: rule.Comment}, 1)
+
+	content, err := ExportRules(&models.Repository{ID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `- condition: missing_sections
+  sections:
+  - Steps to reproduce
+  label: label2
+  comment: Please describe how to reproduce the issue.
+- condition: title_matches
+  pattern: crash
+  comment: Please attach the logs.
+`, string(content))
+}
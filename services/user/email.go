@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// EmailVerificationRequired returns true if new email addresses must be verified before they can be used
+func EmailVerificationRequired() bool {
+	return setting.Service.RegisterEmailConfirm || setting.Service.EmailChangeRequireVerification
+}
+
+// AddEmailAddresses adds email addresses to the user,
+// they are sent a verification email if they have to be verified before they can be used
+func AddEmailAddresses(u *models.User, addresses []string) ([]*models.EmailAddress, error) {
+	requireVerification := EmailVerificationRequired()
+	emails := make([]*models.EmailAddress, len(addresses))
+	for i := range addresses {
+		emails[i] = &models.EmailAddress{
+			UID:         u.ID,
+			Email:       addresses[i],
+			IsActivated: !requireVerification,
+		}
+	}
+	if err := models.AddEmailAddresses(emails); err != nil {
+		return nil, err
+	}
+
+	if requireVerification {
+		for _, email := range emails {
+			mailer.SendActivateEmailMail(u, email)
+		}
+	}
+	return emails, nil
+}
+
+// MakeEmailPrimary makes a verified email address of the user its primary address,
+// the previous primary address is notified if setting.Service.EmailChangeNotifyOldAddress is enabled
+func MakeEmailPrimary(u *models.User, emailID int64) error {
+	oldEmail := u.Email
+	email := &models.EmailAddress{ID: emailID, UID: u.ID}
+	if err := models.MakeEmailPrimary(email); err != nil {
+		return err
+	}
+	u.Email = email.Email
+
+	if setting.Service.EmailChangeNotifyOldAddress && oldEmail != "" && oldEmail != email.Email {
+		mailer.SendPrimaryEmailChangedMail(u, oldEmail)
+	}
+	return nil
+}
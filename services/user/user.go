@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrUsernameChangeCooldown represents a "UsernameChangeCooldown" kind of error.
+type ErrUsernameChangeCooldown struct {
+	Name  string
+	Until time.Time
+}
+
+// IsErrUsernameChangeCooldown checks if an error is a ErrUsernameChangeCooldown.
+func IsErrUsernameChangeCooldown(err error) bool {
+	_, ok := err.(ErrUsernameChangeCooldown)
+	return ok
+}
+
+func (err ErrUsernameChangeCooldown) Error() string {
+	return fmt.Sprintf("username has been changed too recently [name: %s, until: %s]", err.Name, err.Until)
+}
+
+// RenameUser changes the name of the user or the organization u, the old name redirects to the new one.
+// The name can only be changed once per setting.Service.UsernameChangeCooldown unless doer is an administrator.
+// As with models.ChangeUserName, the caller has to update the name columns of u.
+func RenameUser(doer, u *models.User, newName string) error {
+	if setting.Service.UsernameChangeCooldown > 0 && !doer.IsAdmin {
+		lastRename, err := models.GetLastUserRenameTime(u.ID)
+		if err != nil {
+			return err
+		}
+		if until := lastRename.Add(setting.Service.UsernameChangeCooldown); !lastRename.IsZero() && until.After(time.Now()) {
+			return ErrUsernameChangeCooldown{Name: u.Name, Until: until}
+		}
+	}
+
+	oldName := u.Name
+	if err := models.ChangeUserName(u, newName); err != nil {
+		return err
+	}
+	log.Trace("User renamed by %s: %s -> %s", doer.Name, oldName, newName)
+	return nil
+}
@@ -14,7 +14,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,8 +23,12 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/hostmatcher"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
+	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
 	"github.com/gobwas/glob"
 )
 
@@ -123,6 +126,10 @@ func Deliver(t *models.HookTask) error {
 	req.Header.Add("X-Hub-Signature-256", "sha256="+signatureSHA256)
 	req.Header["X-GitHub-Delivery"] = []string{t.UUID}
 	req.Header["X-GitHub-Event"] = []string{t.EventType.Event()}
+	// A custom signature header is sent along the ones above, it can't replace a header Gitea already sends.
+	if signatureHeader := w.GetSignatureHeader(); len(w.Secret) > 0 && len(req.Header.Values(signatureHeader)) == 0 {
+		req.Header.Set(signatureHeader, signatureSHA256)
+	}
 
 	// Record delivery information.
 	t.RequestInfo = &models.HookRequest{
@@ -140,6 +147,7 @@ func Deliver(t *models.HookTask) error {
 
 	defer func() {
 		t.Delivered = time.Now().UnixNano()
+		metrics.ObserveWebhookDelivery(string(w.Type), t.IsSucceed)
 		if t.IsSucceed {
 			log.Trace("Hook delivered: %s", t.UUID)
 		} else {
@@ -151,15 +159,17 @@ func Deliver(t *models.HookTask) error {
 		}
 
 		// Update webhook last delivery status.
-		if t.IsSucceed {
-			w.LastStatus = models.HookStatusSucceed
-		} else {
-			w.LastStatus = models.HookStatusFail
-		}
-		if err = models.UpdateWebhookLastStatus(w); err != nil {
-			log.Error("UpdateWebhookLastStatus: %v", err)
+		disabled, err := models.UpdateWebhookDeliveryStatus(w, t.IsSucceed)
+		if err != nil {
+			log.Error("UpdateWebhookDeliveryStatus: %v", err)
 			return
 		}
+		if disabled {
+			log.Warn("Webhook[%d] disabled after %d failed deliveries", w.ID, w.FailureCount)
+			if err := notifyWebhookDisabled(w); err != nil {
+				log.Error("notifyWebhookDisabled[%d]: %v", w.ID, err)
+			}
+		}
 	}()
 
 	if setting.DisableWebhooks {
@@ -189,6 +199,43 @@ func Deliver(t *models.HookTask) error {
 	return nil
 }
 
+// notifyWebhookDisabled notifies the owner of a webhook disabled because its deliveries kept failing,
+// the administrators are notified of the default and system webhooks.
+func notifyWebhookDisabled(w *models.Webhook) error {
+	var owner *models.User
+	var link string
+	switch {
+	case w.RepoID > 0:
+		repo, err := models.GetRepositoryByID(w.RepoID)
+		if err != nil {
+			return err
+		}
+		if err := repo.GetOwner(); err != nil {
+			return err
+		}
+		owner = repo.Owner
+		link = fmt.Sprintf("%s/settings/hooks/%d", repo.HTMLURL(), w.ID)
+	case w.OrgID > 0:
+		var err error
+		if owner, err = models.GetUserByID(w.OrgID); err != nil {
+			return err
+		}
+		if owner.IsOrganization() {
+			link = fmt.Sprintf("%sorg/%s/settings/hooks/%d", setting.AppURL, url.PathEscape(owner.Name), w.ID)
+		} else {
+			link = fmt.Sprintf("%suser/settings/hooks/%d", setting.AppURL, w.ID)
+		}
+	default:
+		return models.CreateNotice(models.NoticeRepository, fmt.Sprintf("Webhook %d (%s) disabled after %d failed deliveries",
+			w.ID, w.URL, w.FailureCount))
+	}
+
+	if setting.MailService == nil {
+		return nil
+	}
+	return mailer.SendWebhookDisabledMail(owner, w, link)
+}
+
 // DeliverHooks checks and delivers undelivered hooks.
 // FIXME: graceful: This would likely benefit from either a worker pool with dummy queue
 // or a full queue. Then more hooks could be sent at same time.
@@ -260,7 +307,7 @@ var (
 
 func webhookProxy() func(req *http.Request) (*url.URL, error) {
 	if setting.Webhook.ProxyURL == "" {
-		return http.ProxyFromEnvironment
+		return proxy.Proxy()
 	}
 
 	once.Do(func() {
@@ -279,7 +326,7 @@ func webhookProxy() func(req *http.Request) (*url.URL, error) {
 				return http.ProxyURL(setting.Webhook.ProxyURLFixed)(req)
 			}
 		}
-		return http.ProxyFromEnvironment(req)
+		return proxy.Proxy()(req)
 	}
 }
 
@@ -287,13 +334,15 @@ func webhookProxy() func(req *http.Request) (*url.URL, error) {
 func InitDeliverHooks() {
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 
+	checker := hostmatcher.NewHTTPChecker("webhook",
+		hostmatcher.ParseHostMatchList("webhook.ALLOWED_HOST_LIST", setting.Webhook.AllowedHostList),
+		hostmatcher.ParseHostMatchList("webhook.BLOCKED_HOST_LIST", setting.Webhook.BlockedHostList))
+
 	webhookHTTPClient = &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify},
-			Proxy:           webhookProxy(),
-			Dial: func(netw, addr string) (net.Conn, error) {
-				return net.DialTimeout(netw, addr, timeout) // dial timeout
-			},
+			Proxy:           checker.Proxy(webhookProxy()),
+			DialContext:     checker.DialContext(timeout), // dial timeout
 		},
 		Timeout: timeout, // request timeout
 	}
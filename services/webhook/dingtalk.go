@@ -161,6 +161,20 @@ func (d *DingtalkPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 	return nil, nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (d *DingtalkPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, _ := getRepositorySettingsPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view repository", p.Repository.HTMLURL), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (d *DingtalkPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, _ := getDeploymentPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view pull request", p.PullRequest.HTMLURL), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (d *DingtalkPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	text, _ := getReleasePayloadInfo(p, noneLinkFormatter, true)
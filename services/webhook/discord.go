@@ -238,6 +238,20 @@ func (d *DiscordPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	return d.createPayload(p.Sender, title, "", url, color), nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (d *DiscordPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, color := getRepositorySettingsPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL+"/settings", color), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (d *DiscordPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, color := getDeploymentPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.PullRequest.HTMLURL, color), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (d *DiscordPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	text, color := getReleasePayloadInfo(p, noneLinkFormatter, false)
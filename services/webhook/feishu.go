@@ -148,6 +148,20 @@ func (f *FeishuPayload) Repository(p *api.RepositoryPayload) (api.Payloader, err
 	return nil, nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (f *FeishuPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, _ := getRepositorySettingsPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (f *FeishuPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, _ := getDeploymentPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (f *FeishuPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	text, _ := getReleasePayloadInfo(p, noneLinkFormatter, true)
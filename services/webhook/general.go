@@ -159,6 +159,69 @@ func getReleasePayloadInfo(p *api.ReleasePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+func getRepositorySettingsPayloadInfo(p *api.RepositorySettingsPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	var branchName, collaborator string
+	if p.BranchProtection != nil {
+		branchName = p.BranchProtection.BranchName
+	}
+	if p.Collaborator != nil {
+		collaborator = linkFormatter(setting.AppURL+p.Collaborator.UserName, p.Collaborator.UserName)
+	}
+
+	switch p.Action {
+	case api.HookRepoSettingsBranchProtectionCreated:
+		text = fmt.Sprintf("[%s] Branch protection created: %s", repoLink, branchName)
+		color = greenColor
+	case api.HookRepoSettingsBranchProtectionUpdated:
+		text = fmt.Sprintf("[%s] Branch protection updated: %s", repoLink, branchName)
+		color = yellowColor
+	case api.HookRepoSettingsBranchProtectionDeleted:
+		text = fmt.Sprintf("[%s] Branch protection deleted: %s", repoLink, branchName)
+		color = redColor
+	case api.HookRepoSettingsCollaboratorAdded:
+		text = fmt.Sprintf("[%s] Collaborator added: %s (%s)", repoLink, collaborator, p.Permission)
+		color = greenColor
+	case api.HookRepoSettingsCollaboratorUpdated:
+		text = fmt.Sprintf("[%s] Collaborator permission changed: %s (%s)", repoLink, collaborator, p.Permission)
+		color = yellowColor
+	case api.HookRepoSettingsCollaboratorRemoved:
+		text = fmt.Sprintf("[%s] Collaborator removed: %s", repoLink, collaborator)
+		color = redColor
+	case api.HookRepoSettingsVisibilityChanged:
+		if p.Repository.Private {
+			text = fmt.Sprintf("[%s] Repository made private", repoLink)
+		} else {
+			text = fmt.Sprintf("[%s] Repository made public", repoLink)
+		}
+		color = yellowColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getDeploymentPayloadInfo(p *api.DeploymentPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	pullLink := linkFormatter(p.PullRequest.HTMLURL, fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title))
+
+	switch p.Action {
+	case api.HookDeploymentCreated:
+		text = fmt.Sprintf("[%s] Environment %s deployed for pull request %s: %s", repoLink, p.Deployment.Environment, pullLink, linkFormatter(p.Deployment.EnvironmentURL, p.Deployment.EnvironmentURL))
+		color = greenColor
+	case api.HookDeploymentTornDown:
+		text = fmt.Sprintf("[%s] Environment %s of pull request %s torn down", repoLink, p.Deployment.Environment, pullLink)
+		color = greyColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
 func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFormatter, withSender bool) (string, string, int) {
 	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
 	issueTitle := fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
@@ -271,6 +271,57 @@ func repositoryTestPayload() *api.RepositoryPayload {
 	}
 }
 
+func repositorySettingsTestPayload() *api.RepositorySettingsPayload {
+	return &api.RepositorySettingsPayload{
+		Action: api.HookRepoSettingsBranchProtectionCreated,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+			Private:  true,
+		},
+		BranchProtection: &api.BranchProtection{
+			BranchName: "master",
+		},
+		Collaborator: &api.User{
+			UserName: "user2",
+		},
+		Permission: "admin",
+	}
+}
+
+func deploymentTestPayload() *api.DeploymentPayload {
+	return &api.DeploymentPayload{
+		Action: api.HookDeploymentCreated,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		PullRequest: &api.PullRequest{
+			ID:      12,
+			Index:   12,
+			HTMLURL: "http://localhost:3000/test/repo/pulls/12",
+			Title:   "Fix bug",
+		},
+		Deployment: &api.Deployment{
+			ID:             1,
+			PullRequest:    12,
+			Environment:    "review",
+			EnvironmentURL: "https://pr-12.review.example.com",
+			Active:         true,
+		},
+	}
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
@@ -502,6 +553,87 @@ func TestGetReleasePayloadInfo(t *testing.T) {
 	}
 }
 
+func TestGetRepositorySettingsPayloadInfo(t *testing.T) {
+	p := repositorySettingsTestPayload()
+
+	cases := []struct {
+		action api.HookRepoSettingsAction
+		text   string
+		color  int
+	}{
+		{
+			api.HookRepoSettingsBranchProtectionCreated,
+			"[test/repo] Branch protection created: master by user1",
+			greenColor,
+		},
+		{
+			api.HookRepoSettingsBranchProtectionUpdated,
+			"[test/repo] Branch protection updated: master by user1",
+			yellowColor,
+		},
+		{
+			api.HookRepoSettingsBranchProtectionDeleted,
+			"[test/repo] Branch protection deleted: master by user1",
+			redColor,
+		},
+		{
+			api.HookRepoSettingsCollaboratorAdded,
+			"[test/repo] Collaborator added: user2 (admin) by user1",
+			greenColor,
+		},
+		{
+			api.HookRepoSettingsCollaboratorUpdated,
+			"[test/repo] Collaborator permission changed: user2 (admin) by user1",
+			yellowColor,
+		},
+		{
+			api.HookRepoSettingsCollaboratorRemoved,
+			"[test/repo] Collaborator removed: user2 by user1",
+			redColor,
+		},
+		{
+			api.HookRepoSettingsVisibilityChanged,
+			"[test/repo] Repository made private by user1",
+			yellowColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		text, color := getRepositorySettingsPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetDeploymentPayloadInfo(t *testing.T) {
+	p := deploymentTestPayload()
+
+	cases := []struct {
+		action api.HookDeploymentAction
+		text   string
+		color  int
+	}{
+		{
+			api.HookDeploymentCreated,
+			"[test/repo] Environment review deployed for pull request #12 Fix bug: https://pr-12.review.example.com by user1",
+			greenColor,
+		},
+		{
+			api.HookDeploymentTornDown,
+			"[test/repo] Environment review of pull request #12 Fix bug torn down by user1",
+			greyColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		text, color := getDeploymentPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
 func TestGetIssueCommentPayloadInfo(t *testing.T) {
 	p := pullRequestCommentTestPayload()
 
@@ -223,6 +223,20 @@ func (m *MatrixPayloadUnsafe) Repository(p *api.RepositoryPayload) (api.Payloade
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (m *MatrixPayloadUnsafe) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, _ := getRepositorySettingsPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (m *MatrixPayloadUnsafe) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, _ := getDeploymentPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
 // GetMatrixPayload converts a Matrix webhook into a MatrixPayloadUnsafe
 func GetMatrixPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	s := new(MatrixPayloadUnsafe)
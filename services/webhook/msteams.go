@@ -172,7 +172,7 @@ func (m *MSTeamsPayload) Issue(p *api.IssuePayload) (api.Payloader, error) {
 		attachmentText,
 		p.Issue.HTMLURL,
 		color,
-		&MSTeamsFact{"Issue #:", fmt.Sprintf("%d", p.Issue.ID)},
+		&MSTeamsFact{"Issue #:", fmt.Sprintf("%d", p.Issue.Index)},
 	), nil
 }
 
@@ -187,7 +187,7 @@ func (m *MSTeamsPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader
 		p.Comment.Body,
 		p.Comment.HTMLURL,
 		color,
-		&MSTeamsFact{"Issue #:", fmt.Sprintf("%d", p.Issue.ID)},
+		&MSTeamsFact{"Issue #:", fmt.Sprintf("%d", p.Issue.Index)},
 	), nil
 }
 
@@ -202,7 +202,7 @@ func (m *MSTeamsPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader,
 		attachmentText,
 		p.PullRequest.HTMLURL,
 		color,
-		&MSTeamsFact{"Pull request #:", fmt.Sprintf("%d", p.PullRequest.ID)},
+		&MSTeamsFact{"Pull request #:", fmt.Sprintf("%d", p.PullRequest.Index)},
 	), nil
 }
 
@@ -239,7 +239,7 @@ func (m *MSTeamsPayload) Review(p *api.PullRequestPayload, event models.HookEven
 		text,
 		p.PullRequest.HTMLURL,
 		color,
-		&MSTeamsFact{"Pull request #:", fmt.Sprintf("%d", p.PullRequest.ID)},
+		&MSTeamsFact{"Pull request #:", fmt.Sprintf("%d", p.PullRequest.Index)},
 	), nil
 }
 
@@ -268,6 +268,36 @@ func (m *MSTeamsPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	), nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (m *MSTeamsPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	title, color := getRepositorySettingsPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL+"/settings",
+		color,
+		nil,
+	), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (m *MSTeamsPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	title, color := getDeploymentPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.PullRequest.HTMLURL,
+		color,
+		nil,
+	), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (m *MSTeamsPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	title, color := getReleasePayloadInfo(p, noneLinkFormatter, false)
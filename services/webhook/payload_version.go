@@ -0,0 +1,73 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// payloadVersionFields are the fields added by each payload version, by key of the objects holding them
+var payloadVersionFields = map[models.HookPayloadVersion]map[string][]string{
+	models.PayloadVersion2: {
+		"issue":      {"reactions"},
+		"comment":    {"reactions"},
+		"repository": {"repo_transfer"},
+	},
+}
+
+// versionedPayload is a payload rendered with the schema of a version older than the latest one
+type versionedPayload map[string]interface{}
+
+// JSONPayload implements Payloader
+func (p versionedPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// toPayloadVersion renders the payload with the schema of the given version by removing the fields added after it
+func toPayloadVersion(p api.Payloader, version models.HookPayloadVersion) (api.Payloader, error) {
+	if version == 0 || version >= models.PayloadVersionLatest {
+		return p, nil
+	}
+
+	data, err := p.JSONPayload()
+	if err != nil {
+		return nil, err
+	}
+	var payload versionedPayload
+	// keep the numbers as they are, int64 IDs don't fit in float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	for v := models.PayloadVersionLatest; v > version; v-- {
+		removePayloadFields(map[string]interface{}(payload), payloadVersionFields[v])
+	}
+	return payload, nil
+}
+
+// removePayloadFields removes the fields of the objects found at any depth under the given keys
+func removePayloadFields(value interface{}, fields map[string][]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if obj, ok := child.(map[string]interface{}); ok {
+				for _, field := range fields[key] {
+					delete(obj, field)
+				}
+			}
+			removePayloadFields(child, fields)
+		}
+	case []interface{}:
+		for _, child := range v {
+			removePayloadFields(child, fields)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPayloadVersion(t *testing.T) {
+	p := &api.IssueCommentPayload{
+		Action: api.HookIssueCommentCreated,
+		Issue: &api.Issue{
+			ID:        1,
+			Title:     "issue1",
+			Reactions: &api.ReactionSummary{TotalCount: 1},
+		},
+		Comment: &api.Comment{
+			ID:        9007199254740993,
+			Reactions: &api.ReactionSummary{},
+		},
+		Repository: &api.Repository{
+			ID:           1,
+			RepoTransfer: &api.RepoTransfer{},
+		},
+	}
+
+	latest, err := toPayloadVersion(p, models.PayloadVersionLatest)
+	assert.NoError(t, err)
+	assert.Equal(t, p, latest)
+
+	legacy, err := toPayloadVersion(p, models.PayloadVersionLegacy)
+	assert.NoError(t, err)
+	payload := legacy.(versionedPayload)
+	assert.NotContains(t, payload["issue"], "reactions")
+	assert.NotContains(t, payload["comment"], "reactions")
+	assert.NotContains(t, payload["repository"], "repo_transfer")
+	assert.Equal(t, "issue1", payload["issue"].(map[string]interface{})["title"])
+	assert.EqualValues(t, json.Number("9007199254740993"), payload["comment"].(map[string]interface{})["id"])
+
+	data, err := legacy.JSONPayload()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "reactions")
+	assert.Contains(t, string(data), "9007199254740993")
+}
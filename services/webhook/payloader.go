@@ -21,6 +21,8 @@ type PayloadConvertor interface {
 	PullRequest(*api.PullRequestPayload) (api.Payloader, error)
 	Review(*api.PullRequestPayload, models.HookEventType) (api.Payloader, error)
 	Repository(*api.RepositoryPayload) (api.Payloader, error)
+	RepositorySettings(*api.RepositorySettingsPayload) (api.Payloader, error)
+	Deployment(*api.DeploymentPayload) (api.Payloader, error)
 	Release(*api.ReleasePayload) (api.Payloader, error)
 }
 
@@ -49,6 +51,10 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEven
 		return s.Review(p.(*api.PullRequestPayload), event)
 	case models.HookEventRepository:
 		return s.Repository(p.(*api.RepositoryPayload))
+	case models.HookEventRepositorySettings:
+		return s.RepositorySettings(p.(*api.RepositorySettingsPayload))
+	case models.HookEventDeployment:
+		return s.Deployment(p.(*api.DeploymentPayload))
 	case models.HookEventRelease:
 		return s.Release(p.(*api.ReleasePayload))
 	}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// repoEventPayloads are the payloads of the events recorded to be replayed, by payload type
+var repoEventPayloads = map[string]func() api.Payloader{
+	"push":          func() api.Payloader { return &api.PushPayload{} },
+	"issue":         func() api.Payloader { return &api.IssuePayload{} },
+	"issue_comment": func() api.Payloader { return &api.IssueCommentPayload{} },
+	"pull_request":  func() api.Payloader { return &api.PullRequestPayload{} },
+}
+
+func repoEventPayloadType(p api.Payloader) string {
+	switch p.(type) {
+	case *api.PushPayload:
+		return "push"
+	case *api.IssuePayload:
+		return "issue"
+	case *api.IssueCommentPayload:
+		return "issue_comment"
+	case *api.PullRequestPayload:
+		return "pull_request"
+	}
+	return ""
+}
+
+// recordRepoEvent records the push, issue and pull request events of the repository to be replayed
+func recordRepoEvent(repo *models.Repository, event models.HookEventType, p api.Payloader) {
+	if setting.Webhook.EventRetention <= 0 {
+		return
+	}
+	payloadType := repoEventPayloadType(p)
+	if payloadType == "" {
+		return
+	}
+
+	data, err := p.JSONPayload()
+	if err != nil {
+		log.Error("Unable to marshal the payload of %s event of %s: %v", event, repo.FullName(), err)
+		return
+	}
+	if err := models.CreateRepoEvent(&models.RepoEvent{
+		RepoID:         repo.ID,
+		EventType:      event,
+		PayloadType:    payloadType,
+		PayloadContent: string(data),
+	}); err != nil {
+		log.Error("CreateRepoEvent [repo: %s, event: %s]: %v", repo.FullName(), event, err)
+	}
+}
+
+// ReplayRepoEvent delivers a recorded event of the repository again.
+// If w is not nil, the event is delivered to it only, whatever its events and branch filter, and its hook task is returned.
+// Otherwise the event is delivered to the active webhooks of the repository, its owner and the system as when it happened.
+func ReplayRepoEvent(repo *models.Repository, e *models.RepoEvent, w *models.Webhook) (*models.HookTask, error) {
+	newPayload, ok := repoEventPayloads[e.PayloadType]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload type %q of repository event %d", e.PayloadType, e.ID)
+	}
+	p := newPayload()
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	if err := json.Unmarshal([]byte(e.PayloadContent), p); err != nil {
+		return nil, fmt.Errorf("unmarshal payload of repository event %d: %v", e.ID, err)
+	}
+
+	// the replayed event is not recorded again
+	var t *models.HookTask
+	var err error
+	if w == nil {
+		err = prepareWebhooks(repo, e.EventType, p)
+	} else {
+		t, err = createHookTask(w, repo, e.EventType, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go hookQueue.Add(repo.ID)
+	return t, nil
+}
@@ -264,6 +264,20 @@ func (s *SlackPayload) Repository(p *api.RepositoryPayload) (api.Payloader, erro
 	return s.createPayload(text, nil), nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (s *SlackPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, _ := getRepositorySettingsPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (s *SlackPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, _ := getDeploymentPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
 func (s *SlackPayload) createPayload(text string, attachments []SlackAttachment) *SlackPayload {
 	return &SlackPayload{
 		Channel:     s.Channel,
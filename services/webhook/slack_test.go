@@ -145,6 +145,30 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Repository created by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 	})
 
+	t.Run("RepositorySettings", func(t *testing.T) {
+		p := repositorySettingsTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.RepositorySettings(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Branch protection created: master by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
+	t.Run("Deployment", func(t *testing.T) {
+		p := deploymentTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.Deployment(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Environment review deployed for pull request <http://localhost:3000/test/repo/pulls/12|#12 Fix bug>: <https://pr-12.review.example.com|https://pr-12.review.example.com> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
 	t.Run("Release", func(t *testing.T) {
 		p := pullReleaseTestPayload()
 
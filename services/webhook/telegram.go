@@ -6,16 +6,33 @@ package webhook
 
 import (
 	"fmt"
+	"html"
 	"strings"
+	"unicode/utf8"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/markup"
 	api "code.gitea.io/gitea/modules/structs"
+
 	jsoniter "github.com/json-iterator/go"
+	"github.com/microcosm-cc/bluemonday"
 )
 
+// telegramMaxTextLength is the length at which the texts written by users are cut,
+// the messages sent to Telegram are limited to 4096 characters
+const telegramMaxTextLength = 1024
+
+// telegramPolicy keeps only the HTML elements supported by Telegram, which rejects messages with others
+var telegramPolicy = func() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("b", "strong", "i", "em", "u", "ins", "s", "strike", "del", "code", "pre")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireParseableURLs(true)
+	return policy
+}()
+
 type (
 	// TelegramPayload represents
 	TelegramPayload struct {
@@ -49,7 +66,7 @@ var (
 func (t *TelegramPayload) JSONPayload() ([]byte, error) {
 	t.ParseMode = "HTML"
 	t.DisableWebPreview = true
-	t.Message = markup.Sanitize(t.Message)
+	t.Message = telegramPolicy.Sanitize(t.Message)
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
 	data, err := json.MarshalIndent(t, "", "  ")
 	if err != nil {
@@ -80,7 +97,7 @@ func (t *TelegramPayload) Delete(p *api.DeletePayload) (api.Payloader, error) {
 
 // Fork implements PayloadConvertor Fork method
 func (t *TelegramPayload) Fork(p *api.ForkPayload) (api.Payloader, error) {
-	title := fmt.Sprintf(`%s is forked to <a href="%s">%s</a>`, p.Forkee.FullName, p.Repo.HTMLURL, p.Repo.FullName)
+	title := fmt.Sprintf(`%s is forked to <a href="%s">%s</a>`, html.EscapeString(p.Forkee.FullName), p.Repo.HTMLURL, p.Repo.FullName)
 
 	return createTelegramPayload(title), nil
 }
@@ -113,7 +130,7 @@ func (t *TelegramPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 			authorName = " - " + commit.Author.Name
 		}
 		text += fmt.Sprintf(`[<a href="%s">%s</a>] %s`, commit.URL, commit.ID[:7],
-			strings.TrimRight(commit.Message, "\r\n")) + authorName
+			telegramText(strings.TrimRight(commit.Message, "\r\n"))) + html.EscapeString(authorName)
 		// add linebreak to each commit but the last
 		if i < len(p.Commits)-1 {
 			text += "\n"
@@ -127,21 +144,21 @@ func (t *TelegramPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 func (t *TelegramPayload) Issue(p *api.IssuePayload) (api.Payloader, error) {
 	text, _, attachmentText, _ := getIssuesPayloadInfo(p, htmlLinkFormatter, true)
 
-	return createTelegramPayload(text + "\n\n" + attachmentText), nil
+	return createTelegramPayload(text + "\n\n" + telegramText(attachmentText)), nil
 }
 
 // IssueComment implements PayloadConvertor IssueComment method
 func (t *TelegramPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader, error) {
 	text, _, _ := getIssueCommentPayloadInfo(p, htmlLinkFormatter, true)
 
-	return createTelegramPayload(text + "\n" + p.Comment.Body), nil
+	return createTelegramPayload(text + "\n" + telegramText(p.Comment.Body)), nil
 }
 
 // PullRequest implements PayloadConvertor PullRequest method
 func (t *TelegramPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, _, attachmentText, _ := getPullRequestPayloadInfo(p, htmlLinkFormatter, true)
 
-	return createTelegramPayload(text + "\n" + attachmentText), nil
+	return createTelegramPayload(text + "\n" + telegramText(attachmentText)), nil
 }
 
 // Review implements PayloadConvertor Review method
@@ -154,8 +171,9 @@ func (t *TelegramPayload) Review(p *api.PullRequestPayload, event models.HookEve
 			return nil, err
 		}
 
-		text = fmt.Sprintf("[%s] Pull request review %s: #%d %s", p.Repository.FullName, action, p.Index, p.PullRequest.Title)
-		attachmentText = p.Review.Content
+		text = fmt.Sprintf("[%s] Pull request review %s: %s", htmlLinkFormatter(p.Repository.HTMLURL, p.Repository.FullName), action,
+			htmlLinkFormatter(p.PullRequest.HTMLURL, fmt.Sprintf("#%d %s", p.Index, p.PullRequest.Title)))
+		attachmentText = telegramText(p.Review.Content)
 	}
 
 	return createTelegramPayload(text + "\n" + attachmentText), nil
@@ -169,12 +187,26 @@ func (t *TelegramPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 		title = fmt.Sprintf(`[<a href="%s">%s</a>] Repository created`, p.Repository.HTMLURL, p.Repository.FullName)
 		return createTelegramPayload(title), nil
 	case api.HookRepoDeleted:
-		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
+		title = fmt.Sprintf("[%s] Repository deleted", html.EscapeString(p.Repository.FullName))
 		return createTelegramPayload(title), nil
 	}
 	return nil, nil
 }
 
+// RepositorySettings implements PayloadConvertor RepositorySettings method
+func (t *TelegramPayload) RepositorySettings(p *api.RepositorySettingsPayload) (api.Payloader, error) {
+	text, _ := getRepositorySettingsPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// Deployment implements PayloadConvertor Deployment method
+func (t *TelegramPayload) Deployment(p *api.DeploymentPayload) (api.Payloader, error) {
+	text, _ := getDeploymentPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (t *TelegramPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	text, _ := getReleasePayloadInfo(p, htmlLinkFormatter, true)
@@ -187,6 +219,14 @@ func GetTelegramPayload(p api.Payloader, event models.HookEventType, meta string
 	return convertPayloader(new(TelegramPayload), p, event)
 }
 
+// telegramText escapes a text written by a user to include it in a message, and cuts it if it is too long
+func telegramText(text string) string {
+	if utf8.RuneCountInString(text) > telegramMaxTextLength {
+		text = string([]rune(text)[:telegramMaxTextLength]) + "…"
+	}
+	return html.EscapeString(text)
+}
+
 func createTelegramPayload(message string) *TelegramPayload {
 	return &TelegramPayload{
 		Message: strings.TrimSpace(message),
@@ -5,6 +5,7 @@
 package webhook
 
 import (
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -130,7 +131,7 @@ func TestTelegramPayload(t *testing.T) {
 		require.NotNil(t, pl)
 		require.IsType(t, &TelegramPayload{}, pl)
 
-		assert.Equal(t, "[test/repo] Pull request review approved: #12 Fix bug\ngood job", pl.(*TelegramPayload).Message)
+		assert.Equal(t, "[<a href=\"http://localhost:3000/test/repo\">test/repo</a>] Pull request review approved: <a href=\"http://localhost:3000/test/repo/pulls/12\">#12 Fix bug</a>\ngood job", pl.(*TelegramPayload).Message)
 	})
 
 	t.Run("Repository", func(t *testing.T) {
@@ -170,3 +171,22 @@ func TestTelegramJSONPayload(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, json)
 }
+
+func TestTelegramPayloadHTML(t *testing.T) {
+	p := issueCommentTestPayload()
+	p.Comment.Body = "<p>a <b>b</b> & c</p>\n<img src=\"x\">"
+
+	pl, err := new(TelegramPayload).IssueComment(p)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(pl.(*TelegramPayload).Message, "\n&lt;p&gt;a &lt;b&gt;b&lt;/b&gt; &amp; c&lt;/p&gt;\n&lt;img src=&#34;x&#34;&gt;"))
+
+	p.Comment.Body = strings.Repeat("a", telegramMaxTextLength+1)
+	pl, err = new(TelegramPayload).IssueComment(p)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(pl.(*TelegramPayload).Message, "\n"+strings.Repeat("a", telegramMaxTextLength)+"…"))
+
+	pl = createTelegramPayload(`<p>Hello <b>world</b> <a href="javascript:alert(1)">x</a> <a href="https://gitea.io">gitea</a></p>`)
+	_, err = pl.JSONPayload()
+	require.NoError(t, err)
+	assert.Equal(t, `Hello <b>world</b> x <a href="https://gitea.io">gitea</a>`, pl.(*TelegramPayload).Message)
+}
@@ -101,6 +101,17 @@ func PrepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	return nil
 }
 
+// ReplayHookTask queues a new delivery of the payload of a hook task of the webhook
+func ReplayHookTask(w *models.Webhook, id int64) (*models.HookTask, error) {
+	t, err := models.ReplayHookTask(w.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	go hookQueue.Add(t.RepoID)
+	return t, nil
+}
+
 func checkBranch(w *models.Webhook, branch string) bool {
 	if w.BranchFilter == "" || w.BranchFilter == "*" {
 		return true
@@ -149,31 +160,43 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 		}
 	}
 
+	_, err := createHookTask(w, repo, event, p)
+	return err
+}
+
+// createHookTask creates the hook task delivering the payload to the webhook in its format
+func createHookTask(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) (*models.HookTask, error) {
 	var payloader api.Payloader
 	var err error
 	webhook, ok := webhooks[w.Type]
 	if ok {
 		payloader, err = webhook.payloadCreator(p, event, w.Meta)
 		if err != nil {
-			return fmt.Errorf("create payload for %s[%s]: %v", w.Type, event, err)
+			return nil, fmt.Errorf("create payload for %s[%s]: %v", w.Type, event, err)
 		}
 	} else {
-		payloader = p
+		payloader, err = toPayloadVersion(p, w.PayloadVersion)
+		if err != nil {
+			return nil, fmt.Errorf("create payload version %d for %s[%s]: %v", w.PayloadVersion, w.Type, event, err)
+		}
 	}
 
-	if err = models.CreateHookTask(&models.HookTask{
+	t := &models.HookTask{
 		RepoID:    repo.ID,
 		HookID:    w.ID,
 		Payloader: payloader,
 		EventType: event,
-	}); err != nil {
-		return fmt.Errorf("CreateHookTask: %v", err)
 	}
-	return nil
+	if err = models.CreateHookTask(t); err != nil {
+		return nil, fmt.Errorf("CreateHookTask: %v", err)
+	}
+	return t, nil
 }
 
 // PrepareWebhooks adds new webhooks to task queue for given payload.
 func PrepareWebhooks(repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+	recordRepoEvent(repo, event, p)
+
 	if err := prepareWebhooks(repo, event, p); err != nil {
 		return err
 	}
@@ -188,15 +211,12 @@ func prepareWebhooks(repo *models.Repository, event models.HookEventType, p api.
 		return fmt.Errorf("GetActiveWebhooksByRepoID: %v", err)
 	}
 
-	// check if repo belongs to org and append additional webhooks
-	if repo.MustOwner().IsOrganization() {
-		// get hooks for org
-		orgHooks, err := models.GetActiveWebhooksByOrgID(repo.OwnerID)
-		if err != nil {
-			return fmt.Errorf("GetActiveWebhooksByOrgID: %v", err)
-		}
-		ws = append(ws, orgHooks...)
+	// append the webhooks of the owner of the repo, be it an org or a user
+	ownerHooks, err := models.GetActiveWebhooksByOrgID(repo.OwnerID)
+	if err != nil {
+		return fmt.Errorf("GetActiveWebhooksByOrgID: %v", err)
 	}
+	ws = append(ws, ownerHooks...)
 
 	// Add any admin-defined system webhooks
 	systemHooks, err := models.GetSystemWebhooks()
@@ -5,7 +5,9 @@
 package webhook
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
@@ -40,6 +42,27 @@ func TestPrepareWebhooks(t *testing.T) {
 	}
 }
 
+func TestPrepareWebhooksUserHook(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	w := &models.Webhook{
+		OrgID:       repo.OwnerID,
+		URL:         "http://localhost/user-hook",
+		ContentType: models.ContentTypeJSON,
+		IsActive:    true,
+		Type:        models.GITEA,
+		HookEvent:   &models.HookEvent{PushOnly: true},
+	}
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, models.CreateWebhook(w))
+
+	hookTask := &models.HookTask{RepoID: repo.ID, HookID: w.ID, EventType: models.HookEventPush}
+	models.AssertNotExistsBean(t, hookTask)
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventPush, &api.PushPayload{Commits: []*api.PayloadCommit{{}}}))
+	models.AssertExistsAndLoadBean(t, hookTask)
+}
+
 func TestPrepareWebhooksBranchFilterMatch(t *testing.T) {
 	assert.NoError(t, models.PrepareTestDatabase())
 
@@ -77,3 +100,55 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks
+
+func TestReplayRepoEvent(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventPush, &api.PushPayload{Ref: "refs/heads/master", Commits: []*api.PayloadCommit{{}}}))
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventCreate, &api.CreatePayload{Ref: "master"}))
+
+	// only push, issue and pull request events are recorded
+	events, err := models.FindRepoEvents(repo.ID, models.ListOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+	event := events[0]
+	assert.Equal(t, models.HookEventPush, event.EventType)
+	assert.Equal(t, "push", event.PayloadType)
+
+	// replaying into all webhooks does not record the event again
+	count, err := models.CountHookTasks(1)
+	assert.NoError(t, err)
+	task, err := ReplayRepoEvent(repo, event, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+	newCount, err := models.CountHookTasks(1)
+	assert.NoError(t, err)
+	assert.Equal(t, count+1, newCount)
+	count, err = models.CountRepoEvents(repo.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// a chosen webhook receives the event whatever its events
+	w := &models.Webhook{
+		RepoID:      repo.ID,
+		URL:         "http://localhost/issues-hook",
+		ContentType: models.ContentTypeJSON,
+		IsActive:    true,
+		Type:        models.GITEA,
+		HookEvent:   &models.HookEvent{ChooseEvents: true, HookEvents: models.HookEvents{Issues: true}},
+	}
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, models.CreateWebhook(w))
+	task, err = ReplayRepoEvent(repo, event, w)
+	assert.NoError(t, err)
+	if assert.NotNil(t, task) {
+		assert.Equal(t, w.ID, task.HookID)
+		assert.Contains(t, task.PayloadContent, `"ref": "refs/heads/master"`)
+	}
+
+	assert.NoError(t, models.DeleteOldRepoEvents(context.Background(), -time.Minute))
+	models.AssertNotExistsBean(t, &models.RepoEvent{ID: event.ID})
+}
@@ -0,0 +1,21 @@
+export default function initAdminTaskLog() {
+  const $log = $('#task-log');
+  if (!$log.length || !window.EventSource) return;
+  const $status = $('#task-log-status');
+
+  const source = new EventSource($log.data('url'));
+  source.addEventListener('start', () => {
+    $log.empty();
+    $status.text($status.data('running'));
+  });
+  source.addEventListener('log', (e) => {
+    const line = JSON.parse(e.data);
+    const time = new Date(line.time).toLocaleTimeString();
+    const progress = line.progress >= 0 ? ` (${line.progress}%)` : '';
+    $('<div>').addClass(line.level).text(`${time} [${line.level.toUpperCase()}] ${line.message}${progress}`).appendTo($log);
+    $log.scrollTop($log.prop('scrollHeight'));
+  });
+  source.addEventListener('done', () => {
+    $status.text($status.data('done'));
+  });
+}
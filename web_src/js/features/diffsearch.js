@@ -0,0 +1,51 @@
+export default function initDiffSearch() {
+  const $form = $('#diff-search-form');
+  if (!$form.length) return;
+  const $results = $('#diff-search-results');
+
+  $form.on('submit', async (e) => {
+    e.preventDefault();
+    const keyword = $form.find('input[name=q]').val().trim();
+    if (!keyword) {
+      $results.addClass('hide').empty();
+      return;
+    }
+
+    const params = new URLSearchParams(window.location.search);
+    params.set('q', keyword);
+    const data = await $.getJSON(`${$form.attr('action')}?${params.toString()}`);
+
+    $results.empty();
+    if (!data.files.length) {
+      $('<p>').text($results.data('no-results')).appendTo($results);
+    }
+    for (const file of data.files) {
+      const $file = $('<div class="diff-search-file">').appendTo($results);
+      $('<a class="file mono bold">').attr('href', `#diff-${file.index}`).text(file.name).appendTo($file);
+      if (file.name_matched) {
+        $('<span class="text grey ml-3">').text($results.data('name-matched')).appendTo($file);
+      }
+      for (const hunk of file.hunks) {
+        const $table = $('<table class="chroma">').appendTo($('<div class="diff-search-hunk">').appendTo($file));
+        $('<tr class="tag-code">').append($('<td colspan="3" class="lines-code">').text(hunk.header)).appendTo($table);
+        for (const line of hunk.lines) {
+          if (line.type === 'skip') {
+            $('<tr class="tag-code">').append($('<td colspan="3" class="lines-code">').text('…')).appendTo($table);
+            continue;
+          }
+          const $row = $('<tr>').addClass(`${line.type}-code`).toggleClass('matched', line.matched).appendTo($table);
+          $('<td class="lines-num">').text(line.left_idx || '').appendTo($row);
+          $('<td class="lines-num">').text(line.right_idx || '').appendTo($row);
+          $('<td class="lines-code">').text(line.content).appendTo($row);
+        }
+      }
+    }
+    if (data.has_more) {
+      $('<p class="text grey">').text($results.data('has-more')).appendTo($results);
+    }
+    if (data.is_incomplete) {
+      $('<p class="text grey">').text($results.data('incomplete')).appendTo($results);
+    }
+    $results.removeClass('hide');
+  });
+}
@@ -66,6 +66,7 @@ export default async function initProject() {
     const projectTitleInput = $(this).find(
       '.content > .form > .field > .project-board-title',
     );
+    const projectAutoMoveInput = $(this).find('.project-board-auto-move');
 
     $(this)
       .find('.content > .form > .actions > .red')
@@ -74,7 +75,7 @@ export default async function initProject() {
 
         $.ajax({
           url: $(this).data('url'),
-          data: JSON.stringify({title: projectTitleInput.val()}),
+          data: JSON.stringify({title: projectTitleInput.val(), auto_move: projectAutoMoveInput.val()}),
           headers: {
             'X-Csrf-Token': csrf,
             'X-Remote': true,
@@ -14,6 +14,8 @@ import initGitGraph from './features/gitgraph.js';
 import initHeatmap from './features/heatmap.js';
 import initImageDiff from './features/imagediff.js';
 import initMigration from './features/migration.js';
+import initAdminTaskLog from './features/admin-tasklog.js';
+import initDiffSearch from './features/diffsearch.js';
 import initProject from './features/projects.js';
 import initServiceWorker from './features/serviceworker.js';
 import initTableSort from './features/tablesort.js';
@@ -2690,18 +2692,25 @@ $(document).ready(async () => {
       thumbnailWidth: 480,
       thumbnailHeight: 480,
       init() {
+        this.on('sending', (file, _xhr, formData) => {
+          // files of a dropped directory keep their path relative to it
+          if (file.fullPath) {
+            formData.append('full_path', file.fullPath);
+          }
+        });
         this.on('success', (file, data) => {
-          filenameDict[file.name] = data.uuid;
+          filenameDict[file.fullPath || file.name] = data.uuid;
           const input = $(`<input id="${data.uuid}" name="files" type="hidden">`).val(data.uuid);
           $dropzone.find('.files').append(input);
         });
         this.on('removedfile', (file) => {
-          if (file.name in filenameDict) {
-            $(`#${filenameDict[file.name]}`).remove();
+          const filename = file.fullPath || file.name;
+          if (filename in filenameDict) {
+            $(`#${filenameDict[filename]}`).remove();
           }
           if ($dropzone.data('remove-url')) {
             $.post($dropzone.data('remove-url'), {
-              file: filenameDict[file.name],
+              file: filenameDict[filename],
               _csrf: csrf
             });
           }
@@ -2817,6 +2826,8 @@ $(document).ready(async () => {
   initArchiveLinks();
   initRepository();
   initMigration();
+  initAdminTaskLog();
+  initDiffSearch();
   initWikiForm();
   initEditForm();
   initEditor();
@@ -2891,6 +2902,13 @@ function deSelect() {
   }
 }
 
+function updatePermalinks(hash) {
+  // keep the selected lines in the permalinks to the file at the commit
+  $('a.file-permalink').each(function () {
+    $(this).attr('href', `${$(this).attr('href').replace(/#.*$/, '')}${hash}`);
+  });
+}
+
 function selectRange($list, $select, $from) {
   $list.removeClass('active');
   if ($from) {
@@ -2909,6 +2927,7 @@ function selectRange($list, $select, $from) {
       }
       $list.filter(classes.join(',')).addClass('active');
       changeHash(`#L${a}-L${b}`);
+      updatePermalinks(`#L${a}-L${b}`);
 
       // add hashchange to permalink
       const $issue = $('a.ref-in-new-issue');
@@ -2928,6 +2947,7 @@ function selectRange($list, $select, $from) {
   }
   $select.addClass('active');
   changeHash(`#${$select.attr('rel')}`);
+  updatePermalinks(`#${$select.attr('rel')}`);
 
   // add hashchange to permalink
   const $issue = $('a.ref-in-new-issue');
@@ -3399,6 +3419,20 @@ function initVueComponents() {
         });
       },
 
+      formatDate(date) {
+        return new Date(date).toLocaleString();
+      },
+
+      syncMirror(repo) {
+        $.ajax({
+          url: `${this.suburl}/api/v1/repos/${repo.full_name}/mirror-sync`,
+          type: 'POST',
+          headers: {'X-Csrf-Token': csrf},
+        }).done(() => {
+          Vue.set(repo, 'mirror_sync_queued', true);
+        });
+      },
+
       repoIcon(repo) {
         if (repo.fork) {
           return 'octicon-repo-forked';